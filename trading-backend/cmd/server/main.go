@@ -13,17 +13,26 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/atlas-desktop/trading-backend/internal/api"
 	"github.com/atlas-desktop/trading-backend/internal/autonomous"
+	"github.com/atlas-desktop/trading-backend/internal/backtester"
 	"github.com/atlas-desktop/trading-backend/internal/blockchain"
 	"github.com/atlas-desktop/trading-backend/internal/data"
+	"github.com/atlas-desktop/trading-backend/internal/events"
 	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/internal/fees"
+	"github.com/atlas-desktop/trading-backend/internal/health"
 	"github.com/atlas-desktop/trading-backend/internal/learning"
+	"github.com/atlas-desktop/trading-backend/internal/metrics"
 	"github.com/atlas-desktop/trading-backend/internal/orchestrator"
 	"github.com/atlas-desktop/trading-backend/internal/regime"
+	"github.com/atlas-desktop/trading-backend/internal/replay"
 	"github.com/atlas-desktop/trading-backend/internal/signals"
 	"github.com/atlas-desktop/trading-backend/internal/strategy"
 	"github.com/atlas-desktop/trading-backend/pkg/types"
@@ -38,6 +47,8 @@ func main() {
 	dataDir := flag.String("data", "./data", "Data directory")
 	logLevel := flag.String("log-level", "info", "Log level (debug, info, warn, error)")
 	paperTrading := flag.Bool("paper", true, "Enable paper trading mode")
+	simLatency := flag.Duration("sim-latency", 0, "Paper trading: delay before orders fill")
+	simPartialFills := flag.Bool("sim-partial-fills", false, "Paper trading: fill orders against order book depth and queue position")
 	flag.Parse()
 
 	// Setup logger
@@ -124,9 +135,16 @@ func main() {
 	orderManager := execution.NewOrderManager(logger)
 	slippageCalculator := execution.NewSlippageCalculator(logger, execution.DefaultSlippageConfig())
 
+	// One fee model prices paper fills, routing and slippage estimates
+	feeModel := fees.NewFeeModel(fees.DefaultConfig())
+	slippageCalculator.SetFeeModel(feeModel)
+
 	// Initialize trade executor
 	executorConfig := execution.ExecutorConfig{
 		PaperTrading:      *paperTrading,
+		SimLatency:        *simLatency,
+		SimPartialFills:   *simPartialFills,
+		SimBookDepth:      20,
 		MaxRetries:        3,
 		RetryDelayMs:      1000,
 		DefaultSlippage:   0.001,
@@ -138,13 +156,132 @@ func main() {
 		executorConfig,
 		riskManager,
 		slippageCalculator,
-		nil, // Exchange adapters set via env
 	)
+	executor.SetFeeModel(feeModel)
+
+	// Live fills recalibrate the slippage model used for routing and backtests
+	executor.SetSlippageCalculator(slippageCalculator)
+
+	// Exchange adapters are built from <NAME>_API_KEY/<NAME>_API_SECRET for
+	// each name in EXCHANGES; the first one configured is the default route.
+	adapterRegistry := adapters.NewAdapterRegistry(logger)
+	adapterRegistry.SetStateDir(filepath.Join(*dataDir, "adapters"))
+	hasDefault := false
+	var exchangeAdapters []execution.ExchangeAdapter
+	// Order flow signals come from the Binance trade stream, and Binance
+	// order updates from its user data stream
+	var binanceAdapter *adapters.BinanceAdapter
+	var orderFlowSource *signals.OrderFlowSignalSource
+	for _, name := range strings.Split(getEnvOrDefault("EXCHANGES", "binance"), ",") {
+		name = strings.TrimSpace(name)
+		adapter, err := adapterRegistry.CreateFromEnv(name)
+		if err != nil {
+			logger.Warn("Exchange adapter not configured", zap.String("exchange", name), zap.Error(err))
+			continue
+		}
+		executor.AddAdapter(adapter)
+		exchangeAdapters = append(exchangeAdapters, adapter)
+
+		// Estimate slippage against live books where the venue streams them
+		if streamer, ok := adapter.(adapters.OrderBookStreamer); ok {
+			err := streamer.SubscribeToOrderBook(ctx, []string{"BTCUSDT", "ETHUSDT", "SOLUSDT"}, func(symbol string, ob *types.OrderBook) {
+				slippageCalculator.UpdateFromOrderBook(ob)
+			})
+			if err != nil {
+				logger.Warn("Order book stream not started", zap.String("exchange", name), zap.Error(err))
+			}
+		}
+		if !hasDefault {
+			executor.SetDefaultAdapter(adapter)
+			hasDefault = true
+		}
+		if binance, ok := adapter.(*adapters.BinanceAdapter); ok && binanceAdapter == nil {
+			binanceAdapter = binance
+			source, err := signals.NewSignalSource(logger, signals.SignalSourceConfig{
+				Type:            signals.SourceTypeOrderFlow,
+				Enabled:         true,
+				TradeSubscriber: signals.BinanceTradeSubscriber(binance),
+			})
+			if err != nil {
+				logger.Warn("Order flow source not configured", zap.Error(err))
+			} else {
+				orderFlowSource = source.(*signals.OrderFlowSignalSource)
+				signalAggregator.AddSource(orderFlowSource)
+			}
+		}
+	}
+
+	// Swap on Solana through Jupiter when a wallet is configured, confirming
+	// swaps through the block tracker
+	if walletKey := os.Getenv("SOLANA_PRIVATE_KEY"); walletKey != "" {
+		jupiter, err := adapters.NewJupiterAdapter(logger, adapters.JupiterConfig{
+			PrivateKey:  walletKey,
+			MaxSlippage: executorConfig.MaxSlippage,
+		}, solanaClient, blockTracker)
+		if err != nil {
+			logger.Warn("Jupiter adapter not configured", zap.Error(err))
+		} else {
+			executor.AddAdapter(jupiter)
+			exchangeAdapters = append(exchangeAdapters, jupiter)
+			logger.Info("Jupiter adapter configured", zap.String("wallet", jupiter.Wallet()))
+		}
+	}
+
+	// Charge each exchange's account fee tier where it reports one
+	if err := executor.SyncFees(ctx); err != nil {
+		logger.Warn("Using default fee schedules", zap.Error(err))
+	}
+
+	// Track cash and positions from the configured exchanges, or simulated
+	// balances when paper trading
+	portfolioConfig := execution.DefaultPortfolioConfig()
+	portfolioConfig.Paper = *paperTrading
+	portfolioManager := execution.NewPortfolioManager(logger, portfolioConfig, exchangeAdapters...)
+	riskManager.SetPortfolioManager(portfolioManager)
+
+	// Route orders across venues when several exchanges are configured,
+	// within the balance each one holds
+	orderRouter := execution.NewRouter(logger, execution.DefaultRouterConfig(), slippageCalculator)
+	orderRouter.SetPortfolioManager(portfolioManager)
+	orderRouter.SetFeeModel(feeModel)
+	executor.SetRouter(orderRouter)
+
+	// Place and replace take-profit ladder orders one update at a time
+	ladderUpdates := make(chan *execution.TPLadder, 100)
+	orderManager.OnLadderUpdate = func(ladder *execution.TPLadder) {
+		select {
+		case ladderUpdates <- ladder:
+		default:
+			logger.Warn("Take-profit ladder update dropped", zap.String("symbol", ladder.Symbol))
+		}
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ladder := <-ladderUpdates:
+				if err := executor.SyncTPLadder(ctx, orderManager, ladder, ""); err != nil {
+					logger.Warn("Failed to sync take-profit ladder", zap.String("symbol", ladder.Symbol), zap.Error(err))
+				}
+			}
+		}
+	}()
 
 	// Initialize learning components
 	feedbackEngine := learning.NewFeedbackEngine(logger)
 	strategyOptimizer := learning.NewStrategyOptimizer(logger, feedbackEngine)
 
+	// Closed trades are journaled for analysis and export, with their feedback
+	tradeJournal, err := learning.NewTradeJournal(logger, *dataDir)
+	if err != nil {
+		logger.Fatal("Failed to open trade journal", zap.Error(err))
+	}
+	feedbackEngine.SetJournal(tradeJournal)
+
+	// Weigh each source by the confidence its signals have realized
+	signalAggregator.SetCalibrator(feedbackEngine)
+
 	// Initialize strategy registry
 	strategyRegistry := strategy.NewStrategyRegistry(logger)
 	logger.Info("Registered strategies",
@@ -159,6 +296,8 @@ func main() {
 	orchConfig.KellyFraction = 0.25 // Quarter Kelly for safety
 	orchConfig.MinSharpeRatio = 0.5
 	orchConfig.MaxDrawdown = 0.2
+	orchConfig.RegimeModelDir = filepath.Join(*dataDir, "regime")
+	orchConfig.EventLogDir = filepath.Join(*dataDir, "events")
 
 	tradingOrchestrator, err := orchestrator.NewTradingOrchestrator(
 		logger,
@@ -170,6 +309,17 @@ func main() {
 		logger.Fatal("Failed to initialize trading orchestrator", zap.Error(err))
 	}
 
+	// Group correlated exposure by correlations measured from bars
+	riskManager.SetCorrelationSource(tradingOrchestrator.Correlations())
+
+	// Re-score the registered strategies by backtesting them over recent
+	// bars, which also gives Monte Carlo validation their trade history
+	tradingOrchestrator.SetBacktestRunner(backtester.NewEvaluationRunner(logger, dataStore,
+		backtester.DefaultEvaluationConfig(), strategyRegistry.NewBacktestStrategy))
+	for _, name := range strategyRegistry.List() {
+		tradingOrchestrator.RegisterStrategy(name, nil)
+	}
+
 	// Initialize Enhanced Trading Agent (PhD-level)
 	enhancedAgentConfig := autonomous.DefaultEnhancedAgentConfig()
 	enhancedAgentConfig.TradingPairs = []string{"BTCUSDT", "ETHUSDT", "SOLUSDT"}
@@ -177,6 +327,9 @@ func main() {
 	enhancedAgentConfig.EnableRegimeAdapt = true
 	enhancedAgentConfig.UseKellySize = true
 	enhancedAgentConfig.RequireMCValidation = true
+	if err := enhancedAgentConfig.Validate(); err != nil {
+		logger.Fatal("Invalid agent config", zap.Error(err))
+	}
 
 	enhancedAgent := autonomous.NewEnhancedTradingAgent(
 		logger,
@@ -187,6 +340,31 @@ func main() {
 		orderManager,
 		signalAggregator,
 	)
+	enhancedAgent.SetPortfolioManager(portfolioManager)
+	enhancedAgent.SetJournal(tradeJournal)
+	portfolioManager.OnPositionClosed = enhancedAgent.RecordClosedTrade
+
+	// Symbols in PERPETUAL_SYMBOLS are traded as perpetual futures: their
+	// positions accrue funding, and the agent weighs it before entering
+	var fundingTracker *execution.FundingTracker
+	if perps := os.Getenv("PERPETUAL_SYMBOLS"); perps != "" {
+		for _, adapter := range exchangeAdapters {
+			source, ok := adapter.(adapters.FundingRateAdapter)
+			if !ok {
+				continue
+			}
+			fundingConfig := execution.DefaultFundingConfig()
+			for _, symbol := range strings.Split(perps, ",") {
+				fundingConfig.Symbols = append(fundingConfig.Symbols, strings.TrimSpace(symbol))
+			}
+			fundingTracker = execution.NewFundingTracker(logger, fundingConfig, source, portfolioManager)
+			enhancedAgent.SetFundingSource(fundingTracker)
+			break
+		}
+		if fundingTracker == nil {
+			logger.Warn("No configured exchange reports funding rates; perpetual funding is not tracked")
+		}
+	}
 
 	// Initialize legacy agent for backwards compatibility
 	agentConfig := autonomous.AgentConfig{
@@ -212,6 +390,12 @@ func main() {
 		feedbackEngine,
 	)
 
+	// Halt entries per symbol on abnormal price moves and drop bad ticks
+	circuitBreaker := data.NewCircuitBreaker(logger, data.DefaultCircuitBreakerConfig())
+	marketDataService.SetCircuitBreaker(circuitBreaker)
+	enhancedAgent.SetCircuitBreaker(circuitBreaker)
+	agent.SetCircuitBreaker(circuitBreaker)
+
 	// Server configuration
 	serverConfig := &types.ServerConfig{
 		Host:           *host,
@@ -231,16 +415,164 @@ func main() {
 	phdHandlers := api.NewPhDHandlers(logger, tradingOrchestrator, enhancedAgent)
 	phdHandlers.RegisterRoutes(server.Router())
 
+	// Prometheus metrics, scraped on their own port
+	var metricsServer *metrics.Server
+	if serverConfig.EnableMetrics {
+		metricsAddr := fmt.Sprintf("%s:%d", *host, serverConfig.MetricsPort)
+		metricsServer = metrics.NewServer(logger, metricsAddr, metrics.NewRegistry(tradingOrchestrator, enhancedAgent))
+	}
+
 	// Setup WebSocket hub for real-time updates
 	wsHub := api.NewHub(logger)
 	go wsHub.Run()
 
+	// On-demand backtests of the registered strategies, streamed over the hub
+	backtestJobs := api.NewBacktestJobs(logger, api.DefaultBacktestJobsConfig(), dataStore,
+		tradingOrchestrator.WorkerPool(), wsHub)
+	for _, name := range strategyRegistry.List() {
+		name := name
+		backtestJobs.RegisterStrategy(name, func(params map[string]float64) (backtester.Strategy, error) {
+			return strategyRegistry.NewBacktestStrategy(name, params)
+		})
+	}
+	server.SetBacktestJobs(backtestJobs)
+	server.SetSlippageCalibrator(slippageCalculator)
+	server.SetTradeJournal(tradeJournal)
+	server.SetSignalExplainer(signalAggregator, enhancedAgent)
+
+	// Strategies running on live bars, tunable without a restart. Parameter
+	// changes are audited to the data directory and broadcast over the hub.
+	liveConfig := strategy.DefaultLiveRegistryConfig()
+	liveConfig.AuditFile = filepath.Join(*dataDir, "strategy-params.jsonl")
+	liveStrategies := strategy.NewLiveRegistry(logger, liveConfig, strategyRegistry)
+	liveStrategies.OnParamChange = wsHub.BroadcastStrategyParams
+	liveStrategies.OnSignal = wsHub.BroadcastStrategySignal
+	server.SetLiveStrategies(liveStrategies)
+
+	// Component health, served at /api/v1/health and published as heartbeats.
+	// Trading stops without market data, the event bus or an exchange, so
+	// those are critical; signal sources and chains only degrade.
+	healthMonitor := health.NewMonitor(logger, health.DefaultConfig(), tradingOrchestrator.GetEventBus())
+	healthMonitor.Register(health.Component{
+		Name:     "market_data",
+		Critical: true,
+		Check:    health.MarketDataCheck(marketDataService),
+	})
+	healthMonitor.Register(health.Component{
+		Name:     "event_bus",
+		Critical: true,
+		Check:    health.EventBusCheck(tradingOrchestrator.GetEventBus()),
+	})
+	for _, adapter := range exchangeAdapters {
+		healthMonitor.Register(health.Component{
+			Name:     "exchange:" + adapter.Name(),
+			Critical: true,
+			Check:    health.AdapterCheck(adapter),
+		})
+	}
+	healthMonitor.Register(health.Component{
+		Name:       "signal_sources",
+		StaleAfter: 15 * time.Minute,
+		Check: health.GroupCheck(func() map[string]health.Report {
+			reports := make(map[string]health.Report)
+			for name, source := range signalAggregator.GetSourceHealth() {
+				reports[name] = health.Report{Healthy: source.IsHealthy, LastReport: source.LastSignalTime, LastError: source.LastError}
+			}
+			return reports
+		}),
+	})
+	healthMonitor.Register(health.Component{
+		Name: "chains",
+		Check: health.GroupCheck(func() map[string]health.Report {
+			reports := make(map[string]health.Report)
+			for chain, state := range blockTracker.GetAllChainStates() {
+				reports[chain] = health.Report{Healthy: state.IsHealthy, LastReport: state.LastBlockTime, LastError: state.LastError}
+			}
+			return reports
+		}),
+	})
+	server.SetHealthMonitor(healthMonitor)
+
+	// JWT auth on the REST and WebSocket endpoints, enabled by a signing secret
+	if secret := os.Getenv("ATLAS_JWT_SECRET"); secret != "" {
+		users, err := api.ParseAuthUsers(os.Getenv("ATLAS_API_USERS"))
+		if err != nil {
+			logger.Fatal("Invalid ATLAS_API_USERS", zap.Error(err))
+		}
+		authConfig := api.DefaultAuthConfig()
+		authConfig.Secret = secret
+		authConfig.Users = users
+		authenticator, err := api.NewAuthenticator(logger, authConfig)
+		if err != nil {
+			logger.Fatal("Failed to create authenticator", zap.Error(err))
+		}
+		server.SetAuthenticator(authenticator)
+	} else {
+		logger.Warn("ATLAS_JWT_SECRET not set, API authentication disabled")
+	}
+
+	// Live PnL: positions are marked on every price and streamed to the hub
+	pnlMonitor := execution.NewPnLMonitor(logger, orderManager, tradingOrchestrator.GetEventBus())
+	tradingOrchestrator.GetEventBus().Subscribe(events.EventTypePnL, func(e events.Event) error {
+		wsHub.BroadcastPnLUpdate(e)
+		return nil
+	})
+
+	// Historical replay drives the stack from stored bars in simulated time.
+	// Replayed symbols' orders go to the replay venue, and their live prices
+	// are held back until the session ends.
+	replayManager := replay.NewManager(logger, replay.DefaultManagerConfig(), dataStore, tradingOrchestrator.GetEventBus())
+	replayManager.SetRouter(executor)
+	replayManager.OnUpdate(wsHub.BroadcastReplayUpdate)
+	replayManager.OnBar(func(bar replay.Bar) {
+		portfolioManager.UpdatePrice(bar.Symbol, bar.Close)
+		pnlMonitor.OnPrice(bar.Symbol, bar.Close)
+		enhancedAgent.UpdatePrice(bar.Symbol, bar.Close)
+		enhancedAgent.UpdateBar(bar.Symbol, bar.OHLCV)
+	})
+	if *paperTrading {
+		server.SetReplayManager(replayManager)
+	}
+
 	// Wire up event callbacks
+	marketDataService.SetEventBus(tradingOrchestrator.GetEventBus())
+	circuitBreaker.SetEventBus(tradingOrchestrator.GetEventBus())
+	circuitBreaker.OnTrip = func(trip data.CircuitBreakerTrip) {
+		wsHub.BroadcastRiskAlert(trip)
+	}
 	marketDataService.OnPrice(func(update data.PriceUpdate) {
+		if replayManager.Replaying(update.Symbol) {
+			return
+		}
+		if update.Stale {
+			// Don't mark positions or trade on a price the feed stopped updating
+			wsHub.PublishToChannel("prices:"+update.Symbol, api.MsgTypePnLUpdate, update)
+			return
+		}
+		portfolioManager.UpdatePrice(update.Symbol, update.Price)
+		pnlMonitor.OnPrice(update.Symbol, update.Price)
+		enhancedAgent.UpdatePrice(update.Symbol, update.Price)
 		wsHub.PublishToChannel("prices:"+update.Symbol, api.MsgTypePnLUpdate, update)
 	})
 
+	marketDataService.OnOHLCV(func(bar data.OHLCV) {
+		if replayManager.Replaying(bar.Symbol) {
+			return
+		}
+		ohlcv := types.OHLCV{
+			Timestamp: time.UnixMilli(bar.Timestamp),
+			Open:      bar.Open,
+			High:      bar.High,
+			Low:       bar.Low,
+			Close:     bar.Close,
+			Volume:    bar.Volume,
+		}
+		enhancedAgent.UpdateBar(bar.Symbol, ohlcv)
+		liveStrategies.OnBar(bar.Symbol, ohlcv)
+	})
+
 	orderManager.OnOrderUpdate = func(order *execution.ManagedOrder) {
+		portfolioManager.OnOrderUpdate(order)
 		wsHub.BroadcastOrderUpdate(&types.Order{
 			ID:     order.Order.ID,
 			Symbol: order.Order.Symbol,
@@ -253,6 +585,12 @@ func main() {
 		wsHub.BroadcastRiskAlert(v)
 	}
 
+	// State corrected from the exchanges means something happened that the
+	// process did not see
+	orderManager.OnDivergence = func(d execution.Divergence) {
+		wsHub.BroadcastRiskAlert(d)
+	}
+
 	agent.SetTradeCallback(func(trade *types.Trade) {
 		wsHub.BroadcastTradeUpdate(trade)
 	})
@@ -274,10 +612,26 @@ func main() {
 		})
 	})
 
+	signalAggregator.SetOnDegradation(func(degradation signals.SourceDegradation) {
+		wsHub.PublishToChannel("signals", api.MsgTypeRiskAlert, degradation)
+	})
+
 	// Handle graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	// Warm up regime detection from downloaded history, if any
+	for _, symbol := range marketDataConfig.Symbols {
+		bars, err := dataStore.LoadHistory(symbol, types.Timeframe1h)
+		if err != nil {
+			logger.Warn("Failed to load regime warmup history", zap.String("symbol", symbol), zap.Error(err))
+			continue
+		}
+		if len(bars) > 0 {
+			tradingOrchestrator.WarmupRegime(symbol, bars)
+		}
+	}
+
 	// Start PhD-level orchestrator
 	go func() {
 		if err := tradingOrchestrator.Start(ctx); err != nil {
@@ -286,6 +640,27 @@ func main() {
 	}()
 
 	// Start services
+	if err := portfolioManager.Start(ctx); err != nil {
+		logger.Error("Portfolio sync error", zap.Error(err))
+	}
+	if fundingTracker != nil {
+		fundingTracker.Start(ctx)
+	}
+	if binanceAdapter != nil {
+		if err := binanceAdapter.StartUserDataStream(ctx, execution.BinanceOrderUpdates(orderManager)); err != nil {
+			logger.Error("Binance user data stream error", zap.Error(err))
+		}
+	}
+	if orderFlowSource != nil {
+		if err := orderFlowSource.StreamTrades(ctx, []string{"BTCUSDT", "ETHUSDT", "SOLUSDT"}); err != nil {
+			logger.Error("Order flow trade stream error", zap.Error(err))
+		}
+	}
+
+	if err := riskManager.Start(ctx); err != nil {
+		logger.Error("Risk manager daily reset error", zap.Error(err))
+	}
+
 	go func() {
 		if err := marketDataService.Start(ctx); err != nil {
 			logger.Error("Market data service error", zap.Error(err))
@@ -305,6 +680,18 @@ func main() {
 		}
 	}()
 
+	if metricsServer != nil {
+		go func() {
+			if err := metricsServer.Start(); err != nil {
+				logger.Error("Metrics server error", zap.Error(err))
+			}
+		}()
+	}
+
+	if err := healthMonitor.Start(ctx); err != nil {
+		logger.Error("Health monitor error", zap.Error(err))
+	}
+
 	logger.Info("Server started successfully",
 		zap.String("ws", fmt.Sprintf("ws://%s:%d/ws", *host, *port)),
 		zap.String("http", fmt.Sprintf("http://%s:%d/api/v1", *host, *port)),
@@ -318,6 +705,7 @@ func main() {
 
 	// Stop services
 	cancel()
+	replayManager.Close()
 
 	// Stop enhanced agent first
 	if enhancedAgent.IsRunning() {
@@ -343,6 +731,7 @@ func main() {
 	}
 
 	blockTracker.Stop()
+	healthMonitor.Stop()
 
 	// Graceful server shutdown with timeout
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -352,6 +741,12 @@ func main() {
 		logger.Error("Error during server shutdown", zap.Error(err))
 	}
 
+	if metricsServer != nil {
+		if err := metricsServer.Stop(shutdownCtx); err != nil {
+			logger.Error("Error during metrics server shutdown", zap.Error(err))
+		}
+	}
+
 	logger.Info("Server stopped")
 }
 
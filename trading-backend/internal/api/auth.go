@@ -0,0 +1,303 @@
+// Package api provides JWT bearer-token authentication for the REST and
+// WebSocket endpoints.
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// Roles carried in token claims
+const (
+	RoleRead  = "read"  // GET-only access
+	RoleTrade = "trade" // Full access, including agent and risk control
+)
+
+// Minimum signing secret length in bytes
+const minSecretLength = 32
+
+// Token errors
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+)
+
+// AuthUser is an account allowed to log in
+type AuthUser struct {
+	Username string `json:"username"`
+	Password string `json:"-"`
+	Role     string `json:"role"`
+}
+
+// AuthConfig configures token signing and the accounts that may log in
+type AuthConfig struct {
+	Secret   string        `json:"-"`
+	TokenTTL time.Duration `json:"tokenTtl"`
+	Users    []AuthUser    `json:"users"`
+}
+
+// DefaultAuthConfig returns a config issuing 12 hour tokens. Secret and
+// Users must be filled in.
+func DefaultAuthConfig() AuthConfig {
+	return AuthConfig{
+		TokenTTL: 12 * time.Hour,
+	}
+}
+
+// Claims are the JWT claims issued at login
+type Claims struct {
+	Subject   string `json:"sub"`
+	Role      string `json:"role"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// LoginRequest is the body of POST /api/v1/auth/login
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// LoginResponse carries an issued token
+type LoginResponse struct {
+	Token     string    `json:"token"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type claimsKey struct{}
+
+// jwtHeader is the fixed header of every token; only HS256 is accepted
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Authenticator issues and verifies HS256 JWTs and guards routes with them
+type Authenticator struct {
+	logger *zap.Logger
+	secret []byte
+	ttl    time.Duration
+	users  map[string]AuthUser
+	public map[string]bool
+}
+
+// NewAuthenticator creates an authenticator. The secret must be at least 32
+// bytes and every user needs a known role.
+func NewAuthenticator(logger *zap.Logger, config AuthConfig) (*Authenticator, error) {
+	if len(config.Secret) < minSecretLength {
+		return nil, fmt.Errorf("auth secret must be at least %d bytes", minSecretLength)
+	}
+	if config.TokenTTL <= 0 {
+		return nil, errors.New("token TTL must be positive")
+	}
+
+	users := make(map[string]AuthUser, len(config.Users))
+	for _, user := range config.Users {
+		if user.Username == "" || user.Password == "" {
+			return nil, errors.New("auth users need a username and password")
+		}
+		if user.Role != RoleRead && user.Role != RoleTrade {
+			return nil, fmt.Errorf("unknown role %q for user %s", user.Role, user.Username)
+		}
+		users[user.Username] = user
+	}
+
+	return &Authenticator{
+		logger: logger.Named("auth"),
+		secret: []byte(config.Secret),
+		ttl:    config.TokenTTL,
+		users:  users,
+		public: map[string]bool{
+			"/api/v1/health":     true,
+			"/api/v1/auth/login": true,
+		},
+	}, nil
+}
+
+// ParseAuthUsers parses "name:password:role" entries separated by commas
+func ParseAuthUsers(spec string) ([]AuthUser, error) {
+	var users []AuthUser
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid user entry %q, want name:password:role", entry)
+		}
+		users = append(users, AuthUser{Username: parts[0], Password: parts[1], Role: parts[2]})
+	}
+	return users, nil
+}
+
+// IssueToken signs a token for username with role
+func (a *Authenticator) IssueToken(username, role string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(a.ttl)
+
+	payload, err := json.Marshal(Claims{
+		Subject:   username,
+		Role:      role,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to encode claims: %w", err)
+	}
+
+	signingInput := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + a.sign(signingInput), expiresAt, nil
+}
+
+// ParseToken verifies a token's signature and expiry and returns its claims
+func (a *Authenticator) ParseToken(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return nil, ErrInvalidToken
+	}
+
+	expected := a.sign(parts[0] + "." + parts[1])
+	if !hmac.Equal([]byte(parts[2]), []byte(expected)) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+	return &claims, nil
+}
+
+// sign returns the base64url HMAC-SHA256 of input
+func (a *Authenticator) sign(input string) string {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(input))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Middleware rejects requests without a valid token with 401, and requests
+// a read-only token may not make with 403. Tokens come from the
+// Authorization header, or the token query parameter on WebSocket upgrades
+// since browsers cannot set headers there.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.public[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token := bearerToken(r)
+		if token == "" && websocket.IsWebSocketUpgrade(r) {
+			token = r.URL.Query().Get("token")
+		}
+		if token == "" {
+			unauthorized(w, "missing bearer token")
+			return
+		}
+
+		claims, err := a.ParseToken(token)
+		if err != nil {
+			unauthorized(w, err.Error())
+			return
+		}
+
+		if !allowed(claims.Role, r.Method) {
+			a.logger.Warn("Forbidden request",
+				zap.String("user", claims.Subject),
+				zap.String("role", claims.Role),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+			)
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsKey{}, claims)))
+	})
+}
+
+// HandleLogin handles POST /api/v1/auth/login
+func (a *Authenticator) HandleLogin(w http.ResponseWriter, r *http.Request) {
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := a.users[req.Username]
+	// Compare digests so timing does not leak the password length
+	given := sha256.Sum256([]byte(req.Password))
+	want := sha256.Sum256([]byte(user.Password))
+	if !ok || subtle.ConstantTimeCompare(given[:], want[:]) != 1 {
+		a.logger.Warn("Failed login", zap.String("user", req.Username))
+		unauthorized(w, "invalid credentials")
+		return
+	}
+
+	token, expiresAt, err := a.IssueToken(user.Username, user.Role)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	a.logger.Info("User logged in", zap.String("user", user.Username), zap.String("role", user.Role))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(LoginResponse{
+		Token:     token,
+		Role:      user.Role,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// ClaimsFromContext returns the claims of an authenticated request
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
+
+// allowed reports whether role may make a request with method
+func allowed(role, method string) bool {
+	switch role {
+	case RoleTrade:
+		return true
+	case RoleRead:
+		return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+	default:
+		return false
+	}
+}
+
+// bearerToken extracts the token from an Authorization: Bearer header
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// unauthorized writes a 401 with a bearer challenge
+func unauthorized(w http.ResponseWriter, reason string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="atlas"`)
+	http.Error(w, "Unauthorized: "+reason, http.StatusUnauthorized)
+}
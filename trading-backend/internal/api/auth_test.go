@@ -0,0 +1,231 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/api"
+	"github.com/atlas-desktop/trading-backend/internal/data"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func newTestAuthRouter(t *testing.T) (*api.Authenticator, *mux.Router) {
+	config := api.DefaultAuthConfig()
+	config.Secret = testSecret
+	config.Users = []api.AuthUser{
+		{Username: "viewer", Password: "view-pass", Role: api.RoleRead},
+		{Username: "trader", Password: "trade-pass", Role: api.RoleTrade},
+	}
+	auth, err := api.NewAuthenticator(zap.NewNop(), config)
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/health", ok).Methods("GET")
+	router.HandleFunc("/api/v1/auth/login", auth.HandleLogin).Methods("POST")
+	router.HandleFunc("/api/v1/agent/status", ok).Methods("GET")
+	router.HandleFunc("/api/v1/agent/emergency-stop", ok).Methods("POST")
+	router.HandleFunc("/ws", ok)
+	router.Use(auth.Middleware)
+	return auth, router
+}
+
+func login(t *testing.T, router http.Handler, username, password string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(api.LoginRequest{Username: username, Password: password})
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(body)))
+	return rec
+}
+
+func tokenFor(t *testing.T, router http.Handler, username, password string) string {
+	rec := login(t, router, username, password)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login %s: status %d", username, rec.Code)
+	}
+	var resp api.LoginResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return resp.Token
+}
+
+func TestAuthRolesGateRoutes(t *testing.T) {
+	_, router := newTestAuthRouter(t)
+	viewer := tokenFor(t, router, "viewer", "view-pass")
+	trader := tokenFor(t, router, "trader", "trade-pass")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"health is public", http.MethodGet, "/api/v1/health", "", http.StatusOK},
+		{"no token", http.MethodGet, "/api/v1/agent/status", "", http.StatusUnauthorized},
+		{"garbage token", http.MethodGet, "/api/v1/agent/status", "not.a.jwt", http.StatusUnauthorized},
+		{"viewer reads", http.MethodGet, "/api/v1/agent/status", viewer, http.StatusOK},
+		{"viewer cannot stop", http.MethodPost, "/api/v1/agent/emergency-stop", viewer, http.StatusForbidden},
+		{"trader stops", http.MethodPost, "/api/v1/agent/emergency-stop", trader, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestAuthRejectsBadLogin(t *testing.T) {
+	_, router := newTestAuthRouter(t)
+
+	if rec := login(t, router, "trader", "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong password: status = %d, want 401", rec.Code)
+	}
+	if rec := login(t, router, "nobody", "trade-pass"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("unknown user: status = %d, want 401", rec.Code)
+	}
+}
+
+func TestAuthTokenQueryOnlyForWebSocket(t *testing.T) {
+	_, router := newTestAuthRouter(t)
+	token := tokenFor(t, router, "viewer", "view-pass")
+
+	upgrade := httptest.NewRequest(http.MethodGet, "/ws?token="+token, nil)
+	upgrade.Header.Set("Connection", "Upgrade")
+	upgrade.Header.Set("Upgrade", "websocket")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, upgrade)
+	if rec.Code != http.StatusOK {
+		t.Errorf("upgrade with query token: status = %d, want 200", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/agent/status?token="+token, nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("REST with query token: status = %d, want 401", rec.Code)
+	}
+}
+
+func TestWebSocketReadRoleCannotRunBacktests(t *testing.T) {
+	dataStore, err := data.NewStore(zap.NewNop(), t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	auth, _ := newTestAuthRouter(t)
+	server := api.NewServer(zap.NewNop(), &types.ServerConfig{WebSocketPath: "/ws"}, dataStore)
+	server.SetAuthenticator(auth)
+	ts := httptest.NewServer(server.Router())
+	defer ts.Close()
+
+	// send issues one command as role and returns the response's error
+	send := func(role, method string, payload interface{}) string {
+		token, _, err := auth.IssueToken(role+"-user", role)
+		if err != nil {
+			t.Fatalf("IssueToken: %v", err)
+		}
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/ws?token="+token, nil)
+		if err != nil {
+			t.Fatalf("Dial: %v", err)
+		}
+		defer conn.Close()
+
+		if err := conn.WriteJSON(api.Message{ID: "1", Type: "request", Method: method, Payload: payload}); err != nil {
+			t.Fatalf("WriteJSON: %v", err)
+		}
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var response api.Message
+		if err := conn.ReadJSON(&response); err != nil {
+			t.Fatalf("ReadJSON: %v", err)
+		}
+		return response.Error
+	}
+
+	run := map[string]interface{}{"id": "ws-backtest", "symbols": []string{"BTC/USDT"}}
+	cancel := map[string]interface{}{"id": "ws-backtest"}
+	if got := send(api.RoleRead, "backtest:run", run); got != "Forbidden" {
+		t.Errorf("read role backtest:run: error = %q, want Forbidden", got)
+	}
+	if got := send(api.RoleRead, "backtest:cancel", cancel); got != "Forbidden" {
+		t.Errorf("read role backtest:cancel: error = %q, want Forbidden", got)
+	}
+
+	// The trade role gets past the role check to the lookup
+	if got := send(api.RoleTrade, "backtest:cancel", cancel); got != "Backtest not found" {
+		t.Errorf("trade role backtest:cancel: error = %q, want Backtest not found", got)
+	}
+}
+
+func TestParseTokenRejectsTamperingAndExpiry(t *testing.T) {
+	auth, _ := newTestAuthRouter(t)
+
+	token, _, err := auth.IssueToken("viewer", api.RoleRead)
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+	claims, err := auth.ParseToken(token)
+	if err != nil || claims.Subject != "viewer" || claims.Role != api.RoleRead {
+		t.Fatalf("ParseToken = %+v, %v", claims, err)
+	}
+
+	// Swap in a payload claiming the trade role, keeping the old signature
+	parts := strings.Split(token, ".")
+	forged, _, _ := auth.IssueToken("viewer", api.RoleTrade)
+	parts[1] = strings.Split(forged, ".")[1]
+	if _, err := auth.ParseToken(strings.Join(parts, ".")); err != api.ErrInvalidToken {
+		t.Errorf("tampered token: err = %v, want ErrInvalidToken", err)
+	}
+
+	config := api.DefaultAuthConfig()
+	config.Secret = testSecret
+	config.TokenTTL = time.Nanosecond
+	shortLived, err := api.NewAuthenticator(zap.NewNop(), config)
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+	expired, _, _ := shortLived.IssueToken("viewer", api.RoleRead)
+	time.Sleep(time.Second)
+	if _, err := auth.ParseToken(expired); err != api.ErrTokenExpired {
+		t.Errorf("expired token: err = %v, want ErrTokenExpired", err)
+	}
+}
+
+func TestNewAuthenticatorValidatesConfig(t *testing.T) {
+	config := api.DefaultAuthConfig()
+	config.Secret = "short"
+	if _, err := api.NewAuthenticator(zap.NewNop(), config); err == nil {
+		t.Error("short secret accepted")
+	}
+
+	config.Secret = testSecret
+	config.Users = []api.AuthUser{{Username: "admin", Password: "pw", Role: "root"}}
+	if _, err := api.NewAuthenticator(zap.NewNop(), config); err == nil {
+		t.Error("unknown role accepted")
+	}
+
+	users, err := api.ParseAuthUsers("viewer:a:read, trader:b:trade")
+	if err != nil || len(users) != 2 || users[1].Role != api.RoleTrade {
+		t.Errorf("ParseAuthUsers = %+v, %v", users, err)
+	}
+	if _, err := api.ParseAuthUsers("missing-role:pw"); err == nil {
+		t.Error("malformed user entry accepted")
+	}
+}
@@ -0,0 +1,349 @@
+// Package api provides on-demand backtests of named strategies, run on a
+// worker pool and polled by job ID.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/backtester"
+	"github.com/atlas-desktop/trading-backend/internal/workers"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// Backtest job statuses
+const (
+	BacktestJobQueued    = "queued"
+	BacktestJobRunning   = "running"
+	BacktestJobCompleted = "completed"
+	BacktestJobFailed    = "failed"
+)
+
+// BacktestStrategyFactory creates a fresh strategy configured with params
+type BacktestStrategyFactory func(params map[string]float64) (backtester.Strategy, error)
+
+// BacktestJobsConfig configures the account on-demand backtests simulate
+type BacktestJobsConfig struct {
+	InitialCapital decimal.Decimal      `json:"initialCapital"`
+	Commission     decimal.Decimal      `json:"commission"`
+	Slippage       types.SlippageConfig `json:"slippage"`
+	RiskLimits     types.RiskLimits     `json:"riskLimits"`
+	MaxRetained    int                  `json:"maxRetained"` // Finished jobs kept for polling
+}
+
+// DefaultBacktestJobsConfig returns a 10k account with 0.1% commission
+func DefaultBacktestJobsConfig() BacktestJobsConfig {
+	return BacktestJobsConfig{
+		InitialCapital: decimal.NewFromInt(10000),
+		Commission:     decimal.NewFromFloat(0.001),
+		RiskLimits: types.RiskLimits{
+			MaxPositionSize:  decimal.NewFromFloat(0.5),
+			MaxDrawdown:      decimal.NewFromFloat(0.3),
+			MaxDailyLoss:     decimal.NewFromFloat(0.1),
+			MaxOpenPositions: 1,
+		},
+		MaxRetained: 100,
+	}
+}
+
+// BacktestRequest is the body of POST /api/v1/backtest
+type BacktestRequest struct {
+	Strategy  string             `json:"strategy"`
+	Symbol    string             `json:"symbol"`
+	Params    map[string]float64 `json:"params,omitempty"`
+	Start     time.Time          `json:"start"`
+	End       time.Time          `json:"end"`
+	Timeframe types.Timeframe    `json:"timeframe"`
+}
+
+// BacktestJob is the state of an on-demand backtest
+type BacktestJob struct {
+	ID          string                      `json:"id"`
+	Status      string                      `json:"status"`
+	Request     BacktestRequest             `json:"request"`
+	Progress    *types.BacktestProgress     `json:"progress,omitempty"`
+	Results     *backtester.BacktestResults `json:"results,omitempty"`
+	EquityCurve []types.EquityCurvePoint    `json:"equityCurve,omitempty"`
+	Error       string                      `json:"error,omitempty"`
+	SubmittedAt time.Time                   `json:"submittedAt"`
+	CompletedAt time.Time                   `json:"completedAt,omitempty"`
+}
+
+// BacktestJobs runs backtests of registered strategies on a worker pool and
+// streams their progress over the WebSocket hub
+type BacktestJobs struct {
+	logger     *zap.Logger
+	config     BacktestJobsConfig
+	dataLoader backtester.DataLoader
+	pool       *workers.Pool
+	hub        *Hub
+
+	mu         sync.RWMutex
+	strategies map[string]BacktestStrategyFactory
+	jobs       map[string]*BacktestJob
+	finished   []string // Finished job IDs, oldest first
+}
+
+// NewBacktestJobs creates a job runner. hub may be nil to skip streaming.
+func NewBacktestJobs(
+	logger *zap.Logger,
+	config BacktestJobsConfig,
+	dataLoader backtester.DataLoader,
+	pool *workers.Pool,
+	hub *Hub,
+) *BacktestJobs {
+	return &BacktestJobs{
+		logger:     logger.Named("backtest-jobs"),
+		config:     config,
+		dataLoader: dataLoader,
+		pool:       pool,
+		hub:        hub,
+		strategies: make(map[string]BacktestStrategyFactory),
+		jobs:       make(map[string]*BacktestJob),
+	}
+}
+
+// RegisterStrategy makes a strategy available to backtest by name
+func (bj *BacktestJobs) RegisterStrategy(name string, factory BacktestStrategyFactory) {
+	bj.mu.Lock()
+	defer bj.mu.Unlock()
+	bj.strategies[name] = factory
+}
+
+// Strategies returns the registered strategy names, sorted
+func (bj *BacktestJobs) Strategies() []string {
+	bj.mu.RLock()
+	defer bj.mu.RUnlock()
+
+	names := make([]string, 0, len(bj.strategies))
+	for name := range bj.strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Submit validates a request and queues it on the worker pool
+func (bj *BacktestJobs) Submit(req BacktestRequest) (*BacktestJob, error) {
+	if req.Symbol == "" {
+		return nil, errors.New("symbol is required")
+	}
+	if req.Timeframe == "" {
+		return nil, errors.New("timeframe is required")
+	}
+	if !req.End.After(req.Start) {
+		return nil, errors.New("end must be after start")
+	}
+
+	bj.mu.RLock()
+	factory, ok := bj.strategies[req.Strategy]
+	bj.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy: %s", req.Strategy)
+	}
+
+	// Build one up front so bad params fail the request, not the job
+	if _, err := factory(req.Params); err != nil {
+		return nil, err
+	}
+
+	job := &BacktestJob{
+		ID:          uuid.New().String(),
+		Status:      BacktestJobQueued,
+		Request:     req,
+		SubmittedAt: time.Now(),
+	}
+
+	bj.mu.Lock()
+	bj.jobs[job.ID] = job
+	bj.mu.Unlock()
+
+	err := bj.pool.Submit(workers.TaskFunc(func() error {
+		return bj.run(job.ID, req, factory)
+	}))
+	if err != nil {
+		bj.mu.Lock()
+		delete(bj.jobs, job.ID)
+		bj.mu.Unlock()
+		return nil, fmt.Errorf("failed to queue backtest: %w", err)
+	}
+
+	bj.logger.Info("Backtest queued",
+		zap.String("id", job.ID),
+		zap.String("strategy", req.Strategy),
+		zap.String("symbol", req.Symbol),
+	)
+
+	return bj.snapshot(job), nil
+}
+
+// Get returns a copy of a job
+func (bj *BacktestJobs) Get(id string) (*BacktestJob, bool) {
+	bj.mu.RLock()
+	defer bj.mu.RUnlock()
+
+	job, ok := bj.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	copied := *job
+	return &copied, true
+}
+
+// run executes a job's backtest, publishing progress as it goes
+func (bj *BacktestJobs) run(id string, req BacktestRequest, factory BacktestStrategyFactory) error {
+	engine := backtester.NewEngine(bj.logger, bj.dataLoader, backtester.CreateSlippageModel(bj.config.Slippage))
+	engine.SetStrategyFactory(func() backtester.Strategy {
+		// Params were validated on submit
+		strategy, _ := factory(req.Params)
+		return strategy
+	})
+
+	config := &types.BacktestConfig{
+		ID:             id,
+		Strategy:       types.StrategyConfig{Name: req.Strategy},
+		Symbols:        []string{req.Symbol},
+		StartDate:      req.Start,
+		EndDate:        req.End,
+		Timeframe:      req.Timeframe,
+		InitialCapital: bj.config.InitialCapital,
+		Commission:     bj.config.Commission,
+		Slippage:       bj.config.Slippage,
+		RiskLimits:     bj.config.RiskLimits,
+	}
+
+	bj.update(id, func(job *BacktestJob) {
+		job.Status = BacktestJobRunning
+	})
+
+	done := make(chan struct{})
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		for {
+			select {
+			case progress := <-engine.ProgressChan():
+				bj.update(id, func(job *BacktestJob) { job.Progress = progress })
+				bj.publish(progress)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	result, err := engine.Run(context.Background(), config)
+	close(done)
+	<-drained
+
+	final := &types.BacktestProgress{ID: id, Status: BacktestJobCompleted, Progress: 100}
+	if err != nil {
+		final.Status = BacktestJobFailed
+		final.Error = err.Error()
+		bj.logger.Warn("Backtest failed", zap.String("id", id), zap.Error(err))
+	} else {
+		final.EventsProcessed = result.EventsProcessed
+		final.TotalEvents = result.EventsProcessed
+		final.TradesExecuted = len(result.Trades)
+		if n := len(result.EquityCurve); n > 0 {
+			final.CurrentDate = result.EquityCurve[n-1].Timestamp
+			final.CurrentEquity = result.EquityCurve[n-1].Equity
+		}
+	}
+
+	bj.update(id, func(job *BacktestJob) {
+		job.Status = final.Status
+		job.Progress = final
+		job.Error = final.Error
+		job.CompletedAt = time.Now()
+		if result != nil {
+			job.Results = backtester.Summarize(result)
+			job.EquityCurve = result.EquityCurve
+		}
+	})
+	bj.retire(id)
+	bj.publish(final)
+
+	return err
+}
+
+// update applies fn to a job under the lock
+func (bj *BacktestJobs) update(id string, fn func(job *BacktestJob)) {
+	bj.mu.Lock()
+	defer bj.mu.Unlock()
+	if job, ok := bj.jobs[id]; ok {
+		fn(job)
+	}
+}
+
+// retire marks a job finished and drops the oldest finished jobs beyond the
+// retention limit
+func (bj *BacktestJobs) retire(id string) {
+	bj.mu.Lock()
+	defer bj.mu.Unlock()
+
+	bj.finished = append(bj.finished, id)
+	for len(bj.finished) > bj.config.MaxRetained {
+		delete(bj.jobs, bj.finished[0])
+		bj.finished = bj.finished[1:]
+	}
+}
+
+// publish streams progress over the hub
+func (bj *BacktestJobs) publish(progress *types.BacktestProgress) {
+	if bj.hub != nil {
+		bj.hub.BroadcastBacktestProgress(progress)
+	}
+}
+
+// snapshot returns a copy of a job taken under the lock
+func (bj *BacktestJobs) snapshot(job *BacktestJob) *BacktestJob {
+	bj.mu.RLock()
+	defer bj.mu.RUnlock()
+	copied := *job
+	return &copied
+}
+
+// HandleSubmit handles POST /api/v1/backtest
+func (bj *BacktestJobs) HandleSubmit(w http.ResponseWriter, r *http.Request) {
+	var req BacktestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	job, err := bj.Submit(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, workers.ErrQueueFull) || errors.Is(err, workers.ErrPoolStopped) {
+			status = http.StatusServiceUnavailable
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/api/v1/backtest/"+job.ID)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+// ServeJob writes a job as JSON, reporting whether it exists
+func (bj *BacktestJobs) ServeJob(w http.ResponseWriter, id string) bool {
+	job, ok := bj.Get(id)
+	if !ok {
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+	return true
+}
@@ -0,0 +1,159 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/api"
+	"github.com/atlas-desktop/trading-backend/internal/backtester"
+	"github.com/atlas-desktop/trading-backend/internal/events"
+	"github.com/atlas-desktop/trading-backend/internal/workers"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// jobBarLoader serves the same daily bars for any symbol
+type jobBarLoader struct {
+	bars []*types.OHLCV
+}
+
+func (l *jobBarLoader) LoadOHLCV(ctx context.Context, symbol string, timeframe types.Timeframe, start, end time.Time) ([]*types.OHLCV, error) {
+	return l.bars, nil
+}
+
+func (l *jobBarLoader) LoadTicks(ctx context.Context, symbol string, start, end time.Time) ([]*types.Tick, error) {
+	return nil, nil
+}
+
+func (l *jobBarLoader) GetAvailableSymbols() []string { return nil }
+
+func (l *jobBarLoader) GetDataRange(symbol string) (time.Time, time.Time, error) {
+	return time.Time{}, time.Time{}, nil
+}
+
+// flipStrategy buys, then sells every hold bars
+type flipStrategy struct {
+	hold int
+	bars int
+}
+
+func (s *flipStrategy) Name() string { return "flip" }
+
+func (s *flipStrategy) OnBar(bar *events.BarEvent) (*types.Signal, error) {
+	s.bars++
+	if s.bars%s.hold != 0 {
+		return nil, nil
+	}
+	if (s.bars/s.hold)%2 == 0 {
+		return &types.Signal{Side: types.OrderSideSell, Type: types.SignalTypeExit}, nil
+	}
+	return &types.Signal{Side: types.OrderSideBuy, Type: types.SignalTypeEntry}, nil
+}
+
+func newTestBacktestJobs(t *testing.T) *api.BacktestJobs {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := make([]*types.OHLCV, 20)
+	for i := range bars {
+		price := decimal.NewFromInt(int64(100 + i))
+		bars[i] = &types.OHLCV{Timestamp: start.AddDate(0, 0, i), Open: price, High: price, Low: price, Close: price}
+	}
+
+	pool := workers.NewPool(zap.NewNop(), workers.DefaultPoolConfig("backtest-test"))
+	pool.Start()
+	t.Cleanup(func() { pool.Stop() })
+
+	jobs := api.NewBacktestJobs(zap.NewNop(), api.DefaultBacktestJobsConfig(), &jobBarLoader{bars: bars}, pool, nil)
+	jobs.RegisterStrategy("flip", func(params map[string]float64) (backtester.Strategy, error) {
+		hold, ok := params["hold"]
+		if !ok || hold < 1 {
+			return nil, errors.New("hold must be at least 1")
+		}
+		return &flipStrategy{hold: int(hold)}, nil
+	})
+	return jobs
+}
+
+func TestBacktestJobRunsToCompletion(t *testing.T) {
+	jobs := newTestBacktestJobs(t)
+	handler := http.HandlerFunc(jobs.HandleSubmit)
+
+	body, _ := json.Marshal(api.BacktestRequest{
+		Strategy:  "flip",
+		Symbol:    "BTCUSDT",
+		Params:    map[string]float64{"hold": 2},
+		Start:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:       time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC),
+		Timeframe: types.Timeframe1d,
+	})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/backtest", bytes.NewReader(body)))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("submit status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var submitted api.BacktestJob
+	if err := json.NewDecoder(rec.Body).Decode(&submitted); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	var job *api.BacktestJob
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, _ = jobs.Get(submitted.ID)
+		if job.Status == api.BacktestJobCompleted || job.Status == api.BacktestJobFailed {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if job.Status != api.BacktestJobCompleted {
+		t.Fatalf("job status = %s (%s), want completed", job.Status, job.Error)
+	}
+	if job.Results == nil || job.Results.TradeCount == 0 {
+		t.Fatalf("results = %+v, want closed trades", job.Results)
+	}
+	if len(job.EquityCurve) != 20 || len(job.Results.EquityCurve) != 20 {
+		t.Errorf("equity curve has %d points, want 20", len(job.EquityCurve))
+	}
+	if job.Progress == nil || job.Progress.Progress != 100 {
+		t.Errorf("final progress = %+v, want 100", job.Progress)
+	}
+}
+
+func TestBacktestJobRejectsBadRequests(t *testing.T) {
+	jobs := newTestBacktestJobs(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	valid := api.BacktestRequest{
+		Strategy:  "flip",
+		Symbol:    "BTCUSDT",
+		Params:    map[string]float64{"hold": 2},
+		Start:     start,
+		End:       start.AddDate(0, 0, 20),
+		Timeframe: types.Timeframe1d,
+	}
+
+	unknown := valid
+	unknown.Strategy = "missing"
+	badParams := valid
+	badParams.Params = nil
+	backwards := valid
+	backwards.End = start.AddDate(0, 0, -1)
+
+	for name, req := range map[string]api.BacktestRequest{
+		"unknown strategy": unknown,
+		"bad params":       badParams,
+		"end before start": backwards,
+	} {
+		if _, err := jobs.Submit(req); err == nil {
+			t.Errorf("%s: Submit succeeded", name)
+		}
+	}
+}
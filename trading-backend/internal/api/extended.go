@@ -49,7 +49,7 @@ func NewExtendedServer(
 		signalAgg:    signalAgg,
 		feedback:     feedback,
 		optimizer:    optimizer,
-		analyzer:     learning.NewPerformanceAnalyzer(logger),
+		analyzer:     learning.NewPerformanceAnalyzer(logger, learning.DefaultPerformanceConfig()),
 	}
 	
 	es.setupRoutes()
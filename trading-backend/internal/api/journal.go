@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/learning"
+	"go.uber.org/zap"
+)
+
+// journalHandlers serves the trade journal.
+type journalHandlers struct {
+	logger   *zap.Logger
+	journal  *learning.TradeJournal
+	analyzer *learning.PerformanceAnalyzer
+}
+
+// SetTradeJournal registers the trade journal routes: listing and a
+// performance report filtered by symbol, strategy and RFC 3339 from/to exit
+// times, CSV export, and CSV import.
+func (s *Server) SetTradeJournal(journal *learning.TradeJournal) {
+	h := &journalHandlers{
+		logger:   s.logger,
+		journal:  journal,
+		analyzer: learning.NewPerformanceAnalyzer(s.logger, learning.DefaultPerformanceConfig()),
+	}
+	s.router.HandleFunc("/api/v1/journal", h.handleQuery).Methods("GET")
+	s.router.HandleFunc("/api/v1/journal/report", h.handleReport).Methods("GET")
+	s.router.HandleFunc("/api/v1/journal/export", h.handleExport).Methods("GET")
+	s.router.HandleFunc("/api/v1/journal/import", h.handleImport).Methods("POST")
+}
+
+// journalQuery parses a journal filter from query parameters.
+func journalQuery(values url.Values) (learning.JournalQuery, error) {
+	q := learning.JournalQuery{
+		Symbol:   values.Get("symbol"),
+		Strategy: values.Get("strategy"),
+	}
+	for name, dst := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+		if value := values.Get(name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return q, fmt.Errorf("invalid %s time %q", name, value)
+			}
+			*dst = t
+		}
+	}
+	return q, nil
+}
+
+// handleQuery lists journaled trades
+func (h *journalHandlers) handleQuery(w http.ResponseWriter, r *http.Request) {
+	q, err := journalQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries := h.journal.Query(q)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"trades": entries,
+		"count":  len(entries),
+	})
+}
+
+// handleReport analyzes journaled trades
+func (h *journalHandlers) handleReport(w http.ResponseWriter, r *http.Request) {
+	q, err := journalQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = "journal"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.analyzer.AnalyzeJournal(h.journal, q, period))
+}
+
+// handleExport downloads the journal as CSV
+func (h *journalHandlers) handleExport(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="journal.csv"`)
+	if err := h.journal.ExportCSV(w); err != nil {
+		h.logger.Error("Failed to export journal", zap.Error(err))
+	}
+}
+
+// handleImport records the trades in a CSV request body
+func (h *journalHandlers) handleImport(w http.ResponseWriter, r *http.Request) {
+	imported, err := h.journal.ImportCSV(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"imported": imported})
+}
@@ -3,11 +3,14 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/atlas-desktop/trading-backend/internal/autonomous"
+	"github.com/atlas-desktop/trading-backend/internal/montecarlo"
 	"github.com/atlas-desktop/trading-backend/internal/orchestrator"
 	"github.com/atlas-desktop/trading-backend/internal/regime"
 	"github.com/atlas-desktop/trading-backend/internal/sizing"
@@ -45,6 +48,7 @@ func (h *PhDHandlers) RegisterRoutes(r *mux.Router) {
 	// Position Sizing Endpoints
 	r.HandleFunc("/api/v1/sizing/calculate", h.CalculatePositionSize).Methods("POST")
 	r.HandleFunc("/api/v1/sizing/kelly", h.GetKellySize).Methods("POST")
+	r.HandleFunc("/api/v1/sizing/correlations", h.GetCorrelations).Methods("GET")
 
 	// Monte Carlo Endpoints
 	r.HandleFunc("/api/v1/montecarlo/validate", h.RunMonteCarloValidation).Methods("POST")
@@ -75,16 +79,20 @@ func (h *PhDHandlers) RegisterRoutes(r *mux.Router) {
 
 // ==================== Regime Detection Endpoints ====================
 
-// GetCurrentRegime returns the current market regime.
+// GetCurrentRegime returns the current regime of the symbol query parameter,
+// or the portfolio-level regime without one.
 func (h *PhDHandlers) GetCurrentRegime(w http.ResponseWriter, r *http.Request) {
-	regimeType, confidence := h.orchestrator.GetCurrentRegime()
-	adjustments := h.orchestrator.GetStrategyAdjustments()
+	symbol := r.URL.Query().Get("symbol")
+	regimeType, confidence := h.orchestrator.GetCurrentRegime(symbol)
+	adjustments := h.orchestrator.GetStrategyAdjustments(symbol)
 
 	response := CurrentRegimeResponse{
-		Regime:      string(regimeType),
-		Confidence:  confidence,
-		Adjustments: adjustments,
-		Timestamp:   time.Now(),
+		Symbol:        symbol,
+		Regime:        string(regimeType),
+		Confidence:    confidence,
+		Probabilities: h.orchestrator.GetRegimeProbabilities(symbol),
+		Adjustments:   adjustments,
+		Timestamp:     time.Now(),
 	}
 
 	h.writeJSON(w, response)
@@ -92,10 +100,12 @@ func (h *PhDHandlers) GetCurrentRegime(w http.ResponseWriter, r *http.Request) {
 
 // CurrentRegimeResponse represents the current regime.
 type CurrentRegimeResponse struct {
-	Regime      string                     `json:"regime"`
-	Confidence  float64                    `json:"confidence"`
-	Adjustments regime.StrategyAdjustments `json:"adjustments"`
-	Timestamp   time.Time                  `json:"timestamp"`
+	Symbol        string                        `json:"symbol,omitempty"`
+	Regime        string                        `json:"regime"`
+	Confidence    float64                       `json:"confidence"`
+	Probabilities map[regime.RegimeType]float64 `json:"probabilities,omitempty"`
+	Adjustments   regime.StrategyAdjustments    `json:"adjustments"`
+	Timestamp     time.Time                     `json:"timestamp"`
 }
 
 // GetRegimeHistory returns recent regime transitions.
@@ -123,9 +133,10 @@ type RegimeHistoryResponse struct {
 	Count       int                             `json:"count"`
 }
 
-// GetRegimeAdjustments returns current regime-based strategy adjustments.
+// GetRegimeAdjustments returns regime-based strategy adjustments for the
+// symbol query parameter, or for the portfolio-level regime without one.
 func (h *PhDHandlers) GetRegimeAdjustments(w http.ResponseWriter, r *http.Request) {
-	adjustments := h.orchestrator.GetStrategyAdjustments()
+	adjustments := h.orchestrator.GetStrategyAdjustments(r.URL.Query().Get("symbol"))
 	h.writeJSON(w, adjustments)
 }
 
@@ -250,6 +261,18 @@ type KellySizeResponse struct {
 	EdgePercent     float64 `json:"edgePercent"`
 }
 
+// GetCorrelations returns the EWMA correlations estimated from bars between
+// the comma-separated symbols query parameter, or between every symbol with
+// bars without one.
+func (h *PhDHandlers) GetCorrelations(w http.ResponseWriter, r *http.Request) {
+	var symbols []string
+	if s := r.URL.Query().Get("symbols"); s != "" {
+		symbols = strings.Split(s, ",")
+	}
+
+	h.writeJSON(w, h.orchestrator.GetCorrelationMatrix(symbols))
+}
+
 // ==================== Monte Carlo Endpoints ====================
 
 // RunMonteCarloValidation runs Monte Carlo validation on trade history.
@@ -260,7 +283,15 @@ func (h *PhDHandlers) RunMonteCarloValidation(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	results := h.orchestrator.RunMonteCarloValidation(req.Trades)
+	results, err := h.orchestrator.RunMonteCarloValidation(req.Trades)
+	if errors.Is(err, montecarlo.ErrInsufficientTrades) {
+		h.writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
 
 	response := MonteCarloResponse{
 		Simulations:         results.NumSimulations,
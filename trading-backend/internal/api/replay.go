@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/atlas-desktop/trading-backend/internal/replay"
+	"github.com/gorilla/mux"
+)
+
+// replayHandlers serves historical replay sessions.
+type replayHandlers struct {
+	manager *replay.Manager
+}
+
+// SetReplayManager registers the replay routes: starting a session, listing
+// sessions, polling one, and stopping it. Register it in paper trading only;
+// a replay routes its symbols' orders to the simulated venue.
+func (s *Server) SetReplayManager(manager *replay.Manager) {
+	h := &replayHandlers{manager: manager}
+	s.router.HandleFunc("/api/v1/replay", h.handleStart).Methods("POST")
+	s.router.HandleFunc("/api/v1/replay", h.handleList).Methods("GET")
+	s.router.HandleFunc("/api/v1/replay/{id}", h.handleGet).Methods("GET")
+	s.router.HandleFunc("/api/v1/replay/{id}", h.handleStop).Methods("DELETE")
+}
+
+// handleStart starts a replay session
+func (h *replayHandlers) handleStart(w http.ResponseWriter, r *http.Request) {
+	var req replay.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.manager.Start(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, replay.ErrSessionRunning) {
+			status = http.StatusConflict
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/api/v1/replay/"+session.ID)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(session)
+}
+
+// handleList lists retained replay sessions, newest first
+func (h *replayHandlers) handleList(w http.ResponseWriter, r *http.Request) {
+	sessions := h.manager.List()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sessions": sessions,
+		"count":    len(sessions),
+	})
+}
+
+// handleGet returns a replay session
+func (h *replayHandlers) handleGet(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.manager.Get(mux.Vars(r)["id"])
+	if !ok {
+		http.Error(w, "Replay session not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+// handleStop stops a running replay session
+func (h *replayHandlers) handleStop(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if _, ok := h.manager.Get(id); !ok {
+		http.Error(w, "Replay session not found", http.StatusNotFound)
+		return
+	}
+	if err := h.manager.Stop(id); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	session, _ := h.manager.Get(id)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
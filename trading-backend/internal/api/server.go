@@ -11,6 +11,8 @@ import (
 
 	"github.com/atlas-desktop/trading-backend/internal/backtester"
 	"github.com/atlas-desktop/trading-backend/internal/data"
+	"github.com/atlas-desktop/trading-backend/internal/health"
+	"github.com/atlas-desktop/trading-backend/internal/strategy"
 	"github.com/atlas-desktop/trading-backend/pkg/types"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -21,16 +23,26 @@ import (
 
 // Server is the HTTP/WebSocket API server
 type Server struct {
-	mu            sync.RWMutex
-	logger        *zap.Logger
-	config        *types.ServerConfig
-	router        *mux.Router
-	httpServer    *http.Server
-	upgrader      websocket.Upgrader
-	clients       map[string]*Client
-	dataStore     *data.Store
-	engine        *backtester.Engine
-	backtests     map[string]*BacktestState
+	mu             sync.RWMutex
+	logger         *zap.Logger
+	config         *types.ServerConfig
+	router         *mux.Router
+	httpServer     *http.Server
+	upgrader       websocket.Upgrader
+	clients        map[string]*Client
+	dataStore      *data.Store
+	engine         *backtester.Engine
+	backtests      map[string]*BacktestState
+	backtestJobs   *BacktestJobs
+	health         *health.Monitor
+	slippage       SlippageCalibrator
+	liveStrategies *strategy.LiveRegistry
+}
+
+// SlippageCalibrator supplies backtest slippage fit to live fills, for
+// backtests whose slippage model is "calibrated".
+type SlippageCalibrator interface {
+	CalibratedBacktestSlippage(symbols []string) (types.SlippageConfig, bool)
 }
 
 // Client represents a WebSocket client
@@ -39,6 +51,8 @@ type Client struct {
 	Conn     *websocket.Conn
 	Send     chan []byte
 	Subs     map[string]bool // Subscriptions
+	User     string          // Authenticated user, for audit
+	Role     string          // Authenticated role, empty when authentication is off
 }
 
 // BacktestState tracks a running backtest
@@ -93,6 +107,7 @@ func (s *Server) setupRoutes() {
 	s.router.HandleFunc("/api/v1/data/history/{symbol}", s.handleGetHistory).Methods("GET")
 	
 	// Backtest endpoints
+	s.router.HandleFunc("/api/v1/backtest", s.handleSubmitBacktest).Methods("POST")
 	s.router.HandleFunc("/api/v1/backtest/run", s.handleRunBacktest).Methods("POST")
 	s.router.HandleFunc("/api/v1/backtest/{id}", s.handleGetBacktest).Methods("GET")
 	s.router.HandleFunc("/api/v1/backtest/{id}/trades", s.handleGetBacktestTrades).Methods("GET")
@@ -102,6 +117,65 @@ func (s *Server) setupRoutes() {
 	s.router.HandleFunc(s.config.WebSocketPath, s.handleWebSocket)
 }
 
+// Router returns the server's router, so other handlers can register routes
+// on it.
+func (s *Server) Router() *mux.Router {
+	return s.router
+}
+
+// SetBacktestJobs enables on-demand backtests of named strategies. Their
+// jobs are polled at the same path as other backtests.
+func (s *Server) SetBacktestJobs(jobs *BacktestJobs) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.backtestJobs = jobs
+}
+
+// SetHealthMonitor makes the health endpoint report the monitor's last check
+// instead of a bare liveness response.
+func (s *Server) SetHealthMonitor(monitor *health.Monitor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health = monitor
+}
+
+// SetSlippageCalibrator lets backtests ask for the slippage model calibrated
+// from live fills.
+func (s *Server) SetSlippageCalibrator(calibrator SlippageCalibrator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.slippage = calibrator
+}
+
+// backtestSlippageModel builds a backtest's slippage model. A "calibrated"
+// model uses each symbol's live-fill fit, and the default model for symbols
+// without one.
+func (s *Server) backtestSlippageModel(config *types.BacktestConfig) backtester.SlippageModel {
+	if config.Slippage.Model != "calibrated" {
+		return backtester.CreateSlippageModel(config.Slippage)
+	}
+	
+	s.mu.RLock()
+	calibrator := s.slippage
+	s.mu.RUnlock()
+	
+	if calibrator != nil {
+		if calibrated, ok := calibrator.CalibratedBacktestSlippage(config.Symbols); ok {
+			return backtester.CreateSlippageModel(calibrated)
+		}
+	}
+	s.logger.Warn("No calibrated slippage for backtest symbols, using the default model",
+		zap.Strings("symbols", config.Symbols))
+	return backtester.CreateSlippageModel(types.SlippageConfig{})
+}
+
+// SetAuthenticator registers the login route and requires a bearer token on
+// every other route except health, including routes added to Router() later.
+func (s *Server) SetAuthenticator(auth *Authenticator) {
+	s.router.HandleFunc("/api/v1/auth/login", auth.HandleLogin).Methods("POST")
+	s.router.Use(auth.Middleware)
+}
+
 // Start starts the HTTP server
 func (s *Server) Start() error {
 	addr := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
@@ -137,12 +211,32 @@ func (s *Server) Stop(ctx context.Context) error {
 	return s.httpServer.Shutdown(ctx)
 }
 
-// handleHealth handles health check requests
+// handleHealth handles health check requests. With a health monitor it
+// returns per-component status and answers 503 while the system is red, so
+// load balancers take the instance out of rotation.
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"status": "healthy",
-		"time":   time.Now().Unix(),
-	})
+	s.mu.RLock()
+	monitor := s.health
+	s.mu.RUnlock()
+	
+	if monitor == nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "healthy",
+			"time":   time.Now().Unix(),
+		})
+		return
+	}
+	
+	report := monitor.Latest()
+	if report == nil {
+		report = monitor.Check(r.Context())
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status == health.StatusRed {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
 }
 
 // handleGetSymbols returns available symbols
@@ -215,7 +309,7 @@ func (s *Server) handleRunBacktest(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	// Create engine and slippage model
-	slippageModel := backtester.CreateSlippageModel(config.Slippage)
+	slippageModel := s.backtestSlippageModel(&config)
 	engine := backtester.NewEngine(s.logger, s.dataStore, slippageModel)
 	
 	// Track backtest state
@@ -275,6 +369,20 @@ func (s *Server) handleRunBacktest(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleSubmitBacktest queues a backtest of a named strategy
+func (s *Server) handleSubmitBacktest(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	jobs := s.backtestJobs
+	s.mu.RUnlock()
+	
+	if jobs == nil {
+		http.Error(w, "Strategy backtests not enabled", http.StatusServiceUnavailable)
+		return
+	}
+	
+	jobs.HandleSubmit(w, r)
+}
+
 // handleGetBacktest returns backtest results
 func (s *Server) handleGetBacktest(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -282,9 +390,13 @@ func (s *Server) handleGetBacktest(w http.ResponseWriter, r *http.Request) {
 	
 	s.mu.RLock()
 	state, ok := s.backtests[id]
+	jobs := s.backtestJobs
 	s.mu.RUnlock()
 	
 	if !ok {
+		if jobs != nil && jobs.ServeJob(w, id) {
+			return
+		}
 		http.Error(w, "Backtest not found", http.StatusNotFound)
 		return
 	}
@@ -376,6 +488,10 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		Conn: conn,
 		Send: make(chan []byte, 256),
 		Subs: make(map[string]bool),
+		User: requestUser(r),
+	}
+	if claims, ok := ClaimsFromContext(r.Context()); ok {
+		client.Role = claims.Role
 	}
 	
 	s.mu.Lock()
@@ -470,7 +586,9 @@ func (s *Server) handleMessage(client *Client, msg *Message) {
 		
 	case "backtest:run":
 		config, ok := msg.Payload.(map[string]interface{})
-		if !ok {
+		if client.Role == RoleRead {
+			response.Error = "Forbidden"
+		} else if !ok {
 			response.Error = "Invalid payload"
 		} else {
 			// Parse config and start backtest
@@ -517,13 +635,41 @@ func (s *Server) handleMessage(client *Client, msg *Message) {
 		state, ok := s.backtests[id]
 		s.mu.RUnlock()
 		
-		if !ok {
+		switch {
+		case client.Role == RoleRead:
+			response.Error = "Forbidden"
+		case !ok:
 			response.Error = "Backtest not found"
-		} else {
+		default:
 			state.Engine.Cancel()
 			response.Payload = map[string]string{"status": "cancelled"}
 		}
 		
+	case "strategy:params":
+		payload, _ := msg.Payload.(map[string]interface{})
+		id, _ := payload["id"].(string)
+		params, _ := payload["params"].(map[string]interface{})
+		
+		s.mu.RLock()
+		live := s.liveStrategies
+		s.mu.RUnlock()
+		
+		switch {
+		case live == nil:
+			response.Error = "Live strategies not enabled"
+		case client.Role == RoleRead:
+			response.Error = "Forbidden"
+		case len(params) == 0:
+			response.Error = "No parameters given"
+		default:
+			changes, err := live.SetParameters(id, params, client.User, "ws")
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Payload = map[string]interface{}{"changes": changes}
+			}
+		}
+		
 	case "subscribe":
 		payload, _ := msg.Payload.(map[string]interface{})
 		channel, _ := payload["channel"].(string)
@@ -546,7 +692,7 @@ func (s *Server) handleMessage(client *Client, msg *Message) {
 
 // runBacktestAsync runs a backtest asynchronously
 func (s *Server) runBacktestAsync(config *types.BacktestConfig) {
-	slippageModel := backtester.CreateSlippageModel(config.Slippage)
+	slippageModel := s.backtestSlippageModel(config)
 	engine := backtester.NewEngine(s.logger, s.dataStore, slippageModel)
 	
 	state := &BacktestState{
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/atlas-desktop/trading-backend/internal/autonomous"
+	"github.com/atlas-desktop/trading-backend/internal/signals"
+)
+
+// SignalDecisions reports the trading agent's latest decision on a symbol's
+// signal. *autonomous.EnhancedTradingAgent implements it.
+type SignalDecisions interface {
+	LastDecision(symbol string) (autonomous.SignalDecision, bool)
+}
+
+// signalExplainHandlers serves signal aggregation breakdowns.
+type signalExplainHandlers struct {
+	aggregator *signals.Aggregator
+	decisions  SignalDecisions
+}
+
+// SignalExplainResponse is the body of GET /api/v1/signals/explain.
+type SignalExplainResponse struct {
+	Symbol      string                     `json:"symbol"`
+	Explanation *signals.SignalExplanation `json:"explanation,omitempty"`
+	Decision    *autonomous.SignalDecision `json:"decision,omitempty"`
+}
+
+// SetSignalExplainer registers GET /api/v1/signals/explain?symbol=, which
+// returns a symbol's latest aggregation broken down by source, and the
+// agent's decision on it when decisions is set. With refresh=true the
+// symbol is aggregated again first.
+func (s *Server) SetSignalExplainer(aggregator *signals.Aggregator, decisions SignalDecisions) {
+	h := &signalExplainHandlers{aggregator: aggregator, decisions: decisions}
+	s.router.HandleFunc("/api/v1/signals/explain", h.handleExplain).Methods("GET")
+}
+
+// handleExplain explains a symbol's latest aggregated signal
+func (h *signalExplainHandlers) handleExplain(w http.ResponseWriter, r *http.Request) {
+	symbol := r.URL.Query().Get("symbol")
+	if symbol == "" {
+		http.Error(w, "symbol is required", http.StatusBadRequest)
+		return
+	}
+
+	// Falling short of consensus is explained too, so the error is not fatal
+	if r.URL.Query().Get("refresh") == "true" {
+		if _, err := h.aggregator.AggregateSignals(r.Context(), symbol); err != nil && r.Context().Err() != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	resp := SignalExplainResponse{Symbol: symbol}
+	if explanation, ok := h.aggregator.Explain(symbol); ok {
+		resp.Explanation = explanation
+	}
+	if h.decisions != nil {
+		if decision, ok := h.decisions.LastDecision(symbol); ok {
+			resp.Decision = &decision
+		}
+	}
+	if resp.Explanation == nil && resp.Decision == nil {
+		http.Error(w, "No signals aggregated for symbol", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
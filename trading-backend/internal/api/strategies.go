@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/atlas-desktop/trading-backend/internal/strategy"
+	"github.com/gorilla/mux"
+)
+
+// strategyHandlers serves running strategies and their live tuning.
+type strategyHandlers struct {
+	live *strategy.LiveRegistry
+}
+
+// StartStrategyRequest is the body of POST /api/v1/strategies.
+type StartStrategyRequest struct {
+	Strategy string                 `json:"strategy"`
+	Symbol   string                 `json:"symbol"`
+	Params   map[string]interface{} `json:"params"`
+}
+
+// SetParamsRequest is the body of POST /api/v1/strategies/{id}/params.
+type SetParamsRequest struct {
+	Params map[string]interface{} `json:"params"`
+}
+
+// SetLiveStrategies registers the running strategy routes: starting,
+// listing, inspecting and stopping strategies, changing their parameters,
+// and the audit trail of changes. The "strategy:params" WebSocket method
+// changes parameters too.
+func (s *Server) SetLiveStrategies(live *strategy.LiveRegistry) {
+	s.mu.Lock()
+	s.liveStrategies = live
+	s.mu.Unlock()
+
+	h := &strategyHandlers{live: live}
+	s.router.HandleFunc("/api/v1/strategies", h.handleStart).Methods("POST")
+	s.router.HandleFunc("/api/v1/strategies", h.handleList).Methods("GET")
+	s.router.HandleFunc("/api/v1/strategies/audit", h.handleAudit).Methods("GET")
+	s.router.HandleFunc("/api/v1/strategies/{id}", h.handleGet).Methods("GET")
+	s.router.HandleFunc("/api/v1/strategies/{id}", h.handleStop).Methods("DELETE")
+	s.router.HandleFunc("/api/v1/strategies/{id}/params", h.handleSetParams).Methods("POST")
+	s.router.HandleFunc("/api/v1/strategies/{id}/audit", h.handleAudit).Methods("GET")
+}
+
+// handleStart starts a strategy on a symbol
+func (h *strategyHandlers) handleStart(w http.ResponseWriter, r *http.Request) {
+	var req StartStrategyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	info, err := h.live.Start(req.Strategy, req.Symbol, req.Params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/api/v1/strategies/"+info.ID)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(info)
+}
+
+// handleList lists running strategies, oldest first
+func (h *strategyHandlers) handleList(w http.ResponseWriter, r *http.Request) {
+	strategies := h.live.List()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"strategies": strategies,
+		"count":      len(strategies),
+	})
+}
+
+// handleGet returns a running strategy with its current parameters
+func (h *strategyHandlers) handleGet(w http.ResponseWriter, r *http.Request) {
+	live, ok := h.live.Get(mux.Vars(r)["id"])
+	if !ok {
+		http.Error(w, "Strategy not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(live.Info())
+}
+
+// handleStop stops a running strategy
+func (h *strategyHandlers) handleStop(w http.ResponseWriter, r *http.Request) {
+	if err := h.live.Stop(mux.Vars(r)["id"]); err != nil {
+		http.Error(w, "Strategy not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleSetParams changes a running strategy's parameters from the next bar
+func (h *strategyHandlers) handleSetParams(w http.ResponseWriter, r *http.Request) {
+	var req SetParamsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(req.Params) == 0 {
+		http.Error(w, "No parameters given", http.StatusBadRequest)
+		return
+	}
+
+	changes, err := h.live.SetParameters(mux.Vars(r)["id"], req.Params, requestUser(r), "api")
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, strategy.ErrLiveStrategyNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"changes": changes,
+	})
+}
+
+// handleAudit returns the parameter changes of one running strategy, or of
+// all of them
+func (h *strategyHandlers) handleAudit(w http.ResponseWriter, r *http.Request) {
+	trail := h.live.AuditTrail(mux.Vars(r)["id"])
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"changes": trail,
+		"count":   len(trail),
+	})
+}
+
+// requestUser returns the authenticated user making a request, or
+// "anonymous" when authentication is off.
+func requestUser(r *http.Request) string {
+	if claims, ok := ClaimsFromContext(r.Context()); ok {
+		return claims.Subject
+	}
+	return "anonymous"
+}
@@ -6,6 +6,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/atlas-desktop/trading-backend/internal/replay"
+	"github.com/atlas-desktop/trading-backend/internal/strategy"
 	"github.com/atlas-desktop/trading-backend/pkg/types"
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
@@ -16,15 +18,18 @@ type MessageType string
 
 const (
 	// Server -> Client messages
-	MsgTypeOrderUpdate    MessageType = "order_update"
-	MsgTypePositionUpdate MessageType = "position_update"
-	MsgTypeTradeUpdate    MessageType = "trade_update"
-	MsgTypeSignalUpdate   MessageType = "signal_update"
-	MsgTypeRiskAlert      MessageType = "risk_alert"
-	MsgTypeAgentStatus    MessageType = "agent_status"
-	MsgTypePnLUpdate      MessageType = "pnl_update"
-	MsgTypeError          MessageType = "error"
-	MsgTypeHeartbeat      MessageType = "heartbeat"
+	MsgTypeOrderUpdate      MessageType = "order_update"
+	MsgTypePositionUpdate   MessageType = "position_update"
+	MsgTypeTradeUpdate      MessageType = "trade_update"
+	MsgTypeSignalUpdate     MessageType = "signal_update"
+	MsgTypeRiskAlert        MessageType = "risk_alert"
+	MsgTypeAgentStatus      MessageType = "agent_status"
+	MsgTypePnLUpdate        MessageType = "pnl_update"
+	MsgTypeBacktestProgress MessageType = "backtest_progress"
+	MsgTypeReplayUpdate     MessageType = "replay_update"
+	MsgTypeStrategyParams   MessageType = "strategy_params"
+	MsgTypeError            MessageType = "error"
+	MsgTypeHeartbeat        MessageType = "heartbeat"
 	
 	// Client -> Server messages
 	MsgTypeSubscribe   MessageType = "subscribe"
@@ -277,6 +282,33 @@ func (h *Hub) BroadcastPnLUpdate(pnl interface{}) {
 	h.PublishToChannel("pnl", MsgTypePnLUpdate, pnl)
 }
 
+// BroadcastBacktestProgress broadcasts a backtest's progress to the
+// "backtests" channel and to the channel of that backtest alone.
+func (h *Hub) BroadcastBacktestProgress(progress *types.BacktestProgress) {
+	h.PublishToChannel("backtests", MsgTypeBacktestProgress, progress)
+	h.PublishToChannel("backtests:"+progress.ID, MsgTypeBacktestProgress, progress)
+}
+
+// BroadcastReplayUpdate broadcasts a replay session's progress to the
+// "replays" channel and to the channel of that session alone.
+func (h *Hub) BroadcastReplayUpdate(session *replay.Session) {
+	h.PublishToChannel("replays", MsgTypeReplayUpdate, session)
+	h.PublishToChannel("replays:"+session.ID, MsgTypeReplayUpdate, session)
+}
+
+// BroadcastStrategyParams broadcasts a running strategy's parameter change
+// to the "strategies" channel and to the channel of that strategy alone.
+func (h *Hub) BroadcastStrategyParams(change strategy.ParamChange) {
+	h.PublishToChannel("strategies", MsgTypeStrategyParams, change)
+	h.PublishToChannel("strategies:"+change.StrategyID, MsgTypeStrategyParams, change)
+}
+
+// BroadcastStrategySignal broadcasts a running strategy's signal to the
+// channel of that strategy.
+func (h *Hub) BroadcastStrategySignal(id string, signal *strategy.Signal) {
+	h.PublishToChannel("strategies:"+id, MsgTypeSignalUpdate, signal)
+}
+
 // ClientCount returns the number of connected clients.
 func (h *Hub) ClientCount() int {
 	h.mu.RLock()
@@ -25,6 +25,7 @@ type TradingAgent struct {
 	riskManager  *execution.RiskManager
 	orderManager *execution.OrderManager
 	signalAgg    *signals.SignalAggregator
+	breaker      EntryHalter // Halts entries after abnormal price moves
 	
 	// State
 	isRunning    bool
@@ -369,7 +370,7 @@ func (ta *TradingAgent) processSignals(ctx context.Context) {
 		}
 		
 		// Check if we can take the position
-		if !ta.canTakePosition(pair) {
+		if ta.entryHalted(pair) || !ta.canTakePosition(pair) {
 			continue
 		}
 		
@@ -408,11 +409,18 @@ func (ta *TradingAgent) canTakePosition(symbol string) bool {
 func (ta *TradingAgent) executeTrade(ctx context.Context, signal *signals.AggregatedSignal) error {
 	// Calculate position size
 	portfolioValue := decimal.NewFromInt(10000) // TODO: Get from portfolio
-	positionSize := ta.calculatePositionSize(portfolioValue, signal)
+	positionSize := types.RoundQuantity("", signal.Symbol, ta.calculatePositionSize(portfolioValue, signal))
 	
 	if positionSize.LessThanOrEqual(decimal.Zero) {
 		return nil
 	}
+	if spec, ok := types.SymbolSpecs.Get("", signal.Symbol); ok && !spec.MeetsMinNotional(positionSize, signal.SuggestedEntry) {
+		ta.logger.Debug("Skipping trade below minimum notional",
+			zap.String("symbol", signal.Symbol),
+			zap.String("quantity", positionSize.String()),
+			zap.String("minNotional", spec.MinNotional.String()))
+		return nil
+	}
 	
 	// Create order
 	order := &types.Order{
@@ -444,13 +452,18 @@ func (ta *TradingAgent) executeTrade(ctx context.Context, signal *signals.Aggreg
 		zap.String("quantity", order.Quantity.String()),
 		zap.String("confidence", signal.Confidence.String()))
 	
-	// Execute with SL/TP if available
+	// Execute with SL/TP if available, rounded on the passive side of the
+	// order that closes the entry
+	exitSide := types.OrderSideSell
+	if order.Side == types.OrderSideSell {
+		exitSide = types.OrderSideBuy
+	}
 	var stopLoss, takeProfit decimal.Decimal
 	if !signal.SuggestedStop.IsZero() {
-		stopLoss = signal.SuggestedStop
+		stopLoss = types.RoundPrice("", signal.Symbol, exitSide, signal.SuggestedStop)
 	}
 	if !signal.SuggestedTarget.IsZero() {
-		takeProfit = signal.SuggestedTarget
+		takeProfit = types.RoundPrice("", signal.Symbol, exitSide, signal.SuggestedTarget)
 	}
 	
 	var result *execution.ExecutionResult
@@ -0,0 +1,44 @@
+package autonomous
+
+// EntryHalter reports symbols whose new entries are halted.
+// *data.CircuitBreaker implements it.
+type EntryHalter interface {
+	Halted(symbol string) bool
+}
+
+// SetCircuitBreaker sets what halts entries into a pair after abnormal price
+// moves. Halted pairs take no new or scaled-in positions; open positions
+// keep their exits.
+func (ea *EnhancedTradingAgent) SetCircuitBreaker(breaker EntryHalter) {
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+
+	ea.breaker = breaker
+}
+
+// entryHalted reports whether the circuit breaker halts entries into pair.
+func (ea *EnhancedTradingAgent) entryHalted(pair string) bool {
+	ea.mu.RLock()
+	breaker := ea.breaker
+	ea.mu.RUnlock()
+
+	return breaker != nil && breaker.Halted(pair)
+}
+
+// SetCircuitBreaker sets what halts entries into a pair after abnormal price
+// moves.
+func (ta *TradingAgent) SetCircuitBreaker(breaker EntryHalter) {
+	ta.mu.Lock()
+	defer ta.mu.Unlock()
+
+	ta.breaker = breaker
+}
+
+// entryHalted reports whether the circuit breaker halts entries into pair.
+func (ta *TradingAgent) entryHalted(pair string) bool {
+	ta.mu.RLock()
+	breaker := ta.breaker
+	ta.mu.RUnlock()
+
+	return breaker != nil && breaker.Halted(pair)
+}
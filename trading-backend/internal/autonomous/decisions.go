@@ -0,0 +1,79 @@
+package autonomous
+
+import (
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/internal/regime"
+	"github.com/atlas-desktop/trading-backend/internal/signals"
+)
+
+// SignalRejection is why the agent passed on a pair's signal.
+type SignalRejection string
+
+// Signal rejection reasons
+const (
+	RejectNoSignal   SignalRejection = "no_signal" // Aggregation produced no signal
+	RejectHalted     SignalRejection = "halted"    // Circuit breaker halted entries
+	RejectFunding    SignalRejection = "funding"
+	RejectConfidence SignalRejection = "confidence"
+	RejectConsensus  SignalRejection = "consensus"
+	RejectRegime     SignalRejection = "regime"
+	RejectPosition   SignalRejection = "position" // Position cap reached, or the open position cannot be scaled into
+	RejectMonteCarlo SignalRejection = "monte_carlo"
+	RejectBoundary   SignalRejection = "boundary" // Funding settlement or session end too close
+)
+
+// SignalDecision is the agent's latest decision on a pair's signal.
+type SignalDecision struct {
+	Symbol    string                    `json:"symbol"`
+	Accepted  bool                      `json:"accepted"`
+	Rejection SignalRejection           `json:"rejection,omitempty"`
+	Detail    string                    `json:"detail,omitempty"`
+	Signal    *signals.AggregatedSignal `json:"signal,omitempty"`
+	Regime    regime.RegimeType         `json:"regime"`
+	DecidedAt time.Time                 `json:"decidedAt"`
+}
+
+// recordDecisionLocked records the decision on pair's signal; an empty
+// rejection accepts it. Callers hold ea.mu.
+func (ea *EnhancedTradingAgent) recordDecisionLocked(
+	pair string,
+	signal *signals.AggregatedSignal,
+	currentRegime regime.RegimeType,
+	rejection SignalRejection,
+	detail string,
+) {
+	ea.decisions[execution.NormalizeSymbol(pair)] = SignalDecision{
+		Symbol:    pair,
+		Accepted:  rejection == "",
+		Rejection: rejection,
+		Detail:    detail,
+		Signal:    signal,
+		Regime:    currentRegime,
+		DecidedAt: time.Now(),
+	}
+}
+
+// LastDecision returns the agent's latest decision on a symbol's signal, and
+// false before it has processed one.
+func (ea *EnhancedTradingAgent) LastDecision(symbol string) (SignalDecision, bool) {
+	ea.mu.RLock()
+	defer ea.mu.RUnlock()
+
+	decision, ok := ea.decisions[execution.NormalizeSymbol(symbol)]
+	return decision, ok
+}
+
+// Decisions returns the agent's latest decision on each pair's signal, by
+// normalized symbol.
+func (ea *EnhancedTradingAgent) Decisions() map[string]SignalDecision {
+	ea.mu.RLock()
+	defer ea.mu.RUnlock()
+
+	decisions := make(map[string]SignalDecision, len(ea.decisions))
+	for key, decision := range ea.decisions {
+		decisions[key] = decision
+	}
+	return decisions
+}
@@ -0,0 +1,132 @@
+package autonomous
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/events"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// DeRiskTier scales trading back once drawdown from peak equity reaches
+// Drawdown. Deeper tiers replace shallower ones rather than compounding.
+type DeRiskTier struct {
+	Drawdown           decimal.Decimal `json:"drawdown"`           // Fraction below peak equity that activates the tier
+	PositionMultiplier decimal.Decimal `json:"positionMultiplier"` // Applied to MaxPositionPercent
+	ConfidenceBump     decimal.Decimal `json:"confidenceBump"`     // Added to BaseMinConfidence
+}
+
+// DefaultDeRiskTiers returns tiers that cut the maximum position to 75%, 50%
+// and 25% and raise the confidence required by 0.05, 0.1 and 0.15 at 3%, 5%
+// and 8% drawdown.
+func DefaultDeRiskTiers() []DeRiskTier {
+	return []DeRiskTier{
+		{Drawdown: decimal.NewFromFloat(0.03), PositionMultiplier: decimal.NewFromFloat(0.75), ConfidenceBump: decimal.NewFromFloat(0.05)},
+		{Drawdown: decimal.NewFromFloat(0.05), PositionMultiplier: decimal.NewFromFloat(0.5), ConfidenceBump: decimal.NewFromFloat(0.1)},
+		{Drawdown: decimal.NewFromFloat(0.08), PositionMultiplier: decimal.NewFromFloat(0.25), ConfidenceBump: decimal.NewFromFloat(0.15)},
+	}
+}
+
+// DeRiskStatus is the de-risking tier the agent is trading under.
+type DeRiskStatus struct {
+	Tier               int             `json:"tier"` // 1-based index into DeRiskTiers; 0 trades at full risk
+	Drawdown           decimal.Decimal `json:"drawdown"`
+	MaxPositionPercent decimal.Decimal `json:"maxPositionPercent"`
+	MinConfidence      decimal.Decimal `json:"minConfidence"`
+	Since              time.Time       `json:"since,omitempty"`
+}
+
+// sortedDeRiskTiers returns tiers from shallowest to deepest drawdown.
+func sortedDeRiskTiers(tiers []DeRiskTier) []DeRiskTier {
+	sorted := append([]DeRiskTier(nil), tiers...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Drawdown.LessThan(sorted[j].Drawdown)
+	})
+	return sorted
+}
+
+// deRiskTierFor returns the tier drawdown calls for. Tiers activate as soon
+// as drawdown reaches them, but are only lifted once drawdown recovers
+// recovery below them, so equity hovering at a threshold doesn't flap.
+func deRiskTierFor(tiers []DeRiskTier, recovery decimal.Decimal, current int, drawdown decimal.Decimal) int {
+	tier := 0
+	for i, t := range tiers {
+		if drawdown.GreaterThanOrEqual(t.Drawdown) {
+			tier = i + 1
+		}
+	}
+
+	if tier < current {
+		held := current
+		for held > tier && drawdown.LessThan(tiers[held-1].Drawdown.Sub(recovery)) {
+			held--
+		}
+		tier = held
+	}
+	return tier
+}
+
+// deRiskLimitsLocked returns MaxPositionPercent and BaseMinConfidence
+// adjusted for the active de-risking tier. The caller must hold ea.mu.
+func (ea *EnhancedTradingAgent) deRiskLimitsLocked() (decimal.Decimal, decimal.Decimal) {
+	maxPositionPercent := ea.config.MaxPositionPercent
+	minConfidence := ea.config.BaseMinConfidence
+	if ea.deRiskTier > 0 {
+		tier := ea.config.DeRiskTiers[ea.deRiskTier-1]
+		maxPositionPercent = maxPositionPercent.Mul(tier.PositionMultiplier)
+		minConfidence = minConfidence.Add(tier.ConfidenceBump)
+	}
+	return maxPositionPercent, minConfidence
+}
+
+// deRiskStatusLocked reports the active tier. The caller must hold ea.mu.
+func (ea *EnhancedTradingAgent) deRiskStatusLocked() DeRiskStatus {
+	maxPositionPercent, minConfidence := ea.deRiskLimitsLocked()
+	return DeRiskStatus{
+		Tier:               ea.deRiskTier,
+		Drawdown:           ea.metrics.CurrentDrawdown,
+		MaxPositionPercent: maxPositionPercent,
+		MinConfidence:      minConfidence,
+		Since:              ea.deRiskSince,
+	}
+}
+
+// updateDeRiskTier moves to the tier drawdown calls for, publishing a risk
+// alert when it changes.
+func (ea *EnhancedTradingAgent) updateDeRiskTier(drawdown decimal.Decimal) {
+	ea.mu.Lock()
+	previous := ea.deRiskTier
+	tier := deRiskTierFor(ea.config.DeRiskTiers, ea.config.DeRiskRecovery, previous, drawdown)
+	if tier == previous {
+		ea.mu.Unlock()
+		return
+	}
+	ea.deRiskTier = tier
+	ea.deRiskSince = time.Now()
+	status := ea.deRiskStatusLocked()
+	ea.mu.Unlock()
+
+	threshold := decimal.Zero
+	severity := "info"
+	message := fmt.Sprintf("Drawdown %s%% recovered, de-risking eased from tier %d to %d",
+		drawdown.Mul(decimal.NewFromInt(100)).StringFixed(2), previous, tier)
+	if tier > previous {
+		severity = "warning"
+		message = fmt.Sprintf("Drawdown %s%% reached de-risking tier %d",
+			drawdown.Mul(decimal.NewFromInt(100)).StringFixed(2), tier)
+	}
+	if tier > 0 {
+		threshold = ea.config.DeRiskTiers[tier-1].Drawdown
+	}
+
+	ea.logger.Warn("De-risking tier changed",
+		zap.Int("from", previous),
+		zap.Int("to", tier),
+		zap.String("drawdown", drawdown.String()),
+		zap.String("maxPositionPercent", status.MaxPositionPercent.String()),
+		zap.String("minConfidence", status.MinConfidence.String()))
+
+	ea.orchestrator.PublishEvent(events.NewRiskAlertEvent("drawdown_derisk", severity, message, drawdown, threshold))
+}
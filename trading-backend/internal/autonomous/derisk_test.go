@@ -0,0 +1,65 @@
+package autonomous
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestDeRiskTierFor(t *testing.T) {
+	tiers := DefaultDeRiskTiers()
+	recovery := decimal.NewFromFloat(0.01)
+
+	tests := []struct {
+		name     string
+		tiers    []DeRiskTier
+		current  int
+		drawdown float64
+		want     int
+	}{
+		{name: "no tiers", current: 0, drawdown: 0.2, want: 0},
+		{name: "at peak", tiers: tiers, current: 0, drawdown: 0, want: 0},
+		{name: "short of the first tier", tiers: tiers, current: 0, drawdown: 0.029, want: 0},
+		{name: "at the first tier", tiers: tiers, current: 0, drawdown: 0.03, want: 1},
+		{name: "between tiers", tiers: tiers, current: 1, drawdown: 0.049, want: 1},
+		{name: "at the second tier", tiers: tiers, current: 1, drawdown: 0.05, want: 2},
+		{name: "gap past every tier", tiers: tiers, current: 0, drawdown: 0.2, want: 3},
+		{name: "held within recovery", tiers: tiers, current: 2, drawdown: 0.045, want: 2},
+		{name: "held at the recovery edge", tiers: tiers, current: 2, drawdown: 0.04, want: 2},
+		{name: "eased one tier", tiers: tiers, current: 2, drawdown: 0.039, want: 1},
+		{name: "eased through several tiers", tiers: tiers, current: 3, drawdown: 0.025, want: 1},
+		{name: "lifted on recovery", tiers: tiers, current: 1, drawdown: 0.019, want: 0},
+		{name: "lifted from the deepest tier", tiers: tiers, current: 3, drawdown: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := deRiskTierFor(tt.tiers, recovery, tt.current, decimal.NewFromFloat(tt.drawdown))
+			if got != tt.want {
+				t.Errorf("Expected tier %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDeRiskTiersAreOptIn(t *testing.T) {
+	ea := newPaperAgent(t, &paperVenue{}, nil)
+	ea.updateDeRiskTier(decimal.NewFromFloat(0.2))
+	if ea.deRiskTier != 0 {
+		t.Errorf("Expected no de-risking without configured tiers, got tier %d", ea.deRiskTier)
+	}
+
+	// Tiers configured out of order apply from the shallowest
+	tiers := DefaultDeRiskTiers()
+	ea = newPaperAgent(t, &paperVenue{}, func(config *EnhancedAgentConfig) {
+		config.DeRiskTiers = []DeRiskTier{tiers[2], tiers[0], tiers[1]}
+	})
+	ea.updateDeRiskTier(decimal.NewFromFloat(0.06))
+
+	ea.mu.RLock()
+	status := ea.deRiskStatusLocked()
+	ea.mu.RUnlock()
+	if status.Tier != 2 || !status.MaxPositionPercent.Equal(decimal.NewFromFloat(0.05)) || !status.MinConfidence.Equal(decimal.NewFromFloat(0.7)) {
+		t.Errorf("Expected the 5%% tier halving the position and adding 0.1 confidence, got %+v", status)
+	}
+}
@@ -3,12 +3,15 @@ package autonomous
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/atlas-desktop/trading-backend/internal/events"
 	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/internal/learning"
+	"github.com/atlas-desktop/trading-backend/internal/montecarlo"
 	"github.com/atlas-desktop/trading-backend/internal/orchestrator"
 	"github.com/atlas-desktop/trading-backend/internal/regime"
 	"github.com/atlas-desktop/trading-backend/internal/signals"
@@ -32,6 +35,7 @@ type EnhancedTradingAgent struct {
 	executor     *execution.Executor
 	riskManager  *execution.RiskManager
 	orderManager *execution.OrderManager
+	portfolio    *execution.PortfolioManager
 	signalAgg    *signals.Aggregator
 
 	// State
@@ -43,8 +47,32 @@ type EnhancedTradingAgent struct {
 	registeredStrategies map[string]*StrategyConfig
 	activeStrategy       string
 
+	// Drawdown de-risking
+	deRiskTier  int // 1-based index into config.DeRiskTiers; 0 when trading at full risk
+	deRiskSince time.Time
+
 	// Metrics
-	metrics EnhancedMetrics
+	metrics      EnhancedMetrics
+	closedTrades []decimal.Decimal // Realized PnL of recent closed trades, oldest first
+
+	// Position management, by normalized symbol
+	managed map[string]*managedPosition
+	bars    map[string][]types.OHLCV
+	prices  chan priceTick
+
+	// Entries of open trades awaiting their close to be journaled, by
+	// normalized symbol
+	journal    *learning.TradeJournal
+	openTrades map[string]learning.JournalEntry
+
+	// Perpetual funding rates for the funding filter
+	fundingSource FundingSource
+
+	// Halts entries after abnormal price moves
+	breaker EntryHalter
+
+	// Latest decision on each pair's signal, by normalized symbol
+	decisions map[string]SignalDecision
 
 	// Control
 	stopCh chan struct{}
@@ -66,6 +94,11 @@ type EnhancedAgentConfig struct {
 	BaseMinConfidence decimal.Decimal `json:"baseMinConfidence"`
 	BaseMinConsensus  decimal.Decimal `json:"baseMinConsensus"`
 
+	// Per-pair and per-regime overrides of the thresholds and position cap,
+	// checked by Validate
+	PairOverrides   map[string]PairOverride              `json:"pairOverrides"`
+	RegimeOverrides map[regime.RegimeType]RegimeOverride `json:"regimeOverrides"`
+
 	// Execution settings
 	PaperTrading bool            `json:"paperTrading"`
 	MaxSlippage  decimal.Decimal `json:"maxSlippage"`
@@ -79,6 +112,11 @@ type EnhancedAgentConfig struct {
 	MaxDailyLoss decimal.Decimal `json:"maxDailyLoss"`
 	MaxDrawdown  decimal.Decimal `json:"maxDrawdown"`
 
+	// Graduated de-risking as drawdown deepens, short of the kill switch. Off
+	// unless tiers are configured, such as DefaultDeRiskTiers.
+	DeRiskTiers    []DeRiskTier    `json:"deRiskTiers"`
+	DeRiskRecovery decimal.Decimal `json:"deRiskRecovery"` // Drawdown recovery below a tier before it is lifted
+
 	// Regime-adaptive settings
 	EnableRegimeAdapt  bool `json:"enableRegimeAdaptation"`
 	ReducePosInHighVol bool `json:"reducePositionInHighVol"`
@@ -87,10 +125,36 @@ type EnhancedAgentConfig struct {
 	// Timing
 	SignalPollInterval time.Duration `json:"signalPollInterval"`
 	RiskCheckInterval  time.Duration `json:"riskCheckInterval"`
+	ReconcileInterval  time.Duration `json:"reconcileInterval"` // How often live state is checked against the exchanges
+
+	// Exchange holdings worth less are dust, not positions to manage
+	ReconcileMinNotional decimal.Decimal `json:"reconcileMinNotional"`
 
 	// Monte Carlo validation
-	RequireMCValidation bool    `json:"requireMonteCarloValidation"`
+	RequireMCValidation bool    `json:"requireMonteCarloValidation"` // Live only; passes until the strategy has enough trades
 	MinRobustnessScore  float64 `json:"minRobustnessScore"`
+
+	// Trailing stops
+	EnableTrailingStop bool            `json:"enableTrailingStop"`
+	TrailDistance      decimal.Decimal `json:"trailDistance"` // Fraction of price, or an ATR multiple with TrailUseATR
+	TrailUseATR        bool            `json:"trailUseAtr"`
+	TrailATRPeriod     int             `json:"trailAtrPeriod"`
+
+	// Position scaling
+	MaxScaleIns      int             `json:"maxScaleIns"`      // Adds allowed to a winning position; 0 disables scaling in
+	ScaleInFraction  decimal.Decimal `json:"scaleInFraction"`  // Each add as a fraction of a fresh position's size
+	ScaleOutFraction decimal.Decimal `json:"scaleOutFraction"` // Fraction closed at the take profit; 0 leaves the exit to the exchange
+
+	// Take-profit ladder attached to each live entry in place of its take
+	// profit and stop; the ladder places and trails its own, so it can't be
+	// combined with ScaleOutFraction. Paper entries exit as without it.
+	TPLadder []execution.TPRung `json:"tpLadder,omitempty"`
+
+	// Perpetual funding, read from the source set with SetFundingSource
+	Funding FundingFilter `json:"funding"`
+
+	// Exits by time in trade and ahead of funding and session boundaries
+	TimeExits TimeExits `json:"timeExits"`
 }
 
 // StrategyConfig defines a trading strategy.
@@ -102,6 +166,10 @@ type StrategyConfig struct {
 	PositionSizeMethod string              `json:"positionSizeMethod"` // "kelly", "volatility", "fixed"
 	RiskPerTrade       decimal.Decimal     `json:"riskPerTrade"`
 	IsActive           bool                `json:"isActive"`
+
+	// Override the agent's TimeExits for positions entered under the strategy
+	MaxHoldingPeriod time.Duration `json:"maxHoldingPeriod,omitempty"`
+	TimeExitMode     TimeExitMode  `json:"timeExitMode,omitempty"`
 }
 
 // EnhancedMetrics tracks enhanced agent metrics.
@@ -121,6 +189,8 @@ type EnhancedMetrics struct {
 	SignalsRejectedConf int `json:"signalsRejectedConfidence"`
 	SignalsRejectedReg  int `json:"signalsRejectedRegime"`
 	SignalsRejectedMC   int `json:"signalsRejectedMonteCarlo"`
+	SignalsRejectedFund int `json:"signalsRejectedFunding"`
+	SignalsRejectedHalt int `json:"signalsRejectedHalt"`
 
 	// Regime metrics
 	RegimeChanges    int     `json:"regimeChanges"`
@@ -146,6 +216,14 @@ func DefaultEnhancedAgentConfig() EnhancedAgentConfig {
 		BaseMinConfidence: decimal.NewFromFloat(0.6),
 		BaseMinConsensus:  decimal.NewFromFloat(0.5),
 
+		RegimeOverrides: map[regime.RegimeType]RegimeOverride{
+			// In high volatility, require higher confidence
+			regime.RegimeHighVol: {
+				ConfidenceMultiplier: decimal.NewFromFloat(1.2),
+				ConsensusMultiplier:  decimal.NewFromFloat(1.2),
+			},
+		},
+
 		PaperTrading: true,
 		MaxSlippage:  decimal.NewFromFloat(0.005),
 
@@ -156,18 +234,63 @@ func DefaultEnhancedAgentConfig() EnhancedAgentConfig {
 		MaxDailyLoss: decimal.NewFromInt(500),
 		MaxDrawdown:  decimal.NewFromFloat(0.1),
 
+		DeRiskRecovery: decimal.NewFromFloat(0.01),
+
 		EnableRegimeAdapt:  true,
 		ReducePosInHighVol: true,
 		PauseInBear:        false, // Can still short
 
 		SignalPollInterval: 5 * time.Second,
 		RiskCheckInterval:  1 * time.Minute,
+		ReconcileInterval:  5 * time.Minute,
+
+		ReconcileMinNotional: decimal.NewFromInt(10),
 
 		RequireMCValidation: true,
 		MinRobustnessScore:  0.6,
+
+		EnableTrailingStop: false,
+		TrailDistance:      decimal.NewFromFloat(0.02), // 2% behind price
+		TrailUseATR:        false,
+		TrailATRPeriod:     14,
+
+		MaxScaleIns:      0,
+		ScaleInFraction:  decimal.NewFromFloat(0.5),
+		ScaleOutFraction: decimal.Zero,
+
+		Funding: FundingFilter{
+			Enabled:        true,
+			MaxPaidRate:    decimal.NewFromFloat(0.001),  // 0.1% a settlement, ten times Binance's baseline
+			PreferRate:     decimal.NewFromFloat(0.0003), // Paid to hold three times the baseline
+			PreferDiscount: decimal.NewFromFloat(0.05),
+		},
+
+		TimeExits: TimeExits{
+			MaxHoldingPeriod: 0, // Held until the stop or target
+			Mode:             TimeExitClose,
+		},
 	}
 }
 
+// Validate checks that the overrides are in range and that the configured
+// exits don't compete for a position.
+func (c EnhancedAgentConfig) Validate() error {
+	for pair, override := range c.PairOverrides {
+		if err := validatePairOverride(pair, override); err != nil {
+			return err
+		}
+	}
+	for regimeType, override := range c.RegimeOverrides {
+		if err := validateRegimeOverride(regimeType, override); err != nil {
+			return err
+		}
+	}
+	if len(c.TPLadder) > 0 && c.ScaleOutFraction.IsPositive() {
+		return fmt.Errorf("tpLadder and scaleOutFraction both take profit; configure one")
+	}
+	return nil
+}
+
 // NewEnhancedTradingAgent creates a new enhanced trading agent.
 func NewEnhancedTradingAgent(
 	logger *zap.Logger,
@@ -178,7 +301,9 @@ func NewEnhancedTradingAgent(
 	orderManager *execution.OrderManager,
 	signalAgg *signals.Aggregator,
 ) *EnhancedTradingAgent {
-	return &EnhancedTradingAgent{
+	config.DeRiskTiers = sortedDeRiskTiers(config.DeRiskTiers)
+
+	ea := &EnhancedTradingAgent{
 		logger:               logger.Named("enhanced-agent"),
 		config:               config,
 		orchestrator:         orch,
@@ -187,8 +312,15 @@ func NewEnhancedTradingAgent(
 		orderManager:         orderManager,
 		signalAgg:            signalAgg,
 		registeredStrategies: make(map[string]*StrategyConfig),
+		managed:              make(map[string]*managedPosition),
+		openTrades:           make(map[string]learning.JournalEntry),
+		decisions:            make(map[string]SignalDecision),
+		bars:                 make(map[string][]types.OHLCV),
+		prices:               make(chan priceTick, 256),
 		stopCh:               make(chan struct{}),
 	}
+	ea.registerOverrides(config.PairOverrides, config.RegimeOverrides)
+	return ea
 }
 
 // Start starts the enhanced trading agent.
@@ -204,6 +336,17 @@ func (ea *EnhancedTradingAgent) Start(ctx context.Context) error {
 	ea.stopCh = make(chan struct{})
 	ea.mu.Unlock()
 
+	// A restarted process must learn what the exchanges already hold before
+	// it trades, or it may double-enter and ignore resting stops
+	if !ea.config.PaperTrading {
+		if err := ea.orderManager.Reconcile(ctx, ea.reconcileScope(), ea.executor.Adapters()...); err != nil {
+			ea.mu.Lock()
+			ea.isRunning = false
+			ea.mu.Unlock()
+			return fmt.Errorf("failed to reconcile with exchanges: %w", err)
+		}
+	}
+
 	ea.logger.Info("Starting Enhanced Trading Agent",
 		zap.Strings("pairs", ea.config.TradingPairs),
 		zap.Bool("paperTrading", ea.config.PaperTrading),
@@ -223,6 +366,16 @@ func (ea *EnhancedTradingAgent) Start(ctx context.Context) error {
 	// Start regime monitoring
 	go ea.regimeMonitorLoop(ctx)
 
+	// Keep live state reconciled with the exchanges
+	if !ea.config.PaperTrading {
+		go ea.reconcileLoop(ctx)
+	}
+
+	// Start position management
+	if ea.managesPositions() {
+		go ea.positionManagementLoop(ctx)
+	}
+
 	return nil
 }
 
@@ -323,7 +476,8 @@ func (ea *EnhancedTradingAgent) regimeMonitorLoop(ctx context.Context) {
 		case <-ea.stopCh:
 			return
 		case <-ticker.C:
-			currentRegime, confidence := ea.orchestrator.GetCurrentRegime()
+			// Pausing and resuming follow the portfolio-level regime
+			currentRegime, confidence := ea.orchestrator.GetCurrentRegime("")
 
 			if currentRegime != lastRegime {
 				ea.handleRegimeChange(lastRegime, currentRegime, confidence)
@@ -376,6 +530,35 @@ func (ea *EnhancedTradingAgent) handleRegimeChange(from, to regime.RegimeType, c
 	}
 }
 
+// reconcileLoop periodically reconciles orders and positions with the
+// exchanges.
+func (ea *EnhancedTradingAgent) reconcileLoop(ctx context.Context) {
+	ticker := time.NewTicker(ea.config.ReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ea.stopCh:
+			return
+		case <-ticker.C:
+			if err := ea.orderManager.Reconcile(ctx, ea.reconcileScope(), ea.executor.Adapters()...); err != nil {
+				ea.logger.Warn("Reconciliation failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// reconcileScope limits reconciliation to the traded pairs, so balances of
+// other assets are not managed as positions.
+func (ea *EnhancedTradingAgent) reconcileScope() execution.ReconcileScope {
+	return execution.ReconcileScope{
+		Symbols:     ea.config.TradingPairs,
+		MinNotional: ea.config.ReconcileMinNotional,
+	}
+}
+
 // shouldTrade checks if trading is allowed.
 func (ea *EnhancedTradingAgent) shouldTrade() bool {
 	ea.mu.RLock()
@@ -395,14 +578,17 @@ func (ea *EnhancedTradingAgent) shouldTrade() bool {
 
 // processSignals processes trading signals with regime awareness.
 func (ea *EnhancedTradingAgent) processSignals(ctx context.Context) {
-	// Get current regime adjustments
-	adjustments := ea.orchestrator.GetStrategyAdjustments()
-	currentRegime, regimeConf := ea.orchestrator.GetCurrentRegime()
-
 	for _, pair := range ea.config.TradingPairs {
+		// Get the pair's regime adjustments, blended across likely regimes
+		adjustments := ea.orchestrator.GetBlendedAdjustments(pair)
+		currentRegime, regimeConf := ea.orchestrator.GetCurrentRegime(pair)
+
 		signal, err := ea.signalAgg.AggregateSignals(ctx, pair)
 		if err != nil {
 			ea.logger.Debug("Failed to aggregate signals", zap.String("pair", pair), zap.Error(err))
+			ea.mu.Lock()
+			ea.recordDecisionLocked(pair, nil, currentRegime, RejectNoSignal, err.Error())
+			ea.mu.Unlock()
 			continue
 		}
 
@@ -415,14 +601,36 @@ func (ea *EnhancedTradingAgent) processSignals(ctx context.Context) {
 			ea.onSignal(signal)
 		}
 
-		// Apply regime-adjusted thresholds
-		minConfidence := ea.config.BaseMinConfidence
-		minConsensus := ea.config.BaseMinConsensus
+		// No entries while the circuit breaker has the pair halted
+		if ea.entryHalted(pair) {
+			ea.mu.Lock()
+			ea.metrics.SignalsRejectedHalt++
+			ea.recordDecisionLocked(pair, signal, currentRegime, RejectHalted, "circuit breaker halted entries")
+			ea.mu.Unlock()
+			continue
+		}
+
+		// Apply pair-, drawdown- and regime-adjusted thresholds
+		limits := ea.limitsFor(pair, currentRegime)
+		minConfidence := limits.minConfidence
+		minConsensus := limits.minConsensus
 
-		// In high volatility, require higher confidence
-		if currentRegime == regime.RegimeHighVolatility {
-			minConfidence = minConfidence.Mul(decimal.NewFromFloat(1.2))
-			minConsensus = minConsensus.Mul(decimal.NewFromFloat(1.2))
+		// Skip entries paying extreme funding, and favor those it pays
+		minConfidence, fundingOK := ea.fundingAdjustedConfidence(signal, minConfidence)
+		if !fundingOK {
+			ea.mu.Lock()
+			ea.metrics.SignalsRejectedFund++
+			ea.recordDecisionLocked(pair, signal, currentRegime, RejectFunding, "entry would pay extreme funding")
+			ea.mu.Unlock()
+			continue
+		}
+
+		// Skip entries the time exits would close straight away
+		if reason, near := ea.nearTimeBoundary(pair, signal.Direction == signals.DirectionLong, time.Now()); near {
+			ea.mu.Lock()
+			ea.recordDecisionLocked(pair, signal, currentRegime, RejectBoundary, reason)
+			ea.mu.Unlock()
+			continue
 		}
 
 		// Check signal quality
@@ -434,6 +642,8 @@ func (ea *EnhancedTradingAgent) processSignals(ctx context.Context) {
 			)
 			ea.mu.Lock()
 			ea.metrics.SignalsRejectedConf++
+			ea.recordDecisionLocked(pair, signal, currentRegime, RejectConfidence,
+				fmt.Sprintf("confidence %s below %s", signal.Confidence.StringFixed(4), minConfidence.StringFixed(4)))
 			ea.mu.Unlock()
 			continue
 		}
@@ -441,6 +651,8 @@ func (ea *EnhancedTradingAgent) processSignals(ctx context.Context) {
 		if signal.ConsensusScore.LessThan(minConsensus) {
 			ea.mu.Lock()
 			ea.metrics.SignalsRejectedConf++
+			ea.recordDecisionLocked(pair, signal, currentRegime, RejectConsensus,
+				fmt.Sprintf("consensus %s below %s", signal.ConsensusScore.StringFixed(4), minConsensus.StringFixed(4)))
 			ea.mu.Unlock()
 			continue
 		}
@@ -450,13 +662,42 @@ func (ea *EnhancedTradingAgent) processSignals(ctx context.Context) {
 			if !ea.isSignalSuitedForRegime(signal, currentRegime, regimeConf) {
 				ea.mu.Lock()
 				ea.metrics.SignalsRejectedReg++
+				ea.recordDecisionLocked(pair, signal, currentRegime, RejectRegime,
+					fmt.Sprintf("%s unsuited to the %s regime", signal.Direction, currentRegime))
 				ea.mu.Unlock()
 				continue
 			}
 		}
 
+		// An open position can only be scaled into
+		if position := ea.orderManager.GetPosition(pair); position != nil {
+			mp := ea.managedPositionFor(pair)
+			if mp == nil || !ea.canScaleIn(signal, position, mp) {
+				ea.mu.Lock()
+				ea.recordDecisionLocked(pair, signal, currentRegime, RejectPosition, "open position cannot be scaled into")
+				ea.mu.Unlock()
+				continue
+			}
+
+			ea.mu.Lock()
+			ea.metrics.SignalsAccepted++
+			ea.recordDecisionLocked(pair, signal, currentRegime, "", "scaling into the open position")
+			ea.mu.Unlock()
+
+			if err := ea.scaleIn(ctx, signal, position, mp); err != nil {
+				ea.logger.Error("Failed to scale into position", zap.Error(err))
+				if ea.onError != nil {
+					ea.onError(err)
+				}
+			}
+			continue
+		}
+
 		// Check if we can take the position
 		if !ea.canTakePosition(pair) {
+			ea.mu.Lock()
+			ea.recordDecisionLocked(pair, signal, currentRegime, RejectPosition, "maximum concurrent positions reached")
+			ea.mu.Unlock()
 			continue
 		}
 
@@ -465,6 +706,7 @@ func (ea *EnhancedTradingAgent) processSignals(ctx context.Context) {
 			if !ea.validateWithMonteCarlo(signal) {
 				ea.mu.Lock()
 				ea.metrics.SignalsRejectedMC++
+				ea.recordDecisionLocked(pair, signal, currentRegime, RejectMonteCarlo, "Monte Carlo validation failed")
 				ea.mu.Unlock()
 				continue
 			}
@@ -472,6 +714,7 @@ func (ea *EnhancedTradingAgent) processSignals(ctx context.Context) {
 
 		ea.mu.Lock()
 		ea.metrics.SignalsAccepted++
+		ea.recordDecisionLocked(pair, signal, currentRegime, "", "")
 		ea.mu.Unlock()
 
 		// Execute trade with regime-aware sizing
@@ -495,7 +738,7 @@ func (ea *EnhancedTradingAgent) isSignalSuitedForRegime(
 		return true
 	}
 
-	adjustments := ea.orchestrator.GetStrategyAdjustments()
+	adjustments := ea.orchestrator.GetStrategyAdjustments(signal.Symbol)
 
 	// Check if signal direction suits regime
 	switch regimeType {
@@ -531,15 +774,30 @@ func (ea *EnhancedTradingAgent) isSignalSuitedForRegime(
 	return true
 }
 
-// validateWithMonteCarlo validates a signal with Monte Carlo simulation.
+// validateWithMonteCarlo validates a signal with Monte Carlo simulation over
+// the active strategy's trade history. Until the history holds enough trades
+// to validate, signals pass unvalidated; once it does, a strategy that isn't
+// robust is rejected.
 func (ea *EnhancedTradingAgent) validateWithMonteCarlo(signal *signals.AggregatedSignal) bool {
-	// Get recent trades for this strategy
-	// In production, this would pull from trade history
-	recentTrades := []float64{
-		100, -50, 75, -30, 120, -60, 80, -40, 90, -45,
+	ea.mu.RLock()
+	strategyID := ea.activeStrategy
+	ea.mu.RUnlock()
+
+	results, err := ea.orchestrator.ValidateStrategy(strategyID)
+	if err != nil {
+		if errors.Is(err, montecarlo.ErrInsufficientTrades) {
+			ea.logger.Debug("Signal passed unvalidated: not enough trade history for Monte Carlo validation",
+				zap.String("symbol", signal.Symbol),
+				zap.String("strategy", strategyID),
+				zap.Error(err))
+			return true
+		}
+		ea.logger.Warn("Monte Carlo validation failed",
+			zap.String("strategy", strategyID),
+			zap.Error(err))
+		return false
 	}
 
-	results := ea.orchestrator.RunMonteCarloValidation(recentTrades)
 	return results.RobustnessScore >= ea.config.MinRobustnessScore
 }
 
@@ -565,34 +823,17 @@ func (ea *EnhancedTradingAgent) executeTrade(
 	adjustments regime.StrategyAdjustments,
 ) error {
 	// Get portfolio value
-	portfolioValue := decimal.NewFromInt(10000) // TODO: Get from portfolio manager
-
-	// Calculate position size using orchestrator
-	sizeRequest := sizing.PositionSizeRequest{
-		Symbol:            signal.Symbol,
-		Direction:         string(signal.Direction),
-		EntryPrice:        signal.SuggestedEntry.InexactFloat64(),
-		StopLoss:          signal.SuggestedStop.InexactFloat64(),
-		TakeProfit:        signal.SuggestedTarget.InexactFloat64(),
-		SignalStrength:    signal.Strength.InexactFloat64(),
-		Confidence:        signal.Confidence.InexactFloat64(),
-		PortfolioValue:    portfolioValue.InexactFloat64(),
-		HistoricalWinRate: ea.getHistoricalWinRate(),
-		AvgWinLossRatio:   ea.getAverageWinLossRatio(),
+	portfolioValue, err := ea.portfolioEquity()
+	if err != nil {
+		return err
 	}
 
-	sizeResult := ea.orchestrator.SizePosition(sizeRequest)
-
-	// Apply regime multiplier (already done in orchestrator, but we can add more)
-	positionSize := decimal.NewFromFloat(sizeResult.PositionSize)
-
-	// Cap at max position
-	maxPosition := portfolioValue.Mul(ea.config.MaxPositionPercent)
-	if positionSize.GreaterThan(maxPosition) {
-		positionSize = maxPosition
-	}
+	// Calculate position size using orchestrator
+	sizeResult := ea.orchestrator.SizePosition(ea.sizeRequest(signal, portfolioValue))
 
-	if positionSize.LessThanOrEqual(decimal.Zero) {
+	// Cap the sized notional and convert it to a quantity the venue accepts
+	positionSize := ea.orderQuantity(signal, portfolioValue, decimal.NewFromFloat(sizeResult.PositionSize), decimal.Zero)
+	if positionSize.IsZero() {
 		return nil
 	}
 
@@ -630,21 +871,36 @@ func (ea *EnhancedTradingAgent) executeTrade(
 		zap.Float64("kellyFraction", sizeResult.KellyFraction),
 	)
 
-	// Apply regime-adjusted stop/take profit
+	// Apply regime-adjusted stop/take profit, rounded on the passive side of the
+	// order that closes the entry
+	exitSide := types.OrderSideSell
+	if order.Side == types.OrderSideSell {
+		exitSide = types.OrderSideBuy
+	}
 	var stopLoss, takeProfit decimal.Decimal
 	if !signal.SuggestedStop.IsZero() {
-		stopLoss = signal.SuggestedStop.Mul(decimal.NewFromFloat(adjustments.StopLossMultiplier))
+		stopLoss = types.RoundPrice("", signal.Symbol, exitSide, signal.SuggestedStop.Mul(decimal.NewFromFloat(adjustments.StopLossMultiplier)))
 	}
 	if !signal.SuggestedTarget.IsZero() {
-		takeProfit = signal.SuggestedTarget.Mul(decimal.NewFromFloat(adjustments.TakeProfitMultiplier))
+		takeProfit = types.RoundPrice("", signal.Symbol, exitSide, signal.SuggestedTarget.Mul(decimal.NewFromFloat(adjustments.TakeProfitMultiplier)))
+	}
+
+	// With tiered exits the agent takes profit itself; only the stop rests
+	// on the exchange. A ladder places its own targets and stop.
+	exchangeStop, exchangeTakeProfit := stopLoss, takeProfit
+	if ea.config.ScaleOutFraction.IsPositive() {
+		exchangeTakeProfit = decimal.Zero
+	}
+	laddered := ea.laddersEntries()
+	if laddered {
+		exchangeStop, exchangeTakeProfit = decimal.Zero, decimal.Zero
 	}
 
 	// Execute
 	var result *execution.ExecutionResult
-	var err error
 
-	if !stopLoss.IsZero() || !takeProfit.IsZero() {
-		result, err = ea.executor.ExecuteWithSLTP(ctx, order, stopLoss, takeProfit)
+	if !exchangeStop.IsZero() || !exchangeTakeProfit.IsZero() {
+		result, err = ea.executor.ExecuteWithSLTP(ctx, order, exchangeStop, exchangeTakeProfit)
 	} else {
 		result, err = ea.executor.Execute(ctx, order)
 	}
@@ -671,6 +927,18 @@ func (ea *EnhancedTradingAgent) executeTrade(
 	}
 	ea.mu.Unlock()
 
+	if laddered {
+		if err := ea.attachLadder(order, result, stopLoss); err != nil {
+			ea.logger.Error("Position left without exits", zap.String("symbol", order.Symbol), zap.Error(err))
+			if ea.onError != nil {
+				ea.onError(err)
+			}
+		}
+	} else if ea.managesPositions() {
+		ea.trackPosition(order, result, stopLoss, takeProfit, signal.Confidence)
+	}
+	ea.openJournalEntry(order, result)
+
 	ea.logger.Info("Trade executed",
 		zap.String("orderId", result.OrderID),
 		zap.String("avgPrice", result.AvgPrice.String()),
@@ -723,26 +991,174 @@ func (ea *EnhancedTradingAgent) checkRiskLimits() {
 	// Update metrics
 	ea.mu.Lock()
 	ea.metrics.DailyPnL = stats.DailyPnL
+	ea.metrics.CurrentDrawdown = stats.CurrentDrawdown
+	if stats.CurrentDrawdown.GreaterThan(ea.metrics.MaxDrawdown) {
+		ea.metrics.MaxDrawdown = stats.CurrentDrawdown
+	}
 	ea.mu.Unlock()
+
+	// Scale back, or restore, position size and confidence with drawdown
+	ea.updateDeRiskTier(stats.CurrentDrawdown)
+}
+
+// portfolioEquity returns the portfolio value positions are sized against.
+func (ea *EnhancedTradingAgent) portfolioEquity() (decimal.Decimal, error) {
+	ea.mu.RLock()
+	portfolio := ea.portfolio
+	ea.mu.RUnlock()
+	if portfolio == nil {
+		return decimal.Zero, fmt.Errorf("no portfolio manager configured")
+	}
+
+	portfolioValue := portfolio.EquityValue()
+	if !portfolioValue.IsPositive() {
+		return decimal.Zero, fmt.Errorf("portfolio equity is %s", portfolioValue)
+	}
+	return portfolioValue, nil
 }
 
-// getHistoricalWinRate returns historical win rate.
+// orderQuantity converts a sized notional into an order quantity. The
+// notional is capped at the pair's max position, scaled by regime, down in
+// drawdown and while the symbol is short of sources, less the notional
+// already held. The quantity is floored to the venue's step size, and zero is
+// returned for what the venue would reject as too small rather than send a
+// doomed order.
+func (ea *EnhancedTradingAgent) orderQuantity(
+	signal *signals.AggregatedSignal,
+	portfolioValue decimal.Decimal,
+	notional decimal.Decimal,
+	held decimal.Decimal,
+) decimal.Decimal {
+	currentRegime, _ := ea.orchestrator.GetCurrentRegime(signal.Symbol)
+	maxPosition := portfolioValue.Mul(ea.limitsFor(signal.Symbol, currentRegime).maxPositionPercent)
+	if signal.Degraded {
+		maxPosition = maxPosition.Mul(signal.PositionScale)
+	}
+	notional = decimal.Min(notional, maxPosition.Sub(held))
+
+	price := signal.SuggestedEntry
+	if !notional.IsPositive() || !price.IsPositive() {
+		return decimal.Zero
+	}
+
+	quantity := types.RoundQuantity("", signal.Symbol, notional.Div(price))
+	if !quantity.IsPositive() {
+		return decimal.Zero
+	}
+	if spec, ok := types.SymbolSpecs.Get("", signal.Symbol); ok && !spec.MeetsMinNotional(quantity, price) {
+		ea.logger.Debug("Skipping order below minimum notional",
+			zap.String("symbol", signal.Symbol),
+			zap.String("quantity", quantity.String()),
+			zap.String("minNotional", spec.MinNotional.String()))
+		return decimal.Zero
+	}
+	return quantity
+}
+
+// sizeRequest builds the orchestrator position sizing request for a signal.
+func (ea *EnhancedTradingAgent) sizeRequest(signal *signals.AggregatedSignal, portfolioValue decimal.Decimal) sizing.PositionSizeRequest {
+	return sizing.PositionSizeRequest{
+		Symbol:            signal.Symbol,
+		Direction:         string(signal.Direction),
+		EntryPrice:        signal.SuggestedEntry.InexactFloat64(),
+		StopLoss:          signal.SuggestedStop.InexactFloat64(),
+		TakeProfit:        signal.SuggestedTarget.InexactFloat64(),
+		SignalStrength:    signal.Strength.InexactFloat64(),
+		Confidence:        signal.Confidence.InexactFloat64(),
+		PortfolioValue:    portfolioValue.InexactFloat64(),
+		HistoricalWinRate: ea.getHistoricalWinRate(),
+		AvgWinLossRatio:   ea.getAverageWinLossRatio(),
+	}
+}
+
+// Closed-trade statistics used for Kelly sizing
+const (
+	maxClosedTrades     = 200 // Closed trades kept for win rate and win/loss ratio
+	minClosedTrades     = 10  // Closed trades needed before the defaults are replaced
+	defaultWinRate      = 0.5
+	defaultWinLossRatio = 1.5
+)
+
+// RecordClosedTrade records the realized PnL of a closed position. Wire it to
+// PortfolioManager.OnPositionClosed.
+func (ea *EnhancedTradingAgent) RecordClosedTrade(position *types.Position) {
+	pnl := position.RealizedPnL
+
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+
+	if ea.journal != nil {
+		ea.closeJournalEntryLocked(position)
+	}
+	delete(ea.managed, execution.NormalizeSymbol(position.Symbol))
+
+	ea.metrics.TotalPnL = ea.metrics.TotalPnL.Add(pnl)
+	switch {
+	case pnl.IsPositive():
+		ea.metrics.WinningTrades++
+	case pnl.IsNegative():
+		ea.metrics.LosingTrades++
+	default:
+		// Scratch trades say nothing about win or loss size
+		return
+	}
+
+	ea.closedTrades = append(ea.closedTrades, pnl)
+	if len(ea.closedTrades) > maxClosedTrades {
+		ea.closedTrades = ea.closedTrades[len(ea.closedTrades)-maxClosedTrades:]
+	}
+}
+
+// getHistoricalWinRate returns the win rate over recent closed trades.
 func (ea *EnhancedTradingAgent) getHistoricalWinRate() float64 {
 	ea.mu.RLock()
 	defer ea.mu.RUnlock()
 
-	total := ea.metrics.WinningTrades + ea.metrics.LosingTrades
-	if total == 0 {
-		return 0.5
+	if len(ea.closedTrades) < minClosedTrades {
+		return defaultWinRate
+	}
+
+	wins := 0
+	for _, pnl := range ea.closedTrades {
+		if pnl.IsPositive() {
+			wins++
+		}
 	}
 
-	return float64(ea.metrics.WinningTrades) / float64(total)
+	return float64(wins) / float64(len(ea.closedTrades))
 }
 
-// getAverageWinLossRatio returns avg win / avg loss ratio.
+// getAverageWinLossRatio returns avg win / avg loss ratio over recent closed
+// trades.
 func (ea *EnhancedTradingAgent) getAverageWinLossRatio() float64 {
-	// TODO: Calculate from trade history
-	return 1.5
+	ea.mu.RLock()
+	defer ea.mu.RUnlock()
+
+	if len(ea.closedTrades) < minClosedTrades {
+		return defaultWinLossRatio
+	}
+
+	totalWin, totalLoss := decimal.Zero, decimal.Zero
+	wins, losses := 0, 0
+	for _, pnl := range ea.closedTrades {
+		if pnl.IsPositive() {
+			totalWin = totalWin.Add(pnl)
+			wins++
+		} else {
+			totalLoss = totalLoss.Add(pnl.Abs())
+			losses++
+		}
+	}
+
+	// Without both wins and losses the ratio is undefined
+	if wins == 0 || losses == 0 {
+		return defaultWinLossRatio
+	}
+
+	avgWin := totalWin.Div(decimal.NewFromInt(int64(wins)))
+	avgLoss := totalLoss.Div(decimal.NewFromInt(int64(losses)))
+
+	return avgWin.Div(avgLoss).InexactFloat64()
 }
 
 // Pause pauses trading.
@@ -817,8 +1233,8 @@ func (ea *EnhancedTradingAgent) GetStatus() EnhancedAgentStatus {
 		uptime = time.Since(ea.startTime)
 	}
 
-	regimeType, regimeConf := ea.orchestrator.GetCurrentRegime()
-	adjustments := ea.orchestrator.GetStrategyAdjustments()
+	regimeType, regimeConf := ea.orchestrator.GetCurrentRegime("")
+	adjustments := ea.orchestrator.GetBlendedAdjustments("")
 
 	return EnhancedAgentStatus{
 		IsRunning:              ea.isRunning,
@@ -832,6 +1248,8 @@ func (ea *EnhancedTradingAgent) GetStatus() EnhancedAgentStatus {
 		PositionSizeMultiplier: adjustments.PositionSizeMultiplier,
 		ActiveStrategy:         ea.activeStrategy,
 		RegisteredStrategies:   len(ea.registeredStrategies),
+		DegradedSymbols:        ea.signalAgg.GetDegradations(),
+		DeRisk:                 ea.deRiskStatusLocked(),
 	}
 }
 
@@ -848,6 +1266,20 @@ type EnhancedAgentStatus struct {
 	PositionSizeMultiplier float64         `json:"positionSizeMultiplier"`
 	ActiveStrategy         string          `json:"activeStrategy"`
 	RegisteredStrategies   int             `json:"registeredStrategies"`
+
+	// Symbols trading on fewer than MinSources healthy signal sources
+	DegradedSymbols map[string]signals.SourceDegradation `json:"degradedSymbols,omitempty"`
+
+	// Drawdown de-risking tier and the limits it sets
+	DeRisk DeRiskStatus `json:"deRisk"`
+}
+
+// SetPortfolioManager sets the portfolio that position sizing and risk checks
+// draw the portfolio value from.
+func (ea *EnhancedTradingAgent) SetPortfolioManager(portfolio *execution.PortfolioManager) {
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+	ea.portfolio = portfolio
 }
 
 // Callbacks
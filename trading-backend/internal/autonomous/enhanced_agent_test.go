@@ -0,0 +1,45 @@
+package autonomous
+
+import (
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/orchestrator"
+	"github.com/atlas-desktop/trading-backend/internal/signals"
+	"go.uber.org/zap"
+)
+
+func TestLiveMonteCarloValidation(t *testing.T) {
+	orch, err := orchestrator.NewTradingOrchestrator(zap.NewNop(), orchestrator.DefaultOrchestratorConfig(), nil, nil)
+	if err != nil {
+		t.Fatalf("NewTradingOrchestrator failed: %v", err)
+	}
+
+	config := DefaultEnhancedAgentConfig()
+	config.PaperTrading = false
+	ea := NewEnhancedTradingAgent(zap.NewNop(), config, orch, nil, nil, nil, nil)
+	ea.activeStrategy = "trend"
+	signal := &signals.AggregatedSignal{Symbol: "BTCUSDT"}
+
+	if !ea.config.RequireMCValidation {
+		t.Fatal("Expected live trading to validate with Monte Carlo by default")
+	}
+
+	// Without history to validate, live signals pass rather than all being rejected
+	if !ea.validateWithMonteCarlo(signal) {
+		t.Error("Expected a signal to pass before the strategy has trade history")
+	}
+	orch.GetTradeHistory().SetBacktest("trend", []float64{100, -50, 80})
+	if !ea.validateWithMonteCarlo(signal) {
+		t.Error("Expected a signal to pass while the history is too short to validate")
+	}
+
+	// A validated losing strategy is rejected
+	losses := make([]float64, 60)
+	for i := range losses {
+		losses[i] = -100
+	}
+	orch.GetTradeHistory().SetBacktest("trend", losses)
+	if ea.validateWithMonteCarlo(signal) {
+		t.Error("Expected a losing strategy's signal to be rejected")
+	}
+}
@@ -0,0 +1,76 @@
+package autonomous
+
+import (
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/internal/signals"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// FundingSource reports perpetual funding rates. *execution.FundingTracker
+// implements it.
+type FundingSource interface {
+	FundingRate(symbol string) (adapters.FundingRate, bool)
+}
+
+// FundingFilter steers entries into perpetuals by the rate predicted for
+// the next settlement, which longs pay when positive and shorts pay when
+// negative. Pairs without a funding rate are unaffected.
+type FundingFilter struct {
+	Enabled        bool            `json:"enabled"`
+	MaxPaidRate    decimal.Decimal `json:"maxPaidRate"`    // Skip entries that would pay more than this per settlement; 0 never skips
+	PreferRate     decimal.Decimal `json:"preferRate"`     // Entries receiving at least this per settlement are preferred; 0 prefers none
+	PreferDiscount decimal.Decimal `json:"preferDiscount"` // Taken off a preferred entry's minimum confidence
+}
+
+// SetFundingSource sets where funding rates are read from.
+func (ea *EnhancedTradingAgent) SetFundingSource(source FundingSource) {
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+
+	ea.fundingSource = source
+}
+
+// fundingAdjustedConfidence applies the funding filter to an entry's
+// minimum confidence, reporting false if the entry should be skipped.
+func (ea *EnhancedTradingAgent) fundingAdjustedConfidence(
+	signal *signals.AggregatedSignal,
+	minConfidence decimal.Decimal,
+) (decimal.Decimal, bool) {
+	filter := ea.config.Funding
+	ea.mu.RLock()
+	source := ea.fundingSource
+	ea.mu.RUnlock()
+	if !filter.Enabled || source == nil {
+		return minConfidence, true
+	}
+
+	rate, ok := source.FundingRate(signal.Symbol)
+	if !ok {
+		return minConfidence, true
+	}
+
+	// What this entry would pay per settlement
+	var paid decimal.Decimal
+	switch signal.Direction {
+	case signals.DirectionLong:
+		paid = rate.PredictedRate
+	case signals.DirectionShort:
+		paid = rate.PredictedRate.Neg()
+	default:
+		return minConfidence, true
+	}
+
+	if filter.MaxPaidRate.IsPositive() && paid.GreaterThan(filter.MaxPaidRate) {
+		ea.logger.Debug("Funding too costly for entry",
+			zap.String("pair", signal.Symbol),
+			zap.String("direction", string(signal.Direction)),
+			zap.String("predictedRate", rate.PredictedRate.String()),
+		)
+		return minConfidence, false
+	}
+	if filter.PreferRate.IsPositive() && paid.Neg().GreaterThanOrEqual(filter.PreferRate) {
+		return decimal.Max(minConfidence.Sub(filter.PreferDiscount), decimal.Zero), true
+	}
+	return minConfidence, true
+}
@@ -0,0 +1,91 @@
+package autonomous
+
+import (
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/internal/learning"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"go.uber.org/zap"
+)
+
+// SetJournal sets the trade journal closed trades are recorded in.
+func (ea *EnhancedTradingAgent) SetJournal(journal *learning.TradeJournal) {
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+
+	ea.journal = journal
+}
+
+// openJournalEntry notes an entry's strategy, regime and fill so the trade
+// can be journaled once it closes. A symbol keeps its first open entry until
+// then.
+func (ea *EnhancedTradingAgent) openJournalEntry(order *types.Order, result *execution.ExecutionResult) {
+	currentRegime, _ := ea.orchestrator.GetCurrentRegime(order.Symbol)
+	key := execution.NormalizeSymbol(order.Symbol)
+
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+
+	if ea.journal == nil {
+		return
+	}
+	if _, open := ea.openTrades[key]; open {
+		return
+	}
+
+	side := "long"
+	if order.Side == types.OrderSideSell {
+		side = "short"
+	}
+	ea.openTrades[key] = learning.JournalEntry{
+		TradeID:    result.OrderID,
+		Symbol:     order.Symbol,
+		Side:       side,
+		Strategy:   ea.activeStrategy,
+		Regime:     string(currentRegime),
+		Quantity:   result.FilledQty,
+		EntryPrice: result.AvgPrice,
+		EntryTime:  result.Timestamp,
+		Fees:       result.Commission,
+	}
+}
+
+// closeJournalEntryLocked journals a closed position against its open entry. The
+// quantity includes scale-ins, the exit price is the average that realized
+// the position's PnL, and fees are the entry's. The caller must hold ea.mu
+// and must not have dropped the position's managed state yet.
+func (ea *EnhancedTradingAgent) closeJournalEntryLocked(position *types.Position) {
+	key := execution.NormalizeSymbol(position.Symbol)
+	entry, open := ea.openTrades[key]
+	if !open {
+		return
+	}
+	delete(ea.openTrades, key)
+
+	if mp, ok := ea.managed[key]; ok {
+		entry.Quantity = mp.result.FilledQty
+	}
+	if !position.EntryPrice.IsZero() {
+		entry.EntryPrice = position.EntryPrice
+	}
+	// Funding is in the realized PnL but not the price move
+	entry.Funding = position.Funding
+	entry.PnL = position.RealizedPnL.Sub(position.Funding)
+	entry.ExitTime = time.Now()
+	entry.ExitPrice = entry.EntryPrice
+	if entry.Quantity.IsPositive() {
+		perUnit := entry.PnL.Div(entry.Quantity)
+		if entry.Side == "short" {
+			perUnit = perUnit.Neg()
+		}
+		entry.ExitPrice = entry.EntryPrice.Add(perUnit)
+	}
+
+	if err := ea.journal.Record(entry); err != nil {
+		ea.logger.Error("Failed to journal trade",
+			zap.String("tradeId", entry.TradeID),
+			zap.String("symbol", entry.Symbol),
+			zap.Error(err))
+	}
+}
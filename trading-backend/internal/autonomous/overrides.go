@@ -0,0 +1,171 @@
+package autonomous
+
+import (
+	"fmt"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/internal/regime"
+	"github.com/shopspring/decimal"
+)
+
+// PairOverride replaces base thresholds for one trading pair. Zero fields
+// fall back to the base config.
+type PairOverride struct {
+	MinConfidence      decimal.Decimal `json:"minConfidence"`      // Replaces BaseMinConfidence
+	MinConsensus       decimal.Decimal `json:"minConsensus"`       // Replaces BaseMinConsensus
+	MaxPositionPercent decimal.Decimal `json:"maxPositionPercent"` // Replaces MaxPositionPercent
+}
+
+// RegimeOverride scales a pair's thresholds while it is in a regime. Zero
+// multipliers leave the value unchanged.
+type RegimeOverride struct {
+	ConfidenceMultiplier decimal.Decimal `json:"confidenceMultiplier"`
+	ConsensusMultiplier  decimal.Decimal `json:"consensusMultiplier"`
+	PositionMultiplier   decimal.Decimal `json:"positionMultiplier"` // Applied to the maximum position size
+}
+
+// Validate checks that thresholds are fractions.
+func (o PairOverride) Validate() error {
+	for name, value := range map[string]decimal.Decimal{
+		"minConfidence":      o.MinConfidence,
+		"minConsensus":       o.MinConsensus,
+		"maxPositionPercent": o.MaxPositionPercent,
+	} {
+		if value.IsNegative() || value.GreaterThan(decimal.NewFromInt(1)) {
+			return fmt.Errorf("%s %s must be between 0 and 1", name, value)
+		}
+	}
+	return nil
+}
+
+// Validate checks that multipliers are not negative.
+func (o RegimeOverride) Validate() error {
+	for name, value := range map[string]decimal.Decimal{
+		"confidenceMultiplier": o.ConfidenceMultiplier,
+		"consensusMultiplier":  o.ConsensusMultiplier,
+		"positionMultiplier":   o.PositionMultiplier,
+	} {
+		if value.IsNegative() {
+			return fmt.Errorf("%s %s must not be negative", name, value)
+		}
+	}
+	return nil
+}
+
+// knownRegimes are the regimes a RegimeOverride can be registered for.
+var knownRegimes = map[regime.RegimeType]bool{
+	regime.RegimeBull:          true,
+	regime.RegimeBear:          true,
+	regime.RegimeHighVol:       true,
+	regime.RegimeLowVol:        true,
+	regime.RegimeMeanReverting: true,
+	regime.RegimeTrending:      true,
+	regime.RegimeTransition:    true,
+	regime.RegimeUnknown:       true,
+}
+
+// SetPairOverride validates and registers a pair's override, replacing any
+// previous one.
+func (ea *EnhancedTradingAgent) SetPairOverride(pair string, override PairOverride) error {
+	if err := validatePairOverride(pair, override); err != nil {
+		return err
+	}
+
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+	ea.config.PairOverrides[execution.NormalizeSymbol(pair)] = override
+	return nil
+}
+
+// SetRegimeOverride validates and registers a regime's override, replacing
+// any previous one.
+func (ea *EnhancedTradingAgent) SetRegimeOverride(regimeType regime.RegimeType, override RegimeOverride) error {
+	if err := validateRegimeOverride(regimeType, override); err != nil {
+		return err
+	}
+
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+	ea.config.RegimeOverrides[regimeType] = override
+	return nil
+}
+
+// validatePairOverride checks an override before it is registered for pair.
+func validatePairOverride(pair string, override PairOverride) error {
+	if err := override.Validate(); err != nil {
+		return fmt.Errorf("invalid override for %s: %w", pair, err)
+	}
+	return nil
+}
+
+// validateRegimeOverride checks an override before it is registered for
+// regimeType.
+func validateRegimeOverride(regimeType regime.RegimeType, override RegimeOverride) error {
+	if !knownRegimes[regimeType] {
+		return fmt.Errorf("unknown regime: %s", regimeType)
+	}
+	if err := override.Validate(); err != nil {
+		return fmt.Errorf("invalid override for regime %s: %w", regimeType, err)
+	}
+	return nil
+}
+
+// registerOverrides copies the configured overrides, keying pairs by their
+// normalized symbol. EnhancedAgentConfig.Validate has already checked them.
+func (ea *EnhancedTradingAgent) registerOverrides(pairs map[string]PairOverride, regimes map[regime.RegimeType]RegimeOverride) {
+	ea.config.PairOverrides = make(map[string]PairOverride, len(pairs))
+	ea.config.RegimeOverrides = make(map[regime.RegimeType]RegimeOverride, len(regimes))
+
+	for pair, override := range pairs {
+		ea.config.PairOverrides[execution.NormalizeSymbol(pair)] = override
+	}
+	for regimeType, override := range regimes {
+		ea.config.RegimeOverrides[regimeType] = override
+	}
+}
+
+// tradeLimits are the thresholds and position cap a pair trades under.
+type tradeLimits struct {
+	minConfidence      decimal.Decimal
+	minConsensus       decimal.Decimal
+	maxPositionPercent decimal.Decimal
+}
+
+// limitsFor returns the base limits with the pair's override, the active
+// de-risking tier, and the regime's override applied in that order.
+func (ea *EnhancedTradingAgent) limitsFor(pair string, regimeType regime.RegimeType) tradeLimits {
+	ea.mu.RLock()
+	defer ea.mu.RUnlock()
+
+	limits := tradeLimits{
+		minConfidence:      ea.config.BaseMinConfidence,
+		minConsensus:       ea.config.BaseMinConsensus,
+		maxPositionPercent: ea.config.MaxPositionPercent,
+	}
+	if override, ok := ea.config.PairOverrides[execution.NormalizeSymbol(pair)]; ok {
+		limits.minConfidence = orDefault(override.MinConfidence, limits.minConfidence)
+		limits.minConsensus = orDefault(override.MinConsensus, limits.minConsensus)
+		limits.maxPositionPercent = orDefault(override.MaxPositionPercent, limits.maxPositionPercent)
+	}
+
+	if ea.deRiskTier > 0 {
+		tier := ea.config.DeRiskTiers[ea.deRiskTier-1]
+		limits.maxPositionPercent = limits.maxPositionPercent.Mul(tier.PositionMultiplier)
+		limits.minConfidence = limits.minConfidence.Add(tier.ConfidenceBump)
+	}
+
+	if override, ok := ea.config.RegimeOverrides[regimeType]; ok {
+		limits.minConfidence = limits.minConfidence.Mul(orDefault(override.ConfidenceMultiplier, decimal.NewFromInt(1)))
+		limits.minConsensus = limits.minConsensus.Mul(orDefault(override.ConsensusMultiplier, decimal.NewFromInt(1)))
+		limits.maxPositionPercent = limits.maxPositionPercent.Mul(orDefault(override.PositionMultiplier, decimal.NewFromInt(1)))
+	}
+	return limits
+}
+
+// orDefault returns value, or fallback when value is zero.
+func orDefault(value, fallback decimal.Decimal) decimal.Decimal {
+	if value.IsZero() {
+		return fallback
+	}
+	return value
+}
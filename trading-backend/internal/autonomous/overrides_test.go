@@ -0,0 +1,100 @@
+package autonomous
+
+import (
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/regime"
+	"github.com/shopspring/decimal"
+)
+
+func TestValidateRejectsInvalidOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*EnhancedAgentConfig)
+		wantErr   bool
+	}{
+		{name: "defaults", configure: func(*EnhancedAgentConfig) {}},
+		{
+			name: "pair override in range",
+			configure: func(c *EnhancedAgentConfig) {
+				c.PairOverrides = map[string]PairOverride{"BTC/USDT": {MinConfidence: decimal.NewFromFloat(0.7)}}
+			},
+		},
+		{
+			name: "pair confidence above one",
+			configure: func(c *EnhancedAgentConfig) {
+				c.PairOverrides = map[string]PairOverride{"BTC/USDT": {MinConfidence: decimal.NewFromFloat(1.5)}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative pair position cap",
+			configure: func(c *EnhancedAgentConfig) {
+				c.PairOverrides = map[string]PairOverride{"ETH/USDT": {MaxPositionPercent: decimal.NewFromFloat(-0.1)}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative regime multiplier",
+			configure: func(c *EnhancedAgentConfig) {
+				c.RegimeOverrides[regime.RegimeBear] = RegimeOverride{PositionMultiplier: decimal.NewFromFloat(-0.5)}
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown regime",
+			configure: func(c *EnhancedAgentConfig) {
+				c.RegimeOverrides["sideways"] = RegimeOverride{ConfidenceMultiplier: decimal.NewFromFloat(1.1)}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultEnhancedAgentConfig()
+			tt.configure(&config)
+			err := config.Validate()
+			if tt.wantErr && err == nil {
+				t.Error("Expected a validation error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestLimitsForAppliesOverrides(t *testing.T) {
+	ea := newPaperAgent(t, &paperVenue{}, func(config *EnhancedAgentConfig) {
+		config.PairOverrides = map[string]PairOverride{
+			"BTCUSDT": {MinConfidence: decimal.NewFromFloat(0.7), MaxPositionPercent: decimal.NewFromFloat(0.2)},
+		}
+		config.RegimeOverrides = map[regime.RegimeType]RegimeOverride{
+			regime.RegimeHighVol: {ConfidenceMultiplier: decimal.NewFromFloat(1.2), PositionMultiplier: decimal.NewFromFloat(0.5)},
+		}
+	})
+
+	// Pairs match under any spelling of the symbol
+	limits := ea.limitsFor("BTC/USDT", regime.RegimeBull)
+	if !limits.minConfidence.Equal(decimal.NewFromFloat(0.7)) || !limits.maxPositionPercent.Equal(decimal.NewFromFloat(0.2)) {
+		t.Errorf("Expected the pair override, got %+v", limits)
+	}
+	if !limits.minConsensus.Equal(ea.config.BaseMinConsensus) {
+		t.Errorf("Expected an unset field to keep the base consensus, got %s", limits.minConsensus)
+	}
+
+	limits = ea.limitsFor("BTC/USDT", regime.RegimeHighVol)
+	if !limits.minConfidence.Equal(decimal.NewFromFloat(0.84)) || !limits.maxPositionPercent.Equal(decimal.NewFromFloat(0.1)) {
+		t.Errorf("Expected the regime to scale the pair override, got %+v", limits)
+	}
+
+	limits = ea.limitsFor("ETH/USDT", regime.RegimeBull)
+	if !limits.minConfidence.Equal(ea.config.BaseMinConfidence) || !limits.maxPositionPercent.Equal(ea.config.MaxPositionPercent) {
+		t.Errorf("Expected base limits without an override, got %+v", limits)
+	}
+
+	if err := ea.SetPairOverride("ETH/USDT", PairOverride{MinConsensus: decimal.NewFromInt(2)}); err == nil {
+		t.Error("Expected SetPairOverride to reject a consensus above one")
+	}
+}
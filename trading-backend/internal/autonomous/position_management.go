@@ -0,0 +1,483 @@
+// Package autonomous provides position management for the enhanced agent:
+// trailing stops, scaling into winners and tiered exits.
+package autonomous
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/internal/signals"
+	"github.com/atlas-desktop/trading-backend/internal/strategy"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// managedPosition is an open position whose exits the agent manages.
+type managedPosition struct {
+	mu         sync.Mutex // Serializes exit changes
+	symbol     string
+	result     *execution.ExecutionResult // Entry execution and its resting exits
+	long       bool
+	stop       decimal.Decimal
+	takeProfit decimal.Decimal // Held by the agent when scaling out
+	confidence decimal.Decimal // Confidence of the latest entry
+	scaleIns   int
+	scaledOut  bool // The take profit was hit and a runner remains
+
+	// Time-based exits
+	openedAt    time.Time
+	maxHolding  time.Duration // 0 holds until the stop or target
+	timeExit    TimeExitMode
+	timeStopped bool            // The holding period elapsed and the stop was moved to breakeven
+	closed      bool            // Closed by the agent, awaiting RecordClosedTrade
+	price       decimal.Decimal // Latest price seen by the management loop
+}
+
+// priceTick is a price update queued for the position management loop.
+type priceTick struct {
+	symbol string
+	price  decimal.Decimal
+}
+
+// managesPositions reports whether any position management is enabled.
+// Paper positions are always managed, since the agent fires their stops.
+func (ea *EnhancedTradingAgent) managesPositions() bool {
+	return ea.config.PaperTrading || ea.config.EnableTrailingStop || ea.config.MaxScaleIns > 0 ||
+		ea.config.ScaleOutFraction.IsPositive() || ea.timeExitsEnabled()
+}
+
+// UpdatePrice feeds a price update to position management. Updates are
+// dropped while the loop is busy; the next one supersedes them anyway.
+func (ea *EnhancedTradingAgent) UpdatePrice(symbol string, price decimal.Decimal) {
+	if !ea.managesPositions() {
+		return
+	}
+
+	select {
+	case ea.prices <- priceTick{symbol: symbol, price: price}:
+	default:
+	}
+}
+
+// UpdateBar records a closed bar for ATR-based trail distances.
+func (ea *EnhancedTradingAgent) UpdateBar(symbol string, bar types.OHLCV) {
+	if !ea.managesPositions() || !ea.config.TrailUseATR {
+		return
+	}
+
+	key := execution.NormalizeSymbol(symbol)
+
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+
+	bars := append(ea.bars[key], bar)
+	if len(bars) > ea.config.TrailATRPeriod+1 {
+		bars = bars[len(bars)-ea.config.TrailATRPeriod-1:]
+	}
+	ea.bars[key] = bars
+}
+
+// laddersEntries reports whether live entries get the take-profit ladder.
+func (ea *EnhancedTradingAgent) laddersEntries() bool {
+	return len(ea.config.TPLadder) > 0 && !ea.config.PaperTrading
+}
+
+// attachLadder records an entry's fill with the order manager and attaches
+// the take-profit ladder to the position, whose update places the rungs and
+// the stop. The ladder manages the position's exits from then on, so it is
+// not tracked here.
+func (ea *EnhancedTradingAgent) attachLadder(order *types.Order, result *execution.ExecutionResult, stopLoss decimal.Decimal) error {
+	// Tracked for what filled, so stream fills already counted are ignored
+	entry := *order
+	entry.ID = result.OrderID
+	entry.Quantity = result.FilledQty
+	ea.orderManager.TrackOrder(&entry, result.Exchange, "")
+	ea.orderManager.RecordFill(execution.OrderFill{
+		OrderID:    entry.ID,
+		Price:      result.AvgPrice,
+		Quantity:   result.FilledQty,
+		Commission: result.Commission,
+		Timestamp:  result.Timestamp,
+	})
+
+	ea.orderManager.SetStopLoss(order.Symbol, stopLoss)
+	if err := ea.orderManager.AttachTPLadder(order.Symbol, ea.config.TPLadder); err != nil {
+		return fmt.Errorf("failed to attach take-profit ladder: %w", err)
+	}
+	return nil
+}
+
+// trackPosition starts managing the exits of an executed order.
+func (ea *EnhancedTradingAgent) trackPosition(
+	order *types.Order,
+	result *execution.ExecutionResult,
+	stopLoss, takeProfit, confidence decimal.Decimal,
+) {
+	ea.mu.Lock()
+	defer ea.mu.Unlock()
+
+	maxHolding, timeExit := ea.holdingPeriodLocked()
+	openedAt := result.Timestamp
+	if openedAt.IsZero() {
+		openedAt = time.Now()
+	}
+
+	ea.managed[execution.NormalizeSymbol(order.Symbol)] = &managedPosition{
+		symbol:     order.Symbol,
+		result:     result,
+		long:       order.Side == types.OrderSideBuy,
+		stop:       stopLoss,
+		takeProfit: takeProfit,
+		confidence: confidence,
+		openedAt:   openedAt,
+		maxHolding: maxHolding,
+		timeExit:   timeExit,
+	}
+}
+
+// managedPositionFor returns the managed position for a symbol, if any.
+func (ea *EnhancedTradingAgent) managedPositionFor(symbol string) *managedPosition {
+	ea.mu.RLock()
+	defer ea.mu.RUnlock()
+	return ea.managed[execution.NormalizeSymbol(symbol)]
+}
+
+// positionManagementLoop manages exits as prices arrive, and checks time
+// exits between prices when they are enabled.
+func (ea *EnhancedTradingAgent) positionManagementLoop(ctx context.Context) {
+	var timeExits <-chan time.Time
+	if ea.timeExitsEnabled() {
+		ticker := time.NewTicker(timeExitCheckInterval)
+		defer ticker.Stop()
+		timeExits = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ea.stopCh:
+			return
+		case tick := <-ea.prices:
+			ea.managePosition(ctx, tick.symbol, tick.price)
+		case now := <-timeExits:
+			ea.checkTimeExits(ctx, now)
+		}
+	}
+}
+
+// managePosition applies time exits, fires paper stops, scales out at the
+// take profit and trails the stop.
+func (ea *EnhancedTradingAgent) managePosition(ctx context.Context, symbol string, price decimal.Decimal) {
+	mp := ea.managedPositionFor(symbol)
+	if mp == nil || !price.IsPositive() {
+		return
+	}
+
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	mp.price = price
+	if ea.applyTimeExit(ctx, mp, time.Now()) {
+		return
+	}
+
+	// Paper positions have no resting stop on an exchange to fill
+	if mp.result.IsPaper && mp.stopHit(price) {
+		ea.closeManaged(ctx, mp, ea.orderManager.GetPosition(mp.symbol), "paper stop loss hit")
+		return
+	}
+
+	if !mp.scaledOut && ea.config.ScaleOutFraction.IsPositive() && mp.reachedTarget(price) {
+		ea.scaleOut(ctx, mp)
+	}
+
+	// A runner left by a scale-out always trails
+	if ea.config.EnableTrailingStop || mp.scaledOut {
+		ea.trailStop(ctx, mp, price)
+	}
+}
+
+// closeManaged cancels a position's resting exits and closes it at market,
+// reporting whether it was closed. A failure is retried on the next check.
+// Callers hold mp.mu.
+func (ea *EnhancedTradingAgent) closeManaged(ctx context.Context, mp *managedPosition, position *types.Position, reason string) bool {
+	quantity := mp.result.FilledQty
+	if position != nil {
+		quantity = position.Quantity
+	}
+
+	// Resting exits left behind could open a new position once filled
+	if err := ea.executor.CancelExits(ctx, mp.result); err != nil {
+		ea.logger.Warn("Failed to cancel exits before closing position",
+			zap.String("symbol", mp.symbol),
+			zap.Error(err))
+		return false
+	}
+
+	side := types.PositionSideLong
+	if !mp.long {
+		side = types.PositionSideShort
+	}
+	exit := &types.Position{
+		Symbol:   mp.symbol,
+		Side:     side,
+		Quantity: quantity,
+	}
+	result, err := ea.executor.ClosePosition(ctx, exit, mp.exitExchange())
+	if err != nil {
+		ea.logger.Warn("Failed to close position",
+			zap.String("symbol", mp.symbol),
+			zap.String("reason", reason),
+			zap.Error(err))
+		return false
+	}
+	mp.closed = true
+
+	ea.logger.Info("Closed managed position",
+		zap.String("symbol", mp.symbol),
+		zap.String("reason", reason),
+		zap.String("quantity", result.FilledQty.String()),
+		zap.Duration("timeInTrade", time.Since(mp.openedAt)))
+	return true
+}
+
+// reachedTarget reports whether price has reached the take profit.
+func (mp *managedPosition) reachedTarget(price decimal.Decimal) bool {
+	if mp.takeProfit.IsZero() {
+		return false
+	}
+	if mp.long {
+		return price.GreaterThanOrEqual(mp.takeProfit)
+	}
+	return price.LessThanOrEqual(mp.takeProfit)
+}
+
+// exitExchange is the venue exits are routed to. Paper fills are tagged
+// "paper", which names no adapter, so they follow the executor's routing.
+func (mp *managedPosition) exitExchange() string {
+	if mp.result.IsPaper {
+		return ""
+	}
+	return mp.result.Exchange
+}
+
+// stopHit reports whether price has reached the stop.
+func (mp *managedPosition) stopHit(price decimal.Decimal) bool {
+	if mp.stop.IsZero() {
+		return false
+	}
+	if mp.long {
+		return price.LessThanOrEqual(mp.stop)
+	}
+	return price.GreaterThanOrEqual(mp.stop)
+}
+
+// canScaleIn reports whether a signal may add to an open position: it must
+// agree with the position, be more confident than the last entry, and arrive
+// while the position is in profit. Positions past their holding period are
+// being exited, not added to, and one of unknown cost can't be known to be in
+// profit.
+func (ea *EnhancedTradingAgent) canScaleIn(signal *signals.AggregatedSignal, position *types.Position, mp *managedPosition) bool {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	if mp.scaledOut || mp.timeStopped || mp.closed || mp.scaleIns >= ea.config.MaxScaleIns || position.CostUnknown {
+		return false
+	}
+	if (signal.Direction == signals.DirectionLong) != mp.long {
+		return false
+	}
+	if !signal.Confidence.GreaterThan(mp.confidence) {
+		return false
+	}
+
+	price := signal.SuggestedEntry
+	if mp.long {
+		return price.GreaterThan(position.EntryPrice)
+	}
+	return price.LessThan(position.EntryPrice)
+}
+
+// scaleIn adds an increment to a winning position, keeping the whole position
+// within the maximum position size, and resizes the stop to cover it.
+func (ea *EnhancedTradingAgent) scaleIn(
+	ctx context.Context,
+	signal *signals.AggregatedSignal,
+	position *types.Position,
+	mp *managedPosition,
+) error {
+	portfolioValue, err := ea.portfolioEquity()
+	if err != nil {
+		return err
+	}
+
+	// The increment and the position already held are sized as notionals,
+	// so the whole position stays within the cap an entry would get
+	sizeResult := ea.orchestrator.SizePosition(ea.sizeRequest(signal, portfolioValue))
+	notional := decimal.NewFromFloat(sizeResult.PositionSize).Mul(ea.config.ScaleInFraction)
+	held := position.Quantity.Mul(signal.SuggestedEntry)
+	increment := ea.orderQuantity(signal, portfolioValue, notional, held)
+	if increment.IsZero() {
+		return nil
+	}
+
+	order := &types.Order{
+		Symbol:   signal.Symbol,
+		Type:     types.OrderTypeMarket,
+		Quantity: increment,
+		Price:    signal.SuggestedEntry,
+		Side:     types.OrderSideBuy,
+	}
+	if !mp.long {
+		order.Side = types.OrderSideSell
+	}
+
+	riskResult := ea.riskManager.CheckOrder(ctx, order, portfolioValue)
+	if !riskResult.Approved {
+		ea.logger.Warn("Scale-in rejected by risk manager",
+			zap.String("symbol", order.Symbol),
+			zap.Int("violations", len(riskResult.Violations)))
+		return nil
+	}
+
+	result, err := ea.executor.Execute(ctx, order)
+	if err != nil {
+		return fmt.Errorf("scale-in execution failed: %w", err)
+	}
+
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	mp.scaleIns++
+	mp.confidence = signal.Confidence
+	mp.result.FilledQty = mp.result.FilledQty.Add(result.FilledQty)
+
+	ea.logger.Info("Scaled into position",
+		zap.String("symbol", order.Symbol),
+		zap.String("quantity", result.FilledQty.String()),
+		zap.Int("scaleIns", mp.scaleIns))
+
+	if mp.stop.IsZero() {
+		return nil
+	}
+	if err := ea.executor.ReplaceStopLoss(ctx, mp.result, mp.stop); err != nil {
+		return fmt.Errorf("failed to resize stop after scale-in: %w", err)
+	}
+	return nil
+}
+
+// scaleOut closes ScaleOutFraction of the position at the take profit and
+// moves the runner's stop to at least breakeven. Callers hold mp.mu.
+func (ea *EnhancedTradingAgent) scaleOut(ctx context.Context, mp *managedPosition) {
+	quantity := mp.result.FilledQty
+	entry := mp.result.AvgPrice
+	if position := ea.orderManager.GetPosition(mp.symbol); position != nil {
+		quantity = position.Quantity
+		entry = position.EntryPrice
+	}
+
+	side := types.PositionSideLong
+	if !mp.long {
+		side = types.PositionSideShort
+	}
+
+	exit := &types.Position{
+		Symbol:   mp.symbol,
+		Side:     side,
+		Quantity: quantity.Mul(ea.config.ScaleOutFraction),
+	}
+	result, err := ea.executor.ClosePosition(ctx, exit, mp.exitExchange())
+	if err != nil {
+		ea.logger.Warn("Failed to scale out",
+			zap.String("symbol", mp.symbol),
+			zap.Error(err))
+		return
+	}
+
+	mp.scaledOut = true
+	mp.result.FilledQty = quantity.Sub(result.FilledQty)
+
+	ea.logger.Info("Scaled out at take profit",
+		zap.String("symbol", mp.symbol),
+		zap.String("closed", result.FilledQty.String()),
+		zap.String("runner", mp.result.FilledQty.String()))
+
+	if !mp.result.FilledQty.IsPositive() {
+		return
+	}
+
+	stop := mp.stop
+	if mp.long && entry.GreaterThan(stop) {
+		stop = entry
+	} else if !mp.long && (stop.IsZero() || entry.LessThan(stop)) {
+		stop = entry
+	}
+
+	if err := ea.executor.ReplaceStopLoss(ctx, mp.result, stop); err != nil {
+		ea.logger.Warn("Failed to move runner stop to breakeven",
+			zap.String("symbol", mp.symbol),
+			zap.Error(err))
+		return
+	}
+	mp.stop = stop
+}
+
+// trailStop ratchets a position's stop toward price. The stop only moves in
+// the position's favor, and the trail distance is scaled by the stop loss
+// multiplier blended across the symbol's likely regimes. Callers hold mp.mu.
+func (ea *EnhancedTradingAgent) trailStop(ctx context.Context, mp *managedPosition, price decimal.Decimal) {
+	ea.mu.RLock()
+	bars := ea.bars[execution.NormalizeSymbol(mp.symbol)]
+	ea.mu.RUnlock()
+
+	distance := ea.trailDistance(price, bars)
+	if distance.IsZero() {
+		return
+	}
+	adjustments := ea.orchestrator.GetBlendedAdjustments(mp.symbol)
+	distance = distance.Mul(decimal.NewFromFloat(adjustments.StopLossMultiplier))
+
+	stop, ok := trailedStop(mp.long, mp.stop, price, distance)
+	if !ok {
+		return
+	}
+
+	if err := ea.executor.ReplaceStopLoss(ctx, mp.result, stop); err != nil {
+		ea.logger.Warn("Failed to trail stop",
+			zap.String("symbol", mp.symbol),
+			zap.String("stop", stop.String()),
+			zap.Error(err))
+		return
+	}
+	mp.stop = stop
+
+	ea.logger.Debug("Trailed stop",
+		zap.String("symbol", mp.symbol),
+		zap.String("price", price.String()),
+		zap.String("stop", stop.String()))
+}
+
+// trailedStop returns the stop distance behind price, and whether it
+// improves on the current stop. A zero current stop is no stop at all.
+func trailedStop(long bool, current, price, distance decimal.Decimal) (decimal.Decimal, bool) {
+	if long {
+		stop := price.Sub(distance)
+		return stop, stop.IsPositive() && stop.GreaterThan(current)
+	}
+	stop := price.Add(distance)
+	return stop, current.IsZero() || stop.LessThan(current)
+}
+
+// trailDistance returns how far behind price the stop trails, or zero while
+// too few bars exist for an ATR-based distance.
+func (ea *EnhancedTradingAgent) trailDistance(price decimal.Decimal, bars []types.OHLCV) decimal.Decimal {
+	if ea.config.TrailUseATR {
+		return strategy.ATR(bars, ea.config.TrailATRPeriod).Mul(ea.config.TrailDistance)
+	}
+	return price.Mul(ea.config.TrailDistance)
+}
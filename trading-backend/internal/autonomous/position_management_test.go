@@ -0,0 +1,497 @@
+package autonomous
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/internal/orchestrator"
+	"github.com/atlas-desktop/trading-backend/internal/signals"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// paperVenue quotes a last price for paper fills and rejects real orders.
+type paperVenue struct {
+	last decimal.Decimal
+}
+
+func (v *paperVenue) Name() string                      { return "venue" }
+func (v *paperVenue) Connect(ctx context.Context) error { return nil }
+func (v *paperVenue) Disconnect() error                 { return nil }
+func (v *paperVenue) CancelOrder(ctx context.Context, orderID string) error {
+	return fmt.Errorf("paper venue has no orders")
+}
+func (v *paperVenue) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	return nil, fmt.Errorf("paper venue has no orders")
+}
+func (v *paperVenue) GetOrder(ctx context.Context, orderID string) (*types.Order, error) {
+	return nil, fmt.Errorf("paper venue has no orders")
+}
+func (v *paperVenue) GetBalance(ctx context.Context, asset string) (decimal.Decimal, error) {
+	return decimal.Zero, nil
+}
+func (v *paperVenue) GetPositions(ctx context.Context) ([]*types.Position, error) { return nil, nil }
+func (v *paperVenue) GetOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error) {
+	return nil, fmt.Errorf("no book")
+}
+func (v *paperVenue) GetTicker(ctx context.Context, symbol string) (*adapters.Ticker, error) {
+	return &adapters.Ticker{Symbol: symbol, LastPrice: v.last}, nil
+}
+
+// newPaperAgent creates an agent trading on a paper executor, with time
+// exits off so only the management under test acts.
+func newPaperAgent(t *testing.T, venue *paperVenue, configure func(*EnhancedAgentConfig)) *EnhancedTradingAgent {
+	t.Helper()
+
+	orch, err := orchestrator.NewTradingOrchestrator(zap.NewNop(), orchestrator.DefaultOrchestratorConfig(), nil, nil)
+	if err != nil {
+		t.Fatalf("NewTradingOrchestrator failed: %v", err)
+	}
+	executor := execution.NewExecutor(zap.NewNop(), execution.ExecutorConfig{PaperTrading: true})
+	executor.SetDefaultAdapter(venue)
+
+	config := DefaultEnhancedAgentConfig()
+	config.PaperTrading = true
+	config.TimeExits = TimeExits{}
+	if configure != nil {
+		configure(&config)
+	}
+	return NewEnhancedTradingAgent(zap.NewNop(), config, orch, executor,
+		execution.NewRiskManager(zap.NewNop(), execution.DefaultRiskConfig()),
+		execution.NewOrderManager(zap.NewNop()), nil)
+}
+
+// openPosition tracks an entry of quantity at price with the given stop.
+func openPosition(ea *EnhancedTradingAgent, side types.OrderSide, quantity, price, stop float64, paper bool) *managedPosition {
+	order := &types.Order{
+		ID:       "entry",
+		Symbol:   "BTC/USDT",
+		Side:     side,
+		Type:     types.OrderTypeMarket,
+		Quantity: decimal.NewFromFloat(quantity),
+	}
+	result := &execution.ExecutionResult{
+		OrderID:   order.ID,
+		Order:     order,
+		Exchange:  "venue",
+		FilledQty: order.Quantity,
+		AvgPrice:  decimal.NewFromFloat(price),
+		Timestamp: time.Now(),
+		IsPaper:   paper,
+	}
+	ea.trackPosition(order, result, decimal.NewFromFloat(stop), decimal.Zero, decimal.NewFromFloat(0.6))
+	return ea.managedPositionFor(order.Symbol)
+}
+
+func TestTrailedStopRatchets(t *testing.T) {
+	d := decimal.NewFromInt
+	tests := []struct {
+		name    string
+		long    bool
+		current decimal.Decimal
+		price   decimal.Decimal
+		want    decimal.Decimal
+		moves   bool
+	}{
+		{"long rises", true, d(95), d(110), d(105), true},
+		{"long falls", true, d(95), d(98), d(93), false},
+		{"long unchanged", true, d(95), d(100), d(95), false},
+		{"long stop below zero", true, decimal.Zero, d(4), d(-1), false},
+		{"short falls", false, d(105), d(90), d(95), true},
+		{"short rises", false, d(105), d(102), d(107), false},
+		{"short without stop", false, decimal.Zero, d(100), d(105), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stop, moves := trailedStop(tt.long, tt.current, tt.price, d(5))
+			if moves != tt.moves {
+				t.Errorf("Expected moves %v, got %v", tt.moves, moves)
+			}
+			if !stop.Equal(tt.want) {
+				t.Errorf("Expected stop %s, got %s", tt.want, stop)
+			}
+		})
+	}
+}
+
+func TestTrailedStopNeverLoosens(t *testing.T) {
+	d := decimal.NewFromInt
+	stop := d(90)
+	for _, price := range []int64{100, 104, 101, 97, 108, 103} {
+		if next, ok := trailedStop(true, stop, d(price), d(5)); ok {
+			stop = next
+		}
+	}
+	if !stop.Equal(d(103)) {
+		t.Errorf("Expected the stop held at its high-water 103, got %s", stop)
+	}
+
+	stop = d(110)
+	for _, price := range []int64{100, 96, 99, 103, 92, 97} {
+		if next, ok := trailedStop(false, stop, d(price), d(5)); ok {
+			stop = next
+		}
+	}
+	if !stop.Equal(d(97)) {
+		t.Errorf("Expected the stop held at its low-water 97, got %s", stop)
+	}
+}
+
+func TestTrailDistance(t *testing.T) {
+	ea := &EnhancedTradingAgent{config: DefaultEnhancedAgentConfig()}
+	price := decimal.NewFromInt(200)
+
+	if got := ea.trailDistance(price, nil); !got.Equal(decimal.NewFromInt(4)) {
+		t.Errorf("Expected 2%% of price, got %s", got)
+	}
+
+	ea.config.TrailUseATR = true
+	ea.config.TrailATRPeriod = 2
+	ea.config.TrailDistance = decimal.NewFromInt(3)
+
+	bar := func(high, low, close int64) types.OHLCV {
+		return types.OHLCV{
+			Timestamp: time.Now(),
+			High:      decimal.NewFromInt(high),
+			Low:       decimal.NewFromInt(low),
+			Close:     decimal.NewFromInt(close),
+		}
+	}
+	bars := []types.OHLCV{bar(101, 99, 100), bar(102, 98, 100)}
+	if got := ea.trailDistance(price, bars); !got.IsZero() {
+		t.Errorf("Expected no distance before the ATR warms up, got %s", got)
+	}
+
+	bars = append(bars, bar(103, 97, 100))
+	// ATR over the last two bars is (4+6)/2
+	if got := ea.trailDistance(price, bars); !got.Equal(decimal.NewFromInt(15)) {
+		t.Errorf("Expected 3 ATRs of 5, got %s", got)
+	}
+}
+
+func TestPaperStopClosesPosition(t *testing.T) {
+	tests := []struct {
+		name   string
+		side   types.OrderSide
+		stop   float64
+		prices []float64
+		paper  bool
+		closed bool
+	}{
+		{name: "long above stop", side: types.OrderSideBuy, stop: 95, prices: []float64{99, 96}},
+		{name: "long at stop", side: types.OrderSideBuy, stop: 95, prices: []float64{99, 95}, closed: true},
+		{name: "long gaps through stop", side: types.OrderSideBuy, stop: 95, prices: []float64{90}, closed: true},
+		{name: "short below stop", side: types.OrderSideSell, stop: 105, prices: []float64{101, 104}},
+		{name: "short through stop", side: types.OrderSideSell, stop: 105, prices: []float64{101, 106}, closed: true},
+		// A live stop rests on the exchange, which fills it
+		{name: "live position", side: types.OrderSideBuy, stop: 95, prices: []float64{90}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			venue := &paperVenue{last: decimal.NewFromInt(100)}
+			ea := newPaperAgent(t, venue, nil)
+			mp := openPosition(ea, tt.side, 1, 100, tt.stop, tt.name != "live position")
+
+			for _, price := range tt.prices {
+				venue.last = decimal.NewFromFloat(price)
+				ea.managePosition(context.Background(), "BTC/USDT", venue.last)
+			}
+			if mp.closed != tt.closed {
+				t.Errorf("Expected closed %v, got %v", tt.closed, mp.closed)
+			}
+		})
+	}
+}
+
+func TestPaperTrailingStopFires(t *testing.T) {
+	venue := &paperVenue{last: decimal.NewFromInt(100)}
+	ea := newPaperAgent(t, venue, func(config *EnhancedAgentConfig) {
+		config.EnableTrailingStop = true
+	})
+	mp := openPosition(ea, types.OrderSideBuy, 1, 100, 95, true)
+	ctx := context.Background()
+
+	venue.last = decimal.NewFromInt(120)
+	ea.managePosition(ctx, "BTC/USDT", venue.last)
+	if mp.closed || !mp.stop.GreaterThan(decimal.NewFromInt(100)) {
+		t.Fatalf("Expected the stop trailed above entry, got %s (closed %v)", mp.stop, mp.closed)
+	}
+
+	// A pullback that would not reach the original stop hits the trailed one
+	venue.last = decimal.NewFromInt(100)
+	ea.managePosition(ctx, "BTC/USDT", venue.last)
+	if !mp.closed {
+		t.Errorf("Expected the trailed paper stop at %s to close the position", mp.stop)
+	}
+
+	// The closed position is left alone
+	ea.managePosition(ctx, "BTC/USDT", decimal.NewFromInt(130))
+	if !mp.stop.LessThan(decimal.NewFromInt(130)) {
+		t.Errorf("Expected no trailing after the close, got stop %s", mp.stop)
+	}
+}
+
+func TestOrderQuantity(t *testing.T) {
+	types.SymbolSpecs.Set(types.SymbolSpec{
+		Symbol:      "STEP/USDT",
+		StepSize:    decimal.NewFromFloat(0.5),
+		MinNotional: decimal.NewFromInt(100),
+	})
+
+	d := decimal.NewFromInt
+	tests := []struct {
+		name     string
+		symbol   string
+		price    int64
+		notional int64
+		held     int64
+		scale    float64
+		want     decimal.Decimal
+	}{
+		{name: "within cap", notional: 500, want: d(5)},
+		{name: "capped", notional: 2000, want: d(10)},
+		{name: "room left by held notional", notional: 500, held: 800, want: d(2)},
+		{name: "held at cap", notional: 500, held: 1000, want: decimal.Zero},
+		{name: "degraded", notional: 2000, scale: 0.5, want: d(5)},
+		{name: "degraded with held notional", notional: 2000, held: 300, scale: 0.5, want: d(2)},
+		{name: "no entry price", price: -1, notional: 500, want: decimal.Zero},
+		{name: "floored to step", symbol: "STEP/USDT", notional: 530, want: decimal.NewFromFloat(5)},
+		{name: "below min notional", symbol: "STEP/USDT", notional: 90, want: decimal.Zero},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ea := newPaperAgent(t, &paperVenue{}, func(config *EnhancedAgentConfig) {
+				config.MaxPositionPercent = decimal.NewFromFloat(0.1)
+			})
+
+			signal := &signals.AggregatedSignal{
+				Symbol:         "BTC/USDT",
+				SuggestedEntry: d(100),
+				PositionScale:  decimal.NewFromInt(1),
+			}
+			if tt.symbol != "" {
+				signal.Symbol = tt.symbol
+			}
+			if tt.price < 0 {
+				signal.SuggestedEntry = decimal.Zero
+			}
+			if tt.scale > 0 {
+				signal.Degraded = true
+				signal.PositionScale = decimal.NewFromFloat(tt.scale)
+			}
+
+			got := ea.orderQuantity(signal, d(10000), d(tt.notional), d(tt.held))
+			if !got.Equal(tt.want) {
+				t.Errorf("Expected quantity %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestScaleInStaysWithinCap(t *testing.T) {
+	venue := &paperVenue{last: decimal.NewFromInt(110)}
+	ea := newPaperAgent(t, venue, func(config *EnhancedAgentConfig) {
+		config.MaxPositionPercent = decimal.NewFromFloat(0.1)
+		config.MaxScaleIns = 2
+	})
+	ea.SetPortfolioManager(execution.NewPortfolioManager(zap.NewNop(), execution.DefaultPortfolioConfig()))
+	mp := openPosition(ea, types.OrderSideBuy, 8, 100, 95, true)
+	ctx := context.Background()
+
+	signal := &signals.AggregatedSignal{
+		Symbol:         "BTC/USDT",
+		Direction:      signals.DirectionLong,
+		Confidence:     decimal.NewFromFloat(0.8),
+		SuggestedEntry: decimal.NewFromInt(110),
+		PositionScale:  decimal.NewFromInt(1),
+	}
+	position := &types.Position{
+		Symbol:     "BTC/USDT",
+		Side:       types.PositionSideLong,
+		Quantity:   decimal.NewFromInt(8),
+		EntryPrice: decimal.NewFromInt(100),
+	}
+	if !ea.canScaleIn(signal, position, mp) {
+		t.Fatal("Expected a more confident signal on a winning position to scale in")
+	}
+
+	// 880 of the 1000 cap is held at 110, leaving room for 120 of notional
+	if err := ea.scaleIn(ctx, signal, position, mp); err != nil {
+		t.Fatalf("scaleIn failed: %v", err)
+	}
+	added := mp.result.FilledQty.Sub(decimal.NewFromInt(8))
+	if mp.scaleIns != 1 || !added.Mul(signal.SuggestedEntry).Round(8).Equal(decimal.NewFromInt(120)) {
+		t.Fatalf("Expected one add of 120 notional, got %d adds of %s", mp.scaleIns, added)
+	}
+
+	// The position is now at the cap
+	position.Quantity = mp.result.FilledQty
+	signal.Confidence = decimal.NewFromFloat(0.9)
+	if err := ea.scaleIn(ctx, signal, position, mp); err != nil {
+		t.Fatalf("scaleIn failed: %v", err)
+	}
+	if mp.scaleIns != 1 {
+		t.Errorf("Expected no add beyond the cap, got %d adds", mp.scaleIns)
+	}
+}
+
+func TestScaleInDegradedCap(t *testing.T) {
+	venue := &paperVenue{last: decimal.NewFromInt(110)}
+	ea := newPaperAgent(t, venue, func(config *EnhancedAgentConfig) {
+		config.MaxPositionPercent = decimal.NewFromFloat(0.1)
+		config.MaxScaleIns = 2
+	})
+	ea.SetPortfolioManager(execution.NewPortfolioManager(zap.NewNop(), execution.DefaultPortfolioConfig()))
+	mp := openPosition(ea, types.OrderSideBuy, 4, 100, 95, true)
+
+	// Half the cap is 500, and 440 of it is held
+	signal := &signals.AggregatedSignal{
+		Symbol:         "BTC/USDT",
+		Direction:      signals.DirectionLong,
+		Confidence:     decimal.NewFromFloat(0.8),
+		SuggestedEntry: decimal.NewFromInt(110),
+		Degraded:       true,
+		PositionScale:  decimal.NewFromFloat(0.5),
+	}
+	position := &types.Position{
+		Symbol:     "BTC/USDT",
+		Side:       types.PositionSideLong,
+		Quantity:   decimal.NewFromInt(4),
+		EntryPrice: decimal.NewFromInt(100),
+	}
+	if err := ea.scaleIn(context.Background(), signal, position, mp); err != nil {
+		t.Fatalf("scaleIn failed: %v", err)
+	}
+	added := mp.result.FilledQty.Sub(decimal.NewFromInt(4))
+	if !added.Mul(signal.SuggestedEntry).Round(8).Equal(decimal.NewFromInt(60)) {
+		t.Errorf("Expected an add of 60 notional under the degraded cap, got %s", added)
+	}
+}
+
+func TestScaleOutAtTakeProfit(t *testing.T) {
+	tests := []struct {
+		name     string
+		side     types.OrderSide
+		stop     float64
+		target   float64
+		price    float64
+		pullback float64
+	}{
+		{name: "long", side: types.OrderSideBuy, stop: 95, target: 110, price: 111, pullback: 100},
+		{name: "short", side: types.OrderSideSell, stop: 105, target: 90, price: 89, pullback: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			venue := &paperVenue{last: decimal.NewFromInt(100)}
+			ea := newPaperAgent(t, venue, func(config *EnhancedAgentConfig) {
+				config.ScaleOutFraction = decimal.NewFromFloat(0.5)
+			})
+			mp := openPosition(ea, tt.side, 2, 100, tt.stop, true)
+			mp.takeProfit = decimal.NewFromFloat(tt.target)
+			ctx := context.Background()
+
+			venue.last = decimal.NewFromFloat(tt.price)
+			ea.managePosition(ctx, "BTC/USDT", venue.last)
+			if !mp.scaledOut || !mp.result.FilledQty.Equal(decimal.NewFromInt(1)) {
+				t.Fatalf("Expected half closed at the target, got scaled out %v with %s left", mp.scaledOut, mp.result.FilledQty)
+			}
+
+			// The runner's stop is at least breakeven
+			entry := decimal.NewFromInt(100)
+			if (tt.side == types.OrderSideBuy && mp.stop.LessThan(entry)) ||
+				(tt.side == types.OrderSideSell && mp.stop.GreaterThan(entry)) {
+				t.Errorf("Expected the runner's stop at or past entry, got %s", mp.stop)
+			}
+
+			// A pullback to entry stops out the runner, never scaling out again
+			venue.last = decimal.NewFromFloat(tt.pullback)
+			ea.managePosition(ctx, "BTC/USDT", venue.last)
+			if !mp.closed {
+				t.Errorf("Expected the runner stopped out at %s", venue.last)
+			}
+		})
+	}
+}
+
+func TestAttachLadderHandsExitsToLadder(t *testing.T) {
+	ea := newPaperAgent(t, &paperVenue{}, func(config *EnhancedAgentConfig) {
+		config.TPLadder = []execution.TPRung{
+			{Fraction: decimal.NewFromFloat(0.5), RMultiple: decimal.NewFromInt(1)},
+			{Fraction: decimal.NewFromFloat(0.5), TrailPercent: decimal.NewFromFloat(0.02)},
+		}
+	})
+	if ea.laddersEntries() {
+		t.Error("Expected paper entries to exit without the ladder")
+	}
+
+	var updates []*execution.TPLadder
+	ea.orderManager.OnLadderUpdate = func(ladder *execution.TPLadder) {
+		updates = append(updates, ladder)
+	}
+
+	order := &types.Order{
+		Symbol:   "BTC/USDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeMarket,
+		Quantity: decimal.NewFromInt(3),
+	}
+	result := &execution.ExecutionResult{
+		OrderID:   "12345",
+		Exchange:  "venue",
+		FilledQty: decimal.NewFromInt(2),
+		AvgPrice:  decimal.NewFromInt(100),
+		Timestamp: time.Now(),
+	}
+	if err := ea.attachLadder(order, result, decimal.NewFromInt(95)); err != nil {
+		t.Fatalf("attachLadder failed: %v", err)
+	}
+
+	ladder := ea.orderManager.GetTPLadder("BTC/USDT")
+	if ladder == nil || len(updates) != 1 {
+		t.Fatalf("Expected a ladder attached and announced, got %+v after %d updates", ladder, len(updates))
+	}
+	if !ladder.InitialQty.Equal(decimal.NewFromInt(2)) || !ladder.Stop.Equal(decimal.NewFromInt(95)) {
+		t.Errorf("Expected the ladder over the 2 filled at a 95 stop, got %s at %s", ladder.InitialQty, ladder.Stop)
+	}
+	if target := ladder.Rungs[0].Target; !target.Equal(decimal.NewFromInt(105)) {
+		t.Errorf("Expected the 1R target at 105, got %s", target)
+	}
+	if ea.managedPositionFor("BTC/USDT") != nil {
+		t.Error("Expected the agent to leave a laddered position's exits to the ladder")
+	}
+
+	// The stream reporting the entry's fill again doesn't grow the position
+	ea.orderManager.ApplyExchangeUpdate(&types.Order{ID: "12345", Symbol: "BTCUSDT"}, &execution.OrderFill{
+		Price:    decimal.NewFromInt(100),
+		Quantity: decimal.NewFromInt(2),
+	})
+	if position := ea.orderManager.GetPosition("BTC/USDT"); !position.Quantity.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("Expected 2 held after a repeated fill, got %s", position.Quantity)
+	}
+
+	if err := ea.attachLadder(order, result, decimal.NewFromInt(95)); err == nil {
+		t.Error("Expected an error attaching a second ladder")
+	}
+}
+
+func TestValidateExclusiveExits(t *testing.T) {
+	config := DefaultEnhancedAgentConfig()
+	config.TPLadder = []execution.TPRung{{Fraction: decimal.NewFromInt(1), RMultiple: decimal.NewFromInt(2)}}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected a ladder alone to be valid, got %v", err)
+	}
+
+	config.ScaleOutFraction = decimal.NewFromFloat(0.5)
+	if err := config.Validate(); err == nil {
+		t.Error("Expected a ladder with a scale-out fraction to be rejected")
+	}
+}
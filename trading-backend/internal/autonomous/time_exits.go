@@ -0,0 +1,201 @@
+package autonomous
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// TimeExitMode is what happens to a position held past its maximum holding
+// period.
+type TimeExitMode string
+
+// Time exit modes
+const (
+	TimeExitClose     TimeExitMode = "close"     // Close the position at market
+	TimeExitBreakeven TimeExitMode = "breakeven" // Move the stop to the entry; a losing position is closed
+)
+
+// timeExitCheckInterval is how often positions are checked against their
+// time exits between price updates.
+const timeExitCheckInterval = time.Minute
+
+// TimeExits exits positions by time rather than price, so a position that
+// reaches neither its stop nor its target is not held through a change of
+// regime, a costly funding settlement or the session's close.
+type TimeExits struct {
+	MaxHoldingPeriod   time.Duration `json:"maxHoldingPeriod"`   // 0 holds until the stop or target
+	Mode               TimeExitMode  `json:"mode"`               // Applied once MaxHoldingPeriod has elapsed
+	CloseBeforeFunding time.Duration `json:"closeBeforeFunding"` // Close positions this long before a funding settlement they would pay; 0 disables
+	CloseBeforeSession time.Duration `json:"closeBeforeSession"` // Close positions this long before the session ends at 00:00 UTC; 0 disables
+}
+
+// timeExitsEnabled reports whether any position may be exited by time.
+func (ea *EnhancedTradingAgent) timeExitsEnabled() bool {
+	exits := ea.config.TimeExits
+	if exits.MaxHoldingPeriod > 0 || exits.CloseBeforeFunding > 0 || exits.CloseBeforeSession > 0 {
+		return true
+	}
+
+	ea.mu.RLock()
+	defer ea.mu.RUnlock()
+
+	for _, strategy := range ea.registeredStrategies {
+		if strategy.MaxHoldingPeriod > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// holdingPeriodLocked returns the maximum holding period and time exit mode
+// for a new position: the active strategy's where it sets them, otherwise
+// the agent's. Callers hold ea.mu.
+func (ea *EnhancedTradingAgent) holdingPeriodLocked() (time.Duration, TimeExitMode) {
+	period, mode := ea.config.TimeExits.MaxHoldingPeriod, ea.config.TimeExits.Mode
+	if strategy, ok := ea.registeredStrategies[ea.activeStrategy]; ok {
+		if strategy.MaxHoldingPeriod > 0 {
+			period = strategy.MaxHoldingPeriod
+		}
+		if strategy.TimeExitMode != "" {
+			mode = strategy.TimeExitMode
+		}
+	}
+	if mode == "" {
+		mode = TimeExitClose
+	}
+	return period, mode
+}
+
+// checkTimeExits applies time exits to every managed position.
+func (ea *EnhancedTradingAgent) checkTimeExits(ctx context.Context, now time.Time) {
+	ea.mu.RLock()
+	positions := make([]*managedPosition, 0, len(ea.managed))
+	for _, mp := range ea.managed {
+		positions = append(positions, mp)
+	}
+	ea.mu.RUnlock()
+
+	for _, mp := range positions {
+		mp.mu.Lock()
+		ea.applyTimeExit(ctx, mp, now)
+		mp.mu.Unlock()
+	}
+}
+
+// applyTimeExit closes a position nearing a funding settlement it would pay
+// or the session's end, and closes, or moves to breakeven the stop of, one
+// held past its maximum holding period. It reports whether the position was
+// closed. Callers hold mp.mu.
+func (ea *EnhancedTradingAgent) applyTimeExit(ctx context.Context, mp *managedPosition, now time.Time) bool {
+	if mp.closed {
+		return true
+	}
+
+	position := ea.orderManager.GetPosition(mp.symbol)
+	if reason, near := ea.nearTimeBoundary(mp.symbol, mp.long, now); near {
+		return ea.closeManaged(ctx, mp, position, reason)
+	}
+
+	openedAt := mp.openedAt
+	if position != nil {
+		openedAt = position.OpenedAt
+	}
+	if mp.maxHolding <= 0 || now.Sub(openedAt) < mp.maxHolding {
+		return false
+	}
+
+	if mp.timeExit == TimeExitBreakeven {
+		if mp.timeStopped {
+			return false
+		}
+		entry := mp.result.AvgPrice
+		if position != nil {
+			entry = position.EntryPrice
+		}
+		// Without a price the position cannot be judged in profit yet
+		if !mp.price.IsPositive() {
+			return false
+		}
+		inProfit := mp.price.GreaterThan(entry)
+		if !mp.long {
+			inProfit = mp.price.LessThan(entry)
+		}
+		if inProfit {
+			ea.stopAtBreakeven(ctx, mp, entry)
+			return false
+		}
+	}
+
+	return ea.closeManaged(ctx, mp, position, "maximum holding period elapsed")
+}
+
+// stopAtBreakeven moves a position's stop to its entry once its holding
+// period has elapsed, unless the stop already protects the entry. Callers
+// hold mp.mu.
+func (ea *EnhancedTradingAgent) stopAtBreakeven(ctx context.Context, mp *managedPosition, entry decimal.Decimal) {
+	protected := !mp.stop.IsZero() && mp.stop.GreaterThanOrEqual(entry)
+	if !mp.long {
+		protected = !mp.stop.IsZero() && mp.stop.LessThanOrEqual(entry)
+	}
+	if !protected {
+		if err := ea.executor.ReplaceStopLoss(ctx, mp.result, entry); err != nil {
+			ea.logger.Warn("Failed to move stop to breakeven after holding period",
+				zap.String("symbol", mp.symbol),
+				zap.Error(err))
+			return
+		}
+		mp.stop = entry
+	}
+	mp.timeStopped = true
+
+	ea.logger.Info("Holding period elapsed, stop at breakeven",
+		zap.String("symbol", mp.symbol),
+		zap.String("stop", mp.stop.String()))
+}
+
+// nearTimeBoundary reports why a position on symbol, or an entry into one,
+// is too close to a funding settlement it would pay or to the session's end.
+func (ea *EnhancedTradingAgent) nearTimeBoundary(symbol string, long bool, now time.Time) (string, bool) {
+	exits := ea.config.TimeExits
+	if exits.CloseBeforeSession > 0 && sessionEnd(now).Sub(now) <= exits.CloseBeforeSession {
+		return "session ending", true
+	}
+	if exits.CloseBeforeFunding <= 0 {
+		return "", false
+	}
+
+	ea.mu.RLock()
+	source := ea.fundingSource
+	ea.mu.RUnlock()
+	if source == nil {
+		return "", false
+	}
+	rate, ok := source.FundingRate(symbol)
+	if !ok {
+		return "", false
+	}
+	until := rate.NextFundingTime.Sub(now)
+	if until <= 0 || until > exits.CloseBeforeFunding {
+		return "", false
+	}
+
+	// Longs pay a positive rate and shorts a negative one
+	paid := rate.PredictedRate
+	if !long {
+		paid = paid.Neg()
+	}
+	if !paid.IsPositive() {
+		return "", false
+	}
+	return "funding settlement due", true
+}
+
+// sessionEnd returns when the session containing t ends, at the next
+// 00:00 UTC.
+func sessionEnd(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+}
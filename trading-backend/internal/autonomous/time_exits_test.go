@@ -0,0 +1,226 @@
+package autonomous
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/internal/regime"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// fixedFunding reports the same funding rate for every symbol.
+type fixedFunding struct {
+	rate adapters.FundingRate
+}
+
+func (f fixedFunding) FundingRate(symbol string) (adapters.FundingRate, bool) {
+	return f.rate, true
+}
+
+// opened is a fixed entry time well clear of the session's end.
+var opened = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestTimeExitOnExpiry(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      TimeExitMode
+		side      types.OrderSide
+		price     float64
+		held      time.Duration
+		closed    bool
+		breakeven bool
+	}{
+		{name: "close before expiry", mode: TimeExitClose, side: types.OrderSideBuy, price: 105, held: 3*time.Hour + 59*time.Minute},
+		{name: "close at expiry", mode: TimeExitClose, side: types.OrderSideBuy, price: 105, held: 4 * time.Hour, closed: true},
+		{name: "breakeven long in profit", mode: TimeExitBreakeven, side: types.OrderSideBuy, price: 105, held: 5 * time.Hour, breakeven: true},
+		{name: "breakeven long at a loss", mode: TimeExitBreakeven, side: types.OrderSideBuy, price: 97, held: 5 * time.Hour, closed: true},
+		{name: "breakeven short in profit", mode: TimeExitBreakeven, side: types.OrderSideSell, price: 95, held: 5 * time.Hour, breakeven: true},
+		{name: "breakeven short at a loss", mode: TimeExitBreakeven, side: types.OrderSideSell, price: 103, held: 5 * time.Hour, closed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			venue := &paperVenue{last: decimal.NewFromFloat(tt.price)}
+			ea := newPaperAgent(t, venue, func(config *EnhancedAgentConfig) {
+				config.TimeExits = TimeExits{MaxHoldingPeriod: 4 * time.Hour, Mode: tt.mode}
+			})
+			stop := 95.0
+			if tt.side == types.OrderSideSell {
+				stop = 105
+			}
+			mp := openPosition(ea, tt.side, 1, 100, stop, true)
+			mp.openedAt = opened
+			mp.price = decimal.NewFromFloat(tt.price)
+
+			closed := ea.applyTimeExit(context.Background(), mp, opened.Add(tt.held))
+			if closed != tt.closed || mp.closed != tt.closed {
+				t.Errorf("Expected closed %v, got %v (position closed %v)", tt.closed, closed, mp.closed)
+			}
+			if mp.timeStopped != tt.breakeven {
+				t.Errorf("Expected stop moved to breakeven %v, got %v", tt.breakeven, mp.timeStopped)
+			}
+			if tt.breakeven && !mp.stop.Equal(decimal.NewFromInt(100)) {
+				t.Errorf("Expected the stop at the 100 entry, got %s", mp.stop)
+			}
+		})
+	}
+}
+
+func TestTimeExitBreakevenAppliesOnce(t *testing.T) {
+	venue := &paperVenue{last: decimal.NewFromInt(105)}
+	ea := newPaperAgent(t, venue, func(config *EnhancedAgentConfig) {
+		config.TimeExits = TimeExits{MaxHoldingPeriod: time.Hour, Mode: TimeExitBreakeven}
+	})
+	mp := openPosition(ea, types.OrderSideBuy, 1, 100, 95, true)
+	mp.openedAt = opened
+	mp.price = decimal.NewFromInt(105)
+	ctx := context.Background()
+
+	ea.applyTimeExit(ctx, mp, opened.Add(2*time.Hour))
+	if !mp.timeStopped {
+		t.Fatal("Expected the stop moved to breakeven")
+	}
+
+	// Once at breakeven the stop, not the clock, ends the trade
+	mp.price = decimal.NewFromInt(99)
+	if ea.applyTimeExit(ctx, mp, opened.Add(3*time.Hour)) || mp.closed {
+		t.Error("Expected no further time exit after the stop moved to breakeven")
+	}
+}
+
+func TestHoldingPeriodFollowsActiveStrategy(t *testing.T) {
+	ea := newPaperAgent(t, &paperVenue{}, func(config *EnhancedAgentConfig) {
+		config.TimeExits = TimeExits{MaxHoldingPeriod: 8 * time.Hour, Mode: TimeExitClose}
+	})
+	ea.RegisterStrategy(&StrategyConfig{
+		ID:               "mean-reversion",
+		PreferredRegimes: []regime.RegimeType{regime.RegimeMeanReverting},
+		MaxHoldingPeriod: time.Hour,
+		TimeExitMode:     TimeExitBreakeven,
+	})
+	ea.RegisterStrategy(&StrategyConfig{
+		ID:               "trend",
+		PreferredRegimes: []regime.RegimeType{regime.RegimeTrending},
+	})
+
+	tests := []struct {
+		strategy string
+		period   time.Duration
+		mode     TimeExitMode
+	}{
+		{strategy: "", period: 8 * time.Hour, mode: TimeExitClose},
+		{strategy: "mean-reversion", period: time.Hour, mode: TimeExitBreakeven},
+		// A strategy without its own limits keeps the agent's
+		{strategy: "trend", period: 8 * time.Hour, mode: TimeExitClose},
+	}
+
+	for _, tt := range tests {
+		ea.mu.Lock()
+		ea.activeStrategy = tt.strategy
+		ea.mu.Unlock()
+
+		mp := openPosition(ea, types.OrderSideBuy, 1, 100, 95, true)
+		if mp.maxHolding != tt.period || mp.timeExit != tt.mode {
+			t.Errorf("Strategy %q: expected %s %s, got %s %s", tt.strategy, tt.period, tt.mode, mp.maxHolding, mp.timeExit)
+		}
+	}
+
+	// A position keeps the limits it was entered under
+	ea.mu.Lock()
+	ea.activeStrategy = "mean-reversion"
+	ea.mu.Unlock()
+	mp := openPosition(ea, types.OrderSideBuy, 1, 100, 95, true)
+	mp.openedAt = opened
+	mp.price = decimal.NewFromInt(105)
+	if err := ea.SetActiveStrategy("trend"); err != nil {
+		t.Fatal(err)
+	}
+	ea.applyTimeExit(context.Background(), mp, opened.Add(2*time.Hour))
+	if !mp.timeStopped {
+		t.Error("Expected the mean-reversion hour to apply after the strategy changed")
+	}
+}
+
+func TestTimeBoundaryExits(t *testing.T) {
+	settlement := opened.Add(8 * time.Hour)
+	funding := func(rate float64) fixedFunding {
+		return fixedFunding{rate: adapters.FundingRate{
+			PredictedRate:   decimal.NewFromFloat(rate),
+			NextFundingTime: settlement,
+		}}
+	}
+
+	tests := []struct {
+		name    string
+		side    types.OrderSide
+		funding fixedFunding
+		now     time.Time
+		closed  bool
+	}{
+		{name: "long paying funding", side: types.OrderSideBuy, funding: funding(0.0005), now: settlement.Add(-10 * time.Minute), closed: true},
+		{name: "long paying funding early", side: types.OrderSideBuy, funding: funding(0.0005), now: settlement.Add(-time.Hour)},
+		{name: "short receiving funding", side: types.OrderSideSell, funding: funding(0.0005), now: settlement.Add(-10 * time.Minute)},
+		{name: "short paying funding", side: types.OrderSideSell, funding: funding(-0.0005), now: settlement.Add(-10 * time.Minute), closed: true},
+		{name: "session ending", side: types.OrderSideBuy, funding: funding(-0.0005), now: sessionEnd(opened).Add(-10 * time.Minute), closed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ea := newPaperAgent(t, &paperVenue{last: decimal.NewFromInt(100)}, func(config *EnhancedAgentConfig) {
+				config.TimeExits = TimeExits{CloseBeforeFunding: 30 * time.Minute, CloseBeforeSession: 15 * time.Minute}
+			})
+			ea.SetFundingSource(tt.funding)
+			mp := openPosition(ea, tt.side, 1, 100, 0, true)
+			mp.openedAt = opened
+
+			if closed := ea.applyTimeExit(context.Background(), mp, tt.now); closed != tt.closed {
+				t.Errorf("Expected closed %v, got %v", tt.closed, closed)
+			}
+		})
+	}
+}
+
+func TestTimeExitOrder(t *testing.T) {
+	ctx := context.Background()
+
+	// A boundary closes the position even where the holding period would
+	// only move the stop to breakeven
+	ea := newPaperAgent(t, &paperVenue{last: decimal.NewFromInt(105)}, func(config *EnhancedAgentConfig) {
+		config.TimeExits = TimeExits{MaxHoldingPeriod: time.Hour, Mode: TimeExitBreakeven, CloseBeforeSession: 15 * time.Minute}
+	})
+	mp := openPosition(ea, types.OrderSideBuy, 1, 100, 95, true)
+	mp.openedAt = opened
+	mp.price = decimal.NewFromInt(105)
+	if !ea.applyTimeExit(ctx, mp, sessionEnd(opened).Add(-5*time.Minute)) || mp.timeStopped {
+		t.Errorf("Expected the session end to close the position, got closed %v, breakeven %v", mp.closed, mp.timeStopped)
+	}
+
+	// Time exits run before the price-driven exits, so an expired position
+	// is closed rather than scaled out at its target
+	venue := &paperVenue{last: decimal.NewFromInt(110)}
+	ea = newPaperAgent(t, venue, func(config *EnhancedAgentConfig) {
+		config.TimeExits = TimeExits{MaxHoldingPeriod: time.Hour, Mode: TimeExitClose}
+		config.ScaleOutFraction = decimal.NewFromFloat(0.5)
+	})
+	mp = openPosition(ea, types.OrderSideBuy, 1, 100, 95, true)
+	mp.takeProfit = decimal.NewFromInt(110)
+	mp.openedAt = time.Now().Add(-2 * time.Hour)
+	ea.managePosition(ctx, "BTC/USDT", venue.last)
+	if !mp.closed || mp.scaledOut {
+		t.Errorf("Expected the time exit to close the position before a scale-out, got closed %v, scaled out %v", mp.closed, mp.scaledOut)
+	}
+
+	// An expired position in profit runs on with its stop at the entry
+	ea = newPaperAgent(t, &paperVenue{last: decimal.NewFromInt(105)}, func(config *EnhancedAgentConfig) {
+		config.TimeExits = TimeExits{MaxHoldingPeriod: time.Hour, Mode: TimeExitBreakeven}
+	})
+	mp = openPosition(ea, types.OrderSideBuy, 1, 100, 95, true)
+	mp.openedAt = time.Now().Add(-2 * time.Hour)
+	ea.managePosition(ctx, "BTC/USDT", decimal.NewFromInt(105))
+	if mp.closed || !mp.timeStopped || !mp.stop.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected the stop moved to the entry, got stop %s, closed %v", mp.stop, mp.closed)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/atlas-desktop/trading-backend/internal/backtester/events"
+	bus "github.com/atlas-desktop/trading-backend/internal/events"
 	"github.com/atlas-desktop/trading-backend/pkg/types"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -28,11 +29,18 @@ type Engine struct {
 	riskManager     *RiskManager
 	metricsCalc     *MetricsCalculator
 	
+	// Strategy
+	newStrategy     StrategyFactory
+	strategyBus     *bus.EventBus
+	pendingSignals  []*types.Signal
+	
 	// State
 	running         atomic.Bool
 	cancelled       atomic.Bool
 	currentTime     time.Time
 	eventsProcessed atomic.Uint64
+	lastPrices      map[string]decimal.Decimal
+	entryCommission map[string]decimal.Decimal
 	
 	// Results
 	trades          []*types.Trade
@@ -42,6 +50,10 @@ type Engine struct {
 	progressChan    chan *types.BacktestProgress
 }
 
+// StrategyFactory creates a fresh strategy for each run, so state from one
+// backtest or walk-forward window never leaks into the next
+type StrategyFactory func() Strategy
+
 // DataLoader interface for loading market data
 type DataLoader interface {
 	LoadOHLCV(ctx context.Context, symbol string, timeframe types.Timeframe, start, end time.Time) ([]*types.OHLCV, error)
@@ -50,11 +62,6 @@ type DataLoader interface {
 	GetDataRange(symbol string) (start, end time.Time, err error)
 }
 
-// SlippageModel interface for slippage calculation
-type SlippageModel interface {
-	Calculate(order *types.Order, marketData *events.MarketDataEvent) decimal.Decimal
-}
-
 // NewEngine creates a new backtesting engine
 func NewEngine(logger *zap.Logger, dataLoader DataLoader, slippageModel SlippageModel) *Engine {
 	return &Engine{
@@ -68,6 +75,14 @@ func NewEngine(logger *zap.Logger, dataLoader DataLoader, slippageModel Slippage
 	}
 }
 
+// SetStrategyFactory sets the strategy that generates signals. Without one the
+// engine replays the data but never trades.
+func (e *Engine) SetStrategyFactory(newStrategy StrategyFactory) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.newStrategy = newStrategy
+}
+
 // Run executes a backtest with the given configuration
 func (e *Engine) Run(ctx context.Context, config *types.BacktestConfig) (*types.BacktestResult, error) {
 	e.mu.Lock()
@@ -92,12 +107,34 @@ func (e *Engine) Run(ctx context.Context, config *types.BacktestConfig) (*types.
 	e.riskManager = NewRiskManager(e.logger, &config.RiskLimits)
 	e.metricsCalc = NewMetricsCalculator()
 
+	slippageModel := e.slippageModel
+	if slippageModel == nil {
+		slippageModel = CreateSlippageModel(config.Slippage)
+	}
+	e.orderManager.SetSlippageModel(slippageModel)
+	if config.Fees != nil {
+		e.orderManager.SetFeeSchedule(*config.Fees)
+	}
+	
 	// Reset state
 	e.trades = e.trades[:0]
 	e.equityCurve = e.equityCurve[:0]
+	e.pendingSignals = e.pendingSignals[:0]
+	e.lastPrices = make(map[string]decimal.Decimal)
+	e.entryCommission = make(map[string]decimal.Decimal)
 	e.eventsProcessed.Store(0)
 	e.eventQueue.Clear()
 
+	// Bars reach the strategy over an event bus, as they do in live trading
+	e.strategyBus = nil
+	if e.newStrategy != nil {
+		e.strategyBus = bus.NewEventBus(e.logger, bus.EventBusConfig{NumWorkers: 1, BufferSize: 1})
+		defer e.strategyBus.Close()
+		SubscribeStrategy(e.strategyBus, e.newStrategy(), func(signal *types.Signal) {
+			e.pendingSignals = append(e.pendingSignals, signal)
+		})
+	}
+	
 	// Load market data and create events
 	totalEvents, err := e.loadMarketData(ctx, config)
 	if err != nil {
@@ -140,6 +177,13 @@ func (e *Engine) Run(ctx context.Context, config *types.BacktestConfig) (*types.
 		}
 	}
 
+	// Fills from the final bar settle after its equity point was recorded
+	if n := len(e.equityCurve); n > 0 {
+		e.equityCurve[n-1].Equity = e.portfolio.GetEquity()
+		e.equityCurve[n-1].Cash = e.portfolio.GetCash()
+		e.equityCurve[n-1].Drawdown = e.portfolio.GetDrawdown()
+	}
+	
 	// Calculate final metrics
 	metrics := e.metricsCalc.Calculate(e.trades, e.equityCurve, e.config.InitialCapital)
 	riskMetrics := e.metricsCalc.CalculateRiskMetrics(e.equityCurve)
@@ -267,11 +311,11 @@ func (e *Engine) handleMarketData(event *events.MarketDataEvent) error {
 	// Update portfolio with current prices
 	if event.OHLCV != nil {
 		e.portfolio.UpdatePrice(event.Symbol, event.OHLCV.Close)
+		e.lastPrices[event.Symbol] = event.OHLCV.Close
 	}
 
 	// Generate signals from strategy
-	signal := e.generateSignal(event)
-	if signal != nil {
+	for _, signal := range e.generateSignals(event) {
 		signalEvent := &events.SignalEvent{
 			BaseEvent: events.BaseEvent{
 				Type:      events.EventTypeSignal,
@@ -315,8 +359,25 @@ func (e *Engine) handleSignal(event *events.SignalEvent) error {
 		return nil
 	}
 
-	// Calculate position size
-	positionSize := e.calculatePositionSize(signal)
+	// Positions are long-only and all-or-nothing: a buy opens one, a sell
+	// closes it, and nothing is sent while an order for the symbol is pending
+	if e.hasPendingOrder(signal.Symbol) {
+		return nil
+	}
+	
+	var positionSize decimal.Decimal
+	position := e.portfolio.GetPosition(signal.Symbol)
+	if signal.Side == types.OrderSideSell {
+		if position == nil {
+			return nil
+		}
+		positionSize = position.Quantity
+	} else {
+		if position != nil {
+			return nil
+		}
+		positionSize = e.calculatePositionSize(signal)
+	}
 	if positionSize.IsZero() {
 		return nil
 	}
@@ -367,8 +428,16 @@ func (e *Engine) handleFill(event *events.FillEvent) error {
 	// Update portfolio
 	if event.Side == types.OrderSideBuy {
 		e.portfolio.Buy(event.Symbol, event.Quantity, event.Price, event.Commission)
+		e.entryCommission[event.Symbol] = e.entryCommission[event.Symbol].Add(event.Commission)
 	} else {
+		if e.portfolio.GetPosition(event.Symbol) == nil {
+			return nil
+		}
+		
+		// Trade PnL covers the round trip, including the entry commission
 		pnl := e.portfolio.Sell(event.Symbol, event.Quantity, event.Price, event.Commission)
+		pnl = pnl.Sub(e.entryCommission[event.Symbol])
+		delete(e.entryCommission, event.Symbol)
 		
 		// Record trade
 		trade := &types.Trade{
@@ -410,11 +479,36 @@ func (e *Engine) handleKillSwitch(event *events.KillSwitchEvent) error {
 	return nil
 }
 
-// generateSignal generates trading signals from market data
-func (e *Engine) generateSignal(event *events.MarketDataEvent) *types.Signal {
-	// This would be replaced with actual strategy logic
-	// For now, return nil (no signal)
-	return nil
+// generateSignals publishes a bar to the strategy and returns the signals it
+// raised, stamped with the bar's symbol and time where the strategy left them
+// blank
+func (e *Engine) generateSignals(event *events.MarketDataEvent) []*types.Signal {
+	if e.strategyBus == nil || event.OHLCV == nil {
+		return nil
+	}
+	
+	e.pendingSignals = e.pendingSignals[:0]
+	e.strategyBus.PublishSync(newBarEvent(event.Symbol, event.OHLCV))
+	
+	for _, signal := range e.pendingSignals {
+		if signal.Symbol == "" {
+			signal.Symbol = event.Symbol
+		}
+		if signal.CreatedAt.IsZero() {
+			signal.CreatedAt = event.Timestamp
+		}
+	}
+	return e.pendingSignals
+}
+
+// hasPendingOrder reports whether an order for the symbol is awaiting a fill
+func (e *Engine) hasPendingOrder(symbol string) bool {
+	for _, order := range e.orderManager.GetPendingOrders() {
+		if order.Symbol == symbol {
+			return true
+		}
+	}
+	return false
 }
 
 // calculatePositionSize calculates position size for a signal
@@ -424,11 +518,17 @@ func (e *Engine) calculatePositionSize(signal *types.Signal) decimal.Decimal {
 	
 	// Simple fixed fractional sizing
 	positionValue := equity.Mul(maxPositionPct)
-	if signal.Price.IsZero() {
+	
+	// Market signals carry no price; size them off the last close
+	price := signal.Price
+	if price.IsZero() {
+		price = e.lastPrices[signal.Symbol]
+	}
+	if price.IsZero() {
 		return decimal.Zero
 	}
 	
-	return positionValue.Div(signal.Price)
+	return positionValue.Div(price)
 }
 
 // sendProgress sends a progress update
@@ -472,5 +572,6 @@ func (e *Engine) runMonteCarlo(config types.MonteCarloConfig) *types.MonteCarloR
 // runWalkForward runs walk-forward analysis
 func (e *Engine) runWalkForward(ctx context.Context, config *types.BacktestConfig) (*types.WalkForwardResult, error) {
 	wf := NewWalkForwardAnalyzer(e.logger, e.dataLoader, e.slippageModel)
+	wf.SetStrategyFactory(e.newStrategy)
 	return wf.Run(ctx, config)
 }
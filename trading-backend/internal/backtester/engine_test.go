@@ -3,11 +3,14 @@ package backtester_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/atlas-desktop/trading-backend/internal/backtester"
+	btevents "github.com/atlas-desktop/trading-backend/internal/backtester/events"
 	"github.com/atlas-desktop/trading-backend/internal/data"
+	"github.com/atlas-desktop/trading-backend/internal/events"
 	"github.com/atlas-desktop/trading-backend/pkg/types"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
@@ -148,6 +151,41 @@ func TestSlippageModels(t *testing.T) {
 	}
 }
 
+func TestOrderManagerChargesMakerAndTakerFees(t *testing.T) {
+	om := backtester.NewOrderManager(zap.NewNop(), decimal.NewFromFloat(0.001))
+	om.SetSlippageModel(backtester.NewFixedSlippage(decimal.Zero))
+	om.SetFeeSchedule(types.FeeSchedule{
+		MakerBps: decimal.NewFromInt(2),
+		TakerBps: decimal.NewFromInt(5),
+	})
+	
+	bar := func(price int64) *btevents.MarketDataEvent {
+		return &btevents.MarketDataEvent{
+			Symbol: "BTCUSDT",
+			OHLCV:  &types.OHLCV{Close: decimal.NewFromInt(price), Volume: decimal.NewFromInt(1000)},
+		}
+	}
+	om.CheckFills(bar(100))
+	
+	// The limit rests below the last price; the market order takes
+	om.Submit(&types.Order{ID: "maker", Symbol: "BTCUSDT", Side: types.OrderSideBuy,
+		Type: types.OrderTypeLimit, Price: decimal.NewFromInt(99), Quantity: decimal.NewFromInt(10)})
+	om.Submit(&types.Order{ID: "taker", Symbol: "BTCUSDT", Side: types.OrderSideBuy,
+		Type: types.OrderTypeMarket, Quantity: decimal.NewFromInt(10)})
+	
+	commissions := make(map[string]decimal.Decimal)
+	for _, fill := range om.CheckFills(bar(98)) {
+		commissions[fill.OrderID] = fill.Commission
+	}
+	
+	if got, want := commissions["maker"], decimal.NewFromFloat(0.198); !got.Equal(want) {
+		t.Errorf("Expected maker fee %s, got %s", want, got)
+	}
+	if got, want := commissions["taker"], decimal.NewFromFloat(0.49); !got.Equal(want) {
+		t.Errorf("Expected taker fee %s, got %s", want, got)
+	}
+}
+
 func TestMetricsCalculator(t *testing.T) {
 	calc := backtester.NewMetricsCalculator()
 	
@@ -233,3 +271,244 @@ func TestMonteCarloSimulator(t *testing.T) {
 	t.Logf("Monte Carlo: P5=%s, Median=%s, P95=%s, Ruin=%s",
 		result.P5Return, result.MedianReturn, result.P95Return, result.ProbabilityRuin)
 }
+
+// barLoader serves a fixed set of bars for every symbol
+type barLoader struct {
+	bars []*types.OHLCV
+}
+
+func (l *barLoader) LoadOHLCV(ctx context.Context, symbol string, timeframe types.Timeframe, start, end time.Time) ([]*types.OHLCV, error) {
+	return l.bars, nil
+}
+
+func (l *barLoader) LoadTicks(ctx context.Context, symbol string, start, end time.Time) ([]*types.Tick, error) {
+	return nil, nil
+}
+
+func (l *barLoader) GetAvailableSymbols() []string { return []string{"SOL/USDT"} }
+
+func (l *barLoader) GetDataRange(symbol string) (time.Time, time.Time, error) {
+	return time.Time{}, time.Time{}, nil
+}
+
+// cycleStrategy buys on every fourth bar and sells two bars later
+type cycleStrategy struct {
+	bars int
+}
+
+func (s *cycleStrategy) Name() string { return "cycle" }
+
+func (s *cycleStrategy) OnBar(bar *events.BarEvent) (*types.Signal, error) {
+	defer func() { s.bars++ }()
+	
+	switch s.bars % 4 {
+	case 0:
+		return &types.Signal{Side: types.OrderSideBuy, Type: types.SignalTypeEntry}, nil
+	case 2:
+		return &types.Signal{Side: types.OrderSideSell, Type: types.SignalTypeExit}, nil
+	}
+	return nil, nil
+}
+
+func TestEngineRunsStrategy(t *testing.T) {
+	// Daily bars closing at 100, 101, ..., 107
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := make([]*types.OHLCV, 8)
+	for i := range bars {
+		price := decimal.NewFromInt(int64(100 + i))
+		bars[i] = &types.OHLCV{
+			Timestamp: start.AddDate(0, 0, i),
+			Open:      price,
+			High:      price,
+			Low:       price,
+			Close:     price,
+			Volume:    decimal.NewFromInt(1000000),
+		}
+	}
+	
+	engine := backtester.NewEngine(zap.NewNop(), &barLoader{bars: bars}, backtester.NewFixedSlippage(decimal.Zero))
+	
+	var strategy *cycleStrategy
+	engine.SetStrategyFactory(func() backtester.Strategy {
+		strategy = &cycleStrategy{}
+		return strategy
+	})
+	
+	config := &types.BacktestConfig{
+		ID:             "strategy-backtest",
+		Symbols:        []string{"SOL/USDT"},
+		StartDate:      start,
+		EndDate:        start.AddDate(0, 0, 8),
+		Timeframe:      types.Timeframe1d,
+		InitialCapital: decimal.NewFromInt(10000),
+		Commission:     decimal.NewFromFloat(0.001),
+		RiskLimits: types.RiskLimits{
+			MaxPositionSize:  decimal.NewFromFloat(0.5),
+			MaxDrawdown:      decimal.NewFromFloat(0.2),
+			MaxDailyLoss:     decimal.NewFromFloat(0.05),
+			MaxOpenPositions: 5,
+		},
+	}
+	
+	result, err := engine.Run(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Backtest failed: %v", err)
+	}
+	
+	if strategy.bars != 8 {
+		t.Errorf("Strategy saw %d bars, want 8", strategy.bars)
+	}
+	if len(result.Trades) != 2 {
+		t.Fatalf("Expected 2 round trips, got %d", len(result.Trades))
+	}
+	
+	// The first signal sizes off the 100 close and fills a bar later at 101;
+	// the exit fills at 103. PnL is net of both commissions:
+	// 50 * (103 - 101) - 50 * 101 * 0.001 - 50 * 103 * 0.001 = 89.8
+	first := result.Trades[0]
+	if !first.Quantity.Equal(decimal.NewFromInt(50)) || !first.Price.Equal(decimal.NewFromInt(103)) {
+		t.Errorf("First trade sold %s at %s, want 50 at 103", first.Quantity, first.Price)
+	}
+	if !first.ExecutedAt.Equal(bars[3].Timestamp) {
+		t.Errorf("First trade executed at %s, want %s", first.ExecutedAt, bars[3].Timestamp)
+	}
+	if !first.PnL.Equal(decimal.NewFromFloat(89.8)) {
+		t.Errorf("First trade PnL incorrect: expected 89.8, got %s", first.PnL)
+	}
+	
+	// Flat at the end, so equity is the starting capital plus realized PnL
+	realized := result.Trades[0].PnL.Add(result.Trades[1].PnL)
+	finalEquity := result.EquityCurve[len(result.EquityCurve)-1].Equity
+	if finalEquity.Sub(decimal.NewFromInt(10000).Add(realized)).Abs().GreaterThan(decimal.NewFromFloat(1e-6)) {
+		t.Errorf("Final equity %s does not match realized PnL %s", finalEquity, realized)
+	}
+	
+	if result.Metrics.TotalTrades != 2 || !result.Metrics.WinRate.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Metrics incorrect: %d trades, win rate %s", result.Metrics.TotalTrades, result.Metrics.WinRate)
+	}
+	
+	summary := backtester.Summarize(result)
+	if summary.TradeCount != 2 || len(summary.TradePnLs) != 2 || len(summary.TradeReturns) != 2 {
+		t.Fatalf("Summary has %d trades, %d PnLs, %d returns", summary.TradeCount, len(summary.TradePnLs), len(summary.TradeReturns))
+	}
+	
+	// Compounding the trade returns reproduces the total return
+	growth := 1.0
+	for _, r := range summary.TradeReturns {
+		growth *= 1 + r
+	}
+	if diff := growth - 1 - summary.TotalReturn; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Compounded trade returns %f do not match total return %f", growth-1, summary.TotalReturn)
+	}
+}
+
+// holdStrategy buys on the first bar and never sells
+type holdStrategy struct {
+	bought bool
+}
+
+func (s *holdStrategy) Name() string { return "hold" }
+
+func (s *holdStrategy) OnBar(bar *events.BarEvent) (*types.Signal, error) {
+	if s.bought {
+		return nil, nil
+	}
+	s.bought = true
+	return &types.Signal{Side: types.OrderSideBuy, Type: types.SignalTypeEntry}, nil
+}
+
+func TestEngineReportsReturnWithoutClosedTrades(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := []*types.OHLCV{
+		{Timestamp: start, Close: decimal.NewFromInt(100)},
+		{Timestamp: start.AddDate(0, 0, 1), Close: decimal.NewFromInt(100)},
+		{Timestamp: start.AddDate(0, 0, 2), Close: decimal.NewFromInt(120)},
+	}
+	
+	engine := backtester.NewEngine(zap.NewNop(), &barLoader{bars: bars}, backtester.NewFixedSlippage(decimal.Zero))
+	engine.SetStrategyFactory(func() backtester.Strategy { return &holdStrategy{} })
+	
+	result, err := engine.Run(context.Background(), &types.BacktestConfig{
+		ID:             "open-position",
+		Symbols:        []string{"SOL/USDT"},
+		InitialCapital: decimal.NewFromInt(10000),
+		RiskLimits: types.RiskLimits{
+			MaxPositionSize:  decimal.NewFromFloat(0.5),
+			MaxDrawdown:      decimal.NewFromFloat(0.2),
+			MaxDailyLoss:     decimal.NewFromFloat(0.05),
+			MaxOpenPositions: 5,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Backtest failed: %v", err)
+	}
+	
+	// 50 units bought at 100 are marked at 120
+	if result.Metrics.TotalTrades != 0 {
+		t.Errorf("Expected no closed trades, got %d", result.Metrics.TotalTrades)
+	}
+	if !result.Metrics.TotalReturn.Equal(decimal.NewFromFloat(0.1)) {
+		t.Errorf("Total return incorrect: expected 0.1, got %s", result.Metrics.TotalReturn)
+	}
+}
+
+// windowLoader serves bars and records the window it was asked for
+type windowLoader struct {
+	barLoader
+	start, end time.Time
+}
+
+func (l *windowLoader) LoadOHLCV(ctx context.Context, symbol string, timeframe types.Timeframe, start, end time.Time) ([]*types.OHLCV, error) {
+	l.start, l.end = start, end
+	return l.bars, nil
+}
+
+func TestEvaluationRunnerBacktestsTrailingWindow(t *testing.T) {
+	start := time.Now().Add(-16 * time.Hour)
+	bars := make([]*types.OHLCV, 16)
+	for i := range bars {
+		price := decimal.NewFromInt(int64(100 + i))
+		bars[i] = &types.OHLCV{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Open:      price,
+			High:      price,
+			Low:       price,
+			Close:     price,
+			Volume:    decimal.NewFromInt(1000000),
+		}
+	}
+	loader := &windowLoader{barLoader: barLoader{bars: bars}}
+	
+	var gotParams map[string]float64
+	newStrategy := func(name string, params map[string]float64) (backtester.Strategy, error) {
+		if name != "cycle" {
+			return nil, fmt.Errorf("unknown strategy: %s", name)
+		}
+		gotParams = params
+		return &cycleStrategy{}, nil
+	}
+	
+	config := backtester.DefaultEvaluationConfig()
+	config.Lookback = 24 * time.Hour
+	run := backtester.NewEvaluationRunner(zap.NewNop(), loader, config, newStrategy)
+	
+	result, err := run(context.Background(), "cycle", map[string]float64{"period": 4})
+	if err != nil {
+		t.Fatalf("Evaluation backtest failed: %v", err)
+	}
+	
+	// Four buy/sell cycles over 16 bars
+	if len(result.Trades) != 4 {
+		t.Errorf("Expected 4 round trips, got %d", len(result.Trades))
+	}
+	if gotParams["period"] != 4 {
+		t.Errorf("Expected the strategy configured with its params, got %v", gotParams)
+	}
+	if window := loader.end.Sub(loader.start); window != 24*time.Hour || time.Since(loader.end) > time.Minute {
+		t.Errorf("Expected the trailing 24h window, got %s to %s", loader.start, loader.end)
+	}
+	
+	if _, err := run(context.Background(), "unknown", nil); err == nil {
+		t.Error("Expected an error for an unknown strategy")
+	}
+}
@@ -0,0 +1,83 @@
+// Package backtester provides backtests for periodic strategy evaluation.
+package backtester
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// EvaluationConfig is the backtest strategies are re-scored on: a trailing
+// window of one symbol's bars
+type EvaluationConfig struct {
+	Symbol         string               `json:"symbol"`
+	Timeframe      types.Timeframe      `json:"timeframe"`
+	Lookback       time.Duration        `json:"lookback"`
+	InitialCapital decimal.Decimal      `json:"initialCapital"`
+	Commission     decimal.Decimal      `json:"commission"`
+	Slippage       types.SlippageConfig `json:"slippage"`
+	RiskLimits     types.RiskLimits     `json:"riskLimits"`
+}
+
+// DefaultEvaluationConfig returns 90 days of hourly BTC/USDT on a 10k account
+// with 0.1% commission
+func DefaultEvaluationConfig() EvaluationConfig {
+	return EvaluationConfig{
+		Symbol:         "BTC/USDT",
+		Timeframe:      types.Timeframe1h,
+		Lookback:       90 * 24 * time.Hour,
+		InitialCapital: decimal.NewFromInt(10000),
+		Commission:     decimal.NewFromFloat(0.001),
+		RiskLimits: types.RiskLimits{
+			MaxPositionSize:  decimal.NewFromFloat(0.5),
+			MaxDrawdown:      decimal.NewFromFloat(0.3),
+			MaxDailyLoss:     decimal.NewFromFloat(0.1),
+			MaxOpenPositions: 1,
+		},
+	}
+}
+
+// NamedStrategyFactory creates the strategy registered under name,
+// configured with params
+type NamedStrategyFactory func(name string, params map[string]float64) (Strategy, error)
+
+// NewEvaluationRunner returns a runner that backtests a strategy, looked up
+// by its ID, over the config's trailing window ending now. Each call runs a
+// fresh engine, so evaluations may run concurrently.
+func NewEvaluationRunner(
+	logger *zap.Logger,
+	dataLoader DataLoader,
+	config EvaluationConfig,
+	newStrategy NamedStrategyFactory,
+) func(ctx context.Context, strategyID string, params map[string]float64) (*types.BacktestResult, error) {
+	return func(ctx context.Context, strategyID string, params map[string]float64) (*types.BacktestResult, error) {
+		// Fail on unknown strategies and bad params before loading any data
+		if _, err := newStrategy(strategyID, params); err != nil {
+			return nil, fmt.Errorf("failed to create strategy %s: %w", strategyID, err)
+		}
+
+		engine := NewEngine(logger, dataLoader, CreateSlippageModel(config.Slippage))
+		engine.SetStrategyFactory(func() Strategy {
+			strategy, _ := newStrategy(strategyID, params)
+			return strategy
+		})
+
+		end := time.Now()
+		return engine.Run(ctx, &types.BacktestConfig{
+			ID:             fmt.Sprintf("evaluation-%s-%d", strategyID, end.UnixNano()),
+			Strategy:       types.StrategyConfig{Name: strategyID},
+			Symbols:        []string{config.Symbol},
+			StartDate:      end.Add(-config.Lookback),
+			EndDate:        end,
+			Timeframe:      config.Timeframe,
+			InitialCapital: config.InitialCapital,
+			Commission:     config.Commission,
+			Slippage:       config.Slippage,
+			RiskLimits:     config.RiskLimits,
+		})
+	}
+}
@@ -27,7 +27,9 @@ func (mc *MetricsCalculator) Calculate(
 	equityCurve []types.EquityCurvePoint,
 	initialCapital decimal.Decimal,
 ) *types.PerformanceMetrics {
-	if len(trades) == 0 || len(equityCurve) == 0 {
+	// Returns and drawdown come from the equity curve, so a run without
+	// trades still gets them
+	if len(equityCurve) == 0 {
 		return &types.PerformanceMetrics{}
 	}
 	
@@ -189,17 +191,20 @@ func (mc *MetricsCalculator) CalculateRiskMetrics(equityCurve []types.EquityCurv
 	return metrics
 }
 
-// calculateDailyReturns calculates daily returns from equity curve
+// calculateDailyReturns calculates daily returns from equity curve. Intraday
+// points are resampled to each UTC day's closing equity first, so the sqrt(252)
+// annualization holds whatever the bar timeframe.
 func (mc *MetricsCalculator) calculateDailyReturns(equityCurve []types.EquityCurvePoint) []float64 {
-	if len(equityCurve) < 2 {
+	closes := mc.dailyCloses(equityCurve)
+	if len(closes) < 2 {
 		return nil
 	}
 	
-	returns := make([]float64, 0, len(equityCurve)-1)
+	returns := make([]float64, 0, len(closes)-1)
 	
-	for i := 1; i < len(equityCurve); i++ {
-		prevEquity := equityCurve[i-1].Equity
-		currEquity := equityCurve[i].Equity
+	for i := 1; i < len(closes); i++ {
+		prevEquity := closes[i-1]
+		currEquity := closes[i]
 		
 		if prevEquity.IsZero() {
 			continue
@@ -213,6 +218,24 @@ func (mc *MetricsCalculator) calculateDailyReturns(equityCurve []types.EquityCur
 	return returns
 }
 
+// dailyCloses returns the last equity of each UTC day, in curve order
+func (mc *MetricsCalculator) dailyCloses(equityCurve []types.EquityCurvePoint) []decimal.Decimal {
+	closes := make([]decimal.Decimal, 0)
+	var lastDay time.Time
+	
+	for _, point := range equityCurve {
+		day := point.Timestamp.UTC().Truncate(24 * time.Hour)
+		if len(closes) > 0 && day.Equal(lastDay) {
+			closes[len(closes)-1] = point.Equity
+			continue
+		}
+		closes = append(closes, point.Equity)
+		lastDay = day
+	}
+	
+	return closes
+}
+
 // calculateMaxDrawdown calculates maximum drawdown
 func (mc *MetricsCalculator) calculateMaxDrawdown(equityCurve []types.EquityCurvePoint) (decimal.Decimal, time.Time) {
 	if len(equityCurve) == 0 {
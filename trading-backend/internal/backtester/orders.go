@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/atlas-desktop/trading-backend/internal/backtester/events"
+	"github.com/atlas-desktop/trading-backend/internal/fees"
 	"github.com/atlas-desktop/trading-backend/pkg/types"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
@@ -18,6 +19,9 @@ type OrderManager struct {
 	pendingOrders map[string]*types.Order
 	filledOrders  map[string]*types.Order
 	commission    decimal.Decimal
+	feeSchedule   *types.FeeSchedule
+	liquidity     map[string]fees.Liquidity // Order ID -> liquidity at submission
+	slippageModel SlippageModel
 	lastPrices    map[string]decimal.Decimal
 }
 
@@ -28,10 +32,28 @@ func NewOrderManager(logger *zap.Logger, commission decimal.Decimal) *OrderManag
 		pendingOrders: make(map[string]*types.Order),
 		filledOrders:  make(map[string]*types.Order),
 		commission:    commission,
+		liquidity:     make(map[string]fees.Liquidity),
 		lastPrices:    make(map[string]decimal.Decimal),
 	}
 }
 
+// SetSlippageModel sets the model used to slip market and stop fills. Without
+// one a simple volume-participation estimate is used.
+func (om *OrderManager) SetSlippageModel(model SlippageModel) {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	om.slippageModel = model
+}
+
+// SetFeeSchedule charges maker or taker fees from schedule in place of the
+// flat commission rate. Limit orders that would not cross the last price
+// when submitted pay the maker rate.
+func (om *OrderManager) SetFeeSchedule(schedule types.FeeSchedule) {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	om.feeSchedule = &schedule
+}
+
 // Submit adds a new order to the pending queue
 func (om *OrderManager) Submit(order *types.Order) {
 	om.mu.Lock()
@@ -39,6 +61,7 @@ func (om *OrderManager) Submit(order *types.Order) {
 	
 	order.Status = types.OrderStatusPending
 	om.pendingOrders[order.ID] = order
+	om.liquidity[order.ID] = fees.LiquidityOf(order, om.lastPrices[order.Symbol])
 	
 	om.logger.Debug("Order submitted",
 		zap.String("id", order.ID),
@@ -62,6 +85,7 @@ func (om *OrderManager) Cancel(orderID string) bool {
 	order.Status = types.OrderStatusCancelled
 	order.UpdatedAt = time.Now()
 	delete(om.pendingOrders, orderID)
+	delete(om.liquidity, orderID)
 	
 	om.logger.Debug("Order cancelled", zap.String("id", orderID))
 	return true
@@ -99,13 +123,17 @@ func (om *OrderManager) CheckFills(marketData *events.MarketDataEvent) []*events
 		
 		// Calculate commission
 		commission := order.Quantity.Mul(fillPrice).Mul(om.commission)
+		if om.feeSchedule != nil {
+			commission = fees.Calculate(*om.feeSchedule, om.liquidity[id], order.Quantity, fillPrice).Quote
+		}
 		
-		// Create fill event
+		// Create fill event; it shares the bar's priority so it settles
+		// before any signal raised on the same bar is sized
 		fill := &events.FillEvent{
 			BaseEvent: events.BaseEvent{
 				Type:      events.EventTypeFill,
 				Timestamp: marketData.Timestamp,
-				Priority:  4,
+				Priority:  1,
 			},
 			OrderID:    order.ID,
 			Symbol:     order.Symbol,
@@ -129,6 +157,7 @@ func (om *OrderManager) CheckFills(marketData *events.MarketDataEvent) []*events
 		// Move to filled orders
 		om.filledOrders[id] = order
 		delete(om.pendingOrders, id)
+		delete(om.liquidity, id)
 		
 		om.logger.Debug("Order filled",
 			zap.String("id", order.ID),
@@ -194,6 +223,10 @@ func (om *OrderManager) checkOrderFill(order *types.Order, marketData *events.Ma
 
 // calculateSlippage calculates slippage for an order
 func (om *OrderManager) calculateSlippage(order *types.Order, marketData *events.MarketDataEvent) decimal.Decimal {
+	if om.slippageModel != nil {
+		return om.slippageModel.Calculate(order, marketData)
+	}
+	
 	// Simple volume-based slippage model
 	// In production, this would use order book depth
 	baseSlippage := decimal.NewFromFloat(0.001) // 0.1% base slippage
@@ -272,6 +305,7 @@ func (om *OrderManager) CancelAll() int {
 		order.Status = types.OrderStatusCancelled
 		order.UpdatedAt = time.Now()
 		delete(om.pendingOrders, id)
+		delete(om.liquidity, id)
 	}
 	
 	return count
@@ -236,7 +236,7 @@ func (p *Portfolio) ToTypes() *types.Portfolio {
 		unrealizedPnL := pos.Quantity.Mul(pos.CurrentPrice.Sub(pos.AvgPrice))
 		positions[symbol] = &types.Position{
 			Symbol:        symbol,
-			Side:          types.OrderSideBuy,
+			Side:          types.PositionSideLong,
 			Quantity:      pos.Quantity,
 			EntryPrice:    pos.AvgPrice,
 			CurrentPrice:  pos.CurrentPrice,
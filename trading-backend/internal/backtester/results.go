@@ -0,0 +1,63 @@
+// Package backtester provides plain-number summaries of backtest results.
+package backtester
+
+import (
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/montecarlo"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+)
+
+// BacktestResults summarizes a backtest as plain numbers for consumers that
+// work in float64, such as the orchestrator and the Monte Carlo simulator
+type BacktestResults struct {
+	TotalReturn  float64 `json:"total_return"`
+	SharpeRatio  float64 `json:"sharpe_ratio"`
+	MaxDrawdown  float64 `json:"max_drawdown"`
+	WinRate      float64 `json:"win_rate"`
+	ProfitFactor float64 `json:"profit_factor"`
+	TradeCount   int     `json:"trade_count"`
+
+	// TradePnLs holds each closed trade's round-trip PnL in account currency
+	TradePnLs []float64 `json:"trade_pnls"`
+	// TradeReturns holds the same trades as montecarlo.ReturnsFromPnLs returns
+	TradeReturns []float64 `json:"trade_returns"`
+
+	EquityCurve []float64   `json:"equity_curve"`
+	Timestamps  []time.Time `json:"timestamps"`
+}
+
+// Summarize converts an engine result into BacktestResults
+func Summarize(result *types.BacktestResult) *BacktestResults {
+	summary := &BacktestResults{
+		TradePnLs:   make([]float64, len(result.Trades)),
+		EquityCurve: make([]float64, len(result.EquityCurve)),
+		Timestamps:  make([]time.Time, len(result.EquityCurve)),
+	}
+
+	if m := result.Metrics; m != nil {
+		summary.TotalReturn = m.TotalReturn.InexactFloat64()
+		summary.SharpeRatio = m.SharpeRatio.InexactFloat64()
+		summary.MaxDrawdown = m.MaxDrawdown.InexactFloat64()
+		summary.WinRate = m.WinRate.InexactFloat64()
+		summary.ProfitFactor = m.ProfitFactor.InexactFloat64()
+	}
+	summary.TradeCount = len(result.Trades)
+
+	for i, trade := range result.Trades {
+		summary.TradePnLs[i] = trade.PnL.InexactFloat64()
+	}
+
+	var initialCapital float64
+	if result.Config != nil {
+		initialCapital = result.Config.InitialCapital.InexactFloat64()
+	}
+	summary.TradeReturns = montecarlo.ReturnsFromPnLs(summary.TradePnLs, initialCapital)
+
+	for i, point := range result.EquityCurve {
+		summary.EquityCurve[i] = point.Equity.InexactFloat64()
+		summary.Timestamps[i] = point.Timestamp
+	}
+
+	return summary
+}
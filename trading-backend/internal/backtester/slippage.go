@@ -49,7 +49,7 @@ func NewVolumeWeightedSlippage(baseBps, impactFactor, volumeFrac decimal.Decimal
 func (v *VolumeWeightedSlippage) Calculate(order *types.Order, marketData *events.MarketDataEvent) decimal.Decimal {
 	baseSlip := v.BaseSlippage.Div(decimal.NewFromInt(10000))
 	
-	if marketData.OHLCV == nil || marketData.OHLCV.Volume.IsZero() {
+	if order == nil || marketData == nil || marketData.OHLCV == nil || marketData.OHLCV.Volume.IsZero() {
 		return baseSlip
 	}
 	
@@ -160,6 +160,56 @@ func (o *OrderBookSlippage) calculateFromOrderBook(order *types.Order, book *eve
 	return slippage
 }
 
+// SqrtImpactSlippage models slippage as a base plus square-root market impact
+// on the order's notional value, the form live fills calibrate
+type SqrtImpactSlippage struct {
+	BaseSlippage decimal.Decimal // Base slippage in bps
+	ImpactFactor decimal.Decimal // Slippage per unit of sqrt(notional)
+}
+
+// NewSqrtImpactSlippage creates a square-root impact slippage model
+func NewSqrtImpactSlippage(baseBps, impactFactor decimal.Decimal) *SqrtImpactSlippage {
+	return &SqrtImpactSlippage{
+		BaseSlippage: baseBps,
+		ImpactFactor: impactFactor,
+	}
+}
+
+// Calculate returns base slippage plus impact growing with sqrt(notional)
+func (s *SqrtImpactSlippage) Calculate(order *types.Order, marketData *events.MarketDataEvent) decimal.Decimal {
+	baseSlip := s.BaseSlippage.Div(decimal.NewFromInt(10000))
+	if order == nil {
+		return baseSlip
+	}
+	
+	price := order.Price
+	if price.IsZero() && marketData != nil && marketData.OHLCV != nil {
+		price = marketData.OHLCV.Close
+	}
+	notional := order.Quantity.Mul(price).InexactFloat64()
+	if notional <= 0 {
+		return baseSlip
+	}
+	
+	return baseSlip.Add(s.ImpactFactor.Mul(decimal.NewFromFloat(math.Sqrt(notional))))
+}
+
+// SymbolSlippage applies a per-symbol model, falling back to a default one
+type SymbolSlippage struct {
+	Default SlippageModel
+	Symbols map[string]SlippageModel
+}
+
+// Calculate returns slippage from the order symbol's model
+func (s *SymbolSlippage) Calculate(order *types.Order, marketData *events.MarketDataEvent) decimal.Decimal {
+	if order != nil {
+		if model, ok := s.Symbols[order.Symbol]; ok {
+			return model.Calculate(order, marketData)
+		}
+	}
+	return s.Default.Calculate(order, marketData)
+}
+
 // MEVAwareSlippage models slippage including MEV attack detection
 type MEVAwareSlippage struct {
 	BaseModel       SlippageModel
@@ -216,8 +266,20 @@ func (m *MEVAwareSlippage) detectPotentialMEV(order *types.Order, marketData *ev
 	return false
 }
 
-// CreateSlippageModel creates a slippage model from config
+// CreateSlippageModel creates a slippage model from config, applying any
+// per-symbol models over it
 func CreateSlippageModel(config types.SlippageConfig) SlippageModel {
+	if len(config.Symbols) > 0 {
+		bySymbol := &SymbolSlippage{Symbols: make(map[string]SlippageModel, len(config.Symbols))}
+		for symbol, symbolConfig := range config.Symbols {
+			symbolConfig.Symbols = nil
+			bySymbol.Symbols[symbol] = CreateSlippageModel(symbolConfig)
+		}
+		config.Symbols = nil
+		bySymbol.Default = CreateSlippageModel(config)
+		return bySymbol
+	}
+	
 	switch config.Model {
 	case "fixed":
 		return NewFixedSlippage(config.FixedBps)
@@ -227,6 +289,8 @@ func CreateSlippageModel(config types.SlippageConfig) SlippageModel {
 			config.ImpactFactor,
 			config.VolumeFraction,
 		)
+	case "sqrt_impact":
+		return NewSqrtImpactSlippage(config.FixedBps, config.ImpactFactor)
 	case "orderbook":
 		return NewOrderBookSlippage(10, decimal.NewFromFloat(0.5), decimal.NewFromFloat(1))
 	default:
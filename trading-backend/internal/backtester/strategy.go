@@ -0,0 +1,48 @@
+// Package backtester provides the strategy interface shared by live trading and backtests.
+package backtester
+
+import (
+	"fmt"
+
+	bus "github.com/atlas-desktop/trading-backend/internal/events"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+)
+
+// Strategy turns bars into trading signals. Bars arrive as event bus bar
+// events, so one implementation runs both live and inside the Engine.
+type Strategy interface {
+	Name() string
+	// OnBar returns a signal for the bar, or nil to do nothing
+	OnBar(bar *bus.BarEvent) (*types.Signal, error)
+}
+
+// SignalHandler receives the signals a subscribed strategy emits
+type SignalHandler func(signal *types.Signal)
+
+// SubscribeStrategy feeds bar events from eventBus to strategy and passes each
+// signal it returns to onSignal. The subscription is synchronous so a
+// stateful strategy sees bars one at a time, in publish order.
+func SubscribeStrategy(eventBus *bus.EventBus, strategy Strategy, onSignal SignalHandler) *bus.Subscription {
+	handler := func(event bus.Event) error {
+		bar, ok := event.(*bus.BarEvent)
+		if !ok {
+			return nil
+		}
+
+		signal, err := strategy.OnBar(bar)
+		if err != nil {
+			return fmt.Errorf("strategy %s failed on %s bar: %w", strategy.Name(), bar.Symbol, err)
+		}
+		if signal != nil {
+			onSignal(signal)
+		}
+		return nil
+	}
+
+	return eventBus.Subscribe(bus.EventTypeBar, handler, bus.SubscriptionOptions{Async: false})
+}
+
+// newBarEvent converts a historical bar into the event the live feed publishes
+func newBarEvent(symbol string, bar *types.OHLCV) *bus.BarEvent {
+	return bus.NewBarEvent(symbol, bar.Open, bar.High, bar.Low, bar.Close, bar.Volume, bar.Timestamp)
+}
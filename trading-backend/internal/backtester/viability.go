@@ -358,10 +358,13 @@ func (vc *ViabilityChecker) checkWalkForward(wfResult *types.WalkForwardResult,
 	var totalSharpe decimal.Decimal
 
 	for _, window := range wfResult.Windows {
-		if window.OutSampleReturn.GreaterThan(decimal.Zero) {
+		if window.OutSampleMetrics == nil {
+			continue
+		}
+		if window.OutSampleMetrics.TotalReturn.GreaterThan(decimal.Zero) {
 			profitableWindows++
 		}
-		totalSharpe = totalSharpe.Add(window.OutSampleSharpe)
+		totalSharpe = totalSharpe.Add(window.OutSampleMetrics.SharpeRatio)
 	}
 
 	consistency := decimal.NewFromInt(int64(profitableWindows)).Div(
@@ -462,7 +465,7 @@ func (vc *ViabilityChecker) calculateRobustnessScore(wfResult *types.WalkForward
 	// Calculate from walk-forward results
 	profitableWindows := 0
 	for _, window := range wfResult.Windows {
-		if window.OutSampleReturn.GreaterThan(decimal.Zero) {
+		if window.OutSampleMetrics != nil && window.OutSampleMetrics.TotalReturn.GreaterThan(decimal.Zero) {
 			profitableWindows++
 		}
 	}
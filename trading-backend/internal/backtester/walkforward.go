@@ -16,6 +16,7 @@ type WalkForwardAnalyzer struct {
 	logger        *zap.Logger
 	dataLoader    DataLoader
 	slippageModel SlippageModel
+	newStrategy   StrategyFactory
 }
 
 // NewWalkForwardAnalyzer creates a new walk-forward analyzer
@@ -31,6 +32,11 @@ func NewWalkForwardAnalyzer(
 	}
 }
 
+// SetStrategyFactory sets the strategy each window's backtests trade
+func (wf *WalkForwardAnalyzer) SetStrategyFactory(newStrategy StrategyFactory) {
+	wf.newStrategy = newStrategy
+}
+
 // Run performs walk-forward analysis
 func (wf *WalkForwardAnalyzer) Run(ctx context.Context, config *types.BacktestConfig) (*types.WalkForwardResult, error) {
 	wfConfig := config.Validation.WalkForward
@@ -86,6 +92,7 @@ func (wf *WalkForwardAnalyzer) Run(ctx context.Context, config *types.BacktestCo
 		inSampleConfig.Validation.MonteCarlo.Enabled = false
 		
 		inSampleEngine := NewEngine(wf.logger, wf.dataLoader, wf.slippageModel)
+		inSampleEngine.SetStrategyFactory(wf.newStrategy)
 		inSampleResult, err := inSampleEngine.Run(ctx, &inSampleConfig)
 		if err != nil {
 			wf.logger.Warn("In-sample backtest failed",
@@ -103,6 +110,7 @@ func (wf *WalkForwardAnalyzer) Run(ctx context.Context, config *types.BacktestCo
 		outSampleConfig.Validation.MonteCarlo.Enabled = false
 		
 		outSampleEngine := NewEngine(wf.logger, wf.dataLoader, wf.slippageModel)
+		outSampleEngine.SetStrategyFactory(wf.newStrategy)
 		outSampleResult, err := outSampleEngine.Run(ctx, &outSampleConfig)
 		if err != nil {
 			wf.logger.Warn("Out-of-sample backtest failed",
@@ -178,7 +186,6 @@ func (wf *WalkForwardAnalyzer) generateWindows(
 	// Use 80/20 split for in-sample/out-of-sample
 	inSampleRatio := 0.8
 	inSampleDuration := time.Duration(float64(windowDuration) * inSampleRatio)
-	outSampleDuration := windowDuration - inSampleDuration
 	
 	current := start
 	
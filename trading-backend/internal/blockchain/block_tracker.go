@@ -3,9 +3,12 @@ package blockchain
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/atlas-desktop/trading-backend/internal/backtester/events"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
@@ -21,6 +24,7 @@ type BlockInfo struct {
 	GasUsed         uint64          `json:"gasUsed"`
 	GasLimit        uint64          `json:"gasLimit"`
 	BaseFeePerGas   decimal.Decimal `json:"baseFeePerGas,omitempty"`
+	PriorityFees    []decimal.Decimal `json:"-"` // Effective tip per transaction in wei
 	
 	// Solana-specific
 	Slot            uint64          `json:"slot,omitempty"`
@@ -31,6 +35,40 @@ type BlockInfo struct {
 	TotalVolume     decimal.Decimal `json:"totalVolume"`
 	LargeTransfers  int             `json:"largeTransfers"`
 	MEVDetected     bool            `json:"mevDetected"`
+	
+	// Native value moved to and from known exchange wallets
+	ExchangeInflow  decimal.Decimal `json:"exchangeInflow"`
+	ExchangeOutflow decimal.Decimal `json:"exchangeOutflow"`
+}
+
+// FlowMetrics summarizes whale transfers and exchange flows over recent blocks.
+type FlowMetrics struct {
+	Chain           string          `json:"chain"`
+	Blocks          int             `json:"blocks"`
+	LargeTransfers  int             `json:"largeTransfers"`
+	WhaleVolume     decimal.Decimal `json:"whaleVolume"`
+	ExchangeInflow  decimal.Decimal `json:"exchangeInflow"`
+	ExchangeOutflow decimal.Decimal `json:"exchangeOutflow"`
+	NetExchangeFlow decimal.Decimal `json:"netExchangeFlow"` // Inflow minus outflow; negative when coins leave exchanges
+	From            time.Time       `json:"from"`
+	To              time.Time       `json:"to"`
+}
+
+// exchangeWallets are known centralized exchange hot wallets (lowercase).
+var exchangeWallets = map[string]string{
+	"0x28c6c06298d514db089934071355e5743bf21d60": "binance",
+	"0x21a31ee1afc51d94c2efccaa2092ad1028285549": "binance",
+	"0xdfd5293d8e347dfe59e90efd55b2956a1343963d": "binance",
+	"0x71660c4005ba85c37ccec55d0c4493e66fe775d3": "coinbase",
+	"0x503828976d22510aad0201ac7ec88293211d23da": "coinbase",
+	"0x2910543af39aba0cd09dbb2d50200b3e800a63d2": "kraken",
+	"0x6cc5f688a315f3dc28a7781717a9a798a59fda7b": "okx",
+}
+
+// isExchangeWallet reports whether an address belongs to a known exchange.
+func isExchangeWallet(address string) bool {
+	_, ok := exchangeWallets[strings.ToLower(address)]
+	return ok
 }
 
 // BlockEvent represents an event from block tracking.
@@ -39,6 +77,7 @@ type BlockEvent struct {
 	Chain     string         `json:"chain"`
 	Block     *BlockInfo     `json:"block,omitempty"`
 	Reorg     *ReorgInfo     `json:"reorg,omitempty"`
+	Congestion *CongestionInfo `json:"congestion,omitempty"`
 	Error     error          `json:"error,omitempty"`
 	Timestamp time.Time      `json:"timestamp"`
 }
@@ -52,6 +91,7 @@ const (
 	BlockEventReorg     BlockEventType = "reorg"
 	BlockEventError     BlockEventType = "error"
 	BlockEventGap       BlockEventType = "gap"
+	BlockEventCongestion BlockEventType = "congestion"
 )
 
 // ReorgInfo contains information about a chain reorganization.
@@ -84,6 +124,7 @@ type ChainState struct {
 	// Congestion
 	CongestionLevel    float64         `json:"congestionLevel"` // 0-1
 	PendingTxCount     int             `json:"pendingTxCount"`
+	PendingGasHistogram []GasPriceBucket `json:"pendingGasHistogram,omitempty"`
 }
 
 // BlockTracker tracks blocks across multiple chains in real-time.
@@ -97,6 +138,7 @@ type BlockTracker struct {
 	// State
 	chainStates  map[string]*ChainState
 	blockHistory map[string][]*BlockInfo // Last N blocks per chain
+	mempools     map[string]*mempoolMonitor // Chains with mempool monitoring
 	
 	// Configuration
 	config       BlockTrackerConfig
@@ -130,6 +172,14 @@ type BlockTrackerConfig struct {
 	HealthTimeout   time.Duration     `json:"healthTimeout"`
 	MaxReorgDepth   int               `json:"maxReorgDepth"`
 	
+	// Mempool monitoring (opt-in per EVM chain; fetches every pending tx)
+	MempoolChains      []string      `json:"mempoolChains"`
+	MempoolWindow      time.Duration `json:"mempoolWindow"`      // Rolling window for pending counts
+	MempoolSpikeFactor float64       `json:"mempoolSpikeFactor"` // Pending count over baseline that signals congestion
+	
+	// Fee estimation
+	FeeHistoryBlocks int             `json:"feeHistoryBlocks"` // Recent blocks whose tips inform gas suggestions
+	
 	// Event buffer
 	EventBufferSize int               `json:"eventBufferSize"`
 }
@@ -149,6 +199,9 @@ func DefaultBlockTrackerConfig() BlockTrackerConfig {
 		HistoryDepth:    100,
 		HealthTimeout:   30 * time.Second,
 		MaxReorgDepth:   10,
+		MempoolWindow:      time.Minute,
+		MempoolSpikeFactor: 2.0,
+		FeeHistoryBlocks:   20,
 		EventBufferSize: 1000,
 	}
 }
@@ -160,12 +213,18 @@ func NewBlockTracker(
 	evmClients map[string]*EVMClient,
 	config BlockTrackerConfig,
 ) *BlockTracker {
+	mempools := make(map[string]*mempoolMonitor)
+	for _, chain := range config.MempoolChains {
+		mempools[chain] = newMempoolMonitor(config.MempoolWindow)
+	}
+	
 	return &BlockTracker{
 		logger:       logger.Named("block-tracker"),
 		solana:       solana,
 		evmClients:   evmClients,
 		chainStates:  make(map[string]*ChainState),
 		blockHistory: make(map[string][]*BlockInfo),
+		mempools:     mempools,
 		config:       config,
 		events:       make(chan BlockEvent, config.EventBufferSize),
 	}
@@ -204,6 +263,14 @@ func (bt *BlockTracker) Start(ctx context.Context) error {
 		}
 	}
 	
+	// Start mempool monitors
+	for chain := range bt.mempools {
+		if client, ok := bt.evmClients[chain]; ok {
+			bt.wg.Add(1)
+			go bt.trackMempool(ctx, chain, client)
+		}
+	}
+	
 	// Start health monitor
 	bt.wg.Add(1)
 	go bt.monitorHealth(ctx)
@@ -342,7 +409,12 @@ func (bt *BlockTracker) trackSolana(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			return
-		case slot := <-slotChan:
+		case slot, ok := <-slotChan:
+			if !ok {
+				bt.logger.Warn("Solana slot stream closed")
+				bt.pollSolana(ctx)
+				return
+			}
 			bt.handleSolanaSlot(ctx, slot)
 		}
 	}
@@ -386,15 +458,12 @@ func (bt *BlockTracker) handleSolanaSlot(ctx context.Context, slot uint64) {
 		Chain:            "solana",
 		Number:           slot,
 		Slot:             slot,
-		Hash:             block.Blockhash,
-		ParentHash:       block.PreviousBlockhash,
-		Timestamp:        time.Unix(block.BlockTime, 0),
-		TransactionCount: len(block.Transactions),
+		Hash:             block.BlockHash,
+		ParentHash:       block.ParentHash,
+		Timestamp:        block.Timestamp,
+		TransactionCount: block.TxCount,
 	}
 	
-	// Analyze transactions for trading metrics
-	bt.analyzeSolanaBlock(blockInfo, block)
-	
 	bt.recordBlock("solana", blockInfo)
 	
 	bt.emitEvent(BlockEvent{
@@ -405,37 +474,81 @@ func (bt *BlockTracker) handleSolanaSlot(ctx context.Context, slot uint64) {
 	})
 }
 
-// analyzeSolanaBlock extracts trading-relevant metrics.
-func (bt *BlockTracker) analyzeSolanaBlock(info *BlockInfo, block *SolanaBlock) {
-	// Count DEX transactions (Raydium, Orca, Jupiter, etc.)
-	dexPrograms := map[string]bool{
-		"675kPX9MHTjS2zt1qfr1NYHuzeLXfQM9H24wFSUt1Mp8": true, // Raydium V4
-		"whirLbMiicVdio4qvUfM5KAg6Ct8VwpYzGff3uctyCc":  true, // Orca Whirlpool
-		"JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4":  true, // Jupiter V6
-		"9W959DqEETiGZocYWCQPaJ6sBmUzgfxXfqGeTEdp3aQP": true, // Orca Token Swap
-	}
+// trackEVM tracks blocks on an EVM chain.
+func (bt *BlockTracker) trackEVM(ctx context.Context, chain string, client *EVMClient) {
+	defer bt.wg.Done()
 	
-	for _, tx := range block.Transactions {
-		for _, instruction := range tx.Transaction.Message.Instructions {
-			if dexPrograms[instruction.ProgramId] {
-				info.DEXTransactions++
+	bt.logger.Info("Starting EVM block tracking", zap.String("chain", chain))
+	
+	var lastBlock uint64
+	recentBlocks := make(map[uint64]string) // block -> hash for reorg detection
+	
+	// Prefer streamed new heads when the client has a WebSocket
+	if client.HasWebSocket() {
+		heads, err := client.SubscribeNewHeads(ctx)
+		if err != nil {
+			bt.logger.Error("Failed to subscribe to EVM new heads",
+				zap.String("chain", chain),
+				zap.Error(err))
+		} else {
+			lastBlock = bt.streamEVM(ctx, chain, client, heads, recentBlocks)
+			if ctx.Err() != nil {
+				return
 			}
+			bt.logger.Warn("EVM new heads stream closed", zap.String("chain", chain))
 		}
 	}
+	
+	// Fall back to polling
+	bt.pollEVM(ctx, chain, client, lastBlock, recentBlocks)
 }
 
-// trackEVM tracks blocks on an EVM chain.
-func (bt *BlockTracker) trackEVM(ctx context.Context, chain string, client *EVMClient) {
-	defer bt.wg.Done()
-	
-	bt.logger.Info("Starting EVM block tracking", zap.String("chain", chain))
+// streamEVM processes new heads until the stream closes and returns the last
+// block handled.
+func (bt *BlockTracker) streamEVM(
+	ctx context.Context,
+	chain string,
+	client *EVMClient,
+	heads <-chan *events.BlockEvent,
+	recentBlocks map[uint64]string,
+) uint64 {
+	var lastBlock uint64
 	
+	for {
+		select {
+		case <-ctx.Done():
+			return lastBlock
+		case head, ok := <-heads:
+			if !ok {
+				return lastBlock
+			}
+			
+			if head.BlockNumber > lastBlock {
+				lastBlock = bt.advanceEVM(ctx, chain, client, lastBlock, head.BlockNumber, recentBlocks)
+				continue
+			}
+			
+			// A head at or below our tip replaces a block we already handled.
+			// Rollback leaves us just below it, so handle it as the new tip.
+			if bt.detectReorg(ctx, chain, client, head.BlockNumber, head.BlockHash, head.ParentHash, recentBlocks) {
+				bt.handleEVMBlock(ctx, chain, client, head.BlockNumber, recentBlocks)
+				lastBlock = head.BlockNumber
+			}
+		}
+	}
+}
+
+// pollEVM polls an EVM chain for new blocks, starting after lastBlock.
+func (bt *BlockTracker) pollEVM(
+	ctx context.Context,
+	chain string,
+	client *EVMClient,
+	lastBlock uint64,
+	recentBlocks map[uint64]string,
+) {
 	ticker := time.NewTicker(bt.config.PollInterval)
 	defer ticker.Stop()
 	
-	var lastBlock uint64
-	recentBlocks := make(map[uint64]string) // block -> hash for reorg detection
-	
 	for {
 		select {
 		case <-ctx.Done():
@@ -447,35 +560,51 @@ func (bt *BlockTracker) trackEVM(ctx context.Context, chain string, client *EVMC
 				continue
 			}
 			
-			// Process new blocks
+			// Process new blocks; each is checked for reorgs as it is handled
 			if blockNum > lastBlock {
-				// Check for gaps
-				if lastBlock > 0 && blockNum > lastBlock+1 {
-					bt.logger.Warn("Block gap detected",
-						zap.String("chain", chain),
-						zap.Uint64("from", lastBlock),
-						zap.Uint64("to", blockNum))
-					
-					bt.emitEvent(BlockEvent{
-						Type:      BlockEventGap,
-						Chain:     chain,
-						Timestamp: time.Now(),
-					})
-				}
-				
-				for i := lastBlock + 1; i <= blockNum; i++ {
-					bt.handleEVMBlock(ctx, chain, client, i, recentBlocks)
-				}
-				
-				lastBlock = blockNum
+				lastBlock = bt.advanceEVM(ctx, chain, client, lastBlock, blockNum, recentBlocks)
 			}
-			
-			// Check for reorgs
-			bt.detectReorg(ctx, chain, client, recentBlocks)
 		}
 	}
 }
 
+// advanceEVM handles every block after lastBlock up to blockNum, reporting a
+// gap if any were skipped, and returns blockNum.
+func (bt *BlockTracker) advanceEVM(
+	ctx context.Context,
+	chain string,
+	client *EVMClient,
+	lastBlock uint64,
+	blockNum uint64,
+	recentBlocks map[uint64]string,
+) uint64 {
+	// Check for gaps
+	if lastBlock > 0 && blockNum > lastBlock+1 {
+		bt.logger.Warn("Block gap detected",
+			zap.String("chain", chain),
+			zap.Uint64("from", lastBlock),
+			zap.Uint64("to", blockNum))
+		
+		bt.emitEvent(BlockEvent{
+			Type:      BlockEventGap,
+			Chain:     chain,
+			Timestamp: time.Now(),
+		})
+	}
+	
+	// Start from the new head when we have no history
+	first := lastBlock + 1
+	if lastBlock == 0 {
+		first = blockNum
+	}
+	
+	for i := first; i <= blockNum; i++ {
+		bt.handleEVMBlock(ctx, chain, client, i, recentBlocks)
+	}
+	
+	return blockNum
+}
+
 // handleEVMBlock processes a new EVM block.
 func (bt *BlockTracker) handleEVMBlock(
 	ctx context.Context,
@@ -484,7 +613,7 @@ func (bt *BlockTracker) handleEVMBlock(
 	blockNum uint64,
 	recentBlocks map[uint64]string,
 ) {
-	block, err := client.GetBlock(ctx, blockNum)
+	block, err := client.GetBlockWithTransactions(ctx, blockNum)
 	if err != nil {
 		bt.logger.Debug("Failed to get EVM block",
 			zap.String("chain", chain),
@@ -508,6 +637,9 @@ func (bt *BlockTracker) handleEVMBlock(
 	// Analyze transactions
 	bt.analyzeEVMBlock(blockInfo, block, client)
 	
+	// Check the block extends the chain we recorded
+	bt.detectReorg(ctx, chain, client, blockNum, block.Hash, block.ParentHash, recentBlocks)
+	
 	// Track for reorg detection
 	recentBlocks[blockNum] = block.Hash
 	
@@ -559,6 +691,15 @@ func (bt *BlockTracker) analyzeEVMBlock(info *BlockInfo, block *EVMBlock, client
 			info.DEXTransactions++
 		}
 		
+		// Record the tip it paid over the base fee
+		gasPrice, _ := decimal.NewFromString(tx.GasPrice)
+		maxFee, _ := decimal.NewFromString(tx.MaxFeePerGas)
+		maxPriorityFee, _ := decimal.NewFromString(tx.MaxPriorityFeePerGas)
+		if !gasPrice.IsZero() || !maxFee.IsZero() {
+			info.PriorityFees = append(info.PriorityFees,
+				effectiveTip(info.BaseFeePerGas, gasPrice, maxFee, maxPriorityFee))
+		}
+		
 		// Check for large transfers
 		value, err := decimal.NewFromString(tx.Value)
 		if err == nil {
@@ -568,6 +709,16 @@ func (bt *BlockTracker) analyzeEVMBlock(info *BlockInfo, block *EVMBlock, client
 				info.LargeTransfers++
 				info.TotalVolume = info.TotalVolume.Add(nativeValue)
 			}
+			
+			// Track exchange deposits and withdrawals; transfers between
+			// exchange wallets are internal and cancel out
+			toExchange := isExchangeWallet(tx.To)
+			fromExchange := isExchangeWallet(tx.From)
+			if toExchange && !fromExchange {
+				info.ExchangeInflow = info.ExchangeInflow.Add(nativeValue)
+			} else if fromExchange && !toExchange {
+				info.ExchangeOutflow = info.ExchangeOutflow.Add(nativeValue)
+			}
 		}
 		
 		// MEV detection
@@ -578,63 +729,96 @@ func (bt *BlockTracker) analyzeEVMBlock(info *BlockInfo, block *EVMBlock, client
 	}
 }
 
-// detectReorg checks for chain reorganizations.
+// detectReorg checks a newly observed block against the hashes we recorded.
+// The steady-state cost is two map lookups; only on a mismatch does it walk
+// back through history to find how deep the reorg goes. On a reorg the
+// orphaned blocks are rolled back and the canonical blocks below blockNum are
+// re-fetched and re-emitted, leaving blockNum itself to the caller. It reports
+// whether a reorg occurred.
 func (bt *BlockTracker) detectReorg(
 	ctx context.Context,
 	chain string,
 	client *EVMClient,
+	blockNum uint64,
+	hash string,
+	parentHash string,
 	recentBlocks map[uint64]string,
-) {
-	// Check recent blocks haven't changed
-	for blockNum, expectedHash := range recentBlocks {
-		block, err := client.GetBlock(ctx, blockNum)
-		if err != nil {
-			continue
-		}
-		
-		if block.Hash != expectedHash {
-			bt.logger.Warn("Chain reorganization detected",
-				zap.String("chain", chain),
-				zap.Uint64("block", blockNum),
-				zap.String("expected", expectedHash),
-				zap.String("actual", block.Hash))
-			
-			// Find reorg depth
-			depth := bt.findReorgDepth(ctx, chain, client, blockNum, recentBlocks)
-			
-			bt.mu.Lock()
-			if state, ok := bt.chainStates[chain]; ok {
-				state.ReorgCount++
-			}
-			bt.mu.Unlock()
-			
-			bt.emitEvent(BlockEvent{
-				Type:  BlockEventReorg,
-				Chain: chain,
-				Reorg: &ReorgInfo{
-					Chain:     chain,
-					OldHead:   blockNum,
-					NewHead:   block.Number,
-					Depth:     depth,
-					Timestamp: time.Now(),
-				},
-				Timestamp: time.Now(),
-			})
-			
-			// Update our records
-			recentBlocks[blockNum] = block.Hash
-			
-			// Warn if deep reorg
-			if depth > bt.config.MaxReorgDepth {
-				bt.logger.Error("Deep chain reorganization detected",
-					zap.String("chain", chain),
-					zap.Int("depth", depth))
+) bool {
+	var depth int
+	var oldHead uint64
+	
+	// The block replaces one we already saw at this height
+	if expected, ok := recentBlocks[blockNum]; ok && expected != hash {
+		depth = 1
+		oldHead = blockNum
+	}
+	
+	// The block's parent is not the block we recorded below it
+	if blockNum > 0 {
+		if expected, ok := recentBlocks[blockNum-1]; ok && expected != parentHash {
+			if depth == 0 {
+				oldHead = blockNum - 1
 			}
+			depth += bt.findReorgDepth(ctx, chain, client, blockNum-1, recentBlocks)
 		}
 	}
+	
+	if depth == 0 {
+		return false
+	}
+	
+	ancestor := uint64(0)
+	if oldHead > uint64(depth) {
+		ancestor = oldHead - uint64(depth)
+	}
+	
+	bt.logger.Warn("Chain reorganization detected",
+		zap.String("chain", chain),
+		zap.Uint64("block", blockNum),
+		zap.String("hash", hash),
+		zap.String("parentHash", parentHash),
+		zap.Uint64("ancestor", ancestor),
+		zap.Int("depth", depth))
+	
+	// Warn if deep reorg
+	if depth > bt.config.MaxReorgDepth {
+		bt.logger.Error("Deep chain reorganization detected",
+			zap.String("chain", chain),
+			zap.Int("depth", depth))
+	}
+	
+	orphaned := bt.rollback(chain, ancestor, recentBlocks)
+	
+	bt.mu.Lock()
+	if state, ok := bt.chainStates[chain]; ok {
+		state.ReorgCount++
+	}
+	bt.mu.Unlock()
+	
+	bt.emitEvent(BlockEvent{
+		Type:  BlockEventReorg,
+		Chain: chain,
+		Reorg: &ReorgInfo{
+			Chain:          chain,
+			OldHead:        oldHead,
+			NewHead:        blockNum,
+			Depth:          depth,
+			AffectedBlocks: orphaned,
+			Timestamp:      time.Now(),
+		},
+		Timestamp: time.Now(),
+	})
+	
+	// Replay the canonical chain up to the new block
+	for n := ancestor + 1; n < blockNum; n++ {
+		bt.handleEVMBlock(ctx, chain, client, n, recentBlocks)
+	}
+	
+	return true
 }
 
-// findReorgDepth determines how deep a reorg goes.
+// findReorgDepth determines how deep a reorg goes below startBlock, which is
+// already known to be replaced.
 func (bt *BlockTracker) findReorgDepth(
 	ctx context.Context,
 	chain string,
@@ -655,7 +839,7 @@ func (bt *BlockTracker) findReorgDepth(
 			break
 		}
 		
-		if block.Hash == expectedHash {
+		if block.BlockHash == expectedHash {
 			break
 		}
 		
@@ -665,6 +849,119 @@ func (bt *BlockTracker) findReorgDepth(
 	return depth
 }
 
+// rollback drops every recorded block above ancestor and resets the chain
+// state to it. It returns the orphaned block numbers in ascending order.
+func (bt *BlockTracker) rollback(chain string, ancestor uint64, recentBlocks map[uint64]string) []uint64 {
+	orphaned := make([]uint64, 0)
+	for blockNum := range recentBlocks {
+		if blockNum > ancestor {
+			orphaned = append(orphaned, blockNum)
+			delete(recentBlocks, blockNum)
+		}
+	}
+	sort.Slice(orphaned, func(i, j int) bool { return orphaned[i] < orphaned[j] })
+	
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	
+	history := bt.blockHistory[chain]
+	keep := len(history)
+	for keep > 0 && history[keep-1].Number > ancestor {
+		keep--
+	}
+	bt.blockHistory[chain] = history[:keep]
+	
+	if state, ok := bt.chainStates[chain]; ok {
+		state.LatestBlock = ancestor
+		
+		// Blocks past the ancestor are no longer confirmed
+		confirmations := uint64(bt.config.Confirmations[chain])
+		state.ConfirmedBlock = 0
+		if ancestor > confirmations {
+			state.ConfirmedBlock = ancestor - confirmations
+		}
+	}
+	
+	return orphaned
+}
+
+// trackMempool monitors pending transactions on an EVM chain.
+func (bt *BlockTracker) trackMempool(ctx context.Context, chain string, client *EVMClient) {
+	defer bt.wg.Done()
+	
+	mempool := bt.mempools[chain]
+	
+	client.OnTransaction(func(tx *events.MempoolEvent) {
+		if ctx.Err() == nil {
+			mempool.record(tx.GasPrice, time.Now())
+		}
+	})
+	
+	if err := client.SubscribeToPendingTx(); err != nil {
+		bt.logger.Error("Failed to subscribe to pending transactions",
+			zap.String("chain", chain),
+			zap.Error(err))
+		return
+	}
+	
+	bt.logger.Info("Starting mempool monitoring", zap.String("chain", chain))
+	
+	interval := bt.config.MempoolWindow / 6
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			bt.updateMempoolState(chain, mempool)
+		}
+	}
+}
+
+// updateMempoolState refreshes a chain's pending count and gas histogram and
+// emits a congestion event when the pending count spikes.
+func (bt *BlockTracker) updateMempoolState(chain string, mempool *mempoolMonitor) {
+	now := time.Now()
+	count, histogram := mempool.snapshot(now)
+	
+	bt.mu.Lock()
+	if state, ok := bt.chainStates[chain]; ok {
+		state.PendingTxCount = count
+		state.PendingGasHistogram = histogram
+	}
+	bt.mu.Unlock()
+	
+	spike, baseline := mempool.checkSpike(count, bt.config.MempoolSpikeFactor)
+	if !spike {
+		return
+	}
+	
+	median, _ := mempool.gasPriceAt(0.5, now)
+	
+	bt.logger.Warn("Mempool congestion detected",
+		zap.String("chain", chain),
+		zap.Int("pending", count),
+		zap.Float64("baseline", baseline))
+	
+	bt.emitEvent(BlockEvent{
+		Type:  BlockEventCongestion,
+		Chain: chain,
+		Congestion: &CongestionInfo{
+			Chain:          chain,
+			PendingTxCount: count,
+			Baseline:       baseline,
+			MedianGasPrice: median,
+			Timestamp:      now,
+		},
+		Timestamp: now,
+	})
+}
+
 // recordBlock records a block in history.
 func (bt *BlockTracker) recordBlock(chain string, block *BlockInfo) {
 	bt.mu.Lock()
@@ -792,49 +1089,69 @@ func (bt *BlockTracker) GetCongestionLevel(chain string) float64 {
 	return 0
 }
 
-// GetOptimalGasPrice suggests optimal gas price based on recent blocks.
-func (bt *BlockTracker) GetOptimalGasPrice(chain string, urgency float64) decimal.Decimal {
+// GetFlowMetrics summarizes whale transfers and exchange flows over the last
+// limit blocks of a chain (all recorded blocks if limit <= 0). It returns nil
+// if no blocks have been recorded.
+func (bt *BlockTracker) GetFlowMetrics(chain string, limit int) *FlowMetrics {
 	bt.mu.RLock()
 	defer bt.mu.RUnlock()
 	
 	history := bt.blockHistory[chain]
+	if limit > 0 && limit < len(history) {
+		history = history[len(history)-limit:]
+	}
 	if len(history) == 0 {
-		return decimal.Zero
+		return nil
+	}
+	
+	metrics := &FlowMetrics{
+		Chain:  chain,
+		Blocks: len(history),
+		From:   history[0].Timestamp,
+		To:     history[len(history)-1].Timestamp,
 	}
 	
-	// Collect recent gas prices
-	var gasPrices []decimal.Decimal
 	for _, block := range history {
-		if !block.BaseFeePerGas.IsZero() {
-			gasPrices = append(gasPrices, block.BaseFeePerGas)
-		}
+		metrics.LargeTransfers += block.LargeTransfers
+		metrics.WhaleVolume = metrics.WhaleVolume.Add(block.TotalVolume)
+		metrics.ExchangeInflow = metrics.ExchangeInflow.Add(block.ExchangeInflow)
+		metrics.ExchangeOutflow = metrics.ExchangeOutflow.Add(block.ExchangeOutflow)
 	}
+	metrics.NetExchangeFlow = metrics.ExchangeInflow.Sub(metrics.ExchangeOutflow)
 	
-	if len(gasPrices) == 0 {
-		return decimal.Zero
+	return metrics
+}
+
+// GetOptimalGasPrice suggests EIP-1559 fees for a transaction on chain.
+// Urgency (0-1) selects the percentile of tips paid in recent blocks and how
+// far ahead of the projected base fee the max fee reaches. On chains with
+// mempool monitoring the tip is raised to compete with pending transactions
+// at the same urgency percentile. The estimate is zero if no blocks have been
+// recorded.
+func (bt *BlockTracker) GetOptimalGasPrice(chain string, urgency float64) GasPriceEstimate {
+	bt.mu.RLock()
+	history := bt.blockHistory[chain]
+	if limit := bt.config.FeeHistoryBlocks; limit > 0 && limit < len(history) {
+		history = history[len(history)-limit:]
 	}
+	estimate := estimateGasPrice(history, urgency)
+	bt.mu.RUnlock()
 	
-	// Calculate percentile based on urgency
-	// urgency 0.0 = lowest price, 1.0 = highest price
-	sortedPrices := make([]decimal.Decimal, len(gasPrices))
-	copy(sortedPrices, gasPrices)
+	if len(history) == 0 {
+		return estimate
+	}
 	
-	// Simple sort
-	for i := 0; i < len(sortedPrices); i++ {
-		for j := i + 1; j < len(sortedPrices); j++ {
-			if sortedPrices[i].GreaterThan(sortedPrices[j]) {
-				sortedPrices[i], sortedPrices[j] = sortedPrices[j], sortedPrices[i]
+	if mempool, ok := bt.mempools[chain]; ok {
+		if pending, ok := mempool.gasPriceAt(clampUnit(urgency), time.Now()); ok {
+			// Pending gas prices include the base fee they expect to pay
+			if tip := pending.Sub(estimate.ProjectedBaseFee); tip.GreaterThan(estimate.MaxPriorityFeePerGas) {
+				estimate.MaxFeePerGas = estimate.MaxFeePerGas.Add(tip.Sub(estimate.MaxPriorityFeePerGas))
+				estimate.MaxPriorityFeePerGas = tip
 			}
 		}
 	}
 	
-	index := int(urgency * float64(len(sortedPrices)-1))
-	basePrice := sortedPrices[index]
-	
-	// Add priority fee based on urgency
-	priorityFee := basePrice.Mul(decimal.NewFromFloat(urgency * 0.2))
-	
-	return basePrice.Add(priorityFee)
+	return estimate
 }
 
 // IsConfirmed checks if a block is confirmed.
@@ -0,0 +1,464 @@
+package blockchain_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/blockchain"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+const (
+	binanceHotWallet = "0x28c6c06298d514db089934071355e5743bf21d60"
+	uniswapV2Router  = "0x7a250d5630b4cf539739df2c5dacb4c659f2488d"
+)
+
+// chainNode is an EVM node whose chain the test builds and rewrites. It
+// serves JSON-RPC over HTTP and pushes subscription notifications over a
+// WebSocket.
+type chainNode struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	blocks map[uint64]map[string]interface{}
+	txs    map[string]map[string]interface{}
+	calls  map[string]int
+
+	push       chan interface{}
+	subscribed chan string
+}
+
+func newChainNode(t *testing.T) *chainNode {
+	n := &chainNode{
+		blocks:     make(map[uint64]map[string]interface{}),
+		txs:        make(map[string]map[string]interface{}),
+		calls:      make(map[string]int),
+		push:       make(chan interface{}, 64),
+		subscribed: make(chan string, 8),
+	}
+	n.Server = httptest.NewServer(n)
+	t.Cleanup(n.Close)
+	return n
+}
+
+func blockHash(fork string, number uint64) string {
+	return fmt.Sprintf("0x%s%063x", fork, number)
+}
+
+// extend sets the given blocks of fork on the chain, each child of the block
+// below it. Blocks already at those heights are replaced.
+func (n *chainNode) extend(fork string, from, to uint64, txs ...map[string]interface{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for number := from; number <= to; number++ {
+		parent := blockHash(fork, number-1)
+		if below, ok := n.blocks[number-1]; ok {
+			parent = below["hash"].(string)
+		}
+
+		blockTxs := make([]interface{}, len(txs))
+		for i, tx := range txs {
+			blockTxs[i] = tx
+		}
+		n.blocks[number] = map[string]interface{}{
+			"number":        fmt.Sprintf("0x%x", number),
+			"hash":          blockHash(fork, number),
+			"parentHash":    parent,
+			"timestamp":     fmt.Sprintf("0x%x", 1700000000+number*12),
+			"gasUsed":       "0xe4e1c0",
+			"gasLimit":      "0x1c9c380",
+			"baseFeePerGas": "0x2540be400",
+			"transactions":  blockTxs,
+		}
+	}
+}
+
+// head pushes the chain's block at number as a newHeads notification.
+func (n *chainNode) head(number uint64) {
+	n.mu.Lock()
+	block := n.blocks[number]
+	n.mu.Unlock()
+
+	n.push <- map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "eth_subscription",
+		"params":  map[string]interface{}{"subscription": "0x1", "result": block},
+	}
+}
+
+// pending adds a pending transaction and announces its hash.
+func (n *chainNode) pending(hash string, gasPrice uint64) {
+	n.mu.Lock()
+	n.txs[hash] = map[string]interface{}{
+		"hash":     hash,
+		"from":     "0x0000000000000000000000000000000000000001",
+		"to":       "0x0000000000000000000000000000000000000002",
+		"value":    "0x0",
+		"gas":      "0x5208",
+		"gasPrice": fmt.Sprintf("0x%x", gasPrice),
+	}
+	n.mu.Unlock()
+
+	n.push <- map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "eth_subscription",
+		"params":  map[string]interface{}{"subscription": "0x2", "result": hash},
+	}
+}
+
+func (n *chainNode) callCount(method string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.calls[method]
+}
+
+func (n *chainNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		n.serveWS(w, r)
+		return
+	}
+
+	var req struct {
+		Method string        `json:"method"`
+		Params []interface{} `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n.mu.Lock()
+	n.calls[req.Method]++
+	var result interface{}
+	switch req.Method {
+	case "eth_blockNumber":
+		var head uint64
+		for number := range n.blocks {
+			if number > head {
+				head = number
+			}
+		}
+		result = fmt.Sprintf("0x%x", head)
+	case "eth_getBlockByNumber":
+		number, _ := strconv.ParseUint(strings.TrimPrefix(req.Params[0].(string), "0x"), 16, 64)
+		if block, ok := n.blocks[number]; ok {
+			result = block
+		}
+	case "eth_getTransactionByHash":
+		if tx, ok := n.txs[req.Params[0].(string)]; ok {
+			result = tx
+		}
+	}
+	n.mu.Unlock()
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"result":  result,
+	})
+}
+
+func (n *chainNode) serveWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			var msg struct {
+				Params []string `json:"params"`
+			}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			if len(msg.Params) > 0 {
+				n.subscribed <- msg.Params[0]
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		case msg := <-n.push:
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// startTracker connects a client to the node, optionally over its WebSocket,
+// and starts tracking ethereum with it.
+func startTracker(t *testing.T, n *chainNode, withWS bool, configure func(*blockchain.BlockTrackerConfig)) *blockchain.BlockTracker {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	evmConfig := &blockchain.EVMConfig{Chain: blockchain.ChainEthereum, RPCURL: n.URL}
+	if withWS {
+		evmConfig.WSURL = "ws" + strings.TrimPrefix(n.URL, "http")
+	}
+	client := blockchain.NewEVMClient(zap.NewNop(), evmConfig)
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	config := blockchain.DefaultBlockTrackerConfig()
+	config.EnableSolana = false
+	config.EVMChains = []string{"ethereum"}
+	config.PollInterval = 10 * time.Millisecond
+	if configure != nil {
+		configure(&config)
+	}
+
+	tracker := blockchain.NewBlockTracker(zap.NewNop(), nil,
+		map[string]*blockchain.EVMClient{"ethereum": client}, config)
+	if err := tracker.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	t.Cleanup(func() {
+		cancel()
+		client.Disconnect()
+	})
+	return tracker
+}
+
+// nextEvent returns the next tracker event other than a confirmation.
+func nextEvent(t *testing.T, tracker *blockchain.BlockTracker) blockchain.BlockEvent {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-tracker.Events():
+			if event.Type != blockchain.BlockEventConfirmed {
+				return event
+			}
+		case <-timeout:
+			t.Fatal("Timed out waiting for a block event")
+		}
+	}
+}
+
+// expectNewBlock fails unless the next event is a new block with the given
+// number and hash.
+func expectNewBlock(t *testing.T, tracker *blockchain.BlockTracker, number uint64, hash string) {
+	t.Helper()
+
+	event := nextEvent(t, tracker)
+	if event.Type != blockchain.BlockEventNew || event.Block.Number != number || event.Block.Hash != hash {
+		t.Fatalf("Expected new block %d %s, got %s %+v", number, hash, event.Type, event.Block)
+	}
+}
+
+// awaitStream pushes the first head until the tracker's new heads
+// subscription picks it up.
+func awaitStream(t *testing.T, n *chainNode, tracker *blockchain.BlockTracker, number uint64) {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		n.head(number)
+		select {
+		case event := <-tracker.Events():
+			if event.Type == blockchain.BlockEventNew && event.Block.Number == number {
+				return
+			}
+		case <-time.After(20 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("Timed out waiting for the new heads stream")
+		}
+	}
+}
+
+func TestBlockTrackerStreamsNewHeads(t *testing.T) {
+	node := newChainNode(t)
+	node.extend("a", 100, 103)
+	tracker := startTracker(t, node, true, func(config *blockchain.BlockTrackerConfig) {
+		config.PollInterval = time.Hour
+	})
+
+	awaitStream(t, node, tracker, 101)
+
+	// A head that skips a block reports the gap and fills it in order
+	node.head(103)
+	if event := nextEvent(t, tracker); event.Type != blockchain.BlockEventGap {
+		t.Fatalf("Expected a gap event, got %s", event.Type)
+	}
+	expectNewBlock(t, tracker, 102, blockHash("a", 102))
+	expectNewBlock(t, tracker, 103, blockHash("a", 103))
+
+	if calls := node.callCount("eth_blockNumber"); calls != 0 {
+		t.Errorf("Expected streamed heads without polling, got %d eth_blockNumber calls", calls)
+	}
+}
+
+func TestBlockTrackerRollsBackReorgedBlocks(t *testing.T) {
+	node := newChainNode(t)
+	node.extend("a", 100, 104)
+	tracker := startTracker(t, node, true, func(config *blockchain.BlockTrackerConfig) {
+		config.PollInterval = time.Hour
+		config.Confirmations = map[string]int{"ethereum": 2}
+	})
+
+	awaitStream(t, node, tracker, 101)
+	node.head(104)
+	nextEvent(t, tracker) // Gap
+	for number := uint64(102); number <= 104; number++ {
+		expectNewBlock(t, tracker, number, blockHash("a", number))
+	}
+
+	// Blocks 103 and 104 are replaced by a fork off 102
+	node.extend("b", 103, 104)
+	node.head(104)
+
+	event := nextEvent(t, tracker)
+	if event.Type != blockchain.BlockEventReorg {
+		t.Fatalf("Expected a reorg event, got %s", event.Type)
+	}
+	reorg := event.Reorg
+	if reorg.OldHead != 104 || reorg.NewHead != 104 || reorg.Depth != 2 {
+		t.Errorf("Expected a depth 2 reorg at 104, got %+v", reorg)
+	}
+	if !reflect.DeepEqual(reorg.AffectedBlocks, []uint64{103, 104}) {
+		t.Errorf("Expected blocks 103 and 104 orphaned, got %v", reorg.AffectedBlocks)
+	}
+
+	// The canonical blocks are replayed from the common ancestor
+	expectNewBlock(t, tracker, 103, blockHash("b", 103))
+	expectNewBlock(t, tracker, 104, blockHash("b", 104))
+
+	var hashes []string
+	for _, block := range tracker.GetRecentBlocks("ethereum", 10) {
+		hashes = append(hashes, block.Hash)
+	}
+	want := []string{blockHash("b", 104), blockHash("b", 103), blockHash("a", 102), blockHash("a", 101)}
+	if !reflect.DeepEqual(hashes, want) {
+		t.Errorf("Expected history without orphaned blocks\n got %v\nwant %v", hashes, want)
+	}
+
+	state := tracker.GetChainState("ethereum")
+	if state.LatestBlock != 104 || state.ConfirmedBlock != 102 || state.ReorgCount != 1 {
+		t.Errorf("Expected head 104 confirmed to 102 after one reorg, got %+v", state)
+	}
+}
+
+func TestBlockTrackerDetectsReorgWhilePolling(t *testing.T) {
+	node := newChainNode(t)
+	node.extend("a", 100, 102)
+	tracker := startTracker(t, node, false, nil)
+
+	expectNewBlock(t, tracker, 102, blockHash("a", 102))
+
+	// Block 102 is replaced as 103 arrives on top of the new fork
+	node.extend("b", 102, 103)
+
+	event := nextEvent(t, tracker)
+	if event.Type != blockchain.BlockEventReorg {
+		t.Fatalf("Expected a reorg event, got %s", event.Type)
+	}
+	if event.Reorg.Depth != 1 || !reflect.DeepEqual(event.Reorg.AffectedBlocks, []uint64{102}) {
+		t.Errorf("Expected block 102 orphaned, got %+v", event.Reorg)
+	}
+	expectNewBlock(t, tracker, 102, blockHash("b", 102))
+	expectNewBlock(t, tracker, 103, blockHash("b", 103))
+}
+
+func TestBlockTrackerMonitorsMempool(t *testing.T) {
+	node := newChainNode(t)
+	node.extend("a", 100, 100)
+	tracker := startTracker(t, node, true, func(config *blockchain.BlockTrackerConfig) {
+		config.PollInterval = time.Hour
+		config.MempoolChains = []string{"ethereum"}
+		config.MempoolWindow = 600 * time.Millisecond
+	})
+
+	timeout := time.After(5 * time.Second)
+	for subscription := ""; subscription != "newPendingTransactions"; {
+		select {
+		case subscription = <-node.subscribed:
+		case <-timeout:
+			t.Fatal("Timed out waiting for the pending transactions subscription")
+		}
+	}
+
+	node.pending("0xa1", 5e9)
+	node.pending("0xa2", 30e9)
+	node.pending("0xa3", 30e9)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		state := tracker.GetChainState("ethereum")
+		if state.PendingTxCount == 3 {
+			counts := make(map[float64]int)
+			for _, bucket := range state.PendingGasHistogram {
+				counts[bucket.MinGwei] = bucket.Count
+			}
+			if counts[5] != 1 || counts[20] != 2 {
+				t.Errorf("Expected one pending tx from 5 gwei and two from 20 gwei, got %+v", state.PendingGasHistogram)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected 3 pending transactions, got %d", state.PendingTxCount)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBlockTrackerRecordsWhaleTransfersAndExchangeFlows(t *testing.T) {
+	eth := func(n int64) string {
+		return "0x" + decimal.New(n, 18).BigInt().Text(16)
+	}
+	node := newChainNode(t)
+	node.extend("a", 100, 101,
+		// A whale deposits 50 ETH to an exchange
+		map[string]interface{}{"hash": "0x1", "from": "0x00000000000000000000000000000000000000aa", "to": binanceHotWallet, "value": eth(50), "gasPrice": "0x2540be400"},
+		// The exchange pays out 20 ETH
+		map[string]interface{}{"hash": "0x2", "from": binanceHotWallet, "to": "0x00000000000000000000000000000000000000bb", "value": eth(20), "gasPrice": "0x2540be400"},
+		// A small swap paying 90 gwei
+		map[string]interface{}{"hash": "0x3", "from": "0x00000000000000000000000000000000000000cc", "to": uniswapV2Router, "value": eth(1), "gasPrice": "0x14f46b0400"},
+	)
+	tracker := startTracker(t, node, false, nil)
+
+	expectNewBlock(t, tracker, 101, blockHash("a", 101))
+
+	block := tracker.GetRecentBlocks("ethereum", 1)[0]
+	if block.TransactionCount != 3 || block.LargeTransfers != 2 || !block.TotalVolume.Equal(decimal.NewFromInt(70)) {
+		t.Errorf("Expected 2 of 3 transfers large for 70 ETH, got %d of %d for %s",
+			block.LargeTransfers, block.TransactionCount, block.TotalVolume)
+	}
+	if !block.ExchangeInflow.Equal(decimal.NewFromInt(50)) || !block.ExchangeOutflow.Equal(decimal.NewFromInt(20)) {
+		t.Errorf("Expected 50 ETH in and 20 ETH out of exchanges, got %s in and %s out",
+			block.ExchangeInflow, block.ExchangeOutflow)
+	}
+	if block.DEXTransactions != 1 || !block.MEVDetected {
+		t.Errorf("Expected the high-gas swap counted and flagged as MEV, got %d DEX, MEV %v",
+			block.DEXTransactions, block.MEVDetected)
+	}
+	if len(block.PriorityFees) != 3 {
+		t.Errorf("Expected a tip per transaction, got %v", block.PriorityFees)
+	}
+
+	flows := tracker.GetFlowMetrics("ethereum", 0)
+	if flows == nil || flows.Blocks != 1 || !flows.NetExchangeFlow.Equal(decimal.NewFromInt(30)) {
+		t.Errorf("Expected a net 30 ETH exchange inflow over one block, got %+v", flows)
+	}
+}
@@ -3,6 +3,13 @@ package blockchain_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -16,10 +23,9 @@ import (
 func TestSolanaClientCreation(t *testing.T) {
 	logger := zap.NewNop()
 	
-	client := blockchain.NewSolanaClient(logger, blockchain.SolanaConfig{
-		RPCURL:    "https://api.mainnet-beta.solana.com",
-		WSURL:     "wss://api.mainnet-beta.solana.com",
-		RateLimit: 10,
+	client := blockchain.NewSolanaClient(logger, &blockchain.SolanaConfig{
+		RPCURL: "https://api.mainnet-beta.solana.com",
+		WSURL:  "wss://api.mainnet-beta.solana.com",
 	})
 	
 	if client == nil {
@@ -34,9 +40,8 @@ func TestSolanaGetSlot(t *testing.T) {
 	
 	logger := zap.NewNop()
 	
-	client := blockchain.NewSolanaClient(logger, blockchain.SolanaConfig{
-		RPCURL:    "https://api.mainnet-beta.solana.com",
-		RateLimit: 5, // Conservative rate limit
+	client := blockchain.NewSolanaClient(logger, &blockchain.SolanaConfig{
+		RPCURL: "https://api.mainnet-beta.solana.com",
 	})
 	
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -62,9 +67,8 @@ func TestSolanaGetBalance(t *testing.T) {
 	
 	logger := zap.NewNop()
 	
-	client := blockchain.NewSolanaClient(logger, blockchain.SolanaConfig{
-		RPCURL:    "https://api.mainnet-beta.solana.com",
-		RateLimit: 5,
+	client := blockchain.NewSolanaClient(logger, &blockchain.SolanaConfig{
+		RPCURL: "https://api.mainnet-beta.solana.com",
 	})
 	
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -84,14 +88,14 @@ func TestSolanaGetBalance(t *testing.T) {
 func TestEVMClientCreation(t *testing.T) {
 	logger := zap.NewNop()
 	
-	configs := map[string]blockchain.EVMConfig{
-		"ethereum": {RPCURL: "https://eth.llamarpc.com", ChainID: 1},
-		"polygon":  {RPCURL: "https://polygon-rpc.com", ChainID: 137},
-		"arbitrum": {RPCURL: "https://arb1.arbitrum.io/rpc", ChainID: 42161},
+	configs := map[string]*blockchain.EVMConfig{
+		"ethereum": {Chain: blockchain.ChainEthereum, RPCURL: "https://eth.llamarpc.com"},
+		"polygon":  {Chain: blockchain.ChainPolygon, RPCURL: "https://polygon-rpc.com"},
+		"arbitrum": {Chain: blockchain.ChainArbitrum, RPCURL: "https://arb1.arbitrum.io/rpc"},
 	}
 	
 	for name, config := range configs {
-		client := blockchain.NewEVMClient(logger, name, config)
+		client := blockchain.NewEVMClient(logger, config)
 		if client == nil {
 			t.Errorf("Client for %s is nil", name)
 		}
@@ -105,10 +109,9 @@ func TestEVMGetBlockNumber(t *testing.T) {
 	
 	logger := zap.NewNop()
 	
-	client := blockchain.NewEVMClient(logger, "ethereum", blockchain.EVMConfig{
-		RPCURL:    "https://eth.llamarpc.com",
-		ChainID:   1,
-		RateLimit: 5,
+	client := blockchain.NewEVMClient(logger, &blockchain.EVMConfig{
+		Chain:  blockchain.ChainEthereum,
+		RPCURL: "https://eth.llamarpc.com",
 	})
 	
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -134,10 +137,9 @@ func TestEVMGetBalance(t *testing.T) {
 	
 	logger := zap.NewNop()
 	
-	client := blockchain.NewEVMClient(logger, "ethereum", blockchain.EVMConfig{
-		RPCURL:    "https://eth.llamarpc.com",
-		ChainID:   1,
-		RateLimit: 5,
+	client := blockchain.NewEVMClient(logger, &blockchain.EVMConfig{
+		Chain:  blockchain.ChainEthereum,
+		RPCURL: "https://eth.llamarpc.com",
 	})
 	
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -163,17 +165,17 @@ func TestMultiChainSupport(t *testing.T) {
 	
 	chains := []struct {
 		name    string
-		config  blockchain.EVMConfig
+		config  *blockchain.EVMConfig
 		address string
 	}{
 		{
 			name:    "ethereum",
-			config:  blockchain.EVMConfig{RPCURL: "https://eth.llamarpc.com", ChainID: 1, RateLimit: 3},
+			config:  &blockchain.EVMConfig{Chain: blockchain.ChainEthereum, RPCURL: "https://eth.llamarpc.com"},
 			address: "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045",
 		},
 		{
 			name:    "polygon",
-			config:  blockchain.EVMConfig{RPCURL: "https://polygon-rpc.com", ChainID: 137, RateLimit: 3},
+			config:  &blockchain.EVMConfig{Chain: blockchain.ChainPolygon, RPCURL: "https://polygon-rpc.com"},
 			address: "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045",
 		},
 	}
@@ -182,7 +184,7 @@ func TestMultiChainSupport(t *testing.T) {
 	defer cancel()
 	
 	for _, chain := range chains {
-		client := blockchain.NewEVMClient(logger, chain.name, chain.config)
+		client := blockchain.NewEVMClient(logger, chain.config)
 		
 		blockNum, err := client.GetBlockNumber(ctx)
 		if err != nil {
@@ -197,9 +199,9 @@ func TestMultiChainSupport(t *testing.T) {
 func TestDEXDetection(t *testing.T) {
 	logger := zap.NewNop()
 	
-	client := blockchain.NewEVMClient(logger, "ethereum", blockchain.EVMConfig{
-		RPCURL:  "https://eth.llamarpc.com",
-		ChainID: 1,
+	client := blockchain.NewEVMClient(logger, &blockchain.EVMConfig{
+		Chain:  blockchain.ChainEthereum,
+		RPCURL: "https://eth.llamarpc.com",
 	})
 	
 	// Known DEX router addresses
@@ -226,9 +228,9 @@ func TestDEXDetection(t *testing.T) {
 func TestMEVDetection(t *testing.T) {
 	logger := zap.NewNop()
 	
-	client := blockchain.NewEVMClient(logger, "ethereum", blockchain.EVMConfig{
-		RPCURL:  "https://eth.llamarpc.com",
-		ChainID: 1,
+	client := blockchain.NewEVMClient(logger, &blockchain.EVMConfig{
+		Chain:  blockchain.ChainEthereum,
+		RPCURL: "https://eth.llamarpc.com",
 	})
 	
 	// Create test transactions
@@ -280,3 +282,104 @@ func TestWebSocketSubscription(t *testing.T) {
 	// In production, we'd test against a local node or mock
 	t.Skip("WebSocket subscription test requires running node")
 }
+
+// fakeEVMNode serves a chain that grows one block per eth_blockNumber call and
+// counts the RPC calls it receives.
+type fakeEVMNode struct {
+	mu    sync.Mutex
+	head  uint64
+	calls int
+}
+
+func (n *fakeEVMNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Method string        `json:"method"`
+		Params []interface{} `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	
+	n.mu.Lock()
+	n.calls++
+	var result interface{}
+	switch req.Method {
+	case "eth_blockNumber":
+		n.head++
+		result = fmt.Sprintf("0x%x", n.head)
+	case "eth_getBlockByNumber":
+		num, _ := strconv.ParseUint(strings.TrimPrefix(req.Params[0].(string), "0x"), 16, 64)
+		result = map[string]interface{}{
+			"number":       fmt.Sprintf("0x%x", num),
+			"hash":         fmt.Sprintf("0x%064x", num),
+			"parentHash":   fmt.Sprintf("0x%064x", num-1),
+			"timestamp":    fmt.Sprintf("0x%x", 1700000000+num*12),
+			"gasUsed":      "0x0",
+			"gasLimit":     "0x1c9c380",
+			"transactions": []interface{}{},
+		}
+	}
+	n.mu.Unlock()
+	
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"result":  result,
+	})
+}
+
+func (n *fakeEVMNode) callCount() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.calls
+}
+
+// BenchmarkBlockTrackerRPCPerBlock reports RPC calls per tracked block. Reorg
+// detection checks parent-hash linkage of new blocks only, so this stays near
+// two (eth_blockNumber plus eth_getBlockByNumber) however deep the history is,
+// where re-fetching every recent block each poll cost HistoryDepth more.
+func BenchmarkBlockTrackerRPCPerBlock(b *testing.B) {
+	node := &fakeEVMNode{head: 1000}
+	server := httptest.NewServer(node)
+	defer server.Close()
+	
+	client := blockchain.NewEVMClient(zap.NewNop(), &blockchain.EVMConfig{
+		Chain:  blockchain.ChainEthereum,
+		RPCURL: server.URL,
+	})
+	
+	config := blockchain.DefaultBlockTrackerConfig()
+	config.EnableSolana = false
+	config.EVMChains = []string{"ethereum"}
+	config.PollInterval = time.Millisecond
+	config.HistoryDepth = 100
+	
+	tracker := blockchain.NewBlockTracker(zap.NewNop(), nil,
+		map[string]*blockchain.EVMClient{"ethereum": client}, config)
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	
+	b.ResetTimer()
+	if err := tracker.Start(ctx); err != nil {
+		b.Fatal(err)
+	}
+	
+	blocks := 0
+	for event := range tracker.Events() {
+		if event.Type == blockchain.BlockEventReorg {
+			b.Fatalf("unexpected reorg at block %d", event.Reorg.NewHead)
+		}
+		if event.Type == blockchain.BlockEventNew {
+			blocks++
+			if blocks >= b.N {
+				break
+			}
+		}
+	}
+	cancel()
+	b.StopTimer()
+	
+	b.ReportMetric(float64(node.callCount())/float64(blocks), "rpc/block")
+}
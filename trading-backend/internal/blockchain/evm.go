@@ -44,6 +44,7 @@ type EVMClient struct {
 	currentBlock   uint64
 	blockCallbacks []func(*events.BlockEvent)
 	txCallbacks    []func(*events.MempoolEvent)
+	headSubs       []chan *events.BlockEvent
 	
 	// Connection state
 	connected bool
@@ -125,6 +126,11 @@ func (c *EVMClient) Disconnect() {
 		c.wsConn = nil
 	}
 	
+	for _, heads := range c.headSubs {
+		close(heads)
+	}
+	c.headSubs = nil
+	
 	c.connected = false
 	c.logger.Info("Disconnected from EVM chain", zap.String("chain", string(c.chain)))
 }
@@ -136,6 +142,60 @@ func (c *EVMClient) IsConnected() bool {
 	return c.connected
 }
 
+// HasWebSocket reports whether the client has a live WebSocket connection
+func (c *EVMClient) HasWebSocket() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.wsConn != nil
+}
+
+// SubscribeNewHeads streams new block headers from the eth_subscribe("newHeads")
+// subscription opened by Connect. Heads arrive in order; the channel is closed
+// when ctx is done or the WebSocket connection drops.
+func (c *EVMClient) SubscribeNewHeads(ctx context.Context) (<-chan *events.BlockEvent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	if c.wsConn == nil || !c.connected {
+		return nil, fmt.Errorf("not connected")
+	}
+	
+	heads := make(chan *events.BlockEvent, 64)
+	c.headSubs = append(c.headSubs, heads)
+	
+	go func() {
+		<-ctx.Done()
+		c.removeHeadSub(heads)
+	}()
+	
+	return heads, nil
+}
+
+// removeHeadSub unregisters and closes a new heads channel
+func (c *EVMClient) removeHeadSub(heads chan *events.BlockEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	for i, sub := range c.headSubs {
+		if sub == heads {
+			c.headSubs = append(c.headSubs[:i], c.headSubs[i+1:]...)
+			close(heads)
+			return
+		}
+	}
+}
+
+// closeHeadSubs closes every new heads channel
+func (c *EVMClient) closeHeadSubs() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	for _, heads := range c.headSubs {
+		close(heads)
+	}
+	c.headSubs = nil
+}
+
 // GetCurrentBlock returns the current block number
 func (c *EVMClient) GetCurrentBlock() uint64 {
 	c.mu.RLock()
@@ -216,6 +276,94 @@ func (c *EVMClient) GetBlock(ctx context.Context, blockNumber uint64) (*events.B
 	}, nil
 }
 
+// EVMTransaction is a transaction included in a block. Amounts are base-10
+// strings in wei.
+type EVMTransaction struct {
+	Hash                 string
+	From                 string
+	To                   string
+	Value                string
+	GasPrice             string
+	MaxFeePerGas         string
+	MaxPriorityFeePerGas string
+}
+
+// EVMBlock is a block with its transactions
+type EVMBlock struct {
+	Number        uint64
+	Hash          string
+	ParentHash    string
+	Timestamp     uint64
+	GasUsed       uint64
+	GasLimit      uint64
+	BaseFeePerGas decimal.Decimal
+	Transactions  []EVMTransaction
+}
+
+// GetBlockWithTransactions fetches a block by number with its full transactions
+func (c *EVMClient) GetBlockWithTransactions(ctx context.Context, blockNumber uint64) (*EVMBlock, error) {
+	blockHex := fmt.Sprintf("0x%x", blockNumber)
+	
+	resp, err := c.rpcCall(ctx, "eth_getBlockByNumber", []interface{}{blockHex, true})
+	if err != nil {
+		return nil, err
+	}
+	
+	result, ok := resp["result"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid response format")
+	}
+	
+	hash, _ := result["hash"].(string)
+	parentHash, _ := result["parentHash"].(string)
+	block := &EVMBlock{
+		Number:     blockNumber,
+		Hash:       hash,
+		ParentHash: parentHash,
+	}
+	if ts, ok := result["timestamp"].(string); ok {
+		block.Timestamp = hexToUint64(ts)
+	}
+	if gu, ok := result["gasUsed"].(string); ok {
+		block.GasUsed = hexToUint64(gu)
+	}
+	if gl, ok := result["gasLimit"].(string); ok {
+		block.GasLimit = hexToUint64(gl)
+	}
+	if bf, ok := result["baseFeePerGas"].(string); ok {
+		block.BaseFeePerGas = hexToDecimal(bf)
+	}
+	
+	txs, _ := result["transactions"].([]interface{})
+	for _, raw := range txs {
+		fields, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		
+		tx := EVMTransaction{}
+		tx.Hash, _ = fields["hash"].(string)
+		tx.From, _ = fields["from"].(string)
+		tx.To, _ = fields["to"].(string)
+		
+		// Convert hex quantities to base-10 wei
+		for key, dst := range map[string]*string{
+			"value":                &tx.Value,
+			"gasPrice":             &tx.GasPrice,
+			"maxFeePerGas":         &tx.MaxFeePerGas,
+			"maxPriorityFeePerGas": &tx.MaxPriorityFeePerGas,
+		} {
+			if v, ok := fields[key].(string); ok {
+				*dst = hexToBigInt(v).String()
+			}
+		}
+		
+		block.Transactions = append(block.Transactions, tx)
+	}
+	
+	return block, nil
+}
+
 // GetTransaction fetches a transaction by hash
 func (c *EVMClient) GetTransaction(ctx context.Context, txHash string) (*events.MempoolEvent, error) {
 	resp, err := c.rpcCall(ctx, "eth_getTransactionByHash", []interface{}{txHash})
@@ -355,6 +503,7 @@ func (c *EVMClient) handleMessages() {
 				c.mu.Lock()
 				c.connected = false
 				c.mu.Unlock()
+				c.closeHeadSubs()
 				return
 			}
 			
@@ -425,6 +574,19 @@ func (c *EVMClient) handleNewBlock(block map[string]interface{}) {
 		BaseFee:     baseFee,
 	}
 	
+	// Feed head subscribers in order, dropping rather than stalling the reader
+	c.mu.RLock()
+	for _, heads := range c.headSubs {
+		select {
+		case heads <- blockEvent:
+		default:
+			c.logger.Warn("New heads buffer full, dropping head",
+				zap.String("chain", string(c.chain)),
+				zap.Uint64("block", blockNumber))
+		}
+	}
+	c.mu.RUnlock()
+	
 	// Notify callbacks
 	for _, cb := range callbacks {
 		go cb(blockEvent)
@@ -457,31 +619,49 @@ func (c *EVMClient) handlePendingTx(txHash string) {
 	}
 }
 
+// dexRouters are known DEX router addresses (lowercase)
+var dexRouters = map[string]bool{
+	"0x7a250d5630b4cf539739df2c5dacb4c659f2488d": true, // Uniswap V2
+	"0xe592427a0aece92de3edee1f18e0157c05861564": true, // Uniswap V3
+	"0xd9e1ce17f2641f24ae83637ab66a2cca9c378b9f": true, // SushiSwap
+}
+
+// avgGasPrice is the gas price, in wei, that MEV checks treat as typical
+const avgGasPrice = uint64(30e9) // 30 gwei
+
+// IsDEXRouter reports whether address is a known DEX router
+func (c *EVMClient) IsDEXRouter(address string) bool {
+	return dexRouters[strings.ToLower(address)]
+}
+
 // checkMEVIndicators checks if a transaction might be MEV-related
 func (c *EVMClient) checkMEVIndicators(tx *events.MempoolEvent) bool {
 	// High gas price might indicate MEV
 	// This is a simplified check - production would be more sophisticated
-	avgGasPrice := uint64(30e9) // 30 gwei average
-	
 	if tx.GasPrice > avgGasPrice*2 {
 		return true
 	}
 	
 	// Check for known DEX router addresses
-	dexRouters := []string{
-		"0x7a250d5630b4cf539739df2c5dacb4c659f2488d", // Uniswap V2
-		"0xe592427a0aece92de3edee1f18e0157c05861564", // Uniswap V3
-		"0xd9e1ce17f2641f24ae83637ab66a2cca9c378b9f", // SushiSwap
+	return c.IsDEXRouter(tx.To)
+}
+
+// CalculateMEVRisk scores how likely an included transaction is MEV (0-1).
+// DEX trades score 0.5, and gas paid above the typical price adds up to 0.5
+// more at twice the typical price.
+func (c *EVMClient) CalculateMEVRisk(tx *EVMTransaction) float64 {
+	score := 0.0
+	if c.IsDEXRouter(tx.To) {
+		score += 0.5
 	}
 	
-	toLower := strings.ToLower(tx.To)
-	for _, router := range dexRouters {
-		if toLower == router {
-			return true
-		}
+	gasPrice, err := decimal.NewFromString(tx.GasPrice)
+	if err == nil {
+		premium, _ := gasPrice.Div(decimal.NewFromInt(int64(avgGasPrice))).Sub(decimal.NewFromInt(1)).Float64()
+		score += 0.5 * clampUnit(premium)
 	}
 	
-	return false
+	return clampUnit(score)
 }
 
 // rpcCall makes an RPC call to the EVM node
@@ -0,0 +1,126 @@
+// Package blockchain provides EIP-1559 fee estimation from observed blocks.
+package blockchain
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// GasPriceEstimate is a suggested EIP-1559 fee pair in wei. On chains
+// without a base fee MaxFeePerGas equals MaxPriorityFeePerGas and serves as
+// a legacy gas price.
+type GasPriceEstimate struct {
+	BaseFee              decimal.Decimal `json:"baseFee"`          // Base fee of the latest block
+	ProjectedBaseFee     decimal.Decimal `json:"projectedBaseFee"` // Base fee expected in the next block
+	MaxPriorityFeePerGas decimal.Decimal `json:"maxPriorityFeePerGas"`
+	MaxFeePerGas         decimal.Decimal `json:"maxFeePerGas"`
+}
+
+const (
+	// baseFeeChangeDenominator bounds the base fee change per block to 1/8
+	baseFeeChangeDenominator = 8
+
+	// maxHeadroomBlocks is how many consecutive full blocks the max fee of
+	// the most urgent transactions survives
+	maxHeadroomBlocks = 6
+)
+
+var (
+	// maxBaseFeeIncrease is the largest per-block base fee rise (12.5%)
+	maxBaseFeeIncrease = decimal.NewFromInt(1).Add(decimal.NewFromInt(1).Div(decimal.NewFromInt(baseFeeChangeDenominator)))
+
+	// fallbackPriorityFee is suggested when no tips have been observed
+	fallbackPriorityFee = decimal.NewFromInt(1e9) // 1 gwei
+)
+
+// nextBaseFee projects the base fee of the block after one with the given
+// base fee and gas usage, following the EIP-1559 update rule against a
+// target of half the gas limit.
+func nextBaseFee(baseFee decimal.Decimal, gasUsed, gasLimit uint64) decimal.Decimal {
+	target := gasLimit / 2
+	if target == 0 || baseFee.IsZero() {
+		return baseFee
+	}
+
+	delta := decimal.NewFromInt(int64(gasUsed) - int64(target)).
+		Div(decimal.NewFromInt(int64(target))).
+		Div(decimal.NewFromInt(baseFeeChangeDenominator))
+	return baseFee.Add(baseFee.Mul(delta)).Ceil()
+}
+
+// effectiveTip returns the priority fee a transaction paid per gas in a
+// block with the given base fee. Dynamic-fee transactions pay their priority
+// fee capped by what their max fee leaves over the base fee; legacy
+// transactions pay whatever their gas price exceeds it by.
+func effectiveTip(baseFee, gasPrice, maxFee, maxPriorityFee decimal.Decimal) decimal.Decimal {
+	var tip decimal.Decimal
+	if !maxFee.IsZero() {
+		tip = decimal.Min(maxPriorityFee, maxFee.Sub(baseFee))
+	} else {
+		tip = gasPrice.Sub(baseFee)
+	}
+	if tip.IsNegative() {
+		return decimal.Zero
+	}
+	return tip
+}
+
+// estimateGasPrice builds a fee suggestion from recent blocks, oldest first.
+// The tip is the urgency percentile (0-1) of priority fees observed across
+// the blocks, and the max fee covers the projected base fee rising by the
+// maximum 12.5% for up to maxHeadroomBlocks blocks as urgency increases, so
+// urgent transactions stay includable through a run of full blocks. Only the
+// base fee plus tip is charged, so the headroom costs nothing when unused.
+func estimateGasPrice(blocks []*BlockInfo, urgency float64) GasPriceEstimate {
+	if len(blocks) == 0 {
+		return GasPriceEstimate{}
+	}
+	urgency = clampUnit(urgency)
+
+	latest := blocks[len(blocks)-1]
+	estimate := GasPriceEstimate{
+		BaseFee:          latest.BaseFeePerGas,
+		ProjectedBaseFee: nextBaseFee(latest.BaseFeePerGas, latest.GasUsed, latest.GasLimit),
+	}
+
+	var tips []decimal.Decimal
+	for _, block := range blocks {
+		tips = append(tips, block.PriorityFees...)
+	}
+	if len(tips) == 0 {
+		estimate.MaxPriorityFeePerGas = fallbackPriorityFee
+	} else {
+		estimate.MaxPriorityFeePerGas = percentile(tips, urgency)
+	}
+
+	headroom := int(urgency * float64(maxHeadroomBlocks-1))
+	maxBaseFee := estimate.ProjectedBaseFee
+	for i := 0; i < headroom; i++ {
+		maxBaseFee = maxBaseFee.Mul(maxBaseFeeIncrease)
+	}
+	estimate.MaxFeePerGas = maxBaseFee.Ceil().Add(estimate.MaxPriorityFeePerGas)
+
+	return estimate
+}
+
+// percentile returns the value at percentile p (0-1) of values, sorting a
+// copy.
+func percentile(values []decimal.Decimal, p float64) decimal.Decimal {
+	sorted := make([]decimal.Decimal, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].LessThan(sorted[j]) })
+
+	return sorted[int(p*float64(len(sorted)-1))]
+}
+
+// clampUnit limits v to [0, 1].
+func clampUnit(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,152 @@
+// Package blockchain provides pending-transaction monitoring for EVM chains.
+package blockchain
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// gasPriceBucketEdges are the histogram bucket lower bounds in gwei.
+var gasPriceBucketEdges = []float64{0, 1, 2, 5, 10, 20, 50, 100, 200, 500}
+
+// GasPriceBucket counts pending transactions within a gas price range.
+type GasPriceBucket struct {
+	MinGwei float64 `json:"minGwei"`
+	MaxGwei float64 `json:"maxGwei,omitempty"` // Zero for the open-ended top bucket
+	Count   int     `json:"count"`
+}
+
+// CongestionInfo describes a mempool spike.
+type CongestionInfo struct {
+	Chain          string          `json:"chain"`
+	PendingTxCount int             `json:"pendingTxCount"`
+	Baseline       float64         `json:"baseline"`
+	MedianGasPrice decimal.Decimal `json:"medianGasPrice"`
+	Timestamp      time.Time       `json:"timestamp"`
+}
+
+// pendingTx is a pending transaction sighting.
+type pendingTx struct {
+	seen     time.Time
+	gasPrice uint64 // wei
+}
+
+// mempoolMonitor keeps a rolling window of the pending transactions seen on
+// one chain.
+type mempoolMonitor struct {
+	mu      sync.Mutex
+	window  time.Duration
+	samples []pendingTx // Oldest first
+
+	// Spike detection
+	baseline float64 // Moving average of the pending count
+	spiking  bool
+}
+
+// newMempoolMonitor creates a monitor over the given window.
+func newMempoolMonitor(window time.Duration) *mempoolMonitor {
+	return &mempoolMonitor{
+		window:  window,
+		samples: make([]pendingTx, 0),
+	}
+}
+
+// record adds a pending transaction sighting.
+func (m *mempoolMonitor) record(gasPrice uint64, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.samples = append(m.samples, pendingTx{seen: now, gasPrice: gasPrice})
+}
+
+// snapshot prunes sightings older than the window and returns the pending
+// count and gas price histogram.
+func (m *mempoolMonitor) snapshot(now time.Time) (int, []GasPriceBucket) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune(now)
+
+	histogram := make([]GasPriceBucket, len(gasPriceBucketEdges))
+	for i, edge := range gasPriceBucketEdges {
+		histogram[i].MinGwei = edge
+		if i+1 < len(gasPriceBucketEdges) {
+			histogram[i].MaxGwei = gasPriceBucketEdges[i+1]
+		}
+	}
+
+	for _, tx := range m.samples {
+		gwei := float64(tx.gasPrice) / 1e9
+		i := sort.SearchFloat64s(gasPriceBucketEdges, gwei)
+		// SearchFloat64s returns the first edge >= gwei; step back unless on an edge
+		if i == len(gasPriceBucketEdges) || gasPriceBucketEdges[i] > gwei {
+			i--
+		}
+		histogram[i].Count++
+	}
+
+	return len(m.samples), histogram
+}
+
+// gasPriceAt returns the pending gas price at the given percentile (0-1) in
+// wei, and false if no pending transactions are in the window.
+func (m *mempoolMonitor) gasPriceAt(percentile float64, now time.Time) (decimal.Decimal, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune(now)
+	if len(m.samples) == 0 {
+		return decimal.Zero, false
+	}
+
+	prices := make([]uint64, len(m.samples))
+	for i, tx := range m.samples {
+		prices[i] = tx.gasPrice
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i] < prices[j] })
+
+	index := int(percentile * float64(len(prices)-1))
+	return decimal.NewFromInt(int64(prices[index])), true
+}
+
+// checkSpike compares a pending count with the moving baseline, reporting
+// true only when the count first rises above factor times the baseline.
+func (m *mempoolMonitor) checkSpike(count int, factor float64) (bool, float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	baseline := m.baseline
+	spike := false
+
+	if baseline > 0 && float64(count) > baseline*factor {
+		spike = !m.spiking
+		m.spiking = true
+	} else {
+		m.spiking = false
+	}
+
+	// Exponential moving average, seeded with the first count
+	if m.baseline == 0 {
+		m.baseline = float64(count)
+	} else {
+		m.baseline = 0.8*m.baseline + 0.2*float64(count)
+	}
+
+	return spike, baseline
+}
+
+// prune drops sightings older than the window. Callers hold m.mu.
+func (m *mempoolMonitor) prune(now time.Time) {
+	cutoff := now.Add(-m.window)
+
+	drop := 0
+	for drop < len(m.samples) && m.samples[drop].seen.Before(cutoff) {
+		drop++
+	}
+	if drop > 0 {
+		m.samples = append(m.samples[:0], m.samples[drop:]...)
+	}
+}
@@ -0,0 +1,147 @@
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func gwei(n float64) uint64 {
+	return uint64(n * 1e9)
+}
+
+func TestMempoolMonitorRollingWindow(t *testing.T) {
+	m := newMempoolMonitor(time.Minute)
+	start := time.Now()
+
+	m.record(gwei(3), start)
+	m.record(gwei(10), start.Add(30*time.Second))
+	m.record(gwei(25), start.Add(40*time.Second))
+	m.record(gwei(600), start.Add(50*time.Second))
+
+	// The first sighting has left the window
+	count, histogram := m.snapshot(start.Add(70 * time.Second))
+	if count != 3 {
+		t.Fatalf("Expected 3 pending transactions in the window, got %d", count)
+	}
+
+	want := map[float64]int{10: 1, 20: 1, 500: 1}
+	for _, bucket := range histogram {
+		if bucket.Count != want[bucket.MinGwei] {
+			t.Errorf("Bucket from %v gwei has %d transactions, want %d", bucket.MinGwei, bucket.Count, want[bucket.MinGwei])
+		}
+	}
+	if top := histogram[len(histogram)-1]; top.MinGwei != 500 || top.MaxGwei != 0 {
+		t.Errorf("Expected an open-ended top bucket from 500 gwei, got %+v", top)
+	}
+
+	median, ok := m.gasPriceAt(0.5, start.Add(70*time.Second))
+	if !ok || !median.Equal(decimal.NewFromInt(int64(gwei(25)))) {
+		t.Errorf("Expected a 25 gwei median, got %s (%v)", median, ok)
+	}
+
+	if _, ok := m.gasPriceAt(0.5, start.Add(5*time.Minute)); ok {
+		t.Error("Expected no pending gas price once every sighting has expired")
+	}
+}
+
+func TestUpdateMempoolStateEmitsCongestionOnSpike(t *testing.T) {
+	config := DefaultBlockTrackerConfig()
+	config.EnableSolana = false
+	config.EVMChains = []string{"ethereum"}
+	config.MempoolChains = []string{"ethereum"}
+	bt := NewBlockTracker(zap.NewNop(), nil, nil, config)
+	bt.initializeStates()
+	mempool := bt.mempools["ethereum"]
+
+	record := func(n int, price uint64) {
+		for i := 0; i < n; i++ {
+			mempool.record(price, time.Now())
+		}
+	}
+
+	// The first update seeds the baseline
+	record(10, gwei(20))
+	bt.updateMempoolState("ethereum", mempool)
+	if state := bt.GetChainState("ethereum"); state.PendingTxCount != 10 || len(state.PendingGasHistogram) == 0 {
+		t.Fatalf("Expected 10 pending transactions with a histogram, got %+v", state)
+	}
+	if len(bt.events) != 0 {
+		t.Fatalf("Expected no congestion event while seeding the baseline, got %d events", len(bt.events))
+	}
+
+	record(30, gwei(80))
+	bt.updateMempoolState("ethereum", mempool)
+	if len(bt.events) != 1 {
+		t.Fatalf("Expected one congestion event, got %d", len(bt.events))
+	}
+	event := <-bt.events
+	if event.Type != BlockEventCongestion || event.Congestion == nil {
+		t.Fatalf("Expected a congestion event, got %+v", event)
+	}
+	if event.Congestion.PendingTxCount != 40 || event.Congestion.Baseline != 10 {
+		t.Errorf("Expected 40 pending against a baseline of 10, got %+v", event.Congestion)
+	}
+	if !event.Congestion.MedianGasPrice.Equal(decimal.NewFromInt(int64(gwei(80)))) {
+		t.Errorf("Expected an 80 gwei median, got %s", event.Congestion.MedianGasPrice)
+	}
+
+	// The same spike is reported once
+	bt.updateMempoolState("ethereum", mempool)
+	if len(bt.events) != 0 {
+		t.Errorf("Expected no repeat congestion event, got %d", len(bt.events))
+	}
+}
+
+func TestGetOptimalGasPriceCompetesWithMempool(t *testing.T) {
+	config := DefaultBlockTrackerConfig()
+	config.EnableSolana = false
+	config.EVMChains = []string{"ethereum", "polygon"}
+	config.MempoolChains = []string{"ethereum"}
+	bt := NewBlockTracker(zap.NewNop(), nil, nil, config)
+	bt.initializeStates()
+
+	// Half-full blocks keep the base fee at 10 gwei
+	for _, chain := range config.EVMChains {
+		bt.recordBlock(chain, &BlockInfo{
+			Chain:         chain,
+			Number:        1,
+			GasUsed:       15_000_000,
+			GasLimit:      30_000_000,
+			BaseFeePerGas: decimal.NewFromInt(int64(gwei(10))),
+			PriorityFees:  []decimal.Decimal{decimal.NewFromInt(int64(gwei(1))), decimal.NewFromInt(int64(gwei(2)))},
+		})
+	}
+
+	tests := []struct {
+		name    string
+		chain   string
+		pending []uint64
+		tip     uint64
+		maxFee  uint64
+	}{
+		{name: "no pending transactions", chain: "ethereum", tip: gwei(1), maxFee: gwei(11)},
+		{name: "pending below recent tips", chain: "ethereum", pending: []uint64{gwei(10.5)}, tip: gwei(1), maxFee: gwei(11)},
+		{name: "pending above recent tips", chain: "ethereum", pending: []uint64{gwei(30)}, tip: gwei(20), maxFee: gwei(30)},
+		{name: "chain without monitoring", chain: "polygon", tip: gwei(1), maxFee: gwei(11)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bt.mempools["ethereum"] = newMempoolMonitor(time.Minute)
+			for _, price := range tt.pending {
+				bt.mempools["ethereum"].record(price, time.Now())
+			}
+
+			estimate := bt.GetOptimalGasPrice(tt.chain, 0)
+			if !estimate.MaxPriorityFeePerGas.Equal(decimal.NewFromInt(int64(tt.tip))) {
+				t.Errorf("MaxPriorityFeePerGas = %s, want %d", estimate.MaxPriorityFeePerGas, tt.tip)
+			}
+			if !estimate.MaxFeePerGas.Equal(decimal.NewFromInt(int64(tt.maxFee))) {
+				t.Errorf("MaxFeePerGas = %s, want %d", estimate.MaxFeePerGas, tt.maxFee)
+			}
+		})
+	}
+}
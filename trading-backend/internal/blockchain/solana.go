@@ -3,6 +3,7 @@ package blockchain
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -28,6 +29,7 @@ type SolanaClient struct {
 	currentSlot    uint64
 	blockCallbacks []func(*events.BlockEvent)
 	txCallbacks    []func(*events.MempoolEvent)
+	slotSubs       []chan uint64
 	
 	// Connection state
 	connected bool
@@ -98,6 +100,11 @@ func (c *SolanaClient) Disconnect() {
 		c.wsConn = nil
 	}
 	
+	for _, slots := range c.slotSubs {
+		close(slots)
+	}
+	c.slotSubs = nil
+	
 	c.connected = false
 	c.logger.Info("Disconnected from Solana")
 }
@@ -109,6 +116,53 @@ func (c *SolanaClient) IsConnected() bool {
 	return c.connected
 }
 
+// SubscribeSlots streams slots from the slotSubscribe subscription opened by
+// Connect. The channel is closed when ctx is done or the WebSocket connection
+// drops.
+func (c *SolanaClient) SubscribeSlots(ctx context.Context) (<-chan uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	if c.wsConn == nil || !c.connected {
+		return nil, fmt.Errorf("not connected")
+	}
+	
+	slots := make(chan uint64, 64)
+	c.slotSubs = append(c.slotSubs, slots)
+	
+	go func() {
+		<-ctx.Done()
+		c.removeSlotSub(slots)
+	}()
+	
+	return slots, nil
+}
+
+// removeSlotSub unregisters and closes a slot channel
+func (c *SolanaClient) removeSlotSub(slots chan uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	for i, sub := range c.slotSubs {
+		if sub == slots {
+			c.slotSubs = append(c.slotSubs[:i], c.slotSubs[i+1:]...)
+			close(slots)
+			return
+		}
+	}
+}
+
+// closeSlotSubs closes every slot channel
+func (c *SolanaClient) closeSlotSubs() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	for _, slots := range c.slotSubs {
+		close(slots)
+	}
+	c.slotSubs = nil
+}
+
 // GetCurrentSlot returns the current slot number
 func (c *SolanaClient) GetCurrentSlot() uint64 {
 	c.mu.RLock()
@@ -283,6 +337,176 @@ func (c *SolanaClient) GetTokenBalance(ctx context.Context, tokenAccount string)
 	return decimal.NewFromFloat(uiAmount), nil
 }
 
+// GetTokenBalanceByOwner sums an owner's SPL token accounts for a mint
+func (c *SolanaClient) GetTokenBalanceByOwner(ctx context.Context, owner, mint string) (decimal.Decimal, error) {
+	req := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "getTokenAccountsByOwner",
+		"params": []interface{}{
+			owner,
+			map[string]string{"mint": mint},
+			map[string]string{"encoding": "jsonParsed"},
+		},
+	}
+	
+	resp, err := c.rpcCall(ctx, req)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	
+	result, ok := resp["result"].(map[string]interface{})
+	if !ok {
+		return decimal.Zero, fmt.Errorf("invalid response format")
+	}
+	
+	accounts, _ := result["value"].([]interface{})
+	total := decimal.Zero
+	for _, a := range accounts {
+		amount, err := parsedTokenAmount(a)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		total = total.Add(amount)
+	}
+	
+	return total, nil
+}
+
+// parsedTokenAmount reads the UI amount of a jsonParsed token account
+func parsedTokenAmount(account interface{}) (decimal.Decimal, error) {
+	var value interface{} = account
+	for _, key := range []string{"account", "data", "parsed", "info", "tokenAmount", "uiAmountString"} {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return decimal.Zero, fmt.Errorf("invalid token account format")
+		}
+		value = m[key]
+	}
+	
+	amount, ok := value.(string)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("invalid token amount")
+	}
+	return decimal.NewFromString(amount)
+}
+
+// SendTransaction submits a signed, serialized transaction and returns its
+// signature. Preflight simulation rejects a transaction that would fail
+// before it costs fees
+func (c *SolanaClient) SendTransaction(ctx context.Context, tx []byte) (string, error) {
+	req := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "sendTransaction",
+		"params": []interface{}{
+			base64.StdEncoding.EncodeToString(tx),
+			map[string]interface{}{
+				"encoding":            "base64",
+				"preflightCommitment": "confirmed",
+				"maxRetries":          3,
+			},
+		},
+	}
+	
+	resp, err := c.rpcCall(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	
+	signature, ok := resp["result"].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid response format")
+	}
+	
+	return signature, nil
+}
+
+// GetSignatureStatus returns the slot a transaction landed in, zero while the
+// cluster hasn't seen it, and its on-chain error, empty when it succeeded
+func (c *SolanaClient) GetSignatureStatus(ctx context.Context, signature string) (uint64, string, error) {
+	req := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "getSignatureStatuses",
+		"params": []interface{}{
+			[]string{signature},
+			map[string]bool{"searchTransactionHistory": true},
+		},
+	}
+	
+	resp, err := c.rpcCall(ctx, req)
+	if err != nil {
+		return 0, "", err
+	}
+	
+	result, ok := resp["result"].(map[string]interface{})
+	if !ok {
+		return 0, "", fmt.Errorf("invalid response format")
+	}
+	
+	statuses, ok := result["value"].([]interface{})
+	if !ok || len(statuses) == 0 || statuses[0] == nil {
+		return 0, "", nil
+	}
+	
+	status, ok := statuses[0].(map[string]interface{})
+	if !ok {
+		return 0, "", fmt.Errorf("invalid status format")
+	}
+	
+	slot, _ := status["slot"].(float64)
+	if txErr := status["err"]; txErr != nil {
+		encoded, _ := json.Marshal(txErr)
+		return uint64(slot), string(encoded), nil
+	}
+	
+	return uint64(slot), "", nil
+}
+
+// GetSlot fetches the current slot
+func (c *SolanaClient) GetSlot(ctx context.Context) (uint64, error) {
+	req := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "getSlot",
+	}
+	
+	resp, err := c.rpcCall(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	
+	slot, ok := resp["result"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("invalid response format")
+	}
+	
+	return uint64(slot), nil
+}
+
+// GetBlockHeight returns the current block height, which transaction
+// blockhash expiry is measured against
+func (c *SolanaClient) GetBlockHeight(ctx context.Context) (uint64, error) {
+	req := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "getBlockHeight",
+	}
+	
+	resp, err := c.rpcCall(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	
+	height, ok := resp["result"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("invalid response format")
+	}
+	
+	return uint64(height), nil
+}
+
 // subscribeToSlots subscribes to slot updates
 func (c *SolanaClient) subscribeToSlots() error {
 	msg := map[string]interface{}{
@@ -307,6 +531,7 @@ func (c *SolanaClient) handleMessages() {
 				c.mu.Lock()
 				c.connected = false
 				c.mu.Unlock()
+				c.closeSlotSubs()
 				return
 			}
 			
@@ -343,6 +568,17 @@ func (c *SolanaClient) processMessage(message []byte) {
 		copy(callbacks, c.blockCallbacks)
 		c.mu.Unlock()
 		
+		// Feed slot subscribers, dropping rather than stalling the reader
+		c.mu.RLock()
+		for _, slots := range c.slotSubs {
+			select {
+			case slots <- slot:
+			default:
+				c.logger.Warn("Slot buffer full, dropping slot", zap.Uint64("slot", slot))
+			}
+		}
+		c.mu.RUnlock()
+		
 		// Create block event
 		blockEvent := &events.BlockEvent{
 			BaseEvent: events.BaseEvent{
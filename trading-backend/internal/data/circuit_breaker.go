@@ -0,0 +1,314 @@
+// Package data provides per-symbol circuit breakers on live prices.
+package data
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/events"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// circuitBreakerAlert is the risk_alert type trips are reported under.
+const circuitBreakerAlert = "circuit_breaker"
+
+// Reasons a circuit breaker trips
+const (
+	TripFastMove = "fast_move" // Price moved more than MaxMove within MoveWindow
+	TripBadPrint = "bad_print" // A tick was far from the median of recent ticks
+)
+
+// minBreakerTicks is how many ticks a symbol needs before its median and
+// return deviation are trusted.
+const minBreakerTicks = 10
+
+// minTickStdDev floors the standard deviation of tick returns, so a run of
+// identical prices doesn't make every later move look like a bad tick.
+const minTickStdDev = 0.0005
+
+// CircuitBreakerConfig configures circuit breakers.
+type CircuitBreakerConfig struct {
+	MaxMove      float64       // Move from the window's high or low that trips, as a fraction; zero disables
+	MoveWindow   time.Duration // How far back MaxMove is measured
+	MaxDeviation float64       // Distance from the recent median that trips as a bad print, as a fraction; zero disables
+	Ticks        int           // Recent accepted ticks the median and return deviation are taken over
+	RejectSigmas float64       // Ticks returning more than this many standard deviations are dropped; zero disables
+	MaxRejects   int           // Consecutive drops after which a tick is accepted, since the price has really moved
+	Cooldown     time.Duration // How long a tripped symbol's entries stay halted
+}
+
+// DefaultCircuitBreakerConfig trips on a 10% move within five minutes or a
+// tick 5% from the median of the last 50, and drops ticks over eight
+// standard deviations.
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		MaxMove:      0.10,
+		MoveWindow:   5 * time.Minute,
+		MaxDeviation: 0.05,
+		Ticks:        50,
+		RejectSigmas: 8,
+		MaxRejects:   5,
+		Cooldown:     15 * time.Minute,
+	}
+}
+
+// CircuitBreakerTrip describes why a symbol's entries were halted.
+type CircuitBreakerTrip struct {
+	Symbol      string    `json:"symbol"`
+	Reason      string    `json:"reason"`
+	Price       float64   `json:"price"`
+	Reference   float64   `json:"reference"` // Window high or low for a fast move, the median for a bad print
+	Move        float64   `json:"move"`      // Fractional distance from the reference
+	Limit       float64   `json:"limit"`
+	HaltedUntil time.Time `json:"halted_until"`
+}
+
+// CircuitBreaker watches each symbol's prices, halting new entries for a
+// cooldown when the price moves abnormally fast or a tick strays far from
+// recent prices, and dropping individual ticks too far out of line to be
+// real before they reach strategies.
+type CircuitBreaker struct {
+	logger *zap.Logger
+	config CircuitBreakerConfig
+	bus    *events.EventBus
+
+	mu      sync.RWMutex
+	symbols map[string]*breakerState
+
+	// OnTrip is called when a symbol that wasn't halted trips
+	OnTrip func(trip CircuitBreakerTrip)
+}
+
+// breakerState is the running state of one symbol.
+type breakerState struct {
+	ticks       []breakerTick // Accepted ticks, oldest first
+	rejects     int           // Consecutive dropped ticks
+	haltedUntil time.Time
+}
+
+// breakerTick is an accepted price.
+type breakerTick struct {
+	price float64
+	at    time.Time
+}
+
+// NewCircuitBreaker creates a circuit breaker.
+func NewCircuitBreaker(logger *zap.Logger, config CircuitBreakerConfig) *CircuitBreaker {
+	defaults := DefaultCircuitBreakerConfig()
+	if config.Ticks < minBreakerTicks {
+		config.Ticks = defaults.Ticks
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = defaults.Cooldown
+	}
+
+	return &CircuitBreaker{
+		logger:  logger.Named("circuit-breaker"),
+		config:  config,
+		symbols: make(map[string]*breakerState),
+	}
+}
+
+// SetEventBus sets the bus trips are published on as risk alerts.
+func (cb *CircuitBreaker) SetEventBus(bus *events.EventBus) {
+	cb.bus = bus
+}
+
+// Observe checks a price update, reporting false if the tick should be
+// dropped. Stale updates carry no new price and always pass.
+func (cb *CircuitBreaker) Observe(update PriceUpdate) bool {
+	if update.Stale {
+		return true
+	}
+	price := update.Price.InexactFloat64()
+	if price <= 0 {
+		return false
+	}
+	at := time.Now()
+	if update.Timestamp > 0 {
+		at = time.UnixMilli(update.Timestamp)
+	}
+
+	cb.mu.Lock()
+	state, ok := cb.symbols[breakerKey(update.Symbol)]
+	if !ok {
+		state = &breakerState{}
+		cb.symbols[breakerKey(update.Symbol)] = state
+	}
+
+	accept := true
+	var trips []CircuitBreakerTrip
+	if len(state.ticks) >= minBreakerTicks {
+		if cb.config.MaxDeviation > 0 {
+			median := medianPrice(state.ticks)
+			if deviation := math.Abs(price/median - 1); deviation > cb.config.MaxDeviation {
+				if trip, ok := cb.tripLocked(state, update.Symbol, TripBadPrint, price, median, deviation, cb.config.MaxDeviation); ok {
+					trips = append(trips, trip)
+				}
+			}
+		}
+		if cb.config.RejectSigmas > 0 && state.rejects < cb.config.MaxRejects {
+			last := state.ticks[len(state.ticks)-1].price
+			if sigmas := math.Abs(math.Log(price/last)) / tickStdDev(state.ticks); sigmas > cb.config.RejectSigmas {
+				accept = false
+			}
+		}
+	}
+
+	if accept {
+		state.rejects = 0
+		state.ticks = append(state.ticks, breakerTick{price: price, at: at})
+		if excess := len(state.ticks) - cb.config.Ticks; excess > 0 {
+			state.ticks = state.ticks[excess:]
+		}
+		if trip, ok := cb.checkMoveLocked(state, update.Symbol, price, at); ok {
+			trips = append(trips, trip)
+		}
+	} else {
+		state.rejects++
+	}
+	cb.mu.Unlock()
+
+	if !accept {
+		cb.logger.Warn("Dropped out-of-line tick",
+			zap.String("symbol", update.Symbol),
+			zap.String("price", update.Price.String()))
+	}
+	for _, trip := range trips {
+		cb.publish(trip)
+	}
+	return accept
+}
+
+// checkMoveLocked trips a symbol whose price moved more than MaxMove from
+// the high or low of the ticks within MoveWindow, reporting whether that
+// started a halt. Callers hold cb.mu.
+func (cb *CircuitBreaker) checkMoveLocked(state *breakerState, symbol string, price float64, at time.Time) (CircuitBreakerTrip, bool) {
+	if cb.config.MaxMove <= 0 || cb.config.MoveWindow <= 0 {
+		return CircuitBreakerTrip{}, false
+	}
+
+	high, low := price, price
+	for _, tick := range state.ticks {
+		if at.Sub(tick.at) > cb.config.MoveWindow {
+			continue
+		}
+		high = math.Max(high, tick.price)
+		low = math.Min(low, tick.price)
+	}
+
+	if drop := 1 - price/high; drop > cb.config.MaxMove {
+		return cb.tripLocked(state, symbol, TripFastMove, price, high, drop, cb.config.MaxMove)
+	}
+	if rise := price/low - 1; rise > cb.config.MaxMove {
+		return cb.tripLocked(state, symbol, TripFastMove, price, low, rise, cb.config.MaxMove)
+	}
+	return CircuitBreakerTrip{}, false
+}
+
+// tripLocked halts a symbol's entries for the cooldown, reporting false if
+// that only extended a halt already in force. Callers hold cb.mu.
+func (cb *CircuitBreaker) tripLocked(state *breakerState, symbol, reason string, price, reference, move, limit float64) (CircuitBreakerTrip, bool) {
+	now := time.Now()
+	wasHalted := now.Before(state.haltedUntil)
+	state.haltedUntil = now.Add(cb.config.Cooldown)
+
+	return CircuitBreakerTrip{
+		Symbol:      symbol,
+		Reason:      reason,
+		Price:       price,
+		Reference:   reference,
+		Move:        move,
+		Limit:       limit,
+		HaltedUntil: state.haltedUntil,
+	}, !wasHalted
+}
+
+// publish reports a trip on the bus and to OnTrip.
+func (cb *CircuitBreaker) publish(trip CircuitBreakerTrip) {
+	message := fmt.Sprintf("%s entries halted until %s: %s of %.2f%% from %g to %g",
+		trip.Symbol, trip.HaltedUntil.Format(time.RFC3339), trip.Reason, trip.Move*100, trip.Reference, trip.Price)
+	cb.logger.Warn("Circuit breaker tripped",
+		zap.String("symbol", trip.Symbol),
+		zap.String("reason", trip.Reason),
+		zap.Float64("move", trip.Move),
+		zap.Time("haltedUntil", trip.HaltedUntil))
+
+	if cb.bus != nil {
+		alert := events.NewRiskAlertEvent(circuitBreakerAlert, "warning", message,
+			decimal.NewFromFloat(trip.Move), decimal.NewFromFloat(trip.Limit))
+		alert.Symbol = trip.Symbol
+		cb.bus.Publish(alert)
+	}
+	if cb.OnTrip != nil {
+		cb.OnTrip(trip)
+	}
+}
+
+// Halted reports whether new entries in symbol are halted.
+func (cb *CircuitBreaker) Halted(symbol string) bool {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+
+	state, ok := cb.symbols[breakerKey(symbol)]
+	return ok && time.Now().Before(state.haltedUntil)
+}
+
+// Reset lifts a symbol's halt before its cooldown ends.
+func (cb *CircuitBreaker) Reset(symbol string) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if state, ok := cb.symbols[breakerKey(symbol)]; ok {
+		state.haltedUntil = time.Time{}
+	}
+}
+
+// breakerKey matches "BTC/USDT" and "btcusdt" to the same symbol.
+func breakerKey(symbol string) string {
+	return strings.ToUpper(strings.ReplaceAll(symbol, "/", ""))
+}
+
+// medianPrice returns the median of ticks' prices.
+func medianPrice(ticks []breakerTick) float64 {
+	prices := make([]float64, len(ticks))
+	for i, tick := range ticks {
+		prices[i] = tick.price
+	}
+	sort.Float64s(prices)
+
+	mid := len(prices) / 2
+	if len(prices)%2 == 0 {
+		return (prices[mid-1] + prices[mid]) / 2
+	}
+	return prices[mid]
+}
+
+// tickStdDev returns the standard deviation of log returns between ticks,
+// floored at minTickStdDev.
+func tickStdDev(ticks []breakerTick) float64 {
+	returns := make([]float64, 0, len(ticks)-1)
+	mean := 0.0
+	for i := 1; i < len(ticks); i++ {
+		r := math.Log(ticks[i].price / ticks[i-1].price)
+		returns = append(returns, r)
+		mean += r
+	}
+	if len(returns) < 2 {
+		return minTickStdDev
+	}
+	mean /= float64(len(returns))
+
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns) - 1)
+
+	return math.Max(math.Sqrt(variance), minTickStdDev)
+}
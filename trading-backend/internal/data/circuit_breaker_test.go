@@ -0,0 +1,106 @@
+package data_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/data"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// tick is a price update for BTCUSDT at start plus n seconds.
+func tick(start time.Time, n int, price float64) data.PriceUpdate {
+	return data.PriceUpdate{
+		Symbol:    "BTCUSDT",
+		Price:     decimal.NewFromFloat(price),
+		Timestamp: start.Add(time.Duration(n) * time.Second).UnixMilli(),
+	}
+}
+
+func TestCircuitBreakerDropsBadTicksAndHaltsEntries(t *testing.T) {
+	cb := data.NewCircuitBreaker(zap.NewNop(), data.DefaultCircuitBreakerConfig())
+	var trips []data.CircuitBreakerTrip
+	cb.OnTrip = func(trip data.CircuitBreakerTrip) { trips = append(trips, trip) }
+	start := time.Now()
+
+	// Quiet trading around 100
+	for i := 0; i < 20; i++ {
+		if !cb.Observe(tick(start, i, 100+0.05*float64(i%3))) {
+			t.Fatalf("Dropped ordinary tick %d", i)
+		}
+	}
+	if cb.Halted("BTC/USDT") {
+		t.Fatal("Halted on ordinary ticks")
+	}
+
+	// A print at 60 is dropped and, being far from the median, halts entries
+	if cb.Observe(tick(start, 20, 60)) {
+		t.Error("Expected the bad print to be dropped")
+	}
+	if !cb.Halted("BTC/USDT") || len(trips) != 1 || trips[0].Reason != data.TripBadPrint {
+		t.Fatalf("Expected a bad print halt, got %+v", trips)
+	}
+	if !cb.Observe(tick(start, 21, 100.05)) {
+		t.Error("Expected trading to resume at the old price")
+	}
+
+	cb.Reset("BTCUSDT")
+	if cb.Halted("BTCUSDT") {
+		t.Error("Expected Reset to lift the halt")
+	}
+}
+
+func TestCircuitBreakerHaltsOnFastMoves(t *testing.T) {
+	config := data.DefaultCircuitBreakerConfig()
+	config.MaxDeviation = 0
+	cb := data.NewCircuitBreaker(zap.NewNop(), config)
+	var trips []data.CircuitBreakerTrip
+	cb.OnTrip = func(trip data.CircuitBreakerTrip) { trips = append(trips, trip) }
+	start := time.Now()
+
+	for i := 0; i < 20; i++ {
+		cb.Observe(tick(start, i, 100))
+	}
+
+	// A crash of 2% a tick passes the tick filter but is 10% down within
+	// the window by the sixth tick
+	price := 100.0
+	for i := 20; i < 26; i++ {
+		price *= 0.98
+		cb.Observe(tick(start, i, price))
+	}
+	if !cb.Halted("BTCUSDT") || len(trips) != 1 || trips[0].Reason != data.TripFastMove || trips[0].Reference != 100 {
+		t.Fatalf("Expected a fast move halt from 100, got %+v", trips)
+	}
+
+	// Further falls extend the halt without alerting again
+	cb.Observe(tick(start, 26, price*0.98))
+	if len(trips) != 1 {
+		t.Errorf("Expected one alert per halt, got %d", len(trips))
+	}
+}
+
+func TestCircuitBreakerAcceptsSustainedJumps(t *testing.T) {
+	config := data.DefaultCircuitBreakerConfig()
+	config.MaxRejects = 3
+	cb := data.NewCircuitBreaker(zap.NewNop(), config)
+	start := time.Now()
+
+	for i := 0; i < 20; i++ {
+		cb.Observe(tick(start, i, 100))
+	}
+
+	// A real repricing keeps printing; after three drops it gets through
+	for i := 20; i < 23; i++ {
+		if cb.Observe(tick(start, i, 103)) {
+			t.Fatalf("Expected jump tick %d to be dropped", i)
+		}
+	}
+	if !cb.Observe(tick(start, 23, 103)) {
+		t.Error("Expected the jump to be accepted once it persisted")
+	}
+	if !cb.Observe(tick(start, 24, 103.01)) {
+		t.Error("Expected trading at the new level to pass")
+	}
+}
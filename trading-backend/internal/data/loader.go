@@ -0,0 +1,258 @@
+// Package data provides loading of historical OHLCV bars from CSV and
+// Parquet files.
+package data
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/parquet-go/parquet-go"
+	"github.com/shopspring/decimal"
+)
+
+// parquetMagic opens every Parquet file
+var parquetMagic = []byte("PAR1")
+
+// csvColumns are the CSV columns in positional order
+var csvColumns = []string{"timestamp", "open", "high", "low", "close", "volume"}
+
+// csvColumnAliases maps accepted header names to their column
+var csvColumnAliases = map[string]string{
+	"time":     "timestamp",
+	"date":     "timestamp",
+	"datetime": "timestamp",
+}
+
+// timestampLayouts are the text timestamp formats accepted in CSV files.
+// Layouts without a zone are read as UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parquetBar is the Parquet schema of a bar
+type parquetBar struct {
+	Timestamp time.Time `parquet:"timestamp,timestamp(millisecond)"`
+	Open      float64   `parquet:"open"`
+	High      float64   `parquet:"high"`
+	Low       float64   `parquet:"low"`
+	Close     float64   `parquet:"close"`
+	Volume    float64   `parquet:"volume"`
+}
+
+// LoadOHLCV reads bars for symbol from r, which holds either CSV with
+// timestamp,open,high,low,close,volume columns or a Parquet file with the
+// same columns. Timestamps are normalized to UTC. Bars must be strictly
+// increasing in time with no gap wider than one timeframe.
+func LoadOHLCV(symbol, timeframe string, r io.Reader) ([]types.OHLCV, error) {
+	interval, ok := TimeframeInterval(types.Timeframe(timeframe))
+	if !ok {
+		return nil, fmt.Errorf("unsupported timeframe: %s", timeframe)
+	}
+
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s bars: %w", symbol, err)
+	}
+
+	var bars []types.OHLCV
+	if bytes.HasPrefix(raw, parquetMagic) {
+		bars, err = readParquetBars(raw)
+	} else {
+		bars, err = readCSVBars(raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s bars: %w", symbol, err)
+	}
+
+	if err := validateSeries(bars, interval); err != nil {
+		return nil, fmt.Errorf("invalid %s %s bars: %w", symbol, timeframe, err)
+	}
+
+	return bars, nil
+}
+
+// readCSVBars parses CSV bars. A header row is optional; when present its
+// column names set the column order.
+func readCSVBars(raw []byte) ([]types.OHLCV, error) {
+	reader := csv.NewReader(bytes.NewReader(raw))
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	index := make(map[string]int, len(csvColumns))
+	for i, column := range csvColumns {
+		index[column] = i
+	}
+
+	if _, err := parseTimestamp(records[0][0]); err != nil {
+		index, err = csvHeaderIndex(records[0])
+		if err != nil {
+			return nil, err
+		}
+		records = records[1:]
+	}
+
+	bars := make([]types.OHLCV, 0, len(records))
+	for i, record := range records {
+		bar, err := parseCSVBar(record, index)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		bars = append(bars, bar)
+	}
+
+	return bars, nil
+}
+
+// csvHeaderIndex maps each required column to its position in header
+func csvHeaderIndex(header []string) (map[string]int, error) {
+	index := make(map[string]int, len(csvColumns))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if alias, ok := csvColumnAliases[name]; ok {
+			name = alias
+		}
+		index[name] = i
+	}
+
+	for _, column := range csvColumns {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("missing column %q", column)
+		}
+	}
+	return index, nil
+}
+
+// parseCSVBar parses one CSV record
+func parseCSVBar(record []string, index map[string]int) (types.OHLCV, error) {
+	var bar types.OHLCV
+	values := make([]decimal.Decimal, 0, len(csvColumns)-1)
+
+	for _, column := range csvColumns {
+		i := index[column]
+		if i >= len(record) {
+			return bar, fmt.Errorf("missing %s", column)
+		}
+		field := strings.TrimSpace(record[i])
+
+		if column == "timestamp" {
+			ts, err := parseTimestamp(field)
+			if err != nil {
+				return bar, err
+			}
+			bar.Timestamp = ts
+			continue
+		}
+
+		value, err := decimal.NewFromString(field)
+		if err != nil {
+			return bar, fmt.Errorf("invalid %s %q", column, field)
+		}
+		values = append(values, value)
+	}
+
+	bar.Open, bar.High, bar.Low, bar.Close, bar.Volume = values[0], values[1], values[2], values[3], values[4]
+	return bar, nil
+}
+
+// parseTimestamp parses a text or Unix timestamp into UTC. Unix timestamps
+// may be in seconds, milliseconds, microseconds or nanoseconds.
+func parseTimestamp(field string) (time.Time, error) {
+	if n, err := strconv.ParseInt(field, 10, 64); err == nil {
+		switch {
+		case n < 1e11:
+			return time.Unix(n, 0).UTC(), nil
+		case n < 1e14:
+			return time.UnixMilli(n).UTC(), nil
+		case n < 1e17:
+			return time.UnixMicro(n).UTC(), nil
+		default:
+			return time.Unix(0, n).UTC(), nil
+		}
+	}
+
+	for _, layout := range timestampLayouts {
+		if ts, err := time.Parse(layout, field); err == nil {
+			return ts.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", field)
+}
+
+// readParquetBars reads bars from a Parquet file
+func readParquetBars(raw []byte) ([]types.OHLCV, error) {
+	rows, err := parquet.Read[parquetBar](bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return nil, err
+	}
+
+	bars := make([]types.OHLCV, len(rows))
+	for i, row := range rows {
+		bars[i] = types.OHLCV{
+			Timestamp: row.Timestamp.UTC(),
+			Open:      decimal.NewFromFloat(row.Open),
+			High:      decimal.NewFromFloat(row.High),
+			Low:       decimal.NewFromFloat(row.Low),
+			Close:     decimal.NewFromFloat(row.Close),
+			Volume:    decimal.NewFromFloat(row.Volume),
+		}
+	}
+	return bars, nil
+}
+
+// validateSeries checks bars strictly increase in time with no gap wider
+// than interval
+func validateSeries(bars []types.OHLCV, interval time.Duration) error {
+	if len(bars) == 0 {
+		return errors.New("no bars")
+	}
+
+	for i := 1; i < len(bars); i++ {
+		prev, cur := bars[i-1].Timestamp, bars[i].Timestamp
+		if !cur.After(prev) {
+			return fmt.Errorf("bar %d at %s is not after %s", i, cur.Format(time.RFC3339), prev.Format(time.RFC3339))
+		}
+		if gap := cur.Sub(prev); gap > interval {
+			return fmt.Errorf("gap of %s before bar %d at %s exceeds %s", gap, i, cur.Format(time.RFC3339), interval)
+		}
+	}
+	return nil
+}
+
+// TimeframeInterval returns the duration of one bar, or false for an
+// unknown timeframe
+func TimeframeInterval(timeframe types.Timeframe) (time.Duration, bool) {
+	switch timeframe {
+	case types.Timeframe1m:
+		return time.Minute, true
+	case types.Timeframe5m:
+		return 5 * time.Minute, true
+	case types.Timeframe15m:
+		return 15 * time.Minute, true
+	case types.Timeframe1h:
+		return time.Hour, true
+	case types.Timeframe4h:
+		return 4 * time.Hour, true
+	case types.Timeframe1d:
+		return 24 * time.Hour, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,181 @@
+package data_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/data"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/parquet-go/parquet-go"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func TestLoadOHLCVFromCSV(t *testing.T) {
+	input := `Date,Open,High,Low,Close,Volume
+2024-03-01T02:00:00+02:00,100,105,99,104,1200
+2024-03-01T03:00:00+02:00,104,106,101,102.5,900
+2024-03-01T04:00:00+02:00,102.5,103,98,99,1500
+`
+	bars, err := data.LoadOHLCV("BTCUSDT", "1h", strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("LoadOHLCV: %v", err)
+	}
+	if len(bars) != 3 {
+		t.Fatalf("got %d bars, want 3", len(bars))
+	}
+
+	first := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	if !bars[0].Timestamp.Equal(first) || bars[0].Timestamp.Location() != time.UTC {
+		t.Errorf("first timestamp = %v, want %v", bars[0].Timestamp, first)
+	}
+	if !bars[1].Close.Equal(decimal.RequireFromString("102.5")) {
+		t.Errorf("second close = %s, want 102.5", bars[1].Close)
+	}
+}
+
+func TestLoadOHLCVHeaderlessUnixMillis(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var b strings.Builder
+	for i := 0; i < 3; i++ {
+		ts := start.Add(time.Duration(i) * 5 * time.Minute).UnixMilli()
+		b.WriteString(strings.Join([]string{
+			decimal.NewFromInt(ts).String(), "10", "11", "9", "10.5", "3",
+		}, ",") + "\n")
+	}
+
+	bars, err := data.LoadOHLCV("ETHUSDT", "5m", strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("LoadOHLCV: %v", err)
+	}
+	if len(bars) != 3 || !bars[2].Timestamp.Equal(start.Add(10*time.Minute)) {
+		t.Errorf("bars = %+v", bars)
+	}
+}
+
+func TestLoadOHLCVRejectsBadSeries(t *testing.T) {
+	tests := map[string]string{
+		"out of order": "2024-01-01 01:00,1,1,1,1,1\n2024-01-01 00:00,1,1,1,1,1\n",
+		"duplicate":    "2024-01-01 00:00,1,1,1,1,1\n2024-01-01 00:00,1,1,1,1,1\n",
+		"gap":          "2024-01-01 00:00,1,1,1,1,1\n2024-01-01 03:00,1,1,1,1,1\n",
+		"bad price":    "2024-01-01 00:00,1,x,1,1,1\n",
+		"empty":        "timestamp,open,high,low,close,volume\n",
+	}
+
+	for name, input := range tests {
+		if _, err := data.LoadOHLCV("BTCUSDT", "1h", strings.NewReader(input)); err == nil {
+			t.Errorf("%s: LoadOHLCV succeeded", name)
+		}
+	}
+
+	if _, err := data.LoadOHLCV("BTCUSDT", "7m", strings.NewReader("")); err == nil {
+		t.Error("unsupported timeframe accepted")
+	}
+}
+
+type parquetRow struct {
+	Timestamp time.Time `parquet:"timestamp,timestamp(millisecond)"`
+	Open      float64   `parquet:"open"`
+	High      float64   `parquet:"high"`
+	Low       float64   `parquet:"low"`
+	Close     float64   `parquet:"close"`
+	Volume    float64   `parquet:"volume"`
+}
+
+func TestLoadOHLCVFromParquet(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := []parquetRow{
+		{Timestamp: start, Open: 1, High: 2, Low: 0.5, Close: 1.5, Volume: 10},
+		{Timestamp: start.Add(24 * time.Hour), Open: 1.5, High: 3, Low: 1, Close: 2.5, Volume: 20},
+	}
+
+	var buf bytes.Buffer
+	if err := parquet.Write(&buf, rows); err != nil {
+		t.Fatalf("parquet.Write: %v", err)
+	}
+
+	bars, err := data.LoadOHLCV("SOLUSDT", "1d", &buf)
+	if err != nil {
+		t.Fatalf("LoadOHLCV: %v", err)
+	}
+	if len(bars) != 2 {
+		t.Fatalf("got %d bars, want 2", len(bars))
+	}
+	if !bars[1].Timestamp.Equal(rows[1].Timestamp) || !bars[1].Close.Equal(decimal.NewFromFloat(2.5)) {
+		t.Errorf("second bar = %+v", bars[1])
+	}
+}
+
+func TestStoreReadsDownloadedCSV(t *testing.T) {
+	dir := t.TempDir()
+	csv := "timestamp,open,high,low,close,volume\n" +
+		"2024-01-01 00:00:00,100,101,99,100.5,10\n" +
+		"2024-01-01 01:00:00,100.5,102,100,101,12\n"
+	if err := os.WriteFile(filepath.Join(dir, "BTCUSDT_1h.csv"), []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := data.NewStore(zap.NewNop(), dir)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars, err := store.LoadOHLCV(context.Background(), "BTCUSDT", types.Timeframe1h, start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("LoadOHLCV: %v", err)
+	}
+	if len(bars) != 2 || !bars[1].Close.Equal(decimal.NewFromInt(101)) {
+		t.Errorf("bars = %+v, want the two CSV bars", bars)
+	}
+
+	// No file means no warmup history, rather than sample data
+	history, err := store.LoadHistory("ETHUSDT", types.Timeframe1h)
+	if err != nil || history != nil {
+		t.Errorf("LoadHistory = %v, %v, want nil", history, err)
+	}
+}
+
+func TestStoreOHLCVMerges(t *testing.T) {
+	store, err := data.NewStore(zap.NewNop(), t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bar := func(hour int, close int64) types.OHLCV {
+		price := decimal.NewFromInt(close)
+		return types.OHLCV{Timestamp: start.Add(time.Duration(hour) * time.Hour), Open: price, High: price, Low: price, Close: price}
+	}
+
+	if err := store.StoreOHLCV("BTCUSDT", types.Timeframe1h, []types.OHLCV{bar(0, 100), bar(1, 101)}); err != nil {
+		t.Fatalf("StoreOHLCV: %v", err)
+	}
+	// A later fetch overlaps the last bar and extends the series
+	if err := store.StoreOHLCV("BTCUSDT", types.Timeframe1h, []types.OHLCV{bar(2, 103), bar(1, 102)}); err != nil {
+		t.Fatalf("StoreOHLCV: %v", err)
+	}
+
+	history, err := store.LoadHistory("BTCUSDT", types.Timeframe1h)
+	if err != nil {
+		t.Fatalf("LoadHistory: %v", err)
+	}
+	want := []int64{100, 102, 103}
+	if len(history) != len(want) {
+		t.Fatalf("got %d bars, want %d", len(history), len(want))
+	}
+	for i, close := range want {
+		if !history[i].Close.Equal(decimal.NewFromInt(close)) {
+			t.Errorf("bar %d close = %s, want %d", i, history[i].Close, close)
+		}
+	}
+
+	if symbols := store.GetAvailableSymbols(); len(symbols) != 1 || symbols[0] != "BTCUSDT" {
+		t.Errorf("symbols = %v, want [BTCUSDT]", symbols)
+	}
+}
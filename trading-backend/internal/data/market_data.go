@@ -6,9 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/atlas-desktop/trading-backend/internal/events"
 	"github.com/atlas-desktop/trading-backend/pkg/types"
 	"github.com/gorilla/websocket"
 	"github.com/shopspring/decimal"
@@ -24,6 +27,7 @@ type PriceUpdate struct {
 	Volume    decimal.Decimal `json:"volume"`
 	Timestamp int64           `json:"timestamp"`
 	Source    string          `json:"source"`
+	Stale     bool            `json:"stale,omitempty"` // No update within StaleAfter; don't act on the price
 }
 
 // OHLCV represents a candlestick.
@@ -79,9 +83,13 @@ type MarketDataService struct {
 	running       bool
 	ctx           context.Context
 	cancel        context.CancelFunc
+	lastMessage   int64 // Unix nanos of the last WebSocket message
+	bus           *events.EventBus
+	breaker       *CircuitBreaker
 	
 	// Cache
 	priceCache    map[string]PriceUpdate
+	priceSeen     map[string]time.Time // When each symbol's price last arrived
 	priceMu       sync.RWMutex
 	ohlcvCache    map[string][]OHLCV
 	ohlcvMu       sync.RWMutex
@@ -93,6 +101,11 @@ type MarketDataConfig struct {
 	Symbols      []string
 	Intervals    []string // e.g., ["1m", "5m", "1h"]
 	BufferSize   int
+	
+	// Reconnection backs off exponentially from ReconnectDelay
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+	StaleAfter        time.Duration // A price not updated for this long is marked stale; zero disables
 }
 
 // DefaultMarketDataConfig returns default config.
@@ -102,6 +115,10 @@ func DefaultMarketDataConfig() MarketDataConfig {
 		Symbols:      []string{"BTCUSDT", "ETHUSDT", "SOLUSDT"},
 		Intervals:    []string{"1m", "5m", "15m", "1h"},
 		BufferSize:   100,
+		
+		ReconnectDelay:    time.Second,
+		MaxReconnectDelay: time.Minute,
+		StaleAfter:        30 * time.Second,
 	}
 }
 
@@ -112,30 +129,45 @@ func NewMarketDataService(logger *zap.Logger, config MarketDataConfig) *MarketDa
 		config:        config,
 		subscriptions: make(map[string]bool),
 		priceCache:    make(map[string]PriceUpdate),
+		priceSeen:     make(map[string]time.Time),
 		ohlcvCache:    make(map[string][]OHLCV),
 	}
 }
 
+// SetEventBus sets the bus connection status changes are published on.
+func (s *MarketDataService) SetEventBus(bus *events.EventBus) {
+	s.bus = bus
+}
+
+// SetCircuitBreaker sets the breaker price updates pass through; ticks it
+// drops are neither cached nor passed to the price callback.
+func (s *MarketDataService) SetCircuitBreaker(breaker *CircuitBreaker) {
+	s.breaker = breaker
+}
+
 // Start starts the market data service.
 func (s *MarketDataService) Start(ctx context.Context) error {
 	s.ctx, s.cancel = context.WithCancel(ctx)
 	s.running = true
 	
+	// Default symbols are resubscribed on every connection
+	s.subMu.Lock()
+	for _, symbol := range s.config.Symbols {
+		s.subscriptions[symbol] = true
+	}
+	s.subMu.Unlock()
+	
 	// Connect to Binance WebSocket
 	if err := s.connectBinance(); err != nil {
 		return fmt.Errorf("failed to connect to Binance: %w", err)
 	}
+	s.resubscribe()
 	
-	// Subscribe to default symbols
-	for _, symbol := range s.config.Symbols {
-		s.Subscribe(symbol)
-	}
-	
-	// Start read loop
-	go s.readLoop()
+	// Read, reconnecting whenever the connection drops
+	go s.supervise()
 	
-	// Start reconnection monitor
-	go s.reconnectMonitor()
+	// Flag prices that stop updating
+	go s.stalenessWatchdog()
 	
 	s.logger.Info("Market data service started",
 		zap.Int("symbols", len(s.config.Symbols)))
@@ -162,26 +194,26 @@ func (s *MarketDataService) Stop() error {
 
 // connectBinance connects to Binance WebSocket.
 func (s *MarketDataService) connectBinance() error {
-	s.binanceMu.Lock()
-	defer s.binanceMu.Unlock()
-	
 	u, err := url.Parse(s.config.BinanceWSURL)
 	if err != nil {
 		return err
 	}
 	
-	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	conn, _, err := websocket.DefaultDialer.DialContext(s.ctx, u.String(), nil)
 	if err != nil {
 		return err
 	}
 	
+	s.binanceMu.Lock()
 	s.binanceWS = conn
+	s.binanceMu.Unlock()
 	s.logger.Debug("Connected to Binance WebSocket")
 	
 	return nil
 }
 
-// Subscribe subscribes to a symbol.
+// Subscribe subscribes to a symbol. A symbol subscribed while disconnected
+// is subscribed when the connection is restored.
 func (s *MarketDataService) Subscribe(symbol string) error {
 	s.subMu.Lock()
 	if s.subscriptions[symbol] {
@@ -191,32 +223,7 @@ func (s *MarketDataService) Subscribe(symbol string) error {
 	s.subscriptions[symbol] = true
 	s.subMu.Unlock()
 	
-	// Send subscription message
-	streams := []string{
-		fmt.Sprintf("%s@ticker", stringToLower(symbol)),
-		fmt.Sprintf("%s@trade", stringToLower(symbol)),
-		fmt.Sprintf("%s@depth20@100ms", stringToLower(symbol)),
-	}
-	
-	// Add kline streams for configured intervals
-	for _, interval := range s.config.Intervals {
-		streams = append(streams, fmt.Sprintf("%s@kline_%s", stringToLower(symbol), interval))
-	}
-	
-	msg := map[string]interface{}{
-		"method": "SUBSCRIBE",
-		"params": streams,
-		"id":     time.Now().UnixNano(),
-	}
-	
-	s.binanceMu.Lock()
-	defer s.binanceMu.Unlock()
-	
-	if s.binanceWS == nil {
-		return fmt.Errorf("websocket not connected")
-	}
-	
-	if err := s.binanceWS.WriteJSON(msg); err != nil {
+	if err := s.sendSubscription("SUBSCRIBE", symbol); err != nil {
 		return err
 	}
 	
@@ -234,14 +241,35 @@ func (s *MarketDataService) Unsubscribe(symbol string) error {
 	delete(s.subscriptions, symbol)
 	s.subMu.Unlock()
 	
+	return s.sendSubscription("UNSUBSCRIBE", symbol)
+}
+
+// symbolStreams returns the streams subscribed for a symbol.
+func (s *MarketDataService) symbolStreams(symbol string) []string {
 	streams := []string{
 		fmt.Sprintf("%s@ticker", stringToLower(symbol)),
 		fmt.Sprintf("%s@trade", stringToLower(symbol)),
 		fmt.Sprintf("%s@depth20@100ms", stringToLower(symbol)),
 	}
 	
+	// Add kline streams for configured intervals
+	for _, interval := range s.config.Intervals {
+		streams = append(streams, fmt.Sprintf("%s@kline_%s", stringToLower(symbol), interval))
+	}
+	return streams
+}
+
+// sendSubscription sends one SUBSCRIBE or UNSUBSCRIBE message covering
+// every symbol's streams. While disconnected there is nothing to send:
+// the subscriptions are resent on reconnect.
+func (s *MarketDataService) sendSubscription(method string, symbols ...string) error {
+	var streams []string
+	for _, symbol := range symbols {
+		streams = append(streams, s.symbolStreams(symbol)...)
+	}
+	
 	msg := map[string]interface{}{
-		"method": "UNSUBSCRIBE",
+		"method": method,
 		"params": streams,
 		"id":     time.Now().UnixNano(),
 	}
@@ -249,37 +277,211 @@ func (s *MarketDataService) Unsubscribe(symbol string) error {
 	s.binanceMu.Lock()
 	defer s.binanceMu.Unlock()
 	
-	if s.binanceWS != nil {
-		return s.binanceWS.WriteJSON(msg)
+	if s.binanceWS == nil {
+		return nil
 	}
+	return s.binanceWS.WriteJSON(msg)
+}
+
+// resubscribe subscribes the connection to every subscribed symbol in one
+// message, returning how many were subscribed.
+func (s *MarketDataService) resubscribe() int {
+	s.subMu.RLock()
+	symbols := make([]string, 0, len(s.subscriptions))
+	for symbol := range s.subscriptions {
+		symbols = append(symbols, symbol)
+	}
+	s.subMu.RUnlock()
 	
-	return nil
+	if len(symbols) == 0 {
+		return 0
+	}
+	sort.Strings(symbols)
+	
+	if err := s.sendSubscription("SUBSCRIBE", symbols...); err != nil {
+		s.logger.Error("Failed to resubscribe", zap.Strings("symbols", symbols), zap.Error(err))
+		return 0
+	}
+	return len(symbols)
 }
 
-// readLoop reads messages from WebSocket.
-func (s *MarketDataService) readLoop() {
-	for s.running {
+// supervise reads the connection until it drops, then reconnects, until
+// the service stops.
+func (s *MarketDataService) supervise() {
+	for {
 		s.binanceMu.RLock()
 		conn := s.binanceWS
 		s.binanceMu.RUnlock()
 		
-		if conn == nil {
-			time.Sleep(100 * time.Millisecond)
-			continue
+		if conn != nil {
+			err := s.readLoop(conn)
+			if s.ctx.Err() != nil {
+				return
+			}
+			s.dropConnection(conn, err)
 		}
 		
+		if !s.reconnect() {
+			return
+		}
+	}
+}
+
+// readLoop reads messages from a connection until it fails.
+func (s *MarketDataService) readLoop(conn *websocket.Conn) error {
+	for {
 		_, message, err := conn.ReadMessage()
 		if err != nil {
-			if s.running {
-				s.logger.Error("WebSocket read error", zap.Error(err))
+			return err
+		}
+		
+		atomic.StoreInt64(&s.lastMessage, time.Now().UnixNano())
+		s.handleMessage(message)
+	}
+}
+
+// dropConnection closes a failed connection and reports the outage.
+func (s *MarketDataService) dropConnection(conn *websocket.Conn, err error) {
+	s.binanceMu.Lock()
+	if s.binanceWS == conn {
+		s.binanceWS = nil
+	}
+	s.binanceMu.Unlock()
+	conn.Close()
+	
+	s.logger.Error("Binance WebSocket disconnected", zap.Error(err))
+	s.publishStatus("market_data_disconnected", "market_data_connected",
+		fmt.Sprintf("Market data connection lost: %v", err))
+}
+
+// reconnect dials until connected, backing off exponentially from
+// ReconnectDelay up to MaxReconnectDelay, then resubscribes. It returns
+// false if the service stopped first.
+func (s *MarketDataService) reconnect() bool {
+	delay := s.config.ReconnectDelay
+	if delay <= 0 {
+		delay = time.Second
+	}
+	
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-s.ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+		
+		if err := s.connectBinance(); err != nil {
+			s.logger.Warn("Reconnection failed",
+				zap.Int("attempt", attempt),
+				zap.Duration("delay", delay),
+				zap.Error(err))
+			delay *= 2
+			if s.config.MaxReconnectDelay > 0 && delay > s.config.MaxReconnectDelay {
+				delay = s.config.MaxReconnectDelay
 			}
 			continue
 		}
 		
-		s.handleMessage(message)
+		symbols := s.resubscribe()
+		s.logger.Info("Reconnected to Binance",
+			zap.Int("attempts", attempt),
+			zap.Int("symbols", symbols))
+		s.publishStatus("market_data_connected", "market_data_disconnected",
+			fmt.Sprintf("Market data reconnected after %d attempts, resubscribed %d symbols", attempt, symbols))
+		return true
 	}
 }
 
+// publishStatus publishes a connection status change if a bus is set.
+func (s *MarketDataService) publishStatus(status, previous, message string) {
+	if s.bus != nil {
+		s.bus.Publish(events.NewStatusEvent(status, previous, message))
+	}
+}
+
+// stalenessWatchdog periodically marks prices stale.
+func (s *MarketDataService) stalenessWatchdog() {
+	if s.config.StaleAfter <= 0 {
+		return
+	}
+	
+	ticker := time.NewTicker(s.config.StaleAfter / 4)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.markStale(now)
+		}
+	}
+}
+
+// markStale flags cached prices that haven't updated within StaleAfter and
+// passes each to the price callback once, so consumers stop trading on it.
+// The symbol's next update clears the flag.
+func (s *MarketDataService) markStale(now time.Time) {
+	var stale []PriceUpdate
+	s.priceMu.Lock()
+	for symbol, update := range s.priceCache {
+		if update.Stale || now.Sub(s.priceSeen[symbol]) < s.config.StaleAfter {
+			continue
+		}
+		update.Stale = true
+		s.priceCache[symbol] = update
+		stale = append(stale, update)
+	}
+	s.priceMu.Unlock()
+	
+	for _, update := range stale {
+		s.logger.Warn("Price data stale",
+			zap.String("symbol", update.Symbol),
+			zap.Duration("staleAfter", s.config.StaleAfter))
+		if s.onPrice != nil {
+			s.onPrice(update)
+		}
+	}
+}
+
+// MarketDataHealth describes the market data connection.
+type MarketDataHealth struct {
+	Connected     bool      `json:"connected"`
+	LastMessage   time.Time `json:"lastMessage,omitempty"`
+	Subscriptions int       `json:"subscriptions"`
+	Stale         []string  `json:"stale,omitempty"` // Symbols whose prices are stale
+}
+
+// Health returns the connection state and when a message last arrived.
+func (s *MarketDataService) Health() MarketDataHealth {
+	s.binanceMu.RLock()
+	connected := s.binanceWS != nil && s.running
+	s.binanceMu.RUnlock()
+	
+	s.subMu.RLock()
+	subscriptions := len(s.subscriptions)
+	s.subMu.RUnlock()
+	
+	health := MarketDataHealth{
+		Connected:     connected,
+		Subscriptions: subscriptions,
+	}
+	
+	s.priceMu.RLock()
+	for symbol, update := range s.priceCache {
+		if update.Stale {
+			health.Stale = append(health.Stale, symbol)
+		}
+	}
+	s.priceMu.RUnlock()
+	sort.Strings(health.Stale)
+	
+	if ns := atomic.LoadInt64(&s.lastMessage); ns > 0 {
+		health.LastMessage = time.Unix(0, ns)
+	}
+	return health
+}
+
 // handleMessage handles a WebSocket message.
 func (s *MarketDataService) handleMessage(data []byte) {
 	// Try to parse as ticker
@@ -330,9 +532,15 @@ func (s *MarketDataService) handleTicker(msg map[string]interface{}) {
 		Source:    "binance",
 	}
 	
+	// Drop ticks too far out of line to be real
+	if s.breaker != nil && !s.breaker.Observe(update) {
+		return
+	}
+	
 	// Cache
 	s.priceMu.Lock()
 	s.priceCache[symbol] = update
+	s.priceSeen[symbol] = time.Now()
 	s.priceMu.Unlock()
 	
 	// Callback
@@ -444,43 +652,6 @@ func (s *MarketDataService) handleKline(msg map[string]interface{}) {
 	}
 }
 
-// reconnectMonitor monitors and reconnects WebSocket.
-func (s *MarketDataService) reconnectMonitor() {
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
-	
-	for {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-ticker.C:
-			s.binanceMu.RLock()
-			conn := s.binanceWS
-			s.binanceMu.RUnlock()
-			
-			if conn == nil && s.running {
-				s.logger.Info("Attempting to reconnect to Binance...")
-				if err := s.connectBinance(); err != nil {
-					s.logger.Error("Reconnection failed", zap.Error(err))
-				} else {
-					// Resubscribe
-					s.subMu.RLock()
-					symbols := make([]string, 0, len(s.subscriptions))
-					for symbol := range s.subscriptions {
-						symbols = append(symbols, symbol)
-					}
-					s.subMu.RUnlock()
-					
-					for _, symbol := range symbols {
-						s.subscriptions[symbol] = false
-						s.Subscribe(symbol)
-					}
-				}
-			}
-		}
-	}
-}
-
 // Callbacks
 
 // OnPrice sets the price update callback.
@@ -0,0 +1,125 @@
+package data_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/data"
+	"github.com/atlas-desktop/trading-backend/internal/events"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+func TestMarketDataReconnectsResubscribesAndFlagsStalePrices(t *testing.T) {
+	// The first connection sends one ticker and drops; later ones stay
+	// silent so the price goes stale
+	var connections int32
+	subscribed := make(chan []string, 10)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		n := atomic.AddInt32(&connections, 1)
+
+		var msg struct {
+			Method string   `json:"method"`
+			Params []string `json:"params"`
+		}
+		if err := conn.ReadJSON(&msg); err != nil || msg.Method != "SUBSCRIBE" {
+			return
+		}
+		subscribed <- msg.Params
+
+		if n == 1 {
+			conn.WriteJSON(map[string]interface{}{"e": "24hrTicker", "s": "BTCUSDT", "c": "50000", "E": 1})
+			return
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	bus := events.NewEventBus(zap.NewNop(), events.DefaultEventBusConfig())
+	defer bus.Stop()
+	statuses := make(chan string, 10)
+	bus.Subscribe(events.EventTypeStatus, func(e events.Event) error {
+		statuses <- e.(*events.StatusEvent).Status
+		return nil
+	})
+
+	config := data.DefaultMarketDataConfig()
+	config.BinanceWSURL = "ws" + strings.TrimPrefix(server.URL, "http")
+	config.Symbols = []string{"BTCUSDT"}
+	config.Intervals = []string{"1m"}
+	config.ReconnectDelay = 10 * time.Millisecond
+	config.MaxReconnectDelay = 50 * time.Millisecond
+	config.StaleAfter = 200 * time.Millisecond
+
+	service := data.NewMarketDataService(zap.NewNop(), config)
+	service.SetEventBus(bus)
+	staleUpdates := make(chan data.PriceUpdate, 10)
+	service.OnPrice(func(update data.PriceUpdate) {
+		if update.Stale {
+			staleUpdates <- update
+		}
+	})
+
+	if err := service.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start: %v", err)
+	}
+	defer service.Stop()
+
+	timeout := time.After(3 * time.Second)
+	for i := 1; i <= 2; i++ {
+		select {
+		case params := <-subscribed:
+			want := []string{"btcusdt@ticker", "btcusdt@trade", "btcusdt@depth20@100ms", "btcusdt@kline_1m"}
+			if strings.Join(params, ",") != strings.Join(want, ",") {
+				t.Errorf("Connection %d: expected subscription to %v, got %v", i, want, params)
+			}
+		case <-timeout:
+			t.Fatalf("Expected connection %d to subscribe", i)
+		}
+	}
+
+	var changes []string
+	for len(changes) < 2 {
+		select {
+		case status := <-statuses:
+			changes = append(changes, status)
+		case <-timeout:
+			t.Fatalf("Expected disconnect and reconnect status events, got %v", changes)
+		}
+	}
+	if changes[0] != "market_data_disconnected" || changes[1] != "market_data_connected" {
+		t.Errorf("Expected disconnected then connected, got %v", changes)
+	}
+
+	select {
+	case update := <-staleUpdates:
+		if update.Symbol != "BTCUSDT" || update.Price.IntPart() != 50000 {
+			t.Errorf("Expected the cached BTCUSDT price flagged stale, got %+v", update)
+		}
+	case <-timeout:
+		t.Fatal("Expected the silent price to be flagged stale")
+	}
+
+	if price, ok := service.GetPrice("BTCUSDT"); !ok || !price.Stale {
+		t.Errorf("Expected GetPrice to report the price stale, got %+v", price)
+	}
+	health := service.Health()
+	if !health.Connected || len(health.Stale) != 1 || health.Stale[0] != "BTCUSDT" {
+		t.Errorf("Expected a connected feed with BTCUSDT stale, got %+v", health)
+	}
+}
@@ -0,0 +1,75 @@
+// Package data provides aggregation of OHLCV bars into higher timeframes.
+package data
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+)
+
+// Resample aggregates bars of duration from into bars of duration to,
+// aligned to UTC clock boundaries: open is the first open, high the max,
+// low the min, close the last close and volume the sum. Each output bar is
+// stamped with its bucket's start.
+//
+// Buckets with no bars are skipped rather than filled, and a bucket missing
+// some bars is still emitted once later bars show it has closed. A trailing
+// bucket whose last bar does not reach its end is still forming and is
+// dropped, so callers never act on a partial bar.
+func Resample(bars []types.OHLCV, from, to time.Duration) ([]types.OHLCV, error) {
+	if from <= 0 || to <= 0 {
+		return nil, fmt.Errorf("invalid resample durations %s -> %s", from, to)
+	}
+	if to < from || to%from != 0 {
+		return nil, fmt.Errorf("cannot resample %s bars to %s: target must be a multiple of the source", from, to)
+	}
+
+	var (
+		out     []types.OHLCV
+		current types.OHLCV
+		open    bool
+		last    time.Time
+	)
+
+	for i, bar := range bars {
+		ts := bar.Timestamp.UTC()
+		if i > 0 && !ts.After(last) {
+			return nil, fmt.Errorf("bar %d at %s is not after %s", i, ts.Format(time.RFC3339), last.Format(time.RFC3339))
+		}
+		last = ts
+
+		bucket := ts.Truncate(to)
+		if open && bucket.Equal(current.Timestamp) {
+			if bar.High.GreaterThan(current.High) {
+				current.High = bar.High
+			}
+			if bar.Low.LessThan(current.Low) {
+				current.Low = bar.Low
+			}
+			current.Close = bar.Close
+			current.Volume = current.Volume.Add(bar.Volume)
+			continue
+		}
+
+		if open {
+			out = append(out, current)
+		}
+		current = types.OHLCV{
+			Timestamp: bucket,
+			Open:      bar.Open,
+			High:      bar.High,
+			Low:       bar.Low,
+			Close:     bar.Close,
+			Volume:    bar.Volume,
+		}
+		open = true
+	}
+
+	// The trailing bucket is complete only if its last bar closes it
+	if open && !last.Add(from).Before(current.Timestamp.Add(to)) {
+		out = append(out, current)
+	}
+
+	return out, nil
+}
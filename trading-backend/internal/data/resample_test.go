@@ -0,0 +1,105 @@
+package data_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/data"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// minuteBar builds a 1m bar at start+minute
+func minuteBar(start time.Time, minute int, open, high, low, close, volume int64) types.OHLCV {
+	return types.OHLCV{
+		Timestamp: start.Add(time.Duration(minute) * time.Minute),
+		Open:      decimal.NewFromInt(open),
+		High:      decimal.NewFromInt(high),
+		Low:       decimal.NewFromInt(low),
+		Close:     decimal.NewFromInt(close),
+		Volume:    decimal.NewFromInt(volume),
+	}
+}
+
+func TestResampleAggregatesClockAlignedBuckets(t *testing.T) {
+	// Starts mid-bucket at 10:03 so the first 5m bucket is 10:00-10:05
+	start := time.Date(2024, 1, 1, 10, 3, 0, 0, time.UTC)
+	bars := []types.OHLCV{
+		minuteBar(start, 0, 100, 102, 99, 101, 5),  // 10:03
+		minuteBar(start, 1, 101, 104, 100, 103, 7), // 10:04
+		minuteBar(start, 2, 103, 103, 97, 98, 4),   // 10:05
+		minuteBar(start, 3, 98, 99, 96, 99, 6),     // 10:06
+		// 10:07 and 10:08 are missing
+		minuteBar(start, 6, 99, 100, 98, 100, 3), // 10:09
+	}
+
+	out, err := data.Resample(bars, time.Minute, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("Resample: %v", err)
+	}
+	if len(out) != 2 {
+		t.Fatalf("got %d bars, want 2", len(out))
+	}
+
+	want := []struct {
+		ts                             time.Time
+		open, high, low, close, volume int64
+	}{
+		{time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), 100, 104, 99, 103, 12},
+		{time.Date(2024, 1, 1, 10, 5, 0, 0, time.UTC), 103, 103, 96, 100, 13},
+	}
+	for i, w := range want {
+		got := out[i]
+		if !got.Timestamp.Equal(w.ts) ||
+			!got.Open.Equal(decimal.NewFromInt(w.open)) ||
+			!got.High.Equal(decimal.NewFromInt(w.high)) ||
+			!got.Low.Equal(decimal.NewFromInt(w.low)) ||
+			!got.Close.Equal(decimal.NewFromInt(w.close)) ||
+			!got.Volume.Equal(decimal.NewFromInt(w.volume)) {
+			t.Errorf("bar %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestResampleDropsFormingTrailingBucket(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var bars []types.OHLCV
+	for i := 0; i < 90; i++ {
+		bars = append(bars, minuteBar(start, i, 100, 101, 99, 100, 1))
+	}
+
+	out, err := data.Resample(bars, time.Minute, time.Hour)
+	if err != nil {
+		t.Fatalf("Resample: %v", err)
+	}
+	if len(out) != 1 || !out[0].Volume.Equal(decimal.NewFromInt(60)) {
+		t.Fatalf("got %+v, want one complete hour", out)
+	}
+
+	// Once the hour's last minute arrives, the second bucket closes
+	for i := 90; i < 120; i++ {
+		bars = append(bars, minuteBar(start, i, 100, 101, 99, 100, 1))
+	}
+	out, _ = data.Resample(bars, time.Minute, time.Hour)
+	if len(out) != 2 {
+		t.Errorf("got %d bars, want 2", len(out))
+	}
+}
+
+func TestResampleRejectsBadInput(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := []types.OHLCV{minuteBar(start, 1, 1, 1, 1, 1, 1), minuteBar(start, 0, 1, 1, 1, 1, 1)}
+
+	if _, err := data.Resample(bars, time.Minute, 5*time.Minute); err == nil {
+		t.Error("unordered bars accepted")
+	}
+	if _, err := data.Resample(nil, 5*time.Minute, 7*time.Minute); err == nil {
+		t.Error("non-multiple target accepted")
+	}
+	if _, err := data.Resample(nil, time.Hour, time.Minute); err == nil {
+		t.Error("downsampling to a smaller timeframe accepted")
+	}
+	if out, err := data.Resample(nil, time.Minute, time.Hour); err != nil || len(out) != 0 {
+		t.Errorf("empty input = %v, %v", out, err)
+	}
+}
@@ -71,29 +71,18 @@ func (s *Store) LoadOHLCV(ctx context.Context, symbol string, timeframe types.Ti
 	}
 	
 	// Load from file
-	filename := filepath.Join(s.dataDir, fmt.Sprintf("%s_%s.json", symbol, timeframe))
-	data, err := os.ReadFile(filename)
+	bars, err := s.readBars(symbol, timeframe)
 	if err != nil {
-		if os.IsNotExist(err) {
-			// Generate sample data for testing
-			s.logger.Info("Generating sample data", zap.String("symbol", symbol))
-			sampleData := s.generateSampleData(symbol, timeframe, start, end)
-			s.cache[cacheKey] = sampleData
-			return sampleData, nil
-		}
-		return nil, fmt.Errorf("failed to read data file: %w", err)
+		return nil, err
 	}
-	
-	var bars []*types.OHLCV
-	if err := json.Unmarshal(data, &bars); err != nil {
-		return nil, fmt.Errorf("failed to parse data: %w", err)
+	if bars == nil {
+		// Generate sample data for testing
+		s.logger.Info("Generating sample data", zap.String("symbol", symbol))
+		sampleData := s.generateSampleData(symbol, timeframe, start, end)
+		s.cache[cacheKey] = sampleData
+		return sampleData, nil
 	}
 	
-	// Sort by timestamp
-	sort.Slice(bars, func(i, j int) bool {
-		return bars[i].Timestamp.Before(bars[j].Timestamp)
-	})
-	
 	// Cache the data
 	s.cache[cacheKey] = bars
 	
@@ -128,11 +117,112 @@ func (s *Store) GetDataRange(symbol string) (start, end time.Time, err error) {
 	return time.Time{}, time.Time{}, fmt.Errorf("no data available for symbol %s", symbol)
 }
 
+// LoadHistory returns a symbol's stored or downloaded bars, or nil if it has
+// none. Unlike LoadOHLCV it never generates sample data, so it is safe for
+// warming up live models.
+func (s *Store) LoadHistory(symbol string, timeframe types.Timeframe) ([]*types.OHLCV, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	
+	return s.readBars(symbol, timeframe)
+}
+
+// readBars reads a symbol's bars from its JSON file, or failing that from a
+// downloaded CSV or Parquet file. It returns nil if there is no file.
+func (s *Store) readBars(symbol string, timeframe types.Timeframe) ([]*types.OHLCV, error) {
+	base := filepath.Join(s.dataDir, fmt.Sprintf("%s_%s", symbol, timeframe))
+	
+	data, err := os.ReadFile(base + ".json")
+	if err == nil {
+		var bars []*types.OHLCV
+		if err := json.Unmarshal(data, &bars); err != nil {
+			return nil, fmt.Errorf("failed to parse data: %w", err)
+		}
+		
+		// Sort by timestamp
+		sort.Slice(bars, func(i, j int) bool {
+			return bars[i].Timestamp.Before(bars[j].Timestamp)
+		})
+		return bars, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read data file: %w", err)
+	}
+	
+	for _, ext := range []string{".csv", ".parquet"} {
+		file, err := os.Open(base + ext)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open data file: %w", err)
+		}
+		loaded, err := LoadOHLCV(symbol, string(timeframe), file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		
+		s.logger.Info("Loaded historical bars",
+			zap.String("symbol", symbol),
+			zap.String("file", base+ext),
+			zap.Int("bars", len(loaded)),
+		)
+		
+		bars := make([]*types.OHLCV, len(loaded))
+		for i := range loaded {
+			bars[i] = &loaded[i]
+		}
+		return bars, nil
+	}
+	
+	return nil, nil
+}
+
+// StoreOHLCV merges fetched bars into a symbol's stored series and persists
+// it. Bars replace stored bars with the same timestamp.
+func (s *Store) StoreOHLCV(symbol string, timeframe types.Timeframe, bars []types.OHLCV) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	
+	// Merge with what is on disk, not the cache, which may hold sample data
+	existing, err := s.readBars(symbol, timeframe)
+	if err != nil {
+		return err
+	}
+	
+	byTime := make(map[int64]*types.OHLCV, len(existing)+len(bars))
+	for _, bar := range existing {
+		byTime[bar.Timestamp.UnixNano()] = bar
+	}
+	for i := range bars {
+		bar := bars[i]
+		bar.Timestamp = bar.Timestamp.UTC()
+		byTime[bar.Timestamp.UnixNano()] = &bar
+	}
+	
+	merged := make([]*types.OHLCV, 0, len(byTime))
+	for _, bar := range byTime {
+		merged = append(merged, bar)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Timestamp.Before(merged[j].Timestamp)
+	})
+	
+	return s.saveLocked(symbol, timeframe, merged)
+}
+
 // SaveOHLCV saves OHLCV data to disk
 func (s *Store) SaveOHLCV(symbol string, timeframe types.Timeframe, bars []*types.OHLCV) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	
+	return s.saveLocked(symbol, timeframe, bars)
+}
+
+// saveLocked writes bars to the symbol's JSON file and updates the cache and
+// metadata. Callers hold s.mu.
+func (s *Store) saveLocked(symbol string, timeframe types.Timeframe, bars []*types.OHLCV) error {
 	filename := filepath.Join(s.dataDir, fmt.Sprintf("%s_%s.json", symbol, timeframe))
 	
 	data, err := json.MarshalIndent(bars, "", "  ")
@@ -150,6 +240,9 @@ func (s *Store) SaveOHLCV(symbol string, timeframe types.Timeframe, bars []*type
 	
 	// Update metadata
 	if len(bars) > 0 {
+		if _, ok := s.metadata[symbol]; !ok {
+			s.symbols = append(s.symbols, symbol)
+		}
 		s.metadata[symbol] = &SymbolMetadata{
 			Symbol:    symbol,
 			StartDate: bars[0].Timestamp,
@@ -184,21 +277,8 @@ func (s *Store) generateSampleData(symbol string, timeframe types.Timeframe, sta
 	var bars []*types.OHLCV
 	
 	// Determine interval
-	var interval time.Duration
-	switch timeframe {
-	case types.Timeframe1m:
-		interval = time.Minute
-	case types.Timeframe5m:
-		interval = 5 * time.Minute
-	case types.Timeframe15m:
-		interval = 15 * time.Minute
-	case types.Timeframe1h:
-		interval = time.Hour
-	case types.Timeframe4h:
-		interval = 4 * time.Hour
-	case types.Timeframe1d:
-		interval = 24 * time.Hour
-	default:
+	interval, ok := TimeframeInterval(timeframe)
+	if !ok {
 		interval = time.Minute
 	}
 	
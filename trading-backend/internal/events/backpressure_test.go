@@ -0,0 +1,174 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/events"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// stalledBus is a one-worker bus whose worker is stuck in a handler until
+// release is called, so every later Publish lands in the buffer.
+type stalledBus struct {
+	*events.EventBus
+	received chan events.Event
+	gate     chan struct{}
+}
+
+func newStalledBus(t *testing.T, config events.EventBusConfig) *stalledBus {
+	t.Helper()
+	config.NumWorkers = 1
+	config.BufferSize = 4
+
+	sb := &stalledBus{
+		EventBus: events.NewEventBus(zap.NewNop(), config),
+		received: make(chan events.Event, 100),
+		gate:     make(chan struct{}),
+	}
+	t.Cleanup(sb.Stop)
+
+	started := make(chan struct{}, 1)
+	sb.SubscribeAll(func(e events.Event) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-sb.gate
+		sb.received <- e
+		return nil
+	}, events.SubscriptionOptions{Async: false})
+
+	sb.Publish(tick(0))
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("worker never picked up the first event")
+	}
+	return sb
+}
+
+func (sb *stalledBus) release() {
+	close(sb.gate)
+}
+
+// drain collects n delivered events
+func (sb *stalledBus) drain(t *testing.T, n int) []events.Event {
+	t.Helper()
+	var out []events.Event
+	for i := 0; i < n; i++ {
+		select {
+		case e := <-sb.received:
+			out = append(out, e)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("got %d events, want %d", len(out), n)
+		}
+	}
+	return out
+}
+
+func tick(price int64) *events.TickEvent {
+	p := decimal.NewFromInt(price)
+	return events.NewTickEvent("BTCUSDT", p, decimal.NewFromInt(1), p, p, time.Now())
+}
+
+func riskAlert() *events.RiskAlertEvent {
+	return events.NewRiskAlertEvent("drawdown", "critical", "drawdown limit", decimal.NewFromFloat(0.2), decimal.NewFromFloat(0.15))
+}
+
+func TestSaturatedBusDropsTicksButNotRiskAlerts(t *testing.T) {
+	bus := newStalledBus(t, events.DefaultEventBusConfig())
+
+	// Fill the buffer, then overflow it with ticks
+	for i := 1; i <= 7; i++ {
+		bus.Publish(tick(int64(i)))
+	}
+	if stats := bus.GetStats(); stats.OverflowDroppedNewest != 3 || stats.EventsDropped != 3 {
+		t.Fatalf("dropped newest = %d, total = %d, want 3", stats.OverflowDroppedNewest, stats.EventsDropped)
+	}
+
+	// Risk alerts have their own buffer; the fifth one waits for space
+	for i := 0; i < 4; i++ {
+		bus.Publish(riskAlert())
+	}
+	published := make(chan error, 1)
+	go func() {
+		published <- bus.PublishContext(context.Background(), riskAlert())
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for bus.GetStats().OverflowBlocked != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("risk alert did not block on a full buffer")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	bus.release()
+	if err := <-published; err != nil {
+		t.Fatalf("blocked risk alert: %v", err)
+	}
+
+	// The stalled tick, 4 buffered ticks and all 5 alerts
+	alerts := 0
+	for _, e := range bus.drain(t, 10) {
+		if e.GetType() == events.EventTypeRiskAlert {
+			alerts++
+		}
+	}
+	if alerts != 5 {
+		t.Errorf("delivered %d risk alerts, want 5", alerts)
+	}
+	if dropped := bus.GetStats().EventsDropped; dropped != 3 {
+		t.Errorf("total dropped = %d, want 3", dropped)
+	}
+}
+
+func TestDropOldestKeepsNewestTicks(t *testing.T) {
+	config := events.DefaultEventBusConfig()
+	config.OverflowPolicies = map[events.EventType]events.OverflowPolicy{
+		events.EventTypeTick: events.OverflowDropOldest,
+	}
+	bus := newStalledBus(t, config)
+
+	for i := 1; i <= 8; i++ {
+		bus.Publish(tick(int64(i)))
+	}
+	if evicted := bus.GetStats().OverflowDroppedOldest; evicted != 4 {
+		t.Fatalf("evicted %d ticks, want 4", evicted)
+	}
+
+	bus.release()
+	delivered := bus.drain(t, 5)
+	for i, e := range delivered[1:] {
+		want := decimal.NewFromInt(int64(i + 5))
+		if price := e.(*events.TickEvent).Price; !price.Equal(want) {
+			t.Errorf("tick %d price = %s, want %s", i, price, want)
+		}
+	}
+}
+
+func TestBlockingPublishGivesUpAtDeadline(t *testing.T) {
+	config := events.DefaultEventBusConfig()
+	config.BlockTimeout = 0
+	bus := newStalledBus(t, config)
+	defer bus.release()
+
+	for i := 0; i < 4; i++ {
+		bus.Publish(riskAlert())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := bus.PublishContext(ctx, riskAlert()); !errors.Is(err, events.ErrEventDropped) {
+		t.Fatalf("PublishContext = %v, want ErrEventDropped", err)
+	}
+
+	stats := bus.GetStats()
+	if stats.OverflowBlocked != 1 || stats.EventsDropped != 1 {
+		t.Errorf("blocked = %d, dropped = %d, want 1 and 1", stats.OverflowBlocked, stats.EventsDropped)
+	}
+}
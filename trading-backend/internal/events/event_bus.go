@@ -5,6 +5,7 @@ package events
 
 import (
 	"context"
+	"errors"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -71,11 +72,6 @@ func NewBaseEvent(eventType EventType, symbol string) BaseEvent {
 	}
 }
 
-// generateEventID creates a unique event ID
-func generateEventID() string {
-	return time.Now().Format("20060102150405.000000000")
-}
-
 // BarEvent contains OHLCV bar data
 type BarEvent struct {
 	BaseEvent
@@ -176,6 +172,29 @@ type PositionEvent struct {
 	RealizedPnL   float64 `json:"realized_pnl"`
 }
 
+// PnLEvent contains mark-to-market PnL across all open positions
+type PnLEvent struct {
+	BaseEvent
+	Positions     []*PositionEvent `json:"positions"`
+	UnrealizedPnL float64          `json:"unrealized_pnl"` // Sum across positions
+	RealizedPnL   float64          `json:"realized_pnl"`   // Sum across positions
+}
+
+// HeartbeatEvent reports overall system health on every health check
+type HeartbeatEvent struct {
+	BaseEvent
+	Status     string            `json:"status"`     // "green", "yellow" or "red"
+	Components map[string]string `json:"components"` // Status by component name
+}
+
+// StatusEvent reports a change in overall system health
+type StatusEvent struct {
+	BaseEvent
+	Status   string `json:"status"`
+	Previous string `json:"previous,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
 // EventHandler is a function that processes events
 type EventHandler func(event Event) error
 
@@ -185,7 +204,7 @@ type EventFilter func(event Event) bool
 // SubscriptionOptions configures subscription behavior
 type SubscriptionOptions struct {
 	Filter     EventFilter // Optional filter
-	Async      bool        // Process in separate goroutine (default: true)
+	Async      bool        // Process in separate goroutine (default: true); ignored on a partitioned bus
 	BufferSize int         // Channel buffer size for async
 }
 
@@ -215,23 +234,47 @@ type EventBusStats struct {
 	P99LatencyNs      int64         `json:"p99_latency_ns"`
 	P99Latency        time.Duration `json:"p99_latency"` // Convenience field
 	ActiveSubscribers int64         `json:"active_subscribers"`
+	LogErrors         int64         `json:"log_errors"` // Events that could not be written to the event log
+
+	// Times each overflow policy kicked in on a full buffer
+	OverflowBlocked       int64 `json:"overflow_blocked"`
+	OverflowDroppedOldest int64 `json:"overflow_dropped_oldest"`
+	OverflowDroppedNewest int64 `json:"overflow_dropped_newest"`
 }
 
-// EventBus is the central event routing system
-// Designed for 100K+ events/sec throughput with goroutine workers
-type EventBus struct {
-	mu             sync.RWMutex
-	subscribers    map[EventType][]*Subscription
-	allSubscribers []*Subscription // Subscribe to all events
+// OverflowPolicy decides what Publish does when the buffer is full
+type OverflowPolicy string
 
-	// Performance
-	eventChan   chan Event
-	workerCount int
+const (
+	// OverflowBlock waits for space until BlockTimeout or the context ends.
+	// Blocking events have their own buffer, so the drop policies can never
+	// evict them.
+	OverflowBlock OverflowPolicy = "block"
+	// OverflowDropOldest evicts the oldest buffered non-blocking event
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+	// OverflowDropNewest discards the event being published
+	OverflowDropNewest OverflowPolicy = "drop_newest"
+)
+
+// ErrEventDropped is returned when an event could not be queued
+var ErrEventDropped = errors.New("event dropped: buffer full")
 
 // EventBusConfig configures the event bus
 type EventBusConfig struct {
-	NumWorkers int `json:"numWorkers"`
-	BufferSize int `json:"bufferSize"`
+	NumWorkers int            `json:"numWorkers"`
+	BufferSize int            `json:"bufferSize"`
+	Log        EventLogConfig `json:"log"` // Append-only log of published events; off unless Log.Dir is set
+
+	// Partitions, when positive, hashes events by symbol onto this many
+	// queues, each drained by a single worker in place of NumWorkers. Events
+	// of one symbol and overflow class are then handled in publish order, and
+	// handlers run on the partition's worker even if subscribed Async.
+	// Blocking-class events may still overtake queued market data.
+	Partitions int `json:"partitions"`
+
+	OverflowPolicy   OverflowPolicy               `json:"overflowPolicy"`   // For types without their own policy
+	OverflowPolicies map[EventType]OverflowPolicy `json:"overflowPolicies"` // Per-type policies
+	BlockTimeout     time.Duration                `json:"blockTimeout"`     // Longest Publish waits under OverflowBlock; 0 waits until the bus stops
 }
 
 // DefaultEventBusConfig returns sensible defaults
@@ -239,15 +282,53 @@ func DefaultEventBusConfig() EventBusConfig {
 	return EventBusConfig{
 		NumWorkers: 16,
 		BufferSize: 100000,
+		Log:        DefaultEventLogConfig(),
+
+		// Market data may be shed under load; trading and risk events may not
+		OverflowPolicy: OverflowDropNewest,
+		OverflowPolicies: map[EventType]OverflowPolicy{
+			EventTypeSignal:     OverflowBlock,
+			EventTypeOrder:      OverflowBlock,
+			EventTypeExecution:  OverflowBlock,
+			EventTypeFill:       OverflowBlock,
+			EventTypeRiskAlert:  OverflowBlock,
+			EventTypeKillSwitch: OverflowBlock,
+			EventTypeDrawdown:   OverflowBlock,
+		},
+		BlockTimeout: 5 * time.Second,
 	}
 }
 
+// EventBus is the central event routing system
+// Designed for 100K+ events/sec throughput with goroutine workers
+type EventBus struct {
+	mu             sync.RWMutex
+	subscribers    map[EventType][]*Subscription
+	allSubscribers []*Subscription // Subscribe to all events
+
+	// Performance
+	queues      []*eventQueue // One per partition, or a single shared queue
+	partitioned bool
+	workerCount int
+
+	// Overflow handling
+	defaultPolicy OverflowPolicy
+	policies      map[EventType]OverflowPolicy
+	blockTimeout  time.Duration
+
 	// Stats
 	eventsPublished   atomic.Int64
 	eventsProcessed   atomic.Int64
 	eventsDropped     atomic.Int64
 	processingErrors  atomic.Int64
 	activeSubscribers atomic.Int64
+	logErrors         atomic.Int64
+	overflowBlocked   atomic.Int64
+	overflowOldest    atomic.Int64
+	overflowNewest    atomic.Int64
+
+	// Write-ahead log; nil when disabled
+	eventLog *eventLog
 
 	// Latency tracking
 	latencies  []int64
@@ -276,52 +357,102 @@ func NewEventBus(logger *zap.Logger, config EventBusConfig) *EventBus {
 		bufferSize = 100000 // 100K event buffer
 	}
 
+	defaultPolicy := config.OverflowPolicy
+	if defaultPolicy == "" {
+		defaultPolicy = OverflowDropNewest
+	}
+	policies := make(map[EventType]OverflowPolicy, len(config.OverflowPolicies))
+	for eventType, policy := range config.OverflowPolicies {
+		policies[eventType] = policy
+	}
+
+	// Partitioned mode: one queue and one worker per partition
+	partitioned := config.Partitions > 0
+	queueCount := 1
+	if partitioned {
+		queueCount = config.Partitions
+		workerCount = config.Partitions
+	}
+	queues := make([]*eventQueue, queueCount)
+	for i := range queues {
+		queues[i] = newEventQueue(bufferSize)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	eb := &EventBus{
 		subscribers:    make(map[EventType][]*Subscription),
 		allSubscribers: make([]*Subscription, 0),
-		eventChan:      make(chan Event, bufferSize),
+		queues:         queues,
+		partitioned:    partitioned,
 		workerCount:    workerCount,
+		defaultPolicy:  defaultPolicy,
+		policies:       policies,
+		blockTimeout:   config.BlockTimeout,
 		ctx:            ctx,
 		cancel:         cancel,
 		logger:         logger,
 		latencies:      make([]int64, 0, 10000),
 	}
 
+	if config.Log.Dir != "" {
+		eventLog, err := openEventLog(config.Log)
+		if err != nil {
+			logger.Error("Event log disabled", zap.Error(err))
+		} else {
+			eb.eventLog = eventLog
+		}
+	}
+
 	// Start worker pool - this enables 1M+ events/sec processing
 	for i := 0; i < workerCount; i++ {
 		eb.wg.Add(1)
-		go eb.worker(i)
+		go eb.worker(queues[i%queueCount])
 	}
 
 	eb.logger.Info("EventBus initialized",
 		zap.Int("workers", workerCount),
 		zap.Int("buffer_size", bufferSize),
+		zap.Bool("partitioned", partitioned),
 	)
 
 	return eb
 }
 
-// worker processes events from the channel
-func (eb *EventBus) worker(id int) {
+// worker processes events from a queue
+func (eb *EventBus) worker(q *eventQueue) {
 	defer eb.wg.Done()
 
 	for {
+		// Blocking events are the ones that must not be lost; serve them first
+		select {
+		case event := <-q.blocking:
+			eb.handleEvent(event)
+			continue
+		default:
+		}
+
 		select {
 		case <-eb.ctx.Done():
 			return
-		case event := <-eb.eventChan:
-			startTime := time.Now()
-			eb.processEvent(event)
-
-			// Track latency
-			latency := time.Since(startTime).Nanoseconds()
-			eb.trackLatency(latency)
+		case event := <-q.blocking:
+			eb.handleEvent(event)
+		case event := <-q.events:
+			eb.handleEvent(event)
 		}
 	}
 }
 
+// handleEvent processes an event and records its latency
+func (eb *EventBus) handleEvent(event Event) {
+	startTime := time.Now()
+	eb.processEvent(event)
+
+	// Track latency
+	latency := time.Since(startTime).Nanoseconds()
+	eb.trackLatency(latency)
+}
+
 // processEvent routes event to subscribers
 func (eb *EventBus) processEvent(event Event) {
 	eb.mu.RLock()
@@ -340,7 +471,7 @@ func (eb *EventBus) processEvent(event Event) {
 			continue
 		}
 
-		if sub.Options.Async {
+		if sub.Options.Async && !eb.partitioned {
 			go eb.executeHandler(sub, event)
 		} else {
 			eb.executeHandler(sub, event)
@@ -357,7 +488,7 @@ func (eb *EventBus) processEvent(event Event) {
 			continue
 		}
 
-		if sub.Options.Async {
+		if sub.Options.Async && !eb.partitioned {
 			go eb.executeHandler(sub, event)
 		} else {
 			eb.executeHandler(sub, event)
@@ -521,23 +652,121 @@ func (eb *EventBus) Unsubscribe(sub *Subscription) {
 	eb.activeSubscribers.Add(-1)
 }
 
-// Publish sends an event to all subscribers (non-blocking)
-// If the buffer is full, the event is dropped and counted
+// Publish sends an event to all subscribers. If the buffer is full, the
+// event type's overflow policy decides whether to wait or drop.
 func (eb *EventBus) Publish(event Event) {
+	eb.PublishContext(context.Background(), event)
+}
+
+// PublishContext is Publish with a context bounding how long an
+// OverflowBlock event waits for buffer space. It returns ErrEventDropped if
+// the event was not queued.
+func (eb *EventBus) PublishContext(ctx context.Context, event Event) error {
+	eb.logEvent(event)
+
+	policy := eb.policyFor(event.GetType())
+	queue := eb.queueFor(event)
+
 	select {
-	case eb.eventChan <- event:
+	case queue.chanFor(policy) <- event:
 		eb.eventsPublished.Add(1)
+		return nil
 	default:
-		// Buffer full - drop event
+	}
+
+	switch policy {
+	case OverflowBlock:
+		return eb.publishBlocking(ctx, queue, event)
+	case OverflowDropOldest:
+		eb.publishDropOldest(queue, event)
+		return nil
+	default:
+		eb.overflowNewest.Add(1)
 		eb.eventsDropped.Add(1)
 		eb.logger.Warn("Event dropped - buffer full",
 			zap.String("event_type", string(event.GetType())),
 		)
+		return ErrEventDropped
+	}
+}
+
+// publishBlocking waits for buffer space until the block timeout, ctx or
+// the bus ends
+func (eb *EventBus) publishBlocking(ctx context.Context, queue *eventQueue, event Event) error {
+	eb.overflowBlocked.Add(1)
+
+	if eb.blockTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, eb.blockTimeout)
+		defer cancel()
+	}
+
+	select {
+	case queue.blocking <- event:
+		eb.eventsPublished.Add(1)
+		return nil
+	case <-ctx.Done():
+	case <-eb.ctx.Done():
+	}
+
+	eb.eventsDropped.Add(1)
+	eb.logger.Error("Event dropped - buffer full past block timeout",
+		zap.String("event_type", string(event.GetType())),
+		zap.String("event_id", event.GetID()),
+	)
+	return ErrEventDropped
+}
+
+// publishDropOldest evicts buffered events until event fits
+func (eb *EventBus) publishDropOldest(queue *eventQueue, event Event) {
+	for {
+		select {
+		case queue.events <- event:
+			eb.eventsPublished.Add(1)
+			return
+		default:
+		}
+
+		select {
+		case evicted := <-queue.events:
+			eb.overflowOldest.Add(1)
+			eb.eventsDropped.Add(1)
+			eb.logger.Warn("Event evicted - buffer full",
+				zap.String("event_type", string(evicted.GetType())),
+				zap.String("for_event_type", string(event.GetType())),
+			)
+		default:
+			// A worker freed space; retry the send
+		}
+	}
+}
+
+// policyFor returns an event type's overflow policy
+func (eb *EventBus) policyFor(eventType EventType) OverflowPolicy {
+	if policy, ok := eb.policies[eventType]; ok {
+		return policy
+	}
+	return eb.defaultPolicy
+}
+
+// logEvent writes an event to the log before it is routed, so events
+// dropped for a full buffer are still recorded
+func (eb *EventBus) logEvent(event Event) {
+	if eb.eventLog == nil {
+		return
+	}
+	if err := eb.eventLog.append(event); err != nil {
+		eb.logErrors.Add(1)
+		eb.logger.Error("Failed to log event",
+			zap.String("event_type", string(event.GetType())),
+			zap.Error(err),
+		)
 	}
 }
 
 // PublishSync sends an event and waits for processing (blocking)
 func (eb *EventBus) PublishSync(event Event) {
+	eb.logEvent(event)
 	eb.eventsPublished.Add(1)
 	eb.processEvent(event)
 }
@@ -557,6 +786,11 @@ func (eb *EventBus) GetStats() EventBusStats {
 		P99LatencyNs:      p99Ns,
 		P99Latency:        time.Duration(p99Ns),
 		ActiveSubscribers: eb.activeSubscribers.Load(),
+		LogErrors:         eb.logErrors.Load(),
+
+		OverflowBlocked:       eb.overflowBlocked.Load(),
+		OverflowDroppedOldest: eb.overflowOldest.Load(),
+		OverflowDroppedNewest: eb.overflowNewest.Load(),
 	}
 }
 
@@ -618,6 +852,12 @@ func (eb *EventBus) Stop() {
 	case <-time.After(5 * time.Second):
 		eb.logger.Warn("EventBus shutdown timed out")
 	}
+
+	if eb.eventLog != nil {
+		if err := eb.eventLog.close(); err != nil {
+			eb.logger.Warn("Failed to close event log", zap.Error(err))
+		}
+	}
 }
 
 // Close is an alias for Stop (for backwards compatibility)
@@ -677,11 +917,11 @@ func NewSignalEvent(symbol, side, strategy string, strength, entry, stopLoss, ta
 		},
 		Symbol:     symbol,
 		Side:       side,
-		Strength:   strength,
+		Strength:   strength.InexactFloat64(),
 		Strategy:   strategy,
-		EntryPrice: entry,
-		StopLoss:   stopLoss,
-		TakeProfit: takeProfit,
+		EntryPrice: entry.InexactFloat64(),
+		StopLoss:   stopLoss.InexactFloat64(),
+		TakeProfit: takeProfit.InexactFloat64(),
 	}
 }
 
@@ -715,10 +955,10 @@ func NewExecutionEvent(execID, orderID, symbol, side string, qty, price, commiss
 		OrderID:     orderID,
 		Symbol:      symbol,
 		Side:        side,
-		Quantity:    qty,
-		Price:       price,
-		Commission:  commission,
-		Slippage:    slippage,
+		Quantity:    qty.InexactFloat64(),
+		Price:       price.InexactFloat64(),
+		Commission:  commission.InexactFloat64(),
+		Slippage:    slippage.InexactFloat64(),
 		LatencyNs:   latencyNs,
 	}
 }
@@ -749,10 +989,54 @@ func NewPositionEvent(symbol, side string, qty, entry, current, unrealizedPnL, r
 		},
 		Symbol:        symbol,
 		Side:          side,
-		Quantity:      qty,
-		EntryPrice:    entry,
-		CurrentPrice:  current,
-		UnrealizedPnL: unrealizedPnL,
-		RealizedPnL:   realizedPnL,
+		Quantity:      qty.InexactFloat64(),
+		EntryPrice:    entry.InexactFloat64(),
+		CurrentPrice:  current.InexactFloat64(),
+		UnrealizedPnL: unrealizedPnL.InexactFloat64(),
+		RealizedPnL:   realizedPnL.InexactFloat64(),
+	}
+}
+
+// NewPnLEvent creates a PnL event totalling the given positions
+func NewPnLEvent(positions []*PositionEvent) *PnLEvent {
+	event := &PnLEvent{
+		BaseEvent: BaseEvent{
+			ID:        generateEventID(),
+			Type:      EventTypePnL,
+			Timestamp: time.Now(),
+		},
+		Positions: positions,
+	}
+	for _, position := range positions {
+		event.UnrealizedPnL += position.UnrealizedPnL
+		event.RealizedPnL += position.RealizedPnL
+	}
+	return event
+}
+
+// NewHeartbeatEvent creates a heartbeat event
+func NewHeartbeatEvent(status string, components map[string]string) *HeartbeatEvent {
+	return &HeartbeatEvent{
+		BaseEvent: BaseEvent{
+			ID:        generateEventID(),
+			Type:      EventTypeHeartbeat,
+			Timestamp: time.Now(),
+		},
+		Status:     status,
+		Components: components,
+	}
+}
+
+// NewStatusEvent creates a status change event
+func NewStatusEvent(status, previous, message string) *StatusEvent {
+	return &StatusEvent{
+		BaseEvent: BaseEvent{
+			ID:        generateEventID(),
+			Type:      EventTypeStatus,
+			Timestamp: time.Now(),
+		},
+		Status:   status,
+		Previous: previous,
+		Message:  message,
 	}
 }
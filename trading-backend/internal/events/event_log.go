@@ -0,0 +1,316 @@
+// Package events provides an append-only log of published events for audit
+// and replay.
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// EventLogConfig configures the event log. Logging is off unless Dir is set.
+type EventLogConfig struct {
+	Dir         string      `json:"dir"`         // Directory of the JSONL log files
+	MaxFileSize int64       `json:"maxFileSize"` // Bytes before rotating to a new file
+	SyncTypes   []EventType `json:"syncTypes"`   // Types fsynced before Publish returns
+}
+
+// DefaultEventLogConfig returns a disabled log that, once given a Dir,
+// rotates every 64MB and fsyncs trading and risk events.
+func DefaultEventLogConfig() EventLogConfig {
+	return EventLogConfig{
+		MaxFileSize: 64 << 20,
+		SyncTypes: []EventType{
+			EventTypeSignal,
+			EventTypeOrder,
+			EventTypeExecution,
+			EventTypeFill,
+			EventTypeRiskAlert,
+			EventTypeKillSwitch,
+			EventTypeDrawdown,
+		},
+	}
+}
+
+const (
+	eventLogPrefix = "events-"
+	eventLogSuffix = ".jsonl"
+)
+
+// RawEvent is a replayed event whose concrete type the log does not know.
+// Payload holds the event as it was logged.
+type RawEvent struct {
+	BaseEvent
+	Payload json.RawMessage `json:"payload"`
+}
+
+// logRecord is one line of the log
+type logRecord struct {
+	Kind  string          `json:"kind"` // Concrete event type, empty if unknown
+	Event json.RawMessage `json:"event"`
+}
+
+// eventLog appends events to size-rotated JSONL files
+type eventLog struct {
+	mu        sync.Mutex
+	config    EventLogConfig
+	syncTypes map[EventType]bool
+	file      *os.File
+	size      int64
+}
+
+// openEventLog creates the log directory and starts a new log file
+func openEventLog(config EventLogConfig) (*eventLog, error) {
+	if err := os.MkdirAll(config.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create event log directory: %w", err)
+	}
+
+	syncTypes := make(map[EventType]bool, len(config.SyncTypes))
+	for _, eventType := range config.SyncTypes {
+		syncTypes[eventType] = true
+	}
+
+	l := &eventLog{config: config, syncTypes: syncTypes}
+	if err := l.rotate(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// append writes an event as one line, rotating first if the file is full
+func (l *eventLog) append(event Event) error {
+	kind := eventKind(event)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	line, err := json.Marshal(logRecord{Kind: kind, Event: payload})
+	if err != nil {
+		return fmt.Errorf("failed to encode log record: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return errors.New("event log closed")
+	}
+	if l.config.MaxFileSize > 0 && l.size > 0 && l.size+int64(len(line)) > l.config.MaxFileSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write event log: %w", err)
+	}
+
+	if l.syncTypes[event.GetType()] {
+		if err := l.file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync event log: %w", err)
+		}
+	}
+	return nil
+}
+
+// rotate closes the current file and opens a new one. Callers hold l.mu.
+func (l *eventLog) rotate() error {
+	if l.file != nil {
+		if err := l.file.Close(); err != nil {
+			return fmt.Errorf("failed to close event log: %w", err)
+		}
+		l.file = nil
+	}
+
+	// Names sort in creation order
+	name := eventLogPrefix + time.Now().UTC().Format("20060102T150405.000000000") + eventLogSuffix
+	file, err := os.OpenFile(filepath.Join(l.config.Dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open event log: %w", err)
+	}
+
+	l.file = file
+	l.size = 0
+	return nil
+}
+
+// close syncs and closes the current file
+func (l *eventLog) close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	l.file.Sync()
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// files returns the log files in the order they were written
+func (l *eventLog) files() ([]string, error) {
+	entries, err := os.ReadDir(l.config.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list event log: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, eventLogPrefix) && strings.HasSuffix(name, eventLogSuffix) {
+			files = append(files, filepath.Join(l.config.Dir, name))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// eventKind names an event's concrete type for the log
+func eventKind(event Event) string {
+	switch event.(type) {
+	case *BarEvent:
+		return "bar"
+	case *TickEvent:
+		return "tick"
+	case *SignalEvent:
+		return "signal"
+	case *OrderEvent:
+		return "order"
+	case *ExecutionEvent:
+		return "execution"
+	case *RiskAlertEvent:
+		return "risk_alert"
+	case *PositionEvent:
+		return "position"
+	case *PnLEvent:
+		return "pnl"
+	default:
+		return ""
+	}
+}
+
+// decodeEvent rebuilds a logged event as its concrete type, or as a RawEvent
+// if the kind is unknown
+func decodeEvent(record logRecord) (Event, error) {
+	var event Event
+	switch record.Kind {
+	case "bar":
+		event = &BarEvent{}
+	case "tick":
+		event = &TickEvent{}
+	case "signal":
+		event = &SignalEvent{}
+	case "order":
+		event = &OrderEvent{}
+	case "execution":
+		event = &ExecutionEvent{}
+	case "risk_alert":
+		event = &RiskAlertEvent{}
+	case "position":
+		event = &PositionEvent{}
+	case "pnl":
+		event = &PnLEvent{}
+	default:
+		raw := &RawEvent{Payload: record.Event}
+		if err := json.Unmarshal(record.Event, &raw.BaseEvent); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	}
+
+	if err := json.Unmarshal(record.Event, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Replay re-publishes logged events stamped at or after from, in the order
+// they were logged. Replayed events go to subscribers without being logged
+// again, and Replay waits for buffer space rather than dropping them.
+// Unreadable lines, such as one cut short by a crash, are skipped.
+func (eb *EventBus) Replay(ctx context.Context, from time.Time) (int, error) {
+	if eb.eventLog == nil {
+		return 0, errors.New("event log is not enabled")
+	}
+
+	files, err := eb.eventLog.files()
+	if err != nil {
+		return 0, err
+	}
+
+	replayed := 0
+	for _, path := range files {
+		n, err := eb.replayFile(ctx, path, from)
+		replayed += n
+		if err != nil {
+			return replayed, err
+		}
+	}
+
+	eb.logger.Info("Replayed event log",
+		zap.Int("events", replayed),
+		zap.Time("from", from),
+	)
+	return replayed, nil
+}
+
+// replayFile re-publishes one log file's events
+func (eb *EventBus) replayFile(ctx context.Context, path string, from time.Time) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open event log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+
+	replayed := 0
+	for line := 1; scanner.Scan(); line++ {
+		var record logRecord
+		err := json.Unmarshal(scanner.Bytes(), &record)
+		var event Event
+		if err == nil {
+			event, err = decodeEvent(record)
+		}
+		if err != nil {
+			eb.logger.Warn("Skipping unreadable event log line",
+				zap.String("file", path),
+				zap.Int("line", line),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		if event.GetTimestamp().Before(from) {
+			continue
+		}
+
+		select {
+		case eb.queueFor(event).chanFor(eb.policyFor(event.GetType())) <- event:
+			eb.eventsPublished.Add(1)
+			replayed++
+		case <-ctx.Done():
+			return replayed, ctx.Err()
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return replayed, fmt.Errorf("failed to read event log: %w", err)
+	}
+	return replayed, nil
+}
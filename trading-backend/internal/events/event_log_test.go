@@ -0,0 +1,126 @@
+package events_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/events"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func newLoggedBus(dir string, maxFileSize int64) *events.EventBus {
+	config := events.DefaultEventBusConfig()
+	config.NumWorkers = 2
+	config.BufferSize = 100
+	config.Log.Dir = dir
+	config.Log.MaxFileSize = maxFileSize
+	return events.NewEventBus(zap.NewNop(), config)
+}
+
+func TestEventLogReplaysAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	bus := newLoggedBus(dir, 0)
+	old := events.NewBarEvent("BTCUSDT", decimal.NewFromInt(1), decimal.NewFromInt(2), decimal.NewFromInt(1), decimal.NewFromInt(2), decimal.NewFromInt(10), time.Now())
+	old.Timestamp = time.Now().Add(-time.Hour)
+	bus.Publish(old)
+
+	cutoff := time.Now().Add(-time.Minute)
+	signal := events.NewSignalEvent("BTCUSDT", "buy", "momentum", decimal.NewFromFloat(0.8), decimal.NewFromInt(100), decimal.NewFromInt(95), decimal.NewFromInt(110))
+	bus.Publish(signal)
+	bus.Publish(events.NewPnLEvent([]*events.PositionEvent{
+		events.NewPositionEvent("BTCUSDT", "long", decimal.NewFromInt(1), decimal.NewFromInt(100), decimal.NewFromInt(105), decimal.NewFromInt(5), decimal.Zero),
+	}))
+	bus.Stop()
+
+	// A fresh bus over the same directory, as after a crash
+	restarted := newLoggedBus(dir, 0)
+	defer restarted.Stop()
+
+	received := make(chan events.Event, 10)
+	restarted.SubscribeAll(func(e events.Event) error {
+		received <- e
+		return nil
+	}, events.SubscriptionOptions{})
+
+	n, err := restarted.Replay(context.Background(), cutoff)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("replayed %d events, want 2", n)
+	}
+
+	byType := make(map[events.EventType]events.Event)
+	for i := 0; i < n; i++ {
+		select {
+		case e := <-received:
+			byType[e.GetType()] = e
+		case <-time.After(2 * time.Second):
+			t.Fatal("replayed events not delivered")
+		}
+	}
+
+	replayedSignal, ok := byType[events.EventTypeSignal].(*events.SignalEvent)
+	if !ok {
+		t.Fatalf("signal replayed as %T", byType[events.EventTypeSignal])
+	}
+	if replayedSignal.ID != signal.ID || replayedSignal.Strategy != "momentum" {
+		t.Errorf("replayed signal = %+v, want %+v", replayedSignal, signal)
+	}
+	if pnl, ok := byType[events.EventTypePnL].(*events.PnLEvent); !ok || pnl.UnrealizedPnL != 5 {
+		t.Errorf("replayed PnL = %+v", byType[events.EventTypePnL])
+	}
+
+	// Replay does not write the events to the log again
+	if n, _ := restarted.Replay(context.Background(), cutoff); n != 2 {
+		t.Errorf("second replay = %d events, want 2", n)
+	}
+}
+
+func TestEventLogRotatesAndSkipsTornLines(t *testing.T) {
+	dir := t.TempDir()
+
+	bus := newLoggedBus(dir, 512)
+	for i := 0; i < 10; i++ {
+		bus.Publish(events.NewRiskAlertEvent("drawdown", "warning", "drawdown rising", decimal.NewFromFloat(0.1), decimal.NewFromFloat(0.2)))
+	}
+	bus.Stop()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "events-*.jsonl"))
+	if len(files) < 2 {
+		t.Fatalf("got %d log files, want rotation", len(files))
+	}
+
+	// Simulate a crash mid-write on the last file
+	last, err := os.OpenFile(files[len(files)-1], os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last.WriteString(`{"kind":"risk_alert","event":{"id":`)
+	last.Close()
+
+	restarted := newLoggedBus(dir, 512)
+	defer restarted.Stop()
+
+	n, err := restarted.Replay(context.Background(), time.Time{})
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if n != 10 {
+		t.Errorf("replayed %d events, want 10", n)
+	}
+}
+
+func TestReplayWithoutLog(t *testing.T) {
+	bus := events.NewEventBus(zap.NewNop(), events.DefaultEventBusConfig())
+	defer bus.Stop()
+
+	if _, err := bus.Replay(context.Background(), time.Time{}); err == nil {
+		t.Error("Replay succeeded without an event log")
+	}
+}
@@ -0,0 +1,70 @@
+// Package events provides the queues that buffer published events for the
+// EventBus workers, optionally partitioned by symbol.
+package events
+
+import (
+	"hash/fnv"
+	"strings"
+)
+
+// eventQueue buffers events for the workers draining it
+type eventQueue struct {
+	events   chan Event
+	blocking chan Event // Events under OverflowBlock
+}
+
+func newEventQueue(size int) *eventQueue {
+	return &eventQueue{
+		events:   make(chan Event, size),
+		blocking: make(chan Event, size),
+	}
+}
+
+// chanFor returns the channel events under policy are queued on
+func (q *eventQueue) chanFor(policy OverflowPolicy) chan Event {
+	if policy == OverflowBlock {
+		return q.blocking
+	}
+	return q.events
+}
+
+// queueFor returns the queue an event is published to. With partitions,
+// every event of a symbol hashes to the same queue and so the same worker.
+func (eb *EventBus) queueFor(event Event) *eventQueue {
+	if len(eb.queues) == 1 {
+		return eb.queues[0]
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(partitionKey(eventSymbol(event))))
+	return eb.queues[h.Sum32()%uint32(len(eb.queues))]
+}
+
+// partitionKey normalizes a symbol so "BTC/USDT" and "btc-usdt" share a
+// partition
+func partitionKey(symbol string) string {
+	return strings.ToUpper(strings.NewReplacer("/", "", "-", "", "_", "").Replace(symbol))
+}
+
+// eventSymbol returns the symbol an event concerns, or "" for portfolio-wide
+// and unknown events, which share one partition
+func eventSymbol(event Event) string {
+	switch e := event.(type) {
+	case *BarEvent:
+		return e.Symbol
+	case *TickEvent:
+		return e.Symbol
+	case *SignalEvent:
+		return e.Symbol
+	case *OrderEvent:
+		return e.Symbol
+	case *ExecutionEvent:
+		return e.Symbol
+	case *RiskAlertEvent:
+		return e.Symbol
+	case *PositionEvent:
+		return e.Symbol
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,96 @@
+package events_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/events"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func TestPartitionedBusKeepsPerSymbolOrder(t *testing.T) {
+	config := events.DefaultEventBusConfig()
+	config.Partitions = 4
+	bus := events.NewEventBus(zap.NewNop(), config)
+	defer bus.Stop()
+
+	const symbols, barsPerSymbol = 8, 100
+
+	var mu sync.Mutex
+	seen := make(map[string][]int64)
+	var wg sync.WaitGroup
+	wg.Add(symbols * barsPerSymbol)
+
+	// Async is the default; a partitioned bus must still deliver in order
+	bus.Subscribe(events.EventTypeBar, func(e events.Event) error {
+		defer wg.Done()
+		bar := e.(*events.BarEvent)
+		time.Sleep(time.Duration(rand.Intn(50)) * time.Microsecond)
+
+		mu.Lock()
+		seen[bar.Symbol] = append(seen[bar.Symbol], bar.Close.IntPart())
+		mu.Unlock()
+		return nil
+	})
+
+	for i := 0; i < barsPerSymbol; i++ {
+		for s := 0; s < symbols; s++ {
+			price := decimal.NewFromInt(int64(i))
+			bus.Publish(events.NewBarEvent(fmt.Sprintf("SYM%d", s), price, price, price, price, decimal.NewFromInt(1), time.Now()))
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("bars not delivered")
+	}
+
+	for symbol, closes := range seen {
+		for i, c := range closes {
+			if c != int64(i) {
+				t.Fatalf("%s bar %d has close %d: out of order", symbol, i, c)
+			}
+		}
+	}
+}
+
+func TestPartitionedBusNormalizesSymbols(t *testing.T) {
+	config := events.DefaultEventBusConfig()
+	config.Partitions = 8
+	bus := events.NewEventBus(zap.NewNop(), config)
+	defer bus.Stop()
+
+	received := make(chan int64, 100)
+	bus.Subscribe(events.EventTypeTick, func(e events.Event) error {
+		received <- e.(*events.TickEvent).Price.IntPart()
+		return nil
+	})
+
+	// One market under three spellings must share a partition
+	spellings := []string{"BTC/USDT", "btcusdt", "BTC-USDT"}
+	for i := 0; i < 60; i++ {
+		p := decimal.NewFromInt(int64(i))
+		bus.Publish(events.NewTickEvent(spellings[i%len(spellings)], p, decimal.NewFromInt(1), p, p, time.Now()))
+	}
+
+	for i := 0; i < 60; i++ {
+		select {
+		case got := <-received:
+			if got != int64(i) {
+				t.Fatalf("tick %d arrived as %d", i, got)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("ticks not delivered")
+		}
+	}
+}
@@ -0,0 +1,249 @@
+// Package adapters provides the common exchange adapter interface and registry.
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// ExchangeAdapter is implemented by every centralized exchange integration
+// so the executor can route orders without knowing the venue.
+type ExchangeAdapter interface {
+	Name() string
+	Connect(ctx context.Context) error
+	Disconnect() error
+
+	// Trading
+	PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error)
+	CancelOrder(ctx context.Context, orderID string) error
+	GetOrder(ctx context.Context, orderID string) (*types.Order, error)
+
+	// Account
+	GetBalance(ctx context.Context, asset string) (decimal.Decimal, error)
+	GetPositions(ctx context.Context) ([]*types.Position, error)
+
+	// Market data
+	GetOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error)
+	GetTicker(ctx context.Context, symbol string) (*Ticker, error)
+}
+
+// OCOAdapter is implemented by adapters that can submit take-profit and
+// stop-loss exits as one order list, so filling one leg cancels the other.
+type OCOAdapter interface {
+	PlaceOCOOrder(ctx context.Context, order *OCOOrder) (*OCOResult, error)
+	CancelOCOOrder(ctx context.Context, orderListID string) error
+}
+
+// OpenOrdersAdapter is implemented by adapters that can list resting orders,
+// which reconciliation needs to rebuild order state after a restart.
+type OpenOrdersAdapter interface {
+	// GetOpenOrders returns open orders for symbol, or for every symbol when
+	// symbol is empty.
+	GetOpenOrders(ctx context.Context, symbol string) ([]*types.Order, error)
+}
+
+// PingAdapter is implemented by adapters that can cheaply check they can
+// reach the venue, which health monitoring polls.
+type PingAdapter interface {
+	Ping(ctx context.Context) error
+}
+
+// OrderBookStreamer is implemented by adapters that keep live order books
+// from the venue's WebSocket, passing a copy of the full book to the
+// callback after each update.
+type OrderBookStreamer interface {
+	SubscribeToOrderBook(ctx context.Context, symbols []string, callback func(symbol string, ob *types.OrderBook)) error
+}
+
+// ClientOrderLookup is implemented by adapters that can find an order by the
+// client order ID it was placed with, so a retried submission can tell
+// whether an earlier attempt reached the venue. It returns ErrOrderNotFound
+// when the venue has no such order.
+type ClientOrderLookup interface {
+	GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*types.Order, error)
+}
+
+// ErrOrderNotFound is returned when a venue has no order with the ID asked for.
+var ErrOrderNotFound = errors.New("order not found")
+
+// FundingRateAdapter is implemented by adapters quoting perpetual futures,
+// whose open positions are charged or credited funding at each settlement.
+type FundingRateAdapter interface {
+	GetFundingRate(ctx context.Context, symbol string) (*FundingRate, error)
+}
+
+// FundingRate is a perpetual's funding. A positive rate is paid by longs to
+// shorts, as a fraction of position notional at the mark price.
+type FundingRate struct {
+	Symbol          string          `json:"symbol"`
+	Rate            decimal.Decimal `json:"rate"`            // Rate of the last settlement
+	FundingTime     time.Time       `json:"fundingTime"`     // When the last settlement happened
+	PredictedRate   decimal.Decimal `json:"predictedRate"`   // Rate estimated for the next settlement
+	NextFundingTime time.Time       `json:"nextFundingTime"` // When the next settlement happens
+	Interval        time.Duration   `json:"interval"`        // Time between settlements
+	MarkPrice       decimal.Decimal `json:"markPrice"`
+	Timestamp       time.Time       `json:"timestamp"`
+}
+
+// OCOOrder describes a bracket exit. Side applies to both legs. A zero
+// StopLimitPrice makes the stop leg a market order once triggered.
+type OCOOrder struct {
+	Symbol            string          `json:"symbol"`
+	Side              types.OrderSide `json:"side"`
+	Quantity          decimal.Decimal `json:"quantity"`
+	TakeProfitPrice   decimal.Decimal `json:"takeProfitPrice"`
+	StopPrice         decimal.Decimal `json:"stopPrice"`
+	StopLimitPrice    decimal.Decimal `json:"stopLimitPrice,omitempty"`
+	ListClientOrderID string          `json:"listClientOrderId,omitempty"`
+}
+
+// OCOResult is an accepted OCO order list. Both legs carry OrderListID.
+type OCOResult struct {
+	OrderListID string       `json:"orderListId"`
+	TakeProfit  *types.Order `json:"takeProfit"`
+	StopLoss    *types.Order `json:"stopLoss"`
+}
+
+// Ticker is a venue-neutral price snapshot.
+type Ticker struct {
+	Symbol    string          `json:"symbol"`
+	LastPrice decimal.Decimal `json:"lastPrice"`
+	BidPrice  decimal.Decimal `json:"bidPrice"`
+	AskPrice  decimal.Decimal `json:"askPrice"`
+	Volume    decimal.Decimal `json:"volume"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// AdapterConfig holds the credentials used to construct an adapter.
+type AdapterConfig struct {
+	APIKey    string `json:"apiKey"`
+	APISecret string `json:"apiSecret"`
+	Testnet   bool   `json:"testnet"`
+	StateDir  string `json:"stateDir"` // Where adapters keep state across restarts, such as nonces
+}
+
+// AdapterConfigFromEnv reads <NAME>_API_KEY, <NAME>_API_SECRET and
+// <NAME>_TESTNET for the named exchange.
+func AdapterConfigFromEnv(name string) AdapterConfig {
+	prefix := strings.ToUpper(name) + "_"
+	testnet, _ := strconv.ParseBool(os.Getenv(prefix + "TESTNET"))
+
+	return AdapterConfig{
+		APIKey:    os.Getenv(prefix + "API_KEY"),
+		APISecret: os.Getenv(prefix + "API_SECRET"),
+		Testnet:   testnet,
+	}
+}
+
+// AdapterFactory constructs an adapter from its config.
+type AdapterFactory func(logger *zap.Logger, config AdapterConfig) (ExchangeAdapter, error)
+
+// AdapterRegistry manages available exchange adapters by name.
+type AdapterRegistry struct {
+	logger    *zap.Logger
+	factories map[string]AdapterFactory
+	stateDir  string
+	mu        sync.RWMutex
+}
+
+// NewAdapterRegistry creates a registry with the built-in exchanges.
+func NewAdapterRegistry(logger *zap.Logger) *AdapterRegistry {
+	r := &AdapterRegistry{
+		logger:    logger,
+		factories: make(map[string]AdapterFactory),
+	}
+
+	r.Register("binance", func(logger *zap.Logger, config AdapterConfig) (ExchangeAdapter, error) {
+		if config.APIKey == "" || config.APISecret == "" {
+			return nil, fmt.Errorf("binance requires an API key and secret")
+		}
+		return NewBinanceAdapter(logger, BinanceConfig{
+			APIKey:    config.APIKey,
+			APISecret: config.APISecret,
+			Testnet:   config.Testnet,
+		}), nil
+	})
+
+	r.Register("kraken", func(logger *zap.Logger, config AdapterConfig) (ExchangeAdapter, error) {
+		if config.APIKey == "" || config.APISecret == "" {
+			return nil, fmt.Errorf("kraken requires an API key and secret")
+		}
+		if config.Testnet {
+			return nil, fmt.Errorf("kraken has no spot testnet")
+		}
+
+		krakenConfig := KrakenConfig{
+			APIKey:    config.APIKey,
+			APISecret: config.APISecret,
+		}
+		if config.StateDir != "" {
+			krakenConfig.NonceFile = filepath.Join(config.StateDir, "kraken.nonce")
+		} else {
+			logger.Warn("No adapter state directory; kraken nonces will not survive a restart")
+		}
+		return NewKrakenAdapter(logger, krakenConfig)
+	})
+
+	return r
+}
+
+// SetStateDir sets the state directory passed to adapters whose config
+// doesn't name one.
+func (r *AdapterRegistry) SetStateDir(dir string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stateDir = dir
+}
+
+// Register registers an adapter factory under an exchange name.
+func (r *AdapterRegistry) Register(name string, factory AdapterFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[strings.ToLower(name)] = factory
+}
+
+// Create constructs the named adapter.
+func (r *AdapterRegistry) Create(name string, config AdapterConfig) (ExchangeAdapter, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[strings.ToLower(name)]
+	if config.StateDir == "" {
+		config.StateDir = r.stateDir
+	}
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("exchange adapter not registered: %s", name)
+	}
+	return factory(r.logger, config)
+}
+
+// CreateFromEnv constructs the named adapter with credentials from the
+// environment.
+func (r *AdapterRegistry) CreateFromEnv(name string) (ExchangeAdapter, error) {
+	return r.Create(name, AdapterConfigFromEnv(name))
+}
+
+// List returns the registered exchange names in sorted order.
+func (r *AdapterRegistry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,88 @@
+package adapters_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+type stubAdapter struct{ name string }
+
+func (s *stubAdapter) Name() string                      { return s.name }
+func (s *stubAdapter) Connect(ctx context.Context) error { return nil }
+func (s *stubAdapter) Disconnect() error                 { return nil }
+func (s *stubAdapter) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	return order, nil
+}
+func (s *stubAdapter) CancelOrder(ctx context.Context, orderID string) error { return nil }
+func (s *stubAdapter) GetOrder(ctx context.Context, orderID string) (*types.Order, error) {
+	return &types.Order{ID: orderID}, nil
+}
+func (s *stubAdapter) GetBalance(ctx context.Context, asset string) (decimal.Decimal, error) {
+	return decimal.Zero, nil
+}
+func (s *stubAdapter) GetPositions(ctx context.Context) ([]*types.Position, error) {
+	return nil, nil
+}
+func (s *stubAdapter) GetOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error) {
+	return &types.OrderBook{Symbol: symbol}, nil
+}
+func (s *stubAdapter) GetTicker(ctx context.Context, symbol string) (*adapters.Ticker, error) {
+	return &adapters.Ticker{Symbol: symbol}, nil
+}
+
+func TestAdapterRegistryCreatesBinanceFromEnv(t *testing.T) {
+	t.Setenv("BINANCE_API_KEY", "key")
+	t.Setenv("BINANCE_API_SECRET", "secret")
+	t.Setenv("BINANCE_TESTNET", "true")
+
+	registry := adapters.NewAdapterRegistry(zap.NewNop())
+
+	adapter, err := registry.CreateFromEnv("binance")
+	if err != nil {
+		t.Fatalf("CreateFromEnv: %v", err)
+	}
+	if adapter.Name() != "binance" {
+		t.Errorf("Name() = %q, want binance", adapter.Name())
+	}
+}
+
+func TestAdapterRegistryRequiresCredentials(t *testing.T) {
+	registry := adapters.NewAdapterRegistry(zap.NewNop())
+
+	if _, err := registry.Create("binance", adapters.AdapterConfig{}); err == nil {
+		t.Error("expected error creating binance without credentials")
+	}
+}
+
+func TestAdapterRegistryUnknownExchange(t *testing.T) {
+	registry := adapters.NewAdapterRegistry(zap.NewNop())
+
+	if _, err := registry.Create("bybit", adapters.AdapterConfig{}); err == nil {
+		t.Error("expected error for unregistered exchange")
+	}
+}
+
+func TestAdapterRegistryRegister(t *testing.T) {
+	registry := adapters.NewAdapterRegistry(zap.NewNop())
+	registry.Register("Bybit", func(logger *zap.Logger, config adapters.AdapterConfig) (adapters.ExchangeAdapter, error) {
+		return &stubAdapter{name: "bybit"}, nil
+	})
+
+	adapter, err := registry.Create("bybit", adapters.AdapterConfig{})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if adapter.Name() != "bybit" {
+		t.Errorf("Name() = %q, want bybit", adapter.Name())
+	}
+
+	names := registry.List()
+	if len(names) != 3 || names[0] != "binance" || names[1] != "bybit" || names[2] != "kraken" {
+		t.Errorf("List() = %v, want [binance bybit kraken]", names)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -28,6 +30,7 @@ type BinanceAdapter struct {
 	apiKey     string
 	apiSecret  string
 	baseURL    string
+	futuresURL string // USD-M futures REST, for funding rates
 	wsURL      string
 	httpClient *http.Client
 	mu         sync.RWMutex
@@ -35,10 +38,12 @@ type BinanceAdapter struct {
 	// WebSocket connection
 	wsConn     *websocket.Conn
 	wsConnected bool
+	wsClosed    bool     // Set by Disconnect to stop reconnecting
+	streams     []string // Subscribed streams, restored on reconnect
 	
 	// Market data cache
 	tickerCache map[string]*BinanceTicker
-	orderBooks  map[string]*types.OrderBook
+	orderBooks  map[string]*localOrderBook // Live books by BTCUSDT-style symbol
 	
 	// Rate limiting
 	rateLimiter *RateLimiter
@@ -47,6 +52,36 @@ type BinanceAdapter struct {
 	onTicker    func(ticker *BinanceTicker)
 	onOrderBook func(symbol string, ob *types.OrderBook)
 	onTrade     func(trade *BinanceTrade)
+	onResync    func(event WSResyncEvent)
+	
+	// User data stream
+	balances        map[string]BinanceBalance
+	onOrderUpdate   func(update *BinanceOrderUpdate)
+	onBalanceUpdate func(balances []BinanceBalance)
+	
+	// Symbol filters from exchange info
+	symbolFilters   map[string]BinanceSymbolFilters
+	filtersUpdated  time.Time
+	filtersFailures int       // Consecutive failed filter refreshes
+	filtersRetryAt  time.Time // No refresh is attempted before this
+}
+
+var _ ExchangeAdapter = (*BinanceAdapter)(nil)
+
+// WebSocket reconnection settings.
+const (
+	wsBaseBackoff = time.Second
+	wsMaxBackoff  = time.Minute
+	wsReadTimeout = time.Minute // Binance pings every ~20s; allow missed pings before giving up
+)
+
+// WSResyncEvent reports a WebSocket reconnect. Updates between
+// DisconnectedAt and ReconnectedAt may have been missed.
+type WSResyncEvent struct {
+	Streams        []string  `json:"streams"`
+	DisconnectedAt time.Time `json:"disconnectedAt"`
+	ReconnectedAt  time.Time `json:"reconnectedAt"`
+	Attempts       int       `json:"attempts"`
 }
 
 // BinanceConfig contains Binance adapter configuration.
@@ -55,6 +90,11 @@ type BinanceConfig struct {
 	APISecret    string `json:"apiSecret"`
 	Testnet      bool   `json:"testnet"`
 	WSDepthLevel int    `json:"wsDepthLevel"` // 5, 10, or 20
+	
+	// Endpoint overrides, e.g. for a proxy; empty uses Binance's
+	BaseURL        string `json:"baseUrl,omitempty"`
+	FuturesBaseURL string `json:"futuresBaseUrl,omitempty"`
+	WSURL          string `json:"wsUrl,omitempty"`
 }
 
 // BinanceTicker represents a Binance ticker update.
@@ -80,7 +120,8 @@ type BinanceTrade struct {
 	Price    decimal.Decimal `json:"p"`
 	Quantity decimal.Decimal `json:"q"`
 	Time     int64           `json:"T"`
-	IsBuyer  bool            `json:"m"`
+	IsBuyer  bool            `json:"m"` // Buyer is the maker, so the seller was the aggressor
+	Ignore   bool            `json:"M"` // Declared so "M" doesn't case-insensitively match "m"
 }
 
 // BinanceOrder represents a Binance order response.
@@ -97,6 +138,7 @@ type BinanceOrder struct {
 	Type                string          `json:"type"`
 	Side                string          `json:"side"`
 	StopPrice           decimal.Decimal `json:"stopPrice,omitempty"`
+	OrderListID         int64           `json:"orderListId"` // -1 outside an OCO list
 	Time                int64           `json:"time"`
 	UpdateTime          int64           `json:"updateTime"`
 }
@@ -122,73 +164,191 @@ type BinanceAccount struct {
 	Balances         []BinanceBalance `json:"balances"`
 }
 
-// RateLimiter implements simple rate limiting.
+// RateLimiter is a token bucket over Binance request weight. Tokens refill
+// continuously so the full budget is restored once per window.
 type RateLimiter struct {
-	mu       sync.Mutex
-	tokens   int
-	maxTokens int
-	refillRate time.Duration
-	lastRefill time.Time
+	mu           sync.Mutex
+	tokens       float64
+	maxTokens    int
+	window       time.Duration
+	lastRefill   time.Time
+	blockedUntil time.Time // Set from Retry-After on 429/418
 }
 
-// NewRateLimiter creates a new rate limiter.
-func NewRateLimiter(maxTokens int, refillRate time.Duration) *RateLimiter {
+// Binance request weights, per the REST API documentation.
+const (
+	weightPing          = 1
+	weightOrder         = 1
+	weightQueryOrder    = 4
+	weightOpenOrders    = 6  // With a symbol
+	weightAllOpenOrders = 80 // Without a symbol
+	weightAccount       = 20
+	weightTicker        = 2
+	weightExchangeInfo  = 20
+	weightListenKey     = 2
+)
+
+// defaultRetryAfter is used when a 429/418 carries no Retry-After header.
+const defaultRetryAfter = time.Minute
+
+// NewRateLimiter creates a rate limiter allowing maxTokens weight per window.
+func NewRateLimiter(maxTokens int, window time.Duration) *RateLimiter {
 	return &RateLimiter{
-		tokens:     maxTokens,
+		tokens:     float64(maxTokens),
 		maxTokens:  maxTokens,
-		refillRate: refillRate,
+		window:     window,
 		lastRefill: time.Now(),
 	}
 }
 
-// Acquire acquires a token, blocking if necessary.
-func (rl *RateLimiter) Acquire() {
+// Acquire takes weight tokens, blocking until enough have refilled and any
+// exchange-imposed backoff has passed, or until ctx is done. Weights above
+// the bucket size are clamped so they can still proceed from a full bucket.
+func (rl *RateLimiter) Acquire(ctx context.Context, weight int) error {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 	
-	// Refill tokens
-	now := time.Now()
-	elapsed := now.Sub(rl.lastRefill)
-	refills := int(elapsed / rl.refillRate)
-	if refills > 0 {
-		rl.tokens = min(rl.maxTokens, rl.tokens+refills)
-		rl.lastRefill = now
-	}
-	
-	// Wait if no tokens
-	for rl.tokens <= 0 {
+	need := float64(min(weight, rl.maxTokens))
+	for {
+		now := time.Now()
+		rl.refill(now)
+		
+		var wait time.Duration
+		switch {
+		case now.Before(rl.blockedUntil):
+			wait = rl.blockedUntil.Sub(now)
+		case rl.tokens >= need:
+			rl.tokens -= need
+			return nil
+		default:
+			wait = rl.refillTime(need - rl.tokens)
+		}
+		
 		rl.mu.Unlock()
-		time.Sleep(rl.refillRate)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			rl.mu.Lock()
+			return fmt.Errorf("rate limit wait cancelled: %w", ctx.Err())
+		case <-timer.C:
+		}
 		rl.mu.Lock()
-		rl.tokens++
+	}
+}
+
+// AvailableAt returns the tokens the bucket will hold at t without taking any.
+func (rl *RateLimiter) AvailableAt(t time.Time) float64 {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	
+	return rl.availableAt(t)
+}
+
+// Sync resets the bucket to the weight the exchange reports as used in the
+// current window, so local accounting can't drift from Binance's.
+func (rl *RateLimiter) Sync(usedWeight int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	
+	rl.tokens = math.Max(0, float64(rl.maxTokens-usedWeight))
+	rl.lastRefill = time.Now()
+}
+
+// Backoff blocks all Acquire calls for d, as demanded by a Retry-After header.
+func (rl *RateLimiter) Backoff(d time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	
+	if until := time.Now().Add(d); until.After(rl.blockedUntil) {
+		rl.blockedUntil = until
+	}
+}
+
+// refill credits tokens accrued since the last refill.
+func (rl *RateLimiter) refill(now time.Time) {
+	if now.After(rl.lastRefill) {
+		rl.tokens = rl.availableAt(now)
+		rl.lastRefill = now
+	}
+}
+
+// availableAt computes the bucket level at t. Callers hold rl.mu.
+func (rl *RateLimiter) availableAt(t time.Time) float64 {
+	elapsed := t.Sub(rl.lastRefill)
+	if elapsed <= 0 {
+		return rl.tokens
 	}
 	
-	rl.tokens--
+	refilled := float64(rl.maxTokens) * elapsed.Seconds() / rl.window.Seconds()
+	return math.Min(float64(rl.maxTokens), rl.tokens+refilled)
+}
+
+// refillTime returns how long it takes to accrue deficit tokens.
+func (rl *RateLimiter) refillTime(deficit float64) time.Duration {
+	wait := time.Duration(deficit / float64(rl.maxTokens) * float64(rl.window))
+	if wait < time.Millisecond {
+		wait = time.Millisecond
+	}
+	return wait
+}
+
+// depthWeight returns the request weight of an order book snapshot.
+func depthWeight(limit int) int {
+	switch {
+	case limit <= 100:
+		return 5
+	case limit <= 500:
+		return 25
+	case limit <= 1000:
+		return 50
+	default:
+		return 250
+	}
 }
 
 // NewBinanceAdapter creates a new Binance adapter.
 func NewBinanceAdapter(logger *zap.Logger, config BinanceConfig) *BinanceAdapter {
 	baseURL := "https://api.binance.com"
+	futuresURL := "https://fapi.binance.com"
 	wsURL := "wss://stream.binance.com:9443/ws"
 	
 	if config.Testnet {
 		baseURL = "https://testnet.binance.vision"
+		futuresURL = "https://testnet.binancefuture.com"
 		wsURL = "wss://testnet.binance.vision/ws"
 	}
+	if config.BaseURL != "" {
+		baseURL = strings.TrimSuffix(config.BaseURL, "/")
+	}
+	if config.FuturesBaseURL != "" {
+		futuresURL = strings.TrimSuffix(config.FuturesBaseURL, "/")
+	}
+	if config.WSURL != "" {
+		wsURL = strings.TrimSuffix(config.WSURL, "/")
+	}
 	
 	return &BinanceAdapter{
-		logger:      logger.Named("binance"),
-		apiKey:      config.APIKey,
-		apiSecret:   config.APISecret,
-		baseURL:     baseURL,
-		wsURL:       wsURL,
-		httpClient:  &http.Client{Timeout: 30 * time.Second},
-		tickerCache: make(map[string]*BinanceTicker),
-		orderBooks:  make(map[string]*types.OrderBook),
-		rateLimiter: NewRateLimiter(1200, time.Minute), // Binance limit
+		logger:        logger.Named("binance"),
+		apiKey:        config.APIKey,
+		apiSecret:     config.APISecret,
+		baseURL:       baseURL,
+		futuresURL:    futuresURL,
+		wsURL:         wsURL,
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		tickerCache:   make(map[string]*BinanceTicker),
+		orderBooks:    make(map[string]*localOrderBook),
+		balances:      make(map[string]BinanceBalance),
+		symbolFilters: make(map[string]BinanceSymbolFilters),
+		rateLimiter:   NewRateLimiter(6000, time.Minute), // Binance REQUEST_WEIGHT limit
 	}
 }
 
+// Name returns the exchange name used for routing.
+func (b *BinanceAdapter) Name() string {
+	return "binance"
+}
+
 // Connect establishes connection to Binance.
 func (b *BinanceAdapter) Connect(ctx context.Context) error {
 	b.logger.Info("Connecting to Binance")
@@ -207,6 +367,7 @@ func (b *BinanceAdapter) Disconnect() error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	
+	b.wsClosed = true
 	if b.wsConn != nil {
 		err := b.wsConn.Close()
 		b.wsConn = nil
@@ -216,14 +377,31 @@ func (b *BinanceAdapter) Disconnect() error {
 	return nil
 }
 
+// SetOnResync sets the callback invoked after the WebSocket reconnects.
+func (b *BinanceAdapter) SetOnResync(callback func(event WSResyncEvent)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	
+	b.onResync = callback
+}
+
+// Ping tests API connectivity.
+func (b *BinanceAdapter) Ping(ctx context.Context) error {
+	return b.ping(ctx)
+}
+
 // ping tests API connectivity.
 func (b *BinanceAdapter) ping(ctx context.Context) error {
+	if err := b.rateLimiter.Acquire(ctx, weightPing); err != nil {
+		return err
+	}
+	
 	req, err := http.NewRequestWithContext(ctx, "GET", b.baseURL+"/api/v3/ping", nil)
 	if err != nil {
 		return err
 	}
 	
-	resp, err := b.httpClient.Do(req)
+	resp, err := b.do(req)
 	if err != nil {
 		return err
 	}
@@ -238,18 +416,33 @@ func (b *BinanceAdapter) ping(ctx context.Context) error {
 
 // PlaceOrder places an order on Binance.
 func (b *BinanceAdapter) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
-	b.rateLimiter.Acquire()
+	// Round to the symbol's filters so the exchange doesn't reject the order
+	quantity, price, err := b.normalizeOrder(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	
+	if err := b.rateLimiter.Acquire(ctx, weightOrder); err != nil {
+		return nil, err
+	}
 	
 	// Convert order to Binance format
 	params := url.Values{}
 	params.Set("symbol", strings.ReplaceAll(order.Symbol, "/", ""))
 	params.Set("side", strings.ToUpper(string(order.Side)))
 	params.Set("type", b.convertOrderType(order.Type))
-	params.Set("quantity", order.Quantity.String())
+	params.Set("quantity", quantity.String())
 	
 	if order.Type == types.OrderTypeLimit {
-		params.Set("price", order.Price.String())
-		params.Set("timeInForce", "GTC")
+		params.Set("price", price.String())
+		
+		// LIMIT_MAKER orders are rejected rather than take liquidity, and
+		// take no time in force
+		if order.PostOnly {
+			params.Set("type", "LIMIT_MAKER")
+		} else {
+			params.Set("timeInForce", "GTC")
+		}
 	}
 	
 	if order.ClientOrderID != "" {
@@ -269,6 +462,14 @@ func (b *BinanceAdapter) PlaceOrder(ctx context.Context, order *types.Order) (*t
 	}
 	
 	if resp.StatusCode != http.StatusOK {
+		// A retry whose first attempt landed is rejected as a duplicate;
+		// the order it duplicates is the one placed
+		if order.ClientOrderID != "" && isDuplicateOrder(body) {
+			b.logger.Info("Order already placed, fetching it",
+				zap.String("symbol", order.Symbol),
+				zap.String("clientOrderId", order.ClientOrderID))
+			return b.GetOrderByClientID(ctx, order.Symbol, order.ClientOrderID)
+		}
 		return nil, fmt.Errorf("order failed with status %d: %s", resp.StatusCode, string(body))
 	}
 	
@@ -283,7 +484,9 @@ func (b *BinanceAdapter) PlaceOrder(ctx context.Context, order *types.Order) (*t
 
 // CancelOrder cancels an order on Binance.
 func (b *BinanceAdapter) CancelOrder(ctx context.Context, orderID string) error {
-	b.rateLimiter.Acquire()
+	if err := b.rateLimiter.Acquire(ctx, weightOrder); err != nil {
+		return err
+	}
 	
 	// Parse order ID (format: SYMBOL:ORDERID)
 	parts := strings.Split(orderID, ":")
@@ -311,7 +514,9 @@ func (b *BinanceAdapter) CancelOrder(ctx context.Context, orderID string) error
 
 // GetOrder gets an order status from Binance.
 func (b *BinanceAdapter) GetOrder(ctx context.Context, orderID string) (*types.Order, error) {
-	b.rateLimiter.Acquire()
+	if err := b.rateLimiter.Acquire(ctx, weightQueryOrder); err != nil {
+		return nil, err
+	}
 	
 	parts := strings.Split(orderID, ":")
 	if len(parts) != 2 {
@@ -345,6 +550,49 @@ func (b *BinanceAdapter) GetOrder(ctx context.Context, orderID string) (*types.O
 	return b.convertBinanceOrder(&binanceOrder), nil
 }
 
+// GetOpenOrders lists open orders for a symbol, or for all symbols when
+// symbol is empty.
+func (b *BinanceAdapter) GetOpenOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	params := url.Values{}
+	if symbol != "" {
+		if err := b.rateLimiter.Acquire(ctx, weightOpenOrders); err != nil {
+			return nil, err
+		}
+		params.Set("symbol", strings.ReplaceAll(symbol, "/", ""))
+	} else {
+		if err := b.rateLimiter.Acquire(ctx, weightAllOpenOrders); err != nil {
+			return nil, err
+		}
+	}
+	
+	resp, err := b.signedRequest(ctx, "GET", "/api/v3/openOrders", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+	defer resp.Body.Close()
+	
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get open orders failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	
+	var binanceOrders []BinanceOrder
+	if err := json.Unmarshal(body, &binanceOrders); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	
+	orders := make([]*types.Order, 0, len(binanceOrders))
+	for i := range binanceOrders {
+		orders = append(orders, b.convertBinanceOrder(&binanceOrders[i]))
+	}
+	
+	return orders, nil
+}
+
 // GetBalance gets account balance.
 func (b *BinanceAdapter) GetBalance(ctx context.Context, asset string) (decimal.Decimal, error) {
 	account, err := b.GetAccount(ctx)
@@ -363,7 +611,9 @@ func (b *BinanceAdapter) GetBalance(ctx context.Context, asset string) (decimal.
 
 // GetAccount gets full account information.
 func (b *BinanceAdapter) GetAccount(ctx context.Context) (*BinanceAccount, error) {
-	b.rateLimiter.Acquire()
+	if err := b.rateLimiter.Acquire(ctx, weightAccount); err != nil {
+		return nil, err
+	}
 	
 	resp, err := b.signedRequest(ctx, "GET", "/api/v3/account", url.Values{})
 	if err != nil {
@@ -388,6 +638,20 @@ func (b *BinanceAdapter) GetAccount(ctx context.Context) (*BinanceAccount, error
 	return &account, nil
 }
 
+// FeeSchedule returns the account's spot fee tier. Binance reports maker and
+// taker commissions in basis points, charged in the quote asset.
+func (b *BinanceAdapter) FeeSchedule(ctx context.Context) (types.FeeSchedule, error) {
+	account, err := b.GetAccount(ctx)
+	if err != nil {
+		return types.FeeSchedule{}, err
+	}
+	
+	return types.FeeSchedule{
+		MakerBps: decimal.NewFromInt(int64(account.MakerCommission)),
+		TakerBps: decimal.NewFromInt(int64(account.TakerCommission)),
+	}, nil
+}
+
 // GetPositions returns current positions (for spot, this is balances > 0).
 func (b *BinanceAdapter) GetPositions(ctx context.Context) ([]*types.Position, error) {
 	account, err := b.GetAccount(ctx)
@@ -410,9 +674,28 @@ func (b *BinanceAdapter) GetPositions(ctx context.Context) ([]*types.Position, e
 	return positions, nil
 }
 
-// GetTicker gets current ticker for a symbol.
-func (b *BinanceAdapter) GetTicker(ctx context.Context, symbol string) (*BinanceTicker, error) {
-	b.rateLimiter.Acquire()
+// GetTicker gets the current price snapshot for a symbol.
+func (b *BinanceAdapter) GetTicker(ctx context.Context, symbol string) (*Ticker, error) {
+	ticker, err := b.Get24hrTicker(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	
+	return &Ticker{
+		Symbol:    symbol,
+		LastPrice: ticker.LastPrice,
+		BidPrice:  ticker.BidPrice,
+		AskPrice:  ticker.AskPrice,
+		Volume:    ticker.Volume,
+		Timestamp: time.UnixMilli(ticker.CloseTime),
+	}, nil
+}
+
+// Get24hrTicker gets the full 24hr ticker statistics for a symbol.
+func (b *BinanceAdapter) Get24hrTicker(ctx context.Context, symbol string) (*BinanceTicker, error) {
+	if err := b.rateLimiter.Acquire(ctx, weightTicker); err != nil {
+		return nil, err
+	}
 	
 	binanceSymbol := strings.ReplaceAll(symbol, "/", "")
 	
@@ -422,7 +705,7 @@ func (b *BinanceAdapter) GetTicker(ctx context.Context, symbol string) (*Binance
 		return nil, err
 	}
 	
-	resp, err := b.httpClient.Do(req)
+	resp, err := b.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -445,31 +728,46 @@ func (b *BinanceAdapter) GetTicker(ctx context.Context, symbol string) (*Binance
 	return &ticker, nil
 }
 
-// GetOrderBook gets order book for a symbol.
+// GetOrderBook gets order book for a symbol, from the live local book when
+// SubscribeToOrderBook is keeping one in sync and from a REST snapshot
+// otherwise.
 func (b *BinanceAdapter) GetOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error) {
-	b.rateLimiter.Acquire()
+	if ob, ok := b.LocalOrderBook(symbol, limit); ok {
+		return ob, nil
+	}
+	
+	ob, _, err := b.fetchDepthSnapshot(ctx, symbol, limit)
+	return ob, err
+}
+
+// fetchDepthSnapshot gets an order book snapshot over REST along with the
+// last update ID it reflects.
+func (b *BinanceAdapter) fetchDepthSnapshot(ctx context.Context, symbol string, limit int) (*types.OrderBook, int64, error) {
+	if err := b.rateLimiter.Acquire(ctx, depthWeight(limit)); err != nil {
+		return nil, 0, err
+	}
 	
 	binanceSymbol := strings.ReplaceAll(symbol, "/", "")
 	
 	req, err := http.NewRequestWithContext(ctx, "GET",
 		fmt.Sprintf("%s/api/v3/depth?symbol=%s&limit=%d", b.baseURL, binanceSymbol, limit), nil)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	
-	resp, err := b.httpClient.Do(req)
+	resp, err := b.do(req)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer resp.Body.Close()
 	
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("get order book failed: %s", string(body))
+		return nil, 0, fmt.Errorf("get order book failed: %s", string(body))
 	}
 	
 	var rawOB struct {
@@ -479,36 +777,24 @@ func (b *BinanceAdapter) GetOrderBook(ctx context.Context, symbol string, limit
 	}
 	
 	if err := json.Unmarshal(body, &rawOB); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	
 	ob := &types.OrderBook{
 		Symbol:    symbol,
+		Bids:      parseBinanceLevels(rawOB.Bids),
+		Asks:      parseBinanceLevels(rawOB.Asks),
 		Timestamp: time.Now(),
 	}
 	
-	for _, bid := range rawOB.Bids {
-		if len(bid) >= 2 {
-			price, _ := decimal.NewFromString(bid[0])
-			qty, _ := decimal.NewFromString(bid[1])
-			ob.Bids = append(ob.Bids, types.OrderBookLevel{Price: price, Quantity: qty})
-		}
-	}
-	
-	for _, ask := range rawOB.Asks {
-		if len(ask) >= 2 {
-			price, _ := decimal.NewFromString(ask[0])
-			qty, _ := decimal.NewFromString(ask[1])
-			ob.Asks = append(ob.Asks, types.OrderBookLevel{Price: price, Quantity: qty})
-		}
-	}
-	
-	return ob, nil
+	return ob, rawOB.LastUpdateID, nil
 }
 
 // SubscribeToTicker subscribes to ticker updates via WebSocket.
 func (b *BinanceAdapter) SubscribeToTicker(ctx context.Context, symbols []string, callback func(*BinanceTicker)) error {
+	b.mu.Lock()
 	b.onTicker = callback
+	b.mu.Unlock()
 	
 	// Build stream names
 	var streams []string
@@ -520,11 +806,58 @@ func (b *BinanceAdapter) SubscribeToTicker(ctx context.Context, symbols []string
 	return b.subscribeToStreams(ctx, streams)
 }
 
-// subscribeToStreams subscribes to multiple WebSocket streams.
+// SubscribeToTrades subscribes to raw trade updates via WebSocket.
+func (b *BinanceAdapter) SubscribeToTrades(ctx context.Context, symbols []string, callback func(*BinanceTrade)) error {
+	b.mu.Lock()
+	b.onTrade = callback
+	b.mu.Unlock()
+	
+	var streams []string
+	for _, s := range symbols {
+		binanceSymbol := strings.ToLower(strings.ReplaceAll(s, "/", ""))
+		streams = append(streams, binanceSymbol+"@trade")
+	}
+	
+	return b.subscribeToStreams(ctx, streams)
+}
+
+// subscribeToStreams adds streams to the subscription and reconnects the
+// combined WebSocket with the full stream list.
 func (b *BinanceAdapter) subscribeToStreams(ctx context.Context, streams []string) error {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	for _, stream := range streams {
+		if !containsStream(b.streams, stream) {
+			b.streams = append(b.streams, stream)
+		}
+	}
+	allStreams := append([]string(nil), b.streams...)
+	b.wsClosed = false
+	b.mu.Unlock()
 	
+	conn, err := b.dialStreams(ctx, allStreams)
+	if err != nil {
+		return err
+	}
+	
+	b.mu.Lock()
+	previous := b.wsConn
+	b.wsConn = conn
+	b.wsConnected = true
+	b.mu.Unlock()
+	
+	// The previous reader sees it has been superseded and exits
+	if previous != nil {
+		previous.Close()
+	}
+	
+	// Start reading messages
+	go b.readWebSocket(ctx, conn)
+	
+	return nil
+}
+
+// dialStreams opens a combined stream connection with heartbeat handling.
+func (b *BinanceAdapter) dialStreams(ctx context.Context, streams []string) (*websocket.Conn, error) {
 	// Build combined stream URL
 	streamStr := strings.Join(streams, "/")
 	wsURL := b.wsURL + "/" + streamStr
@@ -535,50 +868,164 @@ func (b *BinanceAdapter) subscribeToStreams(ctx context.Context, streams []strin
 	
 	conn, _, err := dialer.DialContext(ctx, wsURL, nil)
 	if err != nil {
-		return fmt.Errorf("failed to connect to WebSocket: %w", err)
+		return nil, fmt.Errorf("failed to connect to WebSocket: %w", err)
 	}
 	
-	b.wsConn = conn
-	b.wsConnected = true
-	
-	// Start reading messages
-	go b.readWebSocket(ctx)
+	// Answer server pings and treat them as proof of life
+	conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+	conn.SetPingHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(10*time.Second))
+		if err == websocket.ErrCloseSent {
+			return nil
+		}
+		return err
+	})
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+	})
 	
-	return nil
+	return conn, nil
 }
 
-// readWebSocket reads messages from WebSocket.
-func (b *BinanceAdapter) readWebSocket(ctx context.Context) {
+// readWebSocket reads messages from conn until it fails, then reconnects
+// unless the connection was closed or replaced.
+func (b *BinanceAdapter) readWebSocket(ctx context.Context, conn *websocket.Conn) {
 	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			b.mu.Lock()
+			current := b.wsConn == conn
+			if current {
+				b.wsConn = nil
+				b.wsConnected = false
+			}
+			b.mu.Unlock()
+			conn.Close()
+			
+			if !current || ctx.Err() != nil {
+				return
+			}
+			
+			b.logger.Error("WebSocket read error, reconnecting", zap.Error(err))
+			b.reconnect(ctx)
+			return
+		}
+		
+		conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+		b.handleWebSocketMessage(message)
+	}
+}
+
+// reconnect re-dials the subscribed streams with capped exponential backoff
+// and jitter, then emits a resync event.
+func (b *BinanceAdapter) reconnect(ctx context.Context) {
+	disconnectedAt := time.Now()
+	
+	for attempt := 0; ; attempt++ {
 		select {
 		case <-ctx.Done():
 			return
-		default:
+		case <-time.After(wsBackoff(attempt)):
 		}
 		
 		b.mu.RLock()
-		conn := b.wsConn
+		closed := b.wsClosed
+		streams := append([]string(nil), b.streams...)
 		b.mu.RUnlock()
-		
-		if conn == nil {
+		if closed {
 			return
 		}
 		
-		_, message, err := conn.ReadMessage()
+		conn, err := b.dialStreams(ctx, streams)
 		if err != nil {
-			b.logger.Error("WebSocket read error", zap.Error(err))
-			b.mu.Lock()
-			b.wsConnected = false
+			b.logger.Warn("WebSocket reconnect failed",
+				zap.Int("attempt", attempt+1),
+				zap.Error(err))
+			continue
+		}
+		
+		b.mu.Lock()
+		if b.wsClosed || b.wsConn != nil {
+			// Disconnected or resubscribed while we were dialing
 			b.mu.Unlock()
+			conn.Close()
 			return
 		}
+		b.wsConn = conn
+		b.wsConnected = true
+		// Diffs were missed while disconnected, so every book starts over
+		for _, book := range b.orderBooks {
+			book.reset()
+		}
+		onResync := b.onResync
+		b.mu.Unlock()
 		
-		b.handleWebSocketMessage(message)
+		go b.readWebSocket(ctx, conn)
+		
+		event := WSResyncEvent{
+			Streams:        streams,
+			DisconnectedAt: disconnectedAt,
+			ReconnectedAt:  time.Now(),
+			Attempts:       attempt + 1,
+		}
+		b.logger.Info("WebSocket reconnected",
+			zap.Int("attempts", event.Attempts),
+			zap.Duration("gap", event.ReconnectedAt.Sub(disconnectedAt)))
+		if onResync != nil {
+			onResync(event)
+		}
+		return
 	}
 }
 
+// wsBackoff returns the delay before a reconnect attempt: exponential from
+// wsBaseBackoff, capped at wsMaxBackoff, with up to 50% jitter.
+func wsBackoff(attempt int) time.Duration {
+	delay := wsMaxBackoff
+	if attempt < 16 {
+		if d := wsBaseBackoff << attempt; d < wsMaxBackoff {
+			delay = d
+		}
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+func containsStream(streams []string, stream string) bool {
+	for _, s := range streams {
+		if s == stream {
+			return true
+		}
+	}
+	return false
+}
+
 // handleWebSocketMessage processes a WebSocket message.
 func (b *BinanceAdapter) handleWebSocketMessage(message []byte) {
+	b.mu.RLock()
+	onTrade, onTicker := b.onTrade, b.onTicker
+	b.mu.RUnlock()
+	
+	// Both keys are declared; "E" would otherwise case-insensitively match "e"
+	var event struct {
+		EventType string `json:"e"`
+		EventTime int64  `json:"E"`
+	}
+	if err := json.Unmarshal(message, &event); err == nil && event.EventType == "trade" {
+		var trade BinanceTrade
+		if err := json.Unmarshal(message, &trade); err == nil && onTrade != nil {
+			onTrade(&trade)
+		}
+		return
+	}
+	if event.EventType == "depthUpdate" {
+		var update binanceDepthUpdate
+		if err := json.Unmarshal(message, &update); err == nil {
+			b.handleDepthUpdate(&update)
+		}
+		return
+	}
+	
 	// Try to parse as ticker
 	var ticker struct {
 		EventType string `json:"e"`
@@ -588,8 +1035,8 @@ func (b *BinanceAdapter) handleWebSocketMessage(message []byte) {
 	}
 	
 	if err := json.Unmarshal(message, &ticker); err == nil {
-		if ticker.EventType == "24hrTicker" && b.onTicker != nil {
-			b.onTicker(&ticker.BinanceTicker)
+		if ticker.EventType == "24hrTicker" && onTicker != nil {
+			onTicker(&ticker.BinanceTicker)
 		}
 	}
 }
@@ -614,7 +1061,33 @@ func (b *BinanceAdapter) signedRequest(ctx context.Context, method, endpoint str
 	
 	req.Header.Set("X-MBX-APIKEY", b.apiKey)
 	
-	return b.httpClient.Do(req)
+	return b.do(req)
+}
+
+// do sends a request and feeds Binance's weight accounting back into the
+// rate limiter. On 429/418 further requests are held off for Retry-After.
+func (b *BinanceAdapter) do(req *http.Request) (*http.Response, error) {
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	
+	if used, err := strconv.Atoi(resp.Header.Get("X-MBX-USED-WEIGHT-1M")); err == nil {
+		b.rateLimiter.Sync(used)
+	}
+	
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot {
+		retryAfter := defaultRetryAfter
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			retryAfter = time.Duration(secs) * time.Second
+		}
+		b.rateLimiter.Backoff(retryAfter)
+		b.logger.Warn("Binance rate limit hit, backing off",
+			zap.Int("status", resp.StatusCode),
+			zap.Duration("retryAfter", retryAfter))
+	}
+	
+	return resp, nil
 }
 
 // sign creates HMAC-SHA256 signature.
@@ -647,6 +1120,7 @@ func (b *BinanceAdapter) convertBinanceOrder(bo *BinanceOrder) *types.Order {
 		ClientOrderID: bo.ClientOrderID,
 		Symbol:        b.formatSymbol(bo.Symbol),
 		Price:         bo.Price,
+		StopPrice:     bo.StopPrice,
 		Quantity:      bo.OrigQty,
 		FilledQty:     bo.ExecutedQty,
 		Status:        b.convertOrderStatus(bo.Status),
@@ -668,6 +1142,16 @@ func (b *BinanceAdapter) convertBinanceOrder(bo *BinanceOrder) *types.Order {
 		order.Type = types.OrderTypeLimit
 	case "STOP_LOSS_LIMIT":
 		order.Type = types.OrderTypeStopLimit
+	case "STOP_LOSS":
+		order.Type = types.OrderTypeStopMarket
+	case "LIMIT_MAKER":
+		// Post-only limits, including the take-profit leg of an OCO list
+		order.Type = types.OrderTypeLimit
+		order.PostOnly = true
+	}
+	
+	if bo.OrderListID > 0 {
+		order.OrderListID = bo.Symbol + ":" + strconv.FormatInt(bo.OrderListID, 10)
 	}
 	
 	return order
@@ -710,14 +1194,16 @@ func (b *BinanceAdapter) formatSymbol(symbol string) string {
 
 // GetExchangeInfo gets exchange trading rules.
 func (b *BinanceAdapter) GetExchangeInfo(ctx context.Context) (*BinanceExchangeInfo, error) {
-	b.rateLimiter.Acquire()
+	if err := b.rateLimiter.Acquire(ctx, weightExchangeInfo); err != nil {
+		return nil, err
+	}
 	
 	req, err := http.NewRequestWithContext(ctx, "GET", b.baseURL+"/api/v3/exchangeInfo", nil)
 	if err != nil {
 		return nil, err
 	}
 	
-	resp, err := b.httpClient.Do(req)
+	resp, err := b.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,81 @@
+// Package adapters provides Binance order lookup by client order ID.
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+)
+
+var _ ClientOrderLookup = (*BinanceAdapter)(nil)
+
+// Binance error codes for order submission and lookup
+const (
+	binanceCodeRejected      = -2010 // New order rejected; "Duplicate order sent." for a reused client order ID
+	binanceCodeOrderNotFound = -2013
+)
+
+// binanceError is the body of a rejected request.
+type binanceError struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// parseBinanceError decodes a rejection body, reporting false if it isn't one.
+func parseBinanceError(body []byte) (binanceError, bool) {
+	var apiErr binanceError
+	if err := json.Unmarshal(body, &apiErr); err != nil || apiErr.Code == 0 {
+		return binanceError{}, false
+	}
+	return apiErr, true
+}
+
+// isDuplicateOrder reports whether an order was rejected because its client
+// order ID matches an order Binance already has open.
+func isDuplicateOrder(body []byte) bool {
+	apiErr, ok := parseBinanceError(body)
+	return ok && apiErr.Code == binanceCodeRejected && strings.Contains(strings.ToLower(apiErr.Msg), "duplicate")
+}
+
+// GetOrderByClientID returns the order placed with clientOrderID. Binance
+// keeps filled and cancelled orders queryable, so an order that landed but
+// whose response was lost is still found after it fills.
+func (b *BinanceAdapter) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*types.Order, error) {
+	if err := b.rateLimiter.Acquire(ctx, weightQueryOrder); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("symbol", strings.ReplaceAll(symbol, "/", ""))
+	params.Set("origClientOrderId", clientOrderID)
+
+	resp, err := b.signedRequest(ctx, "GET", "/api/v3/order", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %s: %w", clientOrderID, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if apiErr, ok := parseBinanceError(body); ok && apiErr.Code == binanceCodeOrderNotFound {
+			return nil, fmt.Errorf("%s: %w", clientOrderID, ErrOrderNotFound)
+		}
+		return nil, fmt.Errorf("get order failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var binanceOrder BinanceOrder
+	if err := json.Unmarshal(body, &binanceOrder); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return b.convertBinanceOrder(&binanceOrder), nil
+}
@@ -0,0 +1,95 @@
+package adapters_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func TestBinanceDuplicateClientOrderIDReturnsPlacedOrder(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		placed = make(map[string]string) // Order JSON by client order ID
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case r.URL.Path == "/api/v3/exchangeInfo":
+			w.Write([]byte(`{"symbols":[{"symbol":"BTCUSDT","filters":[{"filterType":"LOT_SIZE","minQty":"0.00001","maxQty":"9000","stepSize":"0.00001"}]}]}`))
+		case r.URL.Path == "/api/v3/order" && r.Method == "POST":
+			clientID := query.Get("newClientOrderId")
+			mu.Lock()
+			_, duplicate := placed[clientID]
+			if !duplicate {
+				placed[clientID] = fmt.Sprintf(`{"symbol":"BTCUSDT","orderId":%d,"clientOrderId":%q,"origQty":"0.5","executedQty":"0.5","status":"FILLED","type":"MARKET","side":"BUY","orderListId":-1}`, len(placed)+1, clientID)
+			}
+			mu.Unlock()
+
+			if duplicate {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"code":-2010,"msg":"Duplicate order sent."}`))
+				return
+			}
+			// Accepted, but the reply arrives after the client gave up
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte(placed[clientID]))
+		case r.URL.Path == "/api/v3/order" && r.Method == "GET":
+			mu.Lock()
+			order, ok := placed[query.Get("origClientOrderId")]
+			mu.Unlock()
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"code":-2013,"msg":"Order does not exist."}`))
+				return
+			}
+			w.Write([]byte(order))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	b := adapters.NewBinanceAdapter(zap.NewNop(), adapters.BinanceConfig{BaseURL: server.URL})
+	order := &types.Order{
+		ID:            "ord-1",
+		ClientOrderID: "9f2c4e1ab7d05a3c6e8f1b2d4a6c8e0f",
+		Symbol:        "BTC/USDT",
+		Side:          types.OrderSideBuy,
+		Type:          types.OrderTypeMarket,
+		Quantity:      decimal.NewFromFloat(0.5),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := b.PlaceOrder(ctx, order); err == nil {
+		t.Fatal("Expected the first attempt to time out")
+	}
+
+	// The retry reuses the client order ID and gets the order placed first
+	result, err := b.PlaceOrder(context.Background(), order)
+	if err != nil {
+		t.Fatalf("Expected the duplicate to resolve to the placed order, got %v", err)
+	}
+	if result.ID != "BTCUSDT:1" || result.ClientOrderID != order.ClientOrderID || result.Status != types.OrderStatusFilled {
+		t.Errorf("Expected the filled order placed by the first attempt, got %+v", result)
+	}
+	mu.Lock()
+	if len(placed) != 1 {
+		t.Errorf("Expected one order on the venue, got %d", len(placed))
+	}
+	mu.Unlock()
+
+	if _, err := b.GetOrderByClientID(context.Background(), "BTC/USDT", "unknown"); !errors.Is(err, adapters.ErrOrderNotFound) {
+		t.Errorf("Expected ErrOrderNotFound for an unknown client order ID, got %v", err)
+	}
+}
@@ -0,0 +1,337 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// Local order book settings.
+const (
+	depthSnapshotLimit      = 1000 // Levels in the REST snapshot a book starts from
+	maxBufferedDepthUpdates = 1000 // Diffs held while waiting for a snapshot
+)
+
+var _ OrderBookStreamer = (*BinanceAdapter)(nil)
+
+var (
+	// errDepthGap means a diff does not follow on from the book's last
+	// update, so updates were missed and the book needs a new snapshot.
+	errDepthGap = errors.New("order book update sequence gap")
+
+	// errSnapshotBehind means the snapshot predates the first buffered diff.
+	errSnapshotBehind = errors.New("order book snapshot older than buffered updates")
+)
+
+// binanceDepthUpdate is the raw depthUpdate payload from a @depth stream.
+type binanceDepthUpdate struct {
+	EventType     string     `json:"e"`
+	EventTime     int64      `json:"E"`
+	Symbol        string     `json:"s"`
+	FirstUpdateID int64      `json:"U"`
+	FinalUpdateID int64      `json:"u"`
+	Bids          [][]string `json:"b"`
+	Asks          [][]string `json:"a"`
+}
+
+// localOrderBook is an order book kept in sync from a REST snapshot and the
+// @depth diff stream, following Binance's local order book procedure:
+//
+//  1. Buffer diffs from the stream, noting the first one's U.
+//  2. Fetch a snapshot; fetch again while its lastUpdateId is below that U.
+//  3. Drop buffered diffs with u <= lastUpdateId.
+//  4. The first diff applied must have U <= lastUpdateId+1 <= u.
+//  5. Every later diff's U must be the previous diff's u+1; otherwise
+//     updates were missed and the book starts again from 1.
+//
+// Its fields are guarded by the adapter's mutex.
+type localOrderBook struct {
+	symbol       string // As subscribed, e.g. BTC/USDT
+	book         types.OrderBook
+	lastUpdateID int64
+	synced       bool                  // A snapshot has been loaded and diffs apply cleanly
+	bridged      bool                  // A diff has been applied on top of the snapshot
+	buffer       []*binanceDepthUpdate // Diffs received while waiting for a snapshot
+	resync       chan struct{}         // Signalled when diffs are waiting for a snapshot
+}
+
+func newLocalOrderBook(symbol string) *localOrderBook {
+	return &localOrderBook{
+		symbol: symbol,
+		book:   types.OrderBook{Symbol: symbol},
+		resync: make(chan struct{}, 1),
+	}
+}
+
+// reset discards the book's sync state so the next diff starts a new
+// snapshot.
+func (lb *localOrderBook) reset() {
+	lb.synced = false
+	lb.bridged = false
+	lb.buffer = nil
+}
+
+// bufferUpdate holds a diff until a snapshot arrives. The oldest diffs are
+// dropped if the snapshot is slow; a snapshot older than what remains is
+// refetched.
+func (lb *localOrderBook) bufferUpdate(update *binanceDepthUpdate) {
+	if len(lb.buffer) >= maxBufferedDepthUpdates {
+		lb.buffer = lb.buffer[1:]
+	}
+	lb.buffer = append(lb.buffer, update)
+
+	select {
+	case lb.resync <- struct{}{}:
+	default:
+	}
+}
+
+// loadSnapshot replaces the book with a snapshot and replays the buffered
+// diffs over it.
+func (lb *localOrderBook) loadSnapshot(snapshot *types.OrderBook, lastUpdateID int64) error {
+	if len(lb.buffer) > 0 && lastUpdateID < lb.buffer[0].FirstUpdateID {
+		return errSnapshotBehind
+	}
+
+	lb.book = types.OrderBook{
+		Symbol:    lb.symbol,
+		Bids:      snapshot.Bids,
+		Asks:      snapshot.Asks,
+		Timestamp: snapshot.Timestamp,
+	}
+	lb.lastUpdateID = lastUpdateID
+	lb.bridged = false
+
+	buffered := lb.buffer
+	lb.buffer = nil
+	for _, update := range buffered {
+		if _, err := lb.apply(update); err != nil {
+			lb.reset()
+			return err
+		}
+	}
+
+	lb.synced = true
+	return nil
+}
+
+// apply applies a diff to a loaded book. It reports false for a diff the
+// book already reflects, and errDepthGap when diffs were missed.
+func (lb *localOrderBook) apply(update *binanceDepthUpdate) (bool, error) {
+	if update.FinalUpdateID <= lb.lastUpdateID {
+		return false, nil
+	}
+	next := lb.lastUpdateID + 1
+	if (lb.bridged && update.FirstUpdateID != next) || update.FirstUpdateID > next {
+		return false, errDepthGap
+	}
+
+	for _, level := range parseBinanceLevels(update.Bids) {
+		lb.book.Bids = setBookLevel(lb.book.Bids, level.Price, level.Quantity, true)
+	}
+	for _, level := range parseBinanceLevels(update.Asks) {
+		lb.book.Asks = setBookLevel(lb.book.Asks, level.Price, level.Quantity, false)
+	}
+
+	lb.book.Timestamp = time.Now()
+	if update.EventTime > 0 {
+		lb.book.Timestamp = time.UnixMilli(update.EventTime)
+	}
+	lb.lastUpdateID = update.FinalUpdateID
+	lb.bridged = true
+	return true, nil
+}
+
+// snapshot returns a copy of the top limit levels on each side, or the
+// whole book if limit is not positive.
+func (lb *localOrderBook) snapshot(limit int) *types.OrderBook {
+	bids, asks := lb.book.Bids, lb.book.Asks
+	if limit > 0 && len(bids) > limit {
+		bids = bids[:limit]
+	}
+	if limit > 0 && len(asks) > limit {
+		asks = asks[:limit]
+	}
+	return &types.OrderBook{
+		Symbol:    lb.symbol,
+		Bids:      append([]types.OrderBookLevel(nil), bids...),
+		Asks:      append([]types.OrderBookLevel(nil), asks...),
+		Timestamp: lb.book.Timestamp,
+	}
+}
+
+// SubscribeToOrderBook keeps a local order book for each symbol from a REST
+// snapshot and the @depth diff stream, resnapshotting when the stream skips
+// updates. The callback receives a copy of the full book after each update.
+func (b *BinanceAdapter) SubscribeToOrderBook(ctx context.Context, symbols []string, callback func(symbol string, ob *types.OrderBook)) error {
+	b.mu.Lock()
+	b.onOrderBook = callback
+
+	var streams []string
+	var added []*localOrderBook
+	for _, s := range symbols {
+		key := binanceBookKey(s)
+		streams = append(streams, strings.ToLower(key)+"@depth@100ms")
+		if _, ok := b.orderBooks[key]; !ok {
+			book := newLocalOrderBook(s)
+			b.orderBooks[key] = book
+			added = append(added, book)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, book := range added {
+		go b.maintainOrderBook(ctx, book)
+	}
+
+	return b.subscribeToStreams(ctx, streams)
+}
+
+// LocalOrderBook returns the top limit levels of a symbol's live order book,
+// or false if SubscribeToOrderBook is not keeping it in sync.
+func (b *BinanceAdapter) LocalOrderBook(symbol string, limit int) (*types.OrderBook, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	book, ok := b.orderBooks[binanceBookKey(symbol)]
+	if !ok || !book.synced {
+		return nil, false
+	}
+	return book.snapshot(limit), true
+}
+
+// handleDepthUpdate applies a diff to its symbol's book, or buffers it while
+// the book waits for a snapshot.
+func (b *BinanceAdapter) handleDepthUpdate(update *binanceDepthUpdate) {
+	b.mu.Lock()
+	book, ok := b.orderBooks[update.Symbol]
+	if !ok {
+		b.mu.Unlock()
+		return
+	}
+
+	if !book.synced {
+		book.bufferUpdate(update)
+		b.mu.Unlock()
+		return
+	}
+
+	applied, err := book.apply(update)
+	if err != nil {
+		b.logger.Warn("Order book updates missed, resnapshotting",
+			zap.String("symbol", book.symbol),
+			zap.Int64("expected", book.lastUpdateID+1),
+			zap.Int64("received", update.FirstUpdateID))
+		book.reset()
+		book.bufferUpdate(update)
+		b.mu.Unlock()
+		return
+	}
+	if !applied {
+		b.mu.Unlock()
+		return
+	}
+
+	ob := book.snapshot(0)
+	onOrderBook := b.onOrderBook
+	b.mu.Unlock()
+
+	if onOrderBook != nil {
+		onOrderBook(book.symbol, ob)
+	}
+}
+
+// maintainOrderBook loads a snapshot whenever the book has diffs waiting for
+// one, until ctx is done.
+func (b *BinanceAdapter) maintainOrderBook(ctx context.Context, book *localOrderBook) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-book.resync:
+		}
+
+		b.syncOrderBook(ctx, book)
+	}
+}
+
+// syncOrderBook fetches snapshots with backoff until one lines up with the
+// buffered diffs.
+func (b *BinanceAdapter) syncOrderBook(ctx context.Context, book *localOrderBook) {
+	for attempt := 0; ; attempt++ {
+		b.mu.RLock()
+		synced := book.synced
+		b.mu.RUnlock()
+		if synced {
+			return
+		}
+
+		snapshot, lastUpdateID, err := b.fetchDepthSnapshot(ctx, book.symbol, depthSnapshotLimit)
+		if err == nil {
+			b.mu.Lock()
+			err = book.loadSnapshot(snapshot, lastUpdateID)
+			var ob *types.OrderBook
+			if err == nil {
+				ob = book.snapshot(0)
+			}
+			onOrderBook := b.onOrderBook
+			b.mu.Unlock()
+
+			if err == nil {
+				b.logger.Info("Order book synced",
+					zap.String("symbol", book.symbol),
+					zap.Int64("lastUpdateId", lastUpdateID))
+				if onOrderBook != nil {
+					onOrderBook(book.symbol, ob)
+				}
+				return
+			}
+			if errors.Is(err, errDepthGap) {
+				// The buffer was discarded; the next diff starts over
+				return
+			}
+		}
+
+		b.logger.Warn("Order book snapshot failed",
+			zap.String("symbol", book.symbol),
+			zap.Int("attempt", attempt+1),
+			zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wsBackoff(attempt)):
+		}
+	}
+}
+
+// binanceBookKey converts a symbol to the form depth updates carry, e.g.
+// BTC/USDT to BTCUSDT.
+func binanceBookKey(symbol string) string {
+	return strings.ToUpper(strings.ReplaceAll(symbol, "/", ""))
+}
+
+// parseBinanceLevels parses [price, quantity] string pairs.
+func parseBinanceLevels(raw [][]string) []types.OrderBookLevel {
+	levels := make([]types.OrderBookLevel, 0, len(raw))
+	for _, level := range raw {
+		if len(level) < 2 {
+			continue
+		}
+		price, err := decimal.NewFromString(level[0])
+		if err != nil {
+			continue
+		}
+		qty, err := decimal.NewFromString(level[1])
+		if err != nil {
+			continue
+		}
+		levels = append(levels, types.OrderBookLevel{Price: price, Quantity: qty})
+	}
+	return levels
+}
@@ -0,0 +1,142 @@
+package adapters_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+func depthDiff(first, final int64, bids, asks string) string {
+	return fmt.Sprintf(`{"e":"depthUpdate","E":1700000000000,"s":"BTCUSDT","U":%d,"u":%d,"b":%s,"a":%s}`,
+		first, final, bids, asks)
+}
+
+// levels renders a book side as "price:qty" pairs for comparison.
+func levels(side []types.OrderBookLevel) string {
+	var parts []string
+	for _, level := range side {
+		parts = append(parts, level.Price.String()+":"+level.Quantity.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestBinanceLocalOrderBook(t *testing.T) {
+	// The first snapshot predates the first diff and must be refetched
+	snapshots := []string{
+		`{"lastUpdateId":90,"bids":[],"asks":[]}`,
+		`{"lastUpdateId":100,"bids":[["100.0","1"],["99.5","2"]],"asks":[["100.5","1"],["101.0","2"]]}`,
+		`{"lastUpdateId":115,"bids":[["99","1"]],"asks":[["101","1"]]}`,
+	}
+	var fetched int32
+	diffs := make(chan string, 10)
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v3/depth" {
+			n := int(atomic.AddInt32(&fetched, 1)) - 1
+			if n >= len(snapshots) {
+				n = len(snapshots) - 1
+			}
+			w.Write([]byte(snapshots[n]))
+			return
+		}
+		if r.URL.Path != "/ws/btcusdt@depth@100ms" {
+			http.NotFound(w, r)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for diff := range diffs {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(diff)); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+	defer close(diffs)
+
+	b := adapters.NewBinanceAdapter(zap.NewNop(), adapters.BinanceConfig{
+		BaseURL: server.URL,
+		WSURL:   "ws" + strings.TrimPrefix(server.URL, "http") + "/ws",
+	})
+	defer b.Disconnect()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	books := make(chan *types.OrderBook, 20)
+	err := b.SubscribeToOrderBook(ctx, []string{"BTC/USDT"}, func(symbol string, ob *types.OrderBook) {
+		books <- ob
+	})
+	if err != nil {
+		t.Fatalf("SubscribeToOrderBook: %v", err)
+	}
+
+	waitFor := func(bids, asks string) *types.OrderBook {
+		t.Helper()
+		var last *types.OrderBook
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case ob := <-books:
+				last = ob
+				if levels(ob.Bids) == bids && levels(ob.Asks) == asks {
+					return ob
+				}
+			case <-timeout:
+				if last == nil {
+					t.Fatal("no book delivered")
+				}
+				t.Fatalf("book = bids %q asks %q, want bids %q asks %q",
+					levels(last.Bids), levels(last.Asks), bids, asks)
+			}
+		}
+	}
+
+	// The first diff is covered by the snapshot and dropped; the next
+	// straddles the snapshot and the last follows on from it
+	diffs <- depthDiff(95, 100, `[["99.5","9"]]`, `[]`)
+	diffs <- depthDiff(101, 102, `[["100.0","0"]]`, `[["100.2","3"]]`)
+	diffs <- depthDiff(103, 104, `[["99.8","4"]]`, `[]`)
+
+	ob := waitFor("99.8:4 99.5:2", "100.2:3 100.5:1 101:2")
+	if ob.Symbol != "BTC/USDT" {
+		t.Errorf("Symbol = %q, want BTC/USDT", ob.Symbol)
+	}
+
+	local, ok := b.LocalOrderBook("BTCUSDT", 1)
+	if !ok || levels(local.Bids) != "99.8:4" || levels(local.Asks) != "100.2:3" {
+		t.Errorf("LocalOrderBook(1) = %+v, %v", local, ok)
+	}
+	if _, err := b.GetOrderBook(ctx, "BTC/USDT", 5); err != nil || atomic.LoadInt32(&fetched) != 2 {
+		t.Errorf("Expected GetOrderBook to serve the local book, got %v after %d fetches", err, atomic.LoadInt32(&fetched))
+	}
+
+	// Updates 105-109 are missed, so the book is rebuilt from a new snapshot
+	diffs <- depthDiff(110, 111, `[["50","1"]]`, `[]`)
+	waitFor("99:1", "101:1")
+
+	diffs <- depthDiff(114, 116, `[["99","3"]]`, `[]`)
+	waitFor("99:3", "101:1")
+
+	if got := atomic.LoadInt32(&fetched); got != 3 {
+		t.Errorf("Fetched %d snapshots, want 3", got)
+	}
+	if !ob.Timestamp.Equal(time.UnixMilli(1700000000000)) {
+		t.Errorf("Timestamp = %s, want the event time", ob.Timestamp)
+	}
+}
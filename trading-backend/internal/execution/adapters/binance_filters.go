@@ -0,0 +1,244 @@
+// Package adapters provides Binance symbol filter handling.
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// exchangeInfoRefresh is how long cached symbol filters are trusted.
+// Binance changes trading rules rarely, so an hourly refresh is plenty.
+const exchangeInfoRefresh = time.Hour
+
+// BinanceSymbolFilters holds the parsed trading rules for one symbol.
+// Zero values disable the corresponding check.
+type BinanceSymbolFilters struct {
+	TickSize    decimal.Decimal `json:"tickSize"`
+	MinPrice    decimal.Decimal `json:"minPrice"`
+	StepSize    decimal.Decimal `json:"stepSize"`
+	MinQty      decimal.Decimal `json:"minQty"`
+	MaxQty      decimal.Decimal `json:"maxQty"`
+	MinNotional decimal.Decimal `json:"minNotional"`
+}
+
+// ParseSymbolFilters extracts PRICE_FILTER, LOT_SIZE and MIN_NOTIONAL (or
+// its replacement NOTIONAL) from a symbol's exchange info.
+func ParseSymbolFilters(info BinanceSymbolInfo) BinanceSymbolFilters {
+	var filters BinanceSymbolFilters
+
+	for _, f := range info.Filters {
+		switch f.FilterType {
+		case "PRICE_FILTER":
+			filters.TickSize = parseFilterValue(f.TickSize)
+			filters.MinPrice = parseFilterValue(f.MinPrice)
+		case "LOT_SIZE":
+			filters.StepSize = parseFilterValue(f.StepSize)
+			filters.MinQty = parseFilterValue(f.MinQty)
+			filters.MaxQty = parseFilterValue(f.MaxQty)
+		case "MIN_NOTIONAL", "NOTIONAL":
+			filters.MinNotional = parseFilterValue(f.MinNotional)
+		}
+	}
+
+	return filters
+}
+
+// BinanceSymbolSpec converts a symbol's exchange info to the venue-neutral
+// spec the sizing and execution paths round with.
+func BinanceSymbolSpec(info BinanceSymbolInfo) types.SymbolSpec {
+	filters := ParseSymbolFilters(info)
+	return types.SymbolSpec{
+		Symbol:         info.Symbol,
+		TickSize:       filters.TickSize,
+		StepSize:       filters.StepSize,
+		MinNotional:    filters.MinNotional,
+		BasePrecision:  int32(info.BaseAssetPrecision),
+		QuotePrecision: int32(info.QuoteAssetPrecision),
+	}
+}
+
+// parseFilterValue parses a filter string, treating blanks and bad values
+// as zero.
+func parseFilterValue(s string) decimal.Decimal {
+	if s == "" {
+		return decimal.Zero
+	}
+	v, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero
+	}
+	return v
+}
+
+// Normalize floors quantity to the step size and rounds price to the tick
+// size on the passive side for an order of side, then checks the result
+// against the minimums. A zero price (market orders) skips the price and
+// notional checks.
+func (f BinanceSymbolFilters) Normalize(side types.OrderSide, quantity, price decimal.Decimal) (decimal.Decimal, decimal.Decimal, error) {
+	if f.StepSize.IsPositive() {
+		quantity = quantity.Div(f.StepSize).Floor().Mul(f.StepSize)
+	}
+	if !quantity.IsPositive() {
+		return quantity, price, fmt.Errorf("quantity rounds to zero with step size %s", f.StepSize)
+	}
+	if f.MinQty.IsPositive() && quantity.LessThan(f.MinQty) {
+		return quantity, price, fmt.Errorf("quantity %s below minimum %s", quantity, f.MinQty)
+	}
+	if f.MaxQty.IsPositive() && quantity.GreaterThan(f.MaxQty) {
+		return quantity, price, fmt.Errorf("quantity %s above maximum %s", quantity, f.MaxQty)
+	}
+
+	if !price.IsPositive() {
+		return quantity, price, nil
+	}
+
+	price = f.RoundPrice(side, price)
+	if f.MinPrice.IsPositive() && price.LessThan(f.MinPrice) {
+		return quantity, price, fmt.Errorf("price %s below minimum %s", price, f.MinPrice)
+	}
+
+	notional := quantity.Mul(price)
+	if f.MinNotional.IsPositive() && notional.LessThan(f.MinNotional) {
+		return quantity, price, fmt.Errorf("order notional %s below minimum %s", notional, f.MinNotional)
+	}
+
+	return quantity, price, nil
+}
+
+// NormalizeMarket normalizes a market order's quantity and checks its
+// notional at lastPrice, the price the order is expected to fill near. A
+// zero lastPrice skips the notional check.
+func (f BinanceSymbolFilters) NormalizeMarket(quantity, lastPrice decimal.Decimal) (decimal.Decimal, error) {
+	quantity, _, err := f.Normalize(types.OrderSideBuy, quantity, decimal.Zero)
+	if err != nil {
+		return quantity, err
+	}
+
+	notional := quantity.Mul(lastPrice)
+	if f.MinNotional.IsPositive() && lastPrice.IsPositive() && notional.LessThan(f.MinNotional) {
+		return quantity, fmt.Errorf("order notional %s at last price %s below minimum %s", notional, lastPrice, f.MinNotional)
+	}
+	return quantity, nil
+}
+
+// RoundPrice rounds price to the tick on the passive side for an order of
+// side: buys floor and sells ceil.
+func (f BinanceSymbolFilters) RoundPrice(side types.OrderSide, price decimal.Decimal) decimal.Decimal {
+	return types.RoundPriceToTick(price, f.TickSize, side)
+}
+
+// SymbolFilters returns the cached filters for a symbol, refreshing exchange
+// info when the cache is older than exchangeInfoRefresh. A stale cache is
+// used if the refresh fails, and failed refreshes back off exponentially so
+// that every order does not wait on a failing exchangeInfo call.
+func (b *BinanceAdapter) SymbolFilters(ctx context.Context, symbol string) (BinanceSymbolFilters, error) {
+	symbol = strings.ReplaceAll(symbol, "/", "")
+
+	b.mu.RLock()
+	filters, ok := b.symbolFilters[symbol]
+	fresh := time.Since(b.filtersUpdated) < exchangeInfoRefresh
+	retryAt := b.filtersRetryAt
+	b.mu.RUnlock()
+
+	if ok && fresh {
+		return filters, nil
+	}
+
+	if time.Now().Before(retryAt) {
+		if ok {
+			return filters, nil
+		}
+		return BinanceSymbolFilters{}, fmt.Errorf("symbol filters unavailable until %s after failed refresh", retryAt.Format(time.RFC3339))
+	}
+
+	if err := b.refreshSymbolFilters(ctx); err != nil {
+		b.mu.Lock()
+		b.filtersFailures++
+		b.filtersRetryAt = time.Now().Add(wsBackoff(b.filtersFailures - 1))
+		b.mu.Unlock()
+
+		if ok {
+			b.logger.Warn("Using stale symbol filters", zap.String("symbol", symbol), zap.Error(err))
+			return filters, nil
+		}
+		return BinanceSymbolFilters{}, fmt.Errorf("failed to load symbol filters: %w", err)
+	}
+
+	b.mu.RLock()
+	filters, ok = b.symbolFilters[symbol]
+	b.mu.RUnlock()
+
+	if !ok {
+		return BinanceSymbolFilters{}, fmt.Errorf("unknown symbol: %s", symbol)
+	}
+	return filters, nil
+}
+
+// refreshSymbolFilters replaces the filter cache from exchange info.
+func (b *BinanceAdapter) refreshSymbolFilters(ctx context.Context) error {
+	info, err := b.GetExchangeInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	filters := make(map[string]BinanceSymbolFilters, len(info.Symbols))
+	specs := make([]types.SymbolSpec, 0, len(info.Symbols))
+	for _, s := range info.Symbols {
+		filters[s.Symbol] = ParseSymbolFilters(s)
+		spec := BinanceSymbolSpec(s)
+		spec.Venue = b.Name()
+		specs = append(specs, spec)
+	}
+	types.SymbolSpecs.Set(specs...)
+
+	b.mu.Lock()
+	b.symbolFilters = filters
+	b.filtersUpdated = time.Now()
+	b.filtersFailures = 0
+	b.filtersRetryAt = time.Time{}
+	b.mu.Unlock()
+
+	b.logger.Debug("Refreshed symbol filters", zap.Int("symbols", len(filters)))
+	return nil
+}
+
+// normalizeOrder returns the order's quantity and price adjusted to the
+// symbol's filters. Only limit orders carry a price; market orders are
+// checked against the minimum notional at the last traded price.
+func (b *BinanceAdapter) normalizeOrder(ctx context.Context, order *types.Order) (decimal.Decimal, decimal.Decimal, error) {
+	filters, err := b.SymbolFilters(ctx, order.Symbol)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+
+	if order.Type != types.OrderTypeLimit {
+		lastPrice := decimal.Zero
+		if filters.MinNotional.IsPositive() {
+			ticker, err := b.GetTicker(ctx, order.Symbol)
+			if err != nil {
+				// The exchange still enforces the minimum
+				b.logger.Warn("Skipping market order notional check", zap.String("symbol", order.Symbol), zap.Error(err))
+			} else {
+				lastPrice = ticker.LastPrice
+			}
+		}
+
+		quantity, err := filters.NormalizeMarket(order.Quantity, lastPrice)
+		if err != nil {
+			return decimal.Zero, decimal.Zero, fmt.Errorf("order for %s violates exchange filters: %w", order.Symbol, err)
+		}
+		return quantity, decimal.Zero, nil
+	}
+
+	quantity, price, err := filters.Normalize(order.Side, order.Quantity, order.Price)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("order for %s violates exchange filters: %w", order.Symbol, err)
+	}
+	return quantity, price, nil
+}
@@ -0,0 +1,210 @@
+package adapters_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func btcFilters() adapters.BinanceSymbolFilters {
+	return adapters.ParseSymbolFilters(adapters.BinanceSymbolInfo{
+		Symbol: "BTCUSDT",
+		Filters: []adapters.BinanceSymbolFilter{
+			{FilterType: "PRICE_FILTER", MinPrice: "0.01000000", MaxPrice: "1000000.00000000", TickSize: "0.01000000"},
+			{FilterType: "LOT_SIZE", MinQty: "0.00001000", MaxQty: "9000.00000000", StepSize: "0.00001000"},
+			{FilterType: "NOTIONAL", MinNotional: "5.00000000"},
+		},
+	})
+}
+
+func TestParseSymbolFilters(t *testing.T) {
+	f := btcFilters()
+
+	if !f.TickSize.Equal(decimal.RequireFromString("0.01")) {
+		t.Errorf("TickSize = %s, want 0.01", f.TickSize)
+	}
+	if !f.StepSize.Equal(decimal.RequireFromString("0.00001")) {
+		t.Errorf("StepSize = %s, want 0.00001", f.StepSize)
+	}
+	if !f.MinNotional.Equal(decimal.NewFromInt(5)) {
+		t.Errorf("MinNotional = %s, want 5", f.MinNotional)
+	}
+}
+
+func TestNormalizeRoundsToFilters(t *testing.T) {
+	f := btcFilters()
+
+	qty, price, err := f.Normalize(types.OrderSideBuy, decimal.RequireFromString("0.123456789"), decimal.RequireFromString("65000.126"))
+	if err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+
+	if !qty.Equal(decimal.RequireFromString("0.12345")) {
+		t.Errorf("quantity = %s, want 0.12345 (floored to step)", qty)
+	}
+	if !price.Equal(decimal.RequireFromString("65000.12")) {
+		t.Errorf("price = %s, want 65000.12 (buy floored to tick)", price)
+	}
+
+	_, price, err = f.Normalize(types.OrderSideSell, decimal.RequireFromString("0.12345"), decimal.RequireFromString("65000.121"))
+	if err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+	if !price.Equal(decimal.RequireFromString("65000.13")) {
+		t.Errorf("price = %s, want 65000.13 (sell ceiled to tick)", price)
+	}
+}
+
+func TestNormalizeMarketOrderSkipsPrice(t *testing.T) {
+	f := btcFilters()
+
+	qty, price, err := f.Normalize(types.OrderSideBuy, decimal.RequireFromString("0.000019"), decimal.Zero)
+	if err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+	if !qty.Equal(decimal.RequireFromString("0.00001")) {
+		t.Errorf("quantity = %s, want 0.00001", qty)
+	}
+	if !price.IsZero() {
+		t.Errorf("price = %s, want 0", price)
+	}
+}
+
+func TestNormalizeRejectsSmallOrders(t *testing.T) {
+	f := btcFilters()
+
+	tests := []struct {
+		name  string
+		qty   string
+		price string
+	}{
+		{"rounds to zero", "0.000001", "65000"},
+		{"below min notional", "0.00005", "65000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := f.Normalize(types.OrderSideBuy, decimal.RequireFromString(tt.qty), decimal.RequireFromString(tt.price))
+			if err == nil {
+				t.Error("expected error for undersized order")
+			}
+		})
+	}
+}
+
+func TestBinanceSymbolSpecRoundsOrders(t *testing.T) {
+	spec := adapters.BinanceSymbolSpec(adapters.BinanceSymbolInfo{
+		Symbol:              "ETHUSDT",
+		BaseAssetPrecision:  8,
+		QuoteAssetPrecision: 8,
+		Filters: []adapters.BinanceSymbolFilter{
+			{FilterType: "PRICE_FILTER", TickSize: "0.01000000"},
+			{FilterType: "LOT_SIZE", StepSize: "0.00010000"},
+			{FilterType: "NOTIONAL", MinNotional: "5.00000000"},
+		},
+	})
+	spec.Venue = "binance"
+	types.SymbolSpecs.Set(spec)
+
+	if got := types.RoundQuantity("binance", "ETH/USDT", decimal.RequireFromString("0.00129")); !got.Equal(decimal.RequireFromString("0.0012")) {
+		t.Errorf("RoundQuantity = %s, want 0.0012 (floored to step)", got)
+	}
+	if got := types.RoundPrice("binance", "ETHUSDT", types.OrderSideBuy, decimal.RequireFromString("3000.126")); !got.Equal(decimal.RequireFromString("3000.12")) {
+		t.Errorf("RoundPrice = %s, want 3000.12 (a buy never rounds up)", got)
+	}
+	if got := types.RoundPrice("binance", "ETHUSDT", types.OrderSideSell, decimal.RequireFromString("3000.121")); !got.Equal(decimal.RequireFromString("3000.13")) {
+		t.Errorf("RoundPrice = %s, want 3000.13 (a sell never rounds down)", got)
+	}
+
+	spec, ok := types.SymbolSpecs.Get("binance", "eth-usdt")
+	if !ok || spec.BasePrecision != 8 {
+		t.Fatalf("Expected the spec under any spelling of the symbol, got %+v", spec)
+	}
+	if spec.MeetsMinNotional(decimal.RequireFromString("0.0012"), decimal.NewFromInt(3000)) {
+		t.Error("Expected 3.6 USDT to fall below the 5 USDT minimum")
+	}
+	if !spec.MeetsMinNotional(decimal.RequireFromString("0.002"), decimal.NewFromInt(3000)) {
+		t.Error("Expected 6 USDT to meet the minimum")
+	}
+	if _, ok := types.SymbolSpecs.Get("kraken", "ETHUSDT"); ok {
+		t.Error("Expected no kraken spec from binance exchange info")
+	}
+
+	if got := types.RoundQuantity("binance", "XRPUSDT", decimal.RequireFromString("1.23456")); !got.Equal(decimal.RequireFromString("1.23456")) {
+		t.Errorf("Expected symbols without a spec to pass through, got %s", got)
+	}
+}
+
+func TestSymbolSpecsKeepOnePerVenue(t *testing.T) {
+	types.SymbolSpecs.Set(
+		types.SymbolSpec{Venue: "binance", Symbol: "SOLUSDT", TickSize: decimal.RequireFromString("0.01"), StepSize: decimal.RequireFromString("0.001"), MinNotional: decimal.NewFromInt(5)},
+		types.SymbolSpec{Venue: "kraken", Symbol: "SOL/USDT", TickSize: decimal.RequireFromString("0.05"), StepSize: decimal.RequireFromString("0.0001"), MinNotional: decimal.NewFromInt(2)},
+	)
+
+	binance, _ := types.SymbolSpecs.Get("binance", "SOL/USDT")
+	kraken, _ := types.SymbolSpecs.Get("kraken", "SOL/USDT")
+	if !binance.TickSize.Equal(decimal.RequireFromString("0.01")) || !kraken.TickSize.Equal(decimal.RequireFromString("0.05")) {
+		t.Errorf("Expected each venue to keep its own tick, got binance %s kraken %s", binance.TickSize, kraken.TickSize)
+	}
+
+	// Before routing, orders round to what every venue accepts
+	spec, ok := types.SymbolSpecs.Get("", "SOLUSDT")
+	if !ok {
+		t.Fatal("Expected a spec across venues")
+	}
+	if !spec.TickSize.Equal(decimal.RequireFromString("0.05")) || !spec.StepSize.Equal(decimal.RequireFromString("0.001")) || !spec.MinNotional.Equal(decimal.NewFromInt(5)) {
+		t.Errorf("Expected the coarsest tick, step and minimum, got %+v", spec)
+	}
+	if got := types.RoundQuantity("", "SOLUSDT", decimal.RequireFromString("1.23456")); !got.Equal(decimal.RequireFromString("1.234")) {
+		t.Errorf("RoundQuantity = %s, want 1.234", got)
+	}
+}
+
+func TestNormalizeMarketChecksNotionalAtLastPrice(t *testing.T) {
+	f := btcFilters()
+
+	// 0.00005 BTC is $3.25 at $65,000, under the $5 minimum
+	if _, err := f.NormalizeMarket(decimal.RequireFromString("0.00005"), decimal.NewFromInt(65000)); err == nil {
+		t.Error("expected error for market order below min notional")
+	}
+
+	qty, err := f.NormalizeMarket(decimal.RequireFromString("0.000109"), decimal.NewFromInt(65000))
+	if err != nil {
+		t.Fatalf("NormalizeMarket: %v", err)
+	}
+	if !qty.Equal(decimal.RequireFromString("0.0001")) {
+		t.Errorf("quantity = %s, want 0.0001", qty)
+	}
+
+	// Without a last price only the lot size is checked
+	if _, err := f.NormalizeMarket(decimal.RequireFromString("0.00005"), decimal.Zero); err != nil {
+		t.Errorf("unexpected error without a last price: %v", err)
+	}
+}
+
+func TestSymbolFiltersBackOffAfterFailedRefresh(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.Error(w, `{"code":-1001,"msg":"Internal error"}`, http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	b := adapters.NewBinanceAdapter(zap.NewNop(), adapters.BinanceConfig{BaseURL: server.URL})
+
+	for i := 0; i < 3; i++ {
+		if _, err := b.SymbolFilters(context.Background(), "BTC/USDT"); err == nil {
+			t.Fatal("expected an error without exchange info")
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected one exchangeInfo request while backing off, got %d", n)
+	}
+}
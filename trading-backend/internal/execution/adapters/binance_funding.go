@@ -0,0 +1,98 @@
+// Package adapters provides Binance USD-M perpetual funding rates.
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var _ FundingRateAdapter = (*BinanceAdapter)(nil)
+
+// defaultFundingInterval is Binance's usual time between settlements, used
+// until two settlements show a symbol's own.
+const defaultFundingInterval = 8 * time.Hour
+
+// binancePremiumIndex is /fapi/v1/premiumIndex. lastFundingRate is the rate
+// accruing toward nextFundingTime, not the last settled one.
+type binancePremiumIndex struct {
+	Symbol          string          `json:"symbol"`
+	MarkPrice       decimal.Decimal `json:"markPrice"`
+	LastFundingRate decimal.Decimal `json:"lastFundingRate"`
+	NextFundingTime int64           `json:"nextFundingTime"`
+	Time            int64           `json:"time"`
+}
+
+// binanceFundingSettlement is one entry of /fapi/v1/fundingRate.
+type binanceFundingSettlement struct {
+	Symbol      string          `json:"symbol"`
+	FundingRate decimal.Decimal `json:"fundingRate"`
+	FundingTime int64           `json:"fundingTime"`
+}
+
+// GetFundingRate returns a perpetual's last settled and predicted funding
+// from the USD-M futures API. The futures API is public and rate limited
+// separately from spot, so it doesn't draw on the spot weight budget.
+func (b *BinanceAdapter) GetFundingRate(ctx context.Context, symbol string) (*FundingRate, error) {
+	binanceSymbol := strings.ReplaceAll(symbol, "/", "")
+
+	var index binancePremiumIndex
+	if err := b.getFutures(ctx, "/fapi/v1/premiumIndex", url.Values{"symbol": {binanceSymbol}}, &index); err != nil {
+		return nil, fmt.Errorf("failed to get %s premium index: %w", symbol, err)
+	}
+
+	// Oldest first; two settlements give the symbol's interval
+	var settlements []binanceFundingSettlement
+	params := url.Values{"symbol": {binanceSymbol}, "limit": {"2"}}
+	if err := b.getFutures(ctx, "/fapi/v1/fundingRate", params, &settlements); err != nil {
+		return nil, fmt.Errorf("failed to get %s funding history: %w", symbol, err)
+	}
+
+	rate := &FundingRate{
+		Symbol:          symbol,
+		PredictedRate:   index.LastFundingRate,
+		NextFundingTime: time.UnixMilli(index.NextFundingTime),
+		Interval:        defaultFundingInterval,
+		MarkPrice:       index.MarkPrice,
+		Timestamp:       time.UnixMilli(index.Time),
+	}
+	if n := len(settlements); n > 0 {
+		last := settlements[n-1]
+		rate.Rate = last.FundingRate
+		rate.FundingTime = time.UnixMilli(last.FundingTime)
+		if n > 1 {
+			rate.Interval = time.Duration(last.FundingTime-settlements[n-2].FundingTime) * time.Millisecond
+		}
+	}
+	return rate, nil
+}
+
+// getFutures GETs a public futures endpoint and decodes its JSON into v.
+func (b *BinanceAdapter) getFutures(ctx context.Context, endpoint string, params url.Values, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", b.futuresURL+endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
+	}
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,51 @@
+package adapters_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func TestBinanceFundingRate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("symbol") != "BTCUSDT" {
+			http.Error(w, `{"code":-1121,"msg":"Invalid symbol."}`, http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/fapi/v1/premiumIndex":
+			w.Write([]byte(`{"symbol":"BTCUSDT","markPrice":"65000.5","indexPrice":"64990","lastFundingRate":"0.00025","nextFundingTime":1717228800000,"time":1717225200000}`))
+		case "/fapi/v1/fundingRate":
+			w.Write([]byte(`[{"symbol":"BTCUSDT","fundingRate":"0.0001","fundingTime":1717185600000},{"symbol":"BTCUSDT","fundingRate":"0.00015","fundingTime":1717200000000}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	b := adapters.NewBinanceAdapter(zap.NewNop(), adapters.BinanceConfig{FuturesBaseURL: server.URL})
+
+	rate, err := b.GetFundingRate(context.Background(), "BTC/USDT")
+	if err != nil {
+		t.Fatalf("GetFundingRate: %v", err)
+	}
+	if !rate.Rate.Equal(decimal.NewFromFloat(0.00015)) || !rate.FundingTime.Equal(time.UnixMilli(1717200000000)) {
+		t.Errorf("Expected the last settlement, got %s at %s", rate.Rate, rate.FundingTime)
+	}
+	if !rate.PredictedRate.Equal(decimal.NewFromFloat(0.00025)) || !rate.NextFundingTime.Equal(time.UnixMilli(1717228800000)) {
+		t.Errorf("Expected the predicted rate for the next settlement, got %s at %s", rate.PredictedRate, rate.NextFundingTime)
+	}
+	if rate.Interval != 4*time.Hour || !rate.MarkPrice.Equal(decimal.NewFromFloat(65000.5)) {
+		t.Errorf("Expected a 4h interval at a 65000.5 mark, got %s at %s", rate.Interval, rate.MarkPrice)
+	}
+
+	if _, err := b.GetFundingRate(context.Background(), "DOGEBTC"); err == nil {
+		t.Error("Expected an unknown symbol to fail")
+	}
+}
@@ -0,0 +1,194 @@
+// Package adapters provides Binance OCO order support.
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"go.uber.org/zap"
+)
+
+var _ OCOAdapter = (*BinanceAdapter)(nil)
+
+// binanceOCOResponse is the order list returned by /api/v3/order/oco.
+type binanceOCOResponse struct {
+	OrderListID       int64              `json:"orderListId"`
+	ContingencyType   string             `json:"contingencyType"`
+	ListStatusType    string             `json:"listStatusType"`
+	ListOrderStatus   string             `json:"listOrderStatus"`
+	ListClientOrderID string             `json:"listClientOrderId"`
+	TransactionTime   int64              `json:"transactionTime"`
+	Symbol            string             `json:"symbol"`
+	OrderReports      []binanceOCOReport `json:"orderReports"`
+}
+
+// binanceOCOReport is one leg of an OCO response.
+type binanceOCOReport struct {
+	BinanceOrder
+	OrderListID  int64 `json:"orderListId"`
+	TransactTime int64 `json:"transactTime"`
+}
+
+// PlaceOCOOrder submits a take-profit limit leg and a stop-loss leg as one
+// order list, so a fill on either cancels the other.
+func (b *BinanceAdapter) PlaceOCOOrder(ctx context.Context, order *OCOOrder) (*OCOResult, error) {
+	if err := validateOCOPrices(order); err != nil {
+		return nil, err
+	}
+
+	filters, err := b.SymbolFilters(ctx, order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	// The take-profit leg is the one checked against min notional; the stop
+	// leg shares its quantity
+	quantity, takeProfit, err := filters.Normalize(order.Side, order.Quantity, order.TakeProfitPrice)
+	if err != nil {
+		return nil, fmt.Errorf("OCO order for %s violates exchange filters: %w", order.Symbol, err)
+	}
+
+	if err := b.rateLimiter.Acquire(ctx, weightOrder); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("symbol", strings.ReplaceAll(order.Symbol, "/", ""))
+	params.Set("side", strings.ToUpper(string(order.Side)))
+	params.Set("quantity", quantity.String())
+	params.Set("price", takeProfit.String())
+	params.Set("stopPrice", filters.RoundPrice(order.Side, order.StopPrice).String())
+
+	if order.StopLimitPrice.IsPositive() {
+		params.Set("stopLimitPrice", filters.RoundPrice(order.Side, order.StopLimitPrice).String())
+		params.Set("stopLimitTimeInForce", "GTC")
+	}
+
+	if order.ListClientOrderID != "" {
+		params.Set("listClientOrderId", order.ListClientOrderID)
+	}
+
+	resp, err := b.signedRequest(ctx, "POST", "/api/v3/order/oco", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place OCO order: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OCO order failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var list binanceOCOResponse
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	result, err := b.convertOCOResponse(&list)
+	if err != nil {
+		return nil, err
+	}
+
+	b.logger.Info("Placed OCO order",
+		zap.String("orderListId", result.OrderListID),
+		zap.String("takeProfitId", result.TakeProfit.ID),
+		zap.String("stopLossId", result.StopLoss.ID))
+
+	return result, nil
+}
+
+// CancelOCOOrder cancels both legs of an order list. The ID has the form
+// SYMBOL:ORDERLISTID as returned by PlaceOCOOrder.
+func (b *BinanceAdapter) CancelOCOOrder(ctx context.Context, orderListID string) error {
+	if err := b.rateLimiter.Acquire(ctx, weightOrder); err != nil {
+		return err
+	}
+
+	parts := strings.Split(orderListID, ":")
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid order list ID format: %s", orderListID)
+	}
+
+	params := url.Values{}
+	params.Set("symbol", parts[0])
+	params.Set("orderListId", parts[1])
+
+	resp, err := b.signedRequest(ctx, "DELETE", "/api/v3/orderList", params)
+	if err != nil {
+		return fmt.Errorf("failed to cancel OCO order: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("cancel OCO failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// validateOCOPrices checks the legs straddle each other the way Binance
+// requires: for a sell the target is above the stop, for a buy below it.
+func validateOCOPrices(order *OCOOrder) error {
+	if !order.TakeProfitPrice.IsPositive() || !order.StopPrice.IsPositive() {
+		return fmt.Errorf("OCO order needs both take-profit and stop prices")
+	}
+
+	switch order.Side {
+	case types.OrderSideSell:
+		if !order.TakeProfitPrice.GreaterThan(order.StopPrice) {
+			return fmt.Errorf("sell OCO take-profit %s must be above stop %s", order.TakeProfitPrice, order.StopPrice)
+		}
+	case types.OrderSideBuy:
+		if !order.TakeProfitPrice.LessThan(order.StopPrice) {
+			return fmt.Errorf("buy OCO take-profit %s must be below stop %s", order.TakeProfitPrice, order.StopPrice)
+		}
+	default:
+		return fmt.Errorf("invalid OCO side: %s", order.Side)
+	}
+
+	return nil
+}
+
+// convertOCOResponse maps the order reports to take-profit and stop legs.
+func (b *BinanceAdapter) convertOCOResponse(list *binanceOCOResponse) (*OCOResult, error) {
+	result := &OCOResult{
+		OrderListID: list.Symbol + ":" + strconv.FormatInt(list.OrderListID, 10),
+	}
+
+	for i := range list.OrderReports {
+		report := &list.OrderReports[i]
+		leg := b.convertBinanceOrder(&report.BinanceOrder)
+		leg.StopPrice = report.StopPrice
+		leg.OrderListID = result.OrderListID
+		leg.CreatedAt = time.UnixMilli(report.TransactTime)
+		leg.UpdatedAt = leg.CreatedAt
+
+		switch report.Type {
+		case "LIMIT_MAKER":
+			leg.Type = types.OrderTypeTakeProfit
+			result.TakeProfit = leg
+		case "STOP_LOSS", "STOP_LOSS_LIMIT":
+			leg.Type = types.OrderTypeStopLoss
+			result.StopLoss = leg
+		}
+	}
+
+	if result.TakeProfit == nil || result.StopLoss == nil {
+		return nil, fmt.Errorf("OCO response for %s missing a leg", result.OrderListID)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,226 @@
+package adapters_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+const ocoExchangeInfo = `{"symbols":[{"symbol":"BTCUSDT","filters":[` +
+	`{"filterType":"PRICE_FILTER","minPrice":"0.01","maxPrice":"1000000","tickSize":"0.01"},` +
+	`{"filterType":"LOT_SIZE","minQty":"0.00001","maxQty":"9000","stepSize":"0.00001"},` +
+	`{"filterType":"NOTIONAL","minNotional":"5"}]}]}`
+
+// ocoServer serves exchange info and answers OCO requests with reply,
+// recording each OCO request's method and query.
+type ocoServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []*http.Request
+	queries  []url.Values
+}
+
+func newOCOServer(t *testing.T, status int, reply string) *ocoServer {
+	s := &ocoServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v3/exchangeInfo" {
+			w.Write([]byte(ocoExchangeInfo))
+			return
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, r)
+		s.queries = append(s.queries, r.URL.Query())
+		s.mu.Unlock()
+		w.WriteHeader(status)
+		w.Write([]byte(reply))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *ocoServer) adapter() *adapters.BinanceAdapter {
+	return adapters.NewBinanceAdapter(zap.NewNop(), adapters.BinanceConfig{BaseURL: s.URL})
+}
+
+func (s *ocoServer) requestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+func sellOCO() *adapters.OCOOrder {
+	return &adapters.OCOOrder{
+		Symbol:            "BTC/USDT",
+		Side:              types.OrderSideSell,
+		Quantity:          decimal.RequireFromString("0.123456"),
+		TakeProfitPrice:   decimal.RequireFromString("70000.004"),
+		StopPrice:         decimal.RequireFromString("60000.006"),
+		StopLimitPrice:    decimal.RequireFromString("59900"),
+		ListClientOrderID: "bracket-1",
+	}
+}
+
+func TestBinancePlaceOCOOrderRequest(t *testing.T) {
+	server := newOCOServer(t, http.StatusOK, `{"orderListId":77,"contingencyType":"OCO","listStatusType":"EXEC_STARTED","listOrderStatus":"EXECUTING","symbol":"BTCUSDT","orderReports":[`+
+		`{"symbol":"BTCUSDT","orderId":11,"orderListId":77,"price":"59900","origQty":"0.12345","executedQty":"0","status":"NEW","type":"STOP_LOSS_LIMIT","side":"SELL","stopPrice":"60000.01","transactTime":1700000000000},`+
+		`{"symbol":"BTCUSDT","orderId":12,"orderListId":77,"price":"70000.01","origQty":"0.12345","executedQty":"0","status":"NEW","type":"LIMIT_MAKER","side":"SELL","transactTime":1700000000000}]}`)
+
+	result, err := server.adapter().PlaceOCOOrder(context.Background(), sellOCO())
+	if err != nil {
+		t.Fatalf("PlaceOCOOrder: %v", err)
+	}
+
+	if server.requestCount() != 1 {
+		t.Fatalf("Expected one OCO request, got %d", server.requestCount())
+	}
+	req, query := server.requests[0], server.queries[0]
+	if req.Method != http.MethodPost || req.URL.Path != "/api/v3/order/oco" {
+		t.Errorf("Expected POST /api/v3/order/oco, got %s %s", req.Method, req.URL.Path)
+	}
+	want := map[string]string{
+		"symbol":               "BTCUSDT",
+		"side":                 "SELL",
+		"quantity":             "0.12345",
+		"price":                "70000.01",
+		"stopPrice":            "60000.01",
+		"stopLimitPrice":       "59900",
+		"stopLimitTimeInForce": "GTC",
+		"listClientOrderId":    "bracket-1",
+	}
+	for key, value := range want {
+		if got := query.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+	if query.Get("signature") == "" {
+		t.Error("Expected a signed request")
+	}
+
+	if result.OrderListID != "BTCUSDT:77" {
+		t.Errorf("OrderListID = %s, want BTCUSDT:77", result.OrderListID)
+	}
+	if result.TakeProfit.ID != "BTCUSDT:12" || result.TakeProfit.Type != types.OrderTypeTakeProfit {
+		t.Errorf("Unexpected take-profit leg %+v", result.TakeProfit)
+	}
+	if result.StopLoss.ID != "BTCUSDT:11" || result.StopLoss.Type != types.OrderTypeStopLoss {
+		t.Errorf("Unexpected stop leg %+v", result.StopLoss)
+	}
+	if !result.StopLoss.StopPrice.Equal(decimal.RequireFromString("60000.01")) {
+		t.Errorf("Stop leg StopPrice = %s, want 60000.01", result.StopLoss.StopPrice)
+	}
+	for _, leg := range []*types.Order{result.TakeProfit, result.StopLoss} {
+		if leg.OrderListID != result.OrderListID || leg.Status != types.OrderStatusOpen {
+			t.Errorf("Expected an open leg of %s, got %+v", result.OrderListID, leg)
+		}
+	}
+}
+
+func TestBinancePlaceOCOOrderPartialLegFill(t *testing.T) {
+	// The take-profit leg filled in part on placement, which expired the stop
+	server := newOCOServer(t, http.StatusOK, `{"orderListId":78,"listOrderStatus":"EXECUTING","symbol":"BTCUSDT","orderReports":[`+
+		`{"symbol":"BTCUSDT","orderId":21,"orderListId":78,"price":"59900","origQty":"0.12345","executedQty":"0","status":"EXPIRED","type":"STOP_LOSS_LIMIT","side":"SELL","stopPrice":"60000.01"},`+
+		`{"symbol":"BTCUSDT","orderId":22,"orderListId":78,"price":"70000.01","origQty":"0.12345","executedQty":"0.04","status":"PARTIALLY_FILLED","type":"LIMIT_MAKER","side":"SELL"}]}`)
+
+	result, err := server.adapter().PlaceOCOOrder(context.Background(), sellOCO())
+	if err != nil {
+		t.Fatalf("PlaceOCOOrder: %v", err)
+	}
+
+	if result.TakeProfit.Status != types.OrderStatusPartiallyFilled {
+		t.Errorf("Take-profit status = %s, want partially filled", result.TakeProfit.Status)
+	}
+	if !result.TakeProfit.FilledQty.Equal(decimal.RequireFromString("0.04")) {
+		t.Errorf("Take-profit FilledQty = %s, want 0.04", result.TakeProfit.FilledQty)
+	}
+	if result.StopLoss.Status != types.OrderStatusExpired || !result.StopLoss.FilledQty.IsZero() {
+		t.Errorf("Expected the unfilled stop leg expired, got %s with %s filled", result.StopLoss.Status, result.StopLoss.FilledQty)
+	}
+}
+
+func TestBinancePlaceOCOOrderErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		reply    string
+		order    func(*adapters.OCOOrder)
+		requests int
+	}{
+		{
+			name:   "exchange rejection",
+			status: http.StatusBadRequest,
+			reply:  `{"code":-1013,"msg":"Filter failure: PERCENT_PRICE"}`,
+			// The rejected request still reached the exchange
+			requests: 1,
+		},
+		{
+			name:     "missing leg",
+			status:   http.StatusOK,
+			reply:    `{"orderListId":79,"symbol":"BTCUSDT","orderReports":[{"symbol":"BTCUSDT","orderId":31,"price":"70000.01","origQty":"0.12345","status":"NEW","type":"LIMIT_MAKER","side":"SELL"}]}`,
+			requests: 1,
+		},
+		{
+			name:   "inverted prices",
+			status: http.StatusOK,
+			order: func(o *adapters.OCOOrder) {
+				o.TakeProfitPrice, o.StopPrice = o.StopPrice, o.TakeProfitPrice
+			},
+		},
+		{
+			name:   "below min notional",
+			status: http.StatusOK,
+			order: func(o *adapters.OCOOrder) {
+				o.Quantity = decimal.RequireFromString("0.00005")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newOCOServer(t, tt.status, tt.reply)
+			order := sellOCO()
+			if tt.order != nil {
+				tt.order(order)
+			}
+
+			if _, err := server.adapter().PlaceOCOOrder(context.Background(), order); err == nil {
+				t.Error("Expected an error")
+			}
+			if got := server.requestCount(); got != tt.requests {
+				t.Errorf("Expected %d OCO requests, got %d", tt.requests, got)
+			}
+		})
+	}
+}
+
+func TestBinanceCancelOCOOrder(t *testing.T) {
+	server := newOCOServer(t, http.StatusOK, `{"orderListId":77,"listOrderStatus":"ALL_DONE","symbol":"BTCUSDT"}`)
+	b := server.adapter()
+
+	if err := b.CancelOCOOrder(context.Background(), "BTCUSDT:77"); err != nil {
+		t.Fatalf("CancelOCOOrder: %v", err)
+	}
+	req, query := server.requests[0], server.queries[0]
+	if req.Method != http.MethodDelete || req.URL.Path != "/api/v3/orderList" {
+		t.Errorf("Expected DELETE /api/v3/orderList, got %s %s", req.Method, req.URL.Path)
+	}
+	if query.Get("symbol") != "BTCUSDT" || query.Get("orderListId") != "77" {
+		t.Errorf("Unexpected cancel query %v", query)
+	}
+
+	if err := b.CancelOCOOrder(context.Background(), "77"); err == nil {
+		t.Error("Expected an error for an ID without a symbol")
+	}
+
+	rejected := newOCOServer(t, http.StatusBadRequest, `{"code":-2011,"msg":"Unknown order list sent."}`)
+	if err := rejected.adapter().CancelOCOOrder(context.Background(), "BTCUSDT:77"); err == nil {
+		t.Error("Expected an error when the exchange rejects the cancel")
+	}
+}
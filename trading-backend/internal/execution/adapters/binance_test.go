@@ -0,0 +1,110 @@
+package adapters_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+)
+
+func approxEqual(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func TestRateLimiterWeightedAcquire(t *testing.T) {
+	rl := adapters.NewRateLimiter(100, time.Minute)
+
+	rl.Acquire(context.Background(), 10)
+	rl.Acquire(context.Background(), 50)
+
+	if got := rl.AvailableAt(time.Now()); !approxEqual(got, 40, 0.1) {
+		t.Errorf("available = %.2f, want 40", got)
+	}
+}
+
+func TestRateLimiterRefillMath(t *testing.T) {
+	rl := adapters.NewRateLimiter(1200, time.Minute)
+	rl.Sync(1200)
+
+	now := time.Now()
+	tests := []struct {
+		elapsed time.Duration
+		want    float64
+	}{
+		{0, 0},
+		{15 * time.Second, 300},
+		{30 * time.Second, 600},
+		{time.Minute, 1200},
+		{5 * time.Minute, 1200}, // capped at the bucket size
+	}
+
+	for _, tt := range tests {
+		if got := rl.AvailableAt(now.Add(tt.elapsed)); !approxEqual(got, tt.want, 1) {
+			t.Errorf("after %s: available = %.2f, want %.0f", tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimiterSyncToExchangeWeight(t *testing.T) {
+	rl := adapters.NewRateLimiter(1200, time.Minute)
+	rl.Acquire(context.Background(), 5)
+
+	// The exchange has counted more weight than we have, e.g. from
+	// another process sharing the API key.
+	rl.Sync(1000)
+	if got := rl.AvailableAt(time.Now()); !approxEqual(got, 200, 1) {
+		t.Errorf("available after sync = %.2f, want 200", got)
+	}
+
+	rl.Sync(5000)
+	if got := rl.AvailableAt(time.Now()); got > 1 {
+		t.Errorf("available after over-limit sync = %.2f, want 0", got)
+	}
+}
+
+func TestRateLimiterBlocksUntilRefilled(t *testing.T) {
+	rl := adapters.NewRateLimiter(10, 100*time.Millisecond)
+	rl.Sync(10)
+
+	start := time.Now()
+	rl.Acquire(context.Background(), 5) // needs half a window to refill
+	elapsed := time.Since(start)
+
+	if elapsed < 40*time.Millisecond {
+		t.Errorf("Acquire returned after %s, want ~50ms wait", elapsed)
+	}
+}
+
+func TestRateLimiterBackoff(t *testing.T) {
+	rl := adapters.NewRateLimiter(100, time.Minute)
+	rl.Backoff(50 * time.Millisecond)
+
+	start := time.Now()
+	rl.Acquire(context.Background(), 1)
+	if elapsed := time.Since(start); elapsed < 45*time.Millisecond {
+		t.Errorf("Acquire returned after %s, want to wait out Retry-After", elapsed)
+	}
+}
+
+func TestRateLimiterAcquireHonorsContext(t *testing.T) {
+	rl := adapters.NewRateLimiter(100, time.Minute)
+	rl.Backoff(time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := rl.Acquire(ctx, 1); err == nil {
+		t.Fatal("Acquire succeeded during backoff, want context error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Acquire returned after %s, want it to stop at the deadline", elapsed)
+	}
+
+	// The cancelled wait took no tokens
+	if got := rl.AvailableAt(time.Now()); got < 99 {
+		t.Errorf("available after cancelled Acquire = %.2f, want 100", got)
+	}
+}
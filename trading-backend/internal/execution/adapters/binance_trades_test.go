@@ -0,0 +1,76 @@
+package adapters_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+func TestBinanceTradeCallbackReplacedWhileStreaming(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		trade := []byte(`{"e":"trade","E":1700000000001,"s":"BTCUSDT","t":1,"p":"100.5","q":"0.2","T":1700000000000,"m":true,"M":true}`)
+		for {
+			if err := conn.WriteMessage(websocket.TextMessage, trade); err != nil {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	b := adapters.NewBinanceAdapter(zap.NewNop(), adapters.BinanceConfig{
+		BaseURL: server.URL,
+		WSURL:   "ws" + strings.TrimPrefix(server.URL, "http") + "/ws",
+	})
+	defer b.Disconnect()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first := make(chan *adapters.BinanceTrade, 100)
+	if err := b.SubscribeToTrades(ctx, []string{"BTC/USDT"}, func(trade *adapters.BinanceTrade) {
+		select {
+		case first <- trade:
+		default:
+		}
+	}); err != nil {
+		t.Fatalf("SubscribeToTrades: %v", err)
+	}
+	select {
+	case <-first:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no trade delivered")
+	}
+
+	// Replacing the callback while trades stream in must not race the reader
+	second := make(chan *adapters.BinanceTrade, 100)
+	if err := b.SubscribeToTrades(ctx, []string{"BTC/USDT"}, func(trade *adapters.BinanceTrade) {
+		select {
+		case second <- trade:
+		default:
+		}
+	}); err != nil {
+		t.Fatalf("SubscribeToTrades: %v", err)
+	}
+	select {
+	case trade := <-second:
+		if trade.Symbol != "BTCUSDT" || !trade.IsBuyer || trade.Price.String() != "100.5" {
+			t.Errorf("Unexpected trade %+v", trade)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no trade delivered to the replacement callback")
+	}
+}
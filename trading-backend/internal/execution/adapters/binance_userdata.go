@@ -0,0 +1,372 @@
+// Package adapters provides the Binance user data stream.
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// listenKeyKeepalive is how often the listen key is extended. Binance
+// expires keys after 60 minutes without a keepalive.
+const listenKeyKeepalive = 30 * time.Minute
+
+// BinanceFill is a single execution reported by the user data stream.
+type BinanceFill struct {
+	TradeID         int64           `json:"tradeId"`
+	Price           decimal.Decimal `json:"price"`
+	Quantity        decimal.Decimal `json:"quantity"`
+	Commission      decimal.Decimal `json:"commission"`
+	CommissionAsset string          `json:"commissionAsset"`
+	Time            time.Time       `json:"time"`
+}
+
+// BinanceOrderUpdate is an order state change from an executionReport event.
+// Fill is nil unless the report is a trade.
+type BinanceOrderUpdate struct {
+	Order         *types.Order `json:"order"`
+	ExecutionType string       `json:"executionType"`
+	RejectReason  string       `json:"rejectReason,omitempty"`
+	Fill          *BinanceFill `json:"fill,omitempty"`
+}
+
+// binanceExecutionReport is the raw executionReport payload. Keys that
+// differ only in case are all declared, since encoding/json would otherwise
+// match e.g. "Q" into the "q" field.
+type binanceExecutionReport struct {
+	EventType       string          `json:"e"`
+	EventTime       int64           `json:"E"`
+	Symbol          string          `json:"s"`
+	ClientOrderID   string          `json:"c"`
+	Side            string          `json:"S"`
+	Type            string          `json:"o"`
+	Quantity        decimal.Decimal `json:"q"`
+	Price           decimal.Decimal `json:"p"`
+	StopPrice       decimal.Decimal `json:"P"`
+	ExecutionType   string          `json:"x"`
+	Status          string          `json:"X"`
+	RejectReason    string          `json:"r"`
+	OrderID         int64           `json:"i"`
+	LastQty         decimal.Decimal `json:"l"`
+	CumulativeQty   decimal.Decimal `json:"z"`
+	LastPrice       decimal.Decimal `json:"L"`
+	Commission      decimal.Decimal `json:"n"`
+	CommissionAsset string          `json:"N"`
+	TransactionTime int64           `json:"T"`
+	TradeID         int64           `json:"t"`
+	CreationTime    int64           `json:"O"`
+	OrigClientID    string          `json:"C"`
+	IgnoreI         int64           `json:"I"`
+	QuoteQty        decimal.Decimal `json:"Q"`
+	CumulativeQuote decimal.Decimal `json:"Z"`
+	LastQuoteQty    decimal.Decimal `json:"Y"`
+	TimeInForce     string          `json:"f"`
+	IcebergQty      decimal.Decimal `json:"F"`
+	IsMaker         bool            `json:"m"`
+	IgnoreM         bool            `json:"M"`
+	IsWorking       bool            `json:"w"`
+	WorkingTime     int64           `json:"W"`
+}
+
+// binanceAccountPosition is the raw outboundAccountPosition payload.
+type binanceAccountPosition struct {
+	EventType  string `json:"e"`
+	EventTime  int64  `json:"E"`
+	UpdateTime int64  `json:"u"`
+	Balances   []struct {
+		Asset  string          `json:"a"`
+		Free   decimal.Decimal `json:"f"`
+		Locked decimal.Decimal `json:"l"`
+	} `json:"B"`
+}
+
+// SetOnBalanceUpdate sets the callback invoked when the user data stream
+// reports changed balances.
+func (b *BinanceAdapter) SetOnBalanceUpdate(callback func(balances []BinanceBalance)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.onBalanceUpdate = callback
+}
+
+// CachedBalance returns the last balance pushed by the user data stream.
+func (b *BinanceAdapter) CachedBalance(asset string) (BinanceBalance, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	balance, ok := b.balances[asset]
+	return balance, ok
+}
+
+// StartUserDataStream subscribes to order and balance updates. The listen
+// key is kept alive and renewed on expiry or disconnect until ctx is
+// cancelled.
+func (b *BinanceAdapter) StartUserDataStream(ctx context.Context, onOrderUpdate func(update *BinanceOrderUpdate)) error {
+	b.mu.Lock()
+	b.onOrderUpdate = onOrderUpdate
+	b.mu.Unlock()
+
+	listenKey, err := b.createListenKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	conn, err := b.dialUserDataStream(ctx, listenKey)
+	if err != nil {
+		b.closeListenKey(listenKey)
+		return err
+	}
+
+	go b.runUserDataStream(ctx, listenKey, conn)
+	return nil
+}
+
+// runUserDataStream reads the stream, keeps the listen key alive, and
+// reconnects with a fresh key when the connection drops or the key expires.
+func (b *BinanceAdapter) runUserDataStream(ctx context.Context, listenKey string, conn *websocket.Conn) {
+	for {
+		streamCtx, cancel := context.WithCancel(ctx)
+		go b.keepAliveListenKey(streamCtx, listenKey)
+
+		err := b.readUserDataStream(conn)
+		cancel()
+		conn.Close()
+
+		if ctx.Err() != nil {
+			b.closeListenKey(listenKey)
+			return
+		}
+		b.logger.Warn("User data stream interrupted, reconnecting", zap.Error(err))
+
+		listenKey, conn = b.reconnectUserDataStream(ctx)
+		if conn == nil {
+			return
+		}
+	}
+}
+
+// reconnectUserDataStream obtains a new listen key and dials it with capped
+// exponential backoff. It returns a nil conn if ctx is cancelled.
+func (b *BinanceAdapter) reconnectUserDataStream(ctx context.Context) (string, *websocket.Conn) {
+	for attempt := 0; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			return "", nil
+		case <-time.After(wsBackoff(attempt)):
+		}
+
+		listenKey, err := b.createListenKey(ctx)
+		if err != nil {
+			b.logger.Warn("Failed to create listen key", zap.Int("attempt", attempt+1), zap.Error(err))
+			continue
+		}
+
+		conn, err := b.dialUserDataStream(ctx, listenKey)
+		if err != nil {
+			b.closeListenKey(listenKey)
+			b.logger.Warn("Failed to dial user data stream", zap.Int("attempt", attempt+1), zap.Error(err))
+			continue
+		}
+
+		b.logger.Info("User data stream reconnected", zap.Int("attempts", attempt+1))
+		return listenKey, conn
+	}
+}
+
+// dialUserDataStream connects to the user data stream for a listen key.
+func (b *BinanceAdapter) dialUserDataStream(ctx context.Context, listenKey string) (*websocket.Conn, error) {
+	return b.dialStreams(ctx, []string{listenKey})
+}
+
+// readUserDataStream dispatches events until the connection fails or the
+// listen key expires.
+func (b *BinanceAdapter) readUserDataStream(conn *websocket.Conn) error {
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+
+		var event struct {
+			EventType string `json:"e"`
+			EventTime int64  `json:"E"`
+		}
+		if err := json.Unmarshal(message, &event); err != nil {
+			continue
+		}
+
+		switch event.EventType {
+		case "executionReport":
+			var report binanceExecutionReport
+			if err := json.Unmarshal(message, &report); err != nil {
+				b.logger.Warn("Failed to parse execution report", zap.Error(err))
+				continue
+			}
+			b.handleExecutionReport(&report)
+		case "outboundAccountPosition":
+			var position binanceAccountPosition
+			if err := json.Unmarshal(message, &position); err != nil {
+				b.logger.Warn("Failed to parse account position", zap.Error(err))
+				continue
+			}
+			b.handleAccountPosition(&position)
+		case "listenKeyExpired":
+			return fmt.Errorf("listen key expired")
+		}
+	}
+}
+
+// handleExecutionReport converts an execution report and notifies the
+// order update callback.
+func (b *BinanceAdapter) handleExecutionReport(report *binanceExecutionReport) {
+	order := b.convertBinanceOrder(&BinanceOrder{
+		Symbol:        report.Symbol,
+		OrderID:       report.OrderID,
+		ClientOrderID: report.ClientOrderID,
+		Price:         report.Price,
+		OrigQty:       report.Quantity,
+		ExecutedQty:   report.CumulativeQty,
+		Status:        report.Status,
+		Type:          report.Type,
+		Side:          report.Side,
+		Time:          report.CreationTime,
+		UpdateTime:    report.TransactionTime,
+	})
+	order.StopPrice = report.StopPrice
+
+	update := &BinanceOrderUpdate{
+		Order:         order,
+		ExecutionType: report.ExecutionType,
+	}
+	if report.RejectReason != "NONE" {
+		update.RejectReason = report.RejectReason
+	}
+	if report.ExecutionType == "TRADE" {
+		update.Fill = &BinanceFill{
+			TradeID:         report.TradeID,
+			Price:           report.LastPrice,
+			Quantity:        report.LastQty,
+			Commission:      report.Commission,
+			CommissionAsset: report.CommissionAsset,
+			Time:            time.UnixMilli(report.TransactionTime),
+		}
+	}
+
+	b.mu.RLock()
+	onOrderUpdate := b.onOrderUpdate
+	b.mu.RUnlock()
+
+	if onOrderUpdate != nil {
+		onOrderUpdate(update)
+	}
+}
+
+// handleAccountPosition updates cached balances.
+func (b *BinanceAdapter) handleAccountPosition(position *binanceAccountPosition) {
+	balances := make([]BinanceBalance, 0, len(position.Balances))
+
+	b.mu.Lock()
+	for _, raw := range position.Balances {
+		balance := BinanceBalance{Asset: raw.Asset, Free: raw.Free, Locked: raw.Locked}
+		b.balances[raw.Asset] = balance
+		balances = append(balances, balance)
+	}
+	onBalanceUpdate := b.onBalanceUpdate
+	b.mu.Unlock()
+
+	if onBalanceUpdate != nil {
+		onBalanceUpdate(balances)
+	}
+}
+
+// keepAliveListenKey extends the listen key until ctx is cancelled.
+func (b *BinanceAdapter) keepAliveListenKey(ctx context.Context, listenKey string) {
+	ticker := time.NewTicker(listenKeyKeepalive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := b.listenKeyRequest(ctx, http.MethodPut, listenKey, nil); err != nil {
+				b.logger.Warn("Failed to keep listen key alive", zap.Error(err))
+			}
+		}
+	}
+}
+
+// createListenKey starts a new user data stream session.
+func (b *BinanceAdapter) createListenKey(ctx context.Context) (string, error) {
+	var result struct {
+		ListenKey string `json:"listenKey"`
+	}
+	if err := b.listenKeyRequest(ctx, http.MethodPost, "", &result); err != nil {
+		return "", err
+	}
+	if result.ListenKey == "" {
+		return "", fmt.Errorf("empty listen key in response")
+	}
+	return result.ListenKey, nil
+}
+
+// closeListenKey ends a user data stream session.
+func (b *BinanceAdapter) closeListenKey(listenKey string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := b.listenKeyRequest(ctx, http.MethodDelete, listenKey, nil); err != nil {
+		b.logger.Debug("Failed to close listen key", zap.Error(err))
+	}
+}
+
+// listenKeyRequest calls the userDataStream endpoint, which needs the API
+// key header but no signature.
+func (b *BinanceAdapter) listenKeyRequest(ctx context.Context, method, listenKey string, result interface{}) error {
+	if err := b.rateLimiter.Acquire(ctx, weightListenKey); err != nil {
+		return err
+	}
+
+	reqURL := b.baseURL + "/api/v3/userDataStream"
+	if listenKey != "" {
+		reqURL += "?" + url.Values{"listenKey": {listenKey}}.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-MBX-APIKEY", b.apiKey)
+
+	resp, err := b.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("userDataStream %s failed: %s", method, string(body))
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(body, result); err != nil {
+			return fmt.Errorf("failed to decode listen key response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package adapters_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"go.uber.org/zap"
+)
+
+func TestBinanceUserDataStreamClosesListenKeyOnFailedDial(t *testing.T) {
+	var mu sync.Mutex
+	var closed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/userDataStream" {
+			// The stream endpoint refuses the websocket upgrade
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodPost:
+			w.Write([]byte(`{"listenKey":"key-1"}`))
+		case http.MethodDelete:
+			mu.Lock()
+			closed = append(closed, r.URL.Query().Get("listenKey"))
+			mu.Unlock()
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	b := adapters.NewBinanceAdapter(zap.NewNop(), adapters.BinanceConfig{
+		BaseURL: server.URL,
+		WSURL:   "ws" + strings.TrimPrefix(server.URL, "http") + "/ws",
+	})
+
+	if err := b.StartUserDataStream(context.Background(), nil); err == nil {
+		t.Fatal("Expected the failed dial to be reported")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(closed) != 1 || closed[0] != "key-1" {
+		t.Errorf("Expected listen key key-1 closed, got %v", closed)
+	}
+}
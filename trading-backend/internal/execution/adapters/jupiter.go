@@ -0,0 +1,601 @@
+// Package adapters provides an on-chain spot adapter that swaps through
+// Jupiter on Solana.
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// SolanaRPC is the part of the Solana client the Jupiter adapter uses.
+type SolanaRPC interface {
+	// SendTransaction submits a signed transaction and returns its signature.
+	SendTransaction(ctx context.Context, tx []byte) (string, error)
+	// GetSignatureStatus returns the slot a transaction landed in, zero
+	// while it is unknown, and its on-chain error, empty when it succeeded.
+	GetSignatureStatus(ctx context.Context, signature string) (uint64, string, error)
+	GetBlockHeight(ctx context.Context) (uint64, error)
+	// GetBalance returns an address's SOL balance.
+	GetBalance(ctx context.Context, address string) (decimal.Decimal, error)
+	GetTokenBalanceByOwner(ctx context.Context, owner, mint string) (decimal.Decimal, error)
+}
+
+// ConfirmationWaiter waits for a block to gain the chain's required
+// confirmations, as BlockTracker.WaitForConfirmation does.
+type ConfirmationWaiter interface {
+	WaitForConfirmation(ctx context.Context, chain string, blockNumber uint64) error
+}
+
+// JupiterConfig configures the Jupiter adapter.
+type JupiterConfig struct {
+	PrivateKey          string          `json:"privateKey"`          // Wallet keypair, base58 or a Solana CLI JSON byte array
+	MaxSlippage         decimal.Decimal `json:"maxSlippage"`         // Fraction; sent to Jupiter as slippage bps
+	QuoteAsset          string          `json:"quoteAsset"`          // Asset positions are quoted in
+	Assets              []string        `json:"assets"`              // Tokens reported by GetPositions
+	PriorityFeeLamports int64           `json:"priorityFeeLamports"` // Optional prioritization fee per swap
+	ConfirmTimeout      time.Duration   `json:"confirmTimeout"`      // How long PlaceOrder waits before returning the swap open
+	PollInterval        time.Duration   `json:"pollInterval"`        // Signature status polling interval
+	BaseURL             string          `json:"baseUrl"`             // Swap API, defaults to Jupiter's v6 API
+	TokenURL            string          `json:"tokenUrl"`            // Token metadata API for mints outside jupiterTokens
+}
+
+// jupiterToken is a well-known mint and its decimals.
+type jupiterToken struct {
+	mint     string
+	decimals int32
+}
+
+// jupiterTokens maps common asset codes to their mints.
+var jupiterTokens = map[string]jupiterToken{
+	"SOL":  {SOLMint, 9},
+	"WSOL": {WSOLMint, 9},
+	"USDC": {USDCMint, 6},
+	"USDT": {USDTMint, 6},
+	"RAY":  {RAYMint, 6},
+	"SRM":  {SRMMint, 6},
+	"BONK": {BONKMint, 5},
+}
+
+// jupiterQuotes are the quote assets recognized when splitting an
+// unseparated symbol.
+var jupiterQuotes = []string{"USDC", "USDT", "SOL"}
+
+// swapTrackTimeout bounds how long a submitted swap is tracked. Its
+// blockhash expires well before this.
+const swapTrackTimeout = 3 * time.Minute
+
+// JupiterAdapter trades spot pairs on Solana by swapping through the Jupiter
+// aggregator. Swaps are signed locally, submitted through the Solana client
+// and confirmed through the block tracker. The order ID is the transaction
+// signature. Swaps fill at once or not at all, so there is no order book and
+// nothing to cancel.
+type JupiterAdapter struct {
+	logger        *zap.Logger
+	config        JupiterConfig
+	baseURL       string
+	tokenURL      string
+	httpClient    *http.Client
+	rpc           SolanaRPC
+	confirmations ConfirmationWaiter
+	key           ed25519.PrivateKey
+	wallet        string
+	slippageBPS   int
+
+	mu       sync.RWMutex
+	decimals map[string]int32        // By mint, for tokens outside jupiterTokens
+	orders   map[string]*types.Order // By signature
+}
+
+// NewJupiterAdapter creates a Jupiter adapter trading from the configured
+// wallet. Without a confirmation waiter a swap counts as filled once it
+// lands.
+func NewJupiterAdapter(logger *zap.Logger, config JupiterConfig, rpc SolanaRPC, confirmations ConfirmationWaiter) (*JupiterAdapter, error) {
+	key, err := ParseSolanaKey(config.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Solana wallet key: %w", err)
+	}
+	if rpc == nil {
+		return nil, fmt.Errorf("jupiter requires a Solana RPC client")
+	}
+
+	baseURL := "https://quote-api.jup.ag/v6"
+	if config.BaseURL != "" {
+		baseURL = strings.TrimSuffix(config.BaseURL, "/")
+	}
+	tokenURL := "https://tokens.jup.ag/token"
+	if config.TokenURL != "" {
+		tokenURL = strings.TrimSuffix(config.TokenURL, "/")
+	}
+	if config.QuoteAsset == "" {
+		config.QuoteAsset = "USDC"
+	}
+	if config.Assets == nil {
+		config.Assets = []string{"SOL", "RAY", "BONK"}
+	}
+	if config.ConfirmTimeout <= 0 {
+		config.ConfirmTimeout = 60 * time.Second
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+
+	slippageBPS := int(config.MaxSlippage.Mul(decimal.NewFromInt(10000)).Round(0).IntPart())
+	if slippageBPS <= 0 {
+		slippageBPS = 50 // 0.5%
+	}
+
+	return &JupiterAdapter{
+		logger:        logger.Named("jupiter"),
+		config:        config,
+		baseURL:       baseURL,
+		tokenURL:      tokenURL,
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		rpc:           rpc,
+		confirmations: confirmations,
+		key:           key,
+		wallet:        Base58Encode(key.Public().(ed25519.PublicKey)),
+		slippageBPS:   slippageBPS,
+		decimals:      make(map[string]int32),
+		orders:        make(map[string]*types.Order),
+	}, nil
+}
+
+// Name returns the exchange name used for routing.
+func (j *JupiterAdapter) Name() string {
+	return "jupiter"
+}
+
+// Wallet returns the base58 address swaps are made from.
+func (j *JupiterAdapter) Wallet() string {
+	return j.wallet
+}
+
+// Connect checks that Jupiter can quote SOL/USDC.
+func (j *JupiterAdapter) Connect(ctx context.Context) error {
+	if _, err := j.GetTicker(ctx, "SOL/USDC"); err != nil {
+		return fmt.Errorf("failed to connect to Jupiter: %w", err)
+	}
+
+	j.logger.Info("Connected to Jupiter",
+		zap.String("wallet", j.wallet),
+		zap.Int("slippageBps", j.slippageBPS))
+	return nil
+}
+
+// Disconnect is a no-op; the adapter holds no connections.
+func (j *JupiterAdapter) Disconnect() error {
+	return nil
+}
+
+// PlaceOrder swaps order.Quantity of the base asset: a buy swaps the quote
+// asset for exactly that amount, a sell swaps exactly that amount for the
+// quote asset. A limit order swaps only if the quote is within its price; it
+// never rests. It waits up to ConfirmTimeout for confirmation and otherwise
+// returns the swap open, tracking it for GetOrder. Fills are reported at the
+// quoted price.
+func (j *JupiterAdapter) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	if order.Type != types.OrderTypeMarket && order.Type != types.OrderTypeLimit && order.Type != "" {
+		return nil, fmt.Errorf("jupiter does not support %s orders", order.Type)
+	}
+
+	base, quote, err := j.tokens(ctx, order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := order.Quantity.Shift(base.decimals).Truncate(0)
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("quantity %s rounds to zero at %d decimals", order.Quantity, base.decimals)
+	}
+
+	inputMint, outputMint, swapMode := quote.mint, base.mint, "ExactOut"
+	if order.Side == types.OrderSideSell {
+		inputMint, outputMint, swapMode = base.mint, quote.mint, "ExactIn"
+	}
+
+	jupQuote, err := j.getQuote(ctx, inputMint, outputMint, amount.String(), swapMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+
+	price, err := quotePrice(jupQuote, order.Side, base.decimals, quote.decimals)
+	if err != nil {
+		return nil, err
+	}
+	if order.Type == types.OrderTypeLimit && order.Price.IsPositive() {
+		if (order.Side == types.OrderSideBuy && price.GreaterThan(order.Price)) ||
+			(order.Side == types.OrderSideSell && price.LessThan(order.Price)) {
+			return nil, fmt.Errorf("quoted price %s is outside limit %s", price, order.Price)
+		}
+	}
+
+	swap, err := j.buildSwap(ctx, jupQuote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build swap: %w", err)
+	}
+
+	unsigned, err := base64.StdEncoding.DecodeString(swap.SwapTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode swap transaction: %w", err)
+	}
+	signed, signature, err := SignSolanaTransaction(unsigned, j.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign swap transaction: %w", err)
+	}
+
+	sent, err := j.rpc.SendTransaction(ctx, signed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit swap: %w", err)
+	}
+	if sent != "" && sent != signature {
+		j.logger.Warn("RPC returned an unexpected signature",
+			zap.String("expected", signature),
+			zap.String("returned", sent))
+	}
+
+	now := time.Now()
+	placed := *order
+	placed.ID = signature
+	placed.Status = types.OrderStatusOpen
+	placed.Price = price
+	placed.CreatedAt = now
+	placed.UpdatedAt = now
+
+	done := make(chan struct{})
+	j.mu.Lock()
+	j.orders[signature] = &placed
+	j.mu.Unlock()
+
+	j.logger.Info("Submitted Jupiter swap",
+		zap.String("signature", signature),
+		zap.String("symbol", order.Symbol),
+		zap.String("side", string(order.Side)),
+		zap.String("quantity", order.Quantity.String()),
+		zap.String("price", price.String()),
+		zap.String("priceImpact", jupQuote.PriceImpactPct))
+
+	go j.track(signature, uint64(swap.LastValidBlockHeight), done)
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	case <-time.After(j.config.ConfirmTimeout):
+		j.logger.Warn("Swap not confirmed yet, returning it open", zap.String("signature", signature))
+	}
+
+	return j.GetOrder(ctx, signature)
+}
+
+// track polls a submitted swap until it lands, fails or its blockhash
+// expires, then waits for confirmation and records the outcome.
+func (j *JupiterAdapter) track(signature string, lastValidBlockHeight uint64, done chan struct{}) {
+	defer close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), swapTrackTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(j.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		slot, txErr, err := j.rpc.GetSignatureStatus(ctx, signature)
+		switch {
+		case err != nil:
+			j.logger.Debug("Failed to get swap status", zap.String("signature", signature), zap.Error(err))
+		case txErr != "":
+			j.logger.Error("Swap failed on chain", zap.String("signature", signature), zap.String("error", txErr))
+			j.finish(signature, types.OrderStatusRejected)
+			return
+		case slot > 0:
+			if j.confirmations != nil {
+				if err := j.confirmations.WaitForConfirmation(ctx, "solana", slot); err != nil {
+					j.logger.Error("Swap confirmation failed", zap.String("signature", signature), zap.Error(err))
+					return
+				}
+			}
+			j.finish(signature, types.OrderStatusFilled)
+			return
+		default:
+			if height, err := j.rpc.GetBlockHeight(ctx); err == nil && lastValidBlockHeight > 0 && height > lastValidBlockHeight {
+				j.logger.Warn("Swap expired before landing", zap.String("signature", signature))
+				j.finish(signature, types.OrderStatusExpired)
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			j.logger.Error("Gave up tracking swap", zap.String("signature", signature))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// finish records a swap's final status.
+func (j *JupiterAdapter) finish(signature string, status types.OrderStatus) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	order, ok := j.orders[signature]
+	if !ok {
+		return
+	}
+	now := time.Now()
+	order.Status = status
+	order.UpdatedAt = now
+	if status == types.OrderStatusFilled {
+		order.FilledQty = order.Quantity
+		order.AvgFillPrice = order.Price
+		order.FilledAt = &now
+	}
+}
+
+// CancelOrder always fails: swaps execute atomically.
+func (j *JupiterAdapter) CancelOrder(ctx context.Context, orderID string) error {
+	return fmt.Errorf("jupiter swaps cannot be cancelled")
+}
+
+// GetOrder returns a swap placed by this adapter, by signature.
+func (j *JupiterAdapter) GetOrder(ctx context.Context, orderID string) (*types.Order, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	order, ok := j.orders[orderID]
+	if !ok {
+		return nil, fmt.Errorf("unknown swap: %s", orderID)
+	}
+	copied := *order
+	return &copied, nil
+}
+
+// GetBalance returns the wallet's balance of an asset.
+func (j *JupiterAdapter) GetBalance(ctx context.Context, asset string) (decimal.Decimal, error) {
+	asset = strings.ToUpper(asset)
+	if asset == "SOL" {
+		return j.rpc.GetBalance(ctx, j.wallet)
+	}
+
+	token, err := j.token(ctx, asset)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return j.rpc.GetTokenBalanceByOwner(ctx, j.wallet, token.mint)
+}
+
+// GetPositions returns the wallet's holdings of the configured assets as
+// long positions against the quote asset.
+func (j *JupiterAdapter) GetPositions(ctx context.Context) ([]*types.Position, error) {
+	var positions []*types.Position
+	for _, asset := range j.config.Assets {
+		balance, err := j.GetBalance(ctx, asset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s balance: %w", asset, err)
+		}
+		if !balance.IsPositive() {
+			continue
+		}
+		positions = append(positions, &types.Position{
+			Symbol:   strings.ToUpper(asset) + "/" + j.config.QuoteAsset,
+			Side:     types.PositionSideLong,
+			Quantity: balance,
+		})
+	}
+	return positions, nil
+}
+
+// GetOrderBook always fails: an aggregator has no order book. Use GetTicker.
+func (j *JupiterAdapter) GetOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error) {
+	return nil, fmt.Errorf("jupiter has no order book")
+}
+
+// GetTicker prices one unit of the base asset both ways: the bid from
+// selling it and the ask from buying it. The last price is their midpoint.
+func (j *JupiterAdapter) GetTicker(ctx context.Context, symbol string) (*Ticker, error) {
+	base, quote, err := j.tokens(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	unit := decimal.New(1, base.decimals).String()
+
+	sell, err := j.getQuote(ctx, base.mint, quote.mint, unit, "ExactIn")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bid quote: %w", err)
+	}
+	bid, err := quotePrice(sell, types.OrderSideSell, base.decimals, quote.decimals)
+	if err != nil {
+		return nil, err
+	}
+
+	buy, err := j.getQuote(ctx, quote.mint, base.mint, unit, "ExactOut")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ask quote: %w", err)
+	}
+	ask, err := quotePrice(buy, types.OrderSideBuy, base.decimals, quote.decimals)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ticker{
+		Symbol:    symbol,
+		LastPrice: bid.Add(ask).Div(decimal.NewFromInt(2)),
+		BidPrice:  bid,
+		AskPrice:  ask,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// quotePrice returns a quote's price in quote-asset units per base unit.
+func quotePrice(q *JupiterQuote, side types.OrderSide, baseDecimals, quoteDecimals int32) (decimal.Decimal, error) {
+	in, err := decimal.NewFromString(q.InAmount)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("bad quote inAmount %q: %w", q.InAmount, err)
+	}
+	out, err := decimal.NewFromString(q.OutAmount)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("bad quote outAmount %q: %w", q.OutAmount, err)
+	}
+
+	baseAmount, quoteAmount := out, in
+	if side == types.OrderSideSell {
+		baseAmount, quoteAmount = in, out
+	}
+	if !baseAmount.IsPositive() {
+		return decimal.Zero, fmt.Errorf("quote has no base amount")
+	}
+	return quoteAmount.Shift(-quoteDecimals).Div(baseAmount.Shift(-baseDecimals)), nil
+}
+
+// tokens resolves a symbol's base and quote tokens.
+func (j *JupiterAdapter) tokens(ctx context.Context, symbol string) (jupiterToken, jupiterToken, error) {
+	baseAsset, quoteAsset, ok := splitJupiterSymbol(symbol)
+	if !ok {
+		return jupiterToken{}, jupiterToken{}, fmt.Errorf("cannot split symbol %s into base and quote", symbol)
+	}
+
+	base, err := j.token(ctx, baseAsset)
+	if err != nil {
+		return jupiterToken{}, jupiterToken{}, err
+	}
+	quote, err := j.token(ctx, quoteAsset)
+	if err != nil {
+		return jupiterToken{}, jupiterToken{}, err
+	}
+	return base, quote, nil
+}
+
+// splitJupiterSymbol splits "SOL/USDC", "SOL-USDC" or "SOLUSDC" into base
+// and quote. Mint addresses are only accepted with a separator.
+func splitJupiterSymbol(symbol string) (string, string, bool) {
+	for _, sep := range []string{"/", "-", "_"} {
+		if base, quote, ok := strings.Cut(symbol, sep); ok {
+			return base, quote, base != "" && quote != ""
+		}
+	}
+
+	upper := strings.ToUpper(symbol)
+	for _, quote := range jupiterQuotes {
+		if base, ok := strings.CutSuffix(upper, quote); ok && base != "" {
+			return base, quote, true
+		}
+	}
+	return "", "", false
+}
+
+// token resolves an asset code or mint address, looking up the decimals of
+// unknown mints once.
+func (j *JupiterAdapter) token(ctx context.Context, asset string) (jupiterToken, error) {
+	if token, ok := jupiterTokens[strings.ToUpper(asset)]; ok {
+		return token, nil
+	}
+
+	j.mu.RLock()
+	decimals, ok := j.decimals[asset]
+	j.mu.RUnlock()
+	if ok {
+		return jupiterToken{mint: asset, decimals: decimals}, nil
+	}
+
+	var info JupiterTokenInfo
+	if err := j.getJSON(ctx, j.tokenURL+"/"+url.PathEscape(asset), &info); err != nil {
+		return jupiterToken{}, fmt.Errorf("failed to look up token %s: %w", asset, err)
+	}
+
+	j.mu.Lock()
+	j.decimals[asset] = int32(info.Decimals)
+	j.mu.Unlock()
+
+	return jupiterToken{mint: asset, decimals: int32(info.Decimals)}, nil
+}
+
+// getQuote requests a swap quote. amount is in base units of the input
+// (ExactIn) or output (ExactOut) token.
+func (j *JupiterAdapter) getQuote(ctx context.Context, inputMint, outputMint, amount, swapMode string) (*JupiterQuote, error) {
+	params := url.Values{}
+	params.Set("inputMint", inputMint)
+	params.Set("outputMint", outputMint)
+	params.Set("amount", amount)
+	params.Set("slippageBps", fmt.Sprint(j.slippageBPS))
+	params.Set("swapMode", swapMode)
+
+	var quote JupiterQuote
+	if err := j.getJSON(ctx, j.baseURL+"/quote?"+params.Encode(), &quote); err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// buildSwap asks Jupiter for an unsigned swap transaction for a quote.
+func (j *JupiterAdapter) buildSwap(ctx context.Context, quote *JupiterQuote) (*JupiterSwapResponse, error) {
+	body, err := json.Marshal(JupiterSwapRequest{
+		QuoteResponse:             *quote,
+		UserPublicKey:             j.wallet,
+		WrapAndUnwrapSOL:          true,
+		UseSharedAccounts:         true,
+		DynamicComputeUnitLimit:   true,
+		PrioritizationFeeLamports: j.config.PriorityFeeLamports,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal swap request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", j.baseURL+"/swap", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var swap JupiterSwapResponse
+	if err := j.do(req, &swap); err != nil {
+		return nil, err
+	}
+	if swap.SwapTransaction == "" {
+		return nil, fmt.Errorf("swap response has no transaction")
+	}
+	return &swap, nil
+}
+
+// getJSON fetches and decodes a JSON document.
+func (j *JupiterAdapter) getJSON(ctx context.Context, reqURL string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return err
+	}
+	return j.do(req, result)
+}
+
+// do sends a request and decodes a successful JSON response.
+func (j *JupiterAdapter) do(req *http.Request, result interface{}) error {
+	resp, err := j.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jupiter API error: %s (status %d)", strings.TrimSpace(string(body)), resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
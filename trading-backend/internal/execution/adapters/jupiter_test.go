@@ -0,0 +1,317 @@
+package adapters_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// fakeJupiter quotes SOL at 150 USDC to buy and 149 to sell, and returns an
+// unsigned v0 transaction for the wallet.
+type fakeJupiter struct {
+	mu     sync.Mutex
+	wallet ed25519.PublicKey
+	quotes []url.Values
+	swaps  []adapters.JupiterSwapRequest
+}
+
+func (f *fakeJupiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/quote":
+		q := r.URL.Query()
+		f.quotes = append(f.quotes, q)
+		amount, _ := decimal.NewFromString(q.Get("amount"))
+		quote := adapters.JupiterQuote{InputMint: q.Get("inputMint"), OutputMint: q.Get("outputMint"), SwapMode: q.Get("swapMode")}
+		if q.Get("swapMode") == "ExactOut" {
+			// Lamports out, USDC micro-units in
+			quote.OutAmount = amount.String()
+			quote.InAmount = amount.Mul(decimal.NewFromInt(150)).Div(decimal.NewFromInt(1000)).String()
+		} else {
+			quote.InAmount = amount.String()
+			quote.OutAmount = amount.Mul(decimal.NewFromInt(149)).Div(decimal.NewFromInt(1000)).String()
+		}
+		json.NewEncoder(w).Encode(quote)
+	case "/swap":
+		var req adapters.JupiterSwapRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		f.swaps = append(f.swaps, req)
+		json.NewEncoder(w).Encode(adapters.JupiterSwapResponse{
+			SwapTransaction:      base64.StdEncoding.EncodeToString(unsignedTransaction(f.wallet)),
+			LastValidBlockHeight: 1000,
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// unsignedTransaction builds a v0 transaction with one empty signature slot
+// for payer and no instructions.
+func unsignedTransaction(payer ed25519.PublicKey) []byte {
+	var tx bytes.Buffer
+	tx.WriteByte(1)
+	tx.Write(make([]byte, ed25519.SignatureSize))
+	tx.WriteByte(0x80)        // v0
+	tx.Write([]byte{1, 0, 1}) // One signer, one read-only unsigned account
+	tx.WriteByte(2)           // Account keys
+	tx.Write(payer)
+	tx.Write(bytes.Repeat([]byte{7}, 32))
+	tx.Write(bytes.Repeat([]byte{9}, 32)) // Recent blockhash
+	tx.WriteByte(0)                       // Instructions
+	tx.WriteByte(0)                       // Address table lookups
+	return tx.Bytes()
+}
+
+// fakeSolana verifies submitted signatures and reports a scripted status.
+type fakeSolana struct {
+	mu          sync.Mutex
+	t           *testing.T
+	wallet      ed25519.PublicKey
+	sent        [][]byte
+	slot        uint64
+	txErr       string
+	blockHeight uint64
+}
+
+func (f *fakeSolana) SendTransaction(ctx context.Context, tx []byte) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sig := tx[1 : 1+ed25519.SignatureSize]
+	if !ed25519.Verify(f.wallet, tx[1+ed25519.SignatureSize:], sig) {
+		f.t.Errorf("submitted transaction has an invalid signature")
+	}
+	f.sent = append(f.sent, tx)
+	return adapters.Base58Encode(sig), nil
+}
+func (f *fakeSolana) GetSignatureStatus(ctx context.Context, signature string) (uint64, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.slot, f.txErr, nil
+}
+func (f *fakeSolana) GetBlockHeight(ctx context.Context) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.blockHeight, nil
+}
+func (f *fakeSolana) GetBalance(ctx context.Context, address string) (decimal.Decimal, error) {
+	return decimal.NewFromInt(3), nil
+}
+func (f *fakeSolana) GetTokenBalanceByOwner(ctx context.Context, owner, mint string) (decimal.Decimal, error) {
+	return decimal.Zero, nil
+}
+
+// fakeWaiter records confirmation requests.
+type fakeWaiter struct {
+	mu     sync.Mutex
+	chains []string
+	blocks []uint64
+}
+
+func (f *fakeWaiter) WaitForConfirmation(ctx context.Context, chain string, blockNumber uint64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.chains = append(f.chains, chain)
+	f.blocks = append(f.blocks, blockNumber)
+	return nil
+}
+
+func newJupiter(t *testing.T, rpc *fakeSolana, waiter adapters.ConfirmationWaiter) (*adapters.JupiterAdapter, *fakeJupiter) {
+	t.Helper()
+
+	seed := bytes.Repeat([]byte{1}, ed25519.SeedSize)
+	key := ed25519.NewKeyFromSeed(seed)
+	wallet := key.Public().(ed25519.PublicKey)
+	rpc.t = t
+	rpc.wallet = wallet
+
+	fake := &fakeJupiter{wallet: wallet}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	adapter, err := adapters.NewJupiterAdapter(zap.NewNop(), adapters.JupiterConfig{
+		PrivateKey:     adapters.Base58Encode(key),
+		MaxSlippage:    decimal.NewFromFloat(0.05),
+		BaseURL:        server.URL,
+		ConfirmTimeout: 2 * time.Second,
+		PollInterval:   5 * time.Millisecond,
+	}, rpc, waiter)
+	if err != nil {
+		t.Fatalf("NewJupiterAdapter: %v", err)
+	}
+	return adapter, fake
+}
+
+func TestJupiterPlaceOrderSignsAndConfirms(t *testing.T) {
+	rpc := &fakeSolana{slot: 42, blockHeight: 900}
+	waiter := &fakeWaiter{}
+	adapter, fake := newJupiter(t, rpc, waiter)
+
+	order, err := adapter.PlaceOrder(context.Background(), &types.Order{
+		Symbol:   "SOL/USDC",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeMarket,
+		Quantity: decimal.NewFromInt(2),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+
+	quote := fake.quotes[0]
+	if quote.Get("swapMode") != "ExactOut" || quote.Get("amount") != "2000000000" || quote.Get("slippageBps") != "500" {
+		t.Fatalf("quote params = %v", quote)
+	}
+	if quote.Get("inputMint") != adapters.USDCMint || quote.Get("outputMint") != adapters.SOLMint {
+		t.Fatalf("quote mints = %s -> %s", quote.Get("inputMint"), quote.Get("outputMint"))
+	}
+	if fake.swaps[0].UserPublicKey != adapter.Wallet() {
+		t.Fatalf("swap user = %s, want %s", fake.swaps[0].UserPublicKey, adapter.Wallet())
+	}
+
+	if len(rpc.sent) != 1 {
+		t.Fatalf("sent %d transactions, want 1", len(rpc.sent))
+	}
+	if want := adapters.Base58Encode(rpc.sent[0][1 : 1+ed25519.SignatureSize]); order.ID != want {
+		t.Fatalf("order ID = %s, want signature %s", order.ID, want)
+	}
+	if order.Status != types.OrderStatusFilled || !order.FilledQty.Equal(decimal.NewFromInt(2)) {
+		t.Fatalf("order = %s filled %s, want filled 2", order.Status, order.FilledQty)
+	}
+	if !order.AvgFillPrice.Equal(decimal.NewFromInt(150)) {
+		t.Fatalf("fill price = %s, want 150", order.AvgFillPrice)
+	}
+	if len(waiter.blocks) != 1 || waiter.chains[0] != "solana" || waiter.blocks[0] != 42 {
+		t.Fatalf("confirmations = %v %v, want solana 42", waiter.chains, waiter.blocks)
+	}
+
+	stored, err := adapter.GetOrder(context.Background(), order.ID)
+	if err != nil || stored.Status != types.OrderStatusFilled {
+		t.Fatalf("GetOrder = %v, %v", stored, err)
+	}
+}
+
+func TestJupiterLimitOutsideQuoteIsNotSent(t *testing.T) {
+	rpc := &fakeSolana{slot: 42}
+	adapter, _ := newJupiter(t, rpc, nil)
+
+	_, err := adapter.PlaceOrder(context.Background(), &types.Order{
+		Symbol:   "SOLUSDC",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeLimit,
+		Quantity: decimal.NewFromInt(1),
+		Price:    decimal.NewFromInt(149),
+	})
+	if err == nil || !strings.Contains(err.Error(), "outside limit") {
+		t.Fatalf("PlaceOrder error = %v, want limit rejection", err)
+	}
+	if len(rpc.sent) != 0 {
+		t.Fatalf("sent %d transactions, want none", len(rpc.sent))
+	}
+}
+
+func TestJupiterSwapOutcomes(t *testing.T) {
+	tests := []struct {
+		name string
+		rpc  *fakeSolana
+		want types.OrderStatus
+	}{
+		{"expired", &fakeSolana{blockHeight: 1001}, types.OrderStatusExpired},
+		{"failed", &fakeSolana{slot: 42, txErr: `{"InstructionError":[0,{"Custom":6001}]}`}, types.OrderStatusRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter, _ := newJupiter(t, tt.rpc, &fakeWaiter{})
+
+			order, err := adapter.PlaceOrder(context.Background(), &types.Order{
+				Symbol:   "SOL/USDC",
+				Side:     types.OrderSideSell,
+				Type:     types.OrderTypeMarket,
+				Quantity: decimal.NewFromInt(1),
+			})
+			if err != nil {
+				t.Fatalf("PlaceOrder: %v", err)
+			}
+			if order.Status != tt.want || !order.FilledQty.IsZero() {
+				t.Fatalf("order = %s filled %s, want %s unfilled", order.Status, order.FilledQty, tt.want)
+			}
+		})
+	}
+}
+
+func TestJupiterTicker(t *testing.T) {
+	adapter, _ := newJupiter(t, &fakeSolana{}, nil)
+
+	ticker, err := adapter.GetTicker(context.Background(), "SOL/USDC")
+	if err != nil {
+		t.Fatalf("GetTicker: %v", err)
+	}
+	if !ticker.BidPrice.Equal(decimal.NewFromInt(149)) || !ticker.AskPrice.Equal(decimal.NewFromInt(150)) {
+		t.Fatalf("bid/ask = %s/%s, want 149/150", ticker.BidPrice, ticker.AskPrice)
+	}
+	if !ticker.LastPrice.Equal(decimal.NewFromFloat(149.5)) {
+		t.Fatalf("last = %s, want 149.5", ticker.LastPrice)
+	}
+}
+
+func TestBase58(t *testing.T) {
+	if got := adapters.Base58Encode([]byte("Hello World!")); got != "2NEpo7TZRRrLZSi2U" {
+		t.Fatalf("encode = %s", got)
+	}
+	if got := adapters.Base58Encode([]byte{0, 0, 1}); got != "112" {
+		t.Fatalf("encode with leading zeros = %s", got)
+	}
+
+	data := new(big.Int).Lsh(big.NewInt(1), 200).Bytes()
+	decoded, err := adapters.Base58Decode(adapters.Base58Encode(append([]byte{0}, data...)))
+	if err != nil || !bytes.Equal(decoded, append([]byte{0}, data...)) {
+		t.Fatalf("round trip = %x, %v", decoded, err)
+	}
+	if _, err := adapters.Base58Decode("0OIl"); err == nil {
+		t.Fatal("decoded characters outside the alphabet")
+	}
+}
+
+func TestParseSolanaKey(t *testing.T) {
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{3}, ed25519.SeedSize))
+
+	ints := make([]string, len(key))
+	for i, b := range key {
+		ints[i] = fmt.Sprint(b)
+	}
+	fromJSON, err := adapters.ParseSolanaKey("[" + strings.Join(ints, ",") + "]")
+	if err != nil {
+		t.Fatalf("ParseSolanaKey(json): %v", err)
+	}
+	fromBase58, err := adapters.ParseSolanaKey(adapters.Base58Encode(key))
+	if err != nil {
+		t.Fatalf("ParseSolanaKey(base58): %v", err)
+	}
+	if !bytes.Equal(fromJSON, key) || !bytes.Equal(fromBase58, key) {
+		t.Fatal("parsed keys differ from the original")
+	}
+
+	tampered := append([]byte(nil), key...)
+	tampered[40] ^= 1
+	if _, err := adapters.ParseSolanaKey(adapters.Base58Encode(tampered)); err == nil {
+		t.Fatal("accepted a keypair whose public key doesn't match")
+	}
+}
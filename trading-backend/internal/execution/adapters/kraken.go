@@ -0,0 +1,711 @@
+// Package adapters provides the Kraken spot exchange adapter.
+package adapters
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// KrakenAdapter implements the exchange adapter for Kraken spot.
+type KrakenAdapter struct {
+	logger     *zap.Logger
+	apiKey     string
+	apiSecret  []byte // Decoded from the base64 secret Kraken issues
+	baseURL    string
+	wsURL      string
+	httpClient *http.Client
+	nonce      *NonceGenerator
+	mu         sync.RWMutex
+
+	// Private calls are serialized so nonces reach Kraken in order
+	privateMu sync.Mutex
+
+	// Counter-based REST limit for queries; trading calls are limited
+	// separately by Kraken and not counted here
+	rateLimiter *RateLimiter
+
+	// Asset pair precision from AssetPairs, keyed by altname
+	pairs        map[string]KrakenPairInfo
+	pairsUpdated time.Time
+
+	// WebSocket connection
+	wsConn        *websocket.Conn
+	wsClosed      bool
+	subscriptions []krakenSubscription // Restored on reconnect
+	books         map[string]*types.OrderBook
+	bookDepth     int
+
+	// Callbacks
+	onTicker    func(ticker *Ticker)
+	onOrderBook func(symbol string, ob *types.OrderBook)
+	onResync    func(event WSResyncEvent)
+}
+
+var (
+	_ ExchangeAdapter   = (*KrakenAdapter)(nil)
+	_ OpenOrdersAdapter = (*KrakenAdapter)(nil)
+	_ ClientOrderLookup = (*KrakenAdapter)(nil)
+)
+
+// KrakenConfig contains Kraken adapter configuration.
+type KrakenConfig struct {
+	APIKey    string `json:"apiKey"`
+	APISecret string `json:"apiSecret"` // Base64, as issued by Kraken
+	NonceFile string `json:"nonceFile"` // Persists the last nonce across restarts; empty keeps it in memory
+	BookDepth int    `json:"bookDepth"` // WebSocket book depth: 10, 25, 100, 500 or 1000
+
+	// Endpoint overrides, e.g. for a proxy; empty uses Kraken's
+	BaseURL string `json:"baseUrl,omitempty"`
+	WSURL   string `json:"wsUrl,omitempty"`
+}
+
+// krakenResponse is the envelope of every Kraken REST response.
+type krakenResponse struct {
+	Error  []string        `json:"error"`
+	Result json.RawMessage `json:"result"`
+}
+
+// KrakenOrder is an order as returned by QueryOrders, OpenOrders and
+// ClosedOrders.
+type KrakenOrder struct {
+	UserRef  int64   `json:"userref"`
+	ClientID string  `json:"cl_ord_id"`
+	Status   string  `json:"status"`
+	OpenTime float64 `json:"opentm"`
+	Close    float64 `json:"closetm"`
+	Descr    struct {
+		Pair      string `json:"pair"`
+		Type      string `json:"type"`
+		OrderType string `json:"ordertype"`
+		Price     string `json:"price"`
+		Price2    string `json:"price2"`
+	} `json:"descr"`
+	Volume     decimal.Decimal `json:"vol"`
+	VolumeExec decimal.Decimal `json:"vol_exec"`
+	Cost       decimal.Decimal `json:"cost"`
+	Fee        decimal.Decimal `json:"fee"`
+	Price      decimal.Decimal `json:"price"` // Average fill price
+}
+
+// NewKrakenAdapter creates a new Kraken adapter.
+func NewKrakenAdapter(logger *zap.Logger, config KrakenConfig) (*KrakenAdapter, error) {
+	secret, err := base64.StdEncoding.DecodeString(config.APISecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode kraken API secret: %w", err)
+	}
+
+	nonce, err := NewNonceGenerator(config.NonceFile)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := "https://api.kraken.com"
+	if config.BaseURL != "" {
+		baseURL = strings.TrimSuffix(config.BaseURL, "/")
+	}
+	wsURL := "wss://ws.kraken.com/v2"
+	if config.WSURL != "" {
+		wsURL = config.WSURL
+	}
+
+	bookDepth := config.BookDepth
+	if bookDepth <= 0 {
+		bookDepth = defaultKrakenBookDepth
+	}
+
+	return &KrakenAdapter{
+		logger:      logger.Named("kraken"),
+		apiKey:      config.APIKey,
+		apiSecret:   secret,
+		baseURL:     baseURL,
+		wsURL:       wsURL,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		nonce:       nonce,
+		rateLimiter: NewRateLimiter(15, 45*time.Second), // Starter tier: 15 calls, decaying 1 per 3s
+		pairs:       make(map[string]KrakenPairInfo),
+		books:       make(map[string]*types.OrderBook),
+		bookDepth:   bookDepth,
+	}, nil
+}
+
+// Name returns the exchange name used for routing.
+func (k *KrakenAdapter) Name() string {
+	return "kraken"
+}
+
+// Connect checks connectivity and loads asset pair precision.
+func (k *KrakenAdapter) Connect(ctx context.Context) error {
+	k.logger.Info("Connecting to Kraken")
+
+	var status struct {
+		Status string `json:"status"`
+	}
+	if err := k.publicRequest(ctx, "SystemStatus", nil, &status); err != nil {
+		return fmt.Errorf("failed to reach Kraken: %w", err)
+	}
+	if status.Status != "online" {
+		k.logger.Warn("Kraken is not fully online", zap.String("status", status.Status))
+	}
+
+	if err := k.refreshPairs(ctx); err != nil {
+		return fmt.Errorf("failed to load asset pairs: %w", err)
+	}
+
+	k.logger.Info("Successfully connected to Kraken")
+	return nil
+}
+
+// Ping checks Kraken is reachable and fully online. Maintenance and the
+// cancel-only and post-only modes are reported as errors.
+func (k *KrakenAdapter) Ping(ctx context.Context) error {
+	var status struct {
+		Status string `json:"status"`
+	}
+	if err := k.publicRequest(ctx, "SystemStatus", nil, &status); err != nil {
+		return fmt.Errorf("failed to reach Kraken: %w", err)
+	}
+	if status.Status != "online" {
+		return fmt.Errorf("kraken is %s", status.Status)
+	}
+	return nil
+}
+
+// Disconnect closes the WebSocket connection.
+func (k *KrakenAdapter) Disconnect() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.wsClosed = true
+	if k.wsConn != nil {
+		err := k.wsConn.Close()
+		k.wsConn = nil
+		return err
+	}
+	return nil
+}
+
+// PlaceOrder places an order on Kraken. The returned order carries the
+// transaction ID; query it for fills.
+func (k *KrakenAdapter) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	info, err := k.PairInfo(ctx, order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	orderType, err := krakenOrderType(order.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	price, price2 := krakenOrderPrices(order)
+	volume, price, err := info.Normalize(order.Side, order.Quantity, price)
+	if err != nil {
+		return nil, fmt.Errorf("order for %s violates pair precision: %w", order.Symbol, err)
+	}
+	if price2.IsPositive() {
+		price2 = info.RoundPrice(order.Side, price2)
+	}
+
+	params := url.Values{}
+	params.Set("pair", info.Altname)
+	params.Set("type", string(order.Side))
+	params.Set("ordertype", orderType)
+	params.Set("volume", volume.String())
+	if price.IsPositive() {
+		params.Set("price", price.String())
+	}
+	if price2.IsPositive() {
+		params.Set("price2", price2.String())
+	}
+	if order.PostOnly && order.Type == types.OrderTypeLimit {
+		params.Set("oflags", "post")
+	}
+	if order.ClientOrderID != "" {
+		params.Set("cl_ord_id", order.ClientOrderID)
+	}
+
+	var result struct {
+		TxID []string `json:"txid"`
+	}
+	if err := k.privateRequest(ctx, "AddOrder", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+	if len(result.TxID) == 0 {
+		return nil, errors.New("kraken returned no transaction ID")
+	}
+
+	now := time.Now()
+	placed := *order
+	placed.ID = result.TxID[0]
+	placed.Quantity = volume
+	placed.Status = types.OrderStatusOpen
+	placed.CreatedAt = now
+	placed.UpdatedAt = now
+	switch order.Type {
+	case types.OrderTypeLimit:
+		placed.Price = price
+	case types.OrderTypeStopLimit:
+		placed.StopPrice = price
+		placed.Price = price2
+	case types.OrderTypeStopMarket, types.OrderTypeStopLoss, types.OrderTypeTakeProfit:
+		placed.StopPrice = price
+	}
+	return &placed, nil
+}
+
+// CancelOrder cancels an order by transaction ID.
+func (k *KrakenAdapter) CancelOrder(ctx context.Context, orderID string) error {
+	params := url.Values{}
+	params.Set("txid", orderID)
+
+	var result struct {
+		Count int `json:"count"`
+	}
+	if err := k.privateRequest(ctx, "CancelOrder", params, &result); err != nil {
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+	if result.Count == 0 {
+		return fmt.Errorf("order not cancelled: %s", orderID)
+	}
+	return nil
+}
+
+// GetOrder gets an order by transaction ID.
+func (k *KrakenAdapter) GetOrder(ctx context.Context, orderID string) (*types.Order, error) {
+	if err := k.rateLimiter.Acquire(ctx, 1); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("txid", orderID)
+
+	var result map[string]KrakenOrder
+	if err := k.privateRequest(ctx, "QueryOrders", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	ko, ok := result[orderID]
+	if !ok {
+		return nil, fmt.Errorf("order not found: %s", orderID)
+	}
+	return convertKrakenOrder(orderID, &ko), nil
+}
+
+// GetOpenOrders lists open orders for a symbol, or for all symbols when
+// symbol is empty.
+func (k *KrakenAdapter) GetOpenOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	if err := k.rateLimiter.Acquire(ctx, 1); err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Open map[string]KrakenOrder `json:"open"`
+	}
+	if err := k.privateRequest(ctx, "OpenOrders", url.Values{}, &result); err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+
+	pair := ""
+	if symbol != "" {
+		pair = KrakenPair(symbol)
+	}
+
+	orders := make([]*types.Order, 0, len(result.Open))
+	for txid, ko := range result.Open {
+		if pair != "" && KrakenPair(ko.Descr.Pair) != pair {
+			continue
+		}
+		orders = append(orders, convertKrakenOrder(txid, &ko))
+	}
+	return orders, nil
+}
+
+// GetOrderByClientID returns the order placed with clientOrderID, searching
+// open orders and then closed ones, so an order that filled after its
+// response was lost is still found.
+func (k *KrakenAdapter) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*types.Order, error) {
+	for _, method := range []string{"OpenOrders", "ClosedOrders"} {
+		if err := k.rateLimiter.Acquire(ctx, 1); err != nil {
+			return nil, err
+		}
+
+		params := url.Values{}
+		params.Set("cl_ord_id", clientOrderID)
+
+		var result struct {
+			Open   map[string]KrakenOrder `json:"open"`
+			Closed map[string]KrakenOrder `json:"closed"`
+		}
+		if err := k.privateRequest(ctx, method, params, &result); err != nil {
+			return nil, fmt.Errorf("failed to get order %s: %w", clientOrderID, err)
+		}
+
+		orders := result.Open
+		if method == "ClosedOrders" {
+			orders = result.Closed
+		}
+		for txid, ko := range orders {
+			if ko.ClientID == clientOrderID {
+				return convertKrakenOrder(txid, &ko), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s: %w", clientOrderID, ErrOrderNotFound)
+}
+
+// GetBalance gets the balance of an asset, in either naming (BTC or XXBT).
+func (k *KrakenAdapter) GetBalance(ctx context.Context, asset string) (decimal.Decimal, error) {
+	balances, err := k.GetBalances(ctx)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return balances[CommonAsset(asset)], nil
+}
+
+// GetBalances returns all balances keyed by common asset code. Balances
+// held in earn products (suffixed .F, .S, ...) are not spendable and are
+// skipped.
+func (k *KrakenAdapter) GetBalances(ctx context.Context) (map[string]decimal.Decimal, error) {
+	if err := k.rateLimiter.Acquire(ctx, 1); err != nil {
+		return nil, err
+	}
+
+	var result map[string]decimal.Decimal
+	if err := k.privateRequest(ctx, "Balance", url.Values{}, &result); err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	balances := make(map[string]decimal.Decimal, len(result))
+	for asset, amount := range result {
+		if strings.Contains(asset, ".") {
+			continue
+		}
+		balances[CommonAsset(asset)] = amount
+	}
+	return balances, nil
+}
+
+// GetPositions returns current positions (for spot, non-fiat balances > 0).
+func (k *KrakenAdapter) GetPositions(ctx context.Context) ([]*types.Position, error) {
+	balances, err := k.GetBalances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var positions []*types.Position
+	for asset, amount := range balances {
+		if isFiat(asset) || !amount.IsPositive() {
+			continue
+		}
+		positions = append(positions, &types.Position{
+			Symbol:   asset + "/USD",
+			Side:     types.PositionSideLong,
+			Quantity: amount,
+		})
+	}
+	return positions, nil
+}
+
+// isFiat reports whether a common asset code is cash rather than a position.
+func isFiat(asset string) bool {
+	switch asset {
+	case "USD", "EUR", "GBP", "CAD", "JPY", "AUD", "CHF", "USDT", "USDC", "DAI":
+		return true
+	}
+	return false
+}
+
+// GetTicker gets the current price snapshot for a symbol.
+func (k *KrakenAdapter) GetTicker(ctx context.Context, symbol string) (*Ticker, error) {
+	params := url.Values{}
+	params.Set("pair", KrakenPair(symbol))
+
+	var result map[string]struct {
+		Ask    []decimal.Decimal `json:"a"`
+		Bid    []decimal.Decimal `json:"b"`
+		Last   []decimal.Decimal `json:"c"`
+		Volume []decimal.Decimal `json:"v"`
+	}
+	if err := k.publicRequest(ctx, "Ticker", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get ticker: %w", err)
+	}
+
+	for _, t := range result {
+		ticker := &Ticker{Symbol: symbol, Timestamp: time.Now()}
+		if len(t.Last) > 0 {
+			ticker.LastPrice = t.Last[0]
+		}
+		if len(t.Bid) > 0 {
+			ticker.BidPrice = t.Bid[0]
+		}
+		if len(t.Ask) > 0 {
+			ticker.AskPrice = t.Ask[0]
+		}
+		if len(t.Volume) > 1 {
+			ticker.Volume = t.Volume[1] // Last 24 hours
+		}
+		return ticker, nil
+	}
+	return nil, fmt.Errorf("no ticker for %s", symbol)
+}
+
+// GetOrderBook gets the order book for a symbol.
+func (k *KrakenAdapter) GetOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error) {
+	params := url.Values{}
+	params.Set("pair", KrakenPair(symbol))
+	if limit > 0 {
+		params.Set("count", strconv.Itoa(limit))
+	}
+
+	// Levels are [price, volume, timestamp]
+	var result map[string]struct {
+		Asks [][]json.RawMessage `json:"asks"`
+		Bids [][]json.RawMessage `json:"bids"`
+	}
+	if err := k.publicRequest(ctx, "Depth", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get order book: %w", err)
+	}
+
+	for _, book := range result {
+		return &types.OrderBook{
+			Symbol:    symbol,
+			Bids:      parseKrakenLevels(book.Bids),
+			Asks:      parseKrakenLevels(book.Asks),
+			Timestamp: time.Now(),
+		}, nil
+	}
+	return nil, fmt.Errorf("no order book for %s", symbol)
+}
+
+// parseKrakenLevels decodes REST depth levels, skipping malformed ones.
+func parseKrakenLevels(raw [][]json.RawMessage) []types.OrderBookLevel {
+	levels := make([]types.OrderBookLevel, 0, len(raw))
+	for _, level := range raw {
+		if len(level) < 2 {
+			continue
+		}
+		var price, qty decimal.Decimal
+		if price.UnmarshalJSON(level[0]) != nil || qty.UnmarshalJSON(level[1]) != nil {
+			continue
+		}
+		levels = append(levels, types.OrderBookLevel{Price: price, Quantity: qty})
+	}
+	return levels
+}
+
+// publicRequest calls a public endpoint and decodes its result.
+func (k *KrakenAdapter) publicRequest(ctx context.Context, method string, params url.Values, result interface{}) error {
+	reqURL := k.baseURL + "/0/public/" + method
+	if len(params) > 0 {
+		reqURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return err
+	}
+	return k.do(req, method, result)
+}
+
+// privateRequest calls a private endpoint with a fresh nonce and API-Sign.
+func (k *KrakenAdapter) privateRequest(ctx context.Context, method string, params url.Values, result interface{}) error {
+	k.privateMu.Lock()
+	defer k.privateMu.Unlock()
+
+	nonce, err := k.nonce.Next()
+	if err != nil {
+		return err
+	}
+	params.Set("nonce", strconv.FormatUint(nonce, 10))
+	body := params.Encode()
+
+	path := "/0/private/" + method
+	req, err := http.NewRequestWithContext(ctx, "POST", k.baseURL+path, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("API-Key", k.apiKey)
+	req.Header.Set("API-Sign", SignKrakenRequest(k.apiSecret, path, params.Get("nonce"), body))
+
+	return k.do(req, method, result)
+}
+
+// do sends a request and unwraps Kraken's error/result envelope.
+func (k *KrakenAdapter) do(req *http.Request, method string, result interface{}) error {
+	resp, err := k.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s failed with status %d: %s", method, resp.StatusCode, string(body))
+	}
+
+	var envelope krakenResponse
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(envelope.Error) > 0 {
+		if strings.Contains(strings.Join(envelope.Error, ","), "EAPI:Rate limit exceeded") {
+			k.rateLimiter.Backoff(defaultRetryAfter)
+		}
+		return fmt.Errorf("%s: %s", method, strings.Join(envelope.Error, "; "))
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Result, result); err != nil {
+		return fmt.Errorf("failed to parse %s result: %w", method, err)
+	}
+	return nil
+}
+
+// SignKrakenRequest computes API-Sign: base64 HMAC-SHA512, keyed by the
+// decoded secret, of the URI path followed by SHA256(nonce + POST data).
+func SignKrakenRequest(secret []byte, path, nonce, postData string) string {
+	digest := sha256.Sum256([]byte(nonce + postData))
+
+	mac := hmac.New(sha512.New, secret)
+	mac.Write([]byte(path))
+	mac.Write(digest[:])
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// krakenOrderType converts our order type to Kraken's.
+func krakenOrderType(t types.OrderType) (string, error) {
+	switch t {
+	case types.OrderTypeMarket:
+		return "market", nil
+	case types.OrderTypeLimit:
+		return "limit", nil
+	case types.OrderTypeStopMarket, types.OrderTypeStopLoss:
+		return "stop-loss", nil
+	case types.OrderTypeStopLimit:
+		return "stop-loss-limit", nil
+	case types.OrderTypeTakeProfit:
+		return "take-profit", nil
+	default:
+		return "", fmt.Errorf("unsupported order type for kraken: %s", t)
+	}
+}
+
+// krakenOrderPrices returns Kraken's price and price2 for an order. Trigger
+// orders put the trigger in price and any limit in price2.
+func krakenOrderPrices(order *types.Order) (decimal.Decimal, decimal.Decimal) {
+	switch order.Type {
+	case types.OrderTypeMarket:
+		return decimal.Zero, decimal.Zero
+	case types.OrderTypeStopLimit:
+		return order.StopPrice, order.Price
+	case types.OrderTypeStopMarket, types.OrderTypeStopLoss, types.OrderTypeTakeProfit:
+		if order.StopPrice.IsPositive() {
+			return order.StopPrice, decimal.Zero
+		}
+		return order.Price, decimal.Zero
+	default:
+		return order.Price, decimal.Zero
+	}
+}
+
+// convertKrakenOrder converts a Kraken order to our format.
+func convertKrakenOrder(txid string, ko *KrakenOrder) *types.Order {
+	order := &types.Order{
+		ID:            txid,
+		ClientOrderID: ko.ClientID,
+		Symbol:        KrakenSymbol(ko.Descr.Pair),
+		Side:          types.OrderSide(ko.Descr.Type),
+		Quantity:      ko.Volume,
+		FilledQty:     ko.VolumeExec,
+		AvgFillPrice:  ko.Price,
+		Commission:    ko.Fee,
+		Status:        convertKrakenStatus(ko.Status, ko.VolumeExec),
+		CreatedAt:     krakenTime(ko.OpenTime),
+		UpdatedAt:     krakenTime(ko.OpenTime),
+	}
+	if ko.Close > 0 {
+		order.UpdatedAt = krakenTime(ko.Close)
+		if order.Status == types.OrderStatusFilled {
+			filledAt := order.UpdatedAt
+			order.FilledAt = &filledAt
+		}
+	}
+
+	price, price2 := parseFilterValue(ko.Descr.Price), parseFilterValue(ko.Descr.Price2)
+	switch ko.Descr.OrderType {
+	case "market":
+		order.Type = types.OrderTypeMarket
+	case "limit":
+		order.Type = types.OrderTypeLimit
+		order.Price = price
+	case "stop-loss":
+		order.Type = types.OrderTypeStopMarket
+		order.StopPrice = price
+	case "stop-loss-limit":
+		order.Type = types.OrderTypeStopLimit
+		order.StopPrice = price
+		order.Price = price2
+	case "take-profit", "take-profit-limit":
+		order.Type = types.OrderTypeTakeProfit
+		order.StopPrice = price
+		order.Price = price2
+	}
+
+	return order
+}
+
+// convertKrakenStatus converts a Kraken order status. Kraken reports
+// partially filled orders as open, and fully filled ones as closed.
+func convertKrakenStatus(status string, filled decimal.Decimal) types.OrderStatus {
+	switch status {
+	case "pending":
+		return types.OrderStatusPending
+	case "open":
+		if filled.IsPositive() {
+			return types.OrderStatusPartiallyFilled
+		}
+		return types.OrderStatusOpen
+	case "closed":
+		return types.OrderStatusFilled
+	case "canceled":
+		return types.OrderStatusCancelled
+	case "expired":
+		return types.OrderStatusExpired
+	default:
+		return types.OrderStatusOpen
+	}
+}
+
+// krakenTime converts Kraken's fractional unix seconds.
+func krakenTime(seconds float64) time.Time {
+	if seconds <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMicro(int64(seconds * 1e6))
+}
@@ -0,0 +1,241 @@
+// Package adapters provides Kraken asset-pair naming and precision handling.
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// krakenAssets maps Kraken asset codes, including the legacy X/Z-prefixed
+// ones, to the common codes used elsewhere.
+var krakenAssets = map[string]string{
+	"XBT":  "BTC",
+	"XXBT": "BTC",
+	"XDG":  "DOGE",
+	"XXDG": "DOGE",
+	"XETH": "ETH",
+	"XETC": "ETC",
+	"XLTC": "LTC",
+	"XXRP": "XRP",
+	"XXLM": "XLM",
+	"XXMR": "XMR",
+	"XZEC": "ZEC",
+	"XMLN": "MLN",
+	"XREP": "REP",
+	"ZUSD": "USD",
+	"ZEUR": "EUR",
+	"ZGBP": "GBP",
+	"ZCAD": "CAD",
+	"ZJPY": "JPY",
+	"ZAUD": "AUD",
+	"ZCHF": "CHF",
+}
+
+// krakenQuotes are the quote assets recognized when splitting an
+// unseparated pair, longest first so USDT is not read as USD.
+var krakenQuotes = []string{
+	"ZUSD", "ZEUR", "ZGBP", "ZCAD", "ZJPY", "ZAUD", "ZCHF", "XXBT", "XETH",
+	"USDT", "USDC", "USD", "EUR", "GBP", "CAD", "JPY", "AUD", "CHF",
+	"XBT", "BTC", "ETH", "DAI", "DOT",
+}
+
+// KrakenAsset converts a common asset code to Kraken's (BTC -> XBT).
+func KrakenAsset(asset string) string {
+	switch asset = strings.ToUpper(asset); asset {
+	case "BTC":
+		return "XBT"
+	case "DOGE":
+		return "XDG"
+	default:
+		return asset
+	}
+}
+
+// CommonAsset converts a Kraken asset code to the common one
+// (XXBT -> BTC, ZUSD -> USD).
+func CommonAsset(asset string) string {
+	asset = strings.ToUpper(asset)
+	if common, ok := krakenAssets[asset]; ok {
+		return common
+	}
+	return asset
+}
+
+// splitKrakenSymbol splits "BTC/USD", "BTC-USD", "BTCUSD" or a Kraken pair
+// such as "XXBTZUSD" into common base and quote assets.
+func splitKrakenSymbol(symbol string) (string, string, bool) {
+	symbol = strings.ToUpper(symbol)
+	for _, sep := range []string{"/", "-", "_"} {
+		if base, quote, ok := strings.Cut(symbol, sep); ok {
+			return CommonAsset(base), CommonAsset(quote), base != "" && quote != ""
+		}
+	}
+
+	for _, quote := range krakenQuotes {
+		if base, ok := strings.CutSuffix(symbol, quote); ok && base != "" {
+			return CommonAsset(base), CommonAsset(quote), true
+		}
+	}
+	return "", "", false
+}
+
+// KrakenPair converts a symbol to Kraken's REST pair name
+// (BTC/USD -> XBTUSD).
+func KrakenPair(symbol string) string {
+	base, quote, ok := splitKrakenSymbol(symbol)
+	if !ok {
+		return strings.ToUpper(symbol)
+	}
+	return KrakenAsset(base) + KrakenAsset(quote)
+}
+
+// KrakenSymbol converts a Kraken pair name to a BASE/QUOTE symbol
+// (XXBTZUSD -> BTC/USD).
+func KrakenSymbol(pair string) string {
+	base, quote, ok := splitKrakenSymbol(pair)
+	if !ok {
+		return pair
+	}
+	return base + "/" + quote
+}
+
+// krakenWSSymbol converts a symbol to the WebSocket v2 form, which uses
+// common asset codes (BTC/USD).
+func krakenWSSymbol(symbol string) string {
+	return KrakenSymbol(KrakenPair(symbol))
+}
+
+// KrakenPairInfo holds one asset pair's precision and order minimums.
+// Zero values disable the corresponding check.
+type KrakenPairInfo struct {
+	Altname      string          `json:"altname"`
+	WSName       string          `json:"wsname"`
+	Base         string          `json:"base"`
+	Quote        string          `json:"quote"`
+	PairDecimals int32           `json:"pair_decimals"` // Price precision
+	LotDecimals  int32           `json:"lot_decimals"`  // Volume precision
+	CostDecimals int32           `json:"cost_decimals"`
+	OrderMin     decimal.Decimal `json:"ordermin"`
+	CostMin      decimal.Decimal `json:"costmin"`
+	TickSize     decimal.Decimal `json:"tick_size"`
+	Status       string          `json:"status"`
+}
+
+// Normalize truncates volume to the pair's lot decimals and rounds price to
+// its tick size (or price decimals) on the passive side for an order of
+// side, then checks the minimums. A zero price (market orders) skips the
+// price and cost checks.
+func (p KrakenPairInfo) Normalize(side types.OrderSide, volume, price decimal.Decimal) (decimal.Decimal, decimal.Decimal, error) {
+	volume = volume.Truncate(p.LotDecimals)
+	if !volume.IsPositive() {
+		return volume, price, fmt.Errorf("volume rounds to zero at %d lot decimals", p.LotDecimals)
+	}
+	if p.OrderMin.IsPositive() && volume.LessThan(p.OrderMin) {
+		return volume, price, fmt.Errorf("volume %s below minimum %s", volume, p.OrderMin)
+	}
+
+	if !price.IsPositive() {
+		return volume, price, nil
+	}
+
+	price = p.RoundPrice(side, price)
+	cost := volume.Mul(price)
+	if p.CostMin.IsPositive() && cost.LessThan(p.CostMin) {
+		return volume, price, fmt.Errorf("order cost %s below minimum %s", cost, p.CostMin)
+	}
+
+	return volume, price, nil
+}
+
+// RoundPrice rounds price to the tick on the passive side for an order of
+// side (buys floor, sells ceil), stepping by the pair's price decimals when
+// the tick size is unknown.
+func (p KrakenPairInfo) RoundPrice(side types.OrderSide, price decimal.Decimal) decimal.Decimal {
+	return types.RoundPriceToTick(price, p.Spec().TickSize, side)
+}
+
+// Spec converts the pair's precision to the venue-neutral spec the sizing
+// and execution paths round with. Without a tick size, prices step by the
+// pair's price decimals.
+func (p KrakenPairInfo) Spec() types.SymbolSpec {
+	tickSize := p.TickSize
+	if !tickSize.IsPositive() {
+		tickSize = decimal.New(1, -p.PairDecimals)
+	}
+	return types.SymbolSpec{
+		Symbol:         KrakenSymbol(p.Altname),
+		TickSize:       tickSize,
+		StepSize:       decimal.New(1, -p.LotDecimals),
+		MinNotional:    p.CostMin,
+		BasePrecision:  p.LotDecimals,
+		QuotePrecision: p.CostDecimals,
+	}
+}
+
+// PairInfo returns the cached info for a symbol's pair, refreshing it when
+// the cache is older than exchangeInfoRefresh. A stale cache is used if the
+// refresh fails.
+func (k *KrakenAdapter) PairInfo(ctx context.Context, symbol string) (KrakenPairInfo, error) {
+	pair := KrakenPair(symbol)
+
+	k.mu.RLock()
+	info, ok := k.pairs[pair]
+	fresh := time.Since(k.pairsUpdated) < exchangeInfoRefresh
+	k.mu.RUnlock()
+
+	if ok && fresh {
+		return info, nil
+	}
+
+	if err := k.refreshPairs(ctx); err != nil {
+		if ok {
+			k.logger.Warn("Using stale pair info", zap.String("pair", pair), zap.Error(err))
+			return info, nil
+		}
+		return KrakenPairInfo{}, fmt.Errorf("failed to load pair info: %w", err)
+	}
+
+	k.mu.RLock()
+	info, ok = k.pairs[pair]
+	k.mu.RUnlock()
+
+	if !ok {
+		return KrakenPairInfo{}, fmt.Errorf("unknown pair: %s", pair)
+	}
+	return info, nil
+}
+
+// refreshPairs replaces the pair cache from AssetPairs, keyed by altname.
+func (k *KrakenAdapter) refreshPairs(ctx context.Context) error {
+	var result map[string]KrakenPairInfo
+	if err := k.publicRequest(ctx, "AssetPairs", nil, &result); err != nil {
+		return err
+	}
+
+	pairs := make(map[string]KrakenPairInfo, len(result))
+	specs := make([]types.SymbolSpec, 0, len(result))
+	for name, info := range result {
+		if info.Altname == "" {
+			info.Altname = name
+		}
+		pairs[info.Altname] = info
+		spec := info.Spec()
+		spec.Venue = k.Name()
+		specs = append(specs, spec)
+	}
+	types.SymbolSpecs.Set(specs...)
+
+	k.mu.Lock()
+	k.pairs = pairs
+	k.pairsUpdated = time.Now()
+	k.mu.Unlock()
+
+	k.logger.Debug("Refreshed asset pairs", zap.Int("pairs", len(pairs)))
+	return nil
+}
@@ -0,0 +1,440 @@
+package adapters_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// The example from Kraken's REST authentication documentation
+const krakenDocSecret = "kQH5HW/8p1uGOVjbgWA7FunAmGO8lsSUXNsu3eow76sz84Q18fWxnyRzBHCd3pd5nE9qa99HAZtuZuj6F1huXg=="
+
+func TestSignKrakenRequestMatchesDocumentation(t *testing.T) {
+	secret, _ := base64.StdEncoding.DecodeString(krakenDocSecret)
+	got := adapters.SignKrakenRequest(secret, "/0/private/AddOrder", "1616492376594",
+		"nonce=1616492376594&ordertype=limit&pair=XBTUSD&price=37500&type=buy&volume=1.25")
+
+	want := "4/dpxb3iT4tp/ZCVEwSnEsLxx0bqyhLpdfOpc6fn7OR8+UClSV5n9E6aSS8MPtnRfp32bAb0nmbRn6H8ndwLUQ=="
+	if got != want {
+		t.Errorf("API-Sign = %s, want %s", got, want)
+	}
+}
+
+func TestKrakenSymbolNormalization(t *testing.T) {
+	pairs := map[string]string{
+		"BTC/USD":  "XBTUSD",
+		"btc-usdt": "XBTUSDT",
+		"BTCUSD":   "XBTUSD",
+		"DOGE/USD": "XDGUSD",
+		"ETH/BTC":  "ETHXBT",
+		"SOL/EUR":  "SOLEUR",
+	}
+	for symbol, want := range pairs {
+		if got := adapters.KrakenPair(symbol); got != want {
+			t.Errorf("KrakenPair(%q) = %q, want %q", symbol, got, want)
+		}
+	}
+
+	symbols := map[string]string{
+		"XXBTZUSD": "BTC/USD",
+		"XBTUSDT":  "BTC/USDT",
+		"XETHXXBT": "ETH/BTC",
+		"XDGUSD":   "DOGE/USD",
+		"SOLUSD":   "SOL/USD",
+		"BTC/USD":  "BTC/USD",
+	}
+	for pair, want := range symbols {
+		if got := adapters.KrakenSymbol(pair); got != want {
+			t.Errorf("KrakenSymbol(%q) = %q, want %q", pair, got, want)
+		}
+	}
+}
+
+func TestNonceGeneratorSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kraken.nonce")
+
+	gen, err := adapters.NewNonceGenerator(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last uint64
+	for i := 0; i < 3; i++ {
+		n, err := gen.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n <= last {
+			t.Fatalf("nonce %d not above %d", n, last)
+		}
+		last = n
+	}
+
+	// A nonce far ahead of the clock, as after the clock steps backwards
+	future := uint64(time.Now().Add(time.Hour).UnixMicro())
+	os.WriteFile(path, []byte(fmt.Sprint(future)), 0600)
+
+	restarted, err := adapters.NewNonceGenerator(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := restarted.Next(); n != future+1 {
+		t.Errorf("nonce after restart = %d, want %d", n, future+1)
+	}
+}
+
+// fakeKraken fakes the REST endpoints the adapter uses, checks the
+// signature of every private call and records the forms posted.
+type fakeKraken struct {
+	*httptest.Server
+	mu    sync.Mutex
+	forms map[string]url.Values
+}
+
+func newFakeKraken(secret []byte, private map[string]string) *fakeKraken {
+	fk := &fakeKraken{forms: make(map[string]url.Values)}
+	fk.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/0/public/AssetPairs" {
+			fmt.Fprint(w, `{"error":[],"result":{"XXBTZUSD":{"altname":"XBTUSD","wsname":"XBT/USD",
+				"pair_decimals":1,"lot_decimals":8,"ordermin":"0.0001","costmin":"0.5","tick_size":"0.1"}}}`)
+			return
+		}
+
+		method := strings.TrimPrefix(r.URL.Path, "/0/private/")
+		body, _ := io.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+
+		want := adapters.SignKrakenRequest(secret, r.URL.Path, form.Get("nonce"), string(body))
+		if r.Header.Get("API-Sign") != want || r.Header.Get("API-Key") != "key" {
+			fmt.Fprint(w, `{"error":["EAPI:Invalid signature"]}`)
+			return
+		}
+
+		fk.mu.Lock()
+		fk.forms[method] = form
+		fk.mu.Unlock()
+
+		response, ok := private[method]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, response)
+	}))
+	return fk
+}
+
+func (fk *fakeKraken) form(method string) url.Values {
+	fk.mu.Lock()
+	defer fk.mu.Unlock()
+	return fk.forms[method]
+}
+
+func newTestKraken(t *testing.T, baseURL string) *adapters.KrakenAdapter {
+	k, err := adapters.NewKrakenAdapter(zap.NewNop(), adapters.KrakenConfig{
+		APIKey:    "key",
+		APISecret: krakenDocSecret,
+		NonceFile: filepath.Join(t.TempDir(), "nonce"),
+		BaseURL:   baseURL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestKrakenPlaceOrderRoundsToPairPrecision(t *testing.T) {
+	secret, _ := base64.StdEncoding.DecodeString(krakenDocSecret)
+	server := newFakeKraken(secret, map[string]string{
+		"AddOrder": `{"error":[],"result":{"txid":["OUF4EM-FRGI2-MQMWZD"],"descr":{"order":"buy 0.12345678 XBTUSD @ limit 37500.2"}}}`,
+	})
+	defer server.Close()
+
+	k := newTestKraken(t, server.URL)
+	order, err := k.PlaceOrder(context.Background(), &types.Order{
+		Symbol:        "BTC/USD",
+		Side:          types.OrderSideBuy,
+		Type:          types.OrderTypeLimit,
+		Quantity:      decimal.RequireFromString("0.123456789"),
+		Price:         decimal.RequireFromString("37500.26"),
+		ClientOrderID: "atlas-1",
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+
+	if order.ID != "OUF4EM-FRGI2-MQMWZD" || order.Status != types.OrderStatusOpen {
+		t.Errorf("order = %s %s, want OUF4EM-FRGI2-MQMWZD open", order.ID, order.Status)
+	}
+
+	form := server.form("AddOrder")
+	want := map[string]string{
+		"pair":      "XBTUSD",
+		"type":      "buy",
+		"ordertype": "limit",
+		"volume":    "0.12345678",
+		"price":     "37500.2",
+		"cl_ord_id": "atlas-1",
+	}
+	for field, value := range want {
+		if got := form.Get(field); got != value {
+			t.Errorf("%s = %q, want %q", field, got, value)
+		}
+	}
+
+	// Loading the pairs registers their precision for sizing
+	if got := types.RoundQuantity("kraken", "BTC/USD", decimal.RequireFromString("0.123456789")); got.String() != "0.12345678" {
+		t.Errorf("RoundQuantity = %s, want 0.12345678", got)
+	}
+	if got := types.RoundPrice("kraken", "BTCUSD", types.OrderSideSell, decimal.RequireFromString("37500.26")); got.String() != "37500.3" {
+		t.Errorf("RoundPrice = %s, want 37500.3", got)
+	}
+
+	// Below the pair's minimum volume
+	_, err = k.PlaceOrder(context.Background(), &types.Order{
+		Symbol:   "BTC/USD",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeMarket,
+		Quantity: decimal.RequireFromString("0.00001"),
+	})
+	if err == nil {
+		t.Error("order below ordermin accepted")
+	}
+}
+
+func TestKrakenPostOnlyOrderSetsFlag(t *testing.T) {
+	secret, _ := base64.StdEncoding.DecodeString(krakenDocSecret)
+	server := newFakeKraken(secret, map[string]string{
+		"AddOrder": `{"error":[],"result":{"txid":["OUF4EM-FRGI2-MQMWZD"]}}`,
+	})
+	defer server.Close()
+
+	k := newTestKraken(t, server.URL)
+	order := &types.Order{
+		Symbol:   "BTC/USD",
+		Side:     types.OrderSideSell,
+		Type:     types.OrderTypeLimit,
+		Quantity: decimal.RequireFromString("0.1"),
+		Price:    decimal.RequireFromString("37600"),
+		PostOnly: true,
+	}
+	if _, err := k.PlaceOrder(context.Background(), order); err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	if got := server.form("AddOrder").Get("oflags"); got != "post" {
+		t.Errorf("oflags = %q, want post", got)
+	}
+
+	order.PostOnly = false
+	if _, err := k.PlaceOrder(context.Background(), order); err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	if got := server.form("AddOrder").Get("oflags"); got != "" {
+		t.Errorf("oflags = %q for a plain limit order", got)
+	}
+}
+
+func TestKrakenOrderAndBalanceMapping(t *testing.T) {
+	secret, _ := base64.StdEncoding.DecodeString(krakenDocSecret)
+	server := newFakeKraken(secret, map[string]string{
+		"QueryOrders": `{"error":[],"result":{"OQCLML-BW3P3-BUCMWZ":{"status":"open","opentm":1616492376.5,"closetm":0,
+			"descr":{"pair":"XBTUSD","type":"sell","ordertype":"stop-loss-limit","price":"30000.0","price2":"29900.0"},
+			"vol":"2.00000000","vol_exec":"0.50000000","cost":"15000.0","fee":"24.0","price":"30000.0"}}}`,
+		"Balance":     `{"error":[],"result":{"XXBT":"0.5000000000","XBT.F":"1.0","ZUSD":"100.0000"}}`,
+		"CancelOrder": `{"error":[],"result":{"count":0}}`,
+	})
+	defer server.Close()
+
+	k := newTestKraken(t, server.URL)
+	ctx := context.Background()
+
+	order, err := k.GetOrder(ctx, "OQCLML-BW3P3-BUCMWZ")
+	if err != nil {
+		t.Fatalf("GetOrder: %v", err)
+	}
+	if order.Symbol != "BTC/USD" || order.Side != types.OrderSideSell || order.Type != types.OrderTypeStopLimit {
+		t.Errorf("order = %s %s %s", order.Symbol, order.Side, order.Type)
+	}
+	if order.Status != types.OrderStatusPartiallyFilled {
+		t.Errorf("status = %s, want partially_filled", order.Status)
+	}
+	if !order.StopPrice.Equal(decimal.NewFromInt(30000)) || !order.Price.Equal(decimal.NewFromInt(29900)) {
+		t.Errorf("stop/limit = %s/%s, want 30000/29900", order.StopPrice, order.Price)
+	}
+
+	btc, err := k.GetBalance(ctx, "BTC")
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if !btc.Equal(decimal.RequireFromString("0.5")) {
+		t.Errorf("BTC balance = %s, want 0.5 excluding earn", btc)
+	}
+
+	if err := k.CancelOrder(ctx, "OQCLML-BW3P3-BUCMWZ"); err == nil {
+		t.Error("cancel reporting count 0 succeeded")
+	}
+}
+
+func TestKrakenGetOrderByClientID(t *testing.T) {
+	secret, _ := base64.StdEncoding.DecodeString(krakenDocSecret)
+	server := newFakeKraken(secret, map[string]string{
+		"OpenOrders": `{"error":[],"result":{"open":{}}}`,
+		"ClosedOrders": `{"error":[],"result":{"count":1,"closed":{"OUF4EM-FRGI2-MQMWZD":{"cl_ord_id":"atlas-2","status":"closed",
+			"opentm":1616492376.5,"closetm":1616492377.1,"descr":{"pair":"XBTUSD","type":"buy","ordertype":"market","price":"0","price2":"0"},
+			"vol":"0.10000000","vol_exec":"0.10000000","cost":"3750.0","fee":"6.0","price":"37500.0"}}}}`,
+	})
+	defer server.Close()
+
+	k := newTestKraken(t, server.URL)
+	ctx := context.Background()
+
+	// A market order that filled after its response was lost is closed
+	order, err := k.GetOrderByClientID(ctx, "BTC/USD", "atlas-2")
+	if err != nil {
+		t.Fatalf("GetOrderByClientID: %v", err)
+	}
+	if order.ID != "OUF4EM-FRGI2-MQMWZD" || order.Status != types.OrderStatusFilled {
+		t.Errorf("order = %s %s, want OUF4EM-FRGI2-MQMWZD filled", order.ID, order.Status)
+	}
+	if got := server.form("ClosedOrders").Get("cl_ord_id"); got != "atlas-2" {
+		t.Errorf("cl_ord_id = %q, want atlas-2", got)
+	}
+
+	if _, err := k.GetOrderByClientID(ctx, "BTC/USD", "atlas-3"); !errors.Is(err, adapters.ErrOrderNotFound) {
+		t.Errorf("unknown client order ID: err = %v, want ErrOrderNotFound", err)
+	}
+
+	// A failed query is not a definitive miss
+	down := newFakeKraken(secret, map[string]string{
+		"OpenOrders": `{"error":["EService:Unavailable"]}`,
+	})
+	defer down.Close()
+	if _, err := newTestKraken(t, down.URL).GetOrderByClientID(ctx, "BTC/USD", "atlas-2"); err == nil || errors.Is(err, adapters.ErrOrderNotFound) {
+		t.Errorf("unavailable venue: err = %v, want a lookup failure", err)
+	}
+}
+
+func TestKrakenAPIErrorsSurface(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"error":["EAPI:Invalid nonce"]}`)
+	}))
+	defer server.Close()
+
+	k := newTestKraken(t, server.URL)
+	_, err := k.GetBalance(context.Background(), "BTC")
+	if err == nil || !strings.Contains(err.Error(), "EAPI:Invalid nonce") {
+		t.Errorf("err = %v, want the Kraken error", err)
+	}
+}
+
+func TestKrakenPingReportsMaintenance(t *testing.T) {
+	status := "online"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"error":[],"result":{"status":%q}}`, status)
+	}))
+	defer server.Close()
+
+	k := newTestKraken(t, server.URL)
+	if err := k.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping while online: %v", err)
+	}
+
+	status = "maintenance"
+	if err := k.Ping(context.Background()); err == nil || !strings.Contains(err.Error(), "maintenance") {
+		t.Errorf("err = %v, want the maintenance status", err)
+	}
+}
+
+func TestKrakenOrderBookStream(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var sub struct {
+			Method string `json:"method"`
+			Params struct {
+				Channel string   `json:"channel"`
+				Symbol  []string `json:"symbol"`
+			} `json:"params"`
+		}
+		if err := conn.ReadJSON(&sub); err != nil || sub.Params.Channel != "book" || sub.Params.Symbol[0] != "BTC/USD" {
+			t.Errorf("subscription = %+v, %v", sub, err)
+			return
+		}
+
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"method":"subscribe","success":true}`))
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"channel":"book","type":"snapshot","data":[{"symbol":"BTC/USD",
+			"bids":[{"price":100.1,"qty":1},{"price":100.0,"qty":2}],
+			"asks":[{"price":100.2,"qty":1},{"price":100.3,"qty":3}]}]}`))
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"channel":"book","type":"update","data":[{"symbol":"BTC/USD",
+			"bids":[{"price":100.1,"qty":0},{"price":100.05,"qty":4}],
+			"asks":[{"price":100.15,"qty":2}],"timestamp":"2024-01-01T00:00:00.000000Z"}]}`))
+
+		// Hold the connection until the client goes away
+		conn.ReadMessage()
+	}))
+	defer server.Close()
+
+	k, err := adapters.NewKrakenAdapter(zap.NewNop(), adapters.KrakenConfig{
+		APIKey:    "key",
+		APISecret: krakenDocSecret,
+		WSURL:     "ws" + strings.TrimPrefix(server.URL, "http"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer k.Disconnect()
+
+	books := make(chan *types.OrderBook, 4)
+	err = k.SubscribeToOrderBook(context.Background(), []string{"XBTUSD"}, func(symbol string, ob *types.OrderBook) {
+		books <- ob
+	})
+	if err != nil {
+		t.Fatalf("SubscribeToOrderBook: %v", err)
+	}
+
+	var book *types.OrderBook
+	for i := 0; i < 2; i++ {
+		select {
+		case book = <-books:
+		case <-time.After(2 * time.Second):
+			t.Fatal("book not delivered")
+		}
+	}
+
+	wantBids := []string{"100.05", "100"}
+	wantAsks := []string{"100.15", "100.2", "100.3"}
+	if book.Symbol != "BTC/USD" || len(book.Bids) != len(wantBids) || len(book.Asks) != len(wantAsks) {
+		t.Fatalf("book = %+v", book)
+	}
+	for i, p := range wantBids {
+		if !book.Bids[i].Price.Equal(decimal.RequireFromString(p)) {
+			t.Errorf("bid %d = %s, want %s", i, book.Bids[i].Price, p)
+		}
+	}
+	for i, p := range wantAsks {
+		if !book.Asks[i].Price.Equal(decimal.RequireFromString(p)) {
+			t.Errorf("ask %d = %s, want %s", i, book.Asks[i].Price, p)
+		}
+	}
+}
@@ -0,0 +1,390 @@
+// Package adapters provides Kraken WebSocket v2 ticker and book streams.
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// krakenSubscription is one channel subscription, resent on reconnect.
+type krakenSubscription struct {
+	Channel string   `json:"channel"`
+	Symbol  []string `json:"symbol"`
+	Depth   int      `json:"depth,omitempty"`
+}
+
+// krakenWSMessage is the envelope of WebSocket v2 messages.
+type krakenWSMessage struct {
+	Channel string          `json:"channel"`
+	Type    string          `json:"type"` // "snapshot" or "update"
+	Data    json.RawMessage `json:"data"`
+
+	// Set on method responses
+	Method  string `json:"method"`
+	Success *bool  `json:"success"`
+	Error   string `json:"error"`
+}
+
+// krakenWSTicker is one ticker channel entry.
+type krakenWSTicker struct {
+	Symbol string          `json:"symbol"`
+	Bid    decimal.Decimal `json:"bid"`
+	Ask    decimal.Decimal `json:"ask"`
+	Last   decimal.Decimal `json:"last"`
+	Volume decimal.Decimal `json:"volume"`
+}
+
+// krakenWSBook is one book channel entry. In updates a zero quantity
+// removes the level.
+type krakenWSBook struct {
+	Symbol string `json:"symbol"`
+	Bids   []struct {
+		Price decimal.Decimal `json:"price"`
+		Qty   decimal.Decimal `json:"qty"`
+	} `json:"bids"`
+	Asks []struct {
+		Price decimal.Decimal `json:"price"`
+		Qty   decimal.Decimal `json:"qty"`
+	} `json:"asks"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// defaultKrakenBookDepth is used when KrakenConfig.BookDepth is unset.
+const defaultKrakenBookDepth = 10
+
+// SetOnResync sets the callback invoked after the WebSocket reconnects.
+func (k *KrakenAdapter) SetOnResync(callback func(event WSResyncEvent)) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.onResync = callback
+}
+
+// SubscribeToTicker subscribes to ticker updates via WebSocket. Tickers
+// carry BASE/QUOTE symbols.
+func (k *KrakenAdapter) SubscribeToTicker(ctx context.Context, symbols []string, callback func(*Ticker)) error {
+	k.mu.Lock()
+	k.onTicker = callback
+	k.mu.Unlock()
+
+	return k.subscribe(ctx, krakenSubscription{Channel: "ticker", Symbol: krakenWSSymbols(symbols)})
+}
+
+// SubscribeToOrderBook subscribes to order book updates via WebSocket. The
+// callback receives the full book, kept up to date from Kraken's
+// incremental updates, under its BASE/QUOTE symbol.
+func (k *KrakenAdapter) SubscribeToOrderBook(ctx context.Context, symbols []string, callback func(symbol string, ob *types.OrderBook)) error {
+	k.mu.Lock()
+	k.onOrderBook = callback
+	k.mu.Unlock()
+
+	return k.subscribe(ctx, krakenSubscription{
+		Channel: "book",
+		Symbol:  krakenWSSymbols(symbols),
+		Depth:   k.bookDepth,
+	})
+}
+
+func krakenWSSymbols(symbols []string) []string {
+	wsSymbols := make([]string, len(symbols))
+	for i, s := range symbols {
+		wsSymbols[i] = krakenWSSymbol(s)
+	}
+	return wsSymbols
+}
+
+// subscribe records a subscription and sends it. Without a connection it
+// connects and sends every recorded subscription.
+func (k *KrakenAdapter) subscribe(ctx context.Context, sub krakenSubscription) error {
+	k.mu.Lock()
+	k.subscriptions = append(k.subscriptions, sub)
+	k.wsClosed = false
+	if k.wsConn != nil {
+		err := k.writeSubscription(k.wsConn, sub)
+		k.mu.Unlock()
+		return err
+	}
+	k.mu.Unlock()
+
+	conn, err := k.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.wsConn != nil {
+		// Connected meanwhile by another subscriber or a reconnect, which
+		// sent this subscription along with the rest
+		conn.Close()
+		return nil
+	}
+	for _, s := range k.subscriptions {
+		if err := k.writeSubscription(conn, s); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+	k.wsConn = conn
+	k.books = make(map[string]*types.OrderBook)
+
+	go k.readWebSocket(ctx, conn)
+	return nil
+}
+
+// writeSubscription sends a subscribe request. Callers hold k.mu, which
+// also serializes WebSocket writes.
+func (k *KrakenAdapter) writeSubscription(conn *websocket.Conn, sub krakenSubscription) error {
+	request := map[string]interface{}{
+		"method": "subscribe",
+		"params": sub,
+	}
+	if err := conn.WriteJSON(request); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", sub.Channel, err)
+	}
+	return nil
+}
+
+// dial opens a WebSocket connection. Kraken sends heartbeats every second
+// while subscribed, so any message counts as proof of life.
+func (k *KrakenAdapter) dial(ctx context.Context) (*websocket.Conn, error) {
+	dialer := websocket.Dialer{
+		HandshakeTimeout: 10 * time.Second,
+	}
+
+	conn, _, err := dialer.DialContext(ctx, k.wsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to WebSocket: %w", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+	return conn, nil
+}
+
+// readWebSocket reads messages from conn until it fails, then reconnects
+// unless the connection was closed or replaced.
+func (k *KrakenAdapter) readWebSocket(ctx context.Context, conn *websocket.Conn) {
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			k.mu.Lock()
+			current := k.wsConn == conn
+			if current {
+				k.wsConn = nil
+			}
+			k.mu.Unlock()
+			conn.Close()
+
+			if !current || ctx.Err() != nil {
+				return
+			}
+
+			k.logger.Error("WebSocket read error, reconnecting", zap.Error(err))
+			k.reconnect(ctx)
+			return
+		}
+
+		conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+		k.handleWebSocketMessage(message)
+	}
+}
+
+// reconnect re-dials with capped exponential backoff and jitter, restores
+// every subscription, then emits a resync event. Books are rebuilt from the
+// fresh snapshots.
+func (k *KrakenAdapter) reconnect(ctx context.Context) {
+	disconnectedAt := time.Now()
+
+	for attempt := 0; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wsBackoff(attempt)):
+		}
+
+		k.mu.RLock()
+		closed := k.wsClosed
+		k.mu.RUnlock()
+		if closed {
+			return
+		}
+
+		conn, err := k.dial(ctx)
+		if err != nil {
+			k.logger.Warn("WebSocket reconnect failed",
+				zap.Int("attempt", attempt+1),
+				zap.Error(err))
+			continue
+		}
+
+		k.mu.Lock()
+		if k.wsClosed || k.wsConn != nil {
+			// Disconnected or resubscribed while we were dialing
+			k.mu.Unlock()
+			conn.Close()
+			return
+		}
+		k.wsConn = conn
+		k.books = make(map[string]*types.OrderBook)
+		var channels []string
+		for _, sub := range k.subscriptions {
+			if err := k.writeSubscription(conn, sub); err != nil {
+				k.logger.Warn("Failed to restore subscription", zap.Error(err))
+			}
+			channels = append(channels, sub.Channel)
+		}
+		onResync := k.onResync
+		k.mu.Unlock()
+
+		go k.readWebSocket(ctx, conn)
+
+		event := WSResyncEvent{
+			Streams:        channels,
+			DisconnectedAt: disconnectedAt,
+			ReconnectedAt:  time.Now(),
+			Attempts:       attempt + 1,
+		}
+		k.logger.Info("WebSocket reconnected",
+			zap.Int("attempts", event.Attempts),
+			zap.Duration("gap", event.ReconnectedAt.Sub(disconnectedAt)))
+		if onResync != nil {
+			onResync(event)
+		}
+		return
+	}
+}
+
+// handleWebSocketMessage processes a WebSocket message.
+func (k *KrakenAdapter) handleWebSocketMessage(message []byte) {
+	var msg krakenWSMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		k.logger.Debug("Ignoring unparseable WebSocket message", zap.Error(err))
+		return
+	}
+
+	if msg.Method != "" {
+		if msg.Success != nil && !*msg.Success {
+			k.logger.Error("Kraken WebSocket request failed",
+				zap.String("method", msg.Method),
+				zap.String("error", msg.Error))
+		}
+		return
+	}
+
+	switch msg.Channel {
+	case "ticker":
+		var tickers []krakenWSTicker
+		if err := json.Unmarshal(msg.Data, &tickers); err != nil {
+			k.logger.Warn("Bad ticker message", zap.Error(err))
+			return
+		}
+		k.mu.RLock()
+		onTicker := k.onTicker
+		k.mu.RUnlock()
+		if onTicker == nil {
+			return
+		}
+		for _, t := range tickers {
+			onTicker(&Ticker{
+				Symbol:    KrakenSymbol(t.Symbol),
+				LastPrice: t.Last,
+				BidPrice:  t.Bid,
+				AskPrice:  t.Ask,
+				Volume:    t.Volume,
+				Timestamp: time.Now(),
+			})
+		}
+	case "book":
+		var books []krakenWSBook
+		if err := json.Unmarshal(msg.Data, &books); err != nil {
+			k.logger.Warn("Bad book message", zap.Error(err))
+			return
+		}
+		for i := range books {
+			k.applyBook(&books[i], msg.Type == "snapshot")
+		}
+	}
+}
+
+// applyBook applies a snapshot or update to the local book and passes a
+// copy to the callback.
+func (k *KrakenAdapter) applyBook(update *krakenWSBook, snapshot bool) {
+	symbol := KrakenSymbol(update.Symbol)
+
+	k.mu.Lock()
+	book, ok := k.books[symbol]
+	if snapshot || !ok {
+		if !snapshot {
+			// Updates before the snapshot can't be applied
+			k.mu.Unlock()
+			return
+		}
+		book = &types.OrderBook{Symbol: symbol}
+		k.books[symbol] = book
+	}
+
+	for _, level := range update.Bids {
+		book.Bids = setBookLevel(book.Bids, level.Price, level.Qty, true)
+	}
+	for _, level := range update.Asks {
+		book.Asks = setBookLevel(book.Asks, level.Price, level.Qty, false)
+	}
+	if len(book.Bids) > k.bookDepth {
+		book.Bids = book.Bids[:k.bookDepth]
+	}
+	if len(book.Asks) > k.bookDepth {
+		book.Asks = book.Asks[:k.bookDepth]
+	}
+	book.Timestamp = update.Timestamp
+	if book.Timestamp.IsZero() {
+		book.Timestamp = time.Now()
+	}
+
+	snapshotCopy := &types.OrderBook{
+		Symbol:    book.Symbol,
+		Bids:      append([]types.OrderBookLevel(nil), book.Bids...),
+		Asks:      append([]types.OrderBookLevel(nil), book.Asks...),
+		Timestamp: book.Timestamp,
+	}
+	onOrderBook := k.onOrderBook
+	k.mu.Unlock()
+
+	if onOrderBook != nil {
+		onOrderBook(symbol, snapshotCopy)
+	}
+}
+
+// setBookLevel inserts, replaces or (for a zero quantity) removes a price
+// level, keeping bids descending and asks ascending.
+func setBookLevel(levels []types.OrderBookLevel, price, qty decimal.Decimal, bids bool) []types.OrderBookLevel {
+	i := sort.Search(len(levels), func(i int) bool {
+		if bids {
+			return levels[i].Price.LessThanOrEqual(price)
+		}
+		return levels[i].Price.GreaterThanOrEqual(price)
+	})
+
+	exists := i < len(levels) && levels[i].Price.Equal(price)
+	switch {
+	case qty.IsZero() && exists:
+		return append(levels[:i], levels[i+1:]...)
+	case qty.IsZero():
+		return levels
+	case exists:
+		levels[i].Quantity = qty
+		return levels
+	default:
+		levels = append(levels, types.OrderBookLevel{})
+		copy(levels[i+1:], levels[i:])
+		levels[i] = types.OrderBookLevel{Price: price, Quantity: qty}
+		return levels
+	}
+}
@@ -0,0 +1,83 @@
+// Package adapters provides a persisted, strictly increasing nonce for
+// exchanges that sign requests with one.
+package adapters
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NonceGenerator issues strictly increasing nonces. They track the clock in
+// microseconds, and with a file the last nonce is persisted so a restart or a
+// clock stepping backwards can never reuse or undercut one the exchange has
+// already seen.
+type NonceGenerator struct {
+	mu   sync.Mutex
+	path string
+	last uint64
+}
+
+// NewNonceGenerator creates a generator resuming after the nonce stored at
+// path. An empty path keeps nonces in memory only.
+func NewNonceGenerator(path string) (*NonceGenerator, error) {
+	g := &NonceGenerator{path: path}
+	if path == "" {
+		return g, nil
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return g, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read nonce file: %w", err)
+	}
+
+	last, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse nonce file %s: %w", path, err)
+	}
+	g.last = last
+	return g, nil
+}
+
+// Next returns a nonce greater than every nonce issued before, persisting it
+// before returning so it is never reissued.
+func (g *NonceGenerator) Next() (uint64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	nonce := uint64(time.Now().UnixMicro())
+	if nonce <= g.last {
+		nonce = g.last + 1
+	}
+
+	if g.path != "" {
+		if err := g.persist(nonce); err != nil {
+			return 0, err
+		}
+	}
+	g.last = nonce
+	return nonce, nil
+}
+
+// persist atomically replaces the nonce file. Callers hold g.mu.
+func (g *NonceGenerator) persist(nonce uint64) error {
+	if err := os.MkdirAll(filepath.Dir(g.path), 0700); err != nil {
+		return fmt.Errorf("failed to create nonce directory: %w", err)
+	}
+
+	tmp := g.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatUint(nonce, 10)), 0600); err != nil {
+		return fmt.Errorf("failed to write nonce file: %w", err)
+	}
+	if err := os.Rename(tmp, g.path); err != nil {
+		return fmt.Errorf("failed to replace nonce file: %w", err)
+	}
+	return nil
+}
@@ -16,6 +16,9 @@ import (
 )
 
 // SolanaAdapter implements the exchange adapter for Solana DEXs via Jupiter.
+//
+// Deprecated: SolanaAdapter only quotes swaps. Use JupiterAdapter, which
+// signs, submits and confirms them.
 type SolanaAdapter struct {
 	logger       *zap.Logger
 	jupiterURL   string
@@ -0,0 +1,164 @@
+// Package adapters provides Solana key handling and transaction signing.
+package adapters
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Base58Encode encodes data with the Bitcoin alphabet Solana uses for keys
+// and signatures.
+func Base58Encode(data []byte) string {
+	n := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// Base58Decode decodes a Bitcoin-alphabet base58 string.
+func Base58Decode(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(base58Alphabet, s[i])
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", s[i])
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// ParseSolanaKey parses a wallet keypair exported as base58 or as the JSON
+// byte array the Solana CLI writes.
+func ParseSolanaKey(key string) (ed25519.PrivateKey, error) {
+	key = strings.TrimSpace(key)
+
+	var raw []byte
+	if strings.HasPrefix(key, "[") {
+		var ints []int
+		if err := json.Unmarshal([]byte(key), &ints); err != nil {
+			return nil, fmt.Errorf("failed to parse keypair bytes: %w", err)
+		}
+		raw = make([]byte, len(ints))
+		for i, v := range ints {
+			if v < 0 || v > 255 {
+				return nil, fmt.Errorf("keypair byte %d out of range", v)
+			}
+			raw[i] = byte(v)
+		}
+	} else {
+		decoded, err := Base58Decode(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode keypair: %w", err)
+		}
+		raw = decoded
+	}
+
+	if len(raw) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("keypair is %d bytes, want %d", len(raw), ed25519.PrivateKeySize)
+	}
+	private := ed25519.NewKeyFromSeed(raw[:ed25519.SeedSize])
+	if !bytes.Equal(private[ed25519.SeedSize:], raw[ed25519.SeedSize:]) {
+		return nil, fmt.Errorf("keypair public key does not match its seed")
+	}
+	return private, nil
+}
+
+// SignSolanaTransaction signs a serialized legacy or versioned transaction
+// whose signature slots are empty, placing the signature in the slot of the
+// key's account. It returns the signed transaction and its signature, which
+// is the first signature in base58.
+func SignSolanaTransaction(tx []byte, key ed25519.PrivateKey) ([]byte, string, error) {
+	numSignatures, offset, err := readCompactU16(tx, 0)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read signature count: %w", err)
+	}
+	sigStart := offset
+	msgStart := sigStart + numSignatures*ed25519.SignatureSize
+	if numSignatures == 0 || msgStart > len(tx) {
+		return nil, "", fmt.Errorf("transaction has %d signature slots for %d bytes", numSignatures, len(tx))
+	}
+	message := tx[msgStart:]
+
+	// Versioned messages start with a byte whose high bit is set
+	header := 0
+	if len(message) > 0 && message[0]&0x80 != 0 {
+		header = 1
+	}
+	if len(message) < header+3 {
+		return nil, "", fmt.Errorf("transaction message is truncated")
+	}
+	requiredSignatures := int(message[header])
+
+	numKeys, keysStart, err := readCompactU16(message, header+3)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read account count: %w", err)
+	}
+	if requiredSignatures > numKeys || requiredSignatures > numSignatures || keysStart+numKeys*32 > len(message) {
+		return nil, "", fmt.Errorf("transaction message is truncated")
+	}
+
+	public := key.Public().(ed25519.PublicKey)
+	slot := -1
+	for i := 0; i < requiredSignatures; i++ {
+		if bytes.Equal(message[keysStart+i*32:keysStart+(i+1)*32], public) {
+			slot = i
+			break
+		}
+	}
+	if slot < 0 {
+		return nil, "", fmt.Errorf("wallet %s is not a signer of the transaction", Base58Encode(public))
+	}
+
+	signed := append([]byte(nil), tx...)
+	copy(signed[sigStart+slot*ed25519.SignatureSize:], ed25519.Sign(key, message))
+
+	return signed, Base58Encode(signed[sigStart : sigStart+ed25519.SignatureSize]), nil
+}
+
+// readCompactU16 reads Solana's variable-length u16 at offset, returning the
+// value and the offset after it.
+func readCompactU16(data []byte, offset int) (int, int, error) {
+	value := 0
+	for i := 0; i < 3; i++ {
+		if offset >= len(data) {
+			return 0, 0, fmt.Errorf("unexpected end of data")
+		}
+		b := data[offset]
+		offset++
+		value |= int(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return value, offset, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("compact-u16 longer than 3 bytes")
+}
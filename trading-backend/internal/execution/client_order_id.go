@@ -0,0 +1,18 @@
+// Package execution provides idempotent client order IDs.
+package execution
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// ClientOrderID returns the client order ID sent with an order: the first 16
+// bytes of the SHA-256 of its ID, as 32 lowercase hex digits. It is derived
+// only from the ID of the logical order, so every retry of a submission
+// sends the same client order ID and the venue can recognize it as one
+// order. 32 hex digits are within Binance's 36-character limit and are
+// Kraken's short UUID format.
+func ClientOrderID(orderID string) string {
+	sum := sha256.Sum256([]byte(orderID))
+	return hex.EncodeToString(sum[:16])
+}
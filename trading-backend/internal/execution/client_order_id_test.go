@@ -0,0 +1,156 @@
+package execution_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"go.uber.org/zap"
+)
+
+// timeoutVenue places market orders but times out responding to the first,
+// like a venue whose reply was lost after it accepted the order.
+type timeoutVenue struct {
+	*passiveVenue
+	timedOut  bool
+	byClient  map[string]*types.Order
+	lookupErr error // Returned by every lookup when set
+	lookups   int
+}
+
+func (v *timeoutVenue) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	placed, err := v.passiveVenue.PlaceOrder(ctx, order)
+	if err != nil || order.Type != types.OrderTypeMarket {
+		return placed, err
+	}
+	v.byClient[order.ClientOrderID] = placed
+
+	if !v.timedOut {
+		v.timedOut = true
+		return nil, context.DeadlineExceeded
+	}
+	return placed, nil
+}
+
+func (v *timeoutVenue) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*types.Order, error) {
+	v.lookups++
+	if v.lookupErr != nil {
+		return nil, v.lookupErr
+	}
+	if order, ok := v.byClient[clientOrderID]; ok {
+		return order, nil
+	}
+	return nil, fmt.Errorf("%s: %w", clientOrderID, adapters.ErrOrderNotFound)
+}
+
+func TestRetryAfterTimeoutFindsThePlacedOrder(t *testing.T) {
+	v := &timeoutVenue{passiveVenue: newPassiveVenue(100, 101, 0), byClient: make(map[string]*types.Order)}
+
+	config := execution.DefaultExecutorConfig()
+	config.PaperTrading = false
+	config.RetryDelay = time.Millisecond
+	e := execution.NewExecutor(zap.NewNop(), config)
+	e.AddAdapter(v)
+	e.SetDefaultAdapter(v)
+
+	opts := execution.LimitOptions{
+		MaxReprices:     1,
+		RepriceInterval: time.Millisecond,
+		CrossAfter:      5 * time.Millisecond,
+	}
+	result, err := e.ExecuteLimit(context.Background(), limitBuy(1), opts)
+	if err != nil {
+		t.Fatalf("ExecuteLimit failed: %v", err)
+	}
+
+	// Two passive orders, then a single cross despite the timeout
+	if len(v.placed) != 3 {
+		t.Fatalf("Expected the retry to find the cross instead of placing it again, got %d orders", len(v.placed))
+	}
+	cross := v.placed[2]
+	if cross.ClientOrderID != execution.ClientOrderID(cross.ID) || len(cross.ClientOrderID) != 32 {
+		t.Errorf("Expected a 32-digit client order ID derived from %s, got %q", cross.ID, cross.ClientOrderID)
+	}
+	if v.placed[0].ClientOrderID == cross.ClientOrderID {
+		t.Error("Expected the cross to have its own client order ID")
+	}
+	if result.Status != string(types.OrderStatusFilled) || !result.FilledQty.Equal(cross.Quantity) {
+		t.Errorf("Expected the found cross to fill the order, got %s %s", result.Status, result.FilledQty)
+	}
+
+	if execution.ClientOrderID("ord-1") != execution.ClientOrderID("ord-1") || execution.ClientOrderID("ord-1") == execution.ClientOrderID("ord-2") {
+		t.Error("Expected client order IDs to be stable per order and distinct across orders")
+	}
+}
+
+// newTimeoutExecutor creates a live executor on v that retries quickly.
+func newTimeoutExecutor(v *timeoutVenue, retryDelay time.Duration) *execution.Executor {
+	config := execution.DefaultExecutorConfig()
+	config.PaperTrading = false
+	config.RetryDelay = retryDelay
+	e := execution.NewExecutor(zap.NewNop(), config)
+	e.AddAdapter(v)
+	e.SetDefaultAdapter(v)
+	return e
+}
+
+func TestRetryNeverReplacesAfterFailedLookup(t *testing.T) {
+	v := &timeoutVenue{
+		passiveVenue: newPassiveVenue(100, 101, 0),
+		byClient:     make(map[string]*types.Order),
+		lookupErr:    fmt.Errorf("get order failed with status 503"),
+	}
+	e := newTimeoutExecutor(v, time.Millisecond)
+
+	opts := execution.LimitOptions{
+		MaxReprices:     1,
+		RepriceInterval: time.Millisecond,
+		CrossAfter:      5 * time.Millisecond,
+	}
+	if _, err := e.ExecuteLimit(context.Background(), limitBuy(1), opts); err == nil {
+		t.Fatal("Expected an error when the timed-out cross cannot be looked up")
+	}
+
+	// The cross timed out but filled; placing it again would fill twice
+	if len(v.placed) != 3 {
+		t.Errorf("Expected the timed-out cross placed once, got %d orders", len(v.placed))
+	}
+	if v.lookups != execution.DefaultExecutorConfig().RetryAttempts-1 {
+		t.Errorf("Expected the lookup retried within the retry budget, got %d lookups", v.lookups)
+	}
+}
+
+func TestRetryStopsWhenCancelled(t *testing.T) {
+	v := &timeoutVenue{passiveVenue: newPassiveVenue(100, 101, 0), byClient: make(map[string]*types.Order)}
+	e := newTimeoutExecutor(v, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	opts := execution.LimitOptions{
+		MaxReprices:     1,
+		RepriceInterval: time.Millisecond,
+		CrossAfter:      5 * time.Millisecond,
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := e.ExecuteLimit(ctx, limitBuy(1), opts)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Expected the cancelled execution to fail")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected cancellation to end the retry backoff")
+	}
+	if v.lookups != 0 {
+		t.Errorf("Expected no lookup after cancellation, got %d", v.lookups)
+	}
+}
@@ -3,10 +3,14 @@ package execution
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/internal/fees"
 	"github.com/atlas-desktop/trading-backend/pkg/types"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
@@ -16,9 +20,14 @@ import (
 type Executor struct {
 	logger     *zap.Logger
 	adapters   map[string]ExchangeAdapter
+	routes     map[string]ExchangeAdapter // symbol -> adapter
+	fallback   ExchangeAdapter            // used when no route matches
 	orderMgr   *OrderManager
 	riskMgr    *RiskManager
 	slippage   SlippageCalculator
+	router     *Router                    // consulted when several venues can fill
+	fees       *fees.FeeModel             // charges paper fills and estimates unreported live fees
+	calibrator *SlippageCalculator        // refit to live fills when set
 	config     ExecutorConfig
 	
 	// State
@@ -49,6 +58,9 @@ type ExecutorConfig struct {
 	
 	// Paper trading
 	PaperTrading       bool            `json:"paperTrading"`
+	SimLatency         time.Duration   `json:"simLatency"`      // Delay before a paper order fills, during which the price moves
+	SimPartialFills    bool            `json:"simPartialFills"` // Fill paper orders against order book depth and queue position
+	SimBookDepth       int             `json:"simBookDepth"`    // Book levels paper orders are filled against
 }
 
 // DefaultExecutorConfig returns sensible defaults.
@@ -64,6 +76,7 @@ func DefaultExecutorConfig() ExecutorConfig {
 		MaxOrderSize:        decimal.NewFromInt(10000),
 		MinOrderSize:        decimal.NewFromInt(10),
 		PaperTrading:        true, // Safe default
+		SimBookDepth:        20,
 	}
 }
 
@@ -78,27 +91,9 @@ type ExecutorMetrics struct {
 	LastOrderTime     time.Time       `json:"lastOrderTime"`
 }
 
-// ExchangeAdapter defines the interface for exchange integrations.
-type ExchangeAdapter interface {
-	Name() string
-	Connect(ctx context.Context) error
-	Disconnect() error
-	IsConnected() bool
-	
-	// Market data
-	GetPrice(ctx context.Context, symbol string) (decimal.Decimal, error)
-	GetOrderBook(ctx context.Context, symbol string, depth int) (*OrderBook, error)
-	
-	// Trading
-	PlaceOrder(ctx context.Context, order *types.Order) (*OrderResult, error)
-	CancelOrder(ctx context.Context, orderID string) error
-	GetOrder(ctx context.Context, orderID string) (*types.Order, error)
-	GetOpenOrders(ctx context.Context, symbol string) ([]*types.Order, error)
-	
-	// Account
-	GetBalance(ctx context.Context, asset string) (decimal.Decimal, error)
-	GetPositions(ctx context.Context) ([]*types.Position, error)
-}
+// ExchangeAdapter is the common exchange interface, shared with the
+// adapters package so any registered adapter can be routed to.
+type ExchangeAdapter = adapters.ExchangeAdapter
 
 // OrderBook represents an exchange order book.
 type OrderBook struct {
@@ -114,22 +109,6 @@ type OrderBookLevel struct {
 	Amount decimal.Decimal `json:"amount"`
 }
 
-// OrderResult contains the result of an order placement.
-type OrderResult struct {
-	OrderID       string          `json:"orderId"`
-	ClientOrderID string          `json:"clientOrderId"`
-	Symbol        string          `json:"symbol"`
-	Side          string          `json:"side"`
-	Type          string          `json:"type"`
-	Status        string          `json:"status"`
-	Price         decimal.Decimal `json:"price"`
-	Quantity      decimal.Decimal `json:"quantity"`
-	FilledQty     decimal.Decimal `json:"filledQty"`
-	AvgPrice      decimal.Decimal `json:"avgPrice"`
-	Commission    decimal.Decimal `json:"commission"`
-	Timestamp     time.Time       `json:"timestamp"`
-}
-
 // SlippageCalculator calculates expected slippage.
 type SlippageCalculator interface {
 	Calculate(orderBook *OrderBook, order *types.Order) decimal.Decimal
@@ -141,9 +120,11 @@ func NewExecutor(logger *zap.Logger, config ExecutorConfig) *Executor {
 	return &Executor{
 		logger:   logger.Named("executor"),
 		adapters: make(map[string]ExchangeAdapter),
+		routes:   make(map[string]ExchangeAdapter),
 		orderMgr: NewOrderManager(logger),
 		riskMgr:  NewRiskManager(logger, DefaultRiskConfig()),
 		slippage: NewSmartSlippageCalculator(),
+		fees:     fees.NewFeeModel(fees.DefaultConfig()),
 		config:   config,
 		isActive: true,
 	}
@@ -158,16 +139,176 @@ func (e *Executor) AddAdapter(adapter ExchangeAdapter) {
 	e.logger.Info("Added exchange adapter", zap.String("exchange", adapter.Name()))
 }
 
-// Connect connects to all exchanges.
-func (e *Executor) Connect(ctx context.Context) error {
+// SetDefaultAdapter sets the adapter used for symbols without a route.
+func (e *Executor) SetDefaultAdapter(adapter ExchangeAdapter) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	
+	e.fallback = adapter
+	e.logger.Info("Set default exchange adapter", zap.String("exchange", adapter.Name()))
+}
+
+// SetSymbolRoutes routes each symbol's orders to the given adapter,
+// replacing any existing routes.
+func (e *Executor) SetSymbolRoutes(routes map[string]ExchangeAdapter) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	
+	e.routes = make(map[string]ExchangeAdapter, len(routes))
+	for symbol, adapter := range routes {
+		e.routes[symbol] = adapter
+	}
+}
+
+// SetRouter sets the smart order router. Orders without an explicit exchange
+// or symbol route are then routed across every added adapter.
+func (e *Executor) SetRouter(router *Router) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	
+	e.router = router
+}
+
+// SetFeeModel sets the fee model charged on paper fills and used to
+// estimate fees on live fills the venue reports none for.
+func (e *Executor) SetFeeModel(model *fees.FeeModel) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	
+	e.fees = model
+}
+
+// FeeScheduleProvider is implemented by adapters that can report the
+// account's fee tier.
+type FeeScheduleProvider interface {
+	FeeSchedule(ctx context.Context) (types.FeeSchedule, error)
+}
+
+// SyncFees replaces the fee model's schedule for each adapter that reports
+// its account's fee tier. Adapters that fail keep their configured schedule
+// and the first error is returned.
+func (e *Executor) SyncFees(ctx context.Context) error {
+	model := e.feeModel()
+	
+	var firstErr error
+	for _, adapter := range e.Adapters() {
+		provider, ok := adapter.(FeeScheduleProvider)
+		if !ok {
+			continue
+		}
+		
+		schedule, err := provider.FeeSchedule(ctx)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to fetch %s fee schedule: %w", adapter.Name(), err)
+			}
+			continue
+		}
+		model.SetSchedule(adapter.Name(), schedule)
+		
+		e.logger.Info("Synced fee schedule",
+			zap.String("exchange", adapter.Name()),
+			zap.String("makerBps", schedule.MakerBps.String()),
+			zap.String("takerBps", schedule.TakerBps.String()))
+	}
+	
+	return firstErr
+}
+
+// feeModel returns the current fee model.
+func (e *Executor) feeModel() *fees.FeeModel {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	
+	return e.fees
+}
+
+// venuesFor returns the adapters the router may choose between for an
+// order, or nil when the order is pinned to one adapter: an explicit
+// exchange or symbol route wins, and so does a lone adapter.
+func (e *Executor) venuesFor(exchange, symbol string) []ExchangeAdapter {
 	e.mu.RLock()
-	adapters := make([]ExchangeAdapter, 0, len(e.adapters))
+	defer e.mu.RUnlock()
+	
+	if e.router == nil || exchange != "" || len(e.adapters) < 2 {
+		return nil
+	}
+	if _, ok := e.routes[symbol]; ok {
+		return nil
+	}
+	
+	venues := make([]ExchangeAdapter, 0, len(e.adapters))
 	for _, adapter := range e.adapters {
-		adapters = append(adapters, adapter)
+		venues = append(venues, adapter)
 	}
-	e.mu.RUnlock()
+	sort.Slice(venues, func(i, j int) bool {
+		return venues[i].Name() < venues[j].Name()
+	})
+	return venues
+}
+
+// adapterFor picks the adapter for an order. An explicit exchange name wins,
+// then the symbol's route, then the default adapter.
+func (e *Executor) adapterFor(exchange, symbol string) (ExchangeAdapter, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	
-	for _, adapter := range adapters {
+	if exchange != "" {
+		adapter, ok := e.adapters[exchange]
+		if !ok {
+			return nil, fmt.Errorf("exchange adapter not found: %s", exchange)
+		}
+		return adapter, nil
+	}
+	
+	if adapter, ok := e.routes[symbol]; ok {
+		return adapter, nil
+	}
+	
+	if e.fallback != nil {
+		return e.fallback, nil
+	}
+	
+	return nil, fmt.Errorf("no exchange adapter for symbol: %s", symbol)
+}
+
+// Adapters returns every distinct adapter known to the executor.
+func (e *Executor) Adapters() []ExchangeAdapter {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	
+	seen := make(map[ExchangeAdapter]bool)
+	var all []ExchangeAdapter
+	add := func(adapter ExchangeAdapter) {
+		if adapter != nil && !seen[adapter] {
+			seen[adapter] = true
+			all = append(all, adapter)
+		}
+	}
+	
+	for _, adapter := range e.adapters {
+		add(adapter)
+	}
+	for _, adapter := range e.routes {
+		add(adapter)
+	}
+	add(e.fallback)
+	
+	return all
+}
+
+// currentPrice returns the last traded price from the adapter's ticker.
+func (e *Executor) currentPrice(ctx context.Context, adapter ExchangeAdapter, symbol string) (decimal.Decimal, error) {
+	ticker, err := adapter.GetTicker(ctx, symbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return ticker.LastPrice, nil
+}
+
+// Connect connects to all exchanges.
+func (e *Executor) Connect(ctx context.Context) error {
+	for _, adapter := range e.Adapters() {
 		if err := adapter.Connect(ctx); err != nil {
 			e.logger.Error("Failed to connect to exchange",
 				zap.String("exchange", adapter.Name()),
@@ -183,16 +324,21 @@ func (e *Executor) Connect(ctx context.Context) error {
 
 // Disconnect disconnects from all exchanges.
 func (e *Executor) Disconnect() {
-	e.mu.RLock()
-	defer e.mu.RUnlock()
-	
-	for _, adapter := range e.adapters {
+	for _, adapter := range e.Adapters() {
 		adapter.Disconnect()
 	}
 }
 
-// Execute executes a trading signal.
+// Execute executes a trading signal. An empty exchange routes by symbol, or
+// through the router when several adapters could fill the order; a routed
+// order may be split across venues.
 func (e *Executor) Execute(ctx context.Context, signal *types.Signal, exchange string) (*ExecutionResult, error) {
+	return e.execute(ctx, signal, exchange, true)
+}
+
+// execute executes a signal, letting the router split the order only when
+// split is set.
+func (e *Executor) execute(ctx context.Context, signal *types.Signal, exchange string, split bool) (*ExecutionResult, error) {
 	e.mu.RLock()
 	if e.killSwitch {
 		e.mu.RUnlock()
@@ -206,14 +352,14 @@ func (e *Executor) Execute(ctx context.Context, signal *types.Signal, exchange s
 	
 	startTime := time.Now()
 	
-	// Get adapter
-	adapter, ok := e.adapters[exchange]
-	if !ok {
-		return nil, fmt.Errorf("exchange adapter not found: %s", exchange)
-	}
-	
-	if !adapter.IsConnected() {
-		return nil, fmt.Errorf("exchange not connected: %s", exchange)
+	// Get adapter; a routed order without one is priced off its first venue
+	venues := e.venuesFor(exchange, signal.Symbol)
+	adapter, err := e.adapterFor(exchange, signal.Symbol)
+	if err != nil {
+		if venues == nil {
+			return nil, err
+		}
+		adapter = venues[0]
 	}
 	
 	// Validate signal
@@ -222,7 +368,7 @@ func (e *Executor) Execute(ctx context.Context, signal *types.Signal, exchange s
 	}
 	
 	// Get current price
-	currentPrice, err := adapter.GetPrice(ctx, signal.Symbol)
+	currentPrice, err := e.currentPrice(ctx, adapter, signal.Symbol)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get price: %w", err)
 	}
@@ -240,11 +386,15 @@ func (e *Executor) Execute(ctx context.Context, signal *types.Signal, exchange s
 		return nil, fmt.Errorf("risk check failed: %w", err)
 	}
 	
-	// Calculate position size
-	quantity := e.calculateQuantity(signal, currentPrice)
+	// Calculate position size, floored to the step size of every venue that
+	// could be routed to
+	quantity := types.RoundQuantity("", signal.Symbol, e.calculateQuantity(signal, currentPrice))
 	if quantity.LessThan(e.config.MinOrderSize) {
 		return nil, fmt.Errorf("calculated quantity %s below minimum %s", quantity, e.config.MinOrderSize)
 	}
+	if spec, ok := types.SymbolSpecs.Get("", signal.Symbol); ok && !spec.MeetsMinNotional(quantity, currentPrice) {
+		return nil, fmt.Errorf("order notional %s below minimum %s", quantity.Mul(currentPrice), spec.MinNotional)
+	}
 	
 	// Create order
 	order := &types.Order{
@@ -276,94 +426,316 @@ func (e *Executor) Execute(ctx context.Context, signal *types.Signal, exchange s
 		} else {
 			slippageFactor = slippageFactor.Sub(e.config.DefaultSlippage)
 		}
-		order.Price = currentPrice.Mul(slippageFactor)
+		order.Price = types.RoundPrice("", signal.Symbol, order.Side, currentPrice.Mul(slippageFactor))
+	}
+	
+	// Pick the venue, or venues, when several could fill
+	if venues != nil {
+		e.mu.RLock()
+		router := e.router
+		e.mu.RUnlock()
+		
+		plan, err := router.Route(ctx, order, venues, split)
+		if err != nil {
+			return nil, fmt.Errorf("failed to route order: %w", err)
+		}
+		return e.executeRoute(ctx, signal, order, plan, currentPrice, startTime)
 	}
 	
 	// Paper trading simulation
 	if e.config.PaperTrading {
-		return e.simulateExecution(order, currentPrice, startTime)
+		return e.simulatePaper(ctx, adapter, order, currentPrice, startTime)
 	}
 	
 	// Place order with retries
-	var result *OrderResult
-	var lastErr error
-	
-	for attempt := 0; attempt < e.config.RetryAttempts; attempt++ {
-		result, err = adapter.PlaceOrder(ctx, order)
-		if err == nil {
-			break
-		}
-		
-		lastErr = err
-		e.logger.Warn("Order placement failed, retrying",
-			zap.Int("attempt", attempt+1),
-			zap.Error(err))
-		
-		time.Sleep(e.config.RetryDelay)
-	}
-	
-	if result == nil {
+	result, err := e.placeWithRetries(ctx, adapter, order)
+	if err != nil {
 		e.updateMetrics(false, decimal.Zero, time.Since(startTime))
-		return nil, fmt.Errorf("order placement failed after %d attempts: %w", e.config.RetryAttempts, lastErr)
+		return nil, err
 	}
 	
 	// Calculate actual slippage
 	actualSlippage := decimal.Zero
-	if !result.AvgPrice.IsZero() && !currentPrice.IsZero() {
-		actualSlippage = result.AvgPrice.Sub(currentPrice).Abs().Div(currentPrice)
+	if !result.AvgFillPrice.IsZero() && !currentPrice.IsZero() {
+		actualSlippage = result.AvgFillPrice.Sub(currentPrice).Abs().Div(currentPrice)
 	}
 	
 	// Update metrics
 	e.updateMetrics(true, actualSlippage, time.Since(startTime))
 	
+	fill := e.liveFill(adapter.Name(), order, result, currentPrice)
+	e.recordSlippage(order.Symbol, expectedPrice(signal, currentPrice), fill)
+	
 	execResult := &ExecutionResult{
-		OrderID:       result.OrderID,
+		OrderID:       result.ID,
 		Signal:        signal,
 		Order:         order,
-		Exchange:      exchange,
-		Status:        result.Status,
+		Exchange:      adapter.Name(),
+		Status:        string(result.Status),
 		FilledQty:     result.FilledQty,
-		AvgPrice:      result.AvgPrice,
-		Commission:    result.Commission,
+		AvgPrice:      result.AvgFillPrice,
+		Commission:    fill.Commission,
 		Slippage:      actualSlippage,
 		Latency:       time.Since(startTime),
 		Timestamp:     time.Now(),
+		Fills:         []VenueFill{fill},
 	}
 	
 	e.logger.Info("Order executed",
-		zap.String("orderId", result.OrderID),
+		zap.String("orderId", result.ID),
 		zap.String("symbol", order.Symbol),
 		zap.String("side", string(order.Side)),
 		zap.String("qty", order.Quantity.String()),
-		zap.String("price", result.AvgPrice.String()),
+		zap.String("price", result.AvgFillPrice.String()),
 		zap.String("slippage", actualSlippage.String()))
 	
 	return execResult, nil
 }
 
+// placeWithRetries places an order, retrying up to RetryAttempts times.
+// Every attempt carries the order's ClientOrderID, generated from its ID
+// when unset. A failed attempt may still have reached the venue, so the
+// order is placed again only once a lookup by ClientOrderID definitively
+// finds nothing; a lookup that fails is retried in place of the placement.
+// Venues without lookups are not retried, leaving reconciliation to find
+// any order the failed attempt placed.
+func (e *Executor) placeWithRetries(ctx context.Context, adapter ExchangeAdapter, order *types.Order) (*types.Order, error) {
+	var lastErr error
+	
+	if order.ClientOrderID == "" {
+		order.ClientOrderID = ClientOrderID(order.ID)
+	}
+	
+	lookup, canLookup := adapter.(adapters.ClientOrderLookup)
+	attempts := e.config.RetryAttempts
+	if !canLookup {
+		attempts = 1
+	}
+	
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(e.config.RetryDelay):
+			}
+			
+			placed, err := e.findPlaced(ctx, lookup, adapter.Name(), order)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if placed != nil {
+				return placed, nil
+			}
+		}
+		
+		result, err := adapter.PlaceOrder(ctx, order)
+		if err == nil {
+			return result, nil
+		}
+		
+		lastErr = err
+		e.logger.Warn("Order placement failed",
+			zap.String("exchange", adapter.Name()),
+			zap.Int("attempt", attempt+1),
+			zap.Bool("retrying", attempt+1 < attempts),
+			zap.Error(err))
+	}
+	
+	return nil, fmt.Errorf("order placement failed after %d attempts: %w", attempts, lastErr)
+}
+
+// findPlaced looks up an order by its client order ID, returning it if an
+// earlier attempt placed it and nil if the venue definitively has no such
+// order. Any other lookup failure is returned, as the order may exist.
+func (e *Executor) findPlaced(ctx context.Context, lookup adapters.ClientOrderLookup, exchange string, order *types.Order) (*types.Order, error) {
+	placed, err := lookup.GetOrderByClientID(ctx, order.Symbol, order.ClientOrderID)
+	if errors.Is(err, adapters.ErrOrderNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		e.logger.Warn("Failed to check for an earlier attempt, not placing again",
+			zap.String("exchange", exchange),
+			zap.String("clientOrderId", order.ClientOrderID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to look up order %s: %w", order.ClientOrderID, err)
+	}
+	
+	e.logger.Info("Earlier attempt was placed, not retrying",
+		zap.String("exchange", exchange),
+		zap.String("clientOrderId", order.ClientOrderID),
+		zap.String("orderId", placed.ID))
+	return placed, nil
+}
+
+// executeRoute places each leg of a routed order and combines the fills.
+// Legs that fail are reported in Fills with their error; the order fails only
+// when no leg was placed. OrderID and Exchange name the largest leg.
+func (e *Executor) executeRoute(
+	ctx context.Context,
+	signal *types.Signal,
+	order *types.Order,
+	plan *RoutePlan,
+	currentPrice decimal.Decimal,
+	startTime time.Time,
+) (*ExecutionResult, error) {
+	execResult := &ExecutionResult{
+		Signal:  signal,
+		Order:   order,
+		IsPaper: e.config.PaperTrading,
+	}
+	
+	notional := decimal.Zero
+	largest := decimal.Zero
+	statuses := make(map[string]bool)
+	var lastErr error
+	
+	for i, leg := range plan.Legs {
+		legOrder := *order
+		legOrder.Quantity = leg.Quantity
+		if plan.Split() {
+			legOrder.ID = fmt.Sprintf("%s-%d", order.ID, i+1)
+		}
+		
+		fill := VenueFill{
+			Exchange: leg.Venue,
+			OrderID:  legOrder.ID,
+			Quantity: leg.Quantity,
+		}
+		
+		if e.config.PaperTrading {
+			fillPrice, fee, _ := e.simulateFill(&legOrder, leg.Venue, leg.Price)
+			fill.Status = "FILLED"
+			fill.FilledQty = leg.Quantity
+			fill.AvgPrice = fillPrice
+			fill.Commission = fee.Quote
+			fill.CommissionAsset = fee.Asset
+			fill.Liquidity = fee.Liquidity
+		} else {
+			result, err := e.placeWithRetries(ctx, leg.Adapter, &legOrder)
+			if err != nil {
+				lastErr = err
+				fill.Error = err.Error()
+				execResult.Fills = append(execResult.Fills, fill)
+				e.logger.Error("Routed order leg failed",
+					zap.String("orderId", legOrder.ID),
+					zap.String("exchange", leg.Venue),
+					zap.Error(err))
+				continue
+			}
+			fill = e.liveFill(leg.Venue, &legOrder, result, currentPrice)
+			e.recordSlippage(order.Symbol, expectedPrice(signal, currentPrice), fill)
+		}
+		
+		execResult.Fills = append(execResult.Fills, fill)
+		statuses[fill.Status] = true
+		execResult.FilledQty = execResult.FilledQty.Add(fill.FilledQty)
+		execResult.Commission = execResult.Commission.Add(fill.Commission)
+		notional = notional.Add(fill.FilledQty.Mul(fill.AvgPrice))
+		
+		if execResult.OrderID == "" || leg.Quantity.GreaterThan(largest) {
+			largest = leg.Quantity
+			execResult.OrderID = fill.OrderID
+			execResult.Exchange = fill.Exchange
+			execResult.Status = fill.Status
+		}
+	}
+	
+	if execResult.OrderID == "" {
+		e.updateMetrics(false, decimal.Zero, time.Since(startTime))
+		return nil, fmt.Errorf("every routed leg failed: %w", lastErr)
+	}
+	
+	if len(statuses) > 1 || lastErr != nil {
+		execResult.Status = string(types.OrderStatusPartiallyFilled)
+	}
+	if execResult.FilledQty.IsPositive() {
+		execResult.AvgPrice = notional.Div(execResult.FilledQty)
+	}
+	if !execResult.AvgPrice.IsZero() && !currentPrice.IsZero() {
+		execResult.Slippage = execResult.AvgPrice.Sub(currentPrice).Abs().Div(currentPrice)
+	}
+	
+	e.updateMetrics(true, execResult.Slippage, time.Since(startTime))
+	execResult.Latency = time.Since(startTime)
+	execResult.Timestamp = time.Now()
+	
+	venues := make([]string, len(execResult.Fills))
+	for i, fill := range execResult.Fills {
+		venues[i] = fill.Exchange
+	}
+	e.logger.Info("Routed order executed",
+		zap.String("orderId", execResult.OrderID),
+		zap.String("symbol", order.Symbol),
+		zap.String("side", string(order.Side)),
+		zap.String("qty", order.Quantity.String()),
+		zap.Strings("venues", venues),
+		zap.String("price", execResult.AvgPrice.String()),
+		zap.Bool("paper", execResult.IsPaper))
+	
+	return execResult, nil
+}
+
 // ExecuteWithSLTP executes a signal with stop loss and take profit orders.
+// A routed entry goes to a single venue so its exits rest where the position
+// is held.
 func (e *Executor) ExecuteWithSLTP(
 	ctx context.Context,
 	signal *types.Signal,
 	exchange string,
 ) (*ExecutionResult, error) {
 	// Execute main order
-	result, err := e.Execute(ctx, signal, exchange)
+	result, err := e.execute(ctx, signal, exchange, false)
 	if err != nil {
 		return nil, err
 	}
 	
-	adapter := e.adapters[exchange]
+	if exchange == "" && len(result.Fills) == 1 {
+		exchange = result.Fills[0].Exchange
+	}
+	
+	adapter, err := e.adapterFor(exchange, signal.Symbol)
+	if err != nil {
+		return result, err
+	}
+	
+	// Exits rest on the book, so they must sit on the venue's ticks
+	exitSide := e.oppositeSide(result.Order.Side)
+	stopLoss := types.RoundPrice(adapter.Name(), signal.Symbol, exitSide, signal.StopLoss)
+	takeProfit := types.RoundPrice(adapter.Name(), signal.Symbol, exitSide, signal.TakeProfit)
+	
+	// Prefer an OCO bracket so a whipsaw can't fill both exits
+	if !stopLoss.IsZero() && !takeProfit.IsZero() {
+		if oco, ok := adapter.(adapters.OCOAdapter); ok {
+			list, err := oco.PlaceOCOOrder(ctx, &adapters.OCOOrder{
+				Symbol:            signal.Symbol,
+				Side:              exitSide,
+				Quantity:          result.FilledQty,
+				TakeProfitPrice:   takeProfit,
+				StopPrice:         stopLoss,
+				StopLimitPrice:    e.stopLimitPrice(exitSide, stopLoss),
+				ListClientOrderID: fmt.Sprintf("oco-%s", result.OrderID),
+			})
+			if err == nil {
+				result.OrderListID = list.OrderListID
+				result.StopLossOrderID = list.StopLoss.ID
+				result.TakeProfitOrderID = list.TakeProfit.ID
+				return result, nil
+			}
+			e.logger.Error("Failed to place OCO bracket, placing separate exits", zap.Error(err))
+		}
+	}
 	
 	// Place stop loss
-	if !signal.StopLoss.IsZero() {
+	if !stopLoss.IsZero() {
 		slOrder := &types.Order{
 			ID:        fmt.Sprintf("sl-%s", result.OrderID),
 			Symbol:    signal.Symbol,
-			Side:      e.oppositeSide(result.Order.Side),
+			Side:      exitSide,
 			Type:      types.OrderTypeStopLoss,
 			Quantity:  result.FilledQty,
-			StopPrice: signal.StopLoss,
+			StopPrice: stopLoss,
 			Timestamp: time.Now(),
 		}
 		
@@ -376,14 +748,14 @@ func (e *Executor) ExecuteWithSLTP(
 	}
 	
 	// Place take profit
-	if !signal.TakeProfit.IsZero() {
+	if !takeProfit.IsZero() {
 		tpOrder := &types.Order{
 			ID:        fmt.Sprintf("tp-%s", result.OrderID),
 			Symbol:    signal.Symbol,
-			Side:      e.oppositeSide(result.Order.Side),
+			Side:      exitSide,
 			Type:      types.OrderTypeTakeProfit,
 			Quantity:  result.FilledQty,
-			StopPrice: signal.TakeProfit,
+			StopPrice: takeProfit,
 			Timestamp: time.Now(),
 		}
 		
@@ -398,11 +770,121 @@ func (e *Executor) ExecuteWithSLTP(
 	return result, nil
 }
 
+// ReplaceStopLoss moves the stop protecting an executed order to stopPrice by
+// cancelling the resting stop and placing a new one. An OCO bracket is
+// replaced as a whole so its take profit stays linked to the new stop. The
+// exit order IDs on result are updated in place. Paper results have no resting
+// orders, so nothing is sent for them.
+func (e *Executor) ReplaceStopLoss(ctx context.Context, result *ExecutionResult, stopPrice decimal.Decimal) error {
+	if result.IsPaper {
+		return nil
+	}
+	
+	adapter, err := e.adapterFor(result.Exchange, result.Order.Symbol)
+	if err != nil {
+		return err
+	}
+	
+	exitSide := e.oppositeSide(result.Order.Side)
+	
+	if result.OrderListID != "" {
+		if oco, ok := adapter.(adapters.OCOAdapter); ok {
+			if err := oco.CancelOCOOrder(ctx, result.OrderListID); err != nil {
+				return fmt.Errorf("failed to cancel OCO bracket: %w", err)
+			}
+			
+			list, err := oco.PlaceOCOOrder(ctx, &adapters.OCOOrder{
+				Symbol:            result.Order.Symbol,
+				Side:              exitSide,
+				Quantity:          result.FilledQty,
+				TakeProfitPrice:   result.Signal.TakeProfit,
+				StopPrice:         stopPrice,
+				StopLimitPrice:    e.stopLimitPrice(exitSide, stopPrice),
+				ListClientOrderID: fmt.Sprintf("oco-%s-%d", result.OrderID, time.Now().UnixNano()),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to place OCO bracket: %w", err)
+			}
+			
+			result.OrderListID = list.OrderListID
+			result.StopLossOrderID = list.StopLoss.ID
+			result.TakeProfitOrderID = list.TakeProfit.ID
+			return nil
+		}
+	}
+	
+	if result.StopLossOrderID != "" {
+		if err := adapter.CancelOrder(ctx, result.StopLossOrderID); err != nil {
+			return fmt.Errorf("failed to cancel stop loss: %w", err)
+		}
+	}
+	
+	slOrder := &types.Order{
+		ID:        fmt.Sprintf("sl-%s-%d", result.OrderID, time.Now().UnixNano()),
+		Symbol:    result.Order.Symbol,
+		Side:      exitSide,
+		Type:      types.OrderTypeStopLoss,
+		Quantity:  result.FilledQty,
+		StopPrice: stopPrice,
+		Timestamp: time.Now(),
+	}
+	
+	if _, err := adapter.PlaceOrder(ctx, slOrder); err != nil {
+		result.StopLossOrderID = ""
+		return fmt.Errorf("failed to place stop loss: %w", err)
+	}
+	result.StopLossOrderID = slOrder.ID
+	
+	return nil
+}
+
+// CancelExits cancels the resting stop loss and take profit protecting an
+// executed order, so they cannot fill once the position is closed another
+// way. The exit order IDs on result are cleared as they are cancelled. Paper
+// results have no resting orders, so nothing is sent for them.
+func (e *Executor) CancelExits(ctx context.Context, result *ExecutionResult) error {
+	if result.IsPaper {
+		return nil
+	}
+	
+	adapter, err := e.adapterFor(result.Exchange, result.Order.Symbol)
+	if err != nil {
+		return err
+	}
+	
+	if result.OrderListID != "" {
+		if oco, ok := adapter.(adapters.OCOAdapter); ok {
+			if err := oco.CancelOCOOrder(ctx, result.OrderListID); err != nil {
+				return fmt.Errorf("failed to cancel OCO bracket: %w", err)
+			}
+			result.OrderListID = ""
+			result.StopLossOrderID = ""
+			result.TakeProfitOrderID = ""
+			return nil
+		}
+	}
+	
+	if result.StopLossOrderID != "" {
+		if err := adapter.CancelOrder(ctx, result.StopLossOrderID); err != nil {
+			return fmt.Errorf("failed to cancel stop loss: %w", err)
+		}
+		result.StopLossOrderID = ""
+	}
+	if result.TakeProfitOrderID != "" {
+		if err := adapter.CancelOrder(ctx, result.TakeProfitOrderID); err != nil {
+			return fmt.Errorf("failed to cancel take profit: %w", err)
+		}
+		result.TakeProfitOrderID = ""
+	}
+	
+	return nil
+}
+
 // ClosePosition closes an existing position.
 func (e *Executor) ClosePosition(ctx context.Context, position *types.Position, exchange string) (*ExecutionResult, error) {
-	adapter, ok := e.adapters[exchange]
-	if !ok {
-		return nil, fmt.Errorf("exchange adapter not found: %s", exchange)
+	adapter, err := e.adapterFor(exchange, position.Symbol)
+	if err != nil {
+		return nil, err
 	}
 	
 	// Determine close side
@@ -423,8 +905,8 @@ func (e *Executor) ClosePosition(ctx context.Context, position *types.Position,
 	}
 	
 	if e.config.PaperTrading {
-		currentPrice, _ := adapter.GetPrice(ctx, position.Symbol)
-		return e.simulateExecution(order, currentPrice, time.Now())
+		currentPrice, _ := e.currentPrice(ctx, adapter, position.Symbol)
+		return e.simulatePaper(ctx, adapter, order, currentPrice, time.Now())
 	}
 	
 	result, err := adapter.PlaceOrder(ctx, order)
@@ -432,14 +914,18 @@ func (e *Executor) ClosePosition(ctx context.Context, position *types.Position,
 		return nil, err
 	}
 	
+	fill := e.liveFill(adapter.Name(), order, result, decimal.Zero)
+	
 	return &ExecutionResult{
-		OrderID:   result.OrderID,
-		Order:     order,
-		Exchange:  exchange,
-		Status:    result.Status,
-		FilledQty: result.FilledQty,
-		AvgPrice:  result.AvgPrice,
-		Timestamp: time.Now(),
+		OrderID:    result.ID,
+		Order:      order,
+		Exchange:   adapter.Name(),
+		Status:     string(result.Status),
+		FilledQty:  result.FilledQty,
+		AvgPrice:   result.AvgFillPrice,
+		Commission: fill.Commission,
+		Timestamp:  time.Now(),
+		Fills:      []VenueFill{fill},
 	}, nil
 }
 
@@ -525,19 +1011,9 @@ func (e *Executor) calculateQuantity(signal *types.Signal, currentPrice decimal.
 }
 
 // simulateExecution simulates order execution for paper trading.
-func (e *Executor) simulateExecution(order *types.Order, currentPrice decimal.Decimal, startTime time.Time) (*ExecutionResult, error) {
-	// Simulate some slippage
-	simulatedSlippage := e.config.DefaultSlippage.Mul(decimal.NewFromFloat(0.5))
-	
-	fillPrice := currentPrice
-	if order.Side == types.OrderSideBuy {
-		fillPrice = currentPrice.Mul(decimal.NewFromInt(1).Add(simulatedSlippage))
-	} else {
-		fillPrice = currentPrice.Mul(decimal.NewFromInt(1).Sub(simulatedSlippage))
-	}
-	
-	// Simulate commission (0.1%)
-	commission := order.Quantity.Mul(fillPrice).Mul(decimal.NewFromFloat(0.001))
+// Fees are charged at the venue's schedule.
+func (e *Executor) simulateExecution(order *types.Order, venue string, currentPrice decimal.Decimal, startTime time.Time) (*ExecutionResult, error) {
+	fillPrice, fee, simulatedSlippage := e.simulateFill(order, venue, currentPrice)
 	
 	e.updateMetrics(true, simulatedSlippage, time.Since(startTime))
 	
@@ -548,14 +1024,68 @@ func (e *Executor) simulateExecution(order *types.Order, currentPrice decimal.De
 		Status:     "FILLED",
 		FilledQty:  order.Quantity,
 		AvgPrice:   fillPrice,
-		Commission: commission,
+		Commission: fee.Quote,
 		Slippage:   simulatedSlippage,
 		Latency:    time.Since(startTime),
 		Timestamp:  time.Now(),
 		IsPaper:    true,
+		Fills: []VenueFill{{
+			Exchange:        venue,
+			OrderID:         order.ID,
+			Quantity:        order.Quantity,
+			Status:          "FILLED",
+			FilledQty:       order.Quantity,
+			AvgPrice:        fillPrice,
+			Commission:      fee.Quote,
+			CommissionAsset: fee.Asset,
+			Liquidity:       fee.Liquidity,
+		}},
 	}, nil
 }
 
+// simulateFill returns the simulated fill price, fee and slippage of a paper
+// order filled on venue at price. The order makes liquidity only when it
+// would rest at that price.
+func (e *Executor) simulateFill(order *types.Order, venue string, price decimal.Decimal) (decimal.Decimal, fees.Fee, decimal.Decimal) {
+	// Simulate some slippage
+	simulatedSlippage := e.config.DefaultSlippage.Mul(decimal.NewFromFloat(0.5))
+	
+	fillPrice := price
+	if order.Side == types.OrderSideBuy {
+		fillPrice = price.Mul(decimal.NewFromInt(1).Add(simulatedSlippage))
+	} else {
+		fillPrice = price.Mul(decimal.NewFromInt(1).Sub(simulatedSlippage))
+	}
+	
+	fee := e.feeModel().Fee(venue, fees.LiquidityOf(order, price), order.Quantity, fillPrice)
+	
+	return fillPrice, fee, simulatedSlippage
+}
+
+// liveFill records a venue's fill of order. Venues that report no commission
+// are charged the fee model's estimate, classifying liquidity against
+// marketPrice when it is known.
+func (e *Executor) liveFill(venue string, order, result *types.Order, marketPrice decimal.Decimal) VenueFill {
+	fill := VenueFill{
+		Exchange:   venue,
+		OrderID:    result.ID,
+		Quantity:   order.Quantity,
+		Status:     string(result.Status),
+		FilledQty:  result.FilledQty,
+		AvgPrice:   result.AvgFillPrice,
+		Commission: result.Commission,
+	}
+	
+	if fill.Commission.IsZero() && fill.FilledQty.IsPositive() {
+		fee := e.feeModel().Fee(venue, fees.LiquidityOf(order, marketPrice), fill.FilledQty, fill.AvgPrice)
+		fill.Commission = fee.Quote
+		fill.CommissionAsset = fee.Asset
+		fill.Liquidity = fee.Liquidity
+	}
+	
+	return fill
+}
+
 // updateMetrics updates execution metrics.
 func (e *Executor) updateMetrics(success bool, slippage decimal.Decimal, latency time.Duration) {
 	e.mu.Lock()
@@ -585,6 +1115,15 @@ func (e *Executor) updateMetrics(success bool, slippage decimal.Decimal, latency
 	e.metrics.LastOrderTime = time.Now()
 }
 
+// stopLimitPrice returns the limit for a stop-loss leg, offset from the
+// trigger by the maximum slippage so the stop still fills in a fast market.
+func (e *Executor) stopLimitPrice(side types.OrderSide, stopPrice decimal.Decimal) decimal.Decimal {
+	if side == types.OrderSideSell {
+		return stopPrice.Mul(decimal.NewFromInt(1).Sub(e.config.MaxSlippage))
+	}
+	return stopPrice.Mul(decimal.NewFromInt(1).Add(e.config.MaxSlippage))
+}
+
 // oppositeSide returns the opposite order side.
 func (e *Executor) oppositeSide(side types.OrderSide) types.OrderSide {
 	if side == types.OrderSideBuy {
@@ -609,4 +1148,28 @@ type ExecutionResult struct {
 	IsPaper           bool            `json:"isPaper"`
 	StopLossOrderID   string          `json:"stopLossOrderId,omitempty"`
 	TakeProfitOrderID string          `json:"takeProfitOrderId,omitempty"`
+	OrderListID       string          `json:"orderListId,omitempty"` // Set when the exits were placed as an OCO
+	Fills             []VenueFill     `json:"fills,omitempty"`       // Per-venue legs; several when the order was split or repriced
+	Reprices          int             `json:"reprices,omitempty"`    // Times a passive order was moved toward the market
+}
+
+// VenueFill is the part of an execution placed on one venue.
+type VenueFill struct {
+	Exchange   string          `json:"exchange"`
+	OrderID    string          `json:"orderId"`
+	Quantity   decimal.Decimal `json:"quantity"` // Quantity routed to the venue
+	Status     string          `json:"status"`
+	FilledQty  decimal.Decimal `json:"filledQty"`
+	AvgPrice   decimal.Decimal `json:"avgPrice"`
+	Commission decimal.Decimal `json:"commission"`
+	Error      string          `json:"error,omitempty"`
+	
+	// Set when the commission was charged or estimated by the fee model;
+	// venue-reported commissions are in the venue's fee currency
+	CommissionAsset string         `json:"commissionAsset,omitempty"`
+	Liquidity       fees.Liquidity `json:"liquidity,omitempty"`
+	
+	// Set on simulated paper limit orders: quantity quoted at the order's
+	// price ahead of it when it rested
+	QueueAhead      decimal.Decimal `json:"queueAhead,omitempty"`
 }
@@ -0,0 +1,201 @@
+// Package execution provides perpetual futures funding accrual.
+package execution
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// FundingConfig configures funding tracking.
+type FundingConfig struct {
+	Symbols      []string      `json:"symbols"`      // Symbols traded as perpetuals; positions in others never accrue funding
+	PollInterval time.Duration `json:"pollInterval"` // How often rates are re-read
+}
+
+// DefaultFundingConfig polls every minute and tracks no symbols.
+func DefaultFundingConfig() FundingConfig {
+	return FundingConfig{
+		PollInterval: time.Minute,
+	}
+}
+
+// FundingPayment is funding settled against an open position. Amount is
+// positive when the position was credited and negative when it paid.
+type FundingPayment struct {
+	Symbol      string             `json:"symbol"`
+	Side        types.PositionSide `json:"side"`
+	Quantity    decimal.Decimal    `json:"quantity"`
+	MarkPrice   decimal.Decimal    `json:"markPrice"`
+	Rate        decimal.Decimal    `json:"rate"`
+	Amount      decimal.Decimal    `json:"amount"`
+	FundingTime time.Time          `json:"fundingTime"`
+}
+
+// FundingTracker polls perpetual funding rates and, as each settlement
+// passes, accrues the funding against the portfolio's open positions, so it
+// shows up in cash and realized PnL.
+type FundingTracker struct {
+	logger    *zap.Logger
+	config    FundingConfig
+	source    adapters.FundingRateAdapter
+	portfolio *PortfolioManager
+
+	mu      sync.RWMutex
+	rates   map[string]adapters.FundingRate // Latest by normalized symbol
+	settled map[string]time.Time            // Last settlement accrued by normalized symbol
+	total   decimal.Decimal
+
+	// OnFunding is called after each payment is accrued
+	OnFunding func(payment FundingPayment)
+}
+
+// NewFundingTracker creates a tracker reading rates from source.
+func NewFundingTracker(logger *zap.Logger, config FundingConfig, source adapters.FundingRateAdapter, portfolio *PortfolioManager) *FundingTracker {
+	return &FundingTracker{
+		logger:    logger.Named("funding"),
+		config:    config,
+		source:    source,
+		portfolio: portfolio,
+		rates:     make(map[string]adapters.FundingRate),
+		settled:   make(map[string]time.Time),
+	}
+}
+
+// Start polls rates until ctx is cancelled.
+func (ft *FundingTracker) Start(ctx context.Context) {
+	interval := ft.config.PollInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	go func() {
+		ft.Poll(ctx)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ft.Poll(ctx)
+			}
+		}
+	}()
+}
+
+// Poll reads every tracked symbol's rate, accruing any settlement since the
+// last poll. A symbol's first rate only marks where accrual starts.
+func (ft *FundingTracker) Poll(ctx context.Context) {
+	for _, symbol := range ft.config.Symbols {
+		rate, err := ft.source.GetFundingRate(ctx, symbol)
+		if err != nil {
+			ft.logger.Warn("Funding rate unavailable", zap.String("symbol", symbol), zap.Error(err))
+			continue
+		}
+		ft.Update(*rate)
+	}
+}
+
+// Update records a rate, accruing its settlement if it is newer than the
+// last one accrued for the symbol.
+func (ft *FundingTracker) Update(rate adapters.FundingRate) {
+	key := NormalizeSymbol(rate.Symbol)
+
+	ft.mu.Lock()
+	ft.rates[key] = rate
+	last, seen := ft.settled[key]
+	due := seen && rate.FundingTime.After(last)
+	if !seen || due {
+		ft.settled[key] = rate.FundingTime
+	}
+	ft.mu.Unlock()
+
+	if !due {
+		return
+	}
+
+	payment, ok := ft.portfolio.ApplyFunding(rate.Symbol, rate.Rate, rate.MarkPrice, rate.FundingTime)
+	if !ok {
+		return
+	}
+
+	ft.mu.Lock()
+	ft.total = ft.total.Add(payment.Amount)
+	ft.mu.Unlock()
+
+	ft.logger.Info("Funding settled",
+		zap.String("symbol", payment.Symbol),
+		zap.String("side", string(payment.Side)),
+		zap.String("rate", payment.Rate.String()),
+		zap.String("amount", payment.Amount.String()))
+
+	if ft.OnFunding != nil {
+		ft.OnFunding(payment)
+	}
+}
+
+// FundingRate returns a symbol's latest rate, reporting false for symbols
+// not traded as perpetuals or not yet polled.
+func (ft *FundingTracker) FundingRate(symbol string) (adapters.FundingRate, bool) {
+	ft.mu.RLock()
+	defer ft.mu.RUnlock()
+
+	rate, ok := ft.rates[NormalizeSymbol(symbol)]
+	return rate, ok
+}
+
+// TotalFunding returns the funding accrued since the tracker started,
+// positive when more was received than paid.
+func (ft *FundingTracker) TotalFunding() decimal.Decimal {
+	ft.mu.RLock()
+	defer ft.mu.RUnlock()
+	return ft.total
+}
+
+// ApplyFunding settles funding at rate against symbol's position, if one was
+// open at fundingTime. Longs pay a positive rate on their notional at
+// markPrice and shorts receive it; the payment moves cash and is added to
+// the position's realized PnL.
+func (pm *PortfolioManager) ApplyFunding(symbol string, rate, markPrice decimal.Decimal, fundingTime time.Time) (FundingPayment, bool) {
+	key := NormalizeSymbol(symbol)
+
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	position, ok := pm.positions[key]
+	if !ok || position.OpenedAt.After(fundingTime) {
+		return FundingPayment{}, false
+	}
+	if !markPrice.IsPositive() {
+		markPrice = pm.prices[key]
+	}
+	if !markPrice.IsPositive() {
+		markPrice = position.EntryPrice
+	}
+
+	amount := position.Quantity.Mul(markPrice).Mul(rate)
+	if position.Side == types.PositionSideLong {
+		amount = amount.Neg()
+	}
+	pm.cash = pm.cash.Add(amount)
+	position.Funding = position.Funding.Add(amount)
+	position.RealizedPnL = position.RealizedPnL.Add(amount)
+
+	return FundingPayment{
+		Symbol:      symbol,
+		Side:        position.Side,
+		Quantity:    position.Quantity,
+		MarkPrice:   markPrice,
+		Rate:        rate,
+		Amount:      amount,
+		FundingTime: fundingTime,
+	}, true
+}
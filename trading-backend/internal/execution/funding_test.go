@@ -0,0 +1,84 @@
+package execution_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// fundingFeed returns the rate set for each symbol
+type fundingFeed map[string]*adapters.FundingRate
+
+func (f fundingFeed) GetFundingRate(ctx context.Context, symbol string) (*adapters.FundingRate, error) {
+	rate := *f[symbol]
+	return &rate, nil
+}
+
+func TestFundingTrackerAccruesSettlementsAgainstPositions(t *testing.T) {
+	pm := execution.NewPortfolioManager(zap.NewNop(), execution.DefaultPortfolioConfig())
+	var closed *types.Position
+	pm.OnPositionClosed = func(position *types.Position) { closed = position }
+
+	fill := func(id, symbol string, side types.OrderSide, price int64) {
+		pm.OnOrderUpdate(&execution.ManagedOrder{
+			Order: &types.Order{ID: id, Symbol: symbol, Side: side},
+			Fills: []execution.OrderFill{{Price: decimal.NewFromInt(price), Quantity: decimal.NewFromInt(2)}},
+		})
+	}
+	fill("long", "BTCUSDT", types.OrderSideBuy, 100)
+	fill("short", "ETHUSDT", types.OrderSideSell, 50)
+
+	settled := time.Now().Add(-time.Hour)
+	feed := fundingFeed{
+		"BTCUSDT": {Symbol: "BTCUSDT", Rate: decimal.NewFromFloat(0.001), FundingTime: settled, MarkPrice: decimal.NewFromInt(100)},
+		"ETHUSDT": {Symbol: "ETHUSDT", Rate: decimal.NewFromFloat(0.001), FundingTime: settled, MarkPrice: decimal.NewFromInt(50)},
+	}
+	config := execution.DefaultFundingConfig()
+	config.Symbols = []string{"BTCUSDT", "ETHUSDT"}
+	tracker := execution.NewFundingTracker(zap.NewNop(), config, feed, pm)
+	var payments []execution.FundingPayment
+	tracker.OnFunding = func(p execution.FundingPayment) { payments = append(payments, p) }
+
+	// The first poll only records where accrual starts, and a repeat of the
+	// same settlement accrues nothing
+	tracker.Poll(context.Background())
+	tracker.Poll(context.Background())
+	if len(payments) != 0 {
+		t.Fatalf("Expected no funding before a new settlement, got %+v", payments)
+	}
+
+	next := time.Now().Add(time.Hour)
+	feed["BTCUSDT"] = &adapters.FundingRate{Symbol: "BTCUSDT", Rate: decimal.NewFromFloat(0.001), FundingTime: next, MarkPrice: decimal.NewFromInt(110)}
+	feed["ETHUSDT"] = &adapters.FundingRate{Symbol: "ETHUSDT", Rate: decimal.NewFromFloat(0.002), FundingTime: next, MarkPrice: decimal.NewFromInt(50)}
+	tracker.Poll(context.Background())
+
+	// The long pays 2 x 110 x 0.001; the short receives 2 x 50 x 0.002
+	if len(payments) != 2 {
+		t.Fatalf("Expected a payment per position, got %+v", payments)
+	}
+	if !payments[0].Amount.Equal(decimal.NewFromFloat(-0.22)) || !payments[1].Amount.Equal(decimal.NewFromFloat(0.2)) {
+		t.Errorf("Expected -0.22 and 0.2, got %s and %s", payments[0].Amount, payments[1].Amount)
+	}
+	if got := tracker.TotalFunding(); !got.Equal(decimal.NewFromFloat(-0.02)) {
+		t.Errorf("Expected -0.02 in total, got %s", got)
+	}
+	// 10000 - 200 + 100 in trades, then funding
+	if got := pm.AvailableCash(); !got.Equal(decimal.NewFromFloat(9899.98)) {
+		t.Errorf("Expected funding in cash, got %s", got)
+	}
+	if rate, ok := tracker.FundingRate("ETH/USDT"); !ok || !rate.Rate.Equal(decimal.NewFromFloat(0.002)) {
+		t.Errorf("Expected the latest ETH rate, got %+v", rate)
+	}
+
+	// Funding is part of the closed trade's realized PnL
+	fill("exit", "BTCUSDT", types.OrderSideSell, 110)
+	if closed == nil || !closed.Funding.Equal(decimal.NewFromFloat(-0.22)) || !closed.RealizedPnL.Equal(decimal.NewFromFloat(19.78)) {
+		t.Errorf("Expected 20 of price PnL less 0.22 of funding, got %+v", closed)
+	}
+}
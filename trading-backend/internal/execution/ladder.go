@@ -0,0 +1,477 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// ladderTrailStep is how far, as a fraction of the trail distance, a
+// trailing stop must improve before it is moved, so the stop order is not
+// replaced on every tick.
+var ladderTrailStep = decimal.NewFromFloat(0.1)
+
+// TPRung is one take-profit target in a ladder. Exactly one of Price,
+// RMultiple or TrailPercent sets where it exits.
+type TPRung struct {
+	Fraction     decimal.Decimal `json:"fraction"`               // Share of the position when the ladder was attached
+	Price        decimal.Decimal `json:"price,omitempty"`        // Fixed target price
+	RMultiple    decimal.Decimal `json:"rMultiple,omitempty"`    // Target as a multiple of the entry-to-stop distance
+	TrailPercent decimal.Decimal `json:"trailPercent,omitempty"` // Exit on the stop, trailing the best price by this fraction
+
+	// Set by the order manager
+	Quantity  decimal.Decimal `json:"quantity"`
+	Target    decimal.Decimal `json:"target,omitempty"` // Resolved target price; zero for a trailing rung
+	OrderID   string          `json:"orderId,omitempty"`
+	FilledQty decimal.Decimal `json:"filledQty"`
+	Filled    bool            `json:"filled"`
+}
+
+// Trailing reports whether the rung exits on the trailing stop rather than
+// at a resting target.
+func (r TPRung) Trailing() bool {
+	return r.TrailPercent.IsPositive()
+}
+
+// TPLadder is a set of take-profit targets scaling out of one position. The
+// quantity not covered by a filled rung stays protected by Stop, which moves
+// to breakeven once the first target fills and then follows a trailing rung.
+type TPLadder struct {
+	Symbol      string             `json:"symbol"`
+	Side        types.PositionSide `json:"side"`
+	EntryPrice  decimal.Decimal    `json:"entryPrice"`
+	InitialQty  decimal.Decimal    `json:"initialQty"`
+	RiskPerUnit decimal.Decimal    `json:"riskPerUnit"` // Entry-to-stop distance, the R of RMultiple
+	Stop        decimal.Decimal    `json:"stop"`
+	Breakeven   bool               `json:"breakeven"` // The stop has moved to the entry price
+	BestPrice   decimal.Decimal    `json:"bestPrice"` // Most favourable mark since the ladder was attached
+	Rungs       []TPRung           `json:"rungs"`
+	Closed      bool               `json:"closed"` // The position is flat; outstanding child orders should be cancelled
+
+	// The resting stop order protecting the remaining quantity
+	StopOrderID    string          `json:"stopOrderId,omitempty"`
+	StopOrderPrice decimal.Decimal `json:"stopOrderPrice,omitempty"`
+	StopOrderQty   decimal.Decimal `json:"stopOrderQty,omitempty"`
+
+	AttachedAt time.Time `json:"attachedAt"`
+}
+
+// FilledRungs returns how many rungs have filled completely.
+func (l *TPLadder) FilledRungs() int {
+	filled := 0
+	for _, rung := range l.Rungs {
+		if rung.Filled {
+			filled++
+		}
+	}
+	return filled
+}
+
+// nextTarget returns the nearest unfilled resting target, or zero.
+func (l *TPLadder) nextTarget() decimal.Decimal {
+	next := decimal.Zero
+	for _, rung := range l.Rungs {
+		if rung.Filled || rung.Trailing() {
+			continue
+		}
+		if next.IsZero() || l.favourable(next, rung.Target) {
+			next = rung.Target
+		}
+	}
+	return next
+}
+
+// favourable reports whether b is a better exit than a for the position.
+func (l *TPLadder) favourable(a, b decimal.Decimal) bool {
+	if l.Side == types.PositionSideShort {
+		return b.LessThan(a)
+	}
+	return b.GreaterThan(a)
+}
+
+func (l *TPLadder) trailPercent() decimal.Decimal {
+	for _, rung := range l.Rungs {
+		if rung.Trailing() && !rung.Filled {
+			return rung.TrailPercent
+		}
+	}
+	return decimal.Zero
+}
+
+func (l *TPLadder) copy() *TPLadder {
+	ladderCopy := *l
+	ladderCopy.Rungs = append([]TPRung(nil), l.Rungs...)
+	return &ladderCopy
+}
+
+// SetStopLoss records the protective stop of a symbol's position, the
+// distance to which RMultiple rungs are measured in. It has no effect on a
+// position with a ladder attached, whose stop the ladder manages.
+func (om *OrderManager) SetStopLoss(symbol string, stop decimal.Decimal) {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+
+	key := NormalizeSymbol(symbol)
+	if position, ok := om.positions[key]; ok && om.ladders[key] == nil {
+		position.StopLoss = stop
+	}
+}
+
+// AttachTPLadder attaches take-profit targets to a symbol's open position.
+// Rung quantities are their fraction of the current position quantity, and
+// RMultiple rungs need the stop set by SetStopLoss. OnLadderUpdate is then
+// called so the executor's SyncTPLadder can place the child orders.
+func (om *OrderManager) AttachTPLadder(symbol string, rungs []TPRung) error {
+	om.mu.Lock()
+	ladder, err := om.attachTPLadder(symbol, rungs)
+	om.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	om.notifyLadderUpdate(ladder)
+	return nil
+}
+
+// attachTPLadder validates and attaches a ladder, returning a copy of it.
+// Callers must hold om.mu.
+func (om *OrderManager) attachTPLadder(symbol string, rungs []TPRung) (*TPLadder, error) {
+	key := NormalizeSymbol(symbol)
+	position, ok := om.positions[key]
+	if !ok || !position.Quantity.IsPositive() {
+		return nil, fmt.Errorf("no open position for %s", symbol)
+	}
+	if existing := om.ladders[key]; existing != nil {
+		return nil, fmt.Errorf("%s already has a take-profit ladder", symbol)
+	}
+	if len(rungs) == 0 {
+		return nil, fmt.Errorf("take-profit ladder needs at least one rung")
+	}
+
+	ladder := &TPLadder{
+		Symbol:     key,
+		Side:       position.Side,
+		EntryPrice: position.EntryPrice,
+		InitialQty: position.Quantity,
+		Stop:       position.StopLoss,
+		BestPrice:  position.EntryPrice,
+		AttachedAt: time.Now(),
+	}
+	if position.StopLoss.IsPositive() {
+		ladder.RiskPerUnit = position.EntryPrice.Sub(position.StopLoss)
+		if position.Side == types.PositionSideShort {
+			ladder.RiskPerUnit = ladder.RiskPerUnit.Neg()
+		}
+		if !ladder.RiskPerUnit.IsPositive() {
+			return nil, fmt.Errorf("stop %s is not on the losing side of entry %s", position.StopLoss, position.EntryPrice)
+		}
+	}
+
+	total := decimal.Zero
+	trailing := 0
+	for i, rung := range rungs {
+		if !rung.Fraction.IsPositive() {
+			return nil, fmt.Errorf("rung %d: fraction must be positive", i+1)
+		}
+		total = total.Add(rung.Fraction)
+
+		targets := 0
+		for _, set := range []decimal.Decimal{rung.Price, rung.RMultiple, rung.TrailPercent} {
+			if set.IsPositive() {
+				targets++
+			}
+		}
+		if targets != 1 {
+			return nil, fmt.Errorf("rung %d: set exactly one of price, R multiple or trail percent", i+1)
+		}
+
+		switch {
+		case rung.Trailing():
+			trailing++
+			rung.Target = decimal.Zero
+		case rung.RMultiple.IsPositive():
+			if !ladder.RiskPerUnit.IsPositive() {
+				return nil, fmt.Errorf("rung %d: R multiple targets need a stop loss", i+1)
+			}
+			distance := ladder.RiskPerUnit.Mul(rung.RMultiple)
+			rung.Target = ladder.EntryPrice.Add(distance)
+			if ladder.Side == types.PositionSideShort {
+				rung.Target = ladder.EntryPrice.Sub(distance)
+			}
+		default:
+			rung.Target = rung.Price
+			if !ladder.favourable(ladder.EntryPrice, rung.Target) {
+				return nil, fmt.Errorf("rung %d: target %s is not in profit from entry %s", i+1, rung.Target, ladder.EntryPrice)
+			}
+		}
+
+		rung.Quantity = position.Quantity.Mul(rung.Fraction)
+		rung.OrderID = ""
+		rung.FilledQty = decimal.Zero
+		rung.Filled = false
+		ladder.Rungs = append(ladder.Rungs, rung)
+	}
+	if total.GreaterThan(decimal.NewFromInt(1)) {
+		return nil, fmt.Errorf("rung fractions add up to %s, more than the whole position", total)
+	}
+	if trailing > 1 {
+		return nil, fmt.Errorf("take-profit ladder can have only one trailing rung")
+	}
+
+	om.ladders[key] = ladder
+	position.TakeProfit = ladder.nextTarget()
+
+	om.logger.Info("Take-profit ladder attached",
+		zap.String("symbol", key),
+		zap.Int("rungs", len(ladder.Rungs)),
+		zap.String("stop", ladder.Stop.String()))
+	return ladder.copy(), nil
+}
+
+// GetTPLadder returns a copy of a symbol's take-profit ladder, or nil.
+func (om *OrderManager) GetTPLadder(symbol string) *TPLadder {
+	om.mu.RLock()
+	defer om.mu.RUnlock()
+
+	if ladder, ok := om.ladders[NormalizeSymbol(symbol)]; ok {
+		return ladder.copy()
+	}
+	return nil
+}
+
+// LinkTPRung records the order resting at a ladder rung's target, whose
+// fills then count toward the rung.
+func (om *OrderManager) LinkTPRung(symbol string, rung int, orderID string) {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+
+	if ladder, ok := om.ladders[NormalizeSymbol(symbol)]; ok && rung >= 0 && rung < len(ladder.Rungs) {
+		ladder.Rungs[rung].OrderID = orderID
+	}
+}
+
+// LinkTPStop records the stop order protecting a ladder's remaining
+// quantity, and the price and quantity it was placed for.
+func (om *OrderManager) LinkTPStop(symbol, orderID string, price, quantity decimal.Decimal) {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+
+	if ladder, ok := om.ladders[NormalizeSymbol(symbol)]; ok {
+		ladder.StopOrderID = orderID
+		ladder.StopOrderPrice = price
+		ladder.StopOrderQty = quantity
+	}
+}
+
+// applyLadderFill counts a fill toward its rung, moving the stop to
+// breakeven when the first target completes, and closes the ladder once the
+// position is flat. It returns a copy of the ladder if it changed. Callers
+// must hold om.mu.
+func (om *OrderManager) applyLadderFill(order *ManagedOrder, fill OrderFill) *TPLadder {
+	key := NormalizeSymbol(order.Order.Symbol)
+	ladder, ok := om.ladders[key]
+	if !ok {
+		return nil
+	}
+
+	position, open := om.positions[key]
+	if !open {
+		ladder.Closed = true
+		delete(om.ladders, key)
+		om.logger.Info("Take-profit ladder closed",
+			zap.String("symbol", key),
+			zap.Int("filledRungs", ladder.FilledRungs()))
+		return ladder.copy()
+	}
+
+	changed := false
+	for i := range ladder.Rungs {
+		rung := &ladder.Rungs[i]
+		if rung.OrderID == "" || rung.OrderID != fill.OrderID || rung.Filled {
+			continue
+		}
+
+		rung.FilledQty = rung.FilledQty.Add(fill.Quantity)
+		if rung.FilledQty.LessThan(rung.Quantity) {
+			changed = true
+			break
+		}
+		rung.Filled = true
+		changed = true
+
+		om.logger.Info("Take-profit rung filled",
+			zap.String("symbol", key),
+			zap.Int("rung", i+1),
+			zap.String("remaining", position.Quantity.String()))
+
+		// Whatever happens next, the rest of the position can't lose
+		if !ladder.Breakeven {
+			ladder.Breakeven = true
+			if ladder.Stop.IsZero() || ladder.favourable(ladder.Stop, ladder.EntryPrice) {
+				ladder.Stop = ladder.EntryPrice
+			}
+		}
+		break
+	}
+	if !changed {
+		return nil
+	}
+
+	position.StopLoss = ladder.Stop
+	position.TakeProfit = ladder.nextTarget()
+	return ladder.copy()
+}
+
+// trailLadder follows the best price with a ladder's trailing rung once the
+// stop is at breakeven. It returns a copy of the ladder if the stop moved.
+// Callers must hold om.mu.
+func (om *OrderManager) trailLadder(key string, price decimal.Decimal) *TPLadder {
+	ladder, ok := om.ladders[key]
+	if !ok {
+		return nil
+	}
+	if ladder.favourable(ladder.BestPrice, price) {
+		ladder.BestPrice = price
+	}
+
+	trail := ladder.trailPercent()
+	if !ladder.Breakeven || trail.IsZero() {
+		return nil
+	}
+
+	distance := ladder.BestPrice.Mul(trail)
+	stop := ladder.BestPrice.Sub(distance)
+	if ladder.Side == types.PositionSideShort {
+		stop = ladder.BestPrice.Add(distance)
+	}
+	if !ladder.favourable(ladder.Stop, stop) || stop.Sub(ladder.Stop).Abs().LessThan(distance.Mul(ladderTrailStep)) {
+		return nil
+	}
+
+	ladder.Stop = stop
+	if position, ok := om.positions[key]; ok {
+		position.StopLoss = stop
+	}
+	return ladder.copy()
+}
+
+// notifyLadderUpdate invokes OnLadderUpdate. Call without holding om.mu.
+func (om *OrderManager) notifyLadderUpdate(ladder *TPLadder) {
+	if ladder != nil && om.OnLadderUpdate != nil {
+		om.OnLadderUpdate(ladder)
+	}
+}
+
+// withLadder fills in a position copy's ladder progress. Callers must hold
+// om.mu.
+func (om *OrderManager) withLadder(position types.Position) *types.Position {
+	if ladder, ok := om.ladders[NormalizeSymbol(position.Symbol)]; ok {
+		position.TPRungs = len(ladder.Rungs)
+		position.TPRungsFilled = ladder.FilledRungs()
+	}
+	return &position
+}
+
+// SyncTPLadder brings a ladder's child orders in line with it: a take-profit
+// order at each unplaced resting target, and a stop for the remaining
+// quantity at the ladder's stop. Once the ladder is closed, its outstanding
+// orders are cancelled. Paper trading has no resting orders, so nothing is
+// sent.
+func (e *Executor) SyncTPLadder(ctx context.Context, orders *OrderManager, ladder *TPLadder, exchange string) error {
+	if ladder == nil || e.config.PaperTrading {
+		return nil
+	}
+	if !ladder.Closed {
+		// Earlier updates may have placed orders since this copy was taken
+		if current := orders.GetTPLadder(ladder.Symbol); current != nil {
+			ladder = current
+		}
+	}
+
+	adapter, err := e.adapterFor(exchange, ladder.Symbol)
+	if err != nil {
+		return err
+	}
+
+	if ladder.Closed {
+		for _, rung := range ladder.Rungs {
+			if rung.OrderID != "" && !rung.Filled {
+				if err := adapter.CancelOrder(ctx, rung.OrderID); err != nil {
+					e.logger.Warn("Failed to cancel take-profit rung", zap.String("orderId", rung.OrderID), zap.Error(err))
+				}
+			}
+		}
+		if ladder.StopOrderID != "" {
+			if err := adapter.CancelOrder(ctx, ladder.StopOrderID); err != nil {
+				e.logger.Warn("Failed to cancel ladder stop", zap.String("orderId", ladder.StopOrderID), zap.Error(err))
+			}
+		}
+		return nil
+	}
+
+	exitSide := types.OrderSideSell
+	if ladder.Side == types.PositionSideShort {
+		exitSide = types.OrderSideBuy
+	}
+
+	for i, rung := range ladder.Rungs {
+		if rung.Filled || rung.Trailing() || rung.OrderID != "" {
+			continue
+		}
+
+		tpOrder := &types.Order{
+			ID:        fmt.Sprintf("tp%d-%s-%d", i+1, ladder.Symbol, time.Now().UnixNano()),
+			Symbol:    ladder.Symbol,
+			Side:      exitSide,
+			Type:      types.OrderTypeTakeProfit,
+			Quantity:  rung.Quantity,
+			StopPrice: rung.Target,
+			CreatedAt: time.Now(),
+		}
+		if _, err := adapter.PlaceOrder(ctx, tpOrder); err != nil {
+			return fmt.Errorf("failed to place take-profit rung %d: %w", i+1, err)
+		}
+		orders.TrackOrder(tpOrder, adapter.Name(), "")
+		orders.LinkTPRung(ladder.Symbol, i, tpOrder.ID)
+	}
+
+	position := orders.GetPosition(ladder.Symbol)
+	if position == nil || !ladder.Stop.IsPositive() {
+		return nil
+	}
+	if ladder.StopOrderID != "" && ladder.StopOrderPrice.Equal(ladder.Stop) && ladder.StopOrderQty.Equal(position.Quantity) {
+		return nil
+	}
+
+	if ladder.StopOrderID != "" {
+		if err := adapter.CancelOrder(ctx, ladder.StopOrderID); err != nil {
+			return fmt.Errorf("failed to cancel ladder stop: %w", err)
+		}
+	}
+
+	slOrder := &types.Order{
+		ID:        fmt.Sprintf("sl-%s-%d", ladder.Symbol, time.Now().UnixNano()),
+		Symbol:    ladder.Symbol,
+		Side:      exitSide,
+		Type:      types.OrderTypeStopLoss,
+		Quantity:  position.Quantity,
+		StopPrice: ladder.Stop,
+		CreatedAt: time.Now(),
+	}
+	if _, err := adapter.PlaceOrder(ctx, slOrder); err != nil {
+		orders.LinkTPStop(ladder.Symbol, "", decimal.Zero, decimal.Zero)
+		return fmt.Errorf("failed to place ladder stop: %w", err)
+	}
+	orders.TrackOrder(slOrder, adapter.Name(), "")
+	orders.LinkTPStop(ladder.Symbol, slOrder.ID, ladder.Stop, position.Quantity)
+
+	e.logger.Info("Ladder stop placed",
+		zap.String("symbol", ladder.Symbol),
+		zap.String("stop", ladder.Stop.String()),
+		zap.String("quantity", position.Quantity.String()))
+	return nil
+}
@@ -0,0 +1,121 @@
+package execution_test
+
+import (
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func d(v float64) decimal.Decimal {
+	return decimal.NewFromFloat(v)
+}
+
+// fillRung fills quantity of a ladder rung at price through a tracked exit order
+func fillRung(om *execution.OrderManager, symbol string, rung int, price, quantity int64) {
+	id := "tp-" + string(rune('1'+rung))
+	om.TrackOrder(&types.Order{ID: id, Symbol: symbol, Side: types.OrderSideSell, Quantity: decimal.NewFromInt(quantity)}, "paper", "")
+	om.LinkTPRung(symbol, rung, id)
+	om.RecordFill(execution.OrderFill{
+		OrderID:  id,
+		Price:    decimal.NewFromInt(price),
+		Quantity: decimal.NewFromInt(quantity),
+	})
+}
+
+func TestTPLadderScalesOutAndTrails(t *testing.T) {
+	om := execution.NewOrderManager(zap.NewNop())
+	var updates []*execution.TPLadder
+	om.OnLadderUpdate = func(ladder *execution.TPLadder) {
+		updates = append(updates, ladder)
+	}
+
+	openPosition(om, "entry", "BTCUSDT", types.OrderSideBuy, 100, 10)
+	om.SetStopLoss("BTCUSDT", d(95))
+
+	err := om.AttachTPLadder("BTCUSDT", []execution.TPRung{
+		{Fraction: d(0.5), RMultiple: d(1)},
+		{Fraction: d(0.3), RMultiple: d(2)},
+		{Fraction: d(0.2), TrailPercent: d(0.02)},
+	})
+	if err != nil {
+		t.Fatalf("AttachTPLadder: %v", err)
+	}
+
+	ladder := om.GetTPLadder("BTC/USDT")
+	if !ladder.Rungs[0].Target.Equal(d(105)) || !ladder.Rungs[1].Target.Equal(d(110)) || !ladder.Rungs[0].Quantity.Equal(d(5)) {
+		t.Fatalf("Expected 5 at 105 and 3 at 110, got %+v", ladder.Rungs)
+	}
+	if len(updates) != 1 {
+		t.Errorf("Expected attaching to notify, got %d updates", len(updates))
+	}
+
+	// The trail waits for breakeven
+	om.MarkPrice("BTCUSDT", d(104))
+	if len(updates) != 1 {
+		t.Errorf("Expected no trailing before the first target, got %d updates", len(updates))
+	}
+
+	fillRung(om, "BTCUSDT", 0, 105, 5)
+
+	position := om.GetPosition("BTCUSDT")
+	if !position.Quantity.Equal(d(5)) || position.TPRungsFilled != 1 || position.TPRungs != 3 {
+		t.Errorf("Expected 5 remaining with 1 of 3 rungs filled, got %s and %d of %d",
+			position.Quantity, position.TPRungsFilled, position.TPRungs)
+	}
+	if !position.StopLoss.Equal(d(100)) || !position.TakeProfit.Equal(d(110)) {
+		t.Errorf("Expected the stop at breakeven and the next target 110, got %s and %s", position.StopLoss, position.TakeProfit)
+	}
+
+	om.MarkPrice("BTCUSDT", d(120))
+	if stop := om.GetTPLadder("BTCUSDT").Stop; !stop.Equal(d(117.6)) {
+		t.Errorf("Expected the stop to trail 2%% under 120, got %s", stop)
+	}
+
+	// Too small a move to replace the stop order
+	before := len(updates)
+	om.MarkPrice("BTCUSDT", d(120.1))
+	if len(updates) != before {
+		t.Error("Expected a small move not to move the stop")
+	}
+
+	// Selling the rest closes the ladder so its orders are cancelled
+	fillRung(om, "BTCUSDT", 1, 110, 5)
+	last := updates[len(updates)-1]
+	if !last.Closed || om.GetTPLadder("BTCUSDT") != nil || om.GetPosition("BTCUSDT") != nil {
+		t.Errorf("Expected the ladder closed with the position, got %+v", last)
+	}
+}
+
+func TestAttachTPLadderValidatesRungs(t *testing.T) {
+	om := execution.NewOrderManager(zap.NewNop())
+	if err := om.AttachTPLadder("ETHUSDT", []execution.TPRung{{Fraction: d(1), Price: d(2000)}}); err == nil {
+		t.Error("Expected an error without a position")
+	}
+
+	openPosition(om, "short", "ETHUSDT", types.OrderSideSell, 2000, 4)
+
+	for name, rungs := range map[string][]execution.TPRung{
+		"over a whole position": {{Fraction: d(0.6), Price: d(1900)}, {Fraction: d(0.6), Price: d(1800)}},
+		"R without a stop":      {{Fraction: d(0.5), RMultiple: d(1)}},
+		"target at a loss":      {{Fraction: d(0.5), Price: d(2100)}},
+		"two exits on a rung":   {{Fraction: d(0.5), Price: d(1900), TrailPercent: d(0.01)}},
+	} {
+		if err := om.AttachTPLadder("ETHUSDT", rungs); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+
+	om.SetStopLoss("ETHUSDT", d(2050))
+	if err := om.AttachTPLadder("ETHUSDT", []execution.TPRung{{Fraction: d(0.5), RMultiple: d(2)}}); err != nil {
+		t.Fatalf("AttachTPLadder: %v", err)
+	}
+	if target := om.GetTPLadder("ETHUSDT").Rungs[0].Target; !target.Equal(d(1900)) {
+		t.Errorf("Expected a short's 2R target below entry at 1900, got %s", target)
+	}
+	if err := om.AttachTPLadder("ETHUSDT", []execution.TPRung{{Fraction: d(0.5), Price: d(1900)}}); err == nil {
+		t.Error("Expected a second ladder to be rejected")
+	}
+}
@@ -0,0 +1,375 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/fees"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// cancelTimeout bounds cancelling a resting order once the caller's context
+// is done, so passive orders are not left on the book.
+const cancelTimeout = 10 * time.Second
+
+// errOrderUnresolved reports a post-only order that could not be confirmed
+// off the book. It may still fill, so pricing another order over it could
+// fill more than was ordered.
+var errOrderUnresolved = errors.New("post-only order may still be resting")
+
+// LimitOptions configures passive execution with ExecuteLimit.
+type LimitOptions struct {
+	Exchange        string          `json:"exchange,omitempty"` // Adapter to use; empty routes by symbol
+	MaxReprices     int             `json:"maxReprices"`        // Times an unfilled order is moved toward the market
+	RepriceInterval time.Duration   `json:"repriceInterval"`    // How long each price rests before repricing
+	CrossAfter      time.Duration   `json:"crossAfter"`         // Cross the spread with any remainder this long after the first placement; zero never crosses
+	Improve         decimal.Decimal `json:"improve"`            // Fraction of the spread the first order is placed inside the touch
+}
+
+// DefaultLimitOptions returns options that rest at the touch and reprice
+// three times, ten seconds apart, without crossing.
+func DefaultLimitOptions() LimitOptions {
+	return LimitOptions{
+		MaxReprices:     3,
+		RepriceInterval: 10 * time.Second,
+	}
+}
+
+// ExecuteLimit fills order passively with post-only limit orders. The first
+// order rests at the touch, or Improve of the spread inside it; each time it
+// goes unfilled for RepriceInterval the remainder is cancelled and placed
+// again an equal step closer to the far side of the spread, up to
+// MaxReprices times. The last order rests until CrossAfter, when the
+// remainder is sent as a market order, or for one more interval when
+// CrossAfter is zero, after which the remainder is cancelled.
+//
+// A post-only order the venue rejects because the market moved through it
+// counts as an attempt. The result reports every attempt in Fills, the
+// number of reprices, and the average price of what filled; an order that
+// filled nothing is returned with a cancelled status rather than an error.
+// An order that can't be confirmed cancelled aborts the execution with an
+// error instead of being repriced; reconciliation picks up what it fills.
+func (e *Executor) ExecuteLimit(ctx context.Context, order *types.Order, opts LimitOptions) (*ExecutionResult, error) {
+	e.mu.RLock()
+	if e.killSwitch {
+		e.mu.RUnlock()
+		return nil, fmt.Errorf("kill switch activated, trading disabled")
+	}
+	if !e.isActive {
+		e.mu.RUnlock()
+		return nil, fmt.Errorf("executor is not active")
+	}
+	e.mu.RUnlock()
+
+	if !order.Quantity.IsPositive() {
+		return nil, fmt.Errorf("invalid order quantity: %s", order.Quantity)
+	}
+	if opts.MaxReprices < 0 || opts.RepriceInterval <= 0 {
+		return nil, fmt.Errorf("invalid limit options: %d reprices every %s", opts.MaxReprices, opts.RepriceInterval)
+	}
+	if opts.Improve.IsNegative() || opts.Improve.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return nil, fmt.Errorf("improve must be in [0, 1), got %s", opts.Improve)
+	}
+
+	adapter, err := e.adapterFor(opts.Exchange, order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now()
+	var crossAt time.Time
+	if opts.CrossAfter > 0 {
+		crossAt = startTime.Add(opts.CrossAfter)
+	}
+
+	result := &ExecutionResult{
+		OrderID:  order.ID,
+		Order:    order,
+		Exchange: adapter.Name(),
+		IsPaper:  e.config.PaperTrading,
+	}
+	remaining := order.Quantity
+	var arrivalPrice decimal.Decimal
+	var lastErr error
+
+	for step := 0; step <= opts.MaxReprices && remaining.IsPositive(); step++ {
+		ticker, err := adapter.GetTicker(ctx, order.Symbol)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to get quote: %w", err)
+			break
+		}
+		if step == 0 {
+			arrivalPrice = ticker.LastPrice
+		}
+		if !ticker.BidPrice.IsPositive() || !ticker.AskPrice.IsPositive() {
+			lastErr = fmt.Errorf("no quote for %s", order.Symbol)
+			break
+		}
+
+		leg := *order
+		leg.ID = fmt.Sprintf("%s-%d", order.ID, step+1)
+		leg.Type = types.OrderTypeLimit
+		leg.PostOnly = true
+		leg.Quantity = remaining
+		leg.Price = passivePrice(order.Side, ticker.BidPrice, ticker.AskPrice, repriceFraction(opts, step))
+		if step > 0 {
+			result.Reprices++
+		}
+
+		// The last order rests until it is time to cross
+		wait := opts.RepriceInterval
+		if !crossAt.IsZero() && (step == opts.MaxReprices || time.Until(crossAt) < wait) {
+			wait = time.Until(crossAt)
+		}
+
+		fill, err := e.restLimit(ctx, adapter, &leg, wait)
+		if errors.Is(err, errOrderUnresolved) {
+			e.updateMetrics(false, decimal.Zero, time.Since(startTime))
+			return nil, fmt.Errorf("limit execution aborted: %w", err)
+		}
+		if err != nil {
+			lastErr = err
+			e.logger.Warn("Post-only order failed",
+				zap.String("orderId", leg.ID),
+				zap.String("exchange", adapter.Name()),
+				zap.Error(err))
+		}
+		if fill != nil {
+			result.Fills = append(result.Fills, *fill)
+			remaining = remaining.Sub(fill.FilledQty)
+		}
+		if ctx.Err() != nil || (!crossAt.IsZero() && !time.Now().Before(crossAt)) {
+			break
+		}
+	}
+
+	// Cross the spread with whatever is left
+	if remaining.IsPositive() && !crossAt.IsZero() && ctx.Err() == nil {
+		fill, err := e.crossLimit(ctx, adapter, order, remaining)
+		if err != nil {
+			lastErr = err
+		} else {
+			result.Fills = append(result.Fills, *fill)
+			remaining = remaining.Sub(fill.FilledQty)
+		}
+	}
+
+	notional := decimal.Zero
+	for _, fill := range result.Fills {
+		result.FilledQty = result.FilledQty.Add(fill.FilledQty)
+		result.Commission = result.Commission.Add(fill.Commission)
+		notional = notional.Add(fill.FilledQty.Mul(fill.AvgPrice))
+	}
+
+	if !result.FilledQty.IsPositive() && lastErr != nil {
+		e.updateMetrics(false, decimal.Zero, time.Since(startTime))
+		return nil, fmt.Errorf("limit execution failed: %w", lastErr)
+	}
+
+	switch {
+	case !remaining.IsPositive():
+		result.Status = string(types.OrderStatusFilled)
+	case result.FilledQty.IsPositive():
+		result.Status = string(types.OrderStatusPartiallyFilled)
+	default:
+		result.Status = string(types.OrderStatusCancelled)
+	}
+	if result.FilledQty.IsPositive() {
+		result.AvgPrice = notional.Div(result.FilledQty)
+		if arrivalPrice.IsPositive() {
+			result.Slippage = result.AvgPrice.Sub(arrivalPrice).Abs().Div(arrivalPrice)
+		}
+		e.updateMetrics(true, result.Slippage, time.Since(startTime))
+	}
+	result.Latency = time.Since(startTime)
+	result.Timestamp = time.Now()
+
+	e.logger.Info("Limit order executed",
+		zap.String("orderId", order.ID),
+		zap.String("symbol", order.Symbol),
+		zap.String("side", string(order.Side)),
+		zap.String("qty", order.Quantity.String()),
+		zap.String("filled", result.FilledQty.String()),
+		zap.String("price", result.AvgPrice.String()),
+		zap.Int("reprices", result.Reprices),
+		zap.Bool("paper", result.IsPaper))
+
+	return result, nil
+}
+
+// repriceFraction returns how far across the spread, from the near touch, the
+// order at step is priced: Improve at first, then evenly stepping toward the
+// far side without reaching it.
+func repriceFraction(opts LimitOptions, step int) decimal.Decimal {
+	one := decimal.NewFromInt(1)
+	progress := decimal.NewFromInt(int64(step)).Div(decimal.NewFromInt(int64(opts.MaxReprices + 1)))
+	return opts.Improve.Add(one.Sub(opts.Improve).Mul(progress))
+}
+
+// passivePrice returns the price fraction of the spread inside the touch on
+// side's own side of the book.
+func passivePrice(side types.OrderSide, bid, ask, fraction decimal.Decimal) decimal.Decimal {
+	spread := ask.Sub(bid)
+	if side == types.OrderSideBuy {
+		return bid.Add(spread.Mul(fraction))
+	}
+	return ask.Sub(spread.Mul(fraction))
+}
+
+// restLimit rests a post-only order for wait, then cancels what is left of it
+// and returns what filled. Paper orders fill in full when the far touch
+// trades through their price by the end of the wait; with SimPartialFills,
+// one that only reaches their price fills them behind the queue they joined.
+func (e *Executor) restLimit(ctx context.Context, adapter ExchangeAdapter, leg *types.Order, wait time.Duration) (*VenueFill, error) {
+	if e.config.PaperTrading {
+		joined, hasBook := e.paperBook(ctx, adapter, leg)
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+		if hasBook {
+			own := joined.Bids
+			if leg.Side == types.OrderSideSell {
+				own = joined.Asks
+			}
+			if book, ok := e.paperBook(ctx, adapter, leg); ok {
+				return e.restPaperLimit(leg, adapter.Name(), book, queuedAt(own, leg.Price)), nil
+			}
+		}
+		ticker, err := adapter.GetTicker(ctx, leg.Symbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get quote: %w", err)
+		}
+
+		fill := &VenueFill{
+			Exchange:  adapter.Name(),
+			OrderID:   leg.ID,
+			Quantity:  leg.Quantity,
+			Status:    string(types.OrderStatusCancelled),
+			Liquidity: fees.LiquidityMaker,
+		}
+		crossed := (leg.Side == types.OrderSideBuy && ticker.AskPrice.IsPositive() && ticker.AskPrice.LessThanOrEqual(leg.Price)) ||
+			(leg.Side == types.OrderSideSell && ticker.BidPrice.GreaterThanOrEqual(leg.Price))
+		if crossed {
+			fee := e.feeModel().Fee(adapter.Name(), fees.LiquidityMaker, leg.Quantity, leg.Price)
+			fill.Status = string(types.OrderStatusFilled)
+			fill.FilledQty = leg.Quantity
+			fill.AvgPrice = leg.Price
+			fill.Commission = fee.Quote
+			fill.CommissionAsset = fee.Asset
+		}
+		return fill, nil
+	}
+
+	placed, err := adapter.PlaceOrder(ctx, leg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place post-only order: %w", err)
+	}
+
+	waitErr := sleepContext(ctx, wait)
+
+	// Cancel and re-read the order even when ctx is done, so nothing is left
+	// resting and fills made meanwhile are counted
+	cancelCtx, cancel := context.WithTimeout(context.Background(), cancelTimeout)
+	defer cancel()
+
+	final, err := adapter.GetOrder(cancelCtx, placed.ID)
+	if err != nil || final.Status != types.OrderStatusFilled {
+		cancelErr := adapter.CancelOrder(cancelCtx, placed.ID)
+		if final, err = adapter.GetOrder(cancelCtx, placed.ID); err != nil {
+			return nil, fmt.Errorf("%w: failed to get order %s: %v", errOrderUnresolved, placed.ID, err)
+		}
+		if cancelErr != nil {
+			if restingStatus(final.Status) {
+				return nil, fmt.Errorf("%w: failed to cancel order %s: %v", errOrderUnresolved, placed.ID, cancelErr)
+			}
+			// The order filled or closed before the cancel reached it
+			e.logger.Warn("Failed to cancel post-only order",
+				zap.String("orderId", placed.ID),
+				zap.String("status", string(final.Status)),
+				zap.Error(cancelErr))
+		}
+	}
+
+	// Limit fills are at the order's price unless the venue reports better
+	if final.AvgFillPrice.IsZero() {
+		final.AvgFillPrice = placed.Price
+	}
+	fill := e.liveFill(adapter.Name(), leg, final, decimal.Zero)
+	return &fill, waitErr
+}
+
+// restingStatus reports whether an order with status can still fill.
+func restingStatus(status types.OrderStatus) bool {
+	switch status {
+	case types.OrderStatusPending, types.OrderStatusOpen, types.OrderStatusPartiallyFilled, types.OrderStatusPartial:
+		return true
+	}
+	return false
+}
+
+// crossLimit sends the remainder of a passive order as a market order.
+func (e *Executor) crossLimit(ctx context.Context, adapter ExchangeAdapter, order *types.Order, remaining decimal.Decimal) (*VenueFill, error) {
+	cross := *order
+	cross.ID = order.ID + "-cross"
+	cross.ClientOrderID = ""
+	cross.Type = types.OrderTypeMarket
+	cross.PostOnly = false
+	cross.Price = decimal.Zero
+	cross.Quantity = remaining
+
+	if e.config.PaperTrading {
+		if book, ok := e.paperBook(ctx, adapter, &cross); ok {
+			fill := e.walkBook(adapter.Name(), &cross, book)
+			return &fill, nil
+		}
+		ticker, err := adapter.GetTicker(ctx, order.Symbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get quote: %w", err)
+		}
+		price := ticker.AskPrice
+		if order.Side == types.OrderSideSell {
+			price = ticker.BidPrice
+		}
+
+		fillPrice, fee, _ := e.simulateFill(&cross, adapter.Name(), price)
+		return &VenueFill{
+			Exchange:        adapter.Name(),
+			OrderID:         cross.ID,
+			Quantity:        remaining,
+			Status:          string(types.OrderStatusFilled),
+			FilledQty:       remaining,
+			AvgPrice:        fillPrice,
+			Commission:      fee.Quote,
+			CommissionAsset: fee.Asset,
+			Liquidity:       fee.Liquidity,
+		}, nil
+	}
+
+	result, err := e.placeWithRetries(ctx, adapter, &cross)
+	if err != nil {
+		return nil, err
+	}
+	fill := e.liveFill(adapter.Name(), &cross, result, decimal.Zero)
+	return &fill, nil
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
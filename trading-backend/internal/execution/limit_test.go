@@ -0,0 +1,218 @@
+package execution_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/internal/fees"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// passiveVenue quotes a fixed spread and fills buy limits priced at or above
+// fillAt, and market orders at the ask.
+type passiveVenue struct {
+	stubExchange
+	bid, ask float64
+	fillAt   float64
+	placed   []types.Order
+}
+
+func newPassiveVenue(bid, ask, fillAt float64) *passiveVenue {
+	return &passiveVenue{
+		stubExchange: stubExchange{orders: make(map[string]*types.Order)},
+		bid:          bid,
+		ask:          ask,
+		fillAt:       fillAt,
+	}
+}
+
+func (v *passiveVenue) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	v.placed = append(v.placed, *order)
+
+	placed := *order
+	placed.Status = types.OrderStatusOpen
+	switch {
+	case order.Type == types.OrderTypeMarket:
+		placed.Status = types.OrderStatusFilled
+		placed.FilledQty = order.Quantity
+		placed.AvgFillPrice = decimal.NewFromFloat(v.ask)
+	case v.fillAt > 0 && order.Price.GreaterThanOrEqual(decimal.NewFromFloat(v.fillAt)):
+		placed.Status = types.OrderStatusFilled
+		placed.FilledQty = order.Quantity
+	}
+	v.orders[placed.ID] = &placed
+	return &placed, nil
+}
+
+func (v *passiveVenue) CancelOrder(ctx context.Context, orderID string) error {
+	if order, ok := v.orders[orderID]; ok && order.Status == types.OrderStatusOpen {
+		order.Status = types.OrderStatusCancelled
+	}
+	return nil
+}
+
+func (v *passiveVenue) GetTicker(ctx context.Context, symbol string) (*adapters.Ticker, error) {
+	return &adapters.Ticker{
+		Symbol:    symbol,
+		LastPrice: decimal.NewFromFloat(v.bid),
+		BidPrice:  decimal.NewFromFloat(v.bid),
+		AskPrice:  decimal.NewFromFloat(v.ask),
+	}, nil
+}
+
+func newLimitExecutor(v execution.ExchangeAdapter, paper bool) *execution.Executor {
+	config := execution.DefaultExecutorConfig()
+	config.PaperTrading = paper
+	config.RetryAttempts = 1
+
+	e := execution.NewExecutor(zap.NewNop(), config)
+	e.AddAdapter(v)
+	e.SetDefaultAdapter(v)
+	return e
+}
+
+func limitBuy(quantity float64) *types.Order {
+	return &types.Order{
+		ID:       "dca-1",
+		Symbol:   "BTC/USDT",
+		Side:     types.OrderSideBuy,
+		Quantity: decimal.NewFromFloat(quantity),
+	}
+}
+
+func TestExecuteLimitRepricesTowardTheMarket(t *testing.T) {
+	v := newPassiveVenue(100, 101, 100.5)
+	e := newLimitExecutor(v, false)
+
+	opts := execution.LimitOptions{MaxReprices: 3, RepriceInterval: time.Millisecond}
+	result, err := e.ExecuteLimit(context.Background(), limitBuy(2), opts)
+	if err != nil {
+		t.Fatalf("ExecuteLimit failed: %v", err)
+	}
+
+	// 100, 100.25, then 100.5 fills
+	if len(v.placed) != 3 || result.Reprices != 2 {
+		t.Fatalf("Expected 3 orders and 2 reprices, got %d and %d", len(v.placed), result.Reprices)
+	}
+	for i, order := range v.placed {
+		if !order.PostOnly || order.Type != types.OrderTypeLimit {
+			t.Errorf("Order %d was not a post-only limit: %+v", i, order)
+		}
+	}
+	if !v.placed[1].Price.Equal(decimal.NewFromFloat(100.25)) {
+		t.Errorf("Expected the first reprice at 100.25, got %s", v.placed[1].Price)
+	}
+
+	if result.Status != string(types.OrderStatusFilled) || !result.AvgPrice.Equal(decimal.NewFromFloat(100.5)) {
+		t.Errorf("Expected a fill at 100.5, got %s at %s", result.Status, result.AvgPrice)
+	}
+	last := result.Fills[len(result.Fills)-1]
+	if last.Liquidity != fees.LiquidityMaker || !last.Commission.Equal(decimal.NewFromFloat(0.201)) {
+		t.Errorf("Expected a 0.201 maker fee, got %s %s", last.Commission, last.Liquidity)
+	}
+	if v.orders["dca-1-1"].Status != types.OrderStatusCancelled {
+		t.Errorf("Expected the unfilled order to be cancelled, got %s", v.orders["dca-1-1"].Status)
+	}
+}
+
+func TestExecuteLimitCrossesAfterRepricesRunOut(t *testing.T) {
+	v := newPassiveVenue(100, 101, 0)
+	e := newLimitExecutor(v, false)
+
+	opts := execution.LimitOptions{
+		MaxReprices:     1,
+		RepriceInterval: time.Millisecond,
+		CrossAfter:      5 * time.Millisecond,
+	}
+	result, err := e.ExecuteLimit(context.Background(), limitBuy(1), opts)
+	if err != nil {
+		t.Fatalf("ExecuteLimit failed: %v", err)
+	}
+
+	if len(result.Fills) != 3 || result.Reprices != 1 {
+		t.Fatalf("Expected two passive attempts and a cross, got %d fills and %d reprices", len(result.Fills), result.Reprices)
+	}
+	cross := result.Fills[2]
+	if v.placed[2].Type != types.OrderTypeMarket || cross.Liquidity != fees.LiquidityTaker {
+		t.Errorf("Expected the remainder to cross as a taker, got %s %s", v.placed[2].Type, cross.Liquidity)
+	}
+	if result.Status != string(types.OrderStatusFilled) || !result.AvgPrice.Equal(decimal.NewFromInt(101)) {
+		t.Errorf("Expected a fill at the ask, got %s at %s", result.Status, result.AvgPrice)
+	}
+}
+
+func TestExecuteLimitPaperLeavesUnfilledOrdersCancelled(t *testing.T) {
+	v := newPassiveVenue(100, 101, 0)
+	e := newLimitExecutor(v, true)
+
+	opts := execution.LimitOptions{
+		MaxReprices:     2,
+		RepriceInterval: time.Millisecond,
+		Improve:         decimal.NewFromFloat(0.2),
+	}
+	result, err := e.ExecuteLimit(context.Background(), limitBuy(1), opts)
+	if err != nil {
+		t.Fatalf("ExecuteLimit failed: %v", err)
+	}
+
+	if len(v.placed) != 0 {
+		t.Errorf("Expected paper orders to stay off the venue, got %d", len(v.placed))
+	}
+	if result.Status != string(types.OrderStatusCancelled) || !result.FilledQty.IsZero() || result.Reprices != 2 {
+		t.Errorf("Expected nothing filled after 2 reprices, got %s %s after %d", result.Status, result.FilledQty, result.Reprices)
+	}
+
+	if _, err := e.ExecuteLimit(context.Background(), limitBuy(1), execution.LimitOptions{MaxReprices: 1}); err == nil {
+		t.Error("Expected a zero reprice interval to be rejected")
+	}
+}
+
+// stuckVenue fails to cancel orders, leaving them open unless fillOnCancel
+// fills them first.
+type stuckVenue struct {
+	*passiveVenue
+	fillOnCancel bool
+}
+
+func (v *stuckVenue) CancelOrder(ctx context.Context, orderID string) error {
+	if order, ok := v.orders[orderID]; ok && v.fillOnCancel {
+		order.Status = types.OrderStatusFilled
+		order.FilledQty = order.Quantity
+	}
+	return fmt.Errorf("cancel rejected")
+}
+
+func TestExecuteLimitAbortsWhenCancelFails(t *testing.T) {
+	v := &stuckVenue{passiveVenue: newPassiveVenue(100, 101, 0)}
+	e := newLimitExecutor(v, false)
+
+	opts := execution.LimitOptions{
+		MaxReprices:     2,
+		RepriceInterval: time.Millisecond,
+		CrossAfter:      5 * time.Millisecond,
+	}
+	if _, err := e.ExecuteLimit(context.Background(), limitBuy(1), opts); err == nil {
+		t.Fatal("Expected an error while the order may still be resting")
+	}
+	if len(v.placed) != 1 {
+		t.Errorf("Expected no reprice or cross over the resting order, got %d orders", len(v.placed))
+	}
+
+	// An order that filled before the cancel reached it is not resting
+	v = &stuckVenue{passiveVenue: newPassiveVenue(100, 101, 0), fillOnCancel: true}
+	e = newLimitExecutor(v, false)
+
+	result, err := e.ExecuteLimit(context.Background(), limitBuy(1), opts)
+	if err != nil {
+		t.Fatalf("ExecuteLimit failed: %v", err)
+	}
+	if len(v.placed) != 1 || result.Status != string(types.OrderStatusFilled) {
+		t.Errorf("Expected the first order filled, got %s after %d orders", result.Status, len(v.placed))
+	}
+}
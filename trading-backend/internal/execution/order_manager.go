@@ -3,9 +3,11 @@ package execution
 
 import (
 	"context"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
 	"github.com/atlas-desktop/trading-backend/pkg/types"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
@@ -15,12 +17,24 @@ import (
 type OrderManager struct {
 	logger       *zap.Logger
 	orders       map[string]*ManagedOrder
-	positions    map[string]*types.Position
+	positions    map[string]*types.Position // By normalized symbol
+	ladders      map[string]*TPLadder       // Take-profit ladders by normalized symbol
 	mu           sync.RWMutex
 	
 	// Event channels
 	orderUpdates chan OrderUpdate
 	fills        chan OrderFill
+	
+	// OnOrderUpdate is called after an order's status or fills change
+	OnOrderUpdate func(order *ManagedOrder)
+	
+	// OnDivergence is called for each difference from the exchanges that
+	// Reconcile corrects
+	OnDivergence func(d Divergence)
+	
+	// OnLadderUpdate is called with a copy of a take-profit ladder after a
+	// rung fills, its stop moves, or its position closes
+	OnLadderUpdate func(ladder *TPLadder)
 }
 
 // ManagedOrder wraps an order with management state.
@@ -82,6 +96,7 @@ func NewOrderManager(logger *zap.Logger) *OrderManager {
 		logger:       logger.Named("order-manager"),
 		orders:       make(map[string]*ManagedOrder),
 		positions:    make(map[string]*types.Position),
+		ladders:      make(map[string]*TPLadder),
 		orderUpdates: make(chan OrderUpdate, 1000),
 		fills:        make(chan OrderFill, 1000),
 	}
@@ -114,11 +129,18 @@ func (om *OrderManager) TrackOrder(order *types.Order, exchange string, signalID
 // UpdateOrderStatus updates an order's status.
 func (om *OrderManager) UpdateOrderStatus(orderID string, status OrderStatus, message string) {
 	om.mu.Lock()
-	defer om.mu.Unlock()
+	order, ok := om.updateOrderStatusLocked(orderID, status, message)
+	om.mu.Unlock()
 	
+	if ok {
+		om.notifyOrderUpdate(order)
+	}
+}
+
+func (om *OrderManager) updateOrderStatusLocked(orderID string, status OrderStatus, message string) (*ManagedOrder, bool) {
 	order, ok := om.orders[orderID]
 	if !ok {
-		return
+		return nil, false
 	}
 	
 	order.Status = status
@@ -135,15 +157,60 @@ func (om *OrderManager) UpdateOrderStatus(orderID string, status OrderStatus, me
 	default:
 		om.logger.Warn("Order update channel full")
 	}
+	
+	return order, true
+}
+
+// notifyOrderUpdate invokes OnOrderUpdate. Call without holding om.mu.
+func (om *OrderManager) notifyOrderUpdate(order *ManagedOrder) {
+	if om.OnOrderUpdate != nil {
+		om.OnOrderUpdate(order)
+	}
+}
+
+// ApplyExchangeUpdate applies a pushed order update from an exchange stream,
+// recording the fill if there is one.
+func (om *OrderManager) ApplyExchangeUpdate(order *types.Order, fill *OrderFill) {
+	if fill != nil {
+		fill.OrderID = order.ID
+		om.RecordFill(*fill)
+		return
+	}
+	
+	om.UpdateOrderStatus(order.ID, orderStatusFromExchange(order.Status), "updated from exchange stream")
+}
+
+// BinanceOrderUpdates returns a Binance user data stream callback that
+// applies order updates to om.
+func BinanceOrderUpdates(om *OrderManager) func(update *adapters.BinanceOrderUpdate) {
+	return func(update *adapters.BinanceOrderUpdate) {
+		var fill *OrderFill
+		if update.Fill != nil {
+			fill = &OrderFill{
+				TradeID:    strconv.FormatInt(update.Fill.TradeID, 10),
+				Price:      update.Fill.Price,
+				Quantity:   update.Fill.Quantity,
+				Commission: update.Fill.Commission,
+				Timestamp:  update.Fill.Time,
+			}
+		}
+		om.ApplyExchangeUpdate(update.Order, fill)
+	}
 }
 
 // RecordFill records a fill for an order.
 func (om *OrderManager) RecordFill(fill OrderFill) {
 	om.mu.Lock()
-	defer om.mu.Unlock()
-	
 	order, ok := om.orders[fill.OrderID]
 	if !ok {
+		om.mu.Unlock()
+		return
+	}
+	
+	// A fill on a complete order repeats one already recorded, as when an
+	// order is tracked after it executed
+	if order.FilledQty.IsPositive() && order.FilledQty.GreaterThanOrEqual(order.Order.Quantity) {
+		om.mu.Unlock()
 		return
 	}
 	
@@ -173,6 +240,7 @@ func (om *OrderManager) RecordFill(fill OrderFill) {
 	
 	// Update position
 	om.updatePosition(order, fill)
+	ladder := om.applyLadderFill(order, fill)
 	
 	// Send fill notification
 	select {
@@ -180,45 +248,69 @@ func (om *OrderManager) RecordFill(fill OrderFill) {
 	default:
 		om.logger.Warn("Fill channel full")
 	}
+	om.mu.Unlock()
+	
+	om.notifyOrderUpdate(order)
+	om.notifyLadderUpdate(ladder)
 }
 
-// updatePosition updates the position based on a fill.
+// updatePosition updates the position based on a fill. Positions are keyed by
+// normalized symbol so fills reported as "BTC/USDT" and "BTCUSDT" build one
+// position with one average entry.
 func (om *OrderManager) updatePosition(order *ManagedOrder, fill OrderFill) {
-	symbol := order.Order.Symbol
-	position, exists := om.positions[symbol]
+	applyFill(om.positions, NormalizeSymbol(order.Order.Symbol), order.Order.Side, fill)
+}
+
+// applyFill applies a fill for an order on symbol to a position map, opening,
+// growing, reducing or removing the symbol's position. Reducing fills add to
+// the position's RealizedPnL; the position is returned once fully closed.
+// A position of unknown cost keeps no entry price and realizes no PnL.
+func applyFill(positions map[string]*types.Position, symbol string, side types.OrderSide, fill OrderFill) *types.Position {
+	position, exists := positions[symbol]
 	
 	if !exists {
 		// Create new position
-		side := types.PositionSideLong
-		if order.Order.Side == types.OrderSideSell {
-			side = types.PositionSideShort
+		positionSide := types.PositionSideLong
+		if side == types.OrderSideSell {
+			positionSide = types.PositionSideShort
+		}
+		
+		// The position was entered when its first fill executed
+		openedAt := fill.Timestamp
+		if openedAt.IsZero() {
+			openedAt = time.Now()
 		}
 		
 		position = &types.Position{
 			Symbol:       symbol,
-			Side:         side,
+			Side:         positionSide,
 			Quantity:     decimal.Zero,
 			EntryPrice:   decimal.Zero,
 			CurrentPrice: fill.Price,
-			OpenedAt:     time.Now(),
+			OpenedAt:     openedAt,
 		}
-		om.positions[symbol] = position
+		positions[symbol] = position
 	}
 	
 	// Update position quantity and entry price
-	if order.Order.Side == types.OrderSideBuy {
+	if side == types.OrderSideBuy {
 		if position.Side == types.PositionSideLong {
 			// Adding to long position
 			totalValue := position.EntryPrice.Mul(position.Quantity).Add(fill.Price.Mul(fill.Quantity))
 			position.Quantity = position.Quantity.Add(fill.Quantity)
-			if !position.Quantity.IsZero() {
+			if !position.Quantity.IsZero() && !position.CostUnknown {
 				position.EntryPrice = totalValue.Div(position.Quantity)
 			}
 		} else {
 			// Closing short position
+			closed := decimal.Min(fill.Quantity, position.Quantity)
+			if !position.CostUnknown {
+				position.RealizedPnL = position.RealizedPnL.Add(position.EntryPrice.Sub(fill.Price).Mul(closed))
+			}
 			position.Quantity = position.Quantity.Sub(fill.Quantity)
 			if position.Quantity.LessThanOrEqual(decimal.Zero) {
-				delete(om.positions, symbol)
+				delete(positions, symbol)
+				return position
 			}
 		}
 	} else { // Sell
@@ -226,17 +318,24 @@ func (om *OrderManager) updatePosition(order *ManagedOrder, fill OrderFill) {
 			// Adding to short position
 			totalValue := position.EntryPrice.Mul(position.Quantity).Add(fill.Price.Mul(fill.Quantity))
 			position.Quantity = position.Quantity.Add(fill.Quantity)
-			if !position.Quantity.IsZero() {
+			if !position.Quantity.IsZero() && !position.CostUnknown {
 				position.EntryPrice = totalValue.Div(position.Quantity)
 			}
 		} else {
 			// Closing long position
+			closed := decimal.Min(fill.Quantity, position.Quantity)
+			if !position.CostUnknown {
+				position.RealizedPnL = position.RealizedPnL.Add(fill.Price.Sub(position.EntryPrice).Mul(closed))
+			}
 			position.Quantity = position.Quantity.Sub(fill.Quantity)
 			if position.Quantity.LessThanOrEqual(decimal.Zero) {
-				delete(om.positions, symbol)
+				delete(positions, symbol)
+				return position
 			}
 		}
 	}
+	
+	return nil
 }
 
 // GetOrder returns a managed order by ID.
@@ -275,15 +374,16 @@ func (om *OrderManager) GetOrdersBySymbol(symbol string) []*ManagedOrder {
 	return orders
 }
 
-// GetPosition returns the position for a symbol.
+// GetPosition returns the position for a symbol, with its average entry price
+// and remaining quantity across all fills, its time in trade, and the
+// progress of its take-profit ladder if one is attached.
 func (om *OrderManager) GetPosition(symbol string) *types.Position {
 	om.mu.RLock()
 	defer om.mu.RUnlock()
 	
-	if pos, ok := om.positions[symbol]; ok {
+	if pos, ok := om.positions[NormalizeSymbol(symbol)]; ok {
 		// Return copy
-		posCopy := *pos
-		return &posCopy
+		return om.positionStatus(*pos)
 	}
 	return nil
 }
@@ -295,12 +395,18 @@ func (om *OrderManager) GetAllPositions() []*types.Position {
 	
 	positions := make([]*types.Position, 0, len(om.positions))
 	for _, pos := range om.positions {
-		posCopy := *pos
-		positions = append(positions, &posCopy)
+		positions = append(positions, om.positionStatus(*pos))
 	}
 	return positions
 }
 
+// positionStatus fills in a position copy's time in trade and ladder
+// progress. Callers must hold om.mu.
+func (om *OrderManager) positionStatus(position types.Position) *types.Position {
+	position.TimeInTrade = time.Since(position.OpenedAt)
+	return om.withLadder(position)
+}
+
 // OrderUpdates returns the order update channel.
 func (om *OrderManager) OrderUpdates() <-chan OrderUpdate {
 	return om.orderUpdates
@@ -0,0 +1,82 @@
+package execution_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func TestOrderManagerPositionTimeInTrade(t *testing.T) {
+	om := execution.NewOrderManager(zap.NewNop())
+	entered := time.Now().Add(-90 * time.Minute)
+
+	fill := func(id string, at time.Time) {
+		om.TrackOrder(&types.Order{ID: id, Symbol: "BTC/USDT", Side: types.OrderSideBuy, Quantity: decimal.NewFromInt(1)}, "paper", "")
+		om.RecordFill(execution.OrderFill{
+			OrderID:   id,
+			Price:     decimal.NewFromInt(100),
+			Quantity:  decimal.NewFromInt(1),
+			Timestamp: at,
+		})
+	}
+	fill("entry", entered)
+	// Adding to the position keeps its entry time
+	fill("add", time.Now())
+
+	position := om.GetPosition("BTCUSDT")
+	if position == nil {
+		t.Fatal("Expected an open position")
+	}
+	if !position.OpenedAt.Equal(entered) {
+		t.Errorf("Expected the position opened at its first fill %v, got %v", entered, position.OpenedAt)
+	}
+	if position.TimeInTrade < 90*time.Minute || position.TimeInTrade > 91*time.Minute {
+		t.Errorf("Expected about 90m in trade, got %v", position.TimeInTrade)
+	}
+
+	all := om.GetAllPositions()
+	if len(all) != 1 || all[0].TimeInTrade < 90*time.Minute {
+		t.Errorf("Expected time in trade on every position, got %+v", all)
+	}
+}
+
+func TestBinanceOrderUpdatesApplyToOrderManager(t *testing.T) {
+	om := execution.NewOrderManager(zap.NewNop())
+	order := &types.Order{ID: "BTCUSDT:42", Symbol: "BTC/USDT", Side: types.OrderSideBuy, Quantity: decimal.NewFromInt(2)}
+	om.TrackOrder(order, "binance", "")
+	apply := execution.BinanceOrderUpdates(om)
+
+	filledAt := time.Now()
+	apply(&adapters.BinanceOrderUpdate{
+		Order:         &types.Order{ID: "BTCUSDT:42", Status: types.OrderStatusPartiallyFilled},
+		ExecutionType: "TRADE",
+		Fill: &adapters.BinanceFill{
+			TradeID:    7,
+			Price:      decimal.NewFromInt(100),
+			Quantity:   decimal.NewFromInt(1),
+			Commission: decimal.NewFromFloat(0.1),
+			Time:       filledAt,
+		},
+	})
+
+	managed := om.GetOrder("BTCUSDT:42")
+	if !managed.FilledQty.Equal(decimal.NewFromInt(1)) || !managed.AvgFillPrice.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected 1 filled at 100, got %s at %s", managed.FilledQty, managed.AvgFillPrice)
+	}
+	if len(managed.Fills) != 1 || managed.Fills[0].TradeID != "7" || !managed.Fills[0].Timestamp.Equal(filledAt) {
+		t.Errorf("Expected trade 7 recorded, got %+v", managed.Fills)
+	}
+
+	apply(&adapters.BinanceOrderUpdate{
+		Order:         &types.Order{ID: "BTCUSDT:42", Status: types.OrderStatusCancelled},
+		ExecutionType: "CANCELED",
+	})
+	if managed := om.GetOrder("BTCUSDT:42"); managed.Status != execution.OrderStatusCancelled {
+		t.Errorf("Expected the order cancelled, got %s", managed.Status)
+	}
+}
@@ -0,0 +1,192 @@
+package execution
+
+import (
+	"context"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/fees"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// simulatePaper fills a paper order the way the venue would: after
+// SimLatency, at the price the market has moved to by then, and, with
+// SimPartialFills, only as far as the order book's depth allows. Without a
+// usable book the order fills in full at the configured slippage.
+func (e *Executor) simulatePaper(ctx context.Context, adapter ExchangeAdapter, order *types.Order, currentPrice decimal.Decimal, startTime time.Time) (*ExecutionResult, error) {
+	if e.config.SimLatency > 0 {
+		if err := sleepContext(ctx, e.config.SimLatency); err != nil {
+			return nil, err
+		}
+		if price, err := e.currentPrice(ctx, adapter, order.Symbol); err == nil && price.IsPositive() {
+			currentPrice = price
+		}
+	}
+
+	book, ok := e.paperBook(ctx, adapter, order)
+	if !ok {
+		return e.simulateExecution(order, adapter.Name(), currentPrice, startTime)
+	}
+
+	fill := e.walkBook(adapter.Name(), order, book)
+	slippage := decimal.Zero
+	if fill.FilledQty.IsPositive() && currentPrice.IsPositive() {
+		slippage = fill.AvgPrice.Sub(currentPrice).Abs().Div(currentPrice)
+	}
+	e.updateMetrics(true, slippage, time.Since(startTime))
+
+	return &ExecutionResult{
+		OrderID:    order.ID,
+		Order:      order,
+		Exchange:   "paper",
+		Status:     fill.Status,
+		FilledQty:  fill.FilledQty,
+		AvgPrice:   fill.AvgPrice,
+		Commission: fill.Commission,
+		Slippage:   slippage,
+		Latency:    time.Since(startTime),
+		Timestamp:  time.Now(),
+		IsPaper:    true,
+		Fills:      []VenueFill{fill},
+	}, nil
+}
+
+// paperBook returns the order book a paper order is filled against, when
+// SimPartialFills is set and the side the order takes from has depth.
+func (e *Executor) paperBook(ctx context.Context, adapter ExchangeAdapter, order *types.Order) (*types.OrderBook, bool) {
+	if !e.config.SimPartialFills {
+		return nil, false
+	}
+
+	book, err := adapter.GetOrderBook(ctx, order.Symbol, e.config.SimBookDepth)
+	if err != nil || book == nil {
+		e.logger.Warn("No order book for paper fill, filling at configured slippage",
+			zap.String("symbol", order.Symbol),
+			zap.Error(err))
+		return nil, false
+	}
+
+	levels := book.Asks
+	if order.Side == types.OrderSideSell {
+		levels = book.Bids
+	}
+	return book, len(levels) > 0
+}
+
+// walkBook fills order against the opposing side of book, best level first,
+// as far as its limit price when it has one. A market order the book is too
+// thin for fills partially and the rest is cancelled; what a limit order
+// cannot take rests behind the quantity already quoted at its price.
+func (e *Executor) walkBook(venue string, order *types.Order, book *types.OrderBook) VenueFill {
+	levels, own := book.Asks, book.Bids
+	if order.Side == types.OrderSideSell {
+		levels, own = book.Bids, book.Asks
+	}
+	limited := order.Type != types.OrderTypeMarket && order.Price.IsPositive()
+
+	remaining := order.Quantity
+	filled, notional := decimal.Zero, decimal.Zero
+	for _, level := range levels {
+		if !remaining.IsPositive() || (limited && !reaches(order.Side, level.Price, order.Price)) {
+			break
+		}
+		qty := decimal.Min(remaining, level.Quantity)
+		filled = filled.Add(qty)
+		notional = notional.Add(qty.Mul(level.Price))
+		remaining = remaining.Sub(qty)
+	}
+
+	fill := VenueFill{
+		Exchange:  venue,
+		OrderID:   order.ID,
+		Quantity:  order.Quantity,
+		FilledQty: filled,
+		Liquidity: fees.LiquidityTaker,
+	}
+	if filled.IsPositive() {
+		fill.AvgPrice = notional.Div(filled)
+		fee := e.feeModel().Fee(venue, fees.LiquidityTaker, filled, fill.AvgPrice)
+		fill.Commission = fee.Quote
+		fill.CommissionAsset = fee.Asset
+	}
+
+	switch {
+	case !remaining.IsPositive():
+		fill.Status = string(types.OrderStatusFilled)
+	case filled.IsPositive():
+		fill.Status = string(types.OrderStatusPartiallyFilled)
+	case limited:
+		fill.Status = string(types.OrderStatusOpen)
+		fill.Liquidity = fees.LiquidityMaker
+	default:
+		fill.Status = string(types.OrderStatusCancelled)
+	}
+	if limited && remaining.IsPositive() {
+		fill.QueueAhead = queuedAt(own, order.Price)
+	}
+	return fill
+}
+
+// restPaperLimit settles a paper post-only order that rested with queue
+// ahead of it at its price. When the far touch has traded through the price
+// the order fills in full; when it has only reached it, the quantity quoted
+// there fills the queue ahead first and the order gets what is left.
+func (e *Executor) restPaperLimit(leg *types.Order, venue string, book *types.OrderBook, queue decimal.Decimal) *VenueFill {
+	levels := book.Asks
+	if leg.Side == types.OrderSideSell {
+		levels = book.Bids
+	}
+
+	filled := decimal.Zero
+	for _, level := range levels {
+		if !reaches(leg.Side, level.Price, leg.Price) {
+			break
+		}
+		if !level.Price.Equal(leg.Price) {
+			filled = leg.Quantity
+			break
+		}
+		filled = decimal.Min(leg.Quantity, decimal.Max(decimal.Zero, level.Quantity.Sub(queue)))
+	}
+
+	fill := &VenueFill{
+		Exchange:   venue,
+		OrderID:    leg.ID,
+		Quantity:   leg.Quantity,
+		Status:     string(types.OrderStatusCancelled),
+		FilledQty:  filled,
+		Liquidity:  fees.LiquidityMaker,
+		QueueAhead: queue,
+	}
+	if filled.IsPositive() {
+		fee := e.feeModel().Fee(venue, fees.LiquidityMaker, filled, leg.Price)
+		fill.Status = string(types.OrderStatusPartiallyFilled)
+		if filled.Equal(leg.Quantity) {
+			fill.Status = string(types.OrderStatusFilled)
+		}
+		fill.AvgPrice = leg.Price
+		fill.Commission = fee.Quote
+		fill.CommissionAsset = fee.Asset
+	}
+	return fill
+}
+
+// reaches reports whether a quote at price on the opposing side of the book
+// would fill a side order limited to limit.
+func reaches(side types.OrderSide, price, limit decimal.Decimal) bool {
+	if side == types.OrderSideBuy {
+		return price.LessThanOrEqual(limit)
+	}
+	return price.GreaterThanOrEqual(limit)
+}
+
+// queuedAt returns the quantity quoted at price on one side of the book.
+func queuedAt(levels []types.OrderBookLevel, price decimal.Decimal) decimal.Decimal {
+	for _, level := range levels {
+		if level.Price.Equal(price) {
+			return level.Quantity
+		}
+	}
+	return decimal.Zero
+}
@@ -0,0 +1,105 @@
+package execution_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// bookVenue quotes a fixed spread and serves books in turn, repeating the
+// last one.
+type bookVenue struct {
+	*passiveVenue
+	books []*types.OrderBook
+	calls int
+}
+
+func (v *bookVenue) GetOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error) {
+	book := v.books[len(v.books)-1]
+	if v.calls < len(v.books) {
+		book = v.books[v.calls]
+	}
+	v.calls++
+	return book, nil
+}
+
+func levels(priceQty ...float64) []types.OrderBookLevel {
+	var out []types.OrderBookLevel
+	for i := 0; i+1 < len(priceQty); i += 2 {
+		out = append(out, types.OrderBookLevel{
+			Price:    decimal.NewFromFloat(priceQty[i]),
+			Quantity: decimal.NewFromFloat(priceQty[i+1]),
+		})
+	}
+	return out
+}
+
+func newSimExecutor(v *bookVenue, latency time.Duration) *execution.Executor {
+	config := execution.DefaultExecutorConfig()
+	config.SimLatency = latency
+	config.SimPartialFills = true
+
+	e := execution.NewExecutor(zap.NewNop(), config)
+	e.AddAdapter(v)
+	e.SetDefaultAdapter(v)
+	return e
+}
+
+func TestPaperMarketOrderWalksTheBook(t *testing.T) {
+	v := &bookVenue{
+		passiveVenue: newPassiveVenue(100, 101, 0),
+		books: []*types.OrderBook{{
+			Bids: levels(100, 5),
+			Asks: levels(101, 1, 102, 1),
+		}},
+	}
+	e := newSimExecutor(v, 5*time.Millisecond)
+
+	position := &types.Position{Symbol: "BTC/USDT", Side: types.PositionSideShort, Quantity: decimal.NewFromInt(3)}
+	result, err := e.ClosePosition(context.Background(), position, "")
+	if err != nil {
+		t.Fatalf("ClosePosition failed: %v", err)
+	}
+
+	// Two of three fill across both ask levels; the rest finds no depth
+	if result.Status != string(types.OrderStatusPartiallyFilled) || !result.FilledQty.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("Expected a partial fill of 2, got %s of %s", result.Status, result.FilledQty)
+	}
+	if !result.AvgPrice.Equal(decimal.NewFromFloat(101.5)) {
+		t.Errorf("Expected an average price of 101.5, got %s", result.AvgPrice)
+	}
+	if result.Latency < 5*time.Millisecond {
+		t.Errorf("Expected at least 5ms of simulated latency, got %s", result.Latency)
+	}
+}
+
+func TestPaperLimitOrderFillsBehindItsQueue(t *testing.T) {
+	v := &bookVenue{
+		passiveVenue: newPassiveVenue(100, 101, 0),
+		books: []*types.OrderBook{
+			// Joins 3 already bid at 100
+			{Bids: levels(100, 3), Asks: levels(101, 5)},
+			// Then 4 are offered at 100, filling the queue first
+			{Bids: levels(99, 3), Asks: levels(100, 4, 101, 5)},
+		},
+	}
+	e := newSimExecutor(v, 0)
+
+	opts := execution.LimitOptions{MaxReprices: 0, RepriceInterval: time.Millisecond}
+	result, err := e.ExecuteLimit(context.Background(), limitBuy(2), opts)
+	if err != nil {
+		t.Fatalf("ExecuteLimit failed: %v", err)
+	}
+
+	if result.Status != string(types.OrderStatusPartiallyFilled) || !result.FilledQty.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Expected 1 filled behind the queue, got %s of %s", result.Status, result.FilledQty)
+	}
+	if fill := result.Fills[0]; !fill.QueueAhead.Equal(decimal.NewFromInt(3)) || !fill.AvgPrice.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected a fill at 100 behind 3 queued, got %s behind %s", fill.AvgPrice, fill.QueueAhead)
+	}
+}
@@ -0,0 +1,90 @@
+// Package execution provides mark-to-market PnL of open positions.
+package execution
+
+import (
+	"github.com/atlas-desktop/trading-backend/internal/events"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// PnLMonitor marks the OrderManager's positions to market on each price
+// update and publishes their PnL to the event bus.
+type PnLMonitor struct {
+	logger       *zap.Logger
+	orderManager *OrderManager
+	bus          *events.EventBus
+}
+
+// NewPnLMonitor creates a PnL monitor publishing to bus.
+func NewPnLMonitor(logger *zap.Logger, orderManager *OrderManager, bus *events.EventBus) *PnLMonitor {
+	return &PnLMonitor{
+		logger:       logger.Named("pnl-monitor"),
+		orderManager: orderManager,
+		bus:          bus,
+	}
+}
+
+// OnPrice re-marks the symbol's position and, if there is one, publishes a
+// PnL event covering every open position. Wire it to
+// MarketDataService.OnPrice.
+func (m *PnLMonitor) OnPrice(symbol string, price decimal.Decimal) {
+	if !m.orderManager.MarkPrice(symbol, price) {
+		return
+	}
+
+	positions := m.orderManager.GetAllPositions()
+	positionEvents := make([]*events.PositionEvent, 0, len(positions))
+	for _, position := range positions {
+		positionEvents = append(positionEvents, events.NewPositionEvent(
+			position.Symbol,
+			string(position.Side),
+			position.Quantity,
+			position.EntryPrice,
+			position.CurrentPrice,
+			position.UnrealizedPnL,
+			position.RealizedPnL,
+		))
+	}
+
+	m.bus.Publish(events.NewPnLEvent(positionEvents))
+}
+
+// MarkPrice updates the current price and unrealized PnL of a symbol's
+// position, and trails its take-profit ladder's stop, reporting whether there
+// is an open position to mark.
+func (om *OrderManager) MarkPrice(symbol string, price decimal.Decimal) bool {
+	if !price.IsPositive() {
+		return false
+	}
+
+	key := NormalizeSymbol(symbol)
+	om.mu.Lock()
+	position, ok := om.positions[key]
+	if !ok {
+		om.mu.Unlock()
+		return false
+	}
+	markToMarket(position, price)
+	ladder := om.trailLadder(key, price)
+	om.mu.Unlock()
+
+	om.notifyLadderUpdate(ladder)
+	return true
+}
+
+// markToMarket sets a position's current price and its unrealized PnL
+// against the entry price. Positions without an entry price, such as those
+// synced from exchange balances, only get the price.
+func markToMarket(position *types.Position, price decimal.Decimal) {
+	position.CurrentPrice = price
+	if position.EntryPrice.IsZero() {
+		return
+	}
+
+	pnl := price.Sub(position.EntryPrice).Mul(position.Quantity)
+	if position.Side == types.PositionSideShort {
+		pnl = pnl.Neg()
+	}
+	position.UnrealizedPnL = pnl
+}
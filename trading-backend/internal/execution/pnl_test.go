@@ -0,0 +1,75 @@
+package execution_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/events"
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// openPosition fills an order for quantity at price through the order manager
+func openPosition(om *execution.OrderManager, id, symbol string, side types.OrderSide, price, quantity int64) {
+	om.TrackOrder(&types.Order{ID: id, Symbol: symbol, Side: side, Quantity: decimal.NewFromInt(quantity)}, "paper", "")
+	om.RecordFill(execution.OrderFill{
+		OrderID:  id,
+		Price:    decimal.NewFromInt(price),
+		Quantity: decimal.NewFromInt(quantity),
+	})
+}
+
+func TestPnLMonitorPublishesMarkedPositions(t *testing.T) {
+	bus := events.NewEventBus(zap.NewNop(), events.DefaultEventBusConfig())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := bus.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer bus.Stop()
+
+	received := make(chan *events.PnLEvent, 4)
+	bus.Subscribe(events.EventTypePnL, func(e events.Event) error {
+		received <- e.(*events.PnLEvent)
+		return nil
+	})
+
+	om := execution.NewOrderManager(zap.NewNop())
+	openPosition(om, "long", "BTC/USDT", types.OrderSideBuy, 100, 2)
+	openPosition(om, "short", "ETHUSDT", types.OrderSideSell, 50, 4)
+
+	monitor := execution.NewPnLMonitor(zap.NewNop(), om, bus)
+
+	// No position, nothing to publish
+	monitor.OnPrice("SOLUSDT", decimal.NewFromInt(20))
+	monitor.OnPrice("ETH/USDT", decimal.NewFromInt(45))
+
+	var event *events.PnLEvent
+	select {
+	case event = <-received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no PnL event published")
+	}
+
+	// Short ETH gains 5 on 4 units; BTC is still marked at its fill
+	if event.UnrealizedPnL != 20 {
+		t.Errorf("total unrealized PnL = %v, want 20", event.UnrealizedPnL)
+	}
+	if len(event.Positions) != 2 {
+		t.Fatalf("positions = %d, want 2", len(event.Positions))
+	}
+
+	position := om.GetPosition("ETHUSDT")
+	if !position.CurrentPrice.Equal(decimal.NewFromInt(45)) || !position.UnrealizedPnL.Equal(decimal.NewFromInt(20)) {
+		t.Errorf("ETH position = %s @ %s, want 20 @ 45", position.UnrealizedPnL, position.CurrentPrice)
+	}
+
+	select {
+	case extra := <-received:
+		t.Errorf("unexpected event for a symbol without a position: %+v", extra)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
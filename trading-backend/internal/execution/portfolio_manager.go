@@ -0,0 +1,331 @@
+// Package execution provides portfolio tracking.
+package execution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// PortfolioConfig configures the portfolio manager.
+type PortfolioConfig struct {
+	QuoteAsset   string          `json:"quoteAsset"`   // Asset cash is held in
+	Paper        bool            `json:"paper"`        // Simulate balances instead of reading them from exchanges
+	InitialCash  decimal.Decimal `json:"initialCash"`  // Starting cash in paper mode
+	SyncInterval time.Duration   `json:"syncInterval"` // How often live balances are re-read
+}
+
+// DefaultPortfolioConfig returns sensible defaults.
+func DefaultPortfolioConfig() PortfolioConfig {
+	return PortfolioConfig{
+		QuoteAsset:   "USDT",
+		Paper:        true,
+		InitialCash:  decimal.NewFromInt(10000),
+		SyncInterval: time.Minute,
+	}
+}
+
+// PortfolioManager tracks cash, open positions and mark-to-market equity.
+// Fills and price updates keep it current between syncs; in live mode Sync
+// replaces cash and positions with the balances reported by the exchanges.
+type PortfolioManager struct {
+	logger   *zap.Logger
+	config   PortfolioConfig
+	adapters []ExchangeAdapter
+	mu       sync.RWMutex
+
+	cash         decimal.Decimal
+	positions    map[string]*types.Position // By normalized symbol
+	prices       map[string]decimal.Decimal // Latest mark price by normalized symbol
+	appliedFills map[string]int             // Fills applied per order ID
+	lastSync     time.Time
+
+	// Per-exchange balances as of the last live sync
+	venueCash     map[string]decimal.Decimal
+	venueHoldings map[string]map[string]decimal.Decimal // Exchange -> normalized symbol -> long quantity
+
+	// OnPositionClosed is called with a position once fills close it out; its
+	// RealizedPnL holds the round trip's price PnL
+	OnPositionClosed func(position *types.Position)
+}
+
+// NewPortfolioManager creates a portfolio manager over the given exchange
+// adapters. In paper mode the adapters are not used.
+func NewPortfolioManager(logger *zap.Logger, config PortfolioConfig, adapters ...ExchangeAdapter) *PortfolioManager {
+	pm := &PortfolioManager{
+		logger:       logger.Named("portfolio-manager"),
+		config:       config,
+		adapters:     adapters,
+		positions:    make(map[string]*types.Position),
+		prices:       make(map[string]decimal.Decimal),
+		appliedFills: make(map[string]int),
+	}
+
+	if config.Paper {
+		pm.cash = config.InitialCash
+	}
+
+	return pm
+}
+
+// Start syncs live balances and keeps them synced until ctx is cancelled. It
+// does nothing in paper mode.
+func (pm *PortfolioManager) Start(ctx context.Context) error {
+	if pm.config.Paper {
+		return nil
+	}
+
+	if err := pm.Sync(ctx); err != nil {
+		return fmt.Errorf("failed to sync portfolio: %w", err)
+	}
+
+	interval := pm.config.SyncInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := pm.Sync(ctx); err != nil {
+					pm.logger.Warn("Portfolio sync failed", zap.Error(err))
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Sync reads cash and positions from every exchange adapter. Positions
+// without a known mark price are priced from the exchange ticker.
+func (pm *PortfolioManager) Sync(ctx context.Context) error {
+	if pm.config.Paper {
+		return nil
+	}
+
+	cash := decimal.Zero
+	positions := make(map[string]*types.Position)
+	venueCash := make(map[string]decimal.Decimal, len(pm.adapters))
+	venueHoldings := make(map[string]map[string]decimal.Decimal, len(pm.adapters))
+
+	for _, adapter := range pm.adapters {
+		balance, err := adapter.GetBalance(ctx, pm.config.QuoteAsset)
+		if err != nil {
+			return fmt.Errorf("failed to get %s balance from %s: %w", pm.config.QuoteAsset, adapter.Name(), err)
+		}
+		cash = cash.Add(balance)
+		venueCash[adapter.Name()] = balance
+		holdings := make(map[string]decimal.Decimal)
+		venueHoldings[adapter.Name()] = holdings
+
+		adapterPositions, err := adapter.GetPositions(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get positions from %s: %w", adapter.Name(), err)
+		}
+
+		for _, position := range adapterPositions {
+			key := NormalizeSymbol(position.Symbol)
+			// Spot adapters report the quote asset as a position; it is cash
+			if key == NormalizeSymbol(pm.config.QuoteAsset+pm.config.QuoteAsset) {
+				continue
+			}
+			if position.Side == types.PositionSideLong {
+				holdings[key] = holdings[key].Add(position.Quantity)
+			}
+
+			if existing, ok := positions[key]; ok && existing.Side == position.Side {
+				existing.Quantity = existing.Quantity.Add(position.Quantity)
+				continue
+			}
+
+			copied := *position
+			positions[key] = &copied
+
+			if pm.markPrice(key).IsZero() {
+				if ticker, err := adapter.GetTicker(ctx, key); err == nil {
+					pm.UpdatePrice(key, ticker.LastPrice)
+				}
+			}
+		}
+	}
+
+	pm.mu.Lock()
+	pm.cash = cash
+	pm.positions = positions
+	pm.venueCash = venueCash
+	pm.venueHoldings = venueHoldings
+	pm.lastSync = time.Now()
+	for key, position := range pm.positions {
+		pm.markPosition(position, pm.prices[key])
+	}
+	pm.mu.Unlock()
+
+	return nil
+}
+
+// OnOrderUpdate applies an order's new fills to cash and positions. Wire it to
+// OrderManager.OnOrderUpdate.
+func (pm *PortfolioManager) OnOrderUpdate(order *ManagedOrder) {
+	pm.mu.Lock()
+
+	applied := pm.appliedFills[order.Order.ID]
+	if applied >= len(order.Fills) {
+		pm.mu.Unlock()
+		return
+	}
+
+	var closed []*types.Position
+
+	symbol := NormalizeSymbol(order.Order.Symbol)
+	for _, fill := range order.Fills[applied:] {
+		value := fill.Price.Mul(fill.Quantity)
+		if order.Order.Side == types.OrderSideBuy {
+			pm.cash = pm.cash.Sub(value)
+		} else {
+			pm.cash = pm.cash.Add(value)
+		}
+		pm.cash = pm.cash.Sub(fill.Commission)
+
+		if position := applyFill(pm.positions, symbol, order.Order.Side, fill); position != nil {
+			closed = append(closed, position)
+		}
+		pm.prices[symbol] = fill.Price
+	}
+	pm.appliedFills[order.Order.ID] = len(order.Fills)
+
+	if position, ok := pm.positions[symbol]; ok {
+		pm.markPosition(position, pm.prices[symbol])
+	}
+	pm.mu.Unlock()
+
+	if pm.OnPositionClosed != nil {
+		for _, position := range closed {
+			pm.OnPositionClosed(position)
+		}
+	}
+}
+
+// UpdatePrice records a symbol's latest price and re-marks its position.
+func (pm *PortfolioManager) UpdatePrice(symbol string, price decimal.Decimal) {
+	if !price.IsPositive() {
+		return
+	}
+
+	key := NormalizeSymbol(symbol)
+
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	pm.prices[key] = price
+	if position, ok := pm.positions[key]; ok {
+		pm.markPosition(position, price)
+	}
+}
+
+// EquityValue returns cash plus the mark-to-market value of open positions.
+// Short positions count against equity.
+func (pm *PortfolioManager) EquityValue() decimal.Decimal {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	equity := pm.cash
+	for key, position := range pm.positions {
+		price := pm.prices[key]
+		if price.IsZero() {
+			price = position.EntryPrice
+		}
+
+		value := position.Quantity.Mul(price)
+		if position.Side == types.PositionSideShort {
+			equity = equity.Sub(value)
+		} else {
+			equity = equity.Add(value)
+		}
+	}
+	return equity
+}
+
+// AvailableCash returns uninvested cash.
+func (pm *PortfolioManager) AvailableCash() decimal.Decimal {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.cash
+}
+
+// VenueCash returns the quote cash held on one exchange as of the last sync.
+// It reports false in paper mode, before the first sync and for exchanges
+// the manager doesn't track.
+func (pm *PortfolioManager) VenueCash(exchange string) (decimal.Decimal, bool) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	cash, ok := pm.venueCash[exchange]
+	return cash, ok
+}
+
+// VenueHolding returns the long quantity of symbol held on one exchange as of
+// the last sync, reporting false like VenueCash.
+func (pm *PortfolioManager) VenueHolding(exchange, symbol string) (decimal.Decimal, bool) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	holdings, ok := pm.venueHoldings[exchange]
+	if !ok {
+		return decimal.Zero, false
+	}
+	return holdings[NormalizeSymbol(symbol)], true
+}
+
+// GetPositions returns copies of the open positions.
+func (pm *PortfolioManager) GetPositions() []*types.Position {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	positions := make([]*types.Position, 0, len(pm.positions))
+	for _, position := range pm.positions {
+		copied := *position
+		positions = append(positions, &copied)
+	}
+	return positions
+}
+
+// LastSync returns when live balances were last read.
+func (pm *PortfolioManager) LastSync() time.Time {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.lastSync
+}
+
+// markPrice returns the latest price for a normalized symbol.
+func (pm *PortfolioManager) markPrice(key string) decimal.Decimal {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.prices[key]
+}
+
+// markPosition updates a position's current price and unrealized PnL.
+// Callers hold pm.mu.
+func (pm *PortfolioManager) markPosition(position *types.Position, price decimal.Decimal) {
+	if price.IsZero() {
+		return
+	}
+	markToMarket(position, price)
+}
+
+// NormalizeSymbol maps "BTC/USDT", "btc-usdt" and "BTCUSDT" to one key.
+func NormalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.NewReplacer("/", "", "-", "", "_", "").Replace(symbol))
+}
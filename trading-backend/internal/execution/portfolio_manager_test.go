@@ -0,0 +1,80 @@
+package execution_test
+
+import (
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func TestPortfolioManagerPaperFills(t *testing.T) {
+	pm := execution.NewPortfolioManager(zap.NewNop(), execution.DefaultPortfolioConfig())
+
+	order := &execution.ManagedOrder{
+		Order: &types.Order{ID: "o1", Symbol: "BTC/USDT", Side: types.OrderSideBuy},
+		Fills: []execution.OrderFill{{
+			Price:      decimal.NewFromInt(100),
+			Quantity:   decimal.NewFromInt(10),
+			Commission: decimal.NewFromInt(1),
+		}},
+	}
+	pm.OnOrderUpdate(order)
+	// A repeated update must not apply the same fill twice
+	pm.OnOrderUpdate(order)
+
+	if got, want := pm.AvailableCash(), decimal.NewFromInt(8999); !got.Equal(want) {
+		t.Fatalf("cash = %s, want %s", got, want)
+	}
+	if got, want := pm.EquityValue(), decimal.NewFromInt(9999); !got.Equal(want) {
+		t.Fatalf("equity = %s, want %s", got, want)
+	}
+
+	pm.UpdatePrice("BTCUSDT", decimal.NewFromInt(150))
+	if got, want := pm.EquityValue(), decimal.NewFromInt(10499); !got.Equal(want) {
+		t.Fatalf("equity after mark = %s, want %s", got, want)
+	}
+
+	positions := pm.GetPositions()
+	if len(positions) != 1 {
+		t.Fatalf("positions = %d, want 1", len(positions))
+	}
+	if got, want := positions[0].UnrealizedPnL, decimal.NewFromInt(500); !got.Equal(want) {
+		t.Fatalf("unrealized PnL = %s, want %s", got, want)
+	}
+}
+
+func TestPortfolioManagerPositionClosed(t *testing.T) {
+	pm := execution.NewPortfolioManager(zap.NewNop(), execution.DefaultPortfolioConfig())
+
+	var closed []*types.Position
+	pm.OnPositionClosed = func(position *types.Position) {
+		closed = append(closed, position)
+	}
+
+	fill := func(id string, side types.OrderSide, price, quantity int64) {
+		pm.OnOrderUpdate(&execution.ManagedOrder{
+			Order: &types.Order{ID: id, Symbol: "ETH/USDT", Side: side},
+			Fills: []execution.OrderFill{{
+				Price:    decimal.NewFromInt(price),
+				Quantity: decimal.NewFromInt(quantity),
+			}},
+		})
+	}
+
+	fill("open", types.OrderSideBuy, 100, 4)
+	fill("reduce", types.OrderSideSell, 110, 1)
+	if len(closed) != 0 {
+		t.Fatalf("position reported closed after a partial exit")
+	}
+
+	fill("close", types.OrderSideSell, 90, 3)
+	if len(closed) != 1 {
+		t.Fatalf("closed positions = %d, want 1", len(closed))
+	}
+	// +10 on one unit, -10 on three
+	if got, want := closed[0].RealizedPnL, decimal.NewFromInt(-20); !got.Equal(want) {
+		t.Fatalf("realized PnL = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,351 @@
+// Package execution provides reconciliation of order manager state with the
+// exchanges.
+package execution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// DivergenceKind classifies a difference between local and exchange state.
+type DivergenceKind string
+
+const (
+	DivergencePositionMissing  DivergenceKind = "position_missing"  // Held on the exchange, not tracked locally
+	DivergencePositionStale    DivergenceKind = "position_stale"    // Tracked locally, gone from the exchange
+	DivergencePositionMismatch DivergenceKind = "position_mismatch" // Side or quantity differ
+	DivergenceOrderMissing     DivergenceKind = "order_missing"     // Resting on the exchange, not tracked locally
+	DivergenceOrderClosed      DivergenceKind = "order_closed"      // Open locally, no longer open on the exchange
+)
+
+// Divergence is a difference between local state and an exchange that
+// reconciliation corrected.
+type Divergence struct {
+	Kind        DivergenceKind  `json:"kind"`
+	Exchange    string          `json:"exchange,omitempty"`
+	Symbol      string          `json:"symbol"`
+	OrderID     string          `json:"orderId,omitempty"`
+	LocalQty    decimal.Decimal `json:"localQty"`
+	ExchangeQty decimal.Decimal `json:"exchangeQty"`
+	Timestamp   time.Time       `json:"timestamp"`
+}
+
+// ReconcileScope limits the exchange holdings Reconcile adopts as positions.
+// Spot venues report every balance, including assets never traded and dust
+// left behind by fees.
+type ReconcileScope struct {
+	Symbols     []string        `json:"symbols"`     // Traded symbols; empty adopts holdings of any symbol
+	MinNotional decimal.Decimal `json:"minNotional"` // Holdings worth less at the last price are dust; zero adopts all
+}
+
+// covers reports whether holdings of a normalized symbol are in scope.
+func (s ReconcileScope) covers(symbol string) bool {
+	if len(s.Symbols) == 0 {
+		return true
+	}
+	for _, traded := range s.Symbols {
+		if NormalizeSymbol(traded) == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// Reconcile rebuilds positions and open orders to match the exchanges, so a
+// restarted process neither double-enters nor forgets resting stops.
+// Positions are replaced with the holdings in scope that the adapters report.
+// A holding reported without an entry price keeps the local entry, or is
+// marked CostUnknown when there is none. Open orders are rebuilt from
+// adapters implementing adapters.OpenOrdersAdapter. Nothing is changed if any
+// adapter cannot be read. Each correction is logged and passed to
+// OnDivergence.
+func (om *OrderManager) Reconcile(ctx context.Context, scope ReconcileScope, exchanges ...ExchangeAdapter) error {
+	exchangePositions := make(map[string]*types.Position)
+	exchangeOrders := make(map[string][]*types.Order)
+
+	for _, adapter := range exchanges {
+		positions, err := adapter.GetPositions(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get positions from %s: %w", adapter.Name(), err)
+		}
+
+		for _, position := range positions {
+			key := NormalizeSymbol(position.Symbol)
+			if !position.Quantity.IsPositive() || selfQuoted(position.Symbol) || !scope.covers(key) {
+				continue
+			}
+			dust, err := isDust(ctx, adapter, position, scope.MinNotional)
+			if err != nil {
+				return fmt.Errorf("failed to price %s on %s: %w", position.Symbol, adapter.Name(), err)
+			}
+			if dust {
+				continue
+			}
+
+			if existing, ok := exchangePositions[key]; ok && existing.Side == position.Side {
+				existing.Quantity = existing.Quantity.Add(position.Quantity)
+				continue
+			}
+
+			copied := *position
+			copied.Symbol = key
+			exchangePositions[key] = &copied
+		}
+
+		if lister, ok := adapter.(adapters.OpenOrdersAdapter); ok {
+			orders, err := lister.GetOpenOrders(ctx, "")
+			if err != nil {
+				return fmt.Errorf("failed to get open orders from %s: %w", adapter.Name(), err)
+			}
+			exchangeOrders[adapter.Name()] = orders
+		}
+	}
+
+	// Local orders no longer open on the exchange are closed with their final
+	// status, which needs an exchange round trip outside the lock
+	closed := om.closedOrders(exchangeOrders)
+	finalStatus := make(map[string]OrderStatus, len(closed))
+	for _, managed := range closed {
+		status := OrderStatusCancelled
+		for _, adapter := range exchanges {
+			if adapter.Name() != managed.Exchange {
+				continue
+			}
+			if order, err := adapter.GetOrder(ctx, managed.Order.ID); err == nil {
+				status = orderStatusFromExchange(order.Status)
+			}
+		}
+		finalStatus[managed.Order.ID] = status
+	}
+
+	now := time.Now()
+	var divergences []Divergence
+	var updated []*ManagedOrder
+
+	om.mu.Lock()
+
+	for key, local := range om.positions {
+		remote, ok := exchangePositions[key]
+		if !ok {
+			divergences = append(divergences, Divergence{
+				Kind:      DivergencePositionStale,
+				Symbol:    key,
+				LocalQty:  local.Quantity,
+				Timestamp: now,
+			})
+			delete(om.positions, key)
+			continue
+		}
+
+		if remote.Side != local.Side || !remote.Quantity.Equal(local.Quantity) {
+			divergences = append(divergences, Divergence{
+				Kind:        DivergencePositionMismatch,
+				Symbol:      key,
+				LocalQty:    local.Quantity,
+				ExchangeQty: remote.Quantity,
+				Timestamp:   now,
+			})
+			om.positions[key] = reconciledPosition(local, remote, now)
+		}
+	}
+
+	for key, remote := range exchangePositions {
+		if _, ok := om.positions[key]; ok {
+			continue
+		}
+		om.positions[key] = reconciledPosition(nil, remote, now)
+		divergences = append(divergences, Divergence{
+			Kind:        DivergencePositionMissing,
+			Symbol:      key,
+			ExchangeQty: remote.Quantity,
+			Timestamp:   now,
+		})
+	}
+
+	for _, managed := range closed {
+		status := finalStatus[managed.Order.ID]
+		if order, ok := om.updateOrderStatusLocked(managed.Order.ID, status, "closed on exchange"); ok {
+			updated = append(updated, order)
+			divergences = append(divergences, Divergence{
+				Kind:      DivergenceOrderClosed,
+				Exchange:  managed.Exchange,
+				Symbol:    managed.Order.Symbol,
+				OrderID:   managed.Order.ID,
+				LocalQty:  managed.Order.Quantity.Sub(managed.FilledQty),
+				Timestamp: now,
+			})
+		}
+	}
+
+	for exchange, orders := range exchangeOrders {
+		for _, order := range orders {
+			if om.findOrderLocked(order) != nil {
+				continue
+			}
+
+			managed := &ManagedOrder{
+				Order:     order,
+				Exchange:  exchange,
+				Status:    orderStatusFromExchange(order.Status),
+				FilledQty: order.FilledQty,
+				CreatedAt: order.CreatedAt,
+				UpdatedAt: now,
+			}
+			om.orders[order.ID] = managed
+			updated = append(updated, managed)
+			divergences = append(divergences, Divergence{
+				Kind:        DivergenceOrderMissing,
+				Exchange:    exchange,
+				Symbol:      order.Symbol,
+				OrderID:     order.ID,
+				ExchangeQty: order.Quantity.Sub(order.FilledQty),
+				Timestamp:   now,
+			})
+		}
+	}
+
+	om.mu.Unlock()
+
+	for _, d := range divergences {
+		om.logger.Warn("Reconciled divergence from exchange",
+			zap.String("kind", string(d.Kind)),
+			zap.String("exchange", d.Exchange),
+			zap.String("symbol", d.Symbol),
+			zap.String("orderId", d.OrderID),
+			zap.String("localQty", d.LocalQty.String()),
+			zap.String("exchangeQty", d.ExchangeQty.String()))
+
+		if om.OnDivergence != nil {
+			om.OnDivergence(d)
+		}
+	}
+	for _, order := range updated {
+		om.notifyOrderUpdate(order)
+	}
+
+	om.logger.Info("Reconciled with exchanges",
+		zap.Int("positions", len(exchangePositions)),
+		zap.Int("divergences", len(divergences)))
+
+	return nil
+}
+
+// reconciledPosition returns the position an exchange holding replaces a
+// local one with. Spot balances carry no entry price, so a holding on the
+// local side keeps the entry, stops and open time built from fills; one with
+// no local entry to keep is marked CostUnknown rather than valued from zero.
+func reconciledPosition(local, remote *types.Position, now time.Time) *types.Position {
+	if local != nil && local.Side == remote.Side {
+		position := *local
+		position.Quantity = remote.Quantity
+		if !remote.EntryPrice.IsZero() {
+			position.EntryPrice = remote.EntryPrice
+			position.CostUnknown = false
+		}
+		return &position
+	}
+
+	if remote.EntryPrice.IsZero() {
+		remote.CostUnknown = true
+	}
+	if remote.OpenedAt.IsZero() {
+		remote.OpenedAt = now
+	}
+	return remote
+}
+
+// isDust reports whether a holding is worth less than minNotional, pricing it
+// from the adapter's ticker when the adapter reported no price. A holding
+// that cannot be priced is kept.
+func isDust(ctx context.Context, adapter ExchangeAdapter, position *types.Position, minNotional decimal.Decimal) (bool, error) {
+	if !minNotional.IsPositive() {
+		return false, nil
+	}
+
+	price := position.CurrentPrice
+	if price.IsZero() {
+		ticker, err := adapter.GetTicker(ctx, position.Symbol)
+		if err != nil {
+			return false, err
+		}
+		if ticker == nil || !ticker.LastPrice.IsPositive() {
+			return false, nil
+		}
+		price = ticker.LastPrice
+	}
+	return position.Quantity.Mul(price).LessThan(minNotional), nil
+}
+
+// closedOrders returns local open orders that the listed exchanges no longer
+// report as open. Exchanges that could not list open orders are skipped.
+func (om *OrderManager) closedOrders(exchangeOrders map[string][]*types.Order) []*ManagedOrder {
+	om.mu.RLock()
+	defer om.mu.RUnlock()
+
+	var closed []*ManagedOrder
+	for _, managed := range om.orders {
+		if managed.Status != OrderStatusPending && managed.Status != OrderStatusOpen && managed.Status != OrderStatusPartialFill {
+			continue
+		}
+
+		orders, listed := exchangeOrders[managed.Exchange]
+		if !listed {
+			continue
+		}
+
+		open := false
+		for _, order := range orders {
+			if order.ID == managed.Order.ID || (order.ClientOrderID != "" && (order.ClientOrderID == managed.Order.ID || order.ClientOrderID == managed.Order.ClientOrderID)) {
+				open = true
+				break
+			}
+		}
+		if !open {
+			closed = append(closed, managed)
+		}
+	}
+	return closed
+}
+
+// findOrderLocked returns the tracked order matching an exchange order by ID
+// or client order ID, whether the client order ID is the tracked order's ID
+// or the one generated for it. Callers hold om.mu.
+func (om *OrderManager) findOrderLocked(order *types.Order) *ManagedOrder {
+	if managed, ok := om.orders[order.ID]; ok {
+		return managed
+	}
+	if order.ClientOrderID != "" {
+		if managed, ok := om.orders[order.ClientOrderID]; ok {
+			return managed
+		}
+		for _, managed := range om.orders {
+			if managed.Order.ClientOrderID == order.ClientOrderID {
+				return managed
+			}
+		}
+	}
+	return nil
+}
+
+// orderStatusFromExchange maps an exchange order status to an OrderStatus.
+func orderStatusFromExchange(status types.OrderStatus) OrderStatus {
+	switch status {
+	case types.OrderStatusPartiallyFilled, types.OrderStatusPartial:
+		return OrderStatusPartialFill
+	}
+	return OrderStatus(status)
+}
+
+// selfQuoted reports whether a symbol quotes an asset in itself, as spot
+// adapters report the quote currency balance ("USDT/USDT").
+func selfQuoted(symbol string) bool {
+	parts := strings.Split(symbol, "/")
+	return len(parts) == 2 && parts[0] == parts[1]
+}
@@ -0,0 +1,214 @@
+package execution_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// stubExchange reports fixed positions, open orders and prices.
+type stubExchange struct {
+	positions  []*types.Position
+	openOrders []*types.Order
+	orders     map[string]*types.Order
+	prices     map[string]int64
+}
+
+func (s *stubExchange) Name() string                      { return "stub" }
+func (s *stubExchange) Connect(ctx context.Context) error { return nil }
+func (s *stubExchange) Disconnect() error                 { return nil }
+func (s *stubExchange) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	return order, nil
+}
+func (s *stubExchange) CancelOrder(ctx context.Context, orderID string) error { return nil }
+func (s *stubExchange) GetOrder(ctx context.Context, orderID string) (*types.Order, error) {
+	if order, ok := s.orders[orderID]; ok {
+		return order, nil
+	}
+	return nil, fmt.Errorf("unknown order %s", orderID)
+}
+func (s *stubExchange) GetBalance(ctx context.Context, asset string) (decimal.Decimal, error) {
+	return decimal.Zero, nil
+}
+func (s *stubExchange) GetPositions(ctx context.Context) ([]*types.Position, error) {
+	return s.positions, nil
+}
+func (s *stubExchange) GetOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error) {
+	return nil, nil
+}
+func (s *stubExchange) GetTicker(ctx context.Context, symbol string) (*adapters.Ticker, error) {
+	price, ok := s.prices[symbol]
+	if !ok {
+		return nil, fmt.Errorf("no ticker for %s", symbol)
+	}
+	return &adapters.Ticker{Symbol: symbol, LastPrice: decimal.NewFromInt(price)}, nil
+}
+func (s *stubExchange) GetOpenOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	return s.openOrders, nil
+}
+
+func TestReconcileAdoptsExchangeState(t *testing.T) {
+	om := execution.NewOrderManager(zap.NewNop())
+
+	var kinds []execution.DivergenceKind
+	om.OnDivergence = func(d execution.Divergence) {
+		kinds = append(kinds, d.Kind)
+	}
+
+	exchange := &stubExchange{
+		positions: []*types.Position{
+			{Symbol: "BTC/USDT", Side: types.PositionSideLong, Quantity: decimal.NewFromInt(2)},
+			{Symbol: "USDT/USDT", Side: types.PositionSideLong, Quantity: decimal.NewFromInt(5000)},
+		},
+		openOrders: []*types.Order{{
+			ID:       "BTCUSDT:42",
+			Symbol:   "BTC/USDT",
+			Side:     types.OrderSideSell,
+			Type:     types.OrderTypeStopMarket,
+			Quantity: decimal.NewFromInt(2),
+			Status:   types.OrderStatusOpen,
+		}},
+	}
+
+	if err := om.Reconcile(context.Background(), execution.ReconcileScope{}, exchange); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	position := om.GetPosition("BTC/USDT")
+	if position == nil || !position.Quantity.Equal(decimal.NewFromInt(2)) {
+		t.Fatalf("position = %+v, want 2 BTC", position)
+	}
+	if om.GetPosition("USDT/USDT") != nil {
+		t.Errorf("quote balance adopted as a position")
+	}
+	if open := om.GetOpenOrders(); len(open) != 1 || open[0].Order.ID != "BTCUSDT:42" {
+		t.Errorf("open orders = %v, want the resting stop", open)
+	}
+	if len(kinds) != 2 {
+		t.Errorf("divergences = %v, want a missing position and a missing order", kinds)
+	}
+
+	// A second pass over unchanged state corrects nothing
+	kinds = nil
+	if err := om.Reconcile(context.Background(), execution.ReconcileScope{}, exchange); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if len(kinds) != 0 {
+		t.Errorf("divergences on unchanged state = %v", kinds)
+	}
+}
+
+func TestReconcileClosesOrdersGoneFromExchange(t *testing.T) {
+	om := execution.NewOrderManager(zap.NewNop())
+	om.TrackOrder(&types.Order{
+		ID:       "o1",
+		Symbol:   "ETH/USDT",
+		Side:     types.OrderSideBuy,
+		Quantity: decimal.NewFromInt(1),
+	}, "stub", "")
+	om.UpdateOrderStatus("o1", execution.OrderStatusOpen, "")
+
+	exchange := &stubExchange{
+		orders: map[string]*types.Order{
+			"o1": {ID: "o1", Status: types.OrderStatusFilled},
+		},
+	}
+
+	if err := om.Reconcile(context.Background(), execution.ReconcileScope{}, exchange); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	if got := om.GetOrder("o1").Status; got != execution.OrderStatusFilled {
+		t.Errorf("status = %s, want %s", got, execution.OrderStatusFilled)
+	}
+}
+
+func TestReconcileAdoptsTradedHoldingsOnly(t *testing.T) {
+	om := execution.NewOrderManager(zap.NewNop())
+	exchange := &stubExchange{
+		positions: []*types.Position{
+			{Symbol: "BTC/USDT", Side: types.PositionSideLong, Quantity: decimal.NewFromInt(2)},
+			{Symbol: "ETH/USDT", Side: types.PositionSideLong, Quantity: decimal.NewFromFloat(0.004)},
+			{Symbol: "DOGE/USDT", Side: types.PositionSideLong, Quantity: decimal.NewFromInt(5000)},
+		},
+		prices: map[string]int64{"BTC/USDT": 50000, "ETH/USDT": 2000},
+	}
+	scope := execution.ReconcileScope{
+		Symbols:     []string{"BTC/USDT", "ETH/USDT"},
+		MinNotional: decimal.NewFromInt(10),
+	}
+
+	if err := om.Reconcile(context.Background(), scope, exchange); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	btc := om.GetPosition("BTC/USDT")
+	if btc == nil || !btc.Quantity.Equal(decimal.NewFromInt(2)) {
+		t.Fatalf("position = %+v, want 2 BTC", btc)
+	}
+	if !btc.CostUnknown || !btc.EntryPrice.IsZero() {
+		t.Errorf("Expected a balance without local fills marked unknown-cost, got %+v", btc)
+	}
+	if eth := om.GetPosition("ETH/USDT"); eth != nil {
+		t.Errorf("Expected 8 USDT of ETH ignored as dust, got %+v", eth)
+	}
+	if doge := om.GetPosition("DOGE/USDT"); doge != nil {
+		t.Errorf("Expected an untraded asset ignored, got %+v", doge)
+	}
+
+	// A holding that must be priced fails the pass rather than guess
+	delete(exchange.prices, "ETH/USDT")
+	if err := om.Reconcile(context.Background(), scope, exchange); err == nil {
+		t.Error("Expected an error when a holding cannot be priced")
+	}
+}
+
+func TestReconcileKeepsLocalEntry(t *testing.T) {
+	om := execution.NewOrderManager(zap.NewNop())
+	for _, order := range []*types.Order{
+		{ID: "btc", Symbol: "BTC/USDT", Side: types.OrderSideBuy, Quantity: decimal.NewFromInt(1)},
+		{ID: "eth", Symbol: "ETH/USDT", Side: types.OrderSideSell, Quantity: decimal.NewFromInt(1)},
+	} {
+		om.TrackOrder(order, "stub", "")
+		om.RecordFill(execution.OrderFill{OrderID: order.ID, Price: decimal.NewFromInt(100), Quantity: order.Quantity})
+	}
+	stop := decimal.NewFromInt(90)
+	om.SetStopLoss("BTC/USDT", stop)
+
+	exchange := &stubExchange{
+		positions: []*types.Position{
+			{Symbol: "BTC/USDT", Side: types.PositionSideLong, Quantity: decimal.NewFromFloat(1.5)},
+			{Symbol: "ETH/USDT", Side: types.PositionSideLong, Quantity: decimal.NewFromInt(2)},
+		},
+	}
+	if err := om.Reconcile(context.Background(), execution.ReconcileScope{}, exchange); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	btc := om.GetPosition("BTC/USDT")
+	if btc == nil || !btc.Quantity.Equal(decimal.NewFromFloat(1.5)) {
+		t.Fatalf("position = %+v, want 1.5 BTC", btc)
+	}
+	if !btc.EntryPrice.Equal(decimal.NewFromInt(100)) || btc.CostUnknown || !btc.StopLoss.Equal(stop) {
+		t.Errorf("Expected the local entry and stop kept, got %+v", btc)
+	}
+
+	// The local short is no help pricing an exchange long
+	eth := om.GetPosition("ETH/USDT")
+	if eth == nil || eth.Side != types.PositionSideLong || !eth.CostUnknown || !eth.EntryPrice.IsZero() {
+		t.Fatalf("Expected an unknown-cost long, got %+v", eth)
+	}
+
+	// Closing a position of unknown cost realizes no PnL against a zero entry
+	om.TrackOrder(&types.Order{ID: "exit", Symbol: "ETH/USDT", Side: types.OrderSideSell, Quantity: decimal.NewFromInt(1)}, "stub", "")
+	om.RecordFill(execution.OrderFill{OrderID: "exit", Price: decimal.NewFromInt(150), Quantity: decimal.NewFromInt(1)})
+	if eth := om.GetPosition("ETH/USDT"); eth == nil || !eth.RealizedPnL.IsZero() || !eth.Quantity.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Expected 1 ETH left with no realized PnL, got %+v", eth)
+	}
+}
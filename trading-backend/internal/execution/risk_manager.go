@@ -4,6 +4,8 @@ package execution
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -22,16 +24,33 @@ type RiskManager struct {
 	dailyPnL           decimal.Decimal
 	dailyTrades        int
 	dailyVolume        decimal.Decimal
+	dailyPnLBuckets    map[string]decimal.Decimal // Realized PnL by day, keyed by date
 	consecutiveLosses  int
 	totalExposure      decimal.Decimal
 	symbolExposure     map[string]decimal.Decimal
+	symbolRisk         map[string]decimal.Decimal // Loss at stop per open position
 	correlatedExposure map[string]decimal.Decimal
+	valueAtRisk        decimal.Decimal            // Last CalculateVaR result
+	
+	// Equity tracking
+	cumulativePnL decimal.Decimal
+	peakEquity    decimal.Decimal
+	
+	// Daily boundary
+	resetLocation *time.Location
+	resetOffset   time.Duration // Reset time as an offset from midnight
 	
 	// Risk tracking
 	violations    []RiskViolation
 	isDisabled    bool
 	disabledUntil time.Time
 	
+	// Portfolio supplies the portfolio value when a check is given none
+	portfolio *PortfolioManager
+	
+	// Correlations replace the static groups when set
+	correlations CorrelationSource
+	
 	// Events
 	riskEvents chan RiskEvent
 }
@@ -49,6 +68,8 @@ type RiskConfig struct {
 	MaxDailyLoss         decimal.Decimal `json:"maxDailyLoss"`         // Max daily loss
 	MaxWeeklyLoss        decimal.Decimal `json:"maxWeeklyLoss"`        // Max weekly loss
 	MaxDrawdown          decimal.Decimal `json:"maxDrawdown"`          // Max drawdown percentage
+	HardDrawdownLimit    decimal.Decimal `json:"hardDrawdownLimit"`    // Drawdown that triggers the kill switch
+	StartingEquity       decimal.Decimal `json:"startingEquity"`       // Equity before any recorded PnL
 	MaxConsecutiveLosses int             `json:"maxConsecutiveLosses"` // Max consecutive losses
 	
 	// Trade limits
@@ -60,17 +81,28 @@ type RiskConfig struct {
 	// Risk per trade
 	RiskPerTrade         decimal.Decimal `json:"riskPerTrade"`         // Max risk per trade (%)
 	DefaultStopLoss      decimal.Decimal `json:"defaultStopLoss"`      // Default stop loss (%)
+	MaxPortfolioHeat     decimal.Decimal `json:"maxPortfolioHeat"`     // Max total risk at stop (%), zero disables
 	
 	// Time limits
 	TradingHoursStart    string          `json:"tradingHoursStart"`    // Start of trading hours
 	TradingHoursEnd      string          `json:"tradingHoursEnd"`      // End of trading hours
+	DailyResetTime       string          `json:"dailyResetTime"`       // Daily stats reset time (HH:MM)
+	DailyResetTimezone   string          `json:"dailyResetTimezone"`   // IANA timezone for the reset
 	
 	// Kill switch
 	KillSwitchThreshold  decimal.Decimal `json:"killSwitchThreshold"`  // Threshold for kill switch
 	CooldownPeriod       time.Duration   `json:"cooldownPeriod"`       // Cooldown after kill switch
 	
 	// Correlation groups
-	CorrelationGroups    map[string][]string `json:"correlationGroups"` // Symbol correlation groups
+	CorrelationGroups    map[string][]string `json:"correlationGroups"`    // Symbol correlation groups
+	CorrelationThreshold decimal.Decimal     `json:"correlationThreshold"` // Correlation at which symbols share exposure, with a correlation source
+}
+
+// CorrelationSource reports how correlated two symbols' returns are, and
+// false until it has enough data. *sizing.CorrelationEstimator implements it
+// for normalized symbols.
+type CorrelationSource interface {
+	Correlation(a, b string) (float64, bool)
 }
 
 // RiskViolation represents a risk rule violation.
@@ -128,6 +160,8 @@ func DefaultRiskConfig() RiskConfig {
 		MaxDailyLoss:          decimal.NewFromInt(500),      // $500
 		MaxWeeklyLoss:         decimal.NewFromInt(1500),     // $1,500
 		MaxDrawdown:           decimal.NewFromFloat(0.1),    // 10%
+		HardDrawdownLimit:     decimal.NewFromFloat(0.15),   // 15%
+		StartingEquity:        decimal.NewFromInt(10000),    // $10,000
 		MaxConsecutiveLosses:  5,
 		
 		MaxDailyTrades:        50,
@@ -137,9 +171,12 @@ func DefaultRiskConfig() RiskConfig {
 		
 		RiskPerTrade:          decimal.NewFromFloat(0.02),   // 2%
 		DefaultStopLoss:       decimal.NewFromFloat(0.05),   // 5%
+		MaxPortfolioHeat:      decimal.Zero,                 // Disabled
 		
 		TradingHoursStart:     "00:00",
 		TradingHoursEnd:       "23:59",
+		DailyResetTime:        "00:00",
+		DailyResetTimezone:    "UTC",
 		
 		KillSwitchThreshold:   decimal.NewFromInt(1000),     // $1,000 loss
 		CooldownPeriod:        4 * time.Hour,
@@ -148,25 +185,132 @@ func DefaultRiskConfig() RiskConfig {
 			"btc-correlated": {"BTC/USD", "ETH/USD", "SOL/USD"},
 			"stablecoins":    {"USDT/USD", "USDC/USD"},
 		},
+		CorrelationThreshold: decimal.NewFromFloat(0.7),
 	}
 }
 
 // NewRiskManager creates a new risk manager.
 func NewRiskManager(logger *zap.Logger, config RiskConfig) *RiskManager {
+	logger = logger.Named("risk-manager")
+	
+	location, offset, err := parseDailyReset(config)
+	if err != nil {
+		logger.Warn("Invalid daily reset config, using 00:00 UTC", zap.Error(err))
+	}
+	
 	return &RiskManager{
-		logger:             logger.Named("risk-manager"),
+		logger:             logger,
 		config:             config,
+		dailyPnLBuckets:    make(map[string]decimal.Decimal),
 		symbolExposure:     make(map[string]decimal.Decimal),
+		symbolRisk:         make(map[string]decimal.Decimal),
 		correlatedExposure: make(map[string]decimal.Decimal),
+		peakEquity:         config.StartingEquity,
+		resetLocation:      location,
+		resetOffset:        offset,
 		riskEvents:         make(chan RiskEvent, 100),
 	}
 }
 
+// parseDailyReset parses the daily reset time and timezone. On error it
+// returns midnight UTC.
+func parseDailyReset(config RiskConfig) (*time.Location, time.Duration, error) {
+	location := time.UTC
+	if config.DailyResetTimezone != "" {
+		loc, err := time.LoadLocation(config.DailyResetTimezone)
+		if err != nil {
+			return time.UTC, 0, fmt.Errorf("invalid daily reset timezone %q: %w", config.DailyResetTimezone, err)
+		}
+		location = loc
+	}
+	
+	if config.DailyResetTime == "" {
+		return location, 0, nil
+	}
+	resetTime, err := time.Parse("15:04", config.DailyResetTime)
+	if err != nil {
+		return time.UTC, 0, fmt.Errorf("invalid daily reset time %q: %w", config.DailyResetTime, err)
+	}
+	
+	offset := time.Duration(resetTime.Hour())*time.Hour + time.Duration(resetTime.Minute())*time.Minute
+	return location, offset, nil
+}
+
+// Start resets daily stats at each configured daily boundary until ctx is
+// cancelled.
+func (rm *RiskManager) Start(ctx context.Context) error {
+	rm.mu.RLock()
+	_, _, err := parseDailyReset(rm.config)
+	rm.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	
+	go rm.dailyResetLoop(ctx)
+	
+	rm.logger.Info("Daily reset scheduled", zap.Time("nextReset", rm.NextDailyReset(time.Now())))
+	return nil
+}
+
+// dailyResetLoop sleeps until the next boundary, resets, and recomputes the
+// boundary so DST changes and config updates are picked up.
+func (rm *RiskManager) dailyResetLoop(ctx context.Context) {
+	for {
+		timer := time.NewTimer(time.Until(rm.NextDailyReset(time.Now())))
+		
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			rm.ResetDailyStats()
+		}
+	}
+}
+
+// NextDailyReset returns the first daily reset boundary after now.
+func (rm *RiskManager) NextDailyReset(now time.Time) time.Time {
+	rm.mu.RLock()
+	location, offset := rm.resetLocation, rm.resetOffset
+	rm.mu.RUnlock()
+	
+	// Build from wall-clock fields so the boundary is stable across DST
+	hour, minute := int(offset/time.Hour), int(offset%time.Hour/time.Minute)
+	local := now.In(location)
+	next := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, location)
+	if !next.After(now) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+1, hour, minute, 0, 0, location)
+	}
+	return next
+}
+
+// SetPortfolioManager sets the portfolio whose equity CheckOrder uses when
+// called with a zero portfolio value.
+func (rm *RiskManager) SetPortfolioManager(portfolio *PortfolioManager) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.portfolio = portfolio
+}
+
+// SetCorrelationSource groups symbols by their measured correlation instead
+// of CorrelationGroups. An order's correlated exposure is then its symbol's
+// exposure plus that of every symbol correlated at least CorrelationThreshold
+// with it; pairs without enough data are treated as uncorrelated.
+func (rm *RiskManager) SetCorrelationSource(source CorrelationSource) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.correlations = source
+}
+
 // CheckOrder validates an order against risk rules.
 func (rm *RiskManager) CheckOrder(ctx context.Context, order *types.Order, portfolioValue decimal.Decimal) RiskCheckResult {
 	rm.mu.RLock()
 	defer rm.mu.RUnlock()
 	
+	if portfolioValue.IsZero() && rm.portfolio != nil {
+		portfolioValue = rm.portfolio.EquityValue()
+	}
+	
 	result := RiskCheckResult{
 		Approved: true,
 	}
@@ -276,6 +420,32 @@ func (rm *RiskManager) CheckOrder(ctx context.Context, order *types.Order, portf
 		})
 	}
 	
+	// Check weekly loss
+	weeklyPnL := rm.weeklyPnL(time.Now())
+	if weeklyPnL.LessThan(rm.config.MaxWeeklyLoss.Neg()) {
+		result.Approved = false
+		result.Violations = append(result.Violations, RiskViolation{
+			Rule:     "max_weekly_loss",
+			Severity: RiskSeverityCritical,
+			Value:    weeklyPnL,
+			Limit:    rm.config.MaxWeeklyLoss.Neg(),
+			Message:  "Maximum weekly loss reached",
+		})
+	}
+	
+	// Check drawdown from the equity high-water mark
+	drawdown := rm.currentDrawdown()
+	if rm.config.MaxDrawdown.IsPositive() && drawdown.GreaterThan(rm.config.MaxDrawdown) {
+		result.Approved = false
+		result.Violations = append(result.Violations, RiskViolation{
+			Rule:     "max_drawdown",
+			Severity: RiskSeverityCritical,
+			Value:    drawdown,
+			Limit:    rm.config.MaxDrawdown,
+			Message:  "Maximum drawdown exceeded",
+		})
+	}
+	
 	// Check consecutive losses
 	if rm.consecutiveLosses >= rm.config.MaxConsecutiveLosses {
 		result.Approved = false
@@ -319,21 +489,57 @@ func (rm *RiskManager) CheckOrder(ctx context.Context, order *types.Order, portf
 		})
 	}
 	
-	// Check correlated exposure
-	for groupName, symbols := range rm.config.CorrelationGroups {
-		for _, sym := range symbols {
-			if sym == order.Symbol {
-				corrExp := rm.correlatedExposure[groupName].Add(orderValue)
-				maxCorrExp := portfolioValue.Mul(rm.config.MaxCorrelatedExposure)
-				if !portfolioValue.IsZero() && corrExp.GreaterThan(maxCorrExp) {
-					result.Warnings = append(result.Warnings, 
-						fmt.Sprintf("Correlated exposure for %s approaching limit", groupName))
+	// Check correlated exposure, measured when there is a correlation source
+	// and for every configured group the symbol belongs to otherwise
+	maxCorrExp := portfolioValue.Mul(rm.config.MaxCorrelatedExposure)
+	if rm.correlations != nil {
+		corrExp := rm.dynamicCorrelatedExposure(order.Symbol).Add(orderValue)
+		if !portfolioValue.IsZero() && corrExp.GreaterThan(maxCorrExp) {
+			result.Approved = false
+			result.Violations = append(result.Violations, RiskViolation{
+				Rule:     "max_correlated_exposure",
+				Severity: RiskSeverityBlock,
+				Value:    corrExp,
+				Limit:    maxCorrExp,
+				Message:  fmt.Sprintf("Maximum exposure correlated with %s exceeded", order.Symbol),
+			})
+		}
+	} else {
+		for groupName, symbols := range rm.config.CorrelationGroups {
+			for _, sym := range symbols {
+				if sym == order.Symbol {
+					corrExp := rm.correlatedExposure[groupName].Add(orderValue)
+					if !portfolioValue.IsZero() && corrExp.GreaterThan(maxCorrExp) {
+						result.Approved = false
+						result.Violations = append(result.Violations, RiskViolation{
+							Rule:     "max_correlated_exposure",
+							Severity: RiskSeverityBlock,
+							Value:    corrExp,
+							Limit:    maxCorrExp,
+							Message:  fmt.Sprintf("Maximum correlated exposure for group %s exceeded", groupName),
+						})
+					}
+					break
 				}
-				break
 			}
 		}
 	}
 	
+	// Check portfolio heat
+	if equity := rm.heatEquity(); rm.config.MaxPortfolioHeat.IsPositive() && order.Side == types.OrderSideBuy && equity.IsPositive() {
+		heat := rm.totalRisk().Add(rm.riskAtStop(orderValue, order.Price, order.StopPrice)).Div(equity)
+		if heat.GreaterThan(rm.config.MaxPortfolioHeat) {
+			result.Approved = false
+			result.Violations = append(result.Violations, RiskViolation{
+				Rule:     "max_portfolio_heat",
+				Severity: RiskSeverityBlock,
+				Value:    heat,
+				Limit:    rm.config.MaxPortfolioHeat,
+				Message:  "Portfolio heat would exceed maximum",
+			})
+		}
+	}
+	
 	// Check trading hours
 	if !rm.isWithinTradingHours() {
 		result.Warnings = append(result.Warnings, "Order placed outside regular trading hours")
@@ -432,6 +638,7 @@ func (rm *RiskManager) RecordTrade(trade *TradeRecord) {
 	if trade.Side == types.OrderSideBuy {
 		rm.totalExposure = rm.totalExposure.Add(trade.Value)
 		rm.symbolExposure[trade.Symbol] = rm.symbolExposure[trade.Symbol].Add(trade.Value)
+		rm.symbolRisk[trade.Symbol] = rm.symbolRisk[trade.Symbol].Add(rm.riskAtStop(trade.Value, trade.Price, trade.StopLoss))
 		
 		// Update correlated exposure
 		for groupName, symbols := range rm.config.CorrelationGroups {
@@ -444,7 +651,15 @@ func (rm *RiskManager) RecordTrade(trade *TradeRecord) {
 		}
 	} else {
 		rm.totalExposure = rm.totalExposure.Sub(trade.Value)
-		rm.symbolExposure[trade.Symbol] = rm.symbolExposure[trade.Symbol].Sub(trade.Value)
+		previous := rm.symbolExposure[trade.Symbol]
+		rm.symbolExposure[trade.Symbol] = previous.Sub(trade.Value)
+		
+		// Scale risk at stop down with the remaining position
+		if remaining := rm.symbolExposure[trade.Symbol]; remaining.IsPositive() && previous.IsPositive() {
+			rm.symbolRisk[trade.Symbol] = rm.symbolRisk[trade.Symbol].Mul(remaining).Div(previous)
+		} else {
+			delete(rm.symbolRisk, trade.Symbol)
+		}
 		
 		// Update correlated exposure
 		for groupName, symbols := range rm.config.CorrelationGroups {
@@ -457,6 +672,14 @@ func (rm *RiskManager) RecordTrade(trade *TradeRecord) {
 		}
 	}
 	
+	// Roll the weekly buckets and book P&L to the trade's day
+	tradeTime := trade.Timestamp
+	if tradeTime.IsZero() {
+		tradeTime = time.Now()
+	}
+	rm.rollPnLBuckets(time.Now())
+	rm.dailyPnLBuckets[rm.pnlBucketKey(tradeTime)] = rm.dailyPnLBuckets[rm.pnlBucketKey(tradeTime)].Add(trade.PnL)
+	
 	// Track P&L and consecutive losses
 	if trade.PnL.LessThan(decimal.Zero) {
 		rm.dailyPnL = rm.dailyPnL.Add(trade.PnL)
@@ -471,6 +694,19 @@ func (rm *RiskManager) RecordTrade(trade *TradeRecord) {
 		rm.consecutiveLosses = 0
 	}
 	
+	// Update the equity high-water mark
+	rm.cumulativePnL = rm.cumulativePnL.Add(trade.PnL)
+	equity := rm.equity()
+	if equity.GreaterThan(rm.peakEquity) {
+		rm.peakEquity = equity
+	}
+	
+	drawdown := rm.currentDrawdown()
+	if !rm.isDisabled && rm.config.HardDrawdownLimit.IsPositive() && drawdown.GreaterThanOrEqual(rm.config.HardDrawdownLimit) {
+		rm.triggerKillSwitch(fmt.Sprintf("Drawdown %s exceeded hard limit %s",
+			drawdown.StringFixed(4), rm.config.HardDrawdownLimit.StringFixed(4)))
+	}
+	
 	rm.logger.Info("Trade recorded",
 		zap.String("symbol", trade.Symbol),
 		zap.String("pnl", trade.PnL.String()),
@@ -480,10 +716,181 @@ func (rm *RiskManager) RecordTrade(trade *TradeRecord) {
 
 // TradeRecord represents a completed trade.
 type TradeRecord struct {
-	Symbol string
-	Side   types.OrderSide
-	Value  decimal.Decimal
-	PnL    decimal.Decimal
+	Symbol    string
+	Side      types.OrderSide
+	Value     decimal.Decimal
+	PnL       decimal.Decimal
+	Price     decimal.Decimal // Fill price, used with StopLoss for risk at stop
+	StopLoss  decimal.Decimal // Falls back to DefaultStopLoss when zero
+	Timestamp time.Time       // Defaults to now when zero
+}
+
+// riskAtStop returns the loss on value if price moves to stop. Without a
+// usable stop, DefaultStopLoss is applied.
+func (rm *RiskManager) riskAtStop(value, price, stop decimal.Decimal) decimal.Decimal {
+	if price.IsPositive() && stop.IsPositive() {
+		return value.Mul(price.Sub(stop).Abs()).Div(price)
+	}
+	return value.Mul(rm.config.DefaultStopLoss)
+}
+
+// totalRisk sums risk at stop across open positions.
+func (rm *RiskManager) totalRisk() decimal.Decimal {
+	total := decimal.Zero
+	for _, risk := range rm.symbolRisk {
+		total = total.Add(risk)
+	}
+	return total
+}
+
+// dynamicCorrelatedExposure sums the exposure of symbol and of every symbol
+// correlated with it at or above the threshold.
+func (rm *RiskManager) dynamicCorrelatedExposure(symbol string) decimal.Decimal {
+	key := NormalizeSymbol(symbol)
+	threshold := rm.config.CorrelationThreshold.InexactFloat64()
+	
+	total := decimal.Zero
+	for sym, exposure := range rm.symbolExposure {
+		if !exposure.IsPositive() {
+			continue
+		}
+		other := NormalizeSymbol(sym)
+		if other != key {
+			if corr, ok := rm.correlations.Correlation(key, other); !ok || corr < threshold {
+				continue
+			}
+		}
+		total = total.Add(exposure)
+	}
+	return total
+}
+
+// PortfolioHeat returns total risk at stop across open positions as a
+// fraction of current equity. CheckOrder measures new risk against the same
+// equity.
+func (rm *RiskManager) PortfolioHeat() decimal.Decimal {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	
+	return rm.portfolioHeat()
+}
+
+func (rm *RiskManager) portfolioHeat() decimal.Decimal {
+	equity := rm.heatEquity()
+	if !equity.IsPositive() {
+		return decimal.Zero
+	}
+	return rm.totalRisk().Div(equity)
+}
+
+// CalculateVaR estimates one-period Value-at-Risk by historical simulation.
+// Each period's portfolio P&L is the sum of current symbol exposure times
+// that symbol's return; the series are aligned on their most recent values.
+// The result is the loss at the given confidence level (e.g. 0.95).
+func (rm *RiskManager) CalculateVaR(confidence float64, returns map[string][]float64) decimal.Decimal {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	
+	if confidence <= 0 || confidence >= 1 {
+		return decimal.Zero
+	}
+	
+	// Only symbols with exposure and history contribute
+	periods := math.MaxInt
+	exposures := make(map[string]float64)
+	for symbol, exposure := range rm.symbolExposure {
+		series := returns[symbol]
+		if exposure.IsZero() || len(series) == 0 {
+			continue
+		}
+		exposures[symbol] = exposure.InexactFloat64()
+		if len(series) < periods {
+			periods = len(series)
+		}
+	}
+	if len(exposures) == 0 {
+		rm.valueAtRisk = decimal.Zero
+		return rm.valueAtRisk
+	}
+	
+	pnl := make([]float64, periods)
+	for symbol, exposure := range exposures {
+		series := returns[symbol]
+		offset := len(series) - periods
+		for i := 0; i < periods; i++ {
+			pnl[i] += exposure * series[offset+i]
+		}
+	}
+	sort.Float64s(pnl)
+	
+	// The loss exceeded in at most (1-confidence) of periods; the epsilon
+	// absorbs float error in e.g. (1-0.9)*10
+	index := int(math.Ceil((1-confidence)*float64(periods)-1e-9)) - 1
+	if index < 0 {
+		index = 0
+	}
+	
+	rm.valueAtRisk = decimal.Zero
+	if pnl[index] < 0 {
+		rm.valueAtRisk = decimal.NewFromFloat(-pnl[index])
+	}
+	return rm.valueAtRisk
+}
+
+// equity returns starting equity plus all recorded P&L.
+func (rm *RiskManager) equity() decimal.Decimal {
+	return rm.config.StartingEquity.Add(rm.cumulativePnL)
+}
+
+// heatEquity returns the equity portfolio heat is measured against: the
+// portfolio's when one is set, otherwise starting equity plus recorded P&L.
+func (rm *RiskManager) heatEquity() decimal.Decimal {
+	if rm.portfolio != nil {
+		if equity := rm.portfolio.EquityValue(); equity.IsPositive() {
+			return equity
+		}
+	}
+	return rm.equity()
+}
+
+// currentDrawdown returns the fractional decline from peak equity.
+func (rm *RiskManager) currentDrawdown() decimal.Decimal {
+	if !rm.peakEquity.IsPositive() {
+		return decimal.Zero
+	}
+	drawdown := rm.peakEquity.Sub(rm.equity()).Div(rm.peakEquity)
+	if drawdown.IsNegative() {
+		return decimal.Zero
+	}
+	return drawdown
+}
+
+// weeklyLossDays is the number of daily buckets in the weekly loss window.
+const weeklyLossDays = 7
+
+// pnlBucketKey returns the daily bucket key for a time. Days start at the
+// configured daily reset time.
+func (rm *RiskManager) pnlBucketKey(t time.Time) string {
+	return t.In(rm.resetLocation).Add(-rm.resetOffset).Format("2006-01-02")
+}
+
+// rollPnLBuckets drops daily buckets that have left the weekly window.
+func (rm *RiskManager) rollPnLBuckets(now time.Time) {
+	oldest := rm.pnlBucketKey(now.AddDate(0, 0, -(weeklyLossDays - 1)))
+	for key := range rm.dailyPnLBuckets {
+		if key < oldest {
+			delete(rm.dailyPnLBuckets, key)
+		}
+	}
+}
+
+// weeklyPnL sums realized P&L over the last seven days, including today.
+func (rm *RiskManager) weeklyPnL(now time.Time) decimal.Decimal {
+	total := decimal.Zero
+	for i := 0; i < weeklyLossDays; i++ {
+		total = total.Add(rm.dailyPnLBuckets[rm.pnlBucketKey(now.AddDate(0, 0, -i))])
+	}
+	return total
 }
 
 // triggerKillSwitch activates the kill switch.
@@ -549,15 +956,32 @@ func (rm *RiskManager) IsDisabled() bool {
 	return rm.isDisabled && time.Now().Before(rm.disabledUntil)
 }
 
-// ResetDailyStats resets daily statistics.
+// ResetDailyStats resets daily statistics. Weekly P&L is kept; only
+// buckets older than the weekly window are dropped. A daily_reset event
+// carries the closing day's stats.
 func (rm *RiskManager) ResetDailyStats() {
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
 	
+	now := time.Now()
+	rm.sendRiskEvent(RiskEvent{
+		Type:    "daily_reset",
+		Message: "Daily stats reset",
+		Data: map[string]any{
+			"dailyPnL":          rm.dailyPnL,
+			"dailyTrades":       rm.dailyTrades,
+			"dailyVolume":       rm.dailyVolume,
+			"consecutiveLosses": rm.consecutiveLosses,
+			"weeklyPnL":         rm.weeklyPnL(now),
+		},
+		Timestamp: now,
+	})
+	
 	rm.dailyPnL = decimal.Zero
 	rm.dailyTrades = 0
 	rm.dailyVolume = decimal.Zero
 	rm.consecutiveLosses = 0
+	rm.rollPnLBuckets(now)
 	
 	rm.logger.Info("Daily stats reset")
 }
@@ -571,8 +995,13 @@ func (rm *RiskManager) GetStats() RiskStats {
 		DailyPnL:          rm.dailyPnL,
 		DailyTrades:       rm.dailyTrades,
 		DailyVolume:       rm.dailyVolume,
+		WeeklyPnL:         rm.weeklyPnL(time.Now()),
 		ConsecutiveLosses: rm.consecutiveLosses,
 		TotalExposure:     rm.totalExposure,
+		CurrentDrawdown:   rm.currentDrawdown(),
+		PeakEquity:        rm.peakEquity,
+		PortfolioHeat:     rm.portfolioHeat(),
+		ValueAtRisk:       rm.valueAtRisk,
 		IsDisabled:        rm.isDisabled,
 		DisabledUntil:     rm.disabledUntil,
 		ViolationCount:    len(rm.violations),
@@ -584,8 +1013,13 @@ type RiskStats struct {
 	DailyPnL          decimal.Decimal `json:"dailyPnL"`
 	DailyTrades       int             `json:"dailyTrades"`
 	DailyVolume       decimal.Decimal `json:"dailyVolume"`
+	WeeklyPnL         decimal.Decimal `json:"weeklyPnL"`
 	ConsecutiveLosses int             `json:"consecutiveLosses"`
 	TotalExposure     decimal.Decimal `json:"totalExposure"`
+	CurrentDrawdown   decimal.Decimal `json:"currentDrawdown"`
+	PeakEquity        decimal.Decimal `json:"peakEquity"`
+	PortfolioHeat     decimal.Decimal `json:"portfolioHeat"`
+	ValueAtRisk       decimal.Decimal `json:"valueAtRisk"` // Last CalculateVaR result
 	IsDisabled        bool            `json:"isDisabled"`
 	DisabledUntil     time.Time       `json:"disabledUntil,omitempty"`
 	ViolationCount    int             `json:"violationCount"`
@@ -669,6 +1103,14 @@ func (rm *RiskManager) UpdateConfig(config RiskConfig) {
 	defer rm.mu.Unlock()
 	
 	rm.config = config
+	location, offset, err := parseDailyReset(config)
+	if err != nil {
+		rm.logger.Warn("Invalid daily reset config, using 00:00 UTC", zap.Error(err))
+	}
+	rm.resetLocation, rm.resetOffset = location, offset
+	if equity := rm.equity(); equity.GreaterThan(rm.peakEquity) {
+		rm.peakEquity = equity
+	}
 	rm.logger.Info("Risk config updated")
 }
 
@@ -0,0 +1,426 @@
+// Package execution_test provides tests for order execution and risk management.
+package execution_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func smallOrder() *types.Order {
+	return &types.Order{
+		Symbol:   "BTC/USD",
+		Side:     types.OrderSideBuy,
+		Quantity: decimal.NewFromFloat(0.01),
+		Price:    decimal.NewFromInt(100),
+	}
+}
+
+func recordLoss(rm *execution.RiskManager, loss int64, at time.Time) {
+	rm.RecordTrade(&execution.TradeRecord{
+		Symbol:    "BTC/USD",
+		Side:      types.OrderSideSell,
+		Value:     decimal.NewFromInt(1000),
+		PnL:       decimal.NewFromInt(-loss),
+		Timestamp: at,
+	})
+}
+
+func hasViolation(result execution.RiskCheckResult, rule string) *execution.RiskViolation {
+	for i := range result.Violations {
+		if result.Violations[i].Rule == rule {
+			return &result.Violations[i]
+		}
+	}
+	return nil
+}
+
+// weeklyLossConfig keeps drawdown limits out of weekly loss tests.
+func weeklyLossConfig() execution.RiskConfig {
+	config := execution.DefaultRiskConfig()
+	config.StartingEquity = decimal.NewFromInt(100000)
+	return config
+}
+
+func TestWeeklyLossAccumulatesAcrossDays(t *testing.T) {
+	rm := execution.NewRiskManager(zap.NewNop(), weeklyLossConfig())
+	now := time.Now()
+
+	// Each day stays under the $500 daily limit but the week exceeds $1,500
+	recordLoss(rm, 450, now.AddDate(0, 0, -3))
+	rm.ResetDailyStats()
+	recordLoss(rm, 450, now.AddDate(0, 0, -2))
+	rm.ResetDailyStats()
+	recordLoss(rm, 450, now.AddDate(0, 0, -1))
+	rm.ResetDailyStats()
+	recordLoss(rm, 200, now)
+
+	stats := rm.GetStats()
+	if !stats.WeeklyPnL.Equal(decimal.NewFromInt(-1550)) {
+		t.Errorf("Expected weekly PnL of -1550, got %s", stats.WeeklyPnL)
+	}
+	if !stats.DailyPnL.Equal(decimal.NewFromInt(-200)) {
+		t.Errorf("Expected daily PnL of -200 after reset, got %s", stats.DailyPnL)
+	}
+
+	result := rm.CheckOrder(context.Background(), smallOrder(), decimal.NewFromInt(100000))
+	if result.Approved {
+		t.Fatal("Expected order to be rejected after weekly loss limit")
+	}
+	violation := hasViolation(result, "max_weekly_loss")
+	if violation == nil {
+		t.Fatalf("Expected max_weekly_loss violation, got %+v", result.Violations)
+	}
+	if violation.Severity != execution.RiskSeverityCritical {
+		t.Errorf("Expected critical severity, got %s", violation.Severity)
+	}
+	if hasViolation(result, "max_daily_loss") != nil {
+		t.Error("Did not expect a daily loss violation")
+	}
+}
+
+func TestWeeklyLossExcludesOldDays(t *testing.T) {
+	rm := execution.NewRiskManager(zap.NewNop(), weeklyLossConfig())
+	now := time.Now()
+
+	recordLoss(rm, 900, now.AddDate(0, 0, -8))
+	rm.ResetDailyStats()
+	recordLoss(rm, 900, now.AddDate(0, 0, -7))
+	rm.ResetDailyStats()
+	recordLoss(rm, 300, now)
+
+	if weekly := rm.GetStats().WeeklyPnL; !weekly.Equal(decimal.NewFromInt(-300)) {
+		t.Errorf("Expected only the last seven days in weekly PnL, got %s", weekly)
+	}
+
+	result := rm.CheckOrder(context.Background(), smallOrder(), decimal.NewFromInt(100000))
+	if hasViolation(result, "max_weekly_loss") != nil {
+		t.Error("Did not expect a weekly loss violation for losses older than a week")
+	}
+}
+
+// drawdownConfig isolates drawdown checks from the loss limits.
+func drawdownConfig() execution.RiskConfig {
+	config := execution.DefaultRiskConfig()
+	config.MaxDailyLoss = decimal.NewFromInt(100000)
+	config.MaxWeeklyLoss = decimal.NewFromInt(100000)
+	config.KillSwitchThreshold = decimal.NewFromInt(100000)
+	config.MaxConsecutiveLosses = 100
+	return config
+}
+
+func TestDrawdownFromHighWaterMarkBlocksOrders(t *testing.T) {
+	rm := execution.NewRiskManager(zap.NewNop(), drawdownConfig())
+
+	rm.RecordTrade(&execution.TradeRecord{Symbol: "BTC/USD", Side: types.OrderSideSell, PnL: decimal.NewFromInt(1000)})
+	recordLoss(rm, 1200, time.Now())
+
+	stats := rm.GetStats()
+	if !stats.PeakEquity.Equal(decimal.NewFromInt(11000)) {
+		t.Errorf("Expected peak equity of 11000, got %s", stats.PeakEquity)
+	}
+	// 1200 below an 11000 peak
+	expected := decimal.NewFromInt(1200).Div(decimal.NewFromInt(11000))
+	if !stats.CurrentDrawdown.Equal(expected) {
+		t.Errorf("Expected drawdown %s, got %s", expected, stats.CurrentDrawdown)
+	}
+	if stats.IsDisabled {
+		t.Error("Did not expect the kill switch below the hard drawdown limit")
+	}
+
+	result := rm.CheckOrder(context.Background(), smallOrder(), decimal.NewFromInt(100000))
+	violation := hasViolation(result, "max_drawdown")
+	if result.Approved || violation == nil {
+		t.Fatalf("Expected max_drawdown violation, got %+v", result.Violations)
+	}
+	if violation.Severity != execution.RiskSeverityCritical {
+		t.Errorf("Expected critical severity, got %s", violation.Severity)
+	}
+}
+
+func TestHardDrawdownTriggersKillSwitch(t *testing.T) {
+	rm := execution.NewRiskManager(zap.NewNop(), drawdownConfig())
+
+	recordLoss(rm, 1000, time.Now())
+	if rm.IsDisabled() {
+		t.Fatal("Did not expect the kill switch at 10% drawdown")
+	}
+
+	recordLoss(rm, 600, time.Now())
+	if !rm.IsDisabled() {
+		t.Error("Expected the kill switch once drawdown passed the hard limit")
+	}
+}
+
+func TestCorrelatedExposureBlocksOrders(t *testing.T) {
+	config := weeklyLossConfig()
+	config.CorrelationGroups["majors"] = []string{"ETH/USD", "BNB/USD"}
+	rm := execution.NewRiskManager(zap.NewNop(), config)
+	portfolio := decimal.NewFromInt(100000)
+
+	// $25,000 across BTC and ETH, under the 20% symbol limit each
+	rm.RecordTrade(&execution.TradeRecord{Symbol: "BTC/USD", Side: types.OrderSideBuy, Value: decimal.NewFromInt(15000)})
+	rm.RecordTrade(&execution.TradeRecord{Symbol: "ETH/USD", Side: types.OrderSideBuy, Value: decimal.NewFromInt(10000)})
+
+	// A further $6,000 of SOL pushes btc-correlated to $31,000, past 30%
+	order := &types.Order{
+		Symbol:   "SOL/USD",
+		Side:     types.OrderSideBuy,
+		Quantity: decimal.NewFromInt(60),
+		Price:    decimal.NewFromInt(100),
+	}
+	result := rm.CheckOrder(context.Background(), order, portfolio)
+	violation := hasViolation(result, "max_correlated_exposure")
+	if result.Approved || violation == nil {
+		t.Fatalf("Expected max_correlated_exposure violation, got %+v", result.Violations)
+	}
+	if violation.Severity != execution.RiskSeverityBlock {
+		t.Errorf("Expected block severity, got %s", violation.Severity)
+	}
+	if !strings.Contains(violation.Message, "btc-correlated") {
+		t.Errorf("Expected message to name the group, got %q", violation.Message)
+	}
+
+	// ETH is in both groups; only btc-correlated is over the limit
+	order.Symbol = "ETH/USD"
+	result = rm.CheckOrder(context.Background(), order, portfolio)
+	count := 0
+	for _, v := range result.Violations {
+		if v.Rule == "max_correlated_exposure" {
+			count++
+			if strings.Contains(v.Message, "majors") {
+				t.Errorf("Did not expect majors group violation: %q", v.Message)
+			}
+		}
+	}
+	if count != 1 {
+		t.Errorf("Expected one correlated exposure violation, got %d", count)
+	}
+
+	// A small order stays within the limit
+	order.Symbol = "SOL/USD"
+	order.Quantity = decimal.NewFromInt(40)
+	if result := rm.CheckOrder(context.Background(), order, portfolio); hasViolation(result, "max_correlated_exposure") != nil {
+		t.Error("Did not expect a violation within the correlated exposure limit")
+	}
+}
+
+// correlations reports fixed correlations between normalized symbols
+type correlations map[[2]string]float64
+
+func (c correlations) Correlation(a, b string) (float64, bool) {
+	if corr, ok := c[[2]string{a, b}]; ok {
+		return corr, true
+	}
+	corr, ok := c[[2]string{b, a}]
+	return corr, ok
+}
+
+func TestMeasuredCorrelationReplacesGroups(t *testing.T) {
+	rm := execution.NewRiskManager(zap.NewNop(), weeklyLossConfig())
+	rm.SetCorrelationSource(correlations{
+		{"BTCUSD", "SOLUSD"}: 0.9,
+		{"BTCUSD", "ETHUSD"}: 0.5,
+	})
+	portfolio := decimal.NewFromInt(100000)
+
+	rm.RecordTrade(&execution.TradeRecord{Symbol: "BTC/USD", Side: types.OrderSideBuy, Value: decimal.NewFromInt(15000)})
+	rm.RecordTrade(&execution.TradeRecord{Symbol: "SOL/USD", Side: types.OrderSideBuy, Value: decimal.NewFromInt(10000)})
+
+	// ETH is in the static btc-correlated group but measured below 0.7
+	order := &types.Order{
+		Symbol:   "ETH/USD",
+		Side:     types.OrderSideBuy,
+		Quantity: decimal.NewFromInt(60),
+		Price:    decimal.NewFromInt(100),
+	}
+	if result := rm.CheckOrder(context.Background(), order, portfolio); hasViolation(result, "max_correlated_exposure") != nil {
+		t.Errorf("Did not expect a violation for a weakly correlated symbol, got %+v", result.Violations)
+	}
+
+	// More BTC adds to the $25,000 held in BTC and SOL, past 30%
+	order.Symbol = "BTC/USD"
+	result := rm.CheckOrder(context.Background(), order, portfolio)
+	violation := hasViolation(result, "max_correlated_exposure")
+	if result.Approved || violation == nil {
+		t.Fatalf("Expected max_correlated_exposure violation, got %+v", result.Violations)
+	}
+	if !violation.Value.Equal(decimal.NewFromInt(31000)) {
+		t.Errorf("Expected $31,000 of correlated exposure, got %s", violation.Value)
+	}
+}
+
+func TestNextDailyResetUsesConfiguredTimezone(t *testing.T) {
+	config := execution.DefaultRiskConfig()
+	config.DailyResetTime = "17:30"
+	config.DailyResetTimezone = "America/New_York"
+	rm := execution.NewRiskManager(zap.NewNop(), config)
+
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"before reset", time.Date(2024, 3, 9, 12, 0, 0, 0, ny), time.Date(2024, 3, 9, 17, 30, 0, 0, ny)},
+		{"after reset", time.Date(2024, 3, 9, 18, 0, 0, 0, ny), time.Date(2024, 3, 10, 17, 30, 0, 0, ny)},
+		{"at reset", time.Date(2024, 3, 10, 17, 30, 0, 0, ny), time.Date(2024, 3, 11, 17, 30, 0, 0, ny)},
+		{"from UTC", time.Date(2024, 3, 9, 22, 0, 0, 0, time.UTC), time.Date(2024, 3, 9, 17, 30, 0, 0, ny)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rm.NextDailyReset(tt.now); !got.Equal(tt.want) {
+				t.Errorf("Expected next reset %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResetDailyStatsEmitsEvent(t *testing.T) {
+	rm := execution.NewRiskManager(zap.NewNop(), weeklyLossConfig())
+	recordLoss(rm, 100, time.Now())
+
+	rm.ResetDailyStats()
+
+	select {
+	case event := <-rm.Events():
+		if event.Type != "daily_reset" {
+			t.Fatalf("Expected daily_reset event, got %s", event.Type)
+		}
+		data, ok := event.Data.(map[string]any)
+		if !ok {
+			t.Fatalf("Expected map event data, got %T", event.Data)
+		}
+		if pnl, _ := data["dailyPnL"].(decimal.Decimal); !pnl.Equal(decimal.NewFromInt(-100)) {
+			t.Errorf("Expected closing daily PnL of -100, got %v", data["dailyPnL"])
+		}
+	default:
+		t.Fatal("Expected a daily_reset event")
+	}
+
+	stats := rm.GetStats()
+	if !stats.DailyPnL.IsZero() || stats.DailyTrades != 0 {
+		t.Errorf("Expected daily stats to be cleared, got %+v", stats)
+	}
+	if !stats.WeeklyPnL.Equal(decimal.NewFromInt(-100)) {
+		t.Errorf("Expected weekly PnL to keep the reset day, got %s", stats.WeeklyPnL)
+	}
+}
+
+func TestStartRejectsInvalidTimezone(t *testing.T) {
+	config := execution.DefaultRiskConfig()
+	config.DailyResetTimezone = "Mars/Olympus_Mons"
+	rm := execution.NewRiskManager(zap.NewNop(), config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := rm.Start(ctx); err == nil {
+		t.Error("Expected error for an invalid reset timezone")
+	}
+}
+
+func buyTrade(symbol string, value, price, stop int64) *execution.TradeRecord {
+	return &execution.TradeRecord{
+		Symbol:   symbol,
+		Side:     types.OrderSideBuy,
+		Value:    decimal.NewFromInt(value),
+		Price:    decimal.NewFromInt(price),
+		StopLoss: decimal.NewFromInt(stop),
+	}
+}
+
+func TestVaRScalesWithExposure(t *testing.T) {
+	returns := map[string][]float64{
+		"BTC/USD": {0.01, -0.02, 0.015, -0.05, 0.03, -0.01, 0.02, -0.03, 0.005, 0.01},
+	}
+
+	rm := execution.NewRiskManager(zap.NewNop(), weeklyLossConfig())
+	if varValue := rm.CalculateVaR(0.95, returns); !varValue.IsZero() {
+		t.Errorf("Expected zero VaR without exposure, got %s", varValue)
+	}
+
+	rm.RecordTrade(buyTrade("BTC/USD", 1000, 100, 95))
+	single := rm.CalculateVaR(0.9, returns)
+	// At 90% over ten periods only the worst loss is in the tail: 5% of 1000
+	if !single.Equal(decimal.NewFromInt(50)) {
+		t.Errorf("Expected VaR of 50, got %s", single)
+	}
+
+	rm.RecordTrade(buyTrade("BTC/USD", 1000, 100, 95))
+	double := rm.CalculateVaR(0.9, returns)
+	if !double.Equal(single.Mul(decimal.NewFromInt(2))) {
+		t.Errorf("Expected VaR to double with exposure, got %s from %s", double, single)
+	}
+	if !rm.GetStats().ValueAtRisk.Equal(double) {
+		t.Errorf("Expected stats to report the last VaR, got %s", rm.GetStats().ValueAtRisk)
+	}
+}
+
+func TestPortfolioHeatLimitsNewRisk(t *testing.T) {
+	config := weeklyLossConfig()
+	config.MaxPortfolioHeat = decimal.NewFromFloat(0.01)
+	rm := execution.NewRiskManager(zap.NewNop(), config)
+
+	// $8,000 with a 10% stop risks $800 of $100,000 equity
+	rm.RecordTrade(buyTrade("BTC/USD", 8000, 100, 90))
+	if heat := rm.PortfolioHeat(); !heat.Equal(decimal.NewFromFloat(0.008)) {
+		t.Errorf("Expected portfolio heat 0.008, got %s", heat)
+	}
+
+	// A further $5,000 at the 5% default stop adds $250
+	order := &types.Order{
+		Symbol:   "ETH/USD",
+		Side:     types.OrderSideBuy,
+		Quantity: decimal.NewFromInt(50),
+		Price:    decimal.NewFromInt(100),
+	}
+	result := rm.CheckOrder(context.Background(), order, decimal.NewFromInt(100000))
+	if hasViolation(result, "max_portfolio_heat") == nil {
+		t.Fatalf("Expected max_portfolio_heat violation, got %+v", result.Violations)
+	}
+
+	// Closing half the position halves its risk
+	rm.RecordTrade(&execution.TradeRecord{Symbol: "BTC/USD", Side: types.OrderSideSell, Value: decimal.NewFromInt(4000)})
+	if heat := rm.GetStats().PortfolioHeat; !heat.Equal(decimal.NewFromFloat(0.004)) {
+		t.Errorf("Expected portfolio heat 0.004 after partial close, got %s", heat)
+	}
+	result = rm.CheckOrder(context.Background(), order, decimal.NewFromInt(100000))
+	if hasViolation(result, "max_portfolio_heat") != nil {
+		t.Error("Did not expect a portfolio heat violation after reducing risk")
+	}
+}
+
+func TestPortfolioHeatCheckUsesReportedEquity(t *testing.T) {
+	config := weeklyLossConfig()
+	config.MaxPortfolioHeat = decimal.NewFromFloat(0.01)
+	rm := execution.NewRiskManager(zap.NewNop(), config)
+	rm.RecordTrade(buyTrade("BTC/USD", 8000, 100, 90))
+
+	// A larger caller-supplied value must not dilute heat below what
+	// PortfolioHeat reports against equity
+	order := &types.Order{
+		Symbol:   "ETH/USD",
+		Side:     types.OrderSideBuy,
+		Quantity: decimal.NewFromInt(50),
+		Price:    decimal.NewFromInt(100),
+	}
+	result := rm.CheckOrder(context.Background(), order, decimal.NewFromInt(1000000))
+	violation := hasViolation(result, "max_portfolio_heat")
+	if violation == nil {
+		t.Fatalf("Expected max_portfolio_heat violation, got %+v", result.Violations)
+	}
+	if !violation.Value.Equal(decimal.NewFromFloat(0.0105)) {
+		t.Errorf("Expected heat 0.0105 against $100,000 equity, got %s", violation.Value)
+	}
+}
@@ -0,0 +1,456 @@
+// Package execution provides smart order routing across exchange venues.
+package execution
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/fees"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// RouterConfig configures the smart order router.
+type RouterConfig struct {
+	BookDepth      int             `json:"bookDepth"`      // Levels requested from each venue
+	AllowSplit     bool            `json:"allowSplit"`     // Split orders no single venue can fill well
+	MinLegNotional decimal.Decimal `json:"minLegNotional"` // Smallest split leg worth sending, in quote currency
+	QuoteTimeout   time.Duration   `json:"quoteTimeout"`   // How long to wait for venue books
+}
+
+// DefaultRouterConfig returns sensible defaults.
+func DefaultRouterConfig() RouterConfig {
+	return RouterConfig{
+		BookDepth:      20,
+		AllowSplit:     true,
+		MinLegNotional: decimal.NewFromInt(10),
+		QuoteTimeout:   2 * time.Second,
+	}
+}
+
+// VenueQuote is one venue's estimated cost of filling a whole order.
+type VenueQuote struct {
+	Venue          string           `json:"venue"`
+	AvgPrice       decimal.Decimal  `json:"avgPrice"` // Book-walk average fill price
+	Slippage       decimal.Decimal  `json:"slippage"` // Allowance from the slippage calculator, as a fraction
+	FeeRate        decimal.Decimal  `json:"feeRate"`
+	EffectivePrice decimal.Decimal  `json:"effectivePrice"`     // AvgPrice after slippage and fees
+	Depth          decimal.Decimal  `json:"depth"`              // Quantity visible in the fetched book
+	Capacity       *decimal.Decimal `json:"capacity,omitempty"` // Quantity the venue balance covers; nil when unknown
+	Error          string           `json:"error,omitempty"`
+}
+
+// RouteLeg is the part of an order sent to one venue.
+type RouteLeg struct {
+	Venue          string          `json:"venue"`
+	Adapter        ExchangeAdapter `json:"-"`
+	Quantity       decimal.Decimal `json:"quantity"`
+	Price          decimal.Decimal `json:"price"`          // Expected average book price
+	EffectivePrice decimal.Decimal `json:"effectivePrice"` // Price after slippage and fees
+}
+
+// RoutePlan is the router's decision for one order.
+type RoutePlan struct {
+	Legs   []RouteLeg   `json:"legs"`
+	Quotes []VenueQuote `json:"quotes"`
+}
+
+// Split reports whether the order is sent to more than one venue.
+func (p *RoutePlan) Split() bool {
+	return len(p.Legs) > 1
+}
+
+// Router picks the venue, or venues, that fill an order at the best
+// effective price. Each venue's book is walked to price the order, the
+// slippage calculator adds its allowance on top and the venue's fee for the
+// order's liquidity is applied. An order goes to the single best venue unless splitting it is
+// cheaper, which happens once it is large enough to walk past the best
+// levels, or no single venue's balance covers it.
+type Router struct {
+	logger    *zap.Logger
+	config    RouterConfig
+	slippage  *SlippageCalculator
+	fees      *fees.FeeModel
+	portfolio *PortfolioManager
+	mu        sync.RWMutex
+}
+
+// venueBook is the fetched state of one venue, used while planning.
+type venueBook struct {
+	adapter  ExchangeAdapter
+	quote    VenueQuote
+	levels   []types.OrderBookLevel // Side the order takes from, best first
+	capacity decimal.Decimal        // Quantity the venue balance covers
+	limited  bool                   // Whether capacity applies
+	factor   decimal.Decimal        // Multiplier from book price to effective price
+}
+
+// NewRouter creates a smart order router charging the default venue fees.
+// The slippage calculator may be nil.
+func NewRouter(logger *zap.Logger, config RouterConfig, slippage *SlippageCalculator) *Router {
+	return &Router{
+		logger:   logger.Named("router"),
+		config:   config,
+		slippage: slippage,
+		fees:     fees.NewFeeModel(fees.DefaultConfig()),
+	}
+}
+
+// SetFeeModel sets the venue fees used to compare effective prices.
+func (r *Router) SetFeeModel(model *fees.FeeModel) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fees = model
+}
+
+// SetPortfolioManager caps each venue's share of an order by the balance the
+// portfolio manager last synced from it.
+func (r *Router) SetPortfolioManager(portfolio *PortfolioManager) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.portfolio = portfolio
+}
+
+// Route plans an order across venues. With split false the order always goes
+// to a single venue. Venues whose book can't be read are skipped; an error is
+// returned when none remain or their balances can't cover the order.
+func (r *Router) Route(ctx context.Context, order *types.Order, venues []ExchangeAdapter, split bool) (*RoutePlan, error) {
+	if len(venues) == 0 {
+		return nil, fmt.Errorf("no venues to route %s", order.Symbol)
+	}
+
+	books := r.fetchBooks(ctx, order, venues)
+
+	plan := &RoutePlan{}
+	var usable []*venueBook
+	for _, book := range books {
+		plan.Quotes = append(plan.Quotes, book.quote)
+		if book.quote.Error == "" {
+			usable = append(usable, book)
+		}
+	}
+	if len(usable) == 0 {
+		return nil, fmt.Errorf("no venue quoted %s", order.Symbol)
+	}
+
+	single := r.bestSingle(order, usable)
+	if split && r.config.AllowSplit {
+		legs, cost, ok := r.splitLegs(order, usable)
+		if ok && len(legs) > 1 && (single == nil || r.better(order.Side, cost, single.quote.EffectivePrice.Mul(order.Quantity))) {
+			plan.Legs = legs
+			return plan, nil
+		}
+	}
+
+	if single == nil {
+		return nil, fmt.Errorf("insufficient balance on any single venue for %s %s", order.Quantity, order.Symbol)
+	}
+	plan.Legs = []RouteLeg{{
+		Venue:          single.quote.Venue,
+		Adapter:        single.adapter,
+		Quantity:       order.Quantity,
+		Price:          single.quote.AvgPrice,
+		EffectivePrice: single.quote.EffectivePrice,
+	}}
+	return plan, nil
+}
+
+// fetchBooks quotes every venue concurrently, keeping the venue order.
+func (r *Router) fetchBooks(ctx context.Context, order *types.Order, venues []ExchangeAdapter) []*venueBook {
+	if r.config.QuoteTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.config.QuoteTimeout)
+		defer cancel()
+	}
+
+	books := make([]*venueBook, len(venues))
+	var wg sync.WaitGroup
+	for i, adapter := range venues {
+		wg.Add(1)
+		go func(i int, adapter ExchangeAdapter) {
+			defer wg.Done()
+			books[i] = r.fetchBook(ctx, order, adapter)
+		}(i, adapter)
+	}
+	wg.Wait()
+
+	return books
+}
+
+// fetchBook reads one venue's book, falling back to its ticker's top of book,
+// and prices the whole order there.
+func (r *Router) fetchBook(ctx context.Context, order *types.Order, adapter ExchangeAdapter) *venueBook {
+	book := &venueBook{adapter: adapter}
+	book.quote.Venue = adapter.Name()
+
+	levels, err := r.bookLevels(ctx, order, adapter)
+	if err != nil {
+		r.logger.Warn("Skipping venue without a quote",
+			zap.String("venue", adapter.Name()),
+			zap.String("symbol", order.Symbol),
+			zap.Error(err))
+		book.quote.Error = err.Error()
+		return book
+	}
+	book.levels = levels
+
+	avgPrice, depth := walkBook(levels, order.Quantity)
+	book.quote.AvgPrice = avgPrice
+	book.quote.Depth = depth
+	book.quote.FeeRate = r.feeRate(order, adapter.Name(), levels[0].Price)
+	book.quote.Slippage = r.slippageAllowance(order, avgPrice)
+
+	one := decimal.NewFromInt(1)
+	if order.Side == types.OrderSideBuy {
+		book.factor = one.Add(book.quote.Slippage).Mul(one.Add(book.quote.FeeRate))
+	} else {
+		book.factor = one.Sub(book.quote.Slippage).Mul(one.Sub(book.quote.FeeRate))
+	}
+	book.quote.EffectivePrice = avgPrice.Mul(book.factor)
+
+	book.capacity, book.limited = r.capacity(order, adapter.Name(), book.quote.EffectivePrice)
+	if book.limited {
+		book.quote.Capacity = &book.capacity
+	}
+
+	return book
+}
+
+// bookLevels returns the side of the venue's book an order takes from.
+func (r *Router) bookLevels(ctx context.Context, order *types.Order, adapter ExchangeAdapter) ([]types.OrderBookLevel, error) {
+	ob, err := adapter.GetOrderBook(ctx, order.Symbol, r.config.BookDepth)
+	if err == nil && ob != nil {
+		levels := ob.Asks
+		if order.Side == types.OrderSideSell {
+			levels = ob.Bids
+		}
+		if len(levels) > 0 {
+			return levels, nil
+		}
+	}
+
+	ticker, tickerErr := adapter.GetTicker(ctx, order.Symbol)
+	if tickerErr != nil {
+		if err == nil {
+			err = fmt.Errorf("empty order book")
+		}
+		return nil, fmt.Errorf("failed to get order book (%v) or ticker: %w", err, tickerErr)
+	}
+
+	price := ticker.AskPrice
+	if order.Side == types.OrderSideSell {
+		price = ticker.BidPrice
+	}
+	if !price.IsPositive() {
+		price = ticker.LastPrice
+	}
+	if !price.IsPositive() {
+		return nil, fmt.Errorf("ticker has no price")
+	}
+
+	// Depth is unknown, so the top of book stands in for the whole order
+	return []types.OrderBookLevel{{Price: price, Quantity: order.Quantity}}, nil
+}
+
+// walkBook returns the average price of filling quantity from levels and the
+// quantity the levels hold. Quantity beyond the visible book is priced at the
+// last level, since deeper levels are at least that far away.
+func walkBook(levels []types.OrderBookLevel, quantity decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	remaining := quantity
+	cost := decimal.Zero
+	depth := decimal.Zero
+
+	for _, level := range levels {
+		depth = depth.Add(level.Quantity)
+		if !remaining.IsPositive() {
+			continue
+		}
+		fill := decimal.Min(remaining, level.Quantity)
+		cost = cost.Add(fill.Mul(level.Price))
+		remaining = remaining.Sub(fill)
+	}
+
+	if remaining.IsPositive() {
+		cost = cost.Add(remaining.Mul(levels[len(levels)-1].Price))
+	}
+	if !quantity.IsPositive() {
+		return levels[0].Price, depth
+	}
+	return cost.Div(quantity), depth
+}
+
+// slippageAllowance asks the slippage calculator for the venue-independent
+// part of its estimate. The book walk already covers spread and depth, so no
+// bid or ask is passed.
+func (r *Router) slippageAllowance(order *types.Order, price decimal.Decimal) decimal.Decimal {
+	if r.slippage == nil {
+		return decimal.Zero
+	}
+
+	priced := *order
+	priced.Price = price
+	estimate := r.slippage.EstimateSlippage(&priced, MarketData{
+		Symbol: order.Symbol,
+		Price:  price,
+	})
+	return estimate.ExpectedSlippage
+}
+
+// feeRate returns a venue's fee rate for the liquidity the order would take
+// or make against the venue's best price.
+func (r *Router) feeRate(order *types.Order, venue string, bestPrice decimal.Decimal) decimal.Decimal {
+	r.mu.RLock()
+	model := r.fees
+	r.mu.RUnlock()
+
+	return model.Rate(venue, fees.LiquidityOf(order, bestPrice))
+}
+
+// capacity returns how much of the order a venue's balance covers: quote cash
+// over the effective price for buys, the held quantity for sells. It reports
+// false when no portfolio manager is set or it has no balance for the venue.
+func (r *Router) capacity(order *types.Order, venue string, effectivePrice decimal.Decimal) (decimal.Decimal, bool) {
+	r.mu.RLock()
+	portfolio := r.portfolio
+	r.mu.RUnlock()
+
+	if portfolio == nil {
+		return decimal.Zero, false
+	}
+
+	if order.Side == types.OrderSideBuy {
+		cash, ok := portfolio.VenueCash(venue)
+		if !ok || !effectivePrice.IsPositive() {
+			return decimal.Zero, false
+		}
+		return cash.Div(effectivePrice), true
+	}
+
+	return portfolio.VenueHolding(venue, order.Symbol)
+}
+
+// covers reports whether a venue's balance covers quantity.
+func (b *venueBook) covers(quantity decimal.Decimal) bool {
+	return !b.limited || b.capacity.GreaterThanOrEqual(quantity)
+}
+
+// bestSingle returns the venue with the best effective price among those
+// whose balance covers the whole order, or nil.
+func (r *Router) bestSingle(order *types.Order, books []*venueBook) *venueBook {
+	var best *venueBook
+	for _, book := range books {
+		if !book.covers(order.Quantity) {
+			continue
+		}
+		if best == nil || r.better(order.Side, book.quote.EffectivePrice, best.quote.EffectivePrice) {
+			best = book
+		}
+	}
+	return best
+}
+
+// better reports whether cost a beats cost b for the order side.
+func (r *Router) better(side types.OrderSide, a, b decimal.Decimal) bool {
+	if side == types.OrderSideBuy {
+		return a.LessThan(b)
+	}
+	return a.GreaterThan(b)
+}
+
+// splitLegs fills the order level by level from whichever venue offers the
+// best effective price next, within each venue's balance. Quantity beyond the
+// visible books goes to the venues with the best last level. Legs too small
+// to be worth sending are folded into the largest leg. It returns the legs,
+// their total effective cost and whether the whole order was placed.
+func (r *Router) splitLegs(order *types.Order, books []*venueBook) ([]RouteLeg, decimal.Decimal, bool) {
+	type offer struct {
+		book      *venueBook
+		price     decimal.Decimal // Effective price
+		quantity  decimal.Decimal
+		unlimited bool // Stands in for depth beyond the visible book
+	}
+
+	var offers []offer
+	for _, book := range books {
+		for _, level := range book.levels {
+			offers = append(offers, offer{book: book, price: level.Price.Mul(book.factor), quantity: level.Quantity})
+		}
+		last := book.levels[len(book.levels)-1]
+		offers = append(offers, offer{book: book, price: last.Price.Mul(book.factor), unlimited: true})
+	}
+	sort.SliceStable(offers, func(i, j int) bool {
+		if offers[i].unlimited != offers[j].unlimited {
+			return !offers[i].unlimited
+		}
+		return r.better(order.Side, offers[i].price, offers[j].price)
+	})
+
+	allocated := make(map[*venueBook]decimal.Decimal)
+	costs := make(map[*venueBook]decimal.Decimal)
+	var venues []*venueBook
+	remaining := order.Quantity
+
+	for _, o := range offers {
+		if !remaining.IsPositive() {
+			break
+		}
+		take := remaining
+		if !o.unlimited {
+			take = decimal.Min(take, o.quantity)
+		}
+		if o.book.limited {
+			take = decimal.Min(take, o.book.capacity.Sub(allocated[o.book]))
+		}
+		if !take.IsPositive() {
+			continue
+		}
+		if _, ok := allocated[o.book]; !ok {
+			venues = append(venues, o.book)
+		}
+		allocated[o.book] = allocated[o.book].Add(take)
+		costs[o.book] = costs[o.book].Add(take.Mul(o.price))
+		remaining = remaining.Sub(take)
+	}
+
+	if remaining.IsPositive() || len(venues) == 0 {
+		return nil, decimal.Zero, false
+	}
+
+	sort.SliceStable(venues, func(i, j int) bool {
+		return allocated[venues[i]].GreaterThan(allocated[venues[j]])
+	})
+	largest := venues[0]
+	kept := venues[:1]
+	for _, book := range venues[1:] {
+		quantity := allocated[book]
+		if quantity.Mul(book.quote.AvgPrice).LessThan(r.config.MinLegNotional) &&
+			largest.covers(allocated[largest].Add(quantity)) {
+			allocated[largest] = allocated[largest].Add(quantity)
+			costs[largest] = costs[largest].Add(quantity.Mul(largest.quote.EffectivePrice))
+			continue
+		}
+		kept = append(kept, book)
+	}
+
+	legs := make([]RouteLeg, 0, len(kept))
+	total := decimal.Zero
+	for _, book := range kept {
+		effective := costs[book].Div(allocated[book])
+		legs = append(legs, RouteLeg{
+			Venue:          book.quote.Venue,
+			Adapter:        book.adapter,
+			Quantity:       allocated[book],
+			Price:          effective.Div(book.factor),
+			EffectivePrice: effective,
+		})
+		total = total.Add(costs[book])
+	}
+
+	return legs, total, true
+}
@@ -0,0 +1,192 @@
+package execution_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/internal/fees"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// venue is a named exchange with a fixed book, cash balance and holdings.
+type venue struct {
+	stubExchange
+	name    string
+	asks    [][2]float64
+	bids    [][2]float64
+	cash    float64
+	bookErr bool
+	last    float64
+}
+
+func (v *venue) Name() string { return v.name }
+func (v *venue) GetBalance(ctx context.Context, asset string) (decimal.Decimal, error) {
+	return decimal.NewFromFloat(v.cash), nil
+}
+func (v *venue) GetOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error) {
+	if v.bookErr {
+		return nil, fmt.Errorf("book unavailable")
+	}
+	levels := func(raw [][2]float64) []types.OrderBookLevel {
+		out := make([]types.OrderBookLevel, len(raw))
+		for i, l := range raw {
+			out[i] = types.OrderBookLevel{Price: decimal.NewFromFloat(l[0]), Quantity: decimal.NewFromFloat(l[1])}
+		}
+		return out
+	}
+	return &types.OrderBook{Symbol: symbol, Asks: levels(v.asks), Bids: levels(v.bids)}, nil
+}
+func (v *venue) GetTicker(ctx context.Context, symbol string) (*adapters.Ticker, error) {
+	return &adapters.Ticker{Symbol: symbol, LastPrice: decimal.NewFromFloat(v.last)}, nil
+}
+
+// newRouter creates a router charging each venue takerBps, and nothing
+// elsewhere.
+func newRouter(takerBps map[string]int64) *execution.Router {
+	config := fees.Config{Venues: make(map[string]types.FeeSchedule)}
+	for name, bps := range takerBps {
+		config.Venues[name] = types.FeeSchedule{TakerBps: decimal.NewFromInt(bps)}
+	}
+
+	router := execution.NewRouter(zap.NewNop(), execution.DefaultRouterConfig(), nil)
+	router.SetFeeModel(fees.NewFeeModel(config))
+	return router
+}
+
+func buy(quantity float64) *types.Order {
+	return &types.Order{Symbol: "BTC/USDT", Side: types.OrderSideBuy, Quantity: decimal.NewFromFloat(quantity)}
+}
+
+func legQuantities(plan *execution.RoutePlan) map[string]string {
+	legs := make(map[string]string)
+	for _, leg := range plan.Legs {
+		legs[leg.Venue] = leg.Quantity.String()
+	}
+	return legs
+}
+
+func TestRouterPicksBestPriceAfterFees(t *testing.T) {
+	cheap := &venue{name: "cheap", asks: [][2]float64{{100, 5}}}
+	lowFee := &venue{name: "lowfee", asks: [][2]float64{{100.3, 5}}}
+	router := newRouter(map[string]int64{"cheap": 50, "lowfee": 10})
+
+	plan, err := router.Route(context.Background(), buy(1), []execution.ExchangeAdapter{cheap, lowFee}, true)
+	if err != nil {
+		t.Fatalf("Route: %v", err)
+	}
+	if plan.Split() || plan.Legs[0].Venue != "lowfee" {
+		t.Fatalf("legs = %v, want all on lowfee", legQuantities(plan))
+	}
+	if got, want := plan.Legs[0].EffectivePrice, decimal.NewFromFloat(100.4003); !got.Equal(want) {
+		t.Fatalf("effective price = %s, want %s", got, want)
+	}
+}
+
+func TestRouterSplitsLargeOrders(t *testing.T) {
+	a := &venue{name: "a", asks: [][2]float64{{100, 1}, {110, 10}}}
+	b := &venue{name: "b", asks: [][2]float64{{101, 1}, {112, 10}}}
+	router := newRouter(nil)
+	venues := []execution.ExchangeAdapter{a, b}
+
+	plan, err := router.Route(context.Background(), buy(2), venues, true)
+	if err != nil {
+		t.Fatalf("Route: %v", err)
+	}
+	if got := legQuantities(plan); len(got) != 2 || got["a"] != "1" || got["b"] != "1" {
+		t.Fatalf("legs = %v, want 1 on each venue", got)
+	}
+
+	// A small order fits the best level and stays on one venue
+	plan, err = router.Route(context.Background(), buy(0.5), venues, true)
+	if err != nil {
+		t.Fatalf("Route: %v", err)
+	}
+	if got := legQuantities(plan); len(got) != 1 || got["a"] != "0.5" {
+		t.Fatalf("legs = %v, want 0.5 on a", got)
+	}
+
+	// Without splitting the whole order goes to the cheaper venue
+	plan, err = router.Route(context.Background(), buy(2), venues, false)
+	if err != nil {
+		t.Fatalf("Route: %v", err)
+	}
+	if got := legQuantities(plan); len(got) != 1 || got["a"] != "2" {
+		t.Fatalf("legs = %v, want 2 on a", got)
+	}
+}
+
+func TestRouterRespectsVenueBalances(t *testing.T) {
+	poor := &venue{name: "poor", asks: [][2]float64{{100, 10}}, cash: 50}
+	rich := &venue{name: "rich", asks: [][2]float64{{101, 10}}, cash: 1000,
+		stubExchange: stubExchange{positions: []*types.Position{
+			{Symbol: "BTCUSDT", Side: types.PositionSideLong, Quantity: decimal.NewFromInt(3)},
+		}}}
+	rich.bids = [][2]float64{{99, 10}}
+	poor.bids = [][2]float64{{100, 10}}
+
+	config := execution.DefaultPortfolioConfig()
+	config.Paper = false
+	pm := execution.NewPortfolioManager(zap.NewNop(), config, poor, rich)
+	if err := pm.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if cash, ok := pm.VenueCash("poor"); !ok || !cash.Equal(decimal.NewFromInt(50)) {
+		t.Fatalf("poor cash = %s, %v, want 50", cash, ok)
+	}
+
+	router := newRouter(nil)
+	router.SetPortfolioManager(pm)
+	venues := []execution.ExchangeAdapter{poor, rich}
+
+	plan, err := router.Route(context.Background(), buy(1), venues, true)
+	if err != nil {
+		t.Fatalf("Route: %v", err)
+	}
+	if got := legQuantities(plan); got["poor"] != "0.5" || got["rich"] != "0.5" {
+		t.Fatalf("legs = %v, want 0.5 on each venue", got)
+	}
+
+	plan, err = router.Route(context.Background(), buy(1), venues, false)
+	if err != nil {
+		t.Fatalf("Route: %v", err)
+	}
+	if got := legQuantities(plan); len(got) != 1 || got["rich"] != "1" {
+		t.Fatalf("legs = %v, want 1 on rich", got)
+	}
+
+	// Only rich holds the asset, so a sale can't use poor's better bid
+	sell := &types.Order{Symbol: "BTC/USDT", Side: types.OrderSideSell, Quantity: decimal.NewFromInt(2)}
+	plan, err = router.Route(context.Background(), sell, venues, true)
+	if err != nil {
+		t.Fatalf("Route: %v", err)
+	}
+	if got := legQuantities(plan); len(got) != 1 || got["rich"] != "2" {
+		t.Fatalf("legs = %v, want 2 on rich", got)
+	}
+
+	if _, err := router.Route(context.Background(), buy(20), venues, true); err == nil {
+		t.Fatal("Route succeeded beyond every venue's balance")
+	}
+}
+
+func TestRouterFallsBackToTicker(t *testing.T) {
+	noBook := &venue{name: "nobook", bookErr: true, last: 99}
+	booked := &venue{name: "booked", asks: [][2]float64{{100, 5}}}
+	router := newRouter(nil)
+
+	plan, err := router.Route(context.Background(), buy(1), []execution.ExchangeAdapter{booked, noBook}, true)
+	if err != nil {
+		t.Fatalf("Route: %v", err)
+	}
+	if got := legQuantities(plan); len(got) != 1 || got["nobook"] != "1" {
+		t.Fatalf("legs = %v, want 1 on nobook", got)
+	}
+	if len(plan.Quotes) != 2 {
+		t.Fatalf("quotes = %d, want 2", len(plan.Quotes))
+	}
+}
@@ -2,10 +2,12 @@
 package execution
 
 import (
+	"fmt"
 	"math"
 	"sync"
 	"time"
 
+	"github.com/atlas-desktop/trading-backend/internal/fees"
 	"github.com/atlas-desktop/trading-backend/pkg/types"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
@@ -22,6 +24,14 @@ type SlippageCalculator struct {
 	
 	// Market impact models
 	orderBooks map[string]*OrderBook
+	
+	// Venue fees, reported alongside slippage when set
+	fees *fees.FeeModel
+	
+	// Models refit to live fills
+	observations     map[string][]slippageObservation
+	calibrations     map[string]SlippageCalibration
+	sinceCalibration map[string]int // Fills since each symbol was last refit
 }
 
 // SlippageConfig contains slippage calculation configuration.
@@ -48,6 +58,11 @@ type SlippageConfig struct {
 	// MEV protection
 	MEVProtectionEnabled bool            `json:"mevProtectionEnabled"`
 	MaxMEVSlippage       decimal.Decimal `json:"maxMevSlippage"`
+	
+	// Calibration from live fills
+	MinCalibrationFills int `json:"minCalibrationFills"` // Fills a symbol needs before its model is refit
+	CalibrationWindow   int `json:"calibrationWindow"`   // Most recent fills a refit uses
+	RecalibrateEvery    int `json:"recalibrateEvery"`    // Fills between automatic refits; 0 leaves it to Recalibrate
 }
 
 // SlippageRecord represents a historical slippage observation.
@@ -89,6 +104,8 @@ type SlippageEstimate struct {
 	Confidence         float64         `json:"confidence"`         // 0-1
 	Factors            []SlippageFactor `json:"factors"`
 	Recommendation     string          `json:"recommendation,omitempty"`
+	Fee                *fees.Fee       `json:"fee,omitempty"`       // Expected venue fee; nil without a fee model
+	TotalCost          decimal.Decimal `json:"totalCost"`           // Slippage plus fee, as a fraction of notional
 }
 
 // SlippageRange represents a range of possible slippage values.
@@ -118,6 +135,9 @@ func DefaultSlippageConfig() SlippageConfig {
 		MaxSlippage:          decimal.NewFromFloat(0.05), // 5%
 		MEVProtectionEnabled: true,
 		MaxMEVSlippage:       decimal.NewFromFloat(0.01), // 1%
+		MinCalibrationFills:  20,
+		CalibrationWindow:    500,
+		RecalibrateEvery:     25,
 	}
 }
 
@@ -128,9 +148,20 @@ func NewSlippageCalculator(logger *zap.Logger, config SlippageConfig) *SlippageC
 		config:             config,
 		historicalSlippage: make(map[string][]SlippageRecord),
 		orderBooks:         make(map[string]*OrderBook),
+		observations:       make(map[string][]slippageObservation),
+		calibrations:       make(map[string]SlippageCalibration),
+		sinceCalibration:   make(map[string]int),
 	}
 }
 
+// SetFeeModel makes estimates include the fee the order's venue charges for
+// the liquidity it would take or make.
+func (sc *SlippageCalculator) SetFeeModel(model *fees.FeeModel) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.fees = model
+}
+
 // EstimateSlippage estimates slippage for an order.
 func (sc *SlippageCalculator) EstimateSlippage(order *types.Order, marketData MarketData) SlippageEstimate {
 	sc.mu.RLock()
@@ -143,32 +174,45 @@ func (sc *SlippageCalculator) EstimateSlippage(order *types.Order, marketData Ma
 	var totalSlippage decimal.Decimal
 	var factors []SlippageFactor
 	
-	// 1. Base slippage
-	baseSlip := sc.config.BaseSlippage
-	totalSlippage = totalSlippage.Add(baseSlip)
-	factors = append(factors, SlippageFactor{
-		Name:         "base",
-		Contribution: baseSlip,
-		Description:  "Base exchange slippage",
-	})
-	
-	// 2. Spread impact
-	spreadSlip := sc.calculateSpreadImpact(order, marketData)
-	totalSlippage = totalSlippage.Add(spreadSlip)
-	factors = append(factors, SlippageFactor{
-		Name:         "spread",
-		Contribution: spreadSlip,
-		Description:  "Bid-ask spread impact",
-	})
-	
-	// 3. Volume impact (market impact)
-	volumeSlip := sc.calculateVolumeImpact(order, marketData)
-	totalSlippage = totalSlippage.Add(volumeSlip)
-	factors = append(factors, SlippageFactor{
-		Name:         "volume",
-		Contribution: volumeSlip,
-		Description:  "Market impact from order size",
-	})
+	// A model fit to live fills already includes base, spread and impact
+	calibration, calibrated := sc.calibrations[order.Symbol]
+	if calibrated {
+		calibratedSlip := calibration.Estimate(order.Quantity.Mul(order.Price))
+		totalSlippage = totalSlippage.Add(calibratedSlip)
+		factors = append(factors, SlippageFactor{
+			Name:         "calibrated",
+			Contribution: calibratedSlip,
+			Description:  fmt.Sprintf("Base and market impact fit to %d live fills", calibration.Samples),
+		})
+		estimate.Confidence = 0.9
+	} else {
+		// 1. Base slippage
+		baseSlip := sc.config.BaseSlippage
+		totalSlippage = totalSlippage.Add(baseSlip)
+		factors = append(factors, SlippageFactor{
+			Name:         "base",
+			Contribution: baseSlip,
+			Description:  "Base exchange slippage",
+		})
+		
+		// 2. Spread impact
+		spreadSlip := sc.calculateSpreadImpact(order, marketData)
+		totalSlippage = totalSlippage.Add(spreadSlip)
+		factors = append(factors, SlippageFactor{
+			Name:         "spread",
+			Contribution: spreadSlip,
+			Description:  "Bid-ask spread impact",
+		})
+		
+		// 3. Volume impact (market impact)
+		volumeSlip := sc.calculateVolumeImpact(order, marketData)
+		totalSlippage = totalSlippage.Add(volumeSlip)
+		factors = append(factors, SlippageFactor{
+			Name:         "volume",
+			Contribution: volumeSlip,
+			Description:  "Market impact from order size",
+		})
+	}
 	
 	// 4. Volatility impact
 	volatilitySlip := sc.calculateVolatilityImpact(marketData)
@@ -204,7 +248,7 @@ func (sc *SlippageCalculator) EstimateSlippage(order *types.Order, marketData Ma
 	
 	// 7. Historical adjustment
 	historicalAdj := sc.calculateHistoricalAdjustment(order.Symbol)
-	if !historicalAdj.IsZero() {
+	if !calibrated && !historicalAdj.IsZero() {
 		totalSlippage = totalSlippage.Add(historicalAdj)
 		factors = append(factors, SlippageFactor{
 			Name:         "historical",
@@ -238,6 +282,24 @@ func (sc *SlippageCalculator) EstimateSlippage(order *types.Order, marketData Ma
 	estimate.MarketImpact = marketImpact
 	estimate.ExpectedFillPrice = expectedFillPrice
 	estimate.Factors = factors
+	estimate.TotalCost = totalSlippage
+	
+	// Add the venue fee, priced against the side of the book the order meets
+	if sc.fees != nil {
+		reference := marketData.Ask
+		if order.Side == types.OrderSideSell {
+			reference = marketData.Bid
+		}
+		if reference.IsZero() {
+			reference = marketData.Price
+		}
+		
+		fee := sc.fees.Fee(marketData.Exchange, fees.LiquidityOf(order, reference), order.Quantity, order.Price)
+		estimate.Fee = &fee
+		if notional := order.Quantity.Mul(order.Price); notional.IsPositive() {
+			estimate.TotalCost = estimate.TotalCost.Add(fee.Quote.Div(notional))
+		}
+	}
 	
 	// Generate recommendation
 	estimate.Recommendation = sc.generateRecommendation(estimate, order)
@@ -443,6 +505,24 @@ func (sc *SlippageCalculator) UpdateOrderBook(symbol string, ob *OrderBook) {
 	sc.orderBooks[symbol] = ob
 }
 
+// UpdateFromOrderBook updates a symbol's order book from an exchange book.
+func (sc *SlippageCalculator) UpdateFromOrderBook(ob *types.OrderBook) {
+	book := &OrderBook{
+		Symbol:    ob.Symbol,
+		Bids:      make([]OrderBookLevel, len(ob.Bids)),
+		Asks:      make([]OrderBookLevel, len(ob.Asks)),
+		UpdatedAt: ob.Timestamp,
+	}
+	for i, level := range ob.Bids {
+		book.Bids[i] = OrderBookLevel(level)
+	}
+	for i, level := range ob.Asks {
+		book.Asks[i] = OrderBookLevel(level)
+	}
+	
+	sc.UpdateOrderBook(ob.Symbol, book)
+}
+
 // DetectMEVAttack detects potential MEV attacks.
 func (sc *SlippageCalculator) DetectMEVAttack(expected, actual decimal.Decimal, blockTime time.Time) (bool, string) {
 	if !sc.config.MEVProtectionEnabled {
@@ -509,6 +589,7 @@ func (sc *SlippageCalculator) GetAverageSlippage(symbol string, period time.Dura
 // MarketData contains market information for slippage calculation.
 type MarketData struct {
 	Symbol    string          `json:"symbol"`
+	Exchange  string          `json:"exchange,omitempty"` // Venue whose fees apply
 	Price     decimal.Decimal `json:"price"`
 	Bid       decimal.Decimal `json:"bid"`
 	Ask       decimal.Decimal `json:"ask"`
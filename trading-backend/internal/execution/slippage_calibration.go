@@ -0,0 +1,240 @@
+package execution
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// slippageObservation is one live fill's slippage against the price the
+// order was expected to fill at.
+type slippageObservation struct {
+	notional float64 // Expected price times size, in the quote asset
+	slippage float64 // As a fraction of the expected price
+}
+
+// SlippageCalibration is a symbol's slippage model refit to its live fills:
+// slippage = Base + ImpactCoefficient * sqrt(notional), with notional in the
+// quote asset. While a symbol has one, it replaces the configured base,
+// spread, volume and historical factors, all of which live fills include.
+type SlippageCalibration struct {
+	Symbol            string          `json:"symbol"`
+	Base              decimal.Decimal `json:"base"`              // Slippage of an arbitrarily small order
+	ImpactCoefficient decimal.Decimal `json:"impactCoefficient"` // Square-root market impact per unit of sqrt(notional)
+	ResidualStdDev    decimal.Decimal `json:"residualStdDev"`    // Spread of the fills around the fit
+	Samples           int             `json:"samples"`
+	FittedAt          time.Time       `json:"fittedAt"`
+}
+
+// Estimate returns the calibrated slippage of an order of notional value.
+func (c SlippageCalibration) Estimate(notional decimal.Decimal) decimal.Decimal {
+	if !notional.IsPositive() {
+		return c.Base
+	}
+	sqrtNotional := decimal.NewFromFloat(math.Sqrt(notional.InexactFloat64()))
+	return c.Base.Add(c.ImpactCoefficient.Mul(sqrtNotional))
+}
+
+// RecordFill records a live fill of size at actual against the expected
+// price, such as the signal's suggested entry or the price when the order
+// was placed. Every RecalibrateEvery fills the symbol's model is refit.
+func (sc *SlippageCalculator) RecordFill(symbol string, size, expected, actual decimal.Decimal) {
+	if !size.IsPositive() || !expected.IsPositive() || !actual.IsPositive() {
+		return
+	}
+
+	slippage := actual.Sub(expected).Div(expected).Abs()
+	isMEV, _ := sc.DetectMEVAttack(expected, actual, time.Now())
+
+	sc.RecordSlippage(SlippageRecord{
+		Symbol:        symbol,
+		ExpectedPrice: expected,
+		ExecutedPrice: actual,
+		Slippage:      slippage,
+		SlippageUSD:   actual.Sub(expected).Abs().Mul(size),
+		OrderSize:     size,
+		Timestamp:     time.Now(),
+		IsMEVAttack:   isMEV,
+	})
+
+	sc.mu.Lock()
+	observations := append(sc.observations[symbol], slippageObservation{
+		notional: expected.Mul(size).InexactFloat64(),
+		slippage: slippage.InexactFloat64(),
+	})
+	if window := sc.config.CalibrationWindow; window > 0 && len(observations) > window {
+		observations = observations[len(observations)-window:]
+	}
+	sc.observations[symbol] = observations
+
+	sc.sinceCalibration[symbol]++
+	refit := sc.config.RecalibrateEvery > 0 && sc.sinceCalibration[symbol] >= sc.config.RecalibrateEvery
+	var calibration SlippageCalibration
+	var fitted bool
+	if refit {
+		calibration, fitted = sc.calibrate(symbol)
+	}
+	sc.mu.Unlock()
+
+	if fitted {
+		sc.logCalibration(calibration)
+	}
+}
+
+// Recalibrate refits the model of every symbol with at least
+// MinCalibrationFills fills, returning the new calibrations sorted by symbol.
+func (sc *SlippageCalculator) Recalibrate() []SlippageCalibration {
+	sc.mu.Lock()
+	symbols := make([]string, 0, len(sc.observations))
+	for symbol := range sc.observations {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	var calibrations []SlippageCalibration
+	for _, symbol := range symbols {
+		if calibration, ok := sc.calibrate(symbol); ok {
+			calibrations = append(calibrations, calibration)
+		}
+	}
+	sc.mu.Unlock()
+
+	for _, calibration := range calibrations {
+		sc.logCalibration(calibration)
+	}
+	return calibrations
+}
+
+// GetCalibration returns a symbol's calibrated model, or false until it has
+// been fit.
+func (sc *SlippageCalculator) GetCalibration(symbol string) (SlippageCalibration, bool) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	calibration, ok := sc.calibrations[symbol]
+	return calibration, ok
+}
+
+// CalibratedBacktestSlippage returns a backtest slippage config applying each
+// calibrated symbol's fit, or false if none of symbols has been calibrated.
+// Symbols without a fit keep the backtester's default model.
+func (sc *SlippageCalculator) CalibratedBacktestSlippage(symbols []string) (types.SlippageConfig, bool) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	config := types.SlippageConfig{Symbols: make(map[string]types.SlippageConfig)}
+	for _, symbol := range symbols {
+		calibration, ok := sc.calibrations[symbol]
+		if !ok {
+			continue
+		}
+		config.Symbols[symbol] = types.SlippageConfig{
+			Model:        "sqrt_impact",
+			FixedBps:     calibration.Base.Mul(decimal.NewFromInt(10000)),
+			ImpactFactor: calibration.ImpactCoefficient,
+		}
+	}
+	return config, len(config.Symbols) > 0
+}
+
+// calibrate refits a symbol's model by least squares of slippage on
+// sqrt(notional). Neither parameter may be negative: a fit where slippage
+// falls with size keeps only the mean, and one with a negative base is
+// forced through the origin. The caller must hold the write lock.
+func (sc *SlippageCalculator) calibrate(symbol string) (SlippageCalibration, bool) {
+	observations := sc.observations[symbol]
+	if len(observations) == 0 || len(observations) < sc.config.MinCalibrationFills {
+		return SlippageCalibration{}, false
+	}
+	sc.sinceCalibration[symbol] = 0
+
+	n := float64(len(observations))
+	var sumX, sumY float64
+	for _, o := range observations {
+		sumX += math.Sqrt(o.notional)
+		sumY += o.slippage
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var covXY, varX, sumXY, sumXX float64
+	for _, o := range observations {
+		x := math.Sqrt(o.notional)
+		covXY += (x - meanX) * (o.slippage - meanY)
+		varX += (x - meanX) * (x - meanX)
+		sumXY += x * o.slippage
+		sumXX += x * x
+	}
+
+	base, impact := meanY, 0.0
+	if varX > 0 {
+		impact = covXY / varX
+		base = meanY - impact*meanX
+	}
+	if impact < 0 {
+		base, impact = meanY, 0
+	}
+	if base < 0 {
+		base, impact = 0, sumXY/sumXX
+	}
+
+	var squared float64
+	for _, o := range observations {
+		residual := o.slippage - (base + impact*math.Sqrt(o.notional))
+		squared += residual * residual
+	}
+
+	calibration := SlippageCalibration{
+		Symbol:            symbol,
+		Base:              decimal.NewFromFloat(base),
+		ImpactCoefficient: decimal.NewFromFloat(impact),
+		ResidualStdDev:    decimal.NewFromFloat(math.Sqrt(squared / n)),
+		Samples:           len(observations),
+		FittedAt:          time.Now(),
+	}
+	sc.calibrations[symbol] = calibration
+	return calibration, true
+}
+
+func (sc *SlippageCalculator) logCalibration(calibration SlippageCalibration) {
+	sc.logger.Info("Slippage model recalibrated",
+		zap.String("symbol", calibration.Symbol),
+		zap.String("base", calibration.Base.String()),
+		zap.String("impactCoefficient", calibration.ImpactCoefficient.String()),
+		zap.Int("samples", calibration.Samples))
+}
+
+// SetSlippageCalculator sets the slippage model live fills are fed into so
+// it can recalibrate to what the venues actually do.
+func (e *Executor) SetSlippageCalculator(calculator *SlippageCalculator) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.calibrator = calculator
+}
+
+// recordSlippage feeds a live fill to the slippage model against the price
+// it was expected to fill at.
+func (e *Executor) recordSlippage(symbol string, expected decimal.Decimal, fill VenueFill) {
+	e.mu.RLock()
+	calibrator := e.calibrator
+	e.mu.RUnlock()
+
+	if calibrator == nil || fill.Error != "" {
+		return
+	}
+	calibrator.RecordFill(symbol, fill.FilledQty, expected, fill.AvgPrice)
+}
+
+// expectedPrice is the price a signal's order is expected to fill at: the
+// signal's price, which aggregated signals set to their suggested entry, or
+// the market price when it has none.
+func expectedPrice(signal *types.Signal, marketPrice decimal.Decimal) decimal.Decimal {
+	if signal != nil && signal.Price.IsPositive() {
+		return signal.Price
+	}
+	return marketPrice
+}
@@ -0,0 +1,93 @@
+package execution_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func TestSlippageRecalibratesFromFills(t *testing.T) {
+	config := execution.DefaultSlippageConfig()
+	config.MinCalibrationFills = 10
+	config.RecalibrateEvery = 0
+	sc := execution.NewSlippageCalculator(zap.NewNop(), config)
+
+	// Fills slip 0.02% plus 0.00001 per sqrt of notional: 0.12% at 10,000
+	expected := decimal.NewFromInt(100)
+	for i := 0; i < 20; i++ {
+		size := decimal.NewFromInt(int64(1 + i*10))
+		slippage := 0.0002 + 0.00001*math.Sqrt(expected.Mul(size).InexactFloat64())
+		actual := expected.Mul(decimal.NewFromFloat(1 + slippage))
+		sc.RecordFill("BTCUSDT", size, expected, actual)
+	}
+
+	if _, ok := sc.GetCalibration("BTCUSDT"); ok {
+		t.Fatal("Expected no calibration before Recalibrate")
+	}
+
+	calibrations := sc.Recalibrate()
+	if len(calibrations) != 1 || calibrations[0].Samples != 20 {
+		t.Fatalf("Expected one calibration from 20 fills, got %+v", calibrations)
+	}
+	calibration := calibrations[0]
+	if math.Abs(calibration.Base.InexactFloat64()-0.0002) > 1e-6 ||
+		math.Abs(calibration.ImpactCoefficient.InexactFloat64()-0.00001) > 1e-7 {
+		t.Errorf("Expected base 0.0002 and impact 0.00001, got %s and %s", calibration.Base, calibration.ImpactCoefficient)
+	}
+
+	estimate := sc.EstimateSlippage(&types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeMarket,
+		Quantity: decimal.NewFromInt(100),
+		Price:    expected,
+	}, execution.MarketData{
+		Symbol: "BTCUSDT",
+		Price:  expected,
+		Bid:    decimal.NewFromFloat(99.9),
+		Ask:    decimal.NewFromFloat(100.1),
+	})
+	var calibrated decimal.Decimal
+	for _, factor := range estimate.Factors {
+		if factor.Name == "spread" || factor.Name == "base" || factor.Name == "historical" {
+			t.Errorf("Expected the calibrated model to replace the %s factor", factor.Name)
+		}
+		if factor.Name == "calibrated" {
+			calibrated = factor.Contribution
+		}
+	}
+	if math.Abs(calibrated.InexactFloat64()-0.0012) > 1e-5 {
+		t.Errorf("Expected 0.12%% calibrated slippage at 10,000 notional, got %s", calibrated)
+	}
+
+	backtest, ok := sc.CalibratedBacktestSlippage([]string{"BTCUSDT", "ETHUSDT"})
+	if !ok || len(backtest.Symbols) != 1 || backtest.Symbols["BTCUSDT"].Model != "sqrt_impact" {
+		t.Errorf("Expected a sqrt_impact backtest model for BTCUSDT only, got %+v", backtest)
+	}
+}
+
+func TestSlippageRecalibratesAutomatically(t *testing.T) {
+	config := execution.DefaultSlippageConfig()
+	config.MinCalibrationFills = 5
+	config.RecalibrateEvery = 5
+	sc := execution.NewSlippageCalculator(zap.NewNop(), config)
+
+	// Slippage that falls with size is no reason to predict negative impact
+	for i := 0; i < 5; i++ {
+		size := decimal.NewFromInt(int64(1 + i))
+		actual := decimal.NewFromFloat(99.9 + float64(i)*0.02)
+		sc.RecordFill("ETHUSDT", size, decimal.NewFromInt(100), actual)
+	}
+
+	calibration, ok := sc.GetCalibration("ETHUSDT")
+	if !ok {
+		t.Fatal("Expected the fifth fill to recalibrate")
+	}
+	if !calibration.ImpactCoefficient.IsZero() || math.Abs(calibration.Base.InexactFloat64()-0.0006) > 1e-9 {
+		t.Errorf("Expected the mean 0.06%% with no impact, got %s and %s", calibration.Base, calibration.ImpactCoefficient)
+	}
+}
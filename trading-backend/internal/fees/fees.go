@@ -0,0 +1,213 @@
+// Package fees provides maker/taker trading fee models shared by live,
+// paper and backtest execution, so simulated fills pay what a venue would
+// charge.
+package fees
+
+import (
+	"sync"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Liquidity is whether a fill added liquidity to the book or took it.
+type Liquidity string
+
+const (
+	LiquidityMaker Liquidity = "maker"
+	LiquidityTaker Liquidity = "taker"
+)
+
+// Fee assets other than a named third asset.
+const (
+	AssetQuote = "quote"
+	AssetBase  = "base"
+)
+
+var bpsDivisor = decimal.NewFromInt(10000)
+
+// Fee is the fee charged on one fill.
+type Fee struct {
+	Liquidity Liquidity       `json:"liquidity"`
+	Rate      decimal.Decimal `json:"rate"`   // Fraction of notional after any discount, before the minimum
+	Quote     decimal.Decimal `json:"quote"`  // Fee value in the quote asset
+	Asset     string          `json:"asset"`  // AssetQuote, AssetBase, or the third asset charged
+	Amount    decimal.Decimal `json:"amount"` // Fee in Asset; zero when a third asset's price is unknown
+}
+
+// LiquidityOf classifies an order as maker or taker against the price it
+// would trade at now (the ask for buys, the bid for sells). Limit and
+// take-profit orders that would not cross rest on the book and make
+// liquidity; every other order takes it. Without a market price limit
+// orders are assumed to rest. Post-only orders only ever make.
+func LiquidityOf(order *types.Order, marketPrice decimal.Decimal) Liquidity {
+	if order.PostOnly {
+		return LiquidityMaker
+	}
+
+	switch order.Type {
+	case types.OrderTypeLimit, types.OrderTypeTakeProfit:
+	default:
+		return LiquidityTaker
+	}
+
+	if marketPrice.IsZero() || order.Price.IsZero() {
+		return LiquidityMaker
+	}
+	if order.Side == types.OrderSideBuy && order.Price.GreaterThanOrEqual(marketPrice) {
+		return LiquidityTaker
+	}
+	if order.Side == types.OrderSideSell && order.Price.LessThanOrEqual(marketPrice) {
+		return LiquidityTaker
+	}
+	return LiquidityMaker
+}
+
+// Rate returns a schedule's fee rate for liquidity as a fraction of
+// notional, after any third-asset discount.
+func Rate(schedule types.FeeSchedule, liquidity Liquidity) decimal.Decimal {
+	bps := schedule.TakerBps
+	if liquidity == LiquidityMaker {
+		bps = schedule.MakerBps
+	}
+	rate := bps.Div(bpsDivisor)
+
+	if thirdAsset(schedule.FeeAsset) && schedule.Discount.IsPositive() {
+		rate = rate.Mul(decimal.NewFromInt(1).Sub(schedule.Discount))
+	}
+	return rate
+}
+
+// Calculate returns the fee a schedule charges on a fill of quantity at
+// price. Amount is left zero for fees paid in a third asset; FeeModel fills
+// it in from the asset's price.
+func Calculate(schedule types.FeeSchedule, liquidity Liquidity, quantity, price decimal.Decimal) Fee {
+	rate := Rate(schedule, liquidity)
+	quote := quantity.Mul(price).Mul(rate)
+	if quote.LessThan(schedule.MinFee) {
+		quote = schedule.MinFee
+	}
+
+	fee := Fee{
+		Liquidity: liquidity,
+		Rate:      rate,
+		Quote:     quote,
+		Asset:     AssetQuote,
+		Amount:    quote,
+	}
+
+	switch {
+	case schedule.FeeAsset == AssetBase:
+		fee.Asset = AssetBase
+		fee.Amount = decimal.Zero
+		if price.IsPositive() {
+			fee.Amount = quote.Div(price)
+		}
+	case thirdAsset(schedule.FeeAsset):
+		fee.Asset = schedule.FeeAsset
+		fee.Amount = decimal.Zero
+	}
+
+	return fee
+}
+
+// thirdAsset reports whether fees are paid in an asset other than the
+// traded pair's.
+func thirdAsset(asset string) bool {
+	return asset != "" && asset != AssetQuote && asset != AssetBase
+}
+
+// Config configures a FeeModel.
+type Config struct {
+	Venues  map[string]types.FeeSchedule `json:"venues"`  // Schedule by venue name
+	Default types.FeeSchedule            `json:"default"` // Used for venues without a schedule
+}
+
+// DefaultConfig returns the base-tier spot schedules of supported venues.
+func DefaultConfig() Config {
+	schedule := func(maker, taker int64) types.FeeSchedule {
+		return types.FeeSchedule{
+			MakerBps: decimal.NewFromInt(maker),
+			TakerBps: decimal.NewFromInt(taker),
+		}
+	}
+
+	return Config{
+		Venues: map[string]types.FeeSchedule{
+			"binance":  schedule(10, 10),
+			"bybit":    schedule(10, 10),
+			"kraken":   schedule(16, 26),
+			"coinbase": schedule(40, 60),
+			"jupiter":  schedule(0, 0), // Network fees are paid separately
+		},
+		Default: schedule(10, 10),
+	}
+}
+
+// FeeModel holds per-venue fee schedules. It is safe for concurrent use.
+type FeeModel struct {
+	mu          sync.RWMutex
+	config      Config
+	assetPrices map[string]decimal.Decimal
+}
+
+// NewFeeModel creates a fee model from config.
+func NewFeeModel(config Config) *FeeModel {
+	venues := make(map[string]types.FeeSchedule, len(config.Venues))
+	for venue, schedule := range config.Venues {
+		venues[venue] = schedule
+	}
+	config.Venues = venues
+
+	return &FeeModel{
+		config:      config,
+		assetPrices: make(map[string]decimal.Decimal),
+	}
+}
+
+// Schedule returns a venue's fee schedule, or the default schedule.
+func (m *FeeModel) Schedule(venue string) types.FeeSchedule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if schedule, ok := m.config.Venues[venue]; ok {
+		return schedule
+	}
+	return m.config.Default
+}
+
+// SetSchedule replaces a venue's fee schedule, e.g. with the account tier
+// the venue reports.
+func (m *FeeModel) SetSchedule(venue string, schedule types.FeeSchedule) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.Venues[venue] = schedule
+}
+
+// SetAssetPrice records the quote price of a third asset fees are paid in,
+// so fees can be expressed in it.
+func (m *FeeModel) SetAssetPrice(asset string, price decimal.Decimal) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.assetPrices[asset] = price
+}
+
+// Rate returns a venue's fee rate for liquidity as a fraction of notional.
+func (m *FeeModel) Rate(venue string, liquidity Liquidity) decimal.Decimal {
+	return Rate(m.Schedule(venue), liquidity)
+}
+
+// Fee returns the fee a venue charges on a fill of quantity at price.
+func (m *FeeModel) Fee(venue string, liquidity Liquidity, quantity, price decimal.Decimal) Fee {
+	fee := Calculate(m.Schedule(venue), liquidity, quantity, price)
+
+	if thirdAsset(fee.Asset) {
+		m.mu.RLock()
+		assetPrice := m.assetPrices[fee.Asset]
+		m.mu.RUnlock()
+		if assetPrice.IsPositive() {
+			fee.Amount = fee.Quote.Div(assetPrice)
+		}
+	}
+	return fee
+}
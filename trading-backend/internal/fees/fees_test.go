@@ -0,0 +1,103 @@
+package fees_test
+
+import (
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/fees"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+func order(orderType types.OrderType, side types.OrderSide, price float64) *types.Order {
+	return &types.Order{Type: orderType, Side: side, Price: decimal.NewFromFloat(price)}
+}
+
+func TestLiquidityOf(t *testing.T) {
+	market := decimal.NewFromInt(100)
+
+	tests := []struct {
+		name  string
+		order *types.Order
+		want  fees.Liquidity
+	}{
+		{"market order", order(types.OrderTypeMarket, types.OrderSideBuy, 0), fees.LiquidityTaker},
+		{"stop order", order(types.OrderTypeStopLoss, types.OrderSideSell, 95), fees.LiquidityTaker},
+		{"resting buy", order(types.OrderTypeLimit, types.OrderSideBuy, 99), fees.LiquidityMaker},
+		{"marketable buy", order(types.OrderTypeLimit, types.OrderSideBuy, 100.5), fees.LiquidityTaker},
+		{"resting sell", order(types.OrderTypeLimit, types.OrderSideSell, 101), fees.LiquidityMaker},
+		{"marketable sell", order(types.OrderTypeLimit, types.OrderSideSell, 100), fees.LiquidityTaker},
+		{"take profit", order(types.OrderTypeTakeProfit, types.OrderSideSell, 110), fees.LiquidityMaker},
+		{"post-only", &types.Order{Type: types.OrderTypeLimit, Side: types.OrderSideBuy, Price: market, PostOnly: true}, fees.LiquidityMaker},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fees.LiquidityOf(tt.order, market); got != tt.want {
+				t.Errorf("LiquidityOf = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if got := fees.LiquidityOf(order(types.OrderTypeLimit, types.OrderSideBuy, 100), decimal.Zero); got != fees.LiquidityMaker {
+		t.Errorf("Expected a limit order without a market price to rest, got %s", got)
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	schedule := types.FeeSchedule{
+		MakerBps: decimal.NewFromInt(2),
+		TakerBps: decimal.NewFromInt(5),
+		MinFee:   decimal.NewFromFloat(0.1),
+	}
+	qty, price := decimal.NewFromInt(2), decimal.NewFromInt(1000)
+
+	taker := fees.Calculate(schedule, fees.LiquidityTaker, qty, price)
+	if !taker.Quote.Equal(decimal.NewFromInt(1)) || taker.Asset != fees.AssetQuote {
+		t.Errorf("Expected a 1.00 quote taker fee, got %s %s", taker.Quote, taker.Asset)
+	}
+
+	maker := fees.Calculate(schedule, fees.LiquidityMaker, qty, price)
+	if !maker.Quote.Equal(decimal.NewFromFloat(0.4)) {
+		t.Errorf("Expected a 0.40 maker fee, got %s", maker.Quote)
+	}
+
+	small := fees.Calculate(schedule, fees.LiquidityMaker, decimal.NewFromFloat(0.01), price)
+	if !small.Quote.Equal(schedule.MinFee) {
+		t.Errorf("Expected the minimum fee, got %s", small.Quote)
+	}
+
+	schedule.FeeAsset = fees.AssetBase
+	base := fees.Calculate(schedule, fees.LiquidityTaker, qty, price)
+	if base.Asset != fees.AssetBase || !base.Amount.Equal(decimal.NewFromFloat(0.001)) {
+		t.Errorf("Expected 0.001 of the base asset, got %s %s", base.Amount, base.Asset)
+	}
+}
+
+func TestFeeModelVenuesAndThirdAsset(t *testing.T) {
+	model := fees.NewFeeModel(fees.DefaultConfig())
+	qty, price := decimal.NewFromInt(1), decimal.NewFromInt(10000)
+
+	if got := model.Fee("kraken", fees.LiquidityTaker, qty, price).Quote; !got.Equal(decimal.NewFromInt(26)) {
+		t.Errorf("Expected a 26 kraken taker fee, got %s", got)
+	}
+	if got := model.Fee("unknown", fees.LiquidityMaker, qty, price).Quote; !got.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("Expected the default 10 bps fee, got %s", got)
+	}
+
+	// Paying in BNB takes 25% off
+	model.SetSchedule("binance", types.FeeSchedule{
+		MakerBps: decimal.NewFromInt(10),
+		TakerBps: decimal.NewFromInt(10),
+		FeeAsset: "BNB",
+		Discount: decimal.NewFromFloat(0.25),
+	})
+	fee := model.Fee("binance", fees.LiquidityTaker, qty, price)
+	if !fee.Quote.Equal(decimal.NewFromFloat(7.5)) || fee.Asset != "BNB" || !fee.Amount.IsZero() {
+		t.Errorf("Expected 7.5 quote in BNB with an unknown amount, got %+v", fee)
+	}
+
+	model.SetAssetPrice("BNB", decimal.NewFromInt(500))
+	if got := model.Fee("binance", fees.LiquidityTaker, qty, price).Amount; !got.Equal(decimal.NewFromFloat(0.015)) {
+		t.Errorf("Expected 0.015 BNB, got %s", got)
+	}
+}
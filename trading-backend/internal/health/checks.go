@@ -0,0 +1,128 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/data"
+	"github.com/atlas-desktop/trading-backend/internal/events"
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+)
+
+// Report is one member of a group's self-reported health, the shape signal
+// sources' SourceHealth and the block tracker's ChainState share.
+type Report struct {
+	Healthy    bool      `json:"healthy"`
+	LastReport time.Time `json:"lastReport,omitempty"`
+	LastError  string    `json:"lastError,omitempty"`
+}
+
+// GroupCheck combines the reports of like components, such as every signal
+// source or every chain. The group is down when all members are unhealthy,
+// degraded when some are, and goes stale with its least recent report.
+func GroupCheck(reports func() map[string]Report) CheckFunc {
+	return func(ctx context.Context) ComponentHealth {
+		members := reports()
+
+		var unhealthy []string
+		var oldest time.Time
+		for name, report := range members {
+			if !report.Healthy {
+				message := name
+				if report.LastError != "" {
+					message += " (" + report.LastError + ")"
+				}
+				unhealthy = append(unhealthy, message)
+			}
+			if !report.LastReport.IsZero() && (oldest.IsZero() || report.LastReport.Before(oldest)) {
+				oldest = report.LastReport
+			}
+		}
+		sort.Strings(unhealthy)
+
+		health := ComponentHealth{Status: StatusGreen, LastReport: oldest, Details: members}
+		switch {
+		case len(unhealthy) > 0 && len(unhealthy) == len(members):
+			health.Status = StatusRed
+			health.Message = "all unhealthy: " + strings.Join(unhealthy, ", ")
+		case len(unhealthy) > 0:
+			health.Status = StatusYellow
+			health.Message = "unhealthy: " + strings.Join(unhealthy, ", ")
+		}
+		return health
+	}
+}
+
+// EventBusCheck degrades the event bus when it dropped events or handlers
+// failed since the previous check.
+func EventBusCheck(bus *events.EventBus) CheckFunc {
+	var mu sync.Mutex
+	var previous events.EventBusStats
+
+	return func(ctx context.Context) ComponentHealth {
+		stats := bus.GetStats()
+
+		mu.Lock()
+		dropped := stats.EventsDropped - previous.EventsDropped
+		failed := stats.ProcessingErrors - previous.ProcessingErrors
+		previous = stats
+		mu.Unlock()
+
+		health := ComponentHealth{Status: StatusGreen, Details: stats}
+		switch {
+		case dropped > 0:
+			health.Status = StatusYellow
+			health.Message = fmt.Sprintf("%d events dropped since the last check", dropped)
+		case failed > 0:
+			health.Status = StatusYellow
+			health.Message = fmt.Sprintf("%d handler errors since the last check", failed)
+		}
+		return health
+	}
+}
+
+// AdapterCheck pings an exchange adapter. Adapters that cannot be pinged are
+// reported healthy without a probe.
+func AdapterCheck(adapter adapters.ExchangeAdapter) CheckFunc {
+	return func(ctx context.Context) ComponentHealth {
+		pinger, ok := adapter.(adapters.PingAdapter)
+		if !ok {
+			return ComponentHealth{Status: StatusGreen, Message: "connectivity not probed"}
+		}
+
+		start := time.Now()
+		if err := pinger.Ping(ctx); err != nil {
+			return ComponentHealth{Status: StatusRed, Message: err.Error()}
+		}
+		return ComponentHealth{
+			Status:     StatusGreen,
+			LastReport: time.Now(),
+			Details:    map[string]interface{}{"latencyMs": time.Since(start).Milliseconds()},
+		}
+	}
+}
+
+// MarketDataCheck reports the market data stream down while it is
+// disconnected, degraded while any symbol's prices are stale, and stale once
+// messages stop arriving.
+func MarketDataCheck(service *data.MarketDataService) CheckFunc {
+	return func(ctx context.Context) ComponentHealth {
+		state := service.Health()
+		if !state.Connected {
+			return ComponentHealth{Status: StatusRed, Message: "not connected", Details: state}
+		}
+		if len(state.Stale) > 0 {
+			return ComponentHealth{
+				Status:     StatusYellow,
+				Message:    "stale prices: " + strings.Join(state.Stale, ", "),
+				LastReport: state.LastMessage,
+				Details:    state,
+			}
+		}
+		return ComponentHealth{Status: StatusGreen, LastReport: state.LastMessage, Details: state}
+	}
+}
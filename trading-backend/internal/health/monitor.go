@@ -0,0 +1,331 @@
+// Package health provides a health monitor that periodically checks the
+// system's components and rolls them up into a red/yellow/green status.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/events"
+	"go.uber.org/zap"
+)
+
+// Status is a traffic-light health status.
+type Status string
+
+const (
+	StatusGreen  Status = "green"  // Working normally
+	StatusYellow Status = "yellow" // Degraded, or stale
+	StatusRed    Status = "red"    // Down
+)
+
+// CheckFunc reports a component's current health. It should return when ctx
+// is done; a check that overruns CheckTimeout is reported as degraded.
+type CheckFunc func(ctx context.Context) ComponentHealth
+
+// Component is a part of the system the monitor checks.
+type Component struct {
+	Name string
+
+	// Critical components turn the overall status red when they are down;
+	// others only turn it yellow
+	Critical bool
+
+	// StaleAfter degrades a component whose last report is older than
+	// this. Zero uses the monitor's StaleAfter.
+	StaleAfter time.Duration
+
+	Check CheckFunc
+}
+
+// ComponentHealth is one component's health at a check.
+type ComponentHealth struct {
+	Name       string      `json:"name"`
+	Status     Status      `json:"status"`
+	Critical   bool        `json:"critical"`
+	Message    string      `json:"message,omitempty"`
+	LastReport time.Time   `json:"lastReport,omitempty"` // Zero if the component has no reports to go stale
+	CheckedAt  time.Time   `json:"checkedAt"`
+	Details    interface{} `json:"details,omitempty"`
+}
+
+// SystemHealth is the overall health and every component's.
+type SystemHealth struct {
+	Status     Status            `json:"status"`
+	Components []ComponentHealth `json:"components"` // Sorted by name
+	CheckedAt  time.Time         `json:"checkedAt"`
+	Uptime     time.Duration     `json:"uptime"`
+}
+
+// Config configures the health monitor.
+type Config struct {
+	Interval     time.Duration // Time between checks
+	CheckTimeout time.Duration // Longest a single component check may take
+	StaleAfter   time.Duration // Default age at which a last report is stale
+}
+
+// DefaultConfig returns sensible defaults.
+func DefaultConfig() Config {
+	return Config{
+		Interval:     15 * time.Second,
+		CheckTimeout: 5 * time.Second,
+		StaleAfter:   2 * time.Minute,
+	}
+}
+
+// Monitor periodically checks registered components, publishes a heartbeat
+// event with the result, and a status event when the overall status changes.
+type Monitor struct {
+	logger  *zap.Logger
+	config  Config
+	bus     *events.EventBus
+	started time.Time
+
+	mu         sync.RWMutex
+	components []Component
+	latest     *SystemHealth
+	running    bool
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewMonitor creates a health monitor. The event bus may be nil, in which
+// case nothing is published.
+func NewMonitor(logger *zap.Logger, config Config, bus *events.EventBus) *Monitor {
+	return &Monitor{
+		logger:  logger,
+		config:  config,
+		bus:     bus,
+		started: time.Now(),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Register adds a component to check. A component registered under an
+// existing name replaces it.
+func (m *Monitor) Register(component Component) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, existing := range m.components {
+		if existing.Name == component.Name {
+			m.components[i] = component
+			return
+		}
+	}
+	m.components = append(m.components, component)
+}
+
+// Start checks every component now and then every Interval until ctx is
+// done or Stop is called.
+func (m *Monitor) Start(ctx context.Context) error {
+	if m.config.Interval <= 0 {
+		return fmt.Errorf("health check interval must be positive")
+	}
+
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return fmt.Errorf("health monitor already started")
+	}
+	m.running = true
+	m.mu.Unlock()
+
+	go func() {
+		defer close(m.done)
+
+		ticker := time.NewTicker(m.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			m.Check(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	m.logger.Info("Health monitor started",
+		zap.Duration("interval", m.config.Interval),
+	)
+	return nil
+}
+
+// Stop ends periodic checks and waits for a running check to finish.
+func (m *Monitor) Stop() {
+	m.mu.RLock()
+	running := m.running
+	m.mu.RUnlock()
+
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+	if running {
+		<-m.done
+	}
+}
+
+// Latest returns the result of the last check, or nil before the first.
+func (m *Monitor) Latest() *SystemHealth {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.latest
+}
+
+// Check runs every component's check concurrently, stores and publishes the
+// result, and returns it.
+func (m *Monitor) Check(ctx context.Context) *SystemHealth {
+	m.mu.RLock()
+	components := append([]Component(nil), m.components...)
+	m.mu.RUnlock()
+
+	results := make([]ComponentHealth, len(components))
+	var wg sync.WaitGroup
+	for i, component := range components {
+		wg.Add(1)
+		go func(i int, component Component) {
+			defer wg.Done()
+			results[i] = m.checkComponent(ctx, component)
+		}(i, component)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+
+	now := time.Now()
+	health := &SystemHealth{
+		Status:     Overall(results),
+		Components: results,
+		CheckedAt:  now,
+		Uptime:     now.Sub(m.started),
+	}
+
+	m.mu.Lock()
+	previous := m.latest
+	m.latest = health
+	m.mu.Unlock()
+
+	m.publish(health, previous)
+	return health
+}
+
+// checkComponent runs one check within CheckTimeout and applies staleness.
+func (m *Monitor) checkComponent(ctx context.Context, component Component) ComponentHealth {
+	timeout := m.config.CheckTimeout
+	if timeout <= 0 {
+		timeout = DefaultConfig().CheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Buffered so a check that ignores ctx can finish without blocking
+	reported := make(chan ComponentHealth, 1)
+	go func() {
+		reported <- component.Check(ctx)
+	}()
+
+	var result ComponentHealth
+	select {
+	case result = <-reported:
+	case <-ctx.Done():
+		result = ComponentHealth{
+			Status:  StatusYellow,
+			Message: fmt.Sprintf("check did not finish within %s", timeout),
+		}
+	}
+
+	now := time.Now()
+	result.Name = component.Name
+	result.Critical = component.Critical
+	result.CheckedAt = now
+	if result.Status == "" {
+		result.Status = StatusGreen
+	}
+
+	staleAfter := component.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = m.config.StaleAfter
+	}
+	if result.Status == StatusGreen && staleAfter > 0 && !result.LastReport.IsZero() {
+		if age := now.Sub(result.LastReport); age > staleAfter {
+			result.Status = StatusYellow
+			result.Message = fmt.Sprintf("no report for %s", age.Round(time.Second))
+		}
+	}
+
+	return result
+}
+
+// publish sends a heartbeat, and a status event if the overall status
+// changed since the previous check.
+func (m *Monitor) publish(health, previous *SystemHealth) {
+	statuses := make(map[string]string, len(health.Components))
+	for _, component := range health.Components {
+		statuses[component.Name] = string(component.Status)
+	}
+
+	changed := previous == nil || previous.Status != health.Status
+	if changed && health.Status != StatusGreen {
+		m.logger.Warn("System health changed",
+			zap.String("status", string(health.Status)),
+			zap.String("reason", summary(health)),
+		)
+	}
+
+	if m.bus == nil {
+		return
+	}
+
+	m.bus.Publish(events.NewHeartbeatEvent(string(health.Status), statuses))
+	if changed {
+		prev := ""
+		if previous != nil {
+			prev = string(previous.Status)
+		}
+		m.bus.Publish(events.NewStatusEvent(string(health.Status), prev, summary(health)))
+	}
+}
+
+// Overall rolls component statuses up: red if a critical component is red,
+// yellow if any component is not green, and green otherwise.
+func Overall(components []ComponentHealth) Status {
+	status := StatusGreen
+	for _, component := range components {
+		switch {
+		case component.Status == StatusRed && component.Critical:
+			return StatusRed
+		case component.Status != StatusGreen:
+			status = StatusYellow
+		}
+	}
+	return status
+}
+
+// summary names the components that are not green.
+func summary(health *SystemHealth) string {
+	var message string
+	for _, component := range health.Components {
+		if component.Status == StatusGreen {
+			continue
+		}
+		if message != "" {
+			message += "; "
+		}
+		message += fmt.Sprintf("%s is %s", component.Name, component.Status)
+		if component.Message != "" {
+			message += ": " + component.Message
+		}
+	}
+	return message
+}
@@ -0,0 +1,184 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/events"
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/internal/health"
+	"go.uber.org/zap"
+)
+
+func fixed(status health.Status) health.CheckFunc {
+	return func(ctx context.Context) health.ComponentHealth {
+		return health.ComponentHealth{Status: status}
+	}
+}
+
+func component(t *testing.T, report *health.SystemHealth, name string) health.ComponentHealth {
+	t.Helper()
+	for _, c := range report.Components {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("No %s component in %+v", name, report.Components)
+	return health.ComponentHealth{}
+}
+
+func TestMonitorRollsUpComponentStatus(t *testing.T) {
+	config := health.DefaultConfig()
+	config.CheckTimeout = 20 * time.Millisecond
+	config.StaleAfter = time.Minute
+
+	m := health.NewMonitor(zap.NewNop(), config, nil)
+	m.Register(health.Component{Name: "exchange", Critical: true, Check: fixed(health.StatusGreen)})
+	m.Register(health.Component{Name: "news", Check: fixed(health.StatusRed)})
+	m.Register(health.Component{Name: "stale", Check: func(ctx context.Context) health.ComponentHealth {
+		return health.ComponentHealth{LastReport: time.Now().Add(-2 * time.Minute)}
+	}})
+	m.Register(health.Component{Name: "hung", Check: func(ctx context.Context) health.ComponentHealth {
+		time.Sleep(time.Second)
+		return health.ComponentHealth{}
+	}})
+
+	report := m.Check(context.Background())
+
+	// A non-critical component being down only degrades the system
+	if report.Status != health.StatusYellow {
+		t.Errorf("Expected yellow with a non-critical component down, got %s", report.Status)
+	}
+	if names := []string{report.Components[0].Name, report.Components[3].Name}; names[0] != "exchange" || names[1] != "stale" {
+		t.Errorf("Expected components sorted by name, got %v", names)
+	}
+	if c := component(t, report, "stale"); c.Status != health.StatusYellow || !strings.Contains(c.Message, "no report") {
+		t.Errorf("Expected the stale component degraded, got %s %q", c.Status, c.Message)
+	}
+	if c := component(t, report, "hung"); c.Status != health.StatusYellow {
+		t.Errorf("Expected the hung check degraded, got %s", c.Status)
+	}
+	if m.Latest() != report {
+		t.Error("Expected Latest to return the last check")
+	}
+
+	m.Register(health.Component{Name: "exchange", Critical: true, Check: fixed(health.StatusRed)})
+	if report := m.Check(context.Background()); report.Status != health.StatusRed || len(report.Components) != 4 {
+		t.Errorf("Expected red from the replaced critical component, got %s with %d components", report.Status, len(report.Components))
+	}
+}
+
+func TestMonitorPublishesHeartbeatsAndStatusChanges(t *testing.T) {
+	bus := events.NewEventBus(zap.NewNop(), events.DefaultEventBusConfig())
+	defer bus.Stop()
+
+	received := make(chan events.Event, 10)
+	bus.SubscribeMultiple([]events.EventType{events.EventTypeHeartbeat, events.EventTypeStatus}, func(e events.Event) error {
+		received <- e
+		return nil
+	})
+
+	status := health.StatusGreen
+	m := health.NewMonitor(zap.NewNop(), health.DefaultConfig(), bus)
+	m.Register(health.Component{Name: "exchange", Critical: true, Check: func(ctx context.Context) health.ComponentHealth {
+		return health.ComponentHealth{Status: status}
+	}})
+
+	m.Check(context.Background())
+	m.Check(context.Background())
+	status = health.StatusRed
+	m.Check(context.Background())
+
+	var heartbeats int
+	var changes []string
+	timeout := time.After(2 * time.Second)
+	for heartbeats+len(changes) < 5 {
+		select {
+		case e := <-received:
+			switch e := e.(type) {
+			case *events.HeartbeatEvent:
+				heartbeats++
+				if e.Components["exchange"] == "" {
+					t.Errorf("Expected the heartbeat to carry component status, got %v", e.Components)
+				}
+			case *events.StatusEvent:
+				changes = append(changes, e.Previous+"->"+e.Status)
+			}
+		case <-timeout:
+			t.Fatalf("Got %d heartbeats and status changes %v, want 3 and 2", heartbeats, changes)
+		}
+	}
+
+	if heartbeats != 3 || len(changes) != 2 {
+		t.Errorf("Expected 3 heartbeats and 2 status changes, got %d and %v", heartbeats, changes)
+	}
+}
+
+func TestMonitorStartChecksPeriodically(t *testing.T) {
+	config := health.DefaultConfig()
+	config.Interval = 5 * time.Millisecond
+
+	checks := make(chan struct{}, 100)
+	m := health.NewMonitor(zap.NewNop(), config, nil)
+	m.Register(health.Component{Name: "ticker", Check: func(ctx context.Context) health.ComponentHealth {
+		checks <- struct{}{}
+		return health.ComponentHealth{}
+	}})
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		select {
+		case <-checks:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected periodic checks, got %d", i)
+		}
+	}
+	m.Stop()
+
+	if err := m.Start(context.Background()); err == nil {
+		t.Error("Expected a second Start to fail")
+	}
+}
+
+func TestGroupCheck(t *testing.T) {
+	reports := map[string]health.Report{
+		"solana":   {Healthy: true, LastReport: time.Now()},
+		"ethereum": {Healthy: false, LastError: "No blocks received recently"},
+	}
+	check := health.GroupCheck(func() map[string]health.Report { return reports })
+
+	result := check(context.Background())
+	if result.Status != health.StatusYellow || !strings.Contains(result.Message, "ethereum (No blocks received recently)") {
+		t.Errorf("Expected one unhealthy chain to degrade the group, got %s %q", result.Status, result.Message)
+	}
+
+	reports["solana"] = health.Report{Healthy: false}
+	if result := check(context.Background()); result.Status != health.StatusRed {
+		t.Errorf("Expected the group down with every member unhealthy, got %s", result.Status)
+	}
+}
+
+// pinger is an adapter that only answers pings
+type pinger struct {
+	adapters.ExchangeAdapter
+	err error
+}
+
+func (p *pinger) Ping(ctx context.Context) error { return p.err }
+
+func TestAdapterCheck(t *testing.T) {
+	up := health.AdapterCheck(&pinger{})(context.Background())
+	if up.Status != health.StatusGreen || up.LastReport.IsZero() {
+		t.Errorf("Expected a reachable adapter green, got %s", up.Status)
+	}
+
+	down := health.AdapterCheck(&pinger{err: errors.New("kraken is maintenance")})(context.Background())
+	if down.Status != health.StatusRed || down.Message != "kraken is maintenance" {
+		t.Errorf("Expected an unreachable adapter red, got %s %q", down.Status, down.Message)
+	}
+}
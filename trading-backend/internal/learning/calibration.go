@@ -0,0 +1,166 @@
+// Package learning provides calibration of stated signal confidence against
+// realized trade outcomes.
+package learning
+
+import (
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// calibrationBuckets is the number of equal-width confidence buckets in a
+	// reliability curve.
+	calibrationBuckets = 10
+
+	// calibrationPriorStrength is how many outcomes the stated confidence is
+	// worth. A bucket's calibrated confidence moves halfway from the stated
+	// confidence to the observed hit rate after this many outcomes.
+	calibrationPriorStrength = 10
+
+	// aggregateCurve keys the reliability curve of aggregated signals.
+	aggregateCurve = ""
+)
+
+// ReliabilityCurve is the realized hit rate of signals bucketed by their
+// stated confidence.
+type ReliabilityCurve struct {
+	Source  string              `json:"source,omitempty"` // Empty for aggregated signals
+	Buckets []ReliabilityBucket `json:"buckets"`
+}
+
+// ReliabilityBucket holds the outcomes of signals stated within a confidence
+// range.
+type ReliabilityBucket struct {
+	Lower   decimal.Decimal `json:"lower"`
+	Upper   decimal.Decimal `json:"upper"`
+	Signals int             `json:"signals"`
+	Hits    int             `json:"hits"` // Signals whose trade made money
+}
+
+// HitRate returns the fraction of the bucket's signals that hit.
+func (b ReliabilityBucket) HitRate() decimal.Decimal {
+	if b.Signals == 0 {
+		return decimal.Zero
+	}
+	return decimal.NewFromInt(int64(b.Hits)).Div(decimal.NewFromInt(int64(b.Signals)))
+}
+
+// ConfidenceCalibrator maps stated signal confidence to the probability it
+// has realized. Each bucket's hit rate is a Beta-Binomial posterior whose
+// prior is centered on the stated confidence, so confidence passes through
+// unchanged until outcomes accumulate and converges on the empirical hit
+// rate as they do.
+type ConfidenceCalibrator struct {
+	mu     sync.RWMutex
+	curves map[string]*ReliabilityCurve // Source -> curve
+}
+
+// NewConfidenceCalibrator creates a calibrator with no recorded outcomes.
+func NewConfidenceCalibrator() *ConfidenceCalibrator {
+	return &ConfidenceCalibrator{
+		curves: make(map[string]*ReliabilityCurve),
+	}
+}
+
+// Record adds the outcome of a signal to a source's reliability curve. An
+// empty source records an aggregated signal.
+func (cc *ConfidenceCalibrator) Record(source string, confidence decimal.Decimal, hit bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	curve, ok := cc.curves[source]
+	if !ok {
+		curve = newReliabilityCurve(source)
+		cc.curves[source] = curve
+	}
+
+	bucket := &curve.Buckets[bucketIndex(confidence)]
+	bucket.Signals++
+	if hit {
+		bucket.Hits++
+	}
+}
+
+// CalibrateConfidence maps the stated confidence of an aggregated signal to
+// its realized probability.
+func (cc *ConfidenceCalibrator) CalibrateConfidence(raw decimal.Decimal) decimal.Decimal {
+	return cc.CalibrateSourceConfidence(aggregateCurve, raw)
+}
+
+// CalibrateSourceConfidence maps a source's stated confidence to its realized
+// probability.
+func (cc *ConfidenceCalibrator) CalibrateSourceConfidence(source string, raw decimal.Decimal) decimal.Decimal {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+
+	curve, ok := cc.curves[source]
+	if !ok {
+		return raw
+	}
+
+	bucket := curve.Buckets[bucketIndex(raw)]
+	prior := decimal.NewFromInt(calibrationPriorStrength)
+
+	// Posterior mean of Beta(raw*k + hits, (1-raw)*k + misses)
+	return raw.Mul(prior).Add(decimal.NewFromInt(int64(bucket.Hits))).
+		Div(prior.Add(decimal.NewFromInt(int64(bucket.Signals))))
+}
+
+// Curves returns a copy of every reliability curve.
+func (cc *ConfidenceCalibrator) Curves() []ReliabilityCurve {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+
+	curves := make([]ReliabilityCurve, 0, len(cc.curves))
+	for _, curve := range cc.curves {
+		copied := ReliabilityCurve{
+			Source:  curve.Source,
+			Buckets: append([]ReliabilityBucket(nil), curve.Buckets...),
+		}
+		curves = append(curves, copied)
+	}
+	return curves
+}
+
+// restore replaces the reliability curves with persisted ones.
+func (cc *ConfidenceCalibrator) restore(curves []ReliabilityCurve) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.curves = make(map[string]*ReliabilityCurve, len(curves))
+	for _, curve := range curves {
+		if len(curve.Buckets) != calibrationBuckets {
+			continue
+		}
+		restored := curve
+		cc.curves[curve.Source] = &restored
+	}
+}
+
+// newReliabilityCurve creates a curve of empty buckets spanning [0, 1].
+func newReliabilityCurve(source string) *ReliabilityCurve {
+	curve := &ReliabilityCurve{
+		Source:  source,
+		Buckets: make([]ReliabilityBucket, calibrationBuckets),
+	}
+	width := decimal.NewFromInt(1).Div(decimal.NewFromInt(calibrationBuckets))
+	for i := range curve.Buckets {
+		curve.Buckets[i].Lower = width.Mul(decimal.NewFromInt(int64(i)))
+		curve.Buckets[i].Upper = width.Mul(decimal.NewFromInt(int64(i + 1)))
+	}
+	return curve
+}
+
+// bucketIndex returns the bucket a confidence falls in, clamping values
+// outside [0, 1].
+func bucketIndex(confidence decimal.Decimal) int {
+	i := int(confidence.Mul(decimal.NewFromInt(calibrationBuckets)).IntPart())
+	if i < 0 {
+		return 0
+	}
+	if i >= calibrationBuckets {
+		return calibrationBuckets - 1
+	}
+	return i
+}
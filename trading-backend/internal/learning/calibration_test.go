@@ -0,0 +1,73 @@
+package learning_test
+
+import (
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/learning"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func TestConfidenceCalibratorPosterior(t *testing.T) {
+	cc := learning.NewConfidenceCalibrator()
+	stated := decimal.NewFromFloat(0.8)
+
+	// No outcomes: stated confidence passes through
+	if got := cc.CalibrateConfidence(stated); !got.Equal(stated) {
+		t.Errorf("uncalibrated = %s, want %s", got, stated)
+	}
+
+	// 0.8 signals hit 3 times in 10: (0.8*10 + 3) / (10 + 10)
+	for i := 0; i < 10; i++ {
+		cc.Record("", stated, i < 3)
+	}
+	if got, want := cc.CalibrateConfidence(stated), decimal.NewFromFloat(0.55); !got.Equal(want) {
+		t.Errorf("calibrated = %s, want %s", got, want)
+	}
+
+	// Other buckets and sources are unaffected
+	low := decimal.NewFromFloat(0.35)
+	if got := cc.CalibrateConfidence(low); !got.Equal(low) {
+		t.Errorf("other bucket = %s, want %s", got, low)
+	}
+	if got := cc.CalibrateSourceConfidence("technical", stated); !got.Equal(stated) {
+		t.Errorf("other source = %s, want %s", got, stated)
+	}
+}
+
+func TestFeedbackEngineCalibrationPersists(t *testing.T) {
+	dir := t.TempDir()
+	fe := learning.NewFeedbackEngine(zap.NewNop(), dir)
+
+	// Ten records trigger a save
+	for i := 0; i < 10; i++ {
+		pnl := decimal.NewFromInt(-10)
+		if i < 2 {
+			pnl = decimal.NewFromInt(10)
+		}
+		fe.RecordFeedback(learning.TradeFeedback{
+			Rating:    3,
+			ActualPnL: pnl,
+			Signal: &learning.SignalContext{
+				SignalType: "breakout",
+				Confidence: decimal.NewFromFloat(0.7),
+				SourceConfidence: map[string]decimal.Decimal{
+					"sentiment": decimal.NewFromFloat(0.9),
+				},
+			},
+		})
+	}
+
+	reloaded := learning.NewFeedbackEngine(zap.NewNop(), dir)
+
+	// (0.7*10 + 2) / 20 and (0.9*10 + 2) / 20
+	if got, want := reloaded.CalibrateConfidence(decimal.NewFromFloat(0.7)), decimal.NewFromFloat(0.45); !got.Equal(want) {
+		t.Errorf("aggregate = %s, want %s", got, want)
+	}
+	if got, want := reloaded.CalibrateSourceConfidence("sentiment", decimal.NewFromFloat(0.9)), decimal.NewFromFloat(0.55); !got.Equal(want) {
+		t.Errorf("sentiment = %s, want %s", got, want)
+	}
+	if curves := reloaded.GetReliabilityCurves(); len(curves) != 2 {
+		t.Errorf("curves = %d, want 2", len(curves))
+	}
+}
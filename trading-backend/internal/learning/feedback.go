@@ -7,6 +7,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -17,12 +18,14 @@ import (
 
 // FeedbackEngine collects and processes user feedback on trades.
 type FeedbackEngine struct {
-	logger    *zap.Logger
-	mu        sync.RWMutex
-	
-	feedback  []TradeFeedback
-	patterns  map[string]*PatternPerformance
-	dataDir   string
+	logger     *zap.Logger
+	mu         sync.RWMutex
+	
+	feedback   []TradeFeedback
+	patterns   map[string]*PatternPerformance
+	calibrator *ConfidenceCalibrator
+	journal    *TradeJournal
+	dataDir    string
 }
 
 // TradeFeedback represents user feedback on a trade.
@@ -46,10 +49,11 @@ type TradeFeedback struct {
 
 // SignalContext captures signal state at trade time.
 type SignalContext struct {
-	SignalType  string          `json:"signalType"`
-	Confidence  decimal.Decimal `json:"confidence"`
-	Sources     []string        `json:"sources"`
-	Indicators  map[string]any  `json:"indicators,omitempty"`
+	SignalType       string                     `json:"signalType"`
+	Confidence       decimal.Decimal            `json:"confidence"`
+	Sources          []string                   `json:"sources"`
+	SourceConfidence map[string]decimal.Decimal `json:"sourceConfidence,omitempty"` // Each source's stated confidence
+	Indicators       map[string]any             `json:"indicators,omitempty"`
 }
 
 // MarketContext captures market state at trade time.
@@ -73,9 +77,10 @@ type PatternPerformance struct {
 // NewFeedbackEngine creates a new feedback engine.
 func NewFeedbackEngine(logger *zap.Logger, dataDir string) *FeedbackEngine {
 	fe := &FeedbackEngine{
-		logger:   logger.Named("feedback-engine"),
-		patterns: make(map[string]*PatternPerformance),
-		dataDir:  dataDir,
+		logger:     logger.Named("feedback-engine"),
+		patterns:   make(map[string]*PatternPerformance),
+		calibrator: NewConfidenceCalibrator(),
+		dataDir:    dataDir,
 	}
 	
 	// Load existing feedback
@@ -92,6 +97,14 @@ func (fe *FeedbackEngine) RecordFeedback(feedback TradeFeedback) {
 	feedback.Timestamp = time.Now()
 	fe.feedback = append(fe.feedback, feedback)
 	
+	if fe.journal != nil {
+		if _, err := fe.journal.LinkFeedback(feedback); err != nil {
+			fe.logger.Error("Failed to link feedback to journal",
+				zap.String("tradeId", feedback.TradeID),
+				zap.Error(err))
+		}
+	}
+	
 	// Update pattern performance
 	if feedback.Signal != nil {
 		pattern := feedback.Signal.SignalType
@@ -118,6 +131,13 @@ func (fe *FeedbackEngine) RecordFeedback(feedback TradeFeedback) {
 		// Update average rating
 		perf.AvgRating = (perf.AvgRating*float64(perf.TotalTrades-1) + float64(feedback.Rating)) / float64(perf.TotalTrades)
 		perf.LastUpdated = time.Now()
+		
+		// Update confidence calibration
+		hit := feedback.ActualPnL.GreaterThan(decimal.Zero)
+		fe.calibrator.Record(aggregateCurve, feedback.Signal.Confidence, hit)
+		for source, confidence := range feedback.Signal.SourceConfidence {
+			fe.calibrator.Record(source, confidence, hit)
+		}
 	}
 	
 	// Save periodically
@@ -130,6 +150,14 @@ func (fe *FeedbackEngine) RecordFeedback(feedback TradeFeedback) {
 		zap.Int("rating", feedback.Rating))
 }
 
+// SetJournal sets the trade journal feedback is linked into.
+func (fe *FeedbackEngine) SetJournal(journal *TradeJournal) {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	
+	fe.journal = journal
+}
+
 // GetPatternPerformance returns performance for a specific pattern.
 func (fe *FeedbackEngine) GetPatternPerformance(pattern string) *PatternPerformance {
 	fe.mu.RLock()
@@ -169,16 +197,36 @@ func (fe *FeedbackEngine) GetRecentFeedback(limit int) []TradeFeedback {
 	return result
 }
 
+// CalibrateConfidence maps the stated confidence of an aggregated signal to
+// the probability realized by past trades.
+func (fe *FeedbackEngine) CalibrateConfidence(raw decimal.Decimal) decimal.Decimal {
+	return fe.calibrator.CalibrateConfidence(raw)
+}
+
+// CalibrateSourceConfidence maps a source's stated confidence to the
+// probability realized by past trades.
+func (fe *FeedbackEngine) CalibrateSourceConfidence(source string, raw decimal.Decimal) decimal.Decimal {
+	return fe.calibrator.CalibrateSourceConfidence(source, raw)
+}
+
+// GetReliabilityCurves returns the reliability curve of aggregated signals
+// and of each source.
+func (fe *FeedbackEngine) GetReliabilityCurves() []ReliabilityCurve {
+	return fe.calibrator.Curves()
+}
+
 // save persists feedback to disk.
 func (fe *FeedbackEngine) save() {
 	path := filepath.Join(fe.dataDir, "feedback.json")
 	
 	data := struct {
-		Feedback []TradeFeedback                `json:"feedback"`
-		Patterns map[string]*PatternPerformance `json:"patterns"`
+		Feedback    []TradeFeedback                `json:"feedback"`
+		Patterns    map[string]*PatternPerformance `json:"patterns"`
+		Calibration []ReliabilityCurve             `json:"calibration"`
 	}{
-		Feedback: fe.feedback,
-		Patterns: fe.patterns,
+		Feedback:    fe.feedback,
+		Patterns:    fe.patterns,
+		Calibration: fe.calibrator.Curves(),
 	}
 	
 	bytes, err := json.MarshalIndent(data, "", "  ")
@@ -207,8 +255,9 @@ func (fe *FeedbackEngine) load() {
 	}
 	
 	var data struct {
-		Feedback []TradeFeedback                `json:"feedback"`
-		Patterns map[string]*PatternPerformance `json:"patterns"`
+		Feedback    []TradeFeedback                `json:"feedback"`
+		Patterns    map[string]*PatternPerformance `json:"patterns"`
+		Calibration []ReliabilityCurve             `json:"calibration"`
 	}
 	
 	if err := json.Unmarshal(bytes, &data); err != nil {
@@ -218,6 +267,7 @@ func (fe *FeedbackEngine) load() {
 	
 	fe.feedback = data.Feedback
 	fe.patterns = data.Patterns
+	fe.calibrator.restore(data.Calibration)
 }
 
 // StrategyOptimizer optimizes strategy parameters from feedback.
@@ -463,6 +513,33 @@ func (so *StrategyOptimizer) GetOptimization(strategy string) *OptimizationResul
 // PerformanceAnalyzer analyzes trading performance.
 type PerformanceAnalyzer struct {
 	logger *zap.Logger
+	config PerformanceConfig
+	store  *ReportStore
+}
+
+// PerformanceConfig configures how risk-adjusted returns are measured.
+type PerformanceConfig struct {
+	InitialEquity       decimal.Decimal // Equity the curve starts from
+	ReturnPeriod        time.Duration   // Length of each period in the return series
+	RiskFreeRate        decimal.Decimal // Annual risk-free rate, subtracted for Sharpe
+	MinAcceptableReturn decimal.Decimal // Annual threshold below which Sortino counts downside
+}
+
+// DefaultPerformanceConfig returns daily returns on a $10,000 curve with no
+// risk-free rate or return threshold.
+func DefaultPerformanceConfig() PerformanceConfig {
+	return PerformanceConfig{
+		InitialEquity:       decimal.NewFromInt(10000),
+		ReturnPeriod:        24 * time.Hour,
+		RiskFreeRate:        decimal.Zero,
+		MinAcceptableReturn: decimal.Zero,
+	}
+}
+
+// periodsPerYear is the number of return periods in a year. Crypto markets
+// trade every day, so a year is 365 days.
+func (c PerformanceConfig) periodsPerYear() float64 {
+	return float64(365*24*time.Hour) / float64(c.ReturnPeriod)
 }
 
 // PerformanceReport contains comprehensive performance analysis.
@@ -473,8 +550,11 @@ type PerformanceReport struct {
 	ProfitFactor     decimal.Decimal               `json:"profitFactor"`
 	SharpeRatio      decimal.Decimal               `json:"sharpeRatio"`
 	SortinoRatio     decimal.Decimal               `json:"sortinoRatio"`
+	CalmarRatio      decimal.Decimal               `json:"calmarRatio"`
+	AnnualizedReturn decimal.Decimal               `json:"annualizedReturn"`
 	MaxDrawdown      decimal.Decimal               `json:"maxDrawdown"`
 	TotalPnL         decimal.Decimal               `json:"totalPnl"`
+	TotalFunding     decimal.Decimal               `json:"totalFunding"` // Perpetual funding received less paid, included in TotalPnL
 	AveragePnL       decimal.Decimal               `json:"averagePnl"`
 	AverageWin       decimal.Decimal               `json:"averageWin"`
 	AverageLoss      decimal.Decimal               `json:"averageLoss"`
@@ -482,8 +562,11 @@ type PerformanceReport struct {
 	WorstTrade       *types.Trade                  `json:"worstTrade,omitempty"`
 	BySymbol         map[string]*SymbolPerformance `json:"bySymbol"`
 	ByDayOfWeek      map[string]*DayPerformance    `json:"byDayOfWeek"`
+	ByMonth          map[string]*MonthPerformance  `json:"byMonth"`
 	ByHour           map[int]*HourPerformance      `json:"byHour"`
 	Streaks          *StreakAnalysis               `json:"streaks"`
+	EquityCurve      []EquityPoint                 `json:"equityCurve"`
+	UnderwaterCurve  []DrawdownPoint               `json:"underwaterCurve"`
 	GeneratedAt      time.Time                     `json:"generatedAt"`
 }
 
@@ -504,6 +587,27 @@ type DayPerformance struct {
 	TotalPnL decimal.Decimal `json:"totalPnl"`
 }
 
+// MonthPerformance contains performance for a calendar month.
+type MonthPerformance struct {
+	Month    string          `json:"month"` // YYYY-MM
+	Trades   int             `json:"trades"`
+	TotalPnL decimal.Decimal `json:"totalPnl"`
+	Return   decimal.Decimal `json:"return"` // PnL over equity at the start of the month
+}
+
+// EquityPoint is account equity after a trade.
+type EquityPoint struct {
+	Timestamp     time.Time       `json:"timestamp"`
+	Equity        decimal.Decimal `json:"equity"`
+	CumulativePnL decimal.Decimal `json:"cumulativePnl"`
+}
+
+// DrawdownPoint is the fractional decline from the equity peak after a trade.
+type DrawdownPoint struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Drawdown  decimal.Decimal `json:"drawdown"`
+}
+
 // HourPerformance contains performance for an hour.
 type HourPerformance struct {
 	Hour     int             `json:"hour"`
@@ -522,12 +626,25 @@ type StreakAnalysis struct {
 }
 
 // NewPerformanceAnalyzer creates a new performance analyzer.
-func NewPerformanceAnalyzer(logger *zap.Logger) *PerformanceAnalyzer {
+func NewPerformanceAnalyzer(logger *zap.Logger, config PerformanceConfig) *PerformanceAnalyzer {
+	if config.InitialEquity.IsZero() {
+		config.InitialEquity = decimal.NewFromInt(10000)
+	}
+	if config.ReturnPeriod <= 0 {
+		config.ReturnPeriod = 24 * time.Hour
+	}
+	
 	return &PerformanceAnalyzer{
 		logger: logger.Named("performance-analyzer"),
+		config: config,
 	}
 }
 
+// SetReportStore sets the store that generated reports are saved to.
+func (pa *PerformanceAnalyzer) SetReportStore(store *ReportStore) {
+	pa.store = store
+}
+
 // Analyze generates a comprehensive performance report.
 func (pa *PerformanceAnalyzer) Analyze(trades []*types.Trade, period string) *PerformanceReport {
 	report := &PerformanceReport{
@@ -535,6 +652,7 @@ func (pa *PerformanceAnalyzer) Analyze(trades []*types.Trade, period string) *Pe
 		TotalTrades: len(trades),
 		BySymbol:    make(map[string]*SymbolPerformance),
 		ByDayOfWeek: make(map[string]*DayPerformance),
+		ByMonth:     make(map[string]*MonthPerformance),
 		ByHour:      make(map[int]*HourPerformance),
 		GeneratedAt: time.Now(),
 	}
@@ -549,11 +667,10 @@ func (pa *PerformanceAnalyzer) Analyze(trades []*types.Trade, period string) *Pe
 	grossProfit := decimal.Zero
 	grossLoss := decimal.Zero
 	totalPnL := decimal.Zero
-	var pnls []decimal.Decimal
 	
 	for _, trade := range trades {
 		totalPnL = totalPnL.Add(trade.PnL)
-		pnls = append(pnls, trade.PnL)
+		report.TotalFunding = report.TotalFunding.Add(trade.Funding)
 		
 		if trade.PnL.GreaterThan(decimal.Zero) {
 			wins++
@@ -588,6 +705,16 @@ func (pa *PerformanceAnalyzer) Analyze(trades []*types.Trade, period string) *Pe
 		dp.Trades++
 		dp.TotalPnL = dp.TotalPnL.Add(trade.PnL)
 		
+		// By month
+		month := trade.ExecutedAt.UTC().Format("2006-01")
+		mp, ok := report.ByMonth[month]
+		if !ok {
+			mp = &MonthPerformance{Month: month}
+			report.ByMonth[month] = mp
+		}
+		mp.Trades++
+		mp.TotalPnL = mp.TotalPnL.Add(trade.PnL)
+		
 		// By hour
 		hour := trade.ExecutedAt.Hour()
 		hp, ok := report.ByHour[hour]
@@ -618,109 +745,217 @@ func (pa *PerformanceAnalyzer) Analyze(trades []*types.Trade, period string) *Pe
 		report.ProfitFactor = grossProfit.Div(grossLoss)
 	}
 	
-	// Sharpe/Sortino ratios
-	if len(pnls) > 1 {
-		report.SharpeRatio = pa.calculateSharpe(pnls)
-		report.SortinoRatio = pa.calculateSortino(pnls)
-	}
+	// Sharpe/Sortino ratios over the equity curve's periodic returns
+	returns := pa.EquityReturns(trades)
+	report.SharpeRatio = pa.SharpeRatio(returns)
+	report.SortinoRatio = pa.SortinoRatio(returns)
+	
+	// Equity and underwater curves, max drawdown and monthly returns
+	pa.buildEquityCurve(trades, report)
 	
-	// Max drawdown
-	report.MaxDrawdown = pa.calculateMaxDrawdown(trades)
+	// Calmar ratio
+	report.AnnualizedReturn = pa.annualizedReturn(report.TotalPnL, len(returns))
+	if report.MaxDrawdown.IsPositive() {
+		report.CalmarRatio = report.AnnualizedReturn.Div(report.MaxDrawdown)
+	}
 	
 	// Streaks
 	report.Streaks = pa.analyzeStreaks(trades)
 	
+	if pa.store != nil {
+		if err := pa.store.SaveReport(report); err != nil {
+			pa.logger.Error("Failed to save performance report",
+				zap.String("period", period),
+				zap.Error(err))
+		}
+	}
+	
 	return report
 }
 
-// calculateSharpe calculates Sharpe ratio.
-func (pa *PerformanceAnalyzer) calculateSharpe(pnls []decimal.Decimal) decimal.Decimal {
-	if len(pnls) < 2 {
-		return decimal.Zero
+// EquityReturns builds the equity curve from trade PnL and returns its
+// percentage return over each ReturnPeriod. Periods without trades return
+// zero, so idle time counts toward volatility as it does in a real account.
+func (pa *PerformanceAnalyzer) EquityReturns(trades []*types.Trade) []decimal.Decimal {
+	if len(trades) == 0 {
+		return nil
 	}
 	
-	sum := decimal.Zero
-	for _, pnl := range pnls {
-		sum = sum.Add(pnl)
+	sorted := chronological(trades)
+	
+	period := pa.config.ReturnPeriod
+	first := sorted[0].ExecutedAt.Truncate(period)
+	last := sorted[len(sorted)-1].ExecutedAt.Truncate(period)
+	
+	// Closing equity of each period, preceded by the starting equity
+	closes := make([]decimal.Decimal, int(last.Sub(first)/period)+2)
+	closes[0] = pa.config.InitialEquity
+	
+	equity := pa.config.InitialEquity
+	next := 0
+	for i := 1; i < len(closes); i++ {
+		end := first.Add(time.Duration(i) * period)
+		for next < len(sorted) && sorted[next].ExecutedAt.Before(end) {
+			equity = equity.Add(sorted[next].PnL)
+			next++
+		}
+		closes[i] = equity
+	}
+	
+	returns := make([]decimal.Decimal, 0, len(closes)-1)
+	for i := 1; i < len(closes); i++ {
+		// A wiped-out account has no further returns
+		if !closes[i-1].IsPositive() {
+			break
+		}
+		returns = append(returns, closes[i].Sub(closes[i-1]).Div(closes[i-1]))
+	}
+	
+	return returns
+}
+
+// SharpeRatio returns the annualized Sharpe ratio of periodic returns: the
+// mean return in excess of the risk-free rate over the sample standard
+// deviation, scaled by the square root of periods per year.
+func (pa *PerformanceAnalyzer) SharpeRatio(returns []decimal.Decimal) decimal.Decimal {
+	if len(returns) < 2 {
+		return decimal.Zero
 	}
-	mean := sum.Div(decimal.NewFromInt(int64(len(pnls))))
+	
+	periods := pa.config.periodsPerYear()
+	riskFree := pa.config.RiskFreeRate.Div(decimal.NewFromFloat(periods))
+	
+	mean := meanOf(returns)
 	
 	sumSq := decimal.Zero
-	for _, pnl := range pnls {
-		diff := pnl.Sub(mean)
+	for _, r := range returns {
+		diff := r.Sub(mean)
 		sumSq = sumSq.Add(diff.Mul(diff))
 	}
-	variance := sumSq.Div(decimal.NewFromInt(int64(len(pnls) - 1)))
-	stdDev := decimal.NewFromFloat(math.Sqrt(variance.InexactFloat64()))
+	variance := sumSq.Div(decimal.NewFromInt(int64(len(returns) - 1)))
+	stdDev := math.Sqrt(variance.InexactFloat64())
 	
-	if stdDev.IsZero() {
+	if stdDev == 0 {
 		return decimal.Zero
 	}
 	
-	// Annualize (assuming daily returns)
-	annFactor := decimal.NewFromFloat(math.Sqrt(252))
-	return mean.Div(stdDev).Mul(annFactor)
+	excess := mean.Sub(riskFree).InexactFloat64()
+	return decimal.NewFromFloat(excess / stdDev * math.Sqrt(periods))
 }
 
-// calculateSortino calculates Sortino ratio.
-func (pa *PerformanceAnalyzer) calculateSortino(pnls []decimal.Decimal) decimal.Decimal {
-	if len(pnls) < 2 {
+// SortinoRatio returns the annualized Sortino ratio of periodic returns: the
+// mean return in excess of the minimum acceptable return over the downside
+// deviation below it, scaled by the square root of periods per year. It is
+// zero when no period falls below the threshold.
+func (pa *PerformanceAnalyzer) SortinoRatio(returns []decimal.Decimal) decimal.Decimal {
+	if len(returns) < 2 {
 		return decimal.Zero
 	}
 	
-	sum := decimal.Zero
-	for _, pnl := range pnls {
-		sum = sum.Add(pnl)
-	}
-	mean := sum.Div(decimal.NewFromInt(int64(len(pnls))))
+	periods := pa.config.periodsPerYear()
+	threshold := pa.config.MinAcceptableReturn.Div(decimal.NewFromFloat(periods))
 	
-	// Only use negative returns for downside deviation
+	// Downside deviation is taken over every period, counting only shortfalls
 	sumSq := decimal.Zero
-	negCount := 0
-	for _, pnl := range pnls {
-		if pnl.LessThan(decimal.Zero) {
-			sumSq = sumSq.Add(pnl.Mul(pnl))
-			negCount++
+	for _, r := range returns {
+		if shortfall := r.Sub(threshold); shortfall.IsNegative() {
+			sumSq = sumSq.Add(shortfall.Mul(shortfall))
 		}
 	}
+	downsideVar := sumSq.Div(decimal.NewFromInt(int64(len(returns))))
+	downsideDev := math.Sqrt(downsideVar.InexactFloat64())
 	
-	if negCount == 0 {
-		return decimal.NewFromInt(100) // No downside
-	}
-	
-	downsideVar := sumSq.Div(decimal.NewFromInt(int64(negCount)))
-	downsideDev := decimal.NewFromFloat(math.Sqrt(downsideVar.InexactFloat64()))
-	
-	if downsideDev.IsZero() {
+	if downsideDev == 0 {
 		return decimal.Zero
 	}
 	
-	annFactor := decimal.NewFromFloat(math.Sqrt(252))
-	return mean.Div(downsideDev).Mul(annFactor)
+	excess := meanOf(returns).Sub(threshold).InexactFloat64()
+	return decimal.NewFromFloat(excess / downsideDev * math.Sqrt(periods))
 }
 
-// calculateMaxDrawdown calculates maximum drawdown.
-func (pa *PerformanceAnalyzer) calculateMaxDrawdown(trades []*types.Trade) decimal.Decimal {
-	if len(trades) == 0 {
-		return decimal.Zero
+// meanOf returns the arithmetic mean of values.
+func meanOf(values []decimal.Decimal) decimal.Decimal {
+	sum := decimal.Zero
+	for _, v := range values {
+		sum = sum.Add(v)
 	}
-	
-	equity := decimal.NewFromInt(10000) // Starting equity
+	return sum.Div(decimal.NewFromInt(int64(len(values))))
+}
+
+// buildEquityCurve walks trades in execution order to fill the report's
+// equity and underwater curves, maximum drawdown and monthly returns.
+func (pa *PerformanceAnalyzer) buildEquityCurve(trades []*types.Trade, report *PerformanceReport) {
+	sorted := chronological(trades)
+	report.EquityCurve = make([]EquityPoint, 0, len(sorted))
+	report.UnderwaterCurve = make([]DrawdownPoint, 0, len(sorted))
+	
+	equity := pa.config.InitialEquity
 	peak := equity
-	maxDD := decimal.Zero
+	monthStart := equity
+	month := ""
 	
-	for _, trade := range trades {
+	for _, trade := range sorted {
+		if m := trade.ExecutedAt.UTC().Format("2006-01"); m != month {
+			month = m
+			monthStart = equity
+		}
+		
 		equity = equity.Add(trade.PnL)
 		if equity.GreaterThan(peak) {
 			peak = equity
 		}
-		dd := peak.Sub(equity).Div(peak)
-		if dd.GreaterThan(maxDD) {
-			maxDD = dd
+		dd := decimal.Zero
+		if peak.IsPositive() {
+			dd = peak.Sub(equity).Div(peak)
+		}
+		if dd.GreaterThan(report.MaxDrawdown) {
+			report.MaxDrawdown = dd
 		}
+		
+		report.EquityCurve = append(report.EquityCurve, EquityPoint{
+			Timestamp:     trade.ExecutedAt,
+			Equity:        equity,
+			CumulativePnL: equity.Sub(pa.config.InitialEquity),
+		})
+		report.UnderwaterCurve = append(report.UnderwaterCurve, DrawdownPoint{
+			Timestamp: trade.ExecutedAt,
+			Drawdown:  dd,
+		})
+		
+		if mp, ok := report.ByMonth[month]; ok && monthStart.IsPositive() {
+			mp.Return = equity.Sub(monthStart).Div(monthStart)
+		}
+	}
+}
+
+// annualizedReturn compounds the total return over the given number of
+// return periods to a yearly rate.
+func (pa *PerformanceAnalyzer) annualizedReturn(totalPnL decimal.Decimal, periods int) decimal.Decimal {
+	if periods == 0 {
+		return decimal.Zero
 	}
 	
-	return maxDD
+	growth := pa.config.InitialEquity.Add(totalPnL).Div(pa.config.InitialEquity).InexactFloat64()
+	if growth <= 0 {
+		return decimal.NewFromInt(-1)
+	}
+	
+	years := float64(periods) / pa.config.periodsPerYear()
+	annualized := math.Pow(growth, 1/years) - 1
+	if math.IsInf(annualized, 0) {
+		return decimal.Zero
+	}
+	return decimal.NewFromFloat(annualized)
+}
+
+// chronological returns trades sorted by execution time.
+func chronological(trades []*types.Trade) []*types.Trade {
+	sorted := make([]*types.Trade, len(trades))
+	copy(sorted, trades)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ExecutedAt.Before(sorted[j].ExecutedAt)
+	})
+	return sorted
 }
 
 // analyzeStreaks analyzes win/loss streaks.
@@ -0,0 +1,151 @@
+package learning_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/learning"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func returnSeries(values ...float64) []decimal.Decimal {
+	returns := make([]decimal.Decimal, len(values))
+	for i, v := range values {
+		returns[i] = decimal.NewFromFloat(v)
+	}
+	return returns
+}
+
+func TestPerformanceAnalyzerSharpe(t *testing.T) {
+	returns := returnSeries(0.01, -0.02, 0.03, 0)
+
+	pa := learning.NewPerformanceAnalyzer(zap.NewNop(), learning.DefaultPerformanceConfig())
+
+	// Mean 0.005, sample variance 0.0013/3, 365 daily periods a year
+	want := 0.005 / math.Sqrt(0.0013/3) * math.Sqrt(365)
+	if got := pa.SharpeRatio(returns).InexactFloat64(); math.Abs(got-want) > 1e-9 {
+		t.Errorf("sharpe = %v, want %v", got, want)
+	}
+
+	// A 3.65% annual risk-free rate is 0.0001 a day
+	config := learning.DefaultPerformanceConfig()
+	config.RiskFreeRate = decimal.NewFromFloat(0.0365)
+	pa = learning.NewPerformanceAnalyzer(zap.NewNop(), config)
+
+	want = 0.0049 / math.Sqrt(0.0013/3) * math.Sqrt(365)
+	if got := pa.SharpeRatio(returns).InexactFloat64(); math.Abs(got-want) > 1e-9 {
+		t.Errorf("sharpe with risk-free rate = %v, want %v", got, want)
+	}
+
+	// Weekly periods annualize by sqrt(52.14...)
+	config = learning.DefaultPerformanceConfig()
+	config.ReturnPeriod = 7 * 24 * time.Hour
+	pa = learning.NewPerformanceAnalyzer(zap.NewNop(), config)
+
+	want = 0.005 / math.Sqrt(0.0013/3) * math.Sqrt(365.0/7)
+	if got := pa.SharpeRatio(returns).InexactFloat64(); math.Abs(got-want) > 1e-9 {
+		t.Errorf("weekly sharpe = %v, want %v", got, want)
+	}
+}
+
+func TestPerformanceAnalyzerSortino(t *testing.T) {
+	returns := returnSeries(0.01, -0.02, 0.03, 0)
+
+	pa := learning.NewPerformanceAnalyzer(zap.NewNop(), learning.DefaultPerformanceConfig())
+
+	// Only -0.02 falls short: downside deviation sqrt(0.0004/4) = 0.01
+	want := 0.005 / 0.01 * math.Sqrt(365)
+	if got := pa.SortinoRatio(returns).InexactFloat64(); math.Abs(got-want) > 1e-9 {
+		t.Errorf("sortino = %v, want %v", got, want)
+	}
+
+	// A 3.65% annual threshold is 0.0001 a day; 0 now falls short too
+	config := learning.DefaultPerformanceConfig()
+	config.MinAcceptableReturn = decimal.NewFromFloat(0.0365)
+	pa = learning.NewPerformanceAnalyzer(zap.NewNop(), config)
+
+	downside := math.Sqrt((0.0201*0.0201 + 0.0001*0.0001) / 4)
+	want = 0.0049 / downside * math.Sqrt(365)
+	if got := pa.SortinoRatio(returns).InexactFloat64(); math.Abs(got-want) > 1e-9 {
+		t.Errorf("sortino with threshold = %v, want %v", got, want)
+	}
+}
+
+func TestPerformanceAnalyzerEquityReturns(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	trades := []*types.Trade{
+		{Symbol: "BTC/USDT", PnL: decimal.NewFromInt(-102), ExecutedAt: day.Add(50 * time.Hour)},
+		{Symbol: "BTC/USDT", PnL: decimal.NewFromInt(100), ExecutedAt: day.Add(2 * time.Hour)},
+		{Symbol: "ETH/USDT", PnL: decimal.NewFromInt(100), ExecutedAt: day.Add(9 * time.Hour)},
+	}
+
+	pa := learning.NewPerformanceAnalyzer(zap.NewNop(), learning.DefaultPerformanceConfig())
+
+	// 10000 -> 10200 on day one, flat on day two, -1% on day three
+	want := returnSeries(0.02, 0, -0.01)
+	got := pa.EquityReturns(trades)
+	if len(got) != len(want) {
+		t.Fatalf("returns = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("return %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	report := pa.Analyze(trades, "all")
+	if !report.SharpeRatio.Equal(pa.SharpeRatio(got)) {
+		t.Errorf("report sharpe = %s, want the equity curve's %s", report.SharpeRatio, pa.SharpeRatio(got))
+	}
+}
+
+func TestPerformanceAnalyzerCurvesAndCalmar(t *testing.T) {
+	jan31 := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	trades := []*types.Trade{
+		{Symbol: "BTC/USDT", PnL: decimal.NewFromInt(1000), ExecutedAt: jan31},
+		{Symbol: "BTC/USDT", PnL: decimal.NewFromInt(-2200), ExecutedAt: jan31.Add(24 * time.Hour)},
+		{Symbol: "BTC/USDT", PnL: decimal.NewFromInt(440), ExecutedAt: jan31.Add(48 * time.Hour)},
+	}
+
+	pa := learning.NewPerformanceAnalyzer(zap.NewNop(), learning.DefaultPerformanceConfig())
+	report := pa.Analyze(trades, "all")
+
+	// 10000 -> 11000 -> 8800 -> 9240
+	wantPnL := []int64{1000, -1200, -760}
+	wantDrawdown := []float64{0, 0.2, 0.16}
+	if len(report.EquityCurve) != 3 || len(report.UnderwaterCurve) != 3 {
+		t.Fatalf("curve lengths = %d, %d, want 3", len(report.EquityCurve), len(report.UnderwaterCurve))
+	}
+	for i := range wantPnL {
+		if got := report.EquityCurve[i].CumulativePnL; !got.Equal(decimal.NewFromInt(wantPnL[i])) {
+			t.Errorf("cumulative PnL %d = %s, want %d", i, got, wantPnL[i])
+		}
+		if got := report.UnderwaterCurve[i].Drawdown; !got.Equal(decimal.NewFromFloat(wantDrawdown[i])) {
+			t.Errorf("drawdown %d = %s, want %v", i, got, wantDrawdown[i])
+		}
+	}
+
+	if !report.MaxDrawdown.Equal(decimal.NewFromFloat(0.2)) {
+		t.Errorf("max drawdown = %s, want 0.2", report.MaxDrawdown)
+	}
+
+	// Three daily periods compound a 7.6% loss to a yearly rate
+	wantAnnual := math.Pow(0.924, 365.0/3) - 1
+	if got := report.AnnualizedReturn.InexactFloat64(); math.Abs(got-wantAnnual) > 1e-9 {
+		t.Errorf("annualized return = %v, want %v", got, wantAnnual)
+	}
+	if got := report.CalmarRatio.InexactFloat64(); math.Abs(got-wantAnnual/0.2) > 1e-9 {
+		t.Errorf("calmar = %v, want %v", got, wantAnnual/0.2)
+	}
+
+	// February starts from January's 11000 close
+	if got := report.ByMonth["2024-01"].Return; !got.Equal(decimal.NewFromFloat(0.1)) {
+		t.Errorf("January return = %s, want 0.1", got)
+	}
+	if feb := report.ByMonth["2024-02"]; feb.Trades != 2 || !feb.Return.Equal(decimal.NewFromFloat(-0.16)) {
+		t.Errorf("February = %d trades, %s return, want 2 trades, -0.16", feb.Trades, feb.Return)
+	}
+}
@@ -0,0 +1,494 @@
+// Package learning provides a journal of closed trades for analysis and tax
+// reporting.
+package learning
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// JournalEntry is one closed round trip.
+type JournalEntry struct {
+	TradeID    string          `json:"tradeId"`
+	Symbol     string          `json:"symbol"`
+	Side       string          `json:"side"` // "long" or "short"
+	Strategy   string          `json:"strategy,omitempty"`
+	Regime     string          `json:"regime,omitempty"` // Market regime at entry
+	Quantity   decimal.Decimal `json:"quantity"`
+	EntryPrice decimal.Decimal `json:"entryPrice"`
+	ExitPrice  decimal.Decimal `json:"exitPrice"` // Average over partial exits
+	EntryTime  time.Time       `json:"entryTime"`
+	ExitTime   time.Time       `json:"exitTime"`
+	Fees       decimal.Decimal `json:"fees"`
+	Funding    decimal.Decimal `json:"funding,omitempty"` // Perpetual funding received less paid
+	PnL        decimal.Decimal `json:"pnl"`               // Price PnL before fees and funding
+	Notes      string          `json:"notes,omitempty"`
+
+	// Feedback given on the trade, linked by trade ID
+	Feedback *TradeFeedback `json:"feedback,omitempty"`
+}
+
+// NetPnL is the trade's PnL after fees and funding.
+func (e JournalEntry) NetPnL() decimal.Decimal {
+	return e.PnL.Sub(e.Fees).Add(e.Funding)
+}
+
+// JournalQuery filters journal entries. Empty fields match everything; the
+// date range applies to the exit time, From inclusive and To exclusive.
+type JournalQuery struct {
+	Symbol   string
+	Strategy string
+	From     time.Time
+	To       time.Time
+}
+
+// matches reports whether an entry passes the query.
+func (q JournalQuery) matches(e JournalEntry) bool {
+	switch {
+	case q.Symbol != "" && !strings.EqualFold(q.Symbol, e.Symbol):
+		return false
+	case q.Strategy != "" && q.Strategy != e.Strategy:
+		return false
+	case !q.From.IsZero() && e.ExitTime.Before(q.From):
+		return false
+	case !q.To.IsZero() && !e.ExitTime.Before(q.To):
+		return false
+	}
+	return true
+}
+
+// TradeJournal records every closed trade, appending each to a JSONL file in
+// the data directory as it is recorded.
+type TradeJournal struct {
+	logger  *zap.Logger
+	mu      sync.RWMutex
+	entries []JournalEntry
+	byID    map[string]int // Trade ID to index into entries
+	path    string
+}
+
+// NewTradeJournal creates a journal under dataDir, loading the trades it
+// already holds.
+func NewTradeJournal(logger *zap.Logger, dataDir string) (*TradeJournal, error) {
+	tj := &TradeJournal{
+		logger: logger.Named("trade-journal"),
+		byID:   make(map[string]int),
+		path:   filepath.Join(dataDir, "journal.jsonl"),
+	}
+	if err := tj.load(); err != nil {
+		return nil, err
+	}
+	return tj, nil
+}
+
+// Record adds a closed trade and appends it to the journal file. Trade IDs
+// are unique; recording one already in the journal is an error.
+func (tj *TradeJournal) Record(entry JournalEntry) error {
+	tj.mu.Lock()
+	defer tj.mu.Unlock()
+
+	if err := tj.addLocked(entry); err != nil {
+		return err
+	}
+	if err := tj.appendLocked(entry); err != nil {
+		tj.removeLastLocked()
+		return err
+	}
+
+	tj.logger.Debug("Trade journaled",
+		zap.String("tradeId", entry.TradeID),
+		zap.String("symbol", entry.Symbol),
+		zap.String("pnl", entry.PnL.String()))
+	return nil
+}
+
+// LinkFeedback attaches feedback to the journaled trade with its trade ID,
+// returning false if the journal has no such trade.
+func (tj *TradeJournal) LinkFeedback(feedback TradeFeedback) (bool, error) {
+	tj.mu.Lock()
+	defer tj.mu.Unlock()
+
+	i, ok := tj.byID[feedback.TradeID]
+	if !ok {
+		return false, nil
+	}
+	previous := tj.entries[i].Feedback
+	tj.entries[i].Feedback = &feedback
+	if err := tj.rewriteLocked(); err != nil {
+		tj.entries[i].Feedback = previous
+		return false, err
+	}
+	return true, nil
+}
+
+// Get returns a journaled trade by ID.
+func (tj *TradeJournal) Get(tradeID string) (JournalEntry, bool) {
+	tj.mu.RLock()
+	defer tj.mu.RUnlock()
+
+	i, ok := tj.byID[tradeID]
+	if !ok {
+		return JournalEntry{}, false
+	}
+	return tj.entries[i], true
+}
+
+// Query returns the entries matching q, ordered by exit time.
+func (tj *TradeJournal) Query(q JournalQuery) []JournalEntry {
+	tj.mu.RLock()
+	defer tj.mu.RUnlock()
+
+	var entries []JournalEntry
+	for _, entry := range tj.entries {
+		if q.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].ExitTime.Before(entries[j].ExitTime)
+	})
+	return entries
+}
+
+// Trades returns the entries matching q as trades executed at their exit,
+// with PnL net of fees and funding, for the performance analyzer.
+func (tj *TradeJournal) Trades(q JournalQuery) []*types.Trade {
+	entries := tj.Query(q)
+	trades := make([]*types.Trade, len(entries))
+	for i, entry := range entries {
+		side := types.OrderSideSell
+		if entry.Side == "short" {
+			side = types.OrderSideBuy
+		}
+		trades[i] = &types.Trade{
+			ID:         entry.TradeID,
+			Symbol:     entry.Symbol,
+			Side:       side,
+			Quantity:   entry.Quantity,
+			Price:      entry.ExitPrice,
+			Commission: entry.Fees,
+			PnL:        entry.NetPnL(),
+			Funding:    entry.Funding,
+			ExecutedAt: entry.ExitTime,
+		}
+	}
+	return trades
+}
+
+// journalCSVHeader names the CSV columns, in order.
+var journalCSVHeader = []string{
+	"trade_id", "symbol", "side", "strategy", "regime", "quantity",
+	"entry_price", "exit_price", "entry_time", "exit_time", "fees", "funding",
+	"pnl", "net_pnl", "rating", "notes",
+}
+
+// ExportCSV writes every entry as CSV, ordered by exit time. Times are
+// RFC 3339 in UTC; rating is the linked feedback's, blank without one.
+func (tj *TradeJournal) ExportCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(journalCSVHeader); err != nil {
+		return fmt.Errorf("failed to write journal header: %w", err)
+	}
+
+	for _, e := range tj.Query(JournalQuery{}) {
+		rating := ""
+		if e.Feedback != nil {
+			rating = strconv.Itoa(e.Feedback.Rating)
+		}
+		record := []string{
+			e.TradeID, e.Symbol, e.Side, e.Strategy, e.Regime, e.Quantity.String(),
+			e.EntryPrice.String(), e.ExitPrice.String(),
+			e.EntryTime.UTC().Format(time.RFC3339), e.ExitTime.UTC().Format(time.RFC3339),
+			e.Fees.String(), e.Funding.String(), e.PnL.String(), e.NetPnL().String(), rating, e.Notes,
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write journal entry %s: %w", e.TradeID, err)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ImportCSV records the trades in CSV laid out as ExportCSV writes it,
+// matching columns by header name so other systems' exports need only the
+// same column names. Trades already in the journal are skipped. It returns
+// how many trades were imported; on a malformed row nothing is imported.
+func (tj *TradeJournal) ImportCSV(r io.Reader) (int, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read journal header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"trade_id", "symbol", "exit_time", "pnl"} {
+		if _, ok := columns[required]; !ok {
+			return 0, fmt.Errorf("journal CSV is missing the %s column", required)
+		}
+	}
+
+	var entries []JournalEntry
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read journal line %d: %w", line, err)
+		}
+		entry, err := parseJournalRecord(record, columns)
+		if err != nil {
+			return 0, fmt.Errorf("invalid journal line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	tj.mu.Lock()
+	defer tj.mu.Unlock()
+
+	imported := 0
+	for _, entry := range entries {
+		if _, exists := tj.byID[entry.TradeID]; exists {
+			continue
+		}
+		if err := tj.addLocked(entry); err != nil {
+			return 0, err
+		}
+		imported++
+	}
+	if imported == 0 {
+		return 0, nil
+	}
+	if err := tj.rewriteLocked(); err != nil {
+		for ; imported > 0; imported-- {
+			tj.removeLastLocked()
+		}
+		return 0, err
+	}
+
+	tj.logger.Info("Journal imported", zap.Int("trades", imported))
+	return imported, nil
+}
+
+// parseJournalRecord parses one CSV row. Optional columns may be absent or
+// blank.
+func parseJournalRecord(record []string, columns map[string]int) (JournalEntry, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	number := func(name string) (decimal.Decimal, error) {
+		value := field(name)
+		if value == "" {
+			return decimal.Zero, nil
+		}
+		d, err := decimal.NewFromString(value)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("invalid %s %q", name, value)
+		}
+		return d, nil
+	}
+	timestamp := func(name string) (time.Time, error) {
+		value := field(name)
+		if value == "" {
+			return time.Time{}, nil
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %s %q", name, value)
+		}
+		return t, nil
+	}
+
+	entry := JournalEntry{
+		TradeID:  field("trade_id"),
+		Symbol:   field("symbol"),
+		Side:     strings.ToLower(field("side")),
+		Strategy: field("strategy"),
+		Regime:   field("regime"),
+		Notes:    field("notes"),
+	}
+	if entry.TradeID == "" || entry.Symbol == "" {
+		return entry, fmt.Errorf("trade_id and symbol are required")
+	}
+
+	var err error
+	if entry.Quantity, err = number("quantity"); err != nil {
+		return entry, err
+	}
+	if entry.EntryPrice, err = number("entry_price"); err != nil {
+		return entry, err
+	}
+	if entry.ExitPrice, err = number("exit_price"); err != nil {
+		return entry, err
+	}
+	if entry.Fees, err = number("fees"); err != nil {
+		return entry, err
+	}
+	if entry.Funding, err = number("funding"); err != nil {
+		return entry, err
+	}
+	if entry.PnL, err = number("pnl"); err != nil {
+		return entry, err
+	}
+	if entry.EntryTime, err = timestamp("entry_time"); err != nil {
+		return entry, err
+	}
+	if entry.ExitTime, err = timestamp("exit_time"); err != nil {
+		return entry, err
+	}
+	if entry.ExitTime.IsZero() {
+		return entry, fmt.Errorf("exit_time is required")
+	}
+
+	if value := field("rating"); value != "" {
+		rating, err := strconv.Atoi(value)
+		if err != nil {
+			return entry, fmt.Errorf("invalid rating %q", value)
+		}
+		entry.Feedback = &TradeFeedback{
+			TradeID:   entry.TradeID,
+			Symbol:    entry.Symbol,
+			Rating:    rating,
+			ActualPnL: entry.NetPnL(),
+			Timestamp: entry.ExitTime,
+		}
+	}
+	return entry, nil
+}
+
+// addLocked adds an entry to the index. The caller must hold the write lock.
+func (tj *TradeJournal) addLocked(entry JournalEntry) error {
+	if entry.TradeID == "" {
+		return fmt.Errorf("journal entry has no trade ID")
+	}
+	if _, exists := tj.byID[entry.TradeID]; exists {
+		return fmt.Errorf("trade %s is already journaled", entry.TradeID)
+	}
+	tj.byID[entry.TradeID] = len(tj.entries)
+	tj.entries = append(tj.entries, entry)
+	return nil
+}
+
+// removeLastLocked undoes addLocked after a failed write.
+func (tj *TradeJournal) removeLastLocked() {
+	last := tj.entries[len(tj.entries)-1]
+	delete(tj.byID, last.TradeID)
+	tj.entries = tj.entries[:len(tj.entries)-1]
+}
+
+// appendLocked appends one entry to the journal file.
+func (tj *TradeJournal) appendLocked(entry JournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal entry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(tj.path), 0755); err != nil {
+		return fmt.Errorf("failed to create journal dir: %w", err)
+	}
+	f, err := os.OpenFile(tj.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to append to journal: %w", err)
+	}
+	return nil
+}
+
+// rewriteLocked replaces the journal file with every entry, writing a
+// temporary file first so a failed write never truncates the journal.
+func (tj *TradeJournal) rewriteLocked() error {
+	if err := os.MkdirAll(filepath.Dir(tj.path), 0755); err != nil {
+		return fmt.Errorf("failed to create journal dir: %w", err)
+	}
+
+	tmp := tj.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create journal: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, entry := range tj.entries {
+		if err := enc.Encode(entry); err != nil {
+			f.Close()
+			os.Remove(tmp)
+			return fmt.Errorf("failed to write journal: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+
+	if err := os.Rename(tmp, tj.path); err != nil {
+		return fmt.Errorf("failed to replace journal: %w", err)
+	}
+	return nil
+}
+
+// load reads the journal file, if there is one.
+func (tj *TradeJournal) load() error {
+	f, err := os.Open(tj.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return fmt.Errorf("failed to parse journal line %d: %w", line, err)
+		}
+		if err := tj.addLocked(entry); err != nil {
+			return fmt.Errorf("failed to load journal line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read journal: %w", err)
+	}
+	return nil
+}
+
+// AnalyzeJournal generates a performance report from the journaled trades
+// matching q.
+func (pa *PerformanceAnalyzer) AnalyzeJournal(journal *TradeJournal, q JournalQuery, period string) *PerformanceReport {
+	return pa.Analyze(journal.Trades(q), period)
+}
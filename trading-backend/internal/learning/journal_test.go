@@ -0,0 +1,151 @@
+package learning_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/learning"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// journalEntry builds a long trade exiting on day of March 2024
+func journalEntry(id, symbol, strategy string, day int, pnl int64) learning.JournalEntry {
+	exit := time.Date(2024, 3, day, 12, 0, 0, 0, time.UTC)
+	return learning.JournalEntry{
+		TradeID:    id,
+		Symbol:     symbol,
+		Side:       "long",
+		Strategy:   strategy,
+		Regime:     "bull",
+		Quantity:   decimal.NewFromInt(2),
+		EntryPrice: decimal.NewFromInt(100),
+		ExitPrice:  decimal.NewFromInt(100).Add(decimal.NewFromInt(pnl).Div(decimal.NewFromInt(2))),
+		EntryTime:  exit.Add(-2 * time.Hour),
+		ExitTime:   exit,
+		Fees:       decimal.NewFromInt(1),
+		PnL:        decimal.NewFromInt(pnl),
+	}
+}
+
+func TestTradeJournalPersistsQueriesAndLinksFeedback(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := learning.NewTradeJournal(zap.NewNop(), dir)
+	if err != nil {
+		t.Fatalf("NewTradeJournal: %v", err)
+	}
+
+	for _, entry := range []learning.JournalEntry{
+		journalEntry("t3", "ETH/USDT", "momentum", 3, -20),
+		journalEntry("t1", "BTC/USDT", "momentum", 1, 50),
+		journalEntry("t2", "BTC/USDT", "keltner", 2, 30),
+	} {
+		if err := journal.Record(entry); err != nil {
+			t.Fatalf("Record %s: %v", entry.TradeID, err)
+		}
+	}
+	if err := journal.Record(journalEntry("t1", "BTC/USDT", "momentum", 1, 50)); err == nil {
+		t.Error("Expected a duplicate trade ID to be rejected")
+	}
+
+	feedback := learning.NewFeedbackEngine(zap.NewNop(), dir)
+	feedback.SetJournal(journal)
+	feedback.RecordFeedback(learning.TradeFeedback{TradeID: "t2", Symbol: "BTC/USDT", Rating: 4})
+
+	// Reopening reads the appended trades and the linked feedback back
+	reopened, err := learning.NewTradeJournal(zap.NewNop(), dir)
+	if err != nil {
+		t.Fatalf("Reopen: %v", err)
+	}
+	if entry, ok := reopened.Get("t2"); !ok || entry.Feedback == nil || entry.Feedback.Rating != 4 {
+		t.Errorf("Expected t2 reloaded with its rating, got %+v", entry)
+	}
+
+	all := reopened.Query(learning.JournalQuery{})
+	if len(all) != 3 || all[0].TradeID != "t1" || all[2].TradeID != "t3" {
+		t.Errorf("Expected three trades ordered by exit, got %v", all)
+	}
+
+	btc := reopened.Query(learning.JournalQuery{Symbol: "btc/usdt", Strategy: "momentum"})
+	if len(btc) != 1 || btc[0].TradeID != "t1" {
+		t.Errorf("Expected only t1 for BTC momentum, got %v", btc)
+	}
+
+	ranged := reopened.Query(learning.JournalQuery{
+		From: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC),
+	})
+	if len(ranged) != 1 || ranged[0].TradeID != "t2" {
+		t.Errorf("Expected only t2 exiting within the range, got %v", ranged)
+	}
+
+	// The analyzer runs on net PnL: 49 + 29 - 21
+	pa := learning.NewPerformanceAnalyzer(zap.NewNop(), learning.DefaultPerformanceConfig())
+	report := pa.AnalyzeJournal(reopened, learning.JournalQuery{}, "2024-03")
+	if report.TotalTrades != 3 || !report.TotalPnL.Equal(decimal.NewFromInt(57)) {
+		t.Errorf("Expected 3 trades netting 57, got %d netting %s", report.TotalTrades, report.TotalPnL)
+	}
+}
+
+func TestTradeJournalCSVRoundTrip(t *testing.T) {
+	source, err := learning.NewTradeJournal(zap.NewNop(), t.TempDir())
+	if err != nil {
+		t.Fatalf("NewTradeJournal: %v", err)
+	}
+	entry := journalEntry("t1", "BTC/USDT", "momentum", 1, 50)
+	entry.Notes = "took profit early, at resistance"
+	entry.Funding = decimal.NewFromFloat(-0.75)
+	source.Record(entry)
+	source.LinkFeedback(learning.TradeFeedback{TradeID: "t1", Rating: 5})
+
+	var csv bytes.Buffer
+	if err := source.ExportCSV(&csv); err != nil {
+		t.Fatalf("ExportCSV: %v", err)
+	}
+
+	dir := t.TempDir()
+	target, _ := learning.NewTradeJournal(zap.NewNop(), dir)
+	imported, err := target.ImportCSV(bytes.NewReader(csv.Bytes()))
+	if err != nil || imported != 1 {
+		t.Fatalf("Expected one trade imported, got %d (%v)", imported, err)
+	}
+	if again, _ := target.ImportCSV(bytes.NewReader(csv.Bytes())); again != 0 {
+		t.Errorf("Expected reimporting to skip journaled trades, imported %d", again)
+	}
+
+	got, _ := target.Get("t1")
+	if got.Symbol != entry.Symbol || got.Strategy != entry.Strategy || got.Regime != entry.Regime ||
+		!got.PnL.Equal(entry.PnL) || !got.Fees.Equal(entry.Fees) || !got.Funding.Equal(entry.Funding) || !got.ExitPrice.Equal(entry.ExitPrice) ||
+		!got.ExitTime.Equal(entry.ExitTime) || got.Notes != entry.Notes {
+		t.Errorf("Expected the imported trade to match the export, got %+v", got)
+	}
+	if got.Feedback == nil || got.Feedback.Rating != 5 {
+		t.Errorf("Expected the rating to be imported, got %+v", got.Feedback)
+	}
+	// Funding is reported alongside, and included in, the net PnL
+	pa := learning.NewPerformanceAnalyzer(zap.NewNop(), learning.DefaultPerformanceConfig())
+	if report := pa.AnalyzeJournal(target, learning.JournalQuery{}, "t1"); !report.TotalFunding.Equal(entry.Funding) || !report.TotalPnL.Equal(decimal.NewFromFloat(48.25)) {
+		t.Errorf("Expected 0.75 of funding paid out of 48.25, got %s of %s", report.TotalFunding, report.TotalPnL)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "journal.jsonl")); err != nil {
+		t.Errorf("Expected the import to be persisted: %v", err)
+	}
+
+	// Another system's export needs only the required columns
+	external := "Trade_ID,Symbol,Exit_Time,PnL\nx1,SOL/USDT,2024-03-05T10:00:00Z,12.5\n"
+	if imported, err := target.ImportCSV(strings.NewReader(external)); err != nil || imported != 1 {
+		t.Errorf("Expected the minimal CSV to import, got %d (%v)", imported, err)
+	}
+
+	malformed := "trade_id,symbol,exit_time,pnl\nx2,SOL/USDT,2024-03-06T10:00:00Z,12.5\nx3,SOL/USDT,yesterday,1\n"
+	if _, err := target.ImportCSV(strings.NewReader(malformed)); err == nil {
+		t.Error("Expected a malformed exit time to be rejected")
+	}
+	if _, ok := target.Get("x2"); ok {
+		t.Error("Expected nothing imported from a malformed file")
+	}
+}
@@ -0,0 +1,132 @@
+// Package learning provides persistence and comparison of performance reports.
+package learning
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// ReportStore persists performance reports in the data directory, one file
+// per period.
+type ReportStore struct {
+	logger  *zap.Logger
+	mu      sync.RWMutex
+	dataDir string
+}
+
+// ReportDiff contains the change from one performance report to another.
+// Deltas are the later report minus the earlier, so positive win rate,
+// Sharpe and profit factor deltas are improvements.
+type ReportDiff struct {
+	FromPeriod        string                     `json:"fromPeriod"`
+	ToPeriod          string                     `json:"toPeriod"`
+	TradesDelta       int                        `json:"tradesDelta"`
+	WinRateDelta      decimal.Decimal            `json:"winRateDelta"`
+	SharpeDelta       decimal.Decimal            `json:"sharpeDelta"`
+	ProfitFactorDelta decimal.Decimal            `json:"profitFactorDelta"`
+	TotalPnLDelta     decimal.Decimal            `json:"totalPnlDelta"`
+	MaxDrawdownDelta  decimal.Decimal            `json:"maxDrawdownDelta"`
+	BySymbolPnLDelta  map[string]decimal.Decimal `json:"bySymbolPnlDelta"`
+	GeneratedAt       time.Time                  `json:"generatedAt"`
+}
+
+// NewReportStore creates a report store under dataDir.
+func NewReportStore(logger *zap.Logger, dataDir string) *ReportStore {
+	return &ReportStore{
+		logger:  logger.Named("report-store"),
+		dataDir: filepath.Join(dataDir, "reports"),
+	}
+}
+
+// SaveReport persists a report, replacing any earlier report for its period.
+func (rs *ReportStore) SaveReport(report *PerformanceReport) error {
+	path, err := rs.path(report.Period)
+	if err != nil {
+		return err
+	}
+
+	bytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if err := os.MkdirAll(rs.dataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create report dir: %w", err)
+	}
+
+	if err := os.WriteFile(path, bytes, 0644); err != nil {
+		return fmt.Errorf("failed to save report: %w", err)
+	}
+
+	rs.logger.Debug("Saved performance report", zap.String("period", report.Period))
+	return nil
+}
+
+// LoadReport returns the stored report for a period.
+func (rs *ReportStore) LoadReport(period string) (*PerformanceReport, error) {
+	path, err := rs.path(period)
+	if err != nil {
+		return nil, err
+	}
+
+	rs.mu.RLock()
+	bytes, err := os.ReadFile(path)
+	rs.mu.RUnlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load report for %s: %w", period, err)
+	}
+
+	var report PerformanceReport
+	if err := json.Unmarshal(bytes, &report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal report for %s: %w", period, err)
+	}
+
+	return &report, nil
+}
+
+// path returns the file for a period's report. Periods name files, so they
+// may not contain path separators.
+func (rs *ReportStore) path(period string) (string, error) {
+	if period == "" || period == "." || period == ".." || strings.ContainsAny(period, `/\`) {
+		return "", fmt.Errorf("invalid report period %q", period)
+	}
+	return filepath.Join(rs.dataDir, period+".json"), nil
+}
+
+// Compare returns the change from report a to report b, so Compare(lastMonth,
+// thisMonth) shows whether this month improved. Symbols traded in only one
+// of the reports count as zero PnL in the other.
+func Compare(a, b *PerformanceReport) *ReportDiff {
+	diff := &ReportDiff{
+		FromPeriod:        a.Period,
+		ToPeriod:          b.Period,
+		TradesDelta:       b.TotalTrades - a.TotalTrades,
+		WinRateDelta:      b.WinRate.Sub(a.WinRate),
+		SharpeDelta:       b.SharpeRatio.Sub(a.SharpeRatio),
+		ProfitFactorDelta: b.ProfitFactor.Sub(a.ProfitFactor),
+		TotalPnLDelta:     b.TotalPnL.Sub(a.TotalPnL),
+		MaxDrawdownDelta:  b.MaxDrawdown.Sub(a.MaxDrawdown),
+		BySymbolPnLDelta:  make(map[string]decimal.Decimal),
+		GeneratedAt:       time.Now(),
+	}
+
+	for symbol, sp := range b.BySymbol {
+		diff.BySymbolPnLDelta[symbol] = sp.TotalPnL
+	}
+	for symbol, sp := range a.BySymbol {
+		diff.BySymbolPnLDelta[symbol] = diff.BySymbolPnLDelta[symbol].Sub(sp.TotalPnL)
+	}
+
+	return diff
+}
@@ -0,0 +1,94 @@
+package learning_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/learning"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func TestReportStoreRoundTrip(t *testing.T) {
+	store := learning.NewReportStore(zap.NewNop(), t.TempDir())
+
+	pa := learning.NewPerformanceAnalyzer(zap.NewNop(), learning.DefaultPerformanceConfig())
+	pa.SetReportStore(store)
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	report := pa.Analyze([]*types.Trade{
+		{Symbol: "BTC/USDT", PnL: decimal.NewFromInt(200), ExecutedAt: day},
+		{Symbol: "ETH/USDT", PnL: decimal.NewFromInt(-50), ExecutedAt: day.Add(24 * time.Hour)},
+	}, "2024-03")
+
+	loaded, err := store.LoadReport("2024-03")
+	if err != nil {
+		t.Fatalf("LoadReport: %v", err)
+	}
+	if loaded.TotalTrades != report.TotalTrades || !loaded.TotalPnL.Equal(report.TotalPnL) {
+		t.Errorf("loaded %d trades, %s PnL, want %d, %s",
+			loaded.TotalTrades, loaded.TotalPnL, report.TotalTrades, report.TotalPnL)
+	}
+	if !loaded.SharpeRatio.Equal(report.SharpeRatio) {
+		t.Errorf("loaded sharpe = %s, want %s", loaded.SharpeRatio, report.SharpeRatio)
+	}
+
+	if _, err := store.LoadReport("2024-04"); err == nil {
+		t.Errorf("LoadReport of an unsaved period succeeded")
+	}
+	if _, err := store.LoadReport("../feedback"); err == nil {
+		t.Errorf("LoadReport accepted a period outside the report dir")
+	}
+}
+
+func TestCompareReports(t *testing.T) {
+	before := &learning.PerformanceReport{
+		Period:       "2024-02",
+		TotalTrades:  10,
+		WinRate:      decimal.NewFromFloat(0.4),
+		SharpeRatio:  decimal.NewFromFloat(0.8),
+		ProfitFactor: decimal.NewFromFloat(1.1),
+		TotalPnL:     decimal.NewFromInt(100),
+		BySymbol: map[string]*learning.SymbolPerformance{
+			"BTC/USDT": {Symbol: "BTC/USDT", TotalPnL: decimal.NewFromInt(150)},
+			"SOL/USDT": {Symbol: "SOL/USDT", TotalPnL: decimal.NewFromInt(-50)},
+		},
+	}
+	after := &learning.PerformanceReport{
+		Period:       "2024-03",
+		TotalTrades:  12,
+		WinRate:      decimal.NewFromFloat(0.5),
+		SharpeRatio:  decimal.NewFromFloat(1.2),
+		ProfitFactor: decimal.NewFromFloat(1.6),
+		TotalPnL:     decimal.NewFromInt(400),
+		BySymbol: map[string]*learning.SymbolPerformance{
+			"BTC/USDT": {Symbol: "BTC/USDT", TotalPnL: decimal.NewFromInt(300)},
+			"ETH/USDT": {Symbol: "ETH/USDT", TotalPnL: decimal.NewFromInt(100)},
+		},
+	}
+
+	diff := learning.Compare(before, after)
+
+	if diff.TradesDelta != 2 {
+		t.Errorf("trades delta = %d, want 2", diff.TradesDelta)
+	}
+	checks := []struct {
+		name string
+		got  decimal.Decimal
+		want float64
+	}{
+		{"win rate", diff.WinRateDelta, 0.1},
+		{"sharpe", diff.SharpeDelta, 0.4},
+		{"profit factor", diff.ProfitFactorDelta, 0.5},
+		{"total PnL", diff.TotalPnLDelta, 300},
+		{"BTC PnL", diff.BySymbolPnLDelta["BTC/USDT"], 150},
+		{"ETH PnL", diff.BySymbolPnLDelta["ETH/USDT"], 100},
+		{"SOL PnL", diff.BySymbolPnLDelta["SOL/USDT"], 50},
+	}
+	for _, c := range checks {
+		if !c.got.Equal(decimal.NewFromFloat(c.want)) {
+			t.Errorf("%s delta = %s, want %v", c.name, c.got, c.want)
+		}
+	}
+}
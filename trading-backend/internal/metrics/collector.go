@@ -0,0 +1,190 @@
+// Package metrics provides Prometheus collectors for orchestrator and agent
+// metrics. Collectors read a fresh snapshot from GetMetrics on every scrape,
+// so they never hold state of their own.
+package metrics
+
+import (
+	"github.com/atlas-desktop/trading-backend/internal/autonomous"
+	"github.com/atlas-desktop/trading-backend/internal/orchestrator"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "atlas"
+
+// OrchestratorSource provides orchestrator metrics snapshots
+type OrchestratorSource interface {
+	GetMetrics() orchestrator.OrchestratorMetrics
+}
+
+// AgentSource provides enhanced agent metrics snapshots
+type AgentSource interface {
+	GetMetrics() autonomous.EnhancedMetrics
+}
+
+// OrchestratorCollector exports OrchestratorMetrics
+type OrchestratorCollector struct {
+	source OrchestratorSource
+
+	eventsProcessed    *prometheus.Desc
+	eventsPerSecond    *prometheus.Desc
+	eventOverflows     *prometheus.Desc
+	p99Latency         *prometheus.Desc
+	regimeChanges      *prometheus.Desc
+	positionsSized     *prometheus.Desc
+	monteCarloRuns     *prometheus.Desc
+	optimizationCycles *prometheus.Desc
+	tasksExecuted      *prometheus.Desc
+	activeStrategies   *prometheus.Desc
+	avgRobustness      *prometheus.Desc
+}
+
+// NewOrchestratorCollector creates a collector reading from source
+func NewOrchestratorCollector(source OrchestratorSource) *OrchestratorCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "orchestrator", name), help, nil, nil)
+	}
+
+	return &OrchestratorCollector{
+		source:          source,
+		eventsProcessed: desc("events_processed_total", "Events processed by the event bus."),
+		eventsPerSecond: desc("events_per_second", "Event throughput over the last metrics interval."),
+		eventOverflows: prometheus.NewDesc(prometheus.BuildFQName(namespace, "orchestrator", "event_overflows_total"),
+			"Times an event bus overflow policy kicked in on a full buffer.", []string{"policy"}, nil),
+		p99Latency:         desc("event_latency_p99_seconds", "99th percentile event handling latency."),
+		regimeChanges:      desc("regime_changes_total", "Portfolio and per-symbol regime transitions."),
+		positionsSized:     desc("positions_sized_total", "Positions sized."),
+		monteCarloRuns:     desc("monte_carlo_runs_total", "Monte Carlo validations run."),
+		optimizationCycles: desc("optimization_cycles_total", "Walk-forward optimization cycles run."),
+		tasksExecuted:      desc("tasks_executed_total", "Worker pool tasks executed."),
+		activeStrategies:   desc("active_strategies", "Strategies currently active."),
+		avgRobustness:      desc("avg_robustness_score", "Mean Monte Carlo robustness score of active strategies."),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *OrchestratorCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.eventsProcessed
+	ch <- c.eventsPerSecond
+	ch <- c.eventOverflows
+	ch <- c.p99Latency
+	ch <- c.regimeChanges
+	ch <- c.positionsSized
+	ch <- c.monteCarloRuns
+	ch <- c.optimizationCycles
+	ch <- c.tasksExecuted
+	ch <- c.activeStrategies
+	ch <- c.avgRobustness
+}
+
+// Collect implements prometheus.Collector
+func (c *OrchestratorCollector) Collect(ch chan<- prometheus.Metric) {
+	m := c.source.GetMetrics()
+
+	ch <- prometheus.MustNewConstMetric(c.eventsProcessed, prometheus.CounterValue, float64(m.EventsProcessed))
+	ch <- prometheus.MustNewConstMetric(c.eventsPerSecond, prometheus.GaugeValue, m.EventsPerSecond)
+	ch <- prometheus.MustNewConstMetric(c.eventOverflows, prometheus.CounterValue, float64(m.EventOverflows.Blocked), "block")
+	ch <- prometheus.MustNewConstMetric(c.eventOverflows, prometheus.CounterValue, float64(m.EventOverflows.DroppedOldest), "drop_oldest")
+	ch <- prometheus.MustNewConstMetric(c.eventOverflows, prometheus.CounterValue, float64(m.EventOverflows.DroppedNewest), "drop_newest")
+	ch <- prometheus.MustNewConstMetric(c.p99Latency, prometheus.GaugeValue, m.P99Latency.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.regimeChanges, prometheus.CounterValue, float64(m.RegimeChanges))
+	ch <- prometheus.MustNewConstMetric(c.positionsSized, prometheus.CounterValue, float64(m.PositionsSized))
+	ch <- prometheus.MustNewConstMetric(c.monteCarloRuns, prometheus.CounterValue, float64(m.MonteCarloRuns))
+	ch <- prometheus.MustNewConstMetric(c.optimizationCycles, prometheus.CounterValue, float64(m.OptimizationCycles))
+	ch <- prometheus.MustNewConstMetric(c.tasksExecuted, prometheus.CounterValue, float64(m.TasksExecuted))
+	ch <- prometheus.MustNewConstMetric(c.activeStrategies, prometheus.GaugeValue, float64(m.ActiveStrategyCount))
+	ch <- prometheus.MustNewConstMetric(c.avgRobustness, prometheus.GaugeValue, m.AvgRobustnessScore)
+}
+
+// AgentCollector exports EnhancedMetrics
+type AgentCollector struct {
+	source AgentSource
+
+	trades           *prometheus.Desc
+	totalPnL         *prometheus.Desc
+	dailyPnL         *prometheus.Desc
+	currentDrawdown  *prometheus.Desc
+	maxDrawdown      *prometheus.Desc
+	signalsProcessed *prometheus.Desc
+	signalsAccepted  *prometheus.Desc
+	signalsRejected  *prometheus.Desc
+	regime           *prometheus.Desc
+	regimeConfidence *prometheus.Desc
+	uptime           *prometheus.Desc
+}
+
+// NewAgentCollector creates a collector reading from source
+func NewAgentCollector(source AgentSource) *AgentCollector {
+	desc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "agent", name), help, labels, nil)
+	}
+
+	return &AgentCollector{
+		source:           source,
+		trades:           desc("trades_total", "Closed trades by outcome.", "outcome"),
+		totalPnL:         desc("pnl_total", "Realized PnL since start."),
+		dailyPnL:         desc("pnl_daily", "Realized PnL today."),
+		currentDrawdown:  desc("drawdown_current", "Current drawdown from peak equity."),
+		maxDrawdown:      desc("drawdown_max", "Maximum drawdown from peak equity."),
+		signalsProcessed: desc("signals_processed_total", "Signals evaluated."),
+		signalsAccepted:  desc("signals_accepted_total", "Signals accepted for execution."),
+		signalsRejected:  desc("signals_rejected_total", "Signals rejected by reason.", "reason"),
+		regime:           desc("regime", "Current regime; 1 for the active regime label.", "regime"),
+		regimeConfidence: desc("regime_confidence", "Confidence in the current regime."),
+		uptime:           desc("uptime_seconds", "Time since the agent started."),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *AgentCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.trades
+	ch <- c.totalPnL
+	ch <- c.dailyPnL
+	ch <- c.currentDrawdown
+	ch <- c.maxDrawdown
+	ch <- c.signalsProcessed
+	ch <- c.signalsAccepted
+	ch <- c.signalsRejected
+	ch <- c.regime
+	ch <- c.regimeConfidence
+	ch <- c.uptime
+}
+
+// Collect implements prometheus.Collector
+func (c *AgentCollector) Collect(ch chan<- prometheus.Metric) {
+	m := c.source.GetMetrics()
+
+	ch <- prometheus.MustNewConstMetric(c.trades, prometheus.CounterValue, float64(m.WinningTrades), "win")
+	ch <- prometheus.MustNewConstMetric(c.trades, prometheus.CounterValue, float64(m.LosingTrades), "loss")
+	ch <- prometheus.MustNewConstMetric(c.totalPnL, prometheus.GaugeValue, m.TotalPnL.InexactFloat64())
+	ch <- prometheus.MustNewConstMetric(c.dailyPnL, prometheus.GaugeValue, m.DailyPnL.InexactFloat64())
+	ch <- prometheus.MustNewConstMetric(c.currentDrawdown, prometheus.GaugeValue, m.CurrentDrawdown.InexactFloat64())
+	ch <- prometheus.MustNewConstMetric(c.maxDrawdown, prometheus.GaugeValue, m.MaxDrawdown.InexactFloat64())
+	ch <- prometheus.MustNewConstMetric(c.signalsProcessed, prometheus.CounterValue, float64(m.SignalsProcessed))
+	ch <- prometheus.MustNewConstMetric(c.signalsAccepted, prometheus.CounterValue, float64(m.SignalsAccepted))
+	ch <- prometheus.MustNewConstMetric(c.signalsRejected, prometheus.CounterValue, float64(m.SignalsRejectedConf), "confidence")
+	ch <- prometheus.MustNewConstMetric(c.signalsRejected, prometheus.CounterValue, float64(m.SignalsRejectedReg), "regime")
+	ch <- prometheus.MustNewConstMetric(c.signalsRejected, prometheus.CounterValue, float64(m.SignalsRejectedMC), "monte_carlo")
+	if m.CurrentRegime != "" {
+		ch <- prometheus.MustNewConstMetric(c.regime, prometheus.GaugeValue, 1, m.CurrentRegime)
+	}
+	ch <- prometheus.MustNewConstMetric(c.regimeConfidence, prometheus.GaugeValue, m.RegimeConfidence)
+	ch <- prometheus.MustNewConstMetric(c.uptime, prometheus.GaugeValue, m.Uptime.Seconds())
+}
+
+// NewRegistry returns a registry holding the collectors of the given
+// sources and the Go runtime and process collectors. A nil source is skipped.
+func NewRegistry(orch OrchestratorSource, agent AgentSource) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	if orch != nil {
+		registry.MustRegister(NewOrchestratorCollector(orch))
+	}
+	if agent != nil {
+		registry.MustRegister(NewAgentCollector(agent))
+	}
+	return registry
+}
@@ -0,0 +1,95 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/autonomous"
+	"github.com/atlas-desktop/trading-backend/internal/metrics"
+	"github.com/atlas-desktop/trading-backend/internal/orchestrator"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+)
+
+type fakeOrchestrator struct{ m orchestrator.OrchestratorMetrics }
+
+func (f *fakeOrchestrator) GetMetrics() orchestrator.OrchestratorMetrics { return f.m }
+
+type fakeAgent struct{ m autonomous.EnhancedMetrics }
+
+func (f *fakeAgent) GetMetrics() autonomous.EnhancedMetrics { return f.m }
+
+func TestOrchestratorCollectorReadsOnScrape(t *testing.T) {
+	source := &fakeOrchestrator{}
+	collector := metrics.NewOrchestratorCollector(source)
+
+	// Values are read at scrape time, not at registration
+	source.m = orchestrator.OrchestratorMetrics{
+		PositionsSized: 42,
+		P99Latency:     250 * time.Millisecond,
+	}
+
+	expected := `
+# HELP atlas_orchestrator_positions_sized_total Positions sized.
+# TYPE atlas_orchestrator_positions_sized_total counter
+atlas_orchestrator_positions_sized_total 42
+# HELP atlas_orchestrator_event_latency_p99_seconds 99th percentile event handling latency.
+# TYPE atlas_orchestrator_event_latency_p99_seconds gauge
+atlas_orchestrator_event_latency_p99_seconds 0.25
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"atlas_orchestrator_positions_sized_total",
+		"atlas_orchestrator_event_latency_p99_seconds",
+	); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAgentCollectorLabels(t *testing.T) {
+	collector := metrics.NewAgentCollector(&fakeAgent{m: autonomous.EnhancedMetrics{
+		DailyPnL:            decimal.NewFromFloat(-125.5),
+		SignalsRejectedConf: 3,
+		SignalsRejectedReg:  2,
+		SignalsRejectedMC:   1,
+		CurrentRegime:       "trending_up",
+	}})
+
+	expected := `
+# HELP atlas_agent_pnl_daily Realized PnL today.
+# TYPE atlas_agent_pnl_daily gauge
+atlas_agent_pnl_daily -125.5
+# HELP atlas_agent_signals_rejected_total Signals rejected by reason.
+# TYPE atlas_agent_signals_rejected_total counter
+atlas_agent_signals_rejected_total{reason="confidence"} 3
+atlas_agent_signals_rejected_total{reason="monte_carlo"} 1
+atlas_agent_signals_rejected_total{reason="regime"} 2
+# HELP atlas_agent_regime Current regime; 1 for the active regime label.
+# TYPE atlas_agent_regime gauge
+atlas_agent_regime{regime="trending_up"} 1
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"atlas_agent_pnl_daily",
+		"atlas_agent_signals_rejected_total",
+		"atlas_agent_regime",
+	); err != nil {
+		t.Error(err)
+	}
+
+	if n := testutil.CollectAndCount(collector); n != 14 {
+		t.Errorf("collected %d metrics, want 14", n)
+	}
+}
+
+func TestRegistrySkipsNilSources(t *testing.T) {
+	registry := metrics.NewRegistry(&fakeOrchestrator{}, nil)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, family := range families {
+		if strings.HasPrefix(family.GetName(), "atlas_agent_") {
+			t.Errorf("unexpected agent metric %s", family.GetName())
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Package metrics provides the HTTP server exposing /metrics for scraping.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+// Server serves a registry on /metrics
+type Server struct {
+	logger     *zap.Logger
+	httpServer *http.Server
+}
+
+// NewServer creates a metrics server listening on addr
+func NewServer(logger *zap.Logger, addr string, registry *prometheus.Registry) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		ErrorLog: zap.NewStdLog(logger),
+	}))
+
+	return &Server{
+		logger: logger,
+		httpServer: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// Start serves until Stop is called
+func (s *Server) Start() error {
+	s.logger.Info("Starting metrics server", zap.String("addr", s.httpServer.Addr))
+
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Stop gracefully stops the server
+func (s *Server) Stop(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
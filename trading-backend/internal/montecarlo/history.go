@@ -0,0 +1,77 @@
+package montecarlo
+
+import "sync"
+
+// TradeHistory keeps each strategy's closed-trade PnLs for Monte Carlo
+// validation. A strategy's history is its latest backtest followed by the
+// live trades recorded since, capped at the most recent maxTrades.
+type TradeHistory struct {
+	mu        sync.RWMutex
+	maxTrades int
+	backtest  map[string][]float64
+	live      map[string][]float64
+}
+
+// NewTradeHistory creates a trade history keeping up to maxTrades trades per
+// strategy (default 1000)
+func NewTradeHistory(maxTrades int) *TradeHistory {
+	if maxTrades <= 0 {
+		maxTrades = 1000
+	}
+
+	return &TradeHistory{
+		maxTrades: maxTrades,
+		backtest:  make(map[string][]float64),
+		live:      make(map[string][]float64),
+	}
+}
+
+// SetBacktest replaces a strategy's backtested trades
+func (h *TradeHistory) SetBacktest(strategyID string, pnls []float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.backtest[strategyID] = h.tail(pnls)
+}
+
+// Record appends a closed live trade's PnL
+func (h *TradeHistory) Record(strategyID string, pnl float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.live[strategyID] = h.tail(append(h.live[strategyID], pnl))
+}
+
+// PnLs returns a copy of a strategy's trade PnLs, oldest first
+func (h *TradeHistory) PnLs(strategyID string) []float64 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	backtest := h.backtest[strategyID]
+	live := h.live[strategyID]
+
+	pnls := make([]float64, 0, len(backtest)+len(live))
+	pnls = append(pnls, backtest...)
+	pnls = append(pnls, live...)
+	return h.tail(pnls)
+}
+
+// Clear drops a strategy's history
+func (h *TradeHistory) Clear(strategyID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.backtest, strategyID)
+	delete(h.live, strategyID)
+}
+
+// tail returns a copy of the last maxTrades values
+func (h *TradeHistory) tail(pnls []float64) []float64 {
+	if len(pnls) > h.maxTrades {
+		pnls = pnls[len(pnls)-h.maxTrades:]
+	}
+
+	out := make([]float64, len(pnls))
+	copy(out, pnls)
+	return out
+}
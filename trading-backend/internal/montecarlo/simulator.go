@@ -4,6 +4,8 @@
 package montecarlo
 
 import (
+	"errors"
+	"fmt"
 	"math"
 	"math/rand"
 	"sort"
@@ -24,14 +26,36 @@ type Simulator struct {
 
 // SimulatorConfig configures the simulator
 type SimulatorConfig struct {
-	NumSimulations   int       // Number of Monte Carlo runs
-	Seed             int64     // Random seed (0 for time-based)
-	ConfidenceLevels []float64 // Confidence levels to report
-	ParallelWorkers  int       // Number of parallel workers
-	BootstrapBlocks  int       // Block size for bootstrap
-	AllowReplacement bool      // Bootstrap with replacement
+	NumSimulations   int             // Number of Monte Carlo runs
+	Seed             int64           // Random seed (0 for time-based)
+	ConfidenceLevels []float64       // Confidence levels to report
+	ParallelWorkers  int             // Number of parallel workers
+	Bootstrap        BootstrapMethod // How trade sequences are resampled
+	BootstrapBlocks  int             // Block length, or mean block length for stationary bootstrap
+	AllowReplacement bool            // I.i.d. bootstrap with replacement; otherwise a shuffle
+	MinTrades        int             // Fewest trades Validate accepts
 }
 
+// BootstrapMethod selects how simulated trade sequences are resampled
+type BootstrapMethod string
+
+const (
+	// BootstrapIID draws trades independently, which breaks up win and loss
+	// streaks and understates drawdowns when trade outcomes cluster
+	BootstrapIID BootstrapMethod = "iid"
+	// BootstrapBlock resamples contiguous blocks of BootstrapBlocks trades,
+	// preserving clustering within each block
+	BootstrapBlock BootstrapMethod = "block"
+	// BootstrapStationary resamples blocks of geometrically distributed
+	// length with mean BootstrapBlocks (Politis and Romano), which avoids
+	// the fixed block length's artifacts at block boundaries
+	BootstrapStationary BootstrapMethod = "stationary"
+)
+
+// ErrInsufficientTrades is returned by Validate when there are too few trades
+// for the simulation to say anything about robustness
+var ErrInsufficientTrades = errors.New("insufficient trades for Monte Carlo validation")
+
 // DefaultSimulatorConfig returns sensible defaults
 func DefaultSimulatorConfig() *SimulatorConfig {
 	return &SimulatorConfig{
@@ -39,8 +63,10 @@ func DefaultSimulatorConfig() *SimulatorConfig {
 		Seed:             0,
 		ConfidenceLevels: []float64{0.05, 0.25, 0.50, 0.75, 0.95},
 		ParallelWorkers:  8,
+		Bootstrap:        BootstrapIID,
 		BootstrapBlocks:  20,
 		AllowReplacement: true,
+		MinTrades:        30,
 	}
 }
 
@@ -51,8 +77,10 @@ func HighConfidenceConfig() *SimulatorConfig {
 		Seed:             0,
 		ConfidenceLevels: []float64{0.01, 0.05, 0.10, 0.25, 0.50, 0.75, 0.90, 0.95, 0.99},
 		ParallelWorkers:  16,
+		Bootstrap:        BootstrapStationary,
 		BootstrapBlocks:  50,
 		AllowReplacement: true,
+		MinTrades:        100,
 	}
 }
 
@@ -90,6 +118,7 @@ type SimulationResult struct {
 	// Distribution statistics
 	FinalEquity  *Distribution `json:"final_equity"`
 	MaxDrawdown  *Distribution `json:"max_drawdown"`
+	LosingStreak *Distribution `json:"longest_losing_streak"`
 	SharpeRatio  *Distribution `json:"sharpe_ratio"`
 	Volatility   *Distribution `json:"volatility"`
 	WinRate      *Distribution `json:"win_rate"`
@@ -135,6 +164,45 @@ type EquityCurveStats struct {
 	WinRate        float64         `json:"win_rate"`
 	ProfitFactor   float64         `json:"profit_factor"`
 	NumTrades      int             `json:"num_trades"`
+	LosingStreak   int             `json:"longest_losing_streak"` // Most consecutive losing trades
+}
+
+// Validate runs the simulation for a go/no-go decision. Unlike RunSimulation
+// it refuses to score fewer than MinTrades trades, returning
+// ErrInsufficientTrades, so a thin history can never pass as robust.
+func (s *Simulator) Validate(trades *TradeSequence, initialCapital decimal.Decimal) (*SimulationResult, error) {
+	minTrades := s.config.MinTrades
+	if minTrades < 1 {
+		minTrades = 1
+	}
+
+	if trades == nil || len(trades.Returns) < minTrades {
+		count := 0
+		if trades != nil {
+			count = len(trades.Returns)
+		}
+		return nil, fmt.Errorf("%w: have %d, need %d", ErrInsufficientTrades, count, minTrades)
+	}
+
+	return s.RunSimulation(trades, initialCapital), nil
+}
+
+// ReturnsFromPnLs converts per-trade PnLs into the fractional returns the
+// simulator compounds, each measured against the realized equity before the
+// trade so compounding them reproduces the total PnL. Trades after equity
+// reaches zero have no defined return and are dropped.
+func ReturnsFromPnLs(pnls []float64, initialCapital float64) []float64 {
+	returns := make([]float64, 0, len(pnls))
+	equity := initialCapital
+
+	for _, pnl := range pnls {
+		if equity > 0 {
+			returns = append(returns, pnl/equity)
+		}
+		equity += pnl
+	}
+
+	return returns
 }
 
 // RunSimulation performs Monte Carlo simulation on trade sequence
@@ -162,6 +230,7 @@ func (s *Simulator) RunSimulation(trades *TradeSequence, initialCapital decimal.
 	// Aggregate results
 	result.FinalEquity = s.calculateDistribution(extractFloats(simResults, "final_equity"))
 	result.MaxDrawdown = s.calculateDistribution(extractFloats(simResults, "max_drawdown"))
+	result.LosingStreak = s.calculateDistribution(extractFloats(simResults, "losing_streak"))
 	result.SharpeRatio = s.calculateDistribution(extractFloats(simResults, "sharpe"))
 	result.Volatility = s.calculateDistribution(extractFloats(simResults, "volatility"))
 	result.WinRate = s.calculateDistribution(extractFloats(simResults, "win_rate"))
@@ -171,6 +240,7 @@ func (s *Simulator) RunSimulation(trades *TradeSequence, initialCapital decimal.
 	// Calculate confidence intervals
 	result.ConfidenceIntervals["final_equity"] = s.calculateConfidenceIntervals(extractFloats(simResults, "final_equity"))
 	result.ConfidenceIntervals["max_drawdown"] = s.calculateConfidenceIntervals(extractFloats(simResults, "max_drawdown"))
+	result.ConfidenceIntervals["longest_losing_streak"] = s.calculateConfidenceIntervals(extractFloats(simResults, "losing_streak"))
 	result.ConfidenceIntervals["sharpe"] = s.calculateConfidenceIntervals(extractFloats(simResults, "sharpe"))
 
 	// Find worst and best cases
@@ -198,20 +268,31 @@ func (s *Simulator) RunSimulation(trades *TradeSequence, initialCapital decimal.
 func (s *Simulator) runParallelSimulations(trades *TradeSequence, initialCapital decimal.Decimal) []*simulationRun {
 	results := make([]*simulationRun, s.config.NumSimulations)
 
+	// Each simulation draws from its own seed, so a seeded simulator gives
+	// the same results however the runs are spread across workers
+	seeds := make([]int64, s.config.NumSimulations)
+	for i := range seeds {
+		seeds[i] = s.rng.Int63()
+	}
+
 	// Worker pool
 	numWorkers := s.config.ParallelWorkers
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
 	jobs := make(chan int, s.config.NumSimulations)
 	var wg sync.WaitGroup
 
 	// Start workers
 	for w := 0; w < numWorkers; w++ {
 		wg.Add(1)
-		go func(workerID int) {
+		go func() {
 			defer wg.Done()
 			// Each worker gets its own RNG
-			rng := rand.New(rand.NewSource(time.Now().UnixNano() + int64(workerID)))
+			rng := rand.New(rand.NewSource(1))
 
 			for simIdx := range jobs {
+				rng.Seed(seeds[simIdx])
 				shuffled := s.shuffleTrades(trades, rng)
 				stats := s.calculateEquityStats(shuffled, initialCapital)
 				results[simIdx] = &simulationRun{
@@ -219,7 +300,7 @@ func (s *Simulator) runParallelSimulations(trades *TradeSequence, initialCapital
 					stats: stats,
 				}
 			}
-		}(w)
+		}()
 	}
 
 	// Submit jobs
@@ -248,6 +329,15 @@ func (s *Simulator) shuffleTrades(trades *TradeSequence, rng *rand.Rand) []float
 
 	result := make([]float64, n)
 
+	switch s.config.Bootstrap {
+	case BootstrapBlock:
+		s.blockBootstrap(trades.Returns, result, rng)
+		return result
+	case BootstrapStationary:
+		s.stationaryBootstrap(trades.Returns, result, rng)
+		return result
+	}
+
 	if s.config.AllowReplacement {
 		// Bootstrap with replacement
 		for i := 0; i < n; i++ {
@@ -264,6 +354,54 @@ func (s *Simulator) shuffleTrades(trades *TradeSequence, rng *rand.Rand) []float
 	return result
 }
 
+// blockLength returns the configured block length, bounded by the sequence
+func (s *Simulator) blockLength(n int) int {
+	length := s.config.BootstrapBlocks
+	if length < 1 {
+		length = 1
+	}
+	if length > n {
+		length = n
+	}
+	return length
+}
+
+// blockBootstrap fills out with fixed-length blocks starting at random trades.
+// Blocks wrap around the end of the sequence (the circular block bootstrap),
+// so every trade is equally likely to be drawn.
+func (s *Simulator) blockBootstrap(returns, out []float64, rng *rand.Rand) {
+	n := len(returns)
+	length := s.blockLength(n)
+
+	for filled := 0; filled < len(out); {
+		start := rng.Intn(n)
+		for j := 0; j < length && filled < len(out); j++ {
+			out[filled] = returns[(start+j)%n]
+			filled++
+		}
+	}
+}
+
+// stationaryBootstrap fills out with blocks of geometric length: after each
+// trade a new block starts at a random trade with probability 1/BootstrapBlocks,
+// otherwise the current block continues, wrapping around the end
+func (s *Simulator) stationaryBootstrap(returns, out []float64, rng *rand.Rand) {
+	n := len(returns)
+	restart := 1 / float64(s.blockLength(n))
+
+	idx := rng.Intn(n)
+	for i := range out {
+		if i > 0 {
+			if rng.Float64() < restart {
+				idx = rng.Intn(n)
+			} else {
+				idx = (idx + 1) % n
+			}
+		}
+		out[i] = returns[idx]
+	}
+}
+
 // calculateEquityStats calculates equity curve statistics
 func (s *Simulator) calculateEquityStats(returns []float64, initialCapital decimal.Decimal) *EquityCurveStats {
 	if len(returns) == 0 {
@@ -281,6 +419,8 @@ func (s *Simulator) calculateEquityStats(returns []float64, initialCapital decim
 	losses := 0
 	grossProfit := 0.0
 	grossLoss := 0.0
+	streak := 0
+	longestStreak := 0
 
 	equityCurve := make([]float64, len(returns)+1)
 	equityCurve[0] = equity
@@ -298,6 +438,16 @@ func (s *Simulator) calculateEquityStats(returns []float64, initialCapital decim
 			grossLoss += math.Abs(ret) * equity
 		}
 
+		// Track losing streaks
+		if ret < 0 {
+			streak++
+			if streak > longestStreak {
+				longestStreak = streak
+			}
+		} else {
+			streak = 0
+		}
+
 		// Track drawdown
 		if equity > peak {
 			peak = equity
@@ -320,6 +470,7 @@ func (s *Simulator) calculateEquityStats(returns []float64, initialCapital decim
 		MaxDrawdownDur: maxDDDuration,
 		TotalReturn:    (equity - initialFloat) / initialFloat,
 		NumTrades:      len(returns),
+		LosingStreak:   longestStreak,
 	}
 
 	// Win rate
@@ -612,6 +763,8 @@ func extractFloats(runs []*simulationRun, field string) []float64 {
 			values[i], _ = run.stats.FinalEquity.Float64()
 		case "max_drawdown":
 			values[i] = run.stats.MaxDrawdown
+		case "losing_streak":
+			values[i] = float64(run.stats.LosingStreak)
 		case "sharpe":
 			values[i] = run.stats.SharpeRatio
 		case "volatility":
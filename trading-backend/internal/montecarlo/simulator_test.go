@@ -0,0 +1,139 @@
+package montecarlo_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/montecarlo"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func TestValidateRejectsTooFewTrades(t *testing.T) {
+	config := montecarlo.DefaultSimulatorConfig()
+	config.NumSimulations = 50
+	config.Seed = 1
+	config.MinTrades = 20
+	sim := montecarlo.NewSimulator(zap.NewNop(), config)
+
+	returns := make([]float64, 19)
+	for i := range returns {
+		returns[i] = 0.01
+	}
+
+	_, err := sim.Validate(&montecarlo.TradeSequence{Returns: returns}, decimal.NewFromInt(10000))
+	if !errors.Is(err, montecarlo.ErrInsufficientTrades) {
+		t.Fatalf("Validate with 19 trades: got %v, want ErrInsufficientTrades", err)
+	}
+
+	result, err := sim.Validate(&montecarlo.TradeSequence{Returns: append(returns, -0.01)}, decimal.NewFromInt(10000))
+	if err != nil {
+		t.Fatalf("Validate with 20 trades: %v", err)
+	}
+	if result.NumSimulations != 50 {
+		t.Errorf("NumSimulations = %d, want 50", result.NumSimulations)
+	}
+}
+
+func TestReturnsFromPnLsCompoundToTotal(t *testing.T) {
+	pnls := []float64{100, -50, 200, -25}
+	returns := montecarlo.ReturnsFromPnLs(pnls, 1000)
+
+	equity := 1000.0
+	for _, r := range returns {
+		equity *= 1 + r
+	}
+	if math.Abs(equity-1225) > 1e-9 {
+		t.Errorf("compounded equity = %f, want 1225", equity)
+	}
+}
+
+func TestTradeHistoryAppendsLiveTradesAndCaps(t *testing.T) {
+	history := montecarlo.NewTradeHistory(4)
+
+	history.SetBacktest("trend", []float64{1, 2, 3})
+	history.Record("trend", 4)
+	history.Record("trend", 5)
+	history.Record("other", 9)
+
+	got := history.PnLs("trend")
+	want := []float64{2, 3, 4, 5}
+	if len(got) != len(want) {
+		t.Fatalf("PnLs = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("PnLs = %v, want %v", got, want)
+		}
+	}
+
+	// A new backtest replaces the old one but keeps live trades
+	history.SetBacktest("trend", []float64{7})
+	if got := history.PnLs("trend"); len(got) != 3 || got[0] != 7 {
+		t.Errorf("PnLs after new backtest = %v, want [7 4 5]", got)
+	}
+
+	history.Clear("trend")
+	if got := history.PnLs("trend"); len(got) != 0 {
+		t.Errorf("PnLs after Clear = %v, want empty", got)
+	}
+}
+
+// streakyReturns alternates runs of ten winning and ten losing trades
+func streakyReturns() []float64 {
+	returns := make([]float64, 0, 200)
+	for cycle := 0; cycle < 10; cycle++ {
+		for i := 0; i < 10; i++ {
+			returns = append(returns, 0.015)
+		}
+		for i := 0; i < 10; i++ {
+			returns = append(returns, -0.014)
+		}
+	}
+	return returns
+}
+
+func simulate(t *testing.T, method montecarlo.BootstrapMethod) *montecarlo.SimulationResult {
+	t.Helper()
+
+	config := montecarlo.DefaultSimulatorConfig()
+	config.NumSimulations = 500
+	config.Seed = 7
+	config.Bootstrap = method
+	config.BootstrapBlocks = 10
+	sim := montecarlo.NewSimulator(zap.NewNop(), config)
+
+	return sim.RunSimulation(&montecarlo.TradeSequence{Returns: streakyReturns()}, decimal.NewFromInt(10000))
+}
+
+func TestBlockBootstrapWidensDrawdownTails(t *testing.T) {
+	iid := simulate(t, montecarlo.BootstrapIID)
+
+	for _, method := range []montecarlo.BootstrapMethod{montecarlo.BootstrapBlock, montecarlo.BootstrapStationary} {
+		blocked := simulate(t, method)
+
+		if blocked.MaxDrawdown.Percentiles[0.95] <= iid.MaxDrawdown.Percentiles[0.95] {
+			t.Errorf("%s: 95th percentile drawdown %.3f not wider than i.i.d. %.3f",
+				method, blocked.MaxDrawdown.Percentiles[0.95], iid.MaxDrawdown.Percentiles[0.95])
+		}
+		if blocked.LosingStreak.Median <= iid.LosingStreak.Median {
+			t.Errorf("%s: median longest losing streak %.0f not longer than i.i.d. %.0f",
+				method, blocked.LosingStreak.Median, iid.LosingStreak.Median)
+		}
+	}
+
+	// The original sequence's streaks are ten trades long
+	if iid.OriginalEquity.LosingStreak != 10 {
+		t.Errorf("original longest losing streak = %d, want 10", iid.OriginalEquity.LosingStreak)
+	}
+}
+
+func TestSeededSimulationIsReproducible(t *testing.T) {
+	first := simulate(t, montecarlo.BootstrapStationary)
+	second := simulate(t, montecarlo.BootstrapStationary)
+
+	if first.MaxDrawdown.Mean != second.MaxDrawdown.Mean || first.FinalEquity.Mean != second.FinalEquity.Mean {
+		t.Error("simulations with the same seed differ")
+	}
+}
@@ -0,0 +1,218 @@
+package optimization
+
+import (
+	"context"
+	"math"
+
+	"go.uber.org/zap"
+)
+
+// CoolingSchedule is how simulated annealing lowers its temperature.
+type CoolingSchedule string
+
+const (
+	// CoolingExponential multiplies the temperature by CoolingRate each step
+	CoolingExponential CoolingSchedule = "exponential"
+	// CoolingLinear lowers the temperature evenly to zero over the budget
+	CoolingLinear CoolingSchedule = "linear"
+	// CoolingLogarithmic divides the initial temperature by 1+ln(1+step),
+	// cooling slowly enough to keep escaping deep local optima
+	CoolingLogarithmic CoolingSchedule = "logarithmic"
+)
+
+const (
+	minStepFraction = 0.05 // Smallest move size, as a fraction of StepSize
+	maxCalibration  = 50   // Random moves used to calibrate the temperature
+)
+
+// temperature returns the temperature after step of steps.
+func (s CoolingSchedule) temperature(initial, rate float64, step, steps int) float64 {
+	switch s {
+	case CoolingLinear:
+		return initial * math.Max(0, 1-float64(step)/float64(steps))
+	case CoolingLogarithmic:
+		return initial / (1 + math.Log1p(float64(step)))
+	default:
+		return initial * math.Pow(rate, float64(step))
+	}
+}
+
+// simulatedAnnealing walks the unit cube from a random point. Each step
+// proposes ParallelWorkers Gaussian moves around the current point, evaluated
+// concurrently, and moves to the best of them if it scores better, or
+// otherwise with the Metropolis probability exp(-worsening/temperature).
+// Moves shrink with the temperature, so the search turns local as it cools.
+func (o *Optimizer) simulatedAnnealing(ctx context.Context, params []Parameter, objective ObjectiveFunc) (*OptimizationResult, error) {
+	result := &OptimizationResult{
+		AllResults:      make([]EvaluationResult, 0),
+		ConvergenceHist: make([]float64, 0),
+	}
+
+	workers := o.config.ParallelWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	budget := o.config.MaxIterations
+
+	o.logger.Info("starting simulated annealing",
+		zap.Int("max_iterations", budget),
+		zap.String("cooling", string(o.config.CoolingSchedule)),
+		zap.Int("batch_size", workers),
+	)
+
+	bestScore := math.Inf(-1)
+	if o.config.MinimizationMode {
+		bestScore = math.Inf(1)
+	}
+	evaluated := 0
+
+	// gain orients scores so higher is always better
+	gain := func(score float64) float64 {
+		if o.config.MinimizationMode {
+			return -score
+		}
+		return score
+	}
+
+	record := func(evals []bayesEval) {
+		for _, ev := range evals {
+			evaluated++
+			if ev.err != nil {
+				continue
+			}
+
+			result.AllResults = append(result.AllResults, EvaluationResult{
+				Params:    ev.params,
+				Score:     ev.score,
+				Iteration: evaluated - 1,
+				Duration:  ev.duration,
+			})
+
+			if gain(ev.score) > gain(bestScore) {
+				bestScore = ev.score
+				result.BestParams = ev.params
+				result.BestScore = ev.score
+			}
+
+			result.ConvergenceHist = append(result.ConvergenceHist, bestScore)
+			result.Iterations++
+		}
+	}
+
+	// Start from a warm-start seed when there is one, else at random, and
+	// keep drawing until a start point evaluates
+	var current []float64
+	currentGain := math.Inf(-1)
+	for evaluated < budget && math.IsInf(currentGain, -1) {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		default:
+		}
+
+		start := o.randomUnitPoint(len(params))
+		if evaluated < len(o.warmStart) {
+			start = encodeParams(params, o.fitParams(params, o.warmStart[evaluated]))
+		}
+
+		evals := o.evaluateBatch([]ParamSet{decodeParams(params, start)}, objective)
+		record(evals)
+		if evals[0].err == nil {
+			current = start
+			currentGain = gain(evals[0].score)
+		}
+	}
+	if current == nil {
+		return result, nil
+	}
+
+	// Without a configured temperature, pick the one at which the average
+	// worsening of random moves from the start is accepted half the time;
+	// the calibration moves count against the budget
+	initialTemp := o.config.InitialTemperature
+	if initialTemp <= 0 {
+		samples := budget / 10
+		if samples > maxCalibration {
+			samples = maxCalibration
+		}
+		if remaining := budget - evaluated; samples > remaining {
+			samples = remaining
+		}
+
+		batch := make([]ParamSet, samples)
+		for i := range batch {
+			batch[i] = decodeParams(params, o.neighbour(current, o.config.StepSize))
+		}
+		evals := o.evaluateInBatches(batch, objective, workers)
+		record(evals)
+
+		var worsening float64
+		var count int
+		for _, ev := range evals {
+			if delta := currentGain - gain(ev.score); ev.err == nil && delta > 0 {
+				worsening += delta
+				count++
+			}
+		}
+
+		initialTemp = 1
+		if count > 0 {
+			initialTemp = worsening / float64(count) / math.Ln2
+		}
+	}
+
+	steps := (budget - evaluated + workers - 1) / workers
+	for step := 0; evaluated < budget; step++ {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		default:
+		}
+
+		temp := o.config.CoolingSchedule.temperature(initialTemp, o.config.CoolingRate, step, steps)
+		scale := o.config.StepSize * math.Max(minStepFraction, math.Sqrt(temp/initialTemp))
+
+		size := workers
+		if remaining := budget - evaluated; size > remaining {
+			size = remaining
+		}
+
+		moves := make([][]float64, size)
+		batch := make([]ParamSet, size)
+		for i := range moves {
+			moves[i] = o.neighbour(current, scale)
+			batch[i] = decodeParams(params, moves[i])
+		}
+
+		evals := o.evaluateBatch(batch, objective)
+		record(evals)
+
+		pick := -1
+		for i, ev := range evals {
+			if ev.err == nil && (pick < 0 || gain(ev.score) > gain(evals[pick].score)) {
+				pick = i
+			}
+		}
+		if pick < 0 {
+			continue
+		}
+
+		delta := gain(evals[pick].score) - currentGain
+		if delta >= 0 || (temp > 0 && o.rng.Float64() < math.Exp(delta/temp)) {
+			current = moves[pick]
+			currentGain = gain(evals[pick].score)
+		}
+	}
+
+	return result, nil
+}
+
+// neighbour returns a Gaussian move from u with standard deviation scale in
+// unit space, clamped to the unit cube.
+func (o *Optimizer) neighbour(u []float64, scale float64) []float64 {
+	moved := make([]float64, len(u))
+	for d := range u {
+		moved[d] = clampUnit(u[d] + o.rng.NormFloat64()*scale)
+	}
+	return moved
+}
@@ -0,0 +1,132 @@
+package optimization_test
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/optimization"
+	"go.uber.org/zap"
+)
+
+// rastrigin has its global minimum of 0 at the origin, surrounded by a local
+// minimum near every integer point. Every local minimum scores at least
+// 0.995, so a best score below rastriginBasin means the global basin was
+// found.
+func rastrigin(p optimization.ParamSet) (float64, error) {
+	sum := 20.0
+	for _, x := range []float64{p["x"], p["y"]} {
+		sum += x*x - 10*math.Cos(2*math.Pi*x)
+	}
+	return sum, nil
+}
+
+var rastriginParams = []optimization.Parameter{
+	{Name: "x", Type: optimization.ParamTypeContinuous, Min: -5.12, Max: 5.12},
+	{Name: "y", Type: optimization.ParamTypeContinuous, Min: -5.12, Max: 5.12},
+}
+
+const rastriginBasin = 0.9
+
+// rastriginBudget matches a 44x44 grid, which steps over the origin's basin
+// and lands on the slopes of neighbouring minima.
+const rastriginBudget = 44 * 44
+
+func rastriginConfig(method optimization.OptimizationMethod) *optimization.OptimizerConfig {
+	config := optimization.DefaultOptimizerConfig()
+	config.Method = method
+	config.MaxIterations = rastriginBudget
+	config.GridResolution = 43
+	config.MinimizationMode = true
+	config.ParallelWorkers = 4
+	config.RandomSeed = 7
+	return config
+}
+
+func optimizeRastrigin(t *testing.T, config *optimization.OptimizerConfig) *optimization.OptimizationResult {
+	t.Helper()
+	result, err := optimization.NewOptimizer(zap.NewNop(), config).
+		Optimize(context.Background(), rastriginParams, rastrigin)
+	if err != nil {
+		t.Fatalf("Optimize %s: %v", config.Method, err)
+	}
+	return result
+}
+
+// checkRun verifies the bookkeeping every budgeted method shares.
+func checkRun(t *testing.T, result *optimization.OptimizationResult, budget int) {
+	t.Helper()
+	if result.Iterations != budget || len(result.AllResults) != budget || len(result.ConvergenceHist) != budget {
+		t.Errorf("Iterations = %d, AllResults = %d, ConvergenceHist = %d, want %d",
+			result.Iterations, len(result.AllResults), len(result.ConvergenceHist), budget)
+	}
+	for i := 1; i < len(result.ConvergenceHist); i++ {
+		if result.ConvergenceHist[i] > result.ConvergenceHist[i-1] {
+			t.Fatalf("convergence history increased at %d while minimizing", i)
+		}
+	}
+}
+
+func TestSimulatedAnnealingEscapesRastriginLocalMinima(t *testing.T) {
+	grid := optimizeRastrigin(t, rastriginConfig(optimization.MethodGridSearch))
+	if grid.BestScore < 1 {
+		t.Fatalf("grid search found %.4f; the test needs a grid that misses the global basin", grid.BestScore)
+	}
+
+	for _, cooling := range []optimization.CoolingSchedule{
+		optimization.CoolingExponential,
+		optimization.CoolingLinear,
+		optimization.CoolingLogarithmic,
+	} {
+		t.Run(string(cooling), func(t *testing.T) {
+			config := rastriginConfig(optimization.MethodAnnealing)
+			config.CoolingSchedule = cooling
+			result := optimizeRastrigin(t, config)
+
+			checkRun(t, result, rastriginBudget)
+			if result.Method != optimization.MethodAnnealing {
+				t.Errorf("Method = %s, want annealing", result.Method)
+			}
+			if result.BestScore > rastriginBasin {
+				t.Errorf("BestScore = %.4f with %s cooling, want the global minimum (grid search: %.4f)",
+					result.BestScore, cooling, grid.BestScore)
+			}
+		})
+	}
+}
+
+func TestSimulatedAnnealingMaximizesAndRespectsWorkers(t *testing.T) {
+	var running, peak int32
+	negated := func(p optimization.ParamSet) (float64, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+				break
+			}
+		}
+		score, err := rastrigin(p)
+		return -score, err
+	}
+
+	config := rastriginConfig(optimization.MethodAnnealing)
+	config.ParallelWorkers = 3
+	minimized := optimizeRastrigin(t, config)
+
+	// The same seed walks the same path when the scores are mirrored
+	config.MinimizationMode = false
+	maximized, err := optimization.NewOptimizer(zap.NewNop(), config).
+		Optimize(context.Background(), rastriginParams, negated)
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+
+	if maximized.BestScore != -minimized.BestScore {
+		t.Errorf("maximizing the negation found %.4f, minimizing found %.4f", maximized.BestScore, minimized.BestScore)
+	}
+	if peak > 3 {
+		t.Errorf("%d concurrent evaluations, want at most 3", peak)
+	}
+}
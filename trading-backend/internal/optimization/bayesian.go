@@ -0,0 +1,570 @@
+package optimization
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Gaussian process settings. Inputs are scaled to the unit cube and scores
+// standardized, so one grid of length scales suits every parameter space.
+var gpLengthScales = []float64{0.05, 0.1, 0.2, 0.35, 0.5, 1.0}
+
+const (
+	gpNoise         = 1e-6 // Observation noise added to the kernel diagonal
+	localCandidates = 20   // Perturbations sampled around each top point
+	localTopPoints  = 5    // Best observations used as local search seeds
+	localSpread     = 0.05 // Std dev of local perturbations in unit space
+)
+
+// bayesEval is the outcome of one objective call.
+type bayesEval struct {
+	params   ParamSet
+	score    float64
+	err      error
+	duration time.Duration
+}
+
+// bayesianOptimization fits a Gaussian process to the scores seen so far and
+// evaluates the points with the highest expected improvement. Each round
+// picks a batch of up to ParallelWorkers points, treating earlier picks in
+// the batch as if they scored the surrogate's mean (the "kriging believer"
+// heuristic) so the batch spreads out.
+func (o *Optimizer) bayesianOptimization(ctx context.Context, params []Parameter, objective ObjectiveFunc) (*OptimizationResult, error) {
+	result := &OptimizationResult{
+		AllResults:      make([]EvaluationResult, 0),
+		ConvergenceHist: make([]float64, 0),
+	}
+
+	workers := o.config.ParallelWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	budget := o.config.MaxIterations
+
+	o.logger.Info("starting bayesian optimization",
+		zap.Int("max_iterations", budget),
+		zap.Int("initial_samples", o.config.InitialSamples),
+		zap.Int("batch_size", workers),
+	)
+
+	bestScore := math.Inf(-1)
+	if o.config.MinimizationMode {
+		bestScore = math.Inf(1)
+	}
+
+	// Successful evaluations in unit space; ys is negated when minimizing so
+	// the surrogate always maximizes
+	var xs [][]float64
+	var ys []float64
+	evaluated := 0
+
+	record := func(evals []bayesEval) {
+		for _, ev := range evals {
+			evaluated++
+			if ev.err != nil {
+				continue
+			}
+
+			result.AllResults = append(result.AllResults, EvaluationResult{
+				Params:    ev.params,
+				Score:     ev.score,
+				Iteration: evaluated - 1,
+				Duration:  ev.duration,
+			})
+
+			isBetter := ev.score > bestScore
+			if o.config.MinimizationMode {
+				isBetter = ev.score < bestScore
+			}
+
+			if isBetter {
+				bestScore = ev.score
+				result.BestParams = ev.params
+				result.BestScore = ev.score
+			}
+
+			result.ConvergenceHist = append(result.ConvergenceHist, bestScore)
+			result.Iterations++
+
+			y := ev.score
+			if o.config.MinimizationMode {
+				y = -y
+			}
+			xs = append(xs, encodeParams(params, ev.params))
+			ys = append(ys, y)
+		}
+	}
+
+	// Space-filling initial design
+	initial := o.config.InitialSamples
+	if initial < 2 {
+		initial = 2
+	}
+	if initial > budget {
+		initial = budget
+	}
+
+	design := o.latinHypercube(len(params), initial)
+	for start := 0; start < len(design); start += workers {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		default:
+		}
+
+		end := start + workers
+		if end > len(design) {
+			end = len(design)
+		}
+
+		batch := make([]ParamSet, 0, end-start)
+		for _, u := range design[start:end] {
+			batch = append(batch, decodeParams(params, u))
+		}
+		record(o.evaluateBatch(batch, objective))
+	}
+
+	for evaluated < budget {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		default:
+		}
+
+		size := workers
+		if remaining := budget - evaluated; size > remaining {
+			size = remaining
+		}
+
+		batch := o.selectBatch(params, xs, ys, size)
+		record(o.evaluateBatch(batch, objective))
+	}
+
+	return result, nil
+}
+
+// selectBatch picks the next points to evaluate. With fewer than two
+// observations there is nothing to model, so points are drawn at random.
+func (o *Optimizer) selectBatch(params []Parameter, xs [][]float64, ys []float64, size int) []ParamSet {
+	batch := make([]ParamSet, 0, size)
+
+	if len(xs) < 2 {
+		for i := 0; i < size; i++ {
+			batch = append(batch, decodeParams(params, o.randomUnitPoint(len(params))))
+		}
+		return batch
+	}
+
+	gp, err := fitGaussianProcess(xs, ys)
+	if err != nil {
+		o.logger.Warn("gaussian process fit failed, sampling randomly", zap.Error(err))
+		for i := 0; i < size; i++ {
+			batch = append(batch, decodeParams(params, o.randomUnitPoint(len(params))))
+		}
+		return batch
+	}
+
+	// Copies so believer points don't leak into the caller's observations
+	xs = append([][]float64(nil), xs...)
+	ys = append([]float64(nil), ys...)
+
+	for i := 0; i < size; i++ {
+		u := o.maximizeExpectedImprovement(params, gp, xs, ys)
+		batch = append(batch, decodeParams(params, u))
+
+		if i == size-1 {
+			break
+		}
+
+		// Kriging believer: pretend the pick scored the predicted mean
+		mean, _ := gp.predict(u)
+		xs = append(xs, u)
+		ys = append(ys, mean)
+		if next, err := newGaussianProcess(xs, ys, gp.lengthScale); err == nil {
+			gp = next
+		}
+	}
+
+	return batch
+}
+
+// maximizeExpectedImprovement scores random and local candidates and
+// returns the one with the highest expected improvement.
+func (o *Optimizer) maximizeExpectedImprovement(params []Parameter, gp *gaussianProcess, xs [][]float64, ys []float64) []float64 {
+	best := math.Inf(-1)
+	for _, y := range ys {
+		best = math.Max(best, y)
+	}
+
+	candidates := make([][]float64, 0, o.config.AcquisitionSamples+localTopPoints*localCandidates)
+	for i := 0; i < o.config.AcquisitionSamples; i++ {
+		candidates = append(candidates, o.randomUnitPoint(len(params)))
+	}
+
+	// Refine around the best observations, where the optimum usually is
+	order := make([]int, len(ys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return ys[order[i]] > ys[order[j]] })
+
+	for k := 0; k < localTopPoints && k < len(order); k++ {
+		center := xs[order[k]]
+		for i := 0; i < localCandidates; i++ {
+			u := make([]float64, len(center))
+			for d := range center {
+				u[d] = clampUnit(center[d] + o.rng.NormFloat64()*localSpread)
+			}
+			candidates = append(candidates, u)
+		}
+	}
+
+	var bestPoint []float64
+	bestEI := 0.0
+	for _, u := range candidates {
+		// Snap to the values the objective will actually see
+		u = encodeParams(params, decodeParams(params, u))
+		mean, std := gp.predict(u)
+		ei := expectedImprovement(gp.standardize(mean), std/gp.yStd, gp.standardize(best), o.config.ExplorationXi)
+		if ei > bestEI {
+			bestEI = ei
+			bestPoint = u
+		}
+	}
+
+	// Every candidate was already evaluated (small discrete spaces)
+	if bestPoint == nil {
+		return o.randomUnitPoint(len(params))
+	}
+	return bestPoint
+}
+
+// evaluateBatch runs the objective on each parameter set concurrently.
+func (o *Optimizer) evaluateBatch(batch []ParamSet, objective ObjectiveFunc) []bayesEval {
+	evals := make([]bayesEval, len(batch))
+	var wg sync.WaitGroup
+
+	for i, paramSet := range batch {
+		wg.Add(1)
+		go func(idx int, params ParamSet) {
+			defer wg.Done()
+
+			start := time.Now()
+			score, err := objective(params)
+			evals[idx] = bayesEval{
+				params:   params,
+				score:    score,
+				err:      err,
+				duration: time.Since(start),
+			}
+		}(i, paramSet)
+	}
+
+	wg.Wait()
+	return evals
+}
+
+// evaluateInBatches runs the objective on each parameter set, at most
+// workers at a time.
+func (o *Optimizer) evaluateInBatches(sets []ParamSet, objective ObjectiveFunc, workers int) []bayesEval {
+	evals := make([]bayesEval, 0, len(sets))
+	for start := 0; start < len(sets); start += workers {
+		end := start + workers
+		if end > len(sets) {
+			end = len(sets)
+		}
+		evals = append(evals, o.evaluateBatch(sets[start:end], objective)...)
+	}
+	return evals
+}
+
+// latinHypercube returns n points in the unit cube with one point in each
+// of n equal strata along every dimension.
+func (o *Optimizer) latinHypercube(dims, n int) [][]float64 {
+	points := make([][]float64, n)
+	for i := range points {
+		points[i] = make([]float64, dims)
+	}
+
+	for d := 0; d < dims; d++ {
+		perm := o.rng.Perm(n)
+		for i := 0; i < n; i++ {
+			points[i][d] = (float64(perm[i]) + o.rng.Float64()) / float64(n)
+		}
+	}
+
+	return points
+}
+
+// randomUnitPoint returns a uniform point in the unit cube.
+func (o *Optimizer) randomUnitPoint(dims int) []float64 {
+	u := make([]float64, dims)
+	for d := range u {
+		u[d] = o.rng.Float64()
+	}
+	return u
+}
+
+// encodeParams maps a parameter set into the unit cube.
+func encodeParams(params []Parameter, values ParamSet) []float64 {
+	u := make([]float64, len(params))
+
+	for i, param := range params {
+		v := values[param.Name]
+
+		switch {
+		case param.Type == ParamTypeDiscrete && len(param.Discrete) > 1:
+			idx := 0
+			for j, choice := range param.Discrete {
+				if math.Abs(choice-v) < math.Abs(param.Discrete[idx]-v) {
+					idx = j
+				}
+			}
+			u[i] = float64(idx) / float64(len(param.Discrete)-1)
+		case param.Type == ParamTypeDiscrete:
+			u[i] = 0
+		case param.Max > param.Min:
+			u[i] = clampUnit((v - param.Min) / (param.Max - param.Min))
+		}
+	}
+
+	return u
+}
+
+// decodeParams maps a unit-cube point back to parameter values, rounding
+// integers and snapping discrete parameters to their nearest choice.
+func decodeParams(params []Parameter, u []float64) ParamSet {
+	values := make(ParamSet, len(params))
+
+	for i, param := range params {
+		switch param.Type {
+		case ParamTypeDiscrete:
+			if len(param.Discrete) == 0 {
+				values[param.Name] = param.Default
+				continue
+			}
+			idx := int(math.Round(u[i] * float64(len(param.Discrete)-1)))
+			values[param.Name] = param.Discrete[idx]
+		case ParamTypeInteger:
+			v := math.Round(param.Min + u[i]*(param.Max-param.Min))
+			values[param.Name] = math.Max(param.Min, math.Min(param.Max, v))
+		default:
+			values[param.Name] = param.Min + u[i]*(param.Max-param.Min)
+		}
+	}
+
+	return values
+}
+
+func clampUnit(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// expectedImprovement is EI for maximization with standardized inputs.
+func expectedImprovement(mean, std, best, xi float64) float64 {
+	if std <= 0 {
+		return 0
+	}
+
+	improvement := mean - best - xi
+	z := improvement / std
+	return improvement*normalCDF(z) + std*normalPDF(z)
+}
+
+func normalCDF(z float64) float64 {
+	return 0.5 * (1 + math.Erf(z/math.Sqrt2))
+}
+
+func normalPDF(z float64) float64 {
+	return math.Exp(-0.5*z*z) / math.Sqrt(2*math.Pi)
+}
+
+// gaussianProcess is a zero-mean GP with a squared exponential kernel over
+// standardized observations.
+type gaussianProcess struct {
+	xs          [][]float64
+	chol        [][]float64 // Lower Cholesky factor of K + noise*I
+	alpha       []float64   // (K + noise*I)^-1 * y
+	lengthScale float64
+	yMean       float64
+	yStd        float64
+	logLik      float64 // Log marginal likelihood, used to pick the length scale
+}
+
+// fitGaussianProcess fits a GP, choosing the length scale with the highest
+// marginal likelihood.
+func fitGaussianProcess(xs [][]float64, ys []float64) (*gaussianProcess, error) {
+	var best *gaussianProcess
+
+	for _, ls := range gpLengthScales {
+		gp, err := newGaussianProcess(xs, ys, ls)
+		if err != nil {
+			continue
+		}
+		if best == nil || gp.logLik > best.logLik {
+			best = gp
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("kernel matrix not positive definite for any length scale")
+	}
+	return best, nil
+}
+
+// newGaussianProcess fits a GP with a fixed length scale.
+func newGaussianProcess(xs [][]float64, ys []float64, lengthScale float64) (*gaussianProcess, error) {
+	n := len(xs)
+
+	mean := 0.0
+	for _, y := range ys {
+		mean += y
+	}
+	mean /= float64(n)
+
+	variance := 0.0
+	for _, y := range ys {
+		variance += (y - mean) * (y - mean)
+	}
+	std := math.Sqrt(variance / float64(n))
+	if std == 0 {
+		std = 1
+	}
+
+	gp := &gaussianProcess{
+		xs:          xs,
+		lengthScale: lengthScale,
+		yMean:       mean,
+		yStd:        std,
+	}
+
+	k := make([][]float64, n)
+	for i := range k {
+		k[i] = make([]float64, n)
+		for j := 0; j <= i; j++ {
+			k[i][j] = gp.kernel(xs[i], xs[j])
+			k[j][i] = k[i][j]
+		}
+		k[i][i] += gpNoise
+	}
+
+	chol, err := cholesky(k)
+	if err != nil {
+		return nil, err
+	}
+	gp.chol = chol
+
+	y := make([]float64, n)
+	for i := range ys {
+		y[i] = gp.standardize(ys[i])
+	}
+	gp.alpha = backSubstitute(chol, forwardSubstitute(chol, y))
+
+	// log p(y) = -1/2 y'alpha - sum(log L_ii) - n/2 log(2pi)
+	gp.logLik = -float64(n) / 2 * math.Log(2*math.Pi)
+	for i := 0; i < n; i++ {
+		gp.logLik -= 0.5*y[i]*gp.alpha[i] + math.Log(chol[i][i])
+	}
+
+	return gp, nil
+}
+
+func (gp *gaussianProcess) kernel(a, b []float64) float64 {
+	dist := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		dist += d * d
+	}
+	return math.Exp(-dist / (2 * gp.lengthScale * gp.lengthScale))
+}
+
+func (gp *gaussianProcess) standardize(y float64) float64 {
+	return (y - gp.yMean) / gp.yStd
+}
+
+// predict returns the posterior mean and standard deviation at u in the
+// original score units.
+func (gp *gaussianProcess) predict(u []float64) (float64, float64) {
+	kStar := make([]float64, len(gp.xs))
+	for i, x := range gp.xs {
+		kStar[i] = gp.kernel(u, x)
+	}
+
+	mean := 0.0
+	for i := range kStar {
+		mean += kStar[i] * gp.alpha[i]
+	}
+
+	v := forwardSubstitute(gp.chol, kStar)
+	variance := 1.0
+	for _, vi := range v {
+		variance -= vi * vi
+	}
+	if variance < 1e-12 {
+		variance = 0
+	}
+
+	return mean*gp.yStd + gp.yMean, math.Sqrt(variance) * gp.yStd
+}
+
+// cholesky returns the lower triangular L with L*L' = a.
+func cholesky(a [][]float64) ([][]float64, error) {
+	n := len(a)
+	l := make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, n)
+	}
+
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			sum := a[i][j]
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+
+			if i == j {
+				if sum <= 0 {
+					return nil, fmt.Errorf("matrix not positive definite at row %d", i)
+				}
+				l[i][i] = math.Sqrt(sum)
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+
+	return l, nil
+}
+
+// forwardSubstitute solves L*x = b for lower triangular L.
+func forwardSubstitute(l [][]float64, b []float64) []float64 {
+	x := make([]float64, len(b))
+	for i := range b {
+		sum := b[i]
+		for k := 0; k < i; k++ {
+			sum -= l[i][k] * x[k]
+		}
+		x[i] = sum / l[i][i]
+	}
+	return x
+}
+
+// backSubstitute solves L'*x = b for lower triangular L.
+func backSubstitute(l [][]float64, b []float64) []float64 {
+	n := len(b)
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := b[i]
+		for k := i + 1; k < n; k++ {
+			sum -= l[k][i] * x[k]
+		}
+		x[i] = sum / l[i][i]
+	}
+	return x
+}
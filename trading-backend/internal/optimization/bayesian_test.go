@@ -0,0 +1,125 @@
+package optimization_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/optimization"
+	"go.uber.org/zap"
+)
+
+// branin has three global minima of 0.397887 on x1 in [-5, 10], x2 in [0, 15].
+func branin(p optimization.ParamSet) (float64, error) {
+	x1, x2 := p["x1"], p["x2"]
+	a, b, c := 1.0, 5.1/(4*math.Pi*math.Pi), 5/math.Pi
+	r, s, t := 6.0, 10.0, 1/(8*math.Pi)
+	return a*math.Pow(x2-b*x1*x1+c*x1-r, 2) + s*(1-t)*math.Cos(x1) + s, nil
+}
+
+var braninParams = []optimization.Parameter{
+	{Name: "x1", Type: optimization.ParamTypeContinuous, Min: -5, Max: 10},
+	{Name: "x2", Type: optimization.ParamTypeContinuous, Min: 0, Max: 15},
+}
+
+func braninConfig(method optimization.OptimizationMethod, workers int) *optimization.OptimizerConfig {
+	config := optimization.DefaultOptimizerConfig()
+	config.Method = method
+	config.MaxIterations = 40
+	config.MinimizationMode = true
+	config.ParallelWorkers = workers
+	config.RandomSeed = 42
+	return config
+}
+
+func TestBayesianOptimizationBranin(t *testing.T) {
+	opt := optimization.NewOptimizer(zap.NewNop(), braninConfig(optimization.MethodBayesian, 1))
+
+	result, err := opt.Optimize(context.Background(), braninParams, branin)
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+
+	if result.Method != optimization.MethodBayesian {
+		t.Errorf("Method = %s, want bayesian", result.Method)
+	}
+	if result.Iterations != 40 || len(result.AllResults) != 40 {
+		t.Errorf("Iterations = %d, AllResults = %d, want 40", result.Iterations, len(result.AllResults))
+	}
+	if result.BestScore > 0.5 {
+		t.Errorf("BestScore = %.4f, want within 0.1 of the 0.398 minimum", result.BestScore)
+	}
+
+	for i := 1; i < len(result.ConvergenceHist); i++ {
+		if result.ConvergenceHist[i] > result.ConvergenceHist[i-1] {
+			t.Fatalf("convergence history increased at %d while minimizing", i)
+		}
+	}
+}
+
+func TestBayesianBeatsRandomSearchOnBranin(t *testing.T) {
+	bayes, err := optimization.NewOptimizer(zap.NewNop(), braninConfig(optimization.MethodBayesian, 1)).
+		Optimize(context.Background(), braninParams, branin)
+	if err != nil {
+		t.Fatalf("bayesian: %v", err)
+	}
+
+	random, err := optimization.NewOptimizer(zap.NewNop(), braninConfig(optimization.MethodRandomSearch, 1)).
+		Optimize(context.Background(), braninParams, branin)
+	if err != nil {
+		t.Fatalf("random: %v", err)
+	}
+
+	if bayes.BestScore >= random.BestScore {
+		t.Errorf("bayesian best %.4f not better than random search %.4f at equal budget", bayes.BestScore, random.BestScore)
+	}
+}
+
+func TestBayesianOptimizationBatchRespectsBudget(t *testing.T) {
+	opt := optimization.NewOptimizer(zap.NewNop(), braninConfig(optimization.MethodBayesian, 4))
+
+	result, err := opt.Optimize(context.Background(), braninParams, branin)
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+
+	if result.Iterations != 40 {
+		t.Errorf("Iterations = %d, want 40", result.Iterations)
+	}
+	if result.BestScore > 1.0 {
+		t.Errorf("BestScore = %.4f, want near the 0.398 minimum", result.BestScore)
+	}
+}
+
+func TestBayesianOptimizationMixedParams(t *testing.T) {
+	config := optimization.DefaultOptimizerConfig()
+	config.Method = optimization.MethodBayesian
+	config.MaxIterations = 30
+	config.ParallelWorkers = 2
+	config.RandomSeed = 7
+
+	params := []optimization.Parameter{
+		{Name: "period", Type: optimization.ParamTypeInteger, Min: 5, Max: 50},
+		{Name: "mult", Type: optimization.ParamTypeDiscrete, Discrete: []float64{1, 1.5, 2, 2.5, 3}},
+	}
+
+	// Peaks at period 21, mult 2
+	objective := func(p optimization.ParamSet) (float64, error) {
+		return -math.Pow(p["period"]-21, 2) - 10*math.Pow(p["mult"]-2, 2), nil
+	}
+
+	result, err := optimization.NewOptimizer(zap.NewNop(), config).Optimize(context.Background(), params, objective)
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+
+	for _, res := range result.AllResults {
+		if res.Params["period"] != math.Round(res.Params["period"]) {
+			t.Fatalf("non-integer period evaluated: %v", res.Params["period"])
+		}
+	}
+
+	if math.Abs(result.BestParams["period"]-21) > 2 || result.BestParams["mult"] != 2 {
+		t.Errorf("BestParams = %v, want period near 21 and mult 2", result.BestParams)
+	}
+}
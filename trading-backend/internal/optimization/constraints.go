@@ -0,0 +1,93 @@
+package optimization
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Constraint reports whether a parameter set is valid. Parameter sets that
+// fail any constraint are never passed to the objective.
+type Constraint func(params ParamSet) bool
+
+// LessThan returns a constraint requiring parameter a to be strictly less
+// than parameter b, such as a fast moving average period and a slow one.
+func LessThan(a, b string) Constraint {
+	return func(params ParamSet) bool {
+		return params[a] < params[b]
+	}
+}
+
+// maxConstraintAttempts bounds how many candidates are drawn or bred in a row
+// while looking for one that satisfies the constraints
+const maxConstraintAttempts = 100
+
+// errConstraintViolated is reported in place of a score for candidates that
+// fail a constraint.
+var errConstraintViolated = errors.New("parameter set violates a constraint")
+
+// satisfies reports whether params passes every configured constraint.
+func (o *Optimizer) satisfies(params ParamSet) bool {
+	for _, constraint := range o.config.Constraints {
+		if !constraint(params) {
+			return false
+		}
+	}
+	return true
+}
+
+// reject counts a candidate discarded by a constraint.
+func (o *Optimizer) reject() {
+	atomic.AddInt64(&o.rejected, 1)
+}
+
+// constrained wraps an objective so candidates that fail a constraint are
+// rejected with an error instead of being evaluated. Every method already
+// skips failed evaluations, so the wrapper is the backstop for the methods
+// that propose candidates in unit space.
+func (o *Optimizer) constrained(objective ObjectiveFunc) ObjectiveFunc {
+	if len(o.config.Constraints) == 0 {
+		return objective
+	}
+	return func(params ParamSet) (float64, error) {
+		if !o.satisfies(params) {
+			o.reject()
+			return 0, errConstraintViolated
+		}
+		return objective(params)
+	}
+}
+
+// randomValidParams draws random parameter sets until one satisfies the
+// constraints. After maxConstraintAttempts draws it gives up and returns the
+// last one, which the constrained objective will reject.
+func (o *Optimizer) randomValidParams(params []Parameter) ParamSet {
+	var candidate ParamSet
+	for attempt := 0; attempt < maxConstraintAttempts; attempt++ {
+		candidate = make(ParamSet, len(params))
+		for _, param := range params {
+			candidate[param.Name] = o.randomParamValue(param)
+		}
+		if o.satisfies(candidate) {
+			return candidate
+		}
+		o.reject()
+	}
+	return candidate
+}
+
+// repair returns child if it satisfies the constraints, otherwise breeds
+// replacements until one does, falling back to a random valid parameter set.
+func (o *Optimizer) repair(params []Parameter, child ParamSet, breed func() ParamSet) ParamSet {
+	for attempt := 0; attempt < maxConstraintAttempts; attempt++ {
+		if o.satisfies(child) {
+			return child
+		}
+		o.reject()
+		child = breed()
+	}
+	if o.satisfies(child) {
+		return child
+	}
+	o.reject()
+	return o.randomValidParams(params)
+}
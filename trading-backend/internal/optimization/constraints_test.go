@@ -0,0 +1,102 @@
+package optimization_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/optimization"
+	"go.uber.org/zap"
+)
+
+var periodParams = []optimization.Parameter{
+	{Name: "fast_period", Type: optimization.ParamTypeInteger, Min: 1, Max: 10},
+	{Name: "slow_period", Type: optimization.ParamTypeInteger, Min: 1, Max: 10},
+}
+
+// optimizePeriods runs config over periodParams with fast_period < slow_period
+// and returns the result and how many invalid sets reached the objective.
+func optimizePeriods(t *testing.T, config *optimization.OptimizerConfig) (*optimization.OptimizationResult, int64) {
+	t.Helper()
+	config.Constraints = []optimization.Constraint{optimization.LessThan("fast_period", "slow_period")}
+	config.RandomSeed = 3
+
+	var invalid int64
+	objective := func(p optimization.ParamSet) (float64, error) {
+		if p["fast_period"] >= p["slow_period"] {
+			atomic.AddInt64(&invalid, 1)
+		}
+		return p["slow_period"] - p["fast_period"], nil
+	}
+
+	result, err := optimization.NewOptimizer(zap.NewNop(), config).
+		Optimize(context.Background(), periodParams, objective)
+	if err != nil {
+		t.Fatalf("Optimize %s: %v", config.Method, err)
+	}
+	return result, invalid
+}
+
+func TestGridSearchSkipsConstrainedCombinations(t *testing.T) {
+	config := optimization.DefaultOptimizerConfig()
+	config.Method = optimization.MethodGridSearch
+
+	result, invalid := optimizePeriods(t, config)
+
+	// 45 of the 100 combinations have fast_period < slow_period
+	if invalid != 0 || result.Iterations != 45 || result.RejectedByConstraint != 55 {
+		t.Errorf("invalid = %d, Iterations = %d, RejectedByConstraint = %d, want 0, 45 and 55",
+			invalid, result.Iterations, result.RejectedByConstraint)
+	}
+	if result.BestScore != 9 {
+		t.Errorf("BestScore = %v, want 9", result.BestScore)
+	}
+}
+
+func TestGeneticAlgorithmRebreedsConstrainedOffspring(t *testing.T) {
+	config := geneticConfig(0)
+	config.Generations = 20
+
+	result, invalid := optimizePeriods(t, config)
+
+	if invalid != 0 {
+		t.Errorf("%d invalid parameter sets reached the objective", invalid)
+	}
+	if result.RejectedByConstraint == 0 {
+		t.Error("expected random draws and offspring to be rejected")
+	}
+	if result.Iterations != 20*10 {
+		t.Errorf("Iterations = %d, want every individual evaluated", result.Iterations)
+	}
+	for _, res := range result.AllResults {
+		if res.Params["fast_period"] >= res.Params["slow_period"] {
+			t.Fatalf("invalid parameter set recorded: %v", res.Params)
+		}
+	}
+}
+
+func TestUnitSpaceMethodsRejectConstrainedCandidates(t *testing.T) {
+	for _, method := range []optimization.OptimizationMethod{
+		optimization.MethodAnnealing,
+		optimization.MethodSwarm,
+		optimization.MethodBayesian,
+		optimization.MethodRandomSearch,
+	} {
+		t.Run(string(method), func(t *testing.T) {
+			config := optimization.DefaultOptimizerConfig()
+			config.Method = method
+			config.MaxIterations = 60
+			config.AcquisitionSamples = 100
+			config.ParallelWorkers = 4
+
+			result, invalid := optimizePeriods(t, config)
+
+			if invalid != 0 {
+				t.Errorf("%d invalid parameter sets reached the objective", invalid)
+			}
+			if result.Iterations == 0 || result.BestScore <= 0 {
+				t.Errorf("Iterations = %d, BestScore = %v", result.Iterations, result.BestScore)
+			}
+		})
+	}
+}
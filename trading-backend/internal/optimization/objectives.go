@@ -0,0 +1,286 @@
+package optimization
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/backtester"
+)
+
+// Metric scores a backtest, larger being better
+type Metric func(results *backtester.BacktestResults) float64
+
+// BacktestFunc runs a backtest of a parameter set
+type BacktestFunc func(params ParamSet) (*backtester.BacktestResults, error)
+
+// tradingDaysPerYear annualizes daily ratios the way the backtester's
+// metrics calculator does
+const tradingDaysPerYear = 252
+
+// maxRatio caps ratios whose denominator is zero, such as the profit factor
+// of a run without a losing trade
+const maxRatio = 100
+
+// DefaultDrawdownPenalty is the Sharpe points the "penalized_sharpe"
+// objective subtracts per unit of max drawdown
+const DefaultDrawdownPenalty = 2.0
+
+// Metrics maps OptimizerConfig.TargetMetric and Objectives names to metrics
+var Metrics = map[string]Metric{
+	"sharpe":           Sharpe,
+	"sortino":          Sortino,
+	"calmar":           Calmar,
+	"cagr":             CAGR,
+	"return":           TotalReturn,
+	"profit_factor":    ProfitFactor,
+	"penalized_sharpe": PenalizedSharpe(DefaultDrawdownPenalty),
+}
+
+// MetricByName returns the named metric
+func MetricByName(name string) (Metric, error) {
+	metric, ok := Metrics[strings.ToLower(name)]
+	if !ok {
+		names := make([]string, 0, len(Metrics))
+		for n := range Metrics {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown objective metric %q (want one of %s)", name, strings.Join(names, ", "))
+	}
+	return metric, nil
+}
+
+// Objective returns an ObjectiveFunc that backtests each parameter set and
+// scores it by the named metric
+func Objective(name string, backtest BacktestFunc) (ObjectiveFunc, error) {
+	metric, err := MetricByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return func(params ParamSet) (float64, error) {
+		results, err := backtest(params)
+		if err != nil {
+			return 0, err
+		}
+		return metric(results), nil
+	}, nil
+}
+
+// MultiObjective returns a MultiObjectiveFunc that backtests each parameter
+// set once and scores it by every named metric
+func MultiObjective(names []string, backtest BacktestFunc) (MultiObjectiveFunc, error) {
+	metrics := make(map[string]Metric, len(names))
+	for _, name := range names {
+		metric, err := MetricByName(name)
+		if err != nil {
+			return nil, err
+		}
+		metrics[name] = metric
+	}
+	return func(params ParamSet) (map[string]float64, error) {
+		results, err := backtest(params)
+		if err != nil {
+			return nil, err
+		}
+		scores := make(map[string]float64, len(metrics))
+		for name, metric := range metrics {
+			scores[name] = metric(results)
+		}
+		return scores, nil
+	}, nil
+}
+
+// Objective returns an ObjectiveFunc scoring backtests by the configured
+// TargetMetric
+func (o *Optimizer) Objective(backtest BacktestFunc) (ObjectiveFunc, error) {
+	return Objective(o.config.TargetMetric, backtest)
+}
+
+// Sharpe is the annualized Sharpe ratio of daily equity returns, with no
+// risk-free rate. Results without an equity curve fall back to the
+// engine's SharpeRatio.
+func Sharpe(results *backtester.BacktestResults) float64 {
+	returns := dailyReturns(results)
+	if len(returns) < 2 {
+		return results.SharpeRatio
+	}
+	mean, std := meanStdDev(returns)
+	if std == 0 {
+		return 0
+	}
+	return mean / std * math.Sqrt(tradingDaysPerYear)
+}
+
+// Sortino is the annualized Sortino ratio of daily equity returns: the mean
+// return over the deviation of the losing days. A run without a losing day
+// scores maxRatio if it made money, and one with a single losing day has no
+// deviation to measure and scores zero, as in the backtester.
+func Sortino(results *backtester.BacktestResults) float64 {
+	returns := dailyReturns(results)
+	if len(returns) < 2 {
+		return 0
+	}
+
+	var losses []float64
+	for _, r := range returns {
+		if r < 0 {
+			losses = append(losses, r)
+		}
+	}
+	mean, _ := meanStdDev(returns)
+	if len(losses) == 0 {
+		return capRatio(mean)
+	}
+	_, downside := meanStdDev(losses)
+	if downside == 0 {
+		return 0
+	}
+	return mean / downside * math.Sqrt(tradingDaysPerYear)
+}
+
+// CAGR is the compound annual growth rate of the equity curve. Without
+// timestamps to measure the period it is the total return.
+func CAGR(results *backtester.BacktestResults) float64 {
+	curve := results.EquityCurve
+	if len(curve) < 2 || curve[0] <= 0 || len(results.Timestamps) != len(curve) {
+		return results.TotalReturn
+	}
+
+	years := results.Timestamps[len(curve)-1].Sub(results.Timestamps[0]).Hours() / (365 * 24)
+	if years <= 0 {
+		return results.TotalReturn
+	}
+	growth := curve[len(curve)-1] / curve[0]
+	if growth <= 0 {
+		return -1
+	}
+	return math.Pow(growth, 1/years) - 1
+}
+
+// Calmar is CAGR over max drawdown, capped at maxRatio for a run that never
+// drew down.
+func Calmar(results *backtester.BacktestResults) float64 {
+	cagr := CAGR(results)
+	drawdown := MaxDrawdown(results)
+	if drawdown == 0 {
+		return capRatio(cagr)
+	}
+	return cagr / drawdown
+}
+
+// TotalReturn is the backtest's total return
+func TotalReturn(results *backtester.BacktestResults) float64 {
+	return results.TotalReturn
+}
+
+// ProfitFactor is gross profit over gross loss of the closed trades, capped
+// at maxRatio for a profitable run without a losing trade. Results without
+// trade PnLs fall back to the engine's ProfitFactor.
+func ProfitFactor(results *backtester.BacktestResults) float64 {
+	if len(results.TradePnLs) == 0 {
+		return results.ProfitFactor
+	}
+
+	var profit, loss float64
+	for _, pnl := range results.TradePnLs {
+		if pnl > 0 {
+			profit += pnl
+		} else {
+			loss -= pnl
+		}
+	}
+	if loss == 0 {
+		return capRatio(profit)
+	}
+	return math.Min(profit/loss, maxRatio)
+}
+
+// PenalizedSharpe returns a metric of Sharpe minus penalty times max
+// drawdown, so of two equally smooth runs the shallower one wins
+func PenalizedSharpe(penalty float64) Metric {
+	return func(results *backtester.BacktestResults) float64 {
+		return Sharpe(results) - penalty*MaxDrawdown(results)
+	}
+}
+
+// MaxDrawdown is the largest fall from peak equity as a fraction of the
+// peak. Results without an equity curve fall back to the engine's
+// MaxDrawdown.
+func MaxDrawdown(results *backtester.BacktestResults) float64 {
+	if len(results.EquityCurve) < 2 {
+		return math.Abs(results.MaxDrawdown)
+	}
+
+	var peak, drawdown float64
+	for _, equity := range results.EquityCurve {
+		if equity > peak {
+			peak = equity
+		}
+		if peak > 0 {
+			drawdown = math.Max(drawdown, (peak-equity)/peak)
+		}
+	}
+	return drawdown
+}
+
+// dailyReturns returns the equity curve's returns between UTC day closes,
+// matching the backtester's annualization. A curve without timestamps is
+// treated as already daily.
+func dailyReturns(results *backtester.BacktestResults) []float64 {
+	curve := results.EquityCurve
+	closes := curve
+	if len(results.Timestamps) == len(curve) {
+		closes = make([]float64, 0, len(curve))
+		var lastDay time.Time
+		for i, equity := range curve {
+			day := results.Timestamps[i].UTC().Truncate(24 * time.Hour)
+			if len(closes) > 0 && day.Equal(lastDay) {
+				closes[len(closes)-1] = equity
+				continue
+			}
+			closes = append(closes, equity)
+			lastDay = day
+		}
+	}
+
+	returns := make([]float64, 0, len(closes))
+	for i := 1; i < len(closes); i++ {
+		if closes[i-1] != 0 {
+			returns = append(returns, closes[i]/closes[i-1]-1)
+		}
+	}
+	return returns
+}
+
+// meanStdDev returns the mean and sample standard deviation of values
+func meanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)-1))
+}
+
+// capRatio is the value of a ratio with a zero denominator: maxRatio when
+// the numerator is positive, otherwise zero
+func capRatio(numerator float64) float64 {
+	if numerator > 0 {
+		return maxRatio
+	}
+	return 0
+}
@@ -0,0 +1,122 @@
+package optimization_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/backtester"
+	"github.com/atlas-desktop/trading-backend/internal/optimization"
+	"go.uber.org/zap"
+)
+
+// dailyResults builds results from daily closing equity starting 2024-01-01
+func dailyResults(equity ...float64) *backtester.BacktestResults {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	results := &backtester.BacktestResults{EquityCurve: equity}
+	for i := range equity {
+		results.Timestamps = append(results.Timestamps, start.AddDate(0, 0, i))
+	}
+	results.TotalReturn = equity[len(equity)-1]/equity[0] - 1
+	return results
+}
+
+func TestObjectiveMetrics(t *testing.T) {
+	// Returns of +10%, -5%, +10%, -5%
+	results := dailyResults(100, 110, 104.5, 114.95, 109.2025)
+	results.TradePnLs = []float64{30, -10, 20, -20}
+
+	// Mean 0.025, sample std dev 0.0866, downside deviation 0 over two
+	// equal losses
+	if got, want := optimization.Sharpe(results), 0.025/math.Sqrt(0.0075)*math.Sqrt(252); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Expected Sharpe %.4f, got %.4f", want, got)
+	}
+	if got := optimization.Sortino(results); got != 0 {
+		t.Errorf("Expected Sortino 0 with no spread in the losses, got %.4f", got)
+	}
+	if got := optimization.ProfitFactor(results); math.Abs(got-50.0/30) > 1e-9 {
+		t.Errorf("Expected profit factor 1.667, got %.4f", got)
+	}
+	if got := optimization.MaxDrawdown(results); math.Abs(got-0.05) > 1e-9 {
+		t.Errorf("Expected max drawdown 5%%, got %.4f", got)
+	}
+
+	// A year of 21% growth
+	yearly := &backtester.BacktestResults{
+		EquityCurve: []float64{100, 90, 121},
+		Timestamps: []time.Time{
+			time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	if got := optimization.CAGR(yearly); math.Abs(got-0.21) > 1e-9 {
+		t.Errorf("Expected CAGR 21%%, got %.4f", got)
+	}
+	if got := optimization.Calmar(yearly); math.Abs(got-2.1) > 1e-9 {
+		t.Errorf("Expected Calmar 2.1 from a 10%% drawdown, got %.4f", got)
+	}
+
+	// Sharpe less twice the drawdown
+	sharpe := optimization.Sharpe(results)
+	if got := optimization.PenalizedSharpe(2)(results); math.Abs(got-(sharpe-0.1)) > 1e-9 {
+		t.Errorf("Expected penalized Sharpe %.4f, got %.4f", sharpe-0.1, got)
+	}
+
+	// Unlosing runs are capped rather than infinite
+	rising := dailyResults(100, 101, 103, 104)
+	rising.TradePnLs = []float64{1, 2, 1}
+	if got := optimization.Sortino(rising); got != 100 {
+		t.Errorf("Expected Sortino capped at 100 without a losing day, got %.4f", got)
+	}
+	if got := optimization.ProfitFactor(rising); got != 100 {
+		t.Errorf("Expected profit factor capped at 100 without a losing trade, got %.4f", got)
+	}
+}
+
+func TestObjectiveSelectsMetricByName(t *testing.T) {
+	if _, err := optimization.Objective("ulcer", nil); err == nil {
+		t.Error("Expected an unknown metric to be rejected")
+	}
+
+	// Smooth growth has the best Sharpe at period 3, raw return at period 5
+	backtest := func(p optimization.ParamSet) (*backtester.BacktestResults, error) {
+		if p["period"] == 5 {
+			return dailyResults(100, 130, 110, 140), nil
+		}
+		if p["period"] == 3 {
+			return dailyResults(100, 104, 108, 112), nil
+		}
+		return dailyResults(100, 101, 99, 100), nil
+	}
+	params := []optimization.Parameter{{Name: "period", Type: optimization.ParamTypeInteger, Min: 1, Max: 5}}
+
+	for metric, want := range map[string]float64{"sharpe": 3, "Sortino": 3, "return": 5} {
+		config := optimization.DefaultOptimizerConfig()
+		config.Method = optimization.MethodGridSearch
+		config.TargetMetric = metric
+		optimizer := optimization.NewOptimizer(zap.NewNop(), config)
+
+		objective, err := optimizer.Objective(backtest)
+		if err != nil {
+			t.Fatalf("Objective %s: %v", metric, err)
+		}
+		result, err := optimizer.Optimize(context.Background(), params, objective)
+		if err != nil {
+			t.Fatalf("Optimize %s: %v", metric, err)
+		}
+		if result.BestParams["period"] != want {
+			t.Errorf("Expected %s to pick period %.0f, got %.0f", metric, want, result.BestParams["period"])
+		}
+	}
+
+	multi, err := optimization.MultiObjective([]string{"cagr", "profit_factor"}, backtest)
+	if err != nil {
+		t.Fatalf("MultiObjective: %v", err)
+	}
+	scores, err := multi(optimization.ParamSet{"period": 3})
+	if err != nil || len(scores) != 2 {
+		t.Errorf("Expected two scores, got %v (%v)", scores, err)
+	}
+}
@@ -1,14 +1,18 @@
 // Package optimization provides strategy parameter optimization.
 // Based on research: "Walk-forward optimization with out-of-sample testing"
-// Methods: Grid search, genetic algorithm, Bayesian optimization
+// Methods: Grid search, genetic algorithm, Bayesian optimization, simulated
+// annealing, particle swarm
 package optimization
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"math/rand"
+	"path/filepath"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
@@ -19,16 +23,26 @@ type Optimizer struct {
 	logger *zap.Logger
 	config *OptimizerConfig
 	rng    *rand.Rand
+
+	warmStart []ParamSet // Seeds for the initial genetic population
+	rejected  int64      // Candidates rejected by constraints in the current run
 }
 
 // OptimizerConfig configures the optimizer
 type OptimizerConfig struct {
 	Method           OptimizationMethod
 	MaxIterations    int
-	TargetMetric     string // Metric to optimize (sharpe, return, calmar)
+	TargetMetric     string // Metric to optimize, a name in Metrics (sharpe, sortino, calmar, ...)
 	MinimizationMode bool   // True if we want to minimize (e.g., drawdown)
 	Timeout          time.Duration
 	ParallelWorkers  int
+	RandomSeed       int64 // 0 seeds from the clock
+
+	// Constraints every evaluated parameter set must satisfy. Grid search
+	// skips combinations that fail them, the genetic algorithm rebreeds
+	// offspring that fail them, and other methods spend an iteration on
+	// each rejected candidate without calling the objective.
+	Constraints []Constraint `json:"-"`
 
 	// Grid search
 	GridResolution int
@@ -40,10 +54,40 @@ type OptimizerConfig struct {
 	EliteCount     int
 	Generations    int
 
+	// Genetic algorithm early stopping: stop once the best score has not
+	// improved by MinImprovement for PatienceGenerations generations
+	PatienceGenerations int // 0 disables early stopping
+	MinImprovement      float64
+
+	// Bayesian optimization; meant for expensive objectives with budgets in
+	// the low hundreds, since each step refits a Gaussian process
+	InitialSamples     int     // Latin hypercube points before the surrogate is used
+	AcquisitionSamples int     // Candidates scored by expected improvement per pick
+	ExplorationXi      float64 // Expected improvement margin; higher explores more
+
+	// Simulated annealing; MaxIterations is the evaluation budget
+	InitialTemperature float64         // In score units; 0 calibrates from random moves
+	CoolingSchedule    CoolingSchedule // How the temperature falls each step
+	CoolingRate        float64         // Per-step factor for exponential cooling
+	StepSize           float64         // Std dev of moves as a fraction of each parameter's range
+
+	// Particle swarm; MaxIterations is the evaluation budget
+	SwarmSize      int
+	Inertia        float64 // Share of its velocity a particle keeps
+	CognitiveCoeff float64 // Pull toward the particle's own best position
+	SocialCoeff    float64 // Pull toward the swarm's best position
+
+	// Pareto optimization via OptimizePareto; the genetic algorithm settings
+	// above size the population and control breeding
+	Objectives        []string // Metrics returned by the MultiObjectiveFunc
+	ObjectiveMinimize []bool   // Per objective; missing entries maximize
+
 	// Walk-forward
-	InSamplePct float64 // % of data for in-sample
-	NumFolds    int     // Number of walk-forward periods
-	AnchoredWF  bool    // Use expanding window
+	InSamplePct   float64 // % of data for in-sample
+	NumFolds      int     // Number of walk-forward periods
+	AnchoredWF    bool    // Use expanding window
+	FoldWorkers   int     // Folds optimized concurrently; 0 or 1 runs them in turn
+	CheckpointDir string  // If set, the manifest and each fold are written here as they complete
 }
 
 // OptimizationMethod represents optimization algorithm
@@ -55,26 +99,42 @@ const (
 	MethodBayesian     OptimizationMethod = "bayesian"
 	MethodRandomSearch OptimizationMethod = "random"
 	MethodWalkForward  OptimizationMethod = "walk_forward"
+	MethodPareto       OptimizationMethod = "pareto" // NSGA-II, see OptimizePareto
+	MethodAnnealing    OptimizationMethod = "annealing"
+	MethodSwarm        OptimizationMethod = "swarm"
 )
 
 // DefaultOptimizerConfig returns sensible defaults
 func DefaultOptimizerConfig() *OptimizerConfig {
 	return &OptimizerConfig{
-		Method:           MethodGeneticAlgo,
-		MaxIterations:    1000,
-		TargetMetric:     "sharpe",
-		MinimizationMode: false,
-		Timeout:          10 * time.Minute,
-		ParallelWorkers:  8,
-		GridResolution:   10,
-		PopulationSize:   50,
-		MutationRate:     0.1,
-		CrossoverRate:    0.7,
-		EliteCount:       5,
-		Generations:      100,
-		InSamplePct:      0.7,
-		NumFolds:         5,
-		AnchoredWF:       false,
+		Method:              MethodGeneticAlgo,
+		MaxIterations:       1000,
+		TargetMetric:        "sharpe",
+		MinimizationMode:    false,
+		Timeout:             10 * time.Minute,
+		ParallelWorkers:     8,
+		GridResolution:      10,
+		PopulationSize:      50,
+		MutationRate:        0.1,
+		CrossoverRate:       0.7,
+		EliteCount:          5,
+		Generations:         100,
+		PatienceGenerations: 20,
+		MinImprovement:      1e-4,
+		InitialSamples:      10,
+		AcquisitionSamples:  1000,
+		ExplorationXi:       0.01,
+		CoolingSchedule:     CoolingExponential,
+		CoolingRate:         0.995,
+		StepSize:            0.1,
+		SwarmSize:           30,
+		Inertia:             0.72,
+		CognitiveCoeff:      1.49,
+		SocialCoeff:         1.49,
+		InSamplePct:         0.7,
+		NumFolds:            5,
+		AnchoredWF:          false,
+		FoldWorkers:         1,
 	}
 }
 
@@ -110,10 +170,15 @@ func NewOptimizer(logger *zap.Logger, config *OptimizerConfig) *Optimizer {
 		config = DefaultOptimizerConfig()
 	}
 
+	seed := config.RandomSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
 	return &Optimizer{
 		logger: logger,
 		config: config,
-		rng:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		rng:    rand.New(rand.NewSource(seed)),
 	}
 }
 
@@ -127,6 +192,18 @@ type OptimizationResult struct {
 	Iterations      int                `json:"iterations"`
 	Method          OptimizationMethod `json:"method"`
 
+	// Candidates discarded for failing a constraint before evaluation
+	RejectedByConstraint int `json:"rejected_by_constraint,omitempty"`
+
+	// Genetic algorithm specific
+	StoppedGeneration int  `json:"stopped_generation,omitempty"` // Generations actually run
+	EarlyStopped      bool `json:"early_stopped,omitempty"`
+
+	// Pareto specific
+	ParetoFront []ParetoPoint `json:"pareto_front,omitempty"`
+
+	Manifest *RunManifest `json:"manifest,omitempty"`
+
 	// Walk-forward specific
 	WalkForwardResults []*WalkForwardFold `json:"walk_forward_results,omitempty"`
 	OOSPerformance     float64            `json:"oos_performance,omitempty"`
@@ -135,10 +212,11 @@ type OptimizationResult struct {
 
 // EvaluationResult represents a single parameter evaluation
 type EvaluationResult struct {
-	Params    ParamSet      `json:"params"`
-	Score     float64       `json:"score"`
-	Iteration int           `json:"iteration"`
-	Duration  time.Duration `json:"duration"`
+	Params    ParamSet           `json:"params"`
+	Score     float64            `json:"score"`
+	Scores    map[string]float64 `json:"scores,omitempty"` // Every objective, for Pareto runs
+	Iteration int                `json:"iteration"`
+	Duration  time.Duration      `json:"duration"`
 }
 
 // WalkForwardFold contains results for one walk-forward period
@@ -161,6 +239,9 @@ func (o *Optimizer) Optimize(ctx context.Context, params []Parameter, objective
 	ctx, cancel := context.WithTimeout(ctx, o.config.Timeout)
 	defer cancel()
 
+	atomic.StoreInt64(&o.rejected, 0)
+	objective = o.constrained(objective)
+
 	var result *OptimizationResult
 	var err error
 
@@ -171,6 +252,14 @@ func (o *Optimizer) Optimize(ctx context.Context, params []Parameter, objective
 		result, err = o.geneticAlgorithm(ctx, params, objective)
 	case MethodRandomSearch:
 		result, err = o.randomSearch(ctx, params, objective)
+	case MethodBayesian:
+		result, err = o.bayesianOptimization(ctx, params, objective)
+	case MethodAnnealing:
+		result, err = o.simulatedAnnealing(ctx, params, objective)
+	case MethodSwarm:
+		result, err = o.particleSwarm(ctx, params, objective)
+	case MethodPareto:
+		return nil, fmt.Errorf("pareto optimization takes several objectives, use OptimizePareto")
 	default:
 		result, err = o.geneticAlgorithm(ctx, params, objective)
 	}
@@ -181,6 +270,9 @@ func (o *Optimizer) Optimize(ctx context.Context, params []Parameter, objective
 
 	result.Duration = time.Since(startTime)
 	result.Method = o.config.Method
+	result.RejectedByConstraint = int(atomic.LoadInt64(&o.rejected))
+	result.Manifest = newManifest(o.config.Method, o.config, startTime)
+	result.Manifest.CompletedAt = time.Now()
 
 	return result, nil
 }
@@ -192,11 +284,22 @@ func (o *Optimizer) gridSearch(ctx context.Context, params []Parameter, objectiv
 		ConvergenceHist: make([]float64, 0),
 	}
 
-	// Generate all parameter combinations
+	// Generate all parameter combinations, dropping those that fail a
+	// constraint
 	combinations := o.generateGridCombinations(params)
+	valid := combinations[:0]
+	for _, combo := range combinations {
+		if o.satisfies(combo) {
+			valid = append(valid, combo)
+		} else {
+			o.reject()
+		}
+	}
+	combinations = valid
 
 	o.logger.Info("starting grid search",
 		zap.Int("combinations", len(combinations)),
+		zap.Int64("rejected_by_constraint", atomic.LoadInt64(&o.rejected)),
 	)
 
 	// Evaluate in parallel
@@ -339,6 +442,10 @@ func (o *Optimizer) geneticAlgorithm(ctx context.Context, params []Parameter, ob
 		bestScore = math.Inf(1)
 	}
 
+	// Best score at the last meaningful improvement, for early stopping
+	plateauScore := bestScore
+	plateauGen := 0
+
 	for gen := 0; gen < o.config.Generations; gen++ {
 		select {
 		case <-ctx.Done():
@@ -398,6 +505,23 @@ func (o *Optimizer) geneticAlgorithm(ctx context.Context, params []Parameter, ob
 
 		result.ConvergenceHist = append(result.ConvergenceHist, bestScore)
 		result.Iterations = (gen + 1) * len(population)
+		result.StoppedGeneration = gen + 1
+
+		improvement := bestScore - plateauScore
+		if o.config.MinimizationMode {
+			improvement = plateauScore - bestScore
+		}
+		if gen == 0 || improvement > o.config.MinImprovement {
+			plateauScore = bestScore
+			plateauGen = gen
+		} else if o.config.PatienceGenerations > 0 && gen-plateauGen >= o.config.PatienceGenerations {
+			result.EarlyStopped = true
+			o.logger.Info("genetic algorithm converged, stopping early",
+				zap.Int("generation", gen+1),
+				zap.Float64("best_score", bestScore),
+			)
+			break
+		}
 
 		// Create next generation
 		population = o.evolvePopulation(params, population, scores)
@@ -410,12 +534,20 @@ func (o *Optimizer) geneticAlgorithm(ctx context.Context, params []Parameter, ob
 func (o *Optimizer) initializePopulation(params []Parameter) []ParamSet {
 	population := make([]ParamSet, o.config.PopulationSize)
 
-	for i := 0; i < o.config.PopulationSize; i++ {
-		individual := make(ParamSet)
-		for _, param := range params {
-			individual[param.Name] = o.randomParamValue(param)
+	// Warm-start individuals from a prior run go first
+	for i, prior := range o.warmStart {
+		if i >= o.config.PopulationSize {
+			break
+		}
+		population[i] = o.fitParams(params, prior)
+		if !o.satisfies(population[i]) {
+			o.reject()
+			population[i] = o.randomValidParams(params)
 		}
-		population[i] = individual
+	}
+
+	for i := len(o.warmStart); i < o.config.PopulationSize; i++ {
+		population[i] = o.randomValidParams(params)
 	}
 
 	return population
@@ -456,8 +588,7 @@ func (o *Optimizer) evolvePopulation(params []Parameter, population []ParamSet,
 		newPopulation[i] = o.copyParams(population[indices[i]])
 	}
 
-	// Fill rest with crossover and mutation
-	for i := o.config.EliteCount; i < o.config.PopulationSize; i++ {
+	breed := func() ParamSet {
 		// Tournament selection
 		parent1 := o.tournamentSelect(population, scores)
 		parent2 := o.tournamentSelect(population, scores)
@@ -471,9 +602,13 @@ func (o *Optimizer) evolvePopulation(params []Parameter, population []ParamSet,
 		}
 
 		// Mutation
-		child = o.mutate(params, child)
+		return o.mutate(params, child)
+	}
 
-		newPopulation[i] = child
+	// Fill rest with crossover and mutation, rebreeding children that fail
+	// a constraint
+	for i := o.config.EliteCount; i < o.config.PopulationSize; i++ {
+		newPopulation[i] = o.repair(params, breed(), breed)
 	}
 
 	return newPopulation
@@ -574,10 +709,7 @@ func (o *Optimizer) randomSearch(ctx context.Context, params []Parameter, object
 		}
 
 		// Generate random parameters
-		paramSet := make(ParamSet)
-		for _, param := range params {
-			paramSet[param.Name] = o.randomParamValue(param)
-		}
+		paramSet := o.randomValidParams(params)
 
 		// Evaluate
 		start := time.Now()
@@ -652,6 +784,13 @@ func (wfo *WalkForwardOptimizer) OptimizeWalkForward(
 	}
 
 	startTime := time.Now()
+	result.Manifest = newManifest(MethodWalkForward, wfo.config, startTime)
+
+	if wfo.config.CheckpointDir != "" {
+		if err := writeJSONFile(filepath.Join(wfo.config.CheckpointDir, "manifest.json"), result.Manifest); err != nil {
+			return nil, err
+		}
+	}
 
 	// Calculate fold boundaries
 	totalDuration := fullRange.End.Sub(fullRange.Start)
@@ -659,15 +798,31 @@ func (wfo *WalkForwardOptimizer) OptimizeWalkForward(
 	inSampleDuration := time.Duration(float64(foldDuration) * wfo.config.InSamplePct)
 	outSampleDuration := foldDuration - inSampleDuration
 
-	var totalISScore, totalOOSScore float64
+	// Fold seeds are drawn up front, in fold order, so each fold's optimizer
+	// sees the same random stream however the folds are scheduled
+	seeds := make([]int64, wfo.config.NumFolds)
+	for fold := range seeds {
+		seeds[fold] = wfo.optimizer.rng.Int63()
+	}
 
-	for fold := 0; fold < wfo.config.NumFolds; fold++ {
-		select {
-		case <-ctx.Done():
-			return result, ctx.Err()
-		default:
-		}
+	folds := make([]*WalkForwardFold, wfo.config.NumFolds)
+	foldResults := make([]*OptimizationResult, wfo.config.NumFolds)
 
+	workers := wfo.config.FoldWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var foldErr error
+
+	sem := make(chan struct{}, workers)
+
+	for fold := 0; fold < wfo.config.NumFolds; fold++ {
 		var isStart, isEnd, oosStart, oosEnd time.Time
 
 		if wfo.config.AnchoredWF {
@@ -690,54 +845,73 @@ func (wfo *WalkForwardOptimizer) OptimizeWalkForward(
 			oosEnd = fullRange.End
 		}
 
-		wfo.logger.Info("walk-forward fold",
-			zap.Int("fold", fold+1),
-			zap.Time("is_start", isStart),
-			zap.Time("is_end", isEnd),
-			zap.Time("oos_start", oosStart),
-			zap.Time("oos_end", oosEnd),
-		)
-
-		// Create objective wrapper for in-sample
-		isObjective := func(p ParamSet) (float64, error) {
-			return objective(p, DataRange{Start: isStart, End: isEnd})
+		foldResult := &WalkForwardFold{
+			FoldNumber:     fold + 1,
+			InSampleStart:  isStart,
+			InSampleEnd:    isEnd,
+			OutSampleStart: oosStart,
+			OutSampleEnd:   oosEnd,
 		}
 
-		// Optimize on in-sample
-		optResult, err := wfo.optimizer.Optimize(ctx, params, isObjective)
-		if err != nil {
-			return nil, err
-		}
+		wg.Add(1)
+		go func(idx int, foldResult *WalkForwardFold) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
 
-		// Evaluate on out-of-sample
-		oosScore, err := objective(optResult.BestParams, DataRange{Start: oosStart, End: oosEnd})
-		if err != nil {
-			return nil, err
-		}
+			if ctx.Err() != nil {
+				return
+			}
 
-		// Calculate degradation
-		degradation := 0.0
-		if optResult.BestScore != 0 {
-			degradation = (optResult.BestScore - oosScore) / math.Abs(optResult.BestScore)
-		}
+			optResult, err := wfo.runFold(ctx, params, objective, foldResult, seeds[idx])
+			if err != nil {
+				errOnce.Do(func() {
+					foldErr = err
+					cancel()
+				})
+				return
+			}
 
-		foldResult := &WalkForwardFold{
-			FoldNumber:      fold + 1,
-			InSampleStart:   isStart,
-			InSampleEnd:     isEnd,
-			OutSampleStart:  oosStart,
-			OutSampleEnd:    oosEnd,
-			OptimizedParams: optResult.BestParams,
-			InSampleScore:   optResult.BestScore,
-			OutSampleScore:  oosScore,
-			Degradation:     degradation,
-		}
+			folds[idx] = foldResult
+			foldResults[idx] = optResult
+
+			if wfo.config.CheckpointDir != "" {
+				checkpoint := &FoldCheckpoint{
+					Manifest:   result.Manifest,
+					Fold:       foldResult,
+					AllResults: optResult.AllResults,
+				}
+				path := foldCheckpointPath(wfo.config.CheckpointDir, foldResult.FoldNumber)
+				if err := writeJSONFile(path, checkpoint); err != nil {
+					wfo.logger.Warn("failed to checkpoint walk-forward fold",
+						zap.Int("fold", foldResult.FoldNumber),
+						zap.Error(err),
+					)
+				}
+			}
+		}(fold, foldResult)
+	}
+
+	wg.Wait()
+
+	if foldErr != nil {
+		return nil, foldErr
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	// Aggregate in fold order so the result does not depend on which fold
+	// finished first
+	var totalISScore, totalOOSScore float64
 
+	for fold, foldResult := range folds {
 		result.WalkForwardResults = append(result.WalkForwardResults, foldResult)
-		result.AllResults = append(result.AllResults, optResult.AllResults...)
+		result.AllResults = append(result.AllResults, foldResults[fold].AllResults...)
+		result.RejectedByConstraint += foldResults[fold].RejectedByConstraint
 
-		totalISScore += optResult.BestScore
-		totalOOSScore += oosScore
+		totalISScore += foldResult.InSampleScore
+		totalOOSScore += foldResult.OutSampleScore
 	}
 
 	// Calculate averages
@@ -758,6 +932,13 @@ func (wfo *WalkForwardOptimizer) OptimizeWalkForward(
 
 	result.Duration = time.Since(startTime)
 	result.Iterations = len(result.AllResults)
+	result.Manifest.CompletedAt = time.Now()
+
+	if wfo.config.CheckpointDir != "" {
+		if err := SaveResult(filepath.Join(wfo.config.CheckpointDir, "result.json"), result); err != nil {
+			wfo.logger.Warn("failed to save walk-forward result", zap.Error(err))
+		}
+	}
 
 	wfo.logger.Info("walk-forward optimization complete",
 		zap.Float64("avg_is_score", avgISScore),
@@ -767,3 +948,59 @@ func (wfo *WalkForwardOptimizer) OptimizeWalkForward(
 
 	return result, nil
 }
+
+// runFold optimizes one fold on its in-sample range with a dedicated
+// optimizer and scores the winner out of sample, filling in foldResult. It
+// returns the in-sample optimization result.
+func (wfo *WalkForwardOptimizer) runFold(
+	ctx context.Context,
+	params []Parameter,
+	objective WalkForwardObjective,
+	foldResult *WalkForwardFold,
+	seed int64,
+) (*OptimizationResult, error) {
+
+	wfo.logger.Info("walk-forward fold",
+		zap.Int("fold", foldResult.FoldNumber),
+		zap.Time("is_start", foldResult.InSampleStart),
+		zap.Time("is_end", foldResult.InSampleEnd),
+		zap.Time("oos_start", foldResult.OutSampleStart),
+		zap.Time("oos_end", foldResult.OutSampleEnd),
+	)
+
+	config := *wfo.config
+	config.RandomSeed = seed
+	optimizer := NewOptimizer(wfo.logger, &config)
+
+	// Create objective wrapper for in-sample
+	isRange := DataRange{Start: foldResult.InSampleStart, End: foldResult.InSampleEnd}
+	isObjective := func(p ParamSet) (float64, error) {
+		return objective(p, isRange)
+	}
+
+	// Optimize on in-sample
+	optResult, err := optimizer.Optimize(ctx, params, isObjective)
+	if err != nil {
+		return nil, fmt.Errorf("fold %d: %w", foldResult.FoldNumber, err)
+	}
+
+	// Evaluate on out-of-sample
+	oosRange := DataRange{Start: foldResult.OutSampleStart, End: foldResult.OutSampleEnd}
+	oosScore, err := objective(optResult.BestParams, oosRange)
+	if err != nil {
+		return nil, fmt.Errorf("fold %d out-of-sample: %w", foldResult.FoldNumber, err)
+	}
+
+	// Calculate degradation
+	degradation := 0.0
+	if optResult.BestScore != 0 {
+		degradation = (optResult.BestScore - oosScore) / math.Abs(optResult.BestScore)
+	}
+
+	foldResult.OptimizedParams = optResult.BestParams
+	foldResult.InSampleScore = optResult.BestScore
+	foldResult.OutSampleScore = oosScore
+	foldResult.Degradation = degradation
+
+	return optResult, nil
+}
@@ -0,0 +1,149 @@
+package optimization_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/optimization"
+	"go.uber.org/zap"
+)
+
+func geneticConfig(patience int) *optimization.OptimizerConfig {
+	config := optimization.DefaultOptimizerConfig()
+	config.Method = optimization.MethodGeneticAlgo
+	config.PopulationSize = 10
+	config.EliteCount = 2
+	config.Generations = 50
+	config.PatienceGenerations = patience
+	config.RandomSeed = 1
+	return config
+}
+
+var flatParams = []optimization.Parameter{
+	{Name: "x", Type: optimization.ParamTypeContinuous, Min: 0, Max: 1},
+}
+
+func flatObjective(optimization.ParamSet) (float64, error) {
+	return 1, nil
+}
+
+func TestGeneticAlgorithmStopsEarlyOnFlatObjective(t *testing.T) {
+	opt := optimization.NewOptimizer(zap.NewNop(), geneticConfig(5))
+
+	result, err := opt.Optimize(context.Background(), flatParams, flatObjective)
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+
+	if !result.EarlyStopped {
+		t.Fatal("expected early stop on a flat objective")
+	}
+	// Generation 1 sets the baseline, then 5 generations without improvement
+	if result.StoppedGeneration != 6 {
+		t.Errorf("StoppedGeneration = %d, want 6", result.StoppedGeneration)
+	}
+	if len(result.ConvergenceHist) != result.StoppedGeneration {
+		t.Errorf("ConvergenceHist has %d entries, want one per generation run (%d)",
+			len(result.ConvergenceHist), result.StoppedGeneration)
+	}
+	if result.Iterations != 6*10 {
+		t.Errorf("Iterations = %d, want 60", result.Iterations)
+	}
+}
+
+func TestGeneticAlgorithmRunsAllGenerationsWithoutPatience(t *testing.T) {
+	opt := optimization.NewOptimizer(zap.NewNop(), geneticConfig(0))
+
+	result, err := opt.Optimize(context.Background(), flatParams, flatObjective)
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+
+	if result.EarlyStopped {
+		t.Error("early stopping should be disabled with zero patience")
+	}
+	if result.StoppedGeneration != 50 || len(result.ConvergenceHist) != 50 {
+		t.Errorf("ran %d generations (%d history entries), want 50",
+			result.StoppedGeneration, len(result.ConvergenceHist))
+	}
+}
+
+func walkForwardConfig(workers int) *optimization.OptimizerConfig {
+	config := optimization.DefaultOptimizerConfig()
+	config.Method = optimization.MethodRandomSearch
+	config.MaxIterations = 30
+	config.ParallelWorkers = 2
+	config.NumFolds = 5
+	config.FoldWorkers = workers
+	config.RandomSeed = 99
+	return config
+}
+
+func TestWalkForwardParallelMatchesSequential(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fullRange := optimization.DataRange{Start: start, End: start.Add(100 * 24 * time.Hour)}
+
+	// Each range prefers a different x, and early folds run slowest so
+	// parallel folds complete out of order
+	objective := func(p optimization.ParamSet, r optimization.DataRange) (float64, error) {
+		days := r.Start.Sub(start).Hours() / 24
+		time.Sleep(time.Duration(100-days) * time.Microsecond)
+		return -math.Abs(p["x"] - days/100), nil
+	}
+
+	run := func(workers int) *optimization.OptimizationResult {
+		wfo := optimization.NewWalkForwardOptimizer(zap.NewNop(), walkForwardConfig(workers))
+		result, err := wfo.OptimizeWalkForward(context.Background(), flatParams, objective, fullRange)
+		if err != nil {
+			t.Fatalf("OptimizeWalkForward with %d workers: %v", workers, err)
+		}
+		return result
+	}
+
+	sequential := run(1)
+	parallel := run(4)
+
+	if len(parallel.WalkForwardResults) != 5 {
+		t.Fatalf("got %d folds, want 5", len(parallel.WalkForwardResults))
+	}
+	for i, fold := range parallel.WalkForwardResults {
+		want := sequential.WalkForwardResults[i]
+		if fold.FoldNumber != i+1 {
+			t.Errorf("fold %d reported as number %d", i+1, fold.FoldNumber)
+		}
+		if fold.InSampleScore != want.InSampleScore || fold.OutSampleScore != want.OutSampleScore ||
+			fold.OptimizedParams["x"] != want.OptimizedParams["x"] {
+			t.Errorf("fold %d differs between sequential and parallel runs", i+1)
+		}
+	}
+	if parallel.OOSPerformance != sequential.OOSPerformance ||
+		parallel.ISvsOOSDegradation != sequential.ISvsOOSDegradation {
+		t.Errorf("averages differ: parallel %v/%v, sequential %v/%v",
+			parallel.OOSPerformance, parallel.ISvsOOSDegradation,
+			sequential.OOSPerformance, sequential.ISvsOOSDegradation)
+	}
+	if parallel.Iterations != 5*30 {
+		t.Errorf("Iterations = %d, want 150", parallel.Iterations)
+	}
+}
+
+func TestWalkForwardParallelReturnsFoldError(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fullRange := optimization.DataRange{Start: start, End: start.Add(100 * 24 * time.Hour)}
+
+	// Only the last fold's out-of-sample range reaches the end of the data
+	objective := func(p optimization.ParamSet, r optimization.DataRange) (float64, error) {
+		if r.End.Equal(fullRange.End) {
+			return 0, errors.New("no data")
+		}
+		return p["x"], nil
+	}
+
+	wfo := optimization.NewWalkForwardOptimizer(zap.NewNop(), walkForwardConfig(3))
+	if _, err := wfo.OptimizeWalkForward(context.Background(), flatParams, objective, fullRange); err == nil {
+		t.Fatal("expected the failing fold's error")
+	}
+}
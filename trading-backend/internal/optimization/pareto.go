@@ -0,0 +1,438 @@
+package optimization
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// MultiObjectiveFunc evaluates a parameter set and returns a value for each
+// metric named in OptimizerConfig.Objectives
+type MultiObjectiveFunc func(params ParamSet) (map[string]float64, error)
+
+// ParetoPoint is one non-dominated parameter set
+type ParetoPoint struct {
+	Params           ParamSet           `json:"params"`
+	Scores           map[string]float64 `json:"scores"`
+	CrowdingDistance float64            `json:"crowding_distance"` // math.MaxFloat64 at the edges of the front
+}
+
+// paretoEval is the outcome of one multi-objective call. objs holds the
+// scores in Objectives order, negated where minimizing so larger is always
+// better; it is nil when the evaluation failed.
+type paretoEval struct {
+	params   ParamSet
+	scores   map[string]float64
+	objs     []float64
+	duration time.Duration
+}
+
+// OptimizePareto searches for the trade-off front between several objectives
+// using NSGA-II: the genetic algorithm settings drive the search, but
+// selection ranks individuals by non-dominated front and then by crowding
+// distance instead of by a single score. The result's ParetoFront holds the
+// final non-dominated set, sorted best-first on the first objective, whose
+// best point also fills BestParams and BestScore.
+func (o *Optimizer) OptimizePareto(ctx context.Context, params []Parameter, objective MultiObjectiveFunc) (*OptimizationResult, error) {
+	if len(o.config.Objectives) == 0 {
+		return nil, fmt.Errorf("pareto optimization needs at least one objective")
+	}
+
+	startTime := time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, o.config.Timeout)
+	defer cancel()
+
+	result := &OptimizationResult{
+		AllResults:      make([]EvaluationResult, 0),
+		ConvergenceHist: make([]float64, 0),
+		Method:          MethodPareto,
+	}
+
+	o.logger.Info("starting pareto optimization",
+		zap.Strings("objectives", o.config.Objectives),
+		zap.Int("population", o.config.PopulationSize),
+		zap.Int("generations", o.config.Generations),
+	)
+
+	atomic.StoreInt64(&o.rejected, 0)
+
+	population := o.evaluatePareto(o.initializePopulation(params), objective, result)
+	ranks, crowding := o.rankPopulation(population)
+
+	for gen := 0; gen < o.config.Generations; gen++ {
+		select {
+		case <-ctx.Done():
+			o.finishPareto(result, population, startTime)
+			return result, ctx.Err()
+		default:
+		}
+
+		breed := func() ParamSet {
+			parent1 := o.crowdedTournament(population, ranks, crowding)
+			parent2 := o.crowdedTournament(population, ranks, crowding)
+
+			var child ParamSet
+			if o.rng.Float64() < o.config.CrossoverRate {
+				child = o.crossover(params, parent1.params, parent2.params)
+			} else {
+				child = o.copyParams(parent1.params)
+			}
+			return o.mutate(params, child)
+		}
+
+		offspring := make([]ParamSet, len(population))
+		for i := range offspring {
+			offspring[i] = o.repair(params, breed(), breed)
+		}
+
+		// Parents and children compete together, so good points are never lost
+		combined := make([]paretoEval, 0, 2*len(population))
+		combined = append(combined, population...)
+		combined = append(combined, o.evaluatePareto(offspring, objective, result)...)
+		population = o.selectSurvivors(combined, o.config.PopulationSize)
+		ranks, crowding = o.rankPopulation(population)
+
+		result.StoppedGeneration = gen + 1
+		result.ConvergenceHist = append(result.ConvergenceHist, o.frontBest(population, ranks))
+	}
+
+	o.finishPareto(result, population, startTime)
+
+	o.logger.Info("pareto optimization complete",
+		zap.Int("front_size", len(result.ParetoFront)),
+		zap.Int("evaluations", result.Iterations),
+	)
+
+	return result, nil
+}
+
+// evaluatePareto scores a batch in parallel and records every evaluation in
+// result. EvaluationResult.Score carries the first objective.
+func (o *Optimizer) evaluatePareto(batch []ParamSet, objective MultiObjectiveFunc, result *OptimizationResult) []paretoEval {
+	evals := make([]paretoEval, len(batch))
+	var wg sync.WaitGroup
+
+	workers := o.config.ParallelWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	sem := make(chan struct{}, workers)
+
+	for i, candidate := range batch {
+		wg.Add(1)
+		go func(idx int, p ParamSet) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if !o.satisfies(p) {
+				o.reject()
+				evals[idx] = paretoEval{params: p}
+				return
+			}
+
+			start := time.Now()
+			scores, err := objective(p)
+			evals[idx] = paretoEval{params: p, duration: time.Since(start)}
+			if err != nil {
+				return
+			}
+
+			objs, ok := o.orientObjectives(scores)
+			if !ok {
+				return
+			}
+			evals[idx].scores = scores
+			evals[idx].objs = objs
+		}(i, candidate)
+	}
+
+	wg.Wait()
+
+	for _, ev := range evals {
+		result.Iterations++
+		if ev.objs == nil {
+			continue
+		}
+		result.AllResults = append(result.AllResults, EvaluationResult{
+			Params:    ev.params,
+			Score:     ev.scores[o.config.Objectives[0]],
+			Scores:    ev.scores,
+			Iteration: result.Iterations - 1,
+			Duration:  ev.duration,
+		})
+	}
+
+	return evals
+}
+
+// orientObjectives orders scores by Objectives and flips minimized ones.
+// It fails when a metric is missing or not a finite number.
+func (o *Optimizer) orientObjectives(scores map[string]float64) ([]float64, bool) {
+	objs := make([]float64, len(o.config.Objectives))
+	for i, name := range o.config.Objectives {
+		v, ok := scores[name]
+		if !ok || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, false
+		}
+		if i < len(o.config.ObjectiveMinimize) && o.config.ObjectiveMinimize[i] {
+			v = -v
+		}
+		objs[i] = v
+	}
+	return objs, true
+}
+
+// rankPopulation returns each individual's front index and crowding distance.
+// Failed evaluations share a last front behind every valid one.
+func (o *Optimizer) rankPopulation(population []paretoEval) ([]int, []float64) {
+	ranks := make([]int, len(population))
+	crowding := make([]float64, len(population))
+
+	fronts := paretoFronts(population)
+	for rank, front := range fronts {
+		distances := crowdingDistances(population, front)
+		for i, idx := range front {
+			ranks[idx] = rank
+			crowding[idx] = distances[i]
+		}
+	}
+
+	return ranks, crowding
+}
+
+// selectSurvivors keeps the best n individuals, filling whole fronts first
+// and breaking the last one by crowding distance to preserve spread.
+func (o *Optimizer) selectSurvivors(population []paretoEval, n int) []paretoEval {
+	survivors := make([]paretoEval, 0, n)
+
+	for _, front := range paretoFronts(population) {
+		if len(survivors)+len(front) <= n {
+			for _, idx := range front {
+				survivors = append(survivors, population[idx])
+			}
+			continue
+		}
+
+		distances := crowdingDistances(population, front)
+		order := make([]int, len(front))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return distances[order[i]] > distances[order[j]]
+		})
+
+		for _, i := range order[:n-len(survivors)] {
+			survivors = append(survivors, population[front[i]])
+		}
+		break
+	}
+
+	return survivors
+}
+
+// crowdedTournament picks the better of two random individuals: lower front
+// first, then the less crowded one.
+func (o *Optimizer) crowdedTournament(population []paretoEval, ranks []int, crowding []float64) paretoEval {
+	a := o.rng.Intn(len(population))
+	b := o.rng.Intn(len(population))
+
+	if ranks[b] < ranks[a] || (ranks[b] == ranks[a] && crowding[b] > crowding[a]) {
+		a = b
+	}
+	return population[a]
+}
+
+// frontBest returns the best first-objective value on the first front, in
+// the caller's orientation.
+func (o *Optimizer) frontBest(population []paretoEval, ranks []int) float64 {
+	best := math.Inf(-1)
+	for i, ev := range population {
+		if ranks[i] == 0 && ev.objs != nil && ev.objs[0] > best {
+			best = ev.objs[0]
+		}
+	}
+
+	if len(o.config.ObjectiveMinimize) > 0 && o.config.ObjectiveMinimize[0] {
+		return -best
+	}
+	return best
+}
+
+// finishPareto fills the front, best point, duration and manifest from the final
+// population. Duplicate points on the front are reported once.
+func (o *Optimizer) finishPareto(result *OptimizationResult, population []paretoEval, startTime time.Time) {
+	result.Duration = time.Since(startTime)
+	result.RejectedByConstraint = int(atomic.LoadInt64(&o.rejected))
+	result.Manifest = newManifest(MethodPareto, o.config, startTime)
+	result.Manifest.CompletedAt = time.Now()
+
+	fronts := paretoFronts(population)
+	if len(fronts) == 0 || population[fronts[0][0]].objs == nil {
+		return
+	}
+
+	front := make([]int, 0, len(fronts[0]))
+	for _, idx := range fronts[0] {
+		duplicate := false
+		for _, kept := range front {
+			if equalObjectives(population[idx].objs, population[kept].objs) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			front = append(front, idx)
+		}
+	}
+
+	distances := crowdingDistances(population, front)
+	result.ParetoFront = make([]ParetoPoint, len(front))
+	for i, idx := range front {
+		result.ParetoFront[i] = ParetoPoint{
+			Params:           population[idx].params,
+			Scores:           population[idx].scores,
+			CrowdingDistance: distances[i],
+		}
+	}
+
+	first := o.config.Objectives[0]
+	minimize := len(o.config.ObjectiveMinimize) > 0 && o.config.ObjectiveMinimize[0]
+	sort.SliceStable(result.ParetoFront, func(i, j int) bool {
+		if minimize {
+			return result.ParetoFront[i].Scores[first] < result.ParetoFront[j].Scores[first]
+		}
+		return result.ParetoFront[i].Scores[first] > result.ParetoFront[j].Scores[first]
+	})
+
+	result.BestParams = result.ParetoFront[0].Params
+	result.BestScore = result.ParetoFront[0].Scores[first]
+}
+
+// paretoFronts sorts a population into non-dominated fronts (the NSGA-II
+// fast non-dominated sort). Failed evaluations form a final front.
+func paretoFronts(population []paretoEval) [][]int {
+	var valid, failed []int
+	for i, ev := range population {
+		if ev.objs == nil {
+			failed = append(failed, i)
+		} else {
+			valid = append(valid, i)
+		}
+	}
+
+	dominatedBy := make(map[int][]int, len(valid)) // individuals each one dominates
+	dominationCount := make(map[int]int, len(valid))
+
+	var fronts [][]int
+	var current []int
+
+	for _, p := range valid {
+		for _, q := range valid {
+			if p == q {
+				continue
+			}
+			if dominates(population[p].objs, population[q].objs) {
+				dominatedBy[p] = append(dominatedBy[p], q)
+			} else if dominates(population[q].objs, population[p].objs) {
+				dominationCount[p]++
+			}
+		}
+		if dominationCount[p] == 0 {
+			current = append(current, p)
+		}
+	}
+
+	for len(current) > 0 {
+		fronts = append(fronts, current)
+
+		var next []int
+		for _, p := range current {
+			for _, q := range dominatedBy[p] {
+				dominationCount[q]--
+				if dominationCount[q] == 0 {
+					next = append(next, q)
+				}
+			}
+		}
+		current = next
+	}
+
+	if len(failed) > 0 {
+		fronts = append(fronts, failed)
+	}
+
+	return fronts
+}
+
+// dominates reports whether a is at least as good as b on every objective
+// and strictly better on one, with larger values better.
+func dominates(a, b []float64) bool {
+	better := false
+	for i := range a {
+		if a[i] < b[i] {
+			return false
+		}
+		if a[i] > b[i] {
+			better = true
+		}
+	}
+	return better
+}
+
+// equalObjectives reports whether two objective vectors are identical.
+func equalObjectives(a, b []float64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// crowdingDistances returns, for each member of front, the sum over
+// objectives of the normalized gap between its neighbours. Points at either
+// end of an objective get math.MaxFloat64 so the extremes always survive.
+func crowdingDistances(population []paretoEval, front []int) []float64 {
+	distances := make([]float64, len(front))
+	if len(front) == 0 || population[front[0]].objs == nil {
+		return distances
+	}
+
+	order := make([]int, len(front))
+	for m := range population[front[0]].objs {
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return population[front[order[i]]].objs[m] < population[front[order[j]]].objs[m]
+		})
+
+		low := population[front[order[0]]].objs[m]
+		high := population[front[order[len(order)-1]]].objs[m]
+
+		distances[order[0]] = math.MaxFloat64
+		distances[order[len(order)-1]] = math.MaxFloat64
+		if high == low {
+			continue
+		}
+
+		for k := 1; k < len(order)-1; k++ {
+			if distances[order[k]] == math.MaxFloat64 {
+				continue
+			}
+			gap := population[front[order[k+1]]].objs[m] - population[front[order[k-1]]].objs[m]
+			distances[order[k]] += gap / (high - low)
+		}
+	}
+
+	return distances
+}
@@ -0,0 +1,130 @@
+package optimization_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/optimization"
+	"go.uber.org/zap"
+)
+
+// schaffer has two minimized objectives whose Pareto set is x in [0, 2].
+func schaffer(p optimization.ParamSet) (map[string]float64, error) {
+	x := p["x"]
+	return map[string]float64{"f1": x * x, "f2": (x - 2) * (x - 2)}, nil
+}
+
+func paretoConfig() *optimization.OptimizerConfig {
+	config := optimization.DefaultOptimizerConfig()
+	config.PopulationSize = 40
+	config.Generations = 40
+	config.MutationRate = 0.3
+	config.RandomSeed = 5
+	config.Objectives = []string{"f1", "f2"}
+	config.ObjectiveMinimize = []bool{true, true}
+	return config
+}
+
+func TestOptimizeParetoFindsSchafferFront(t *testing.T) {
+	params := []optimization.Parameter{
+		{Name: "x", Type: optimization.ParamTypeContinuous, Min: -10, Max: 10},
+	}
+
+	result, err := optimization.NewOptimizer(zap.NewNop(), paretoConfig()).
+		OptimizePareto(context.Background(), params, schaffer)
+	if err != nil {
+		t.Fatalf("OptimizePareto: %v", err)
+	}
+
+	if result.Method != optimization.MethodPareto {
+		t.Errorf("Method = %s, want pareto", result.Method)
+	}
+	if result.Iterations != 40*41 {
+		t.Errorf("Iterations = %d, want %d", result.Iterations, 40*41)
+	}
+	if len(result.ParetoFront) < 20 {
+		t.Fatalf("front has %d points, want a well-populated front", len(result.ParetoFront))
+	}
+
+	low, high := math.Inf(1), math.Inf(-1)
+	for i, point := range result.ParetoFront {
+		x := point.Params["x"]
+		if x < -0.05 || x > 2.05 {
+			t.Errorf("front point x = %.4f outside the Pareto set [0, 2]", x)
+		}
+		low, high = math.Min(low, x), math.Max(high, x)
+
+		if i > 0 && point.Scores["f1"] < result.ParetoFront[i-1].Scores["f1"] {
+			t.Errorf("front not sorted best-first on f1 at %d", i)
+		}
+		for _, other := range result.ParetoFront {
+			if other.Scores["f1"] <= point.Scores["f1"] && other.Scores["f2"] <= point.Scores["f2"] &&
+				(other.Scores["f1"] < point.Scores["f1"] || other.Scores["f2"] < point.Scores["f2"]) {
+				t.Fatalf("front point %v is dominated by %v", point.Scores, other.Scores)
+			}
+		}
+	}
+
+	// Crowding distance should keep the spread across the whole trade-off
+	if low > 0.2 || high < 1.8 {
+		t.Errorf("front spans x in [%.3f, %.3f], want close to [0, 2]", low, high)
+	}
+
+	first, last := result.ParetoFront[0], result.ParetoFront[len(result.ParetoFront)-1]
+	if first.CrowdingDistance != math.MaxFloat64 || last.CrowdingDistance != math.MaxFloat64 {
+		t.Error("front extremes should have maximal crowding distance")
+	}
+	if result.BestParams["x"] != first.Params["x"] || result.BestScore != first.Scores["f1"] {
+		t.Errorf("BestParams %v does not match the front's best f1 point %v", result.BestParams, first.Params)
+	}
+}
+
+func TestOptimizeParetoMixedDirections(t *testing.T) {
+	config := paretoConfig()
+	config.Objectives = []string{"sharpe", "max_drawdown"}
+	config.ObjectiveMinimize = []bool{false, true}
+
+	params := []optimization.Parameter{
+		{Name: "leverage", Type: optimization.ParamTypeContinuous, Min: 0, Max: 5},
+	}
+
+	// Leverage raises Sharpe up to 3, but drawdown grows with it throughout
+	objective := func(p optimization.ParamSet) (map[string]float64, error) {
+		l := p["leverage"]
+		return map[string]float64{
+			"sharpe":       math.Min(l, 3),
+			"max_drawdown": 0.1 * l,
+		}, nil
+	}
+
+	result, err := optimization.NewOptimizer(zap.NewNop(), config).
+		OptimizePareto(context.Background(), params, objective)
+	if err != nil {
+		t.Fatalf("OptimizePareto: %v", err)
+	}
+
+	for _, point := range result.ParetoFront {
+		if point.Params["leverage"] > 3.1 {
+			t.Errorf("leverage %.3f is dominated: more drawdown for no extra Sharpe", point.Params["leverage"])
+		}
+	}
+	if math.Abs(result.BestScore-3) > 0.05 {
+		t.Errorf("BestScore = %.3f, want the front's top Sharpe near 3", result.BestScore)
+	}
+}
+
+func TestOptimizeParetoRequiresObjectives(t *testing.T) {
+	config := paretoConfig()
+	config.Objectives = nil
+
+	opt := optimization.NewOptimizer(zap.NewNop(), config)
+	if _, err := opt.OptimizePareto(context.Background(), flatParams, schaffer); err == nil {
+		t.Error("expected an error without objectives")
+	}
+
+	config.Method = optimization.MethodPareto
+	if _, err := opt.Optimize(context.Background(), flatParams, flatObjective); err == nil {
+		t.Error("expected Optimize to reject the pareto method")
+	}
+}
@@ -0,0 +1,313 @@
+package optimization
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RunManifest records how an optimization run was configured
+type RunManifest struct {
+	Method      OptimizationMethod `json:"method"`
+	Config      OptimizerConfig    `json:"config"`
+	StartedAt   time.Time          `json:"started_at"`
+	CompletedAt time.Time          `json:"completed_at"`
+}
+
+// FoldCheckpoint is one walk-forward fold written to disk as it completes
+type FoldCheckpoint struct {
+	Manifest   *RunManifest       `json:"manifest"`
+	Fold       *WalkForwardFold   `json:"fold"`
+	AllResults []EvaluationResult `json:"all_results"`
+}
+
+// newManifest captures the config at the start of a run
+func newManifest(method OptimizationMethod, config *OptimizerConfig, startedAt time.Time) *RunManifest {
+	return &RunManifest{
+		Method:    method,
+		Config:    *config,
+		StartedAt: startedAt,
+	}
+}
+
+// SaveResult writes a result, including its manifest, as JSON
+func SaveResult(path string, result *OptimizationResult) error {
+	return writeJSONFile(path, result)
+}
+
+// LoadResult reads a result written by SaveResult
+func LoadResult(path string) (*OptimizationResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read result file: %w", err)
+	}
+
+	var result OptimizationResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal result: %w", err)
+	}
+	return &result, nil
+}
+
+// LoadFoldCheckpoints reads the folds a walk-forward run has written to dir,
+// in fold order, so an interrupted run's work can be recovered
+func LoadFoldCheckpoints(dir string) ([]*FoldCheckpoint, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "fold-*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fold checkpoints: %w", err)
+	}
+
+	checkpoints := make([]*FoldCheckpoint, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fold checkpoint: %w", err)
+		}
+
+		var checkpoint FoldCheckpoint
+		if err := json.Unmarshal(data, &checkpoint); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", filepath.Base(path), err)
+		}
+		if checkpoint.Fold == nil {
+			return nil, fmt.Errorf("fold checkpoint %s has no fold", filepath.Base(path))
+		}
+		checkpoints = append(checkpoints, &checkpoint)
+	}
+
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].Fold.FoldNumber < checkpoints[j].Fold.FoldNumber
+	})
+
+	return checkpoints, nil
+}
+
+// foldCheckpointPath is where a fold is written under CheckpointDir
+func foldCheckpointPath(dir string, foldNumber int) string {
+	return filepath.Join(dir, fmt.Sprintf("fold-%03d.json", foldNumber))
+}
+
+// writeJSONFile writes v through a temporary file and a rename, so a crash
+// mid-write never leaves a truncated file behind
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(path), err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// SetWarmStart seeds the next genetic or Pareto run's initial population with
+// up to count of the best distinct parameter sets from a prior result. Values
+// are fitted to the current parameter bounds; parameters the prior run did
+// not have are drawn at random. A nil prior clears the warm start.
+func (o *Optimizer) SetWarmStart(prior *OptimizationResult, count int) {
+	if prior == nil || count <= 0 {
+		o.warmStart = nil
+		return
+	}
+
+	evals := make([]EvaluationResult, len(prior.AllResults))
+	copy(evals, prior.AllResults)
+
+	sort.SliceStable(evals, func(i, j int) bool {
+		if o.config.MinimizationMode {
+			return evals[i].Score < evals[j].Score
+		}
+		return evals[i].Score > evals[j].Score
+	})
+
+	o.warmStart = make([]ParamSet, 0, count)
+	seen := make(map[string]bool)
+
+	for _, eval := range evals {
+		if len(o.warmStart) >= count {
+			break
+		}
+		if math.IsInf(eval.Score, 0) || math.IsNaN(eval.Score) {
+			continue
+		}
+
+		key := paramKey(eval.Params)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		o.warmStart = append(o.warmStart, o.copyParams(eval.Params))
+	}
+
+	o.logger.Info("warm start loaded",
+		zap.Int("individuals", len(o.warmStart)),
+	)
+}
+
+// fitParams adapts a prior parameter set to the current parameter space
+func (o *Optimizer) fitParams(params []Parameter, prior ParamSet) ParamSet {
+	fitted := make(ParamSet, len(params))
+
+	for _, param := range params {
+		v, ok := prior[param.Name]
+		if !ok {
+			fitted[param.Name] = o.randomParamValue(param)
+			continue
+		}
+
+		if param.Type == ParamTypeDiscrete && len(param.Discrete) > 0 {
+			found := false
+			for _, choice := range param.Discrete {
+				if choice == v {
+					found = true
+					break
+				}
+			}
+			if !found {
+				v = o.randomParamValue(param)
+			}
+			fitted[param.Name] = v
+			continue
+		}
+
+		v = math.Max(param.Min, math.Min(param.Max, v))
+		if param.Type == ParamTypeInteger {
+			v = math.Round(v)
+		}
+		fitted[param.Name] = v
+	}
+
+	return fitted
+}
+
+// paramKey is a stable string form of a parameter set, for deduplication
+func paramKey(params ParamSet) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	key := make([]byte, 0, 16*len(names))
+	for _, name := range names {
+		key = append(key, name...)
+		key = append(key, '=')
+		key = strconv.AppendFloat(key, params[name], 'g', -1, 64)
+		key = append(key, ';')
+	}
+	return string(key)
+}
+
+// jsonFloat encodes infinities and NaN as strings, which plain JSON numbers
+// cannot represent. Failed evaluations score as infinities, so results need
+// this to round-trip.
+type jsonFloat float64
+
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	v := float64(f)
+	switch {
+	case math.IsInf(v, 1):
+		return []byte(`"+Inf"`), nil
+	case math.IsInf(v, -1):
+		return []byte(`"-Inf"`), nil
+	case math.IsNaN(v):
+		return []byte(`"NaN"`), nil
+	}
+	return json.Marshal(v)
+}
+
+func (f *jsonFloat) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid score %q: %w", s, err)
+		}
+		*f = jsonFloat(v)
+		return nil
+	}
+
+	var v float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*f = jsonFloat(v)
+	return nil
+}
+
+type evaluationResultJSON EvaluationResult
+
+// MarshalJSON keeps non-finite scores representable
+func (e EvaluationResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		evaluationResultJSON
+		Score jsonFloat `json:"score"`
+	}{evaluationResultJSON(e), jsonFloat(e.Score)})
+}
+
+// UnmarshalJSON reads scores written by MarshalJSON
+func (e *EvaluationResult) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		*evaluationResultJSON
+		Score jsonFloat `json:"score"`
+	}
+	aux.evaluationResultJSON = (*evaluationResultJSON)(e)
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	e.Score = float64(aux.Score)
+	return nil
+}
+
+type optimizationResultJSON OptimizationResult
+
+// MarshalJSON keeps non-finite best and convergence scores representable
+func (r OptimizationResult) MarshalJSON() ([]byte, error) {
+	hist := make([]jsonFloat, len(r.ConvergenceHist))
+	for i, v := range r.ConvergenceHist {
+		hist[i] = jsonFloat(v)
+	}
+
+	return json.Marshal(struct {
+		optimizationResultJSON
+		BestScore       jsonFloat   `json:"best_score"`
+		ConvergenceHist []jsonFloat `json:"convergence_history"`
+	}{optimizationResultJSON(r), jsonFloat(r.BestScore), hist})
+}
+
+// UnmarshalJSON reads results written by MarshalJSON
+func (r *OptimizationResult) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		*optimizationResultJSON
+		BestScore       jsonFloat   `json:"best_score"`
+		ConvergenceHist []jsonFloat `json:"convergence_history"`
+	}
+	aux.optimizationResultJSON = (*optimizationResultJSON)(r)
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.BestScore = float64(aux.BestScore)
+	r.ConvergenceHist = make([]float64, len(aux.ConvergenceHist))
+	for i, v := range aux.ConvergenceHist {
+		r.ConvergenceHist[i] = float64(v)
+	}
+	return nil
+}
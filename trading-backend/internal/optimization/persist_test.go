@@ -0,0 +1,155 @@
+package optimization_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/optimization"
+	"go.uber.org/zap"
+)
+
+// peakAt scores -(x-0.3)^2 and fails for x above 0.9, so genetic results
+// carry -Inf scores for the failures
+func peakAt(p optimization.ParamSet) (float64, error) {
+	if p["x"] > 0.9 {
+		return 0, errors.New("unstable")
+	}
+	return -math.Pow(p["x"]-0.3, 2), nil
+}
+
+func TestSaveAndLoadResult(t *testing.T) {
+	config := geneticConfig(0)
+	config.Generations = 5
+
+	result, err := optimization.NewOptimizer(zap.NewNop(), config).
+		Optimize(context.Background(), flatParams, peakAt)
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "runs", "result.json")
+	if err := optimization.SaveResult(path, result); err != nil {
+		t.Fatalf("SaveResult: %v", err)
+	}
+
+	loaded, err := optimization.LoadResult(path)
+	if err != nil {
+		t.Fatalf("LoadResult: %v", err)
+	}
+
+	if loaded.BestScore != result.BestScore || loaded.BestParams["x"] != result.BestParams["x"] {
+		t.Errorf("best = %v %v, want %v %v", loaded.BestScore, loaded.BestParams, result.BestScore, result.BestParams)
+	}
+	if len(loaded.AllResults) != len(result.AllResults) || len(loaded.ConvergenceHist) != len(result.ConvergenceHist) {
+		t.Fatalf("loaded %d results and %d history entries, want %d and %d",
+			len(loaded.AllResults), len(loaded.ConvergenceHist), len(result.AllResults), len(result.ConvergenceHist))
+	}
+
+	failures := 0
+	for i, eval := range loaded.AllResults {
+		if eval.Score != result.AllResults[i].Score {
+			t.Fatalf("result %d score = %v, want %v", i, eval.Score, result.AllResults[i].Score)
+		}
+		if math.IsInf(eval.Score, -1) {
+			failures++
+		}
+	}
+	if failures == 0 {
+		t.Error("expected failed evaluations to round-trip as -Inf")
+	}
+
+	if loaded.Manifest == nil {
+		t.Fatal("manifest missing")
+	}
+	if loaded.Manifest.Method != optimization.MethodGeneticAlgo || loaded.Manifest.Config.PopulationSize != 10 {
+		t.Errorf("manifest = %+v, want the genetic run's config", loaded.Manifest)
+	}
+	if loaded.Manifest.StartedAt.IsZero() || loaded.Manifest.CompletedAt.Before(loaded.Manifest.StartedAt) {
+		t.Errorf("manifest timestamps %v to %v", loaded.Manifest.StartedAt, loaded.Manifest.CompletedAt)
+	}
+}
+
+func TestWarmStartSeedsPopulation(t *testing.T) {
+	prior := &optimization.OptimizationResult{
+		AllResults: []optimization.EvaluationResult{
+			{Params: optimization.ParamSet{"x": 0.8}, Score: -0.25},
+			{Params: optimization.ParamSet{"x": 0.3}, Score: 0},
+			{Params: optimization.ParamSet{"x": 0.95}, Score: math.Inf(-1)},
+		},
+	}
+
+	config := geneticConfig(0)
+	config.Generations = 1
+
+	cold, err := optimization.NewOptimizer(zap.NewNop(), config).
+		Optimize(context.Background(), flatParams, peakAt)
+	if err != nil {
+		t.Fatalf("cold Optimize: %v", err)
+	}
+	if cold.BestScore == 0 {
+		t.Fatal("cold start hit the optimum by chance; pick another seed")
+	}
+
+	opt := optimization.NewOptimizer(zap.NewNop(), config)
+	opt.SetWarmStart(prior, 1)
+
+	warm, err := opt.Optimize(context.Background(), flatParams, peakAt)
+	if err != nil {
+		t.Fatalf("warm Optimize: %v", err)
+	}
+	if warm.BestScore != 0 || warm.BestParams["x"] != 0.3 {
+		t.Errorf("warm start best = %v at %v, want the prior's 0 at x=0.3", warm.BestScore, warm.BestParams)
+	}
+}
+
+func TestWalkForwardCheckpointsFolds(t *testing.T) {
+	dir := t.TempDir()
+	config := walkForwardConfig(2)
+	config.CheckpointDir = dir
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fullRange := optimization.DataRange{Start: start, End: start.Add(100 * 24 * time.Hour)}
+	objective := func(p optimization.ParamSet, r optimization.DataRange) (float64, error) {
+		return -math.Abs(p["x"] - 0.5), nil
+	}
+
+	result, err := optimization.NewWalkForwardOptimizer(zap.NewNop(), config).
+		OptimizeWalkForward(context.Background(), flatParams, objective, fullRange)
+	if err != nil {
+		t.Fatalf("OptimizeWalkForward: %v", err)
+	}
+
+	checkpoints, err := optimization.LoadFoldCheckpoints(dir)
+	if err != nil {
+		t.Fatalf("LoadFoldCheckpoints: %v", err)
+	}
+	if len(checkpoints) != 5 {
+		t.Fatalf("got %d fold checkpoints, want 5", len(checkpoints))
+	}
+	for i, checkpoint := range checkpoints {
+		want := result.WalkForwardResults[i]
+		if checkpoint.Fold.FoldNumber != i+1 || checkpoint.Fold.OutSampleScore != want.OutSampleScore {
+			t.Errorf("checkpoint %d = fold %d scoring %v, want fold %d scoring %v",
+				i, checkpoint.Fold.FoldNumber, checkpoint.Fold.OutSampleScore, i+1, want.OutSampleScore)
+		}
+		if len(checkpoint.AllResults) != 30 || checkpoint.Manifest.Method != optimization.MethodWalkForward {
+			t.Errorf("checkpoint %d has %d evaluations and method %s", i, len(checkpoint.AllResults), checkpoint.Manifest.Method)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "manifest.json")); err != nil {
+		t.Errorf("manifest not written: %v", err)
+	}
+	saved, err := optimization.LoadResult(filepath.Join(dir, "result.json"))
+	if err != nil {
+		t.Fatalf("LoadResult: %v", err)
+	}
+	if saved.OOSPerformance != result.OOSPerformance || len(saved.WalkForwardResults) != 5 {
+		t.Errorf("saved result does not match the run")
+	}
+}
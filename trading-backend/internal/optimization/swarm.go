@@ -0,0 +1,168 @@
+package optimization
+
+import (
+	"context"
+	"math"
+
+	"go.uber.org/zap"
+)
+
+// maxVelocity caps each particle's per-iteration move in unit space, so the
+// swarm cannot fly across the whole space in one step
+const maxVelocity = 0.2
+
+// particle is one member of the swarm, positioned in the unit cube.
+type particle struct {
+	position []float64
+	velocity []float64
+	best     []float64 // Best position the particle has found
+	bestGain float64   // Score there, oriented so higher is better
+}
+
+// particleSwarm runs particle swarm optimization: SwarmSize particles move
+// through the unit cube, each pulled toward its own best position by
+// CognitiveCoeff and toward the swarm's best by SocialCoeff, while Inertia
+// keeps part of its previous velocity. The swarm is evaluated each iteration,
+// ParallelWorkers particles at a time, until MaxIterations evaluations have
+// been spent.
+func (o *Optimizer) particleSwarm(ctx context.Context, params []Parameter, objective ObjectiveFunc) (*OptimizationResult, error) {
+	result := &OptimizationResult{
+		AllResults:      make([]EvaluationResult, 0),
+		ConvergenceHist: make([]float64, 0),
+	}
+
+	workers := o.config.ParallelWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	size := o.config.SwarmSize
+	if size < 2 {
+		size = 2
+	}
+	budget := o.config.MaxIterations
+
+	o.logger.Info("starting particle swarm",
+		zap.Int("max_iterations", budget),
+		zap.Int("swarm_size", size),
+		zap.Float64("inertia", o.config.Inertia),
+	)
+
+	bestScore := math.Inf(-1)
+	if o.config.MinimizationMode {
+		bestScore = math.Inf(1)
+	}
+
+	gain := func(score float64) float64 {
+		if o.config.MinimizationMode {
+			return -score
+		}
+		return score
+	}
+
+	// Warm-start seeds take the first positions
+	swarm := make([]*particle, size)
+	for i := range swarm {
+		position := o.randomUnitPoint(len(params))
+		if i < len(o.warmStart) {
+			position = encodeParams(params, o.fitParams(params, o.warmStart[i]))
+		}
+
+		velocity := make([]float64, len(params))
+		for d := range velocity {
+			velocity[d] = (o.rng.Float64()*2 - 1) * maxVelocity
+		}
+
+		swarm[i] = &particle{
+			position: position,
+			velocity: velocity,
+			bestGain: math.Inf(-1),
+		}
+	}
+
+	var globalBest []float64
+	globalGain := math.Inf(-1)
+	evaluated := 0
+
+	for evaluated < budget {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		default:
+		}
+
+		// The last iteration evaluates only as many particles as the budget
+		// has left
+		active := swarm
+		if remaining := budget - evaluated; len(active) > remaining {
+			active = active[:remaining]
+		}
+
+		batch := make([]ParamSet, len(active))
+		for i, p := range active {
+			batch[i] = decodeParams(params, p.position)
+		}
+
+		for i, ev := range o.evaluateInBatches(batch, objective, workers) {
+			evaluated++
+			if ev.err != nil {
+				continue
+			}
+
+			result.AllResults = append(result.AllResults, EvaluationResult{
+				Params:    ev.params,
+				Score:     ev.score,
+				Iteration: evaluated - 1,
+				Duration:  ev.duration,
+			})
+
+			p := active[i]
+			if g := gain(ev.score); g > p.bestGain {
+				p.bestGain = g
+				p.best = append([]float64(nil), p.position...)
+				if g > globalGain {
+					globalGain = g
+					globalBest = p.best
+					bestScore = ev.score
+					result.BestParams = ev.params
+					result.BestScore = ev.score
+				}
+			}
+
+			result.ConvergenceHist = append(result.ConvergenceHist, bestScore)
+			result.Iterations++
+		}
+
+		if globalBest == nil {
+			continue
+		}
+
+		for _, p := range swarm {
+			o.moveParticle(p, globalBest)
+		}
+	}
+
+	return result, nil
+}
+
+// moveParticle updates a particle's velocity and position toward its own
+// best position and the swarm's. A particle that has not scored yet is
+// pulled by the swarm alone. Particles stop at the edge of the unit cube.
+func (o *Optimizer) moveParticle(p *particle, globalBest []float64) {
+	for d := range p.position {
+		velocity := o.config.Inertia * p.velocity[d]
+		if p.best != nil {
+			velocity += o.config.CognitiveCoeff * o.rng.Float64() * (p.best[d] - p.position[d])
+		}
+		velocity += o.config.SocialCoeff * o.rng.Float64() * (globalBest[d] - p.position[d])
+		velocity = math.Max(-maxVelocity, math.Min(maxVelocity, velocity))
+
+		position := p.position[d] + velocity
+		if position < 0 || position > 1 {
+			position = clampUnit(position)
+			velocity = 0
+		}
+
+		p.position[d] = position
+		p.velocity[d] = velocity
+	}
+}
@@ -0,0 +1,56 @@
+package optimization_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/optimization"
+	"go.uber.org/zap"
+)
+
+func TestParticleSwarmEscapesRastriginLocalMinima(t *testing.T) {
+	grid := optimizeRastrigin(t, rastriginConfig(optimization.MethodGridSearch))
+
+	result := optimizeRastrigin(t, rastriginConfig(optimization.MethodSwarm))
+
+	checkRun(t, result, rastriginBudget)
+	if result.Method != optimization.MethodSwarm {
+		t.Errorf("Method = %s, want swarm", result.Method)
+	}
+	if result.BestScore > rastriginBasin || result.BestScore >= grid.BestScore {
+		t.Errorf("BestScore = %.4f, want the global minimum (grid search: %.4f)", result.BestScore, grid.BestScore)
+	}
+}
+
+func TestParticleSwarmSkipsFailedEvaluations(t *testing.T) {
+	config := rastriginConfig(optimization.MethodSwarm)
+	config.MaxIterations = 300
+	config.SwarmSize = 20
+
+	// Half the space is infeasible
+	objective := func(p optimization.ParamSet) (float64, error) {
+		if p["x"] < 0 {
+			return 0, errors.New("infeasible")
+		}
+		return rastrigin(p)
+	}
+
+	result, err := optimization.NewOptimizer(zap.NewNop(), config).
+		Optimize(context.Background(), rastriginParams, objective)
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+
+	if len(result.AllResults) == 0 || len(result.AllResults) >= 300 {
+		t.Fatalf("AllResults = %d, want only the feasible evaluations", len(result.AllResults))
+	}
+	for _, res := range result.AllResults {
+		if res.Params["x"] < 0 {
+			t.Fatalf("failed evaluation recorded: %v", res.Params)
+		}
+	}
+	if result.BestParams["x"] < 0 || result.Iterations != len(result.AllResults) {
+		t.Errorf("BestParams = %v, Iterations = %d", result.BestParams, result.Iterations)
+	}
+}
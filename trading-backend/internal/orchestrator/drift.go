@@ -0,0 +1,372 @@
+// Package orchestrator provides detection of live results drifting from
+// their backtest.
+package orchestrator
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/events"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// Drift alert severities, from least to most divergent.
+const (
+	DriftSeverityNone     = ""
+	DriftSeverityInfo     = "info"
+	DriftSeverityWarning  = "warning"
+	DriftSeverityCritical = "critical"
+)
+
+// driftAlertType is the risk_alert type drift is reported under.
+const driftAlertType = "strategy_drift"
+
+// DriftConfig configures backtest-vs-live drift detection. Thresholds are
+// z-scores of the live trailing window against the backtest, counting only
+// divergence in the adverse direction.
+type DriftConfig struct {
+	Window    int     `json:"window"`    // Trailing live trades and fills compared to the backtest
+	MinTrades int     `json:"minTrades"` // Live trades, and backtest trades, needed to judge drift
+	InfoZ     float64 `json:"infoZ"`
+	WarningZ  float64 `json:"warningZ"`
+	CriticalZ float64 `json:"criticalZ"` // Also the confidence band strategies are deactivated outside
+}
+
+// DefaultDriftConfig returns the default drift detection config.
+func DefaultDriftConfig() DriftConfig {
+	return DriftConfig{
+		Window:    50,
+		MinTrades: 20,
+		InfoZ:     1.5,
+		WarningZ:  2.0,
+		CriticalZ: 3.0, // ~99.7% band
+	}
+}
+
+// DriftReport compares a strategy's trailing live results with its backtest.
+// R is measured in units of the backtest's average loss, since neither live
+// fills nor backtest trades carry their initial risk. Z-scores are positive
+// when live results are worse than the backtest.
+type DriftReport struct {
+	StrategyID       string    `json:"strategyId"`
+	LiveTrades       int       `json:"liveTrades"`
+	LiveWinRate      float64   `json:"liveWinRate"`
+	BacktestWinRate  float64   `json:"backtestWinRate"`
+	LiveAvgR         float64   `json:"liveAvgR"`
+	BacktestAvgR     float64   `json:"backtestAvgR"`
+	LiveSlippage     float64   `json:"liveSlippage"`
+	BacktestSlippage float64   `json:"backtestSlippage"`
+	WinRateZ         float64   `json:"winRateZ"`
+	AvgRZ            float64   `json:"avgRZ"`
+	SlippageZ        float64   `json:"slippageZ"`
+	Divergence       float64   `json:"divergence"` // Largest of the z-scores
+	Severity         string    `json:"severity"`
+	CheckedAt        time.Time `json:"checkedAt"`
+}
+
+// driftBaseline is the distribution of a strategy's backtested trades.
+type driftBaseline struct {
+	winRate      float64
+	riskUnit     float64 // Average losing trade, the PnL of -1R
+	meanR        float64
+	stdR         float64
+	meanSlippage float64
+	stdSlippage  float64
+}
+
+// driftState is a strategy's baseline and trailing live results.
+type driftState struct {
+	baseline *driftBaseline
+	pnls     []float64
+	slippage []float64
+	report   DriftReport
+}
+
+// DriftDetector compares each strategy's trailing live win rate, average R
+// and slippage against its backtested distribution with z-tests, catching
+// strategies that were overfit or whose market has moved on.
+type DriftDetector struct {
+	logger *zap.Logger
+	config DriftConfig
+
+	mu         sync.RWMutex
+	strategies map[string]*driftState
+}
+
+// NewDriftDetector creates a new drift detector.
+func NewDriftDetector(logger *zap.Logger, config DriftConfig) *DriftDetector {
+	return &DriftDetector{
+		logger:     logger,
+		config:     config,
+		strategies: make(map[string]*driftState),
+	}
+}
+
+// SetBaseline sets the backtested trades a strategy's live results are
+// compared against. Trades without PnL, such as opening fills, count toward
+// slippage only. It returns false, leaving any previous baseline in place,
+// if the backtest has fewer than MinTrades closed trades or no losses to
+// measure R by.
+func (d *DriftDetector) SetBaseline(strategyID string, trades []types.Trade) bool {
+	var pnls, slippage []float64
+	var wins int
+	var losses, lossCount float64
+	for _, trade := range trades {
+		slippage = append(slippage, trade.Slippage.InexactFloat64())
+		pnl := trade.PnL.InexactFloat64()
+		if pnl == 0 {
+			continue
+		}
+		pnls = append(pnls, pnl)
+		if pnl > 0 {
+			wins++
+		} else {
+			losses -= pnl
+			lossCount++
+		}
+	}
+	if len(pnls) == 0 || len(pnls) < d.config.MinTrades || lossCount == 0 {
+		return false
+	}
+
+	baseline := &driftBaseline{
+		winRate:  float64(wins) / float64(len(pnls)),
+		riskUnit: losses / lossCount,
+	}
+	r := make([]float64, len(pnls))
+	for i, pnl := range pnls {
+		r[i] = pnl / baseline.riskUnit
+	}
+	baseline.meanR, baseline.stdR = meanStdDev(r)
+	baseline.meanSlippage, baseline.stdSlippage = meanStdDev(slippage)
+
+	d.mu.Lock()
+	d.stateLocked(strategyID).baseline = baseline
+	d.mu.Unlock()
+	return true
+}
+
+// RecordTrade adds a closed live trade's PnL to a strategy's trailing window.
+func (d *DriftDetector) RecordTrade(strategyID string, pnl float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state := d.stateLocked(strategyID)
+	state.pnls = d.trail(append(state.pnls, pnl))
+}
+
+// RecordSlippage adds a live fill's slippage, as a fraction of the expected
+// price, to a strategy's trailing window.
+func (d *DriftDetector) RecordSlippage(strategyID string, slippage float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state := d.stateLocked(strategyID)
+	state.slippage = d.trail(append(state.slippage, math.Abs(slippage)))
+}
+
+// Evaluate re-tests a strategy's trailing live results against its
+// backtest. escalated reports whether the severity rose since the last
+// evaluation, so each level of divergence is alerted once.
+func (d *DriftDetector) Evaluate(strategyID string) (report DriftReport, escalated bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state, ok := d.strategies[strategyID]
+	if !ok {
+		return DriftReport{StrategyID: strategyID}, false
+	}
+
+	previous := state.report.Severity
+	state.report = d.compare(strategyID, state)
+	return state.report, driftSeverityRank(state.report.Severity) > driftSeverityRank(previous)
+}
+
+// GetReport returns a strategy's last evaluation.
+func (d *DriftDetector) GetReport(strategyID string) (DriftReport, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	state, ok := d.strategies[strategyID]
+	if !ok {
+		return DriftReport{}, false
+	}
+	return state.report, true
+}
+
+// Clear forgets a strategy's baseline and live results.
+func (d *DriftDetector) Clear(strategyID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.strategies, strategyID)
+}
+
+// compare z-tests the live windows against the baseline. The caller must
+// hold the write lock.
+func (d *DriftDetector) compare(strategyID string, state *driftState) DriftReport {
+	report := DriftReport{
+		StrategyID: strategyID,
+		LiveTrades: len(state.pnls),
+		CheckedAt:  time.Now(),
+	}
+	baseline := state.baseline
+	if baseline == nil {
+		return report
+	}
+	report.BacktestWinRate = baseline.winRate
+	report.BacktestAvgR = baseline.meanR
+	report.BacktestSlippage = baseline.meanSlippage
+
+	if n := float64(len(state.pnls)); len(state.pnls) > 0 && len(state.pnls) >= d.config.MinTrades {
+		var wins int
+		r := make([]float64, len(state.pnls))
+		for i, pnl := range state.pnls {
+			if pnl > 0 {
+				wins++
+			}
+			r[i] = pnl / baseline.riskUnit
+		}
+		report.LiveWinRate = float64(wins) / n
+		report.LiveAvgR, _ = meanStdDev(r)
+
+		// A backtest that never lost, or always did, still has some uncertainty
+		p := math.Min(math.Max(baseline.winRate, 0.01), 0.99)
+		report.WinRateZ = (baseline.winRate - report.LiveWinRate) / math.Sqrt(p*(1-p)/n)
+		if baseline.stdR > 0 {
+			report.AvgRZ = (baseline.meanR - report.LiveAvgR) / (baseline.stdR / math.Sqrt(n))
+		}
+	}
+
+	if m := float64(len(state.slippage)); len(state.slippage) > 0 && len(state.slippage) >= d.config.MinTrades {
+		report.LiveSlippage, _ = meanStdDev(state.slippage)
+
+		// Floored so a backtest with a fixed slippage model doesn't turn any
+		// extra live slippage into drift
+		std := math.Max(baseline.stdSlippage, math.Max(baseline.meanSlippage*0.25, 0.0001))
+		report.SlippageZ = (report.LiveSlippage - baseline.meanSlippage) / (std / math.Sqrt(m))
+	}
+
+	report.Divergence = math.Max(report.WinRateZ, math.Max(report.AvgRZ, report.SlippageZ))
+	switch {
+	case report.Divergence >= d.config.CriticalZ:
+		report.Severity = DriftSeverityCritical
+	case report.Divergence >= d.config.WarningZ:
+		report.Severity = DriftSeverityWarning
+	case report.Divergence >= d.config.InfoZ:
+		report.Severity = DriftSeverityInfo
+	}
+	return report
+}
+
+// stateLocked returns a strategy's state, creating it if needed. The caller
+// must hold the write lock.
+func (d *DriftDetector) stateLocked(strategyID string) *driftState {
+	state, ok := d.strategies[strategyID]
+	if !ok {
+		state = &driftState{report: DriftReport{StrategyID: strategyID}}
+		d.strategies[strategyID] = state
+	}
+	return state
+}
+
+// trail keeps the last Window values.
+func (d *DriftDetector) trail(values []float64) []float64 {
+	if d.config.Window > 0 && len(values) > d.config.Window {
+		return values[len(values)-d.config.Window:]
+	}
+	return values
+}
+
+func driftSeverityRank(severity string) int {
+	switch severity {
+	case DriftSeverityInfo:
+		return 1
+	case DriftSeverityWarning:
+		return 2
+	case DriftSeverityCritical:
+		return 3
+	}
+	return 0
+}
+
+// meanStdDev returns the mean and sample standard deviation of values.
+func meanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+
+	var squared float64
+	for _, v := range values {
+		squared += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(squared / float64(len(values)-1))
+}
+
+// checkDrift evaluates a strategy for drift after a live trade or fill,
+// raising a risk alert when divergence escalates and, if
+// DeactivateOnDrift is set, deactivating the strategy once it leaves the
+// critical band.
+func (o *TradingOrchestrator) checkDrift(strategyID string) {
+	report, escalated := o.driftDetector.Evaluate(strategyID)
+	if !escalated {
+		return
+	}
+
+	thresholds := map[string]float64{
+		DriftSeverityInfo:     o.config.Drift.InfoZ,
+		DriftSeverityWarning:  o.config.Drift.WarningZ,
+		DriftSeverityCritical: o.config.Drift.CriticalZ,
+	}
+	message := fmt.Sprintf("Strategy %s live results diverge from its backtest (z %.2f): win rate %.1f%% vs %.1f%%, avg R %.2f vs %.2f, slippage %.3f%% vs %.3f%%",
+		strategyID, report.Divergence,
+		report.LiveWinRate*100, report.BacktestWinRate*100,
+		report.LiveAvgR, report.BacktestAvgR,
+		report.LiveSlippage*100, report.BacktestSlippage*100)
+
+	o.logger.Warn("Strategy drift detected",
+		zap.String("strategyId", strategyID),
+		zap.String("severity", report.Severity),
+		zap.Float64("winRateZ", report.WinRateZ),
+		zap.Float64("avgRZ", report.AvgRZ),
+		zap.Float64("slippageZ", report.SlippageZ),
+	)
+	o.eventBus.Publish(events.NewRiskAlertEvent(driftAlertType, report.Severity, message,
+		decimal.NewFromFloat(report.Divergence), decimal.NewFromFloat(thresholds[report.Severity])))
+
+	if report.Severity == DriftSeverityCritical && o.config.DeactivateOnDrift {
+		o.mu.Lock()
+		if strategy, exists := o.activeStrategies[strategyID]; exists {
+			strategy.IsActive = false
+		}
+		o.mu.Unlock()
+		o.logger.Error("Strategy deactivated for drifting from its backtest",
+			zap.String("strategyId", strategyID))
+	}
+}
+
+// driftedOut reports whether a strategy is held inactive for drift.
+func (o *TradingOrchestrator) driftedOut(strategyID string) bool {
+	if !o.config.DeactivateOnDrift {
+		return false
+	}
+	report, ok := o.driftDetector.GetReport(strategyID)
+	return ok && report.Severity == DriftSeverityCritical
+}
+
+// GetDriftReport returns the last comparison of a strategy's live results
+// with its backtest.
+func (o *TradingOrchestrator) GetDriftReport(strategyID string) (DriftReport, bool) {
+	return o.driftDetector.GetReport(strategyID)
+}
@@ -0,0 +1,99 @@
+package orchestrator_test
+
+import (
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/orchestrator"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// backtestTrades wins 60% of trades at +2R against a 100 loss, slipping 0.1%
+func backtestTrades() []types.Trade {
+	var trades []types.Trade
+	for i := 0; i < 100; i++ {
+		pnl := decimal.NewFromInt(-100)
+		if i%5 < 3 {
+			pnl = decimal.NewFromInt(200)
+		}
+		trades = append(trades, types.Trade{PnL: pnl, Slippage: decimal.NewFromFloat(0.001)})
+	}
+	return trades
+}
+
+func TestDriftDetectorEscalatesAsLiveResultsDiverge(t *testing.T) {
+	config := orchestrator.DefaultDriftConfig()
+	config.Window = 20
+	config.MinTrades = 20
+	detector := orchestrator.NewDriftDetector(zap.NewNop(), config)
+
+	if !detector.SetBaseline("trend", backtestTrades()) {
+		t.Fatal("Expected 100 backtest trades to set a baseline")
+	}
+
+	// Live trading in line with the backtest
+	for i := 0; i < 20; i++ {
+		pnl := -100.0
+		if i%5 < 3 {
+			pnl = 200
+		}
+		detector.RecordTrade("trend", pnl)
+		detector.RecordSlippage("trend", 0.001)
+	}
+	report, escalated := detector.Evaluate("trend")
+	if escalated || report.Severity != orchestrator.DriftSeverityNone {
+		t.Fatalf("Expected no drift matching the backtest, got %+v", report)
+	}
+	if report.BacktestWinRate != 0.6 || report.BacktestAvgR != 0.8 || report.LiveAvgR != 0.8 {
+		t.Errorf("Expected a 60%% win rate at 0.8R on both, got %+v", report)
+	}
+
+	// Half the window turns into losses, then all of it
+	for i := 0; i < 10; i++ {
+		detector.RecordTrade("trend", -100)
+	}
+	report, escalated = detector.Evaluate("trend")
+	if !escalated || report.Severity != orchestrator.DriftSeverityWarning || report.LiveWinRate != 0.3 {
+		t.Fatalf("Expected a warning at a 30%% win rate, got %+v", report)
+	}
+	if _, escalated = detector.Evaluate("trend"); escalated {
+		t.Error("Expected the same severity not to escalate again")
+	}
+
+	for i := 0; i < 10; i++ {
+		detector.RecordTrade("trend", -100)
+	}
+	report, escalated = detector.Evaluate("trend")
+	if !escalated || report.Severity != orchestrator.DriftSeverityCritical || report.WinRateZ < config.CriticalZ {
+		t.Fatalf("Expected critical drift in win rate, got %+v", report)
+	}
+}
+
+func TestDriftDetectorFlagsSlippage(t *testing.T) {
+	config := orchestrator.DefaultDriftConfig()
+	detector := orchestrator.NewDriftDetector(zap.NewNop(), config)
+	detector.SetBaseline("grid", backtestTrades())
+
+	for i := 0; i < config.MinTrades; i++ {
+		detector.RecordSlippage("grid", 0.0011)
+	}
+	if report, _ := detector.Evaluate("grid"); report.Severity != orchestrator.DriftSeverityInfo {
+		t.Errorf("Expected slightly worse slippage to be informational, got %+v", report)
+	}
+
+	for i := 0; i < config.MinTrades; i++ {
+		detector.RecordSlippage("grid", -0.003)
+	}
+	report, _ := detector.Evaluate("grid")
+	if report.Severity != orchestrator.DriftSeverityCritical || report.SlippageZ < config.CriticalZ {
+		t.Errorf("Expected adverse slippage either side of the price to be critical, got %+v", report)
+	}
+	if report.LiveTrades != 0 || report.WinRateZ != 0 {
+		t.Errorf("Expected no trade comparison without closed trades, got %+v", report)
+	}
+
+	if detector.SetBaseline("thin", backtestTrades()[:10]) {
+		t.Error("Expected too few backtest trades to be rejected")
+	}
+}
@@ -0,0 +1,60 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func TestEvaluateStrategyValidatesBacktestedTrades(t *testing.T) {
+	config := DefaultOrchestratorConfig()
+	config.MonteCarloRuns = 200
+	config.EventLogDir = t.TempDir()
+	o, err := NewTradingOrchestrator(zap.NewNop(), config, nil, nil)
+	if err != nil {
+		t.Fatalf("NewTradingOrchestrator failed: %v", err)
+	}
+
+	// Evaluation is skipped until a runner is set
+	o.RegisterStrategy("cycle", map[string]float64{"period": 4})
+	o.evaluateStrategy(context.Background(), "cycle")
+	if n := len(o.GetTradeHistory().PnLs("cycle")); n != 0 {
+		t.Fatalf("Expected no history without a runner, got %d trades", n)
+	}
+
+	var gotID string
+	var gotParams map[string]float64
+	o.SetBacktestRunner(func(ctx context.Context, strategyID string, params map[string]float64) (*types.BacktestResult, error) {
+		gotID, gotParams = strategyID, params
+
+		// Wins 60% of trades at +200 against a 100 loss
+		trades := make([]types.Trade, 40)
+		for i := range trades {
+			trades[i].PnL = decimal.NewFromInt(-100)
+			if i%5 < 3 {
+				trades[i].PnL = decimal.NewFromInt(200)
+			}
+		}
+		return &types.BacktestResult{
+			Config: &types.BacktestConfig{InitialCapital: decimal.NewFromInt(10000)},
+			Trades: trades,
+		}, nil
+	})
+	o.evaluateStrategy(context.Background(), "cycle")
+
+	if gotID != "cycle" || gotParams["period"] != 4 {
+		t.Errorf("Expected the runner called for cycle with its params, got %s %v", gotID, gotParams)
+	}
+	if n := len(o.GetTradeHistory().PnLs("cycle")); n != 40 {
+		t.Errorf("Expected the 40 backtested trades in Monte Carlo history, got %d", n)
+	}
+	if runs := o.GetMetrics().MonteCarloRuns; runs != 1 {
+		t.Errorf("Expected one Monte Carlo validation, got %d", runs)
+	}
+	if state := o.GetActiveStrategies()["cycle"]; state.RobustnessScore <= 0 {
+		t.Errorf("Expected a robustness score from the validation, got %v", state.RobustnessScore)
+	}
+}
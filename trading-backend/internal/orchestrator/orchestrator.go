@@ -6,6 +6,8 @@ package orchestrator
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -18,6 +20,7 @@ import (
 	"github.com/atlas-desktop/trading-backend/internal/signals"
 	"github.com/atlas-desktop/trading-backend/internal/sizing"
 	"github.com/atlas-desktop/trading-backend/internal/workers"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
@@ -29,12 +32,19 @@ type TradingOrchestrator struct {
 
 	// Core PhD-level components
 	eventBus       *events.EventBus
-	regimeDetector *regime.HMMRegimeDetector
+	regimeDetector *regime.HMMRegimeDetector // Maps regimes to strategy adjustments; state is per symbol
 	positionSizer  *sizing.MultiStrategyPositionSizer
+	riskParity     *sizing.RiskParitySizer
+	cvarSizer      *sizing.CVaRSizer
+	volEstimator   *sizing.VolatilityEstimator
+	corrEstimator  *sizing.CorrelationEstimator
 	monteCarloSim  *montecarlo.Simulator
+	tradeHistory   *montecarlo.TradeHistory
 	optimizer      *optimization.WalkForwardOptimizer
 	workerPool     *workers.Pool
 	viabilityCheck *backtester.ViabilityChecker
+	backtestRunner BacktestRunner
+	driftDetector  *DriftDetector
 
 	// Existing components integration
 	signalAggregator *signals.Aggregator
@@ -42,9 +52,12 @@ type TradingOrchestrator struct {
 	executionModeler *execution.ExecutionModel
 
 	// State tracking
-	mu            sync.RWMutex
-	currentRegime regime.RegimeType
-	regimeHistory []RegimeTransition
+	mu                sync.RWMutex
+	regimeConfig      regime.HMMConfig
+	symbolRegimes     map[string]*symbolRegime // Normalized symbol -> regime
+	currentRegime     regime.RegimeType        // Portfolio-level aggregate
+	currentRegimeProb float64
+	regimeHistory     []RegimeTransition
 
 	// Strategy state
 	activeStrategies map[string]*StrategyState
@@ -57,27 +70,47 @@ type TradingOrchestrator struct {
 	stopCh  chan struct{}
 }
 
+// BacktestRunner backtests a strategy with the given parameters, typically by
+// running a backtester.Engine over recent history.
+type BacktestRunner func(ctx context.Context, strategyID string, params map[string]float64) (*types.BacktestResult, error)
+
 // OrchestratorConfig configures the orchestrator.
 type OrchestratorConfig struct {
 	// Event Bus Configuration
-	EventWorkers    int `json:"eventWorkers"`
-	EventBufferSize int `json:"eventBufferSize"`
+	EventWorkers    int    `json:"eventWorkers"`
+	EventBufferSize int    `json:"eventBufferSize"`
+	EventPartitions int    `json:"eventPartitions"` // Per-symbol ordered queues; replaces EventWorkers, 0 disables
+	EventLogDir     string `json:"eventLogDir"`     // Write-ahead event log; empty disables
 
 	// Regime Detection
 	RegimeDetectionInterval time.Duration `json:"regimeDetectionInterval"`
 	RegimeLookbackBars      int           `json:"regimeLookbackBars"`
 	RegimeMinProbability    float64       `json:"regimeMinProbability"`
+	RegimeModelDir          string        `json:"regimeModelDir"`    // Model checkpoints; empty disables
+	GARCHEnabled            bool          `json:"garchEnabled"`      // Forecast each symbol's volatility with GARCH(1,1)
+	GARCHRegimeWeight       float64       `json:"garchRegimeWeight"` // Weight of the GARCH volatility regime in blended adjustments; 0 uses the HMM alone
 
 	// Position Sizing
-	DefaultSizingStrategy string          `json:"defaultSizingStrategy"` // "kelly", "volatility", "risk_budget"
+	DefaultSizingStrategy string          `json:"defaultSizingStrategy"` // "kelly", "volatility", "risk_budget", "risk_parity", "cvar"
 	MaxPositionSize       decimal.Decimal `json:"maxPositionSize"`
 	KellyFraction         float64         `json:"kellyFraction"`
 	TargetVolatility      float64         `json:"targetVolatility"`
+	MaxExpectedShortfall  float64         `json:"maxExpectedShortfall"` // CVaR limit per position as % of portfolio
+	CVaRConfidence        float64         `json:"cvarConfidence"`
+	VolatilityDecay       float64         `json:"volatilityDecay"`    // EWMA lambda for realized volatility
+	VolatilityWindow      int             `json:"volatilityWindow"`   // Bars in the rolling realized volatility
+	CorrelationDecay      float64         `json:"correlationDecay"`   // EWMA lambda for correlations across symbols
+	CorrelationMinBars    int             `json:"correlationMinBars"` // Shared bars before a pair's correlation is used
 
 	// Monte Carlo Validation
 	MonteCarloRuns       int     `json:"monteCarloRuns"`
 	MonteCarloConfidence float64 `json:"monteCarloConfidence"`
 	MinRobustnessScore   float64 `json:"minRobustnessScore"`
+	MonteCarloMinTrades  int     `json:"monteCarloMinTrades"`
+
+	// Trade History feeding Monte Carlo validation
+	TradeHistorySize int             `json:"tradeHistorySize"`
+	AccountCapital   decimal.Decimal `json:"accountCapital"` // Base that trade PnLs are measured against
 
 	// Walk-Forward Optimization
 	WalkForwardWindows     int           `json:"walkForwardWindows"`
@@ -94,6 +127,10 @@ type OrchestratorConfig struct {
 	MaxDrawdown    float64 `json:"maxDrawdown"`
 	MinWinRate     float64 `json:"minWinRate"`
 	MinTradeCount  int     `json:"minTradeCount"`
+
+	// Backtest-vs-live drift
+	Drift             DriftConfig `json:"drift"`
+	DeactivateOnDrift bool        `json:"deactivateOnDrift"` // Deactivate strategies outside the critical band
 }
 
 // DefaultOrchestratorConfig returns production-ready defaults based on Perplexity research.
@@ -102,6 +139,7 @@ func DefaultOrchestratorConfig() OrchestratorConfig {
 		// Event Bus - High throughput for real-time processing
 		EventWorkers:    16,
 		EventBufferSize: 100000,
+		EventPartitions: 16, // Regime detection needs each symbol's bars in order
 
 		// Regime Detection - Detect market state changes
 		RegimeDetectionInterval: 5 * time.Minute,
@@ -113,11 +151,22 @@ func DefaultOrchestratorConfig() OrchestratorConfig {
 		MaxPositionSize:       decimal.NewFromFloat(0.10), // 10% max
 		KellyFraction:         0.25,                       // Quarter Kelly
 		TargetVolatility:      0.15,                       // 15% annual vol target
+		MaxExpectedShortfall:  0.01,                       // 1% average tail loss per bar
+		CVaRConfidence:        0.95,
+		VolatilityDecay:       0.94, // RiskMetrics
+		VolatilityWindow:      20,
+		CorrelationDecay:      0.97,
+		CorrelationMinBars:    20,
 
 		// Monte Carlo - Statistical validation
 		MonteCarloRuns:       1000,
 		MonteCarloConfidence: 0.95,
 		MinRobustnessScore:   0.6,
+		MonteCarloMinTrades:  30,
+
+		// Trade History - Enough trades for stable bootstrap statistics
+		TradeHistorySize: 1000,
+		AccountCapital:   decimal.NewFromInt(10000),
 
 		// Walk-Forward - Out-of-sample validation
 		WalkForwardWindows:     5,
@@ -134,11 +183,17 @@ func DefaultOrchestratorConfig() OrchestratorConfig {
 		MaxDrawdown:    0.2, // 20%
 		MinWinRate:     0.4, // 40%
 		MinTradeCount:  100,
+
+		// Drift - Alert as live results leave the backtest's distribution
+		Drift:             DefaultDriftConfig(),
+		DeactivateOnDrift: true,
 	}
 }
 
-// RegimeTransition records a regime change.
+// RegimeTransition records a regime change of a symbol, or of the portfolio
+// when Symbol is empty.
 type RegimeTransition struct {
+	Symbol      string                     `json:"symbol,omitempty"`
 	From        regime.RegimeType          `json:"from"`
 	To          regime.RegimeType          `json:"to"`
 	Probability float64                    `json:"probability"`
@@ -146,6 +201,20 @@ type RegimeTransition struct {
 	Adjustments regime.StrategyAdjustments `json:"adjustments"`
 }
 
+// symbolRegime is the regime detected from one symbol's bars.
+type symbolRegime struct {
+	detector    *regime.HMMRegimeDetector
+	current     regime.RegimeType
+	probability float64
+
+	// Close-to-close returns over the regime lookback, for risk sizing
+	lastClose float64
+	returns   []float64
+
+	// Conditional volatility forecast; nil unless GARCHEnabled
+	garch *regime.GARCHDetector
+}
+
 // StrategyState tracks state for each active strategy.
 type StrategyState struct {
 	StrategyID      string                                    `json:"strategyId"`
@@ -160,27 +229,65 @@ type StrategyState struct {
 
 // StrategyPerformance tracks performance in a specific regime.
 type StrategyPerformance struct {
-	Sharpe      float64   `json:"sharpe"`
+	Sharpe      float64   `json:"sharpe"` // Per-trade mean PnL over its standard deviation
 	WinRate     float64   `json:"winRate"`
-	TradeCount  int       `json:"tradeCount"`
+	TradeCount  int       `json:"tradeCount"` // Closed trades
+	Wins        int       `json:"wins"`
+	Losses      int       `json:"losses"`
 	TotalPnL    float64   `json:"totalPnl"`
 	LastUpdated time.Time `json:"lastUpdated"`
+
+	// Running PnL variance (Welford)
+	pnlMean float64
+	pnlM2   float64
+}
+
+// RecordTrade adds a closed trade's PnL to the win rate and Sharpe ratio.
+func (p *StrategyPerformance) RecordTrade(pnl float64) {
+	p.TradeCount++
+	p.TotalPnL += pnl
+	switch {
+	case pnl > 0:
+		p.Wins++
+	case pnl < 0:
+		p.Losses++
+	}
+	p.WinRate = float64(p.Wins) / float64(p.TradeCount)
+
+	delta := pnl - p.pnlMean
+	p.pnlMean += delta / float64(p.TradeCount)
+	p.pnlM2 += delta * (pnl - p.pnlMean)
+
+	p.Sharpe = 0
+	if p.TradeCount > 1 {
+		if stdDev := math.Sqrt(p.pnlM2 / float64(p.TradeCount-1)); stdDev > 0 {
+			p.Sharpe = p.pnlMean / stdDev
+		}
+	}
 }
 
 // OrchestratorMetrics tracks orchestrator performance.
 type OrchestratorMetrics struct {
-	EventsProcessed     int64         `json:"eventsProcessed"`
-	EventsPerSecond     float64       `json:"eventsPerSecond"`
-	RegimeChanges       int           `json:"regimeChanges"`
-	PositionsSized      int64         `json:"positionsSized"`
-	MonteCarloRuns      int64         `json:"monteCarloRuns"`
-	OptimizationCycles  int           `json:"optimizationCycles"`
-	TasksExecuted       int64         `json:"tasksExecuted"`
-	P99Latency          time.Duration `json:"p99Latency"`
-	LastRegimeChange    time.Time     `json:"lastRegimeChange"`
-	CurrentRegime       string        `json:"currentRegime"`
-	ActiveStrategyCount int           `json:"activeStrategyCount"`
-	AvgRobustnessScore  float64       `json:"avgRobustnessScore"`
+	EventsProcessed     int64          `json:"eventsProcessed"`
+	EventsPerSecond     float64        `json:"eventsPerSecond"`
+	EventOverflows      EventOverflows `json:"eventOverflows"`
+	RegimeChanges       int            `json:"regimeChanges"`
+	PositionsSized      int64          `json:"positionsSized"`
+	MonteCarloRuns      int64          `json:"monteCarloRuns"`
+	OptimizationCycles  int            `json:"optimizationCycles"`
+	TasksExecuted       int64          `json:"tasksExecuted"`
+	P99Latency          time.Duration  `json:"p99Latency"`
+	LastRegimeChange    time.Time      `json:"lastRegimeChange"`
+	CurrentRegime       string         `json:"currentRegime"`
+	ActiveStrategyCount int            `json:"activeStrategyCount"`
+	AvgRobustnessScore  float64        `json:"avgRobustnessScore"`
+}
+
+// EventOverflows counts how often each event bus overflow policy kicked in.
+type EventOverflows struct {
+	Blocked       int64 `json:"blocked"`
+	DroppedOldest int64 `json:"droppedOldest"`
+	DroppedNewest int64 `json:"droppedNewest"`
 }
 
 // NewTradingOrchestrator creates a new orchestrator with all PhD-level components.
@@ -191,10 +298,11 @@ func NewTradingOrchestrator(
 	riskMgr *execution.RiskManager,
 ) (*TradingOrchestrator, error) {
 	// Initialize Event Bus
-	eventBusConfig := events.EventBusConfig{
-		BufferSize: config.EventBufferSize,
-		NumWorkers: config.EventWorkers,
-	}
+	eventBusConfig := events.DefaultEventBusConfig()
+	eventBusConfig.BufferSize = config.EventBufferSize
+	eventBusConfig.NumWorkers = config.EventWorkers
+	eventBusConfig.Partitions = config.EventPartitions
+	eventBusConfig.Log.Dir = config.EventLogDir
 	eventBus := events.NewEventBus(logger, eventBusConfig)
 
 	// Initialize HMM Regime Detector
@@ -213,11 +321,31 @@ func NewTradingOrchestrator(
 		MaxPosition:      config.MaxPositionSize.InexactFloat64(),
 	})
 
+	// Basket and tail-risk sizers selected by DefaultSizingStrategy
+	riskParity := sizing.NewRiskParitySizer(logger)
+	cvarSizer := sizing.NewCVaRSizer(logger, config.MaxExpectedShortfall,
+		config.CVaRConfidence, config.MaxPositionSize.InexactFloat64())
+
+	// Realized volatility from bars, feeding volatility targeting
+	volConfig := sizing.DefaultVolatilityConfig()
+	volConfig.DecayFactor = config.VolatilityDecay
+	volConfig.WindowSize = config.VolatilityWindow
+	volEstimator := sizing.NewVolatilityEstimator(logger, volConfig)
+
+	// Correlations across symbols from bars, feeding risk parity and the
+	// risk manager's correlated exposure
+	corrConfig := sizing.DefaultCorrelationConfig()
+	corrConfig.DecayFactor = config.CorrelationDecay
+	corrConfig.MinObservations = config.CorrelationMinBars
+	corrEstimator := sizing.NewCorrelationEstimator(logger, corrConfig)
+
 	// Initialize Monte Carlo Simulator
 	mcConfig := montecarlo.SimulatorConfig{
 		NumSimulations:  config.MonteCarloRuns,
 		ConfidenceLevel: config.MonteCarloConfidence,
-		Bootstrap:       true,
+		Bootstrap:       montecarlo.BootstrapStationary, // Keep win/loss streaks intact
+		BootstrapBlocks: 10,
+		MinTrades:       config.MonteCarloMinTrades,
 	}
 	monteCarloSim := montecarlo.NewSimulator(logger, mcConfig)
 
@@ -254,11 +382,19 @@ func NewTradingOrchestrator(
 		config:           config,
 		eventBus:         eventBus,
 		regimeDetector:   regimeDetector,
+		regimeConfig:     regimeConfig,
+		symbolRegimes:    make(map[string]*symbolRegime),
 		positionSizer:    positionSizer,
+		riskParity:       riskParity,
+		cvarSizer:        cvarSizer,
+		volEstimator:     volEstimator,
+		corrEstimator:    corrEstimator,
 		monteCarloSim:    monteCarloSim,
+		tradeHistory:     montecarlo.NewTradeHistory(config.TradeHistorySize),
 		optimizer:        optimizer,
 		workerPool:       workerPool,
 		viabilityCheck:   viabilityCheck,
+		driftDetector:    NewDriftDetector(logger.Named("drift"), config.Drift),
 		signalAggregator: signalAgg,
 		riskManager:      riskMgr,
 		executionModeler: execModel,
@@ -318,6 +454,7 @@ func (o *TradingOrchestrator) Start(ctx context.Context) error {
 
 	o.logger.Info("Starting Trading Orchestrator with PhD-level components",
 		zap.Int("eventWorkers", o.config.EventWorkers),
+		zap.Int("eventPartitions", o.config.EventPartitions),
 		zap.Int("monteCarloRuns", o.config.MonteCarloRuns),
 		zap.Float64("kellyFraction", o.config.KellyFraction),
 	)
@@ -332,6 +469,9 @@ func (o *TradingOrchestrator) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to start worker pool: %w", err)
 	}
 
+	// Resume regime detection from the last checkpoint
+	o.loadRegimeModels()
+
 	// Start regime detection loop
 	go o.regimeDetectionLoop(ctx)
 
@@ -361,15 +501,27 @@ func (o *TradingOrchestrator) Stop() error {
 	// Stop in reverse order
 	o.workerPool.Stop()
 	o.eventBus.Stop()
+	o.checkpointRegimeModels()
 
 	o.logger.Info("Trading Orchestrator stopped")
 	return nil
 }
 
-// handleBarEvent processes bar data for regime detection.
+// handleBarEvent processes bar data for regime detection and volatility
+// and correlation estimation. Each symbol's bars feed its own detector, so assets never
+// share regime state.
 func (o *TradingOrchestrator) handleBarEvent(e *events.BarEvent) {
-	// Update regime detector with new bar
-	o.regimeDetector.AddBar(regime.Bar{
+	key := execution.NormalizeSymbol(e.Symbol)
+	sr := o.symbolRegimeFor(key)
+
+	o.volEstimator.Update(key, e.Close.InexactFloat64(), e.Timestamp)
+	o.corrEstimator.Update(key, e.Close.InexactFloat64(), e.Timestamp)
+	if sr.garch != nil {
+		sr.garch.AddPrice(e.Close.InexactFloat64(), e.Timestamp)
+	}
+
+	// Update the symbol's regime detector with the new bar
+	sr.detector.AddBar(regime.Bar{
 		Open:   e.Open,
 		High:   e.High,
 		Low:    e.Low,
@@ -379,47 +531,158 @@ func (o *TradingOrchestrator) handleBarEvent(e *events.BarEvent) {
 	})
 
 	// Check for regime change
-	newRegime, prob := o.regimeDetector.GetCurrentRegime()
+	newRegime, prob := sr.detector.GetCurrentRegime()
 
 	o.mu.Lock()
-	if newRegime != o.currentRegime && prob >= o.config.RegimeMinProbability {
-		// Regime transition detected
-		adjustments := o.regimeDetector.GetStrategyAdjustments(newRegime)
-
-		transition := RegimeTransition{
-			From:        o.currentRegime,
-			To:          newRegime,
-			Probability: prob,
-			Timestamp:   time.Now(),
-			Adjustments: adjustments,
+	defer o.mu.Unlock()
+
+	closePrice := e.Close.InexactFloat64()
+	if sr.lastClose > 0 {
+		sr.returns = append(sr.returns, closePrice/sr.lastClose-1)
+		if excess := len(sr.returns) - o.config.RegimeLookbackBars; excess > 0 {
+			sr.returns = sr.returns[excess:]
 		}
-		o.regimeHistory = append(o.regimeHistory, transition)
-		o.currentRegime = newRegime
-		o.metrics.RegimeChanges++
-		o.metrics.LastRegimeChange = time.Now()
-		o.metrics.CurrentRegime = string(newRegime)
-
-		o.logger.Info("Regime transition detected",
-			zap.String("from", string(transition.From)),
-			zap.String("to", string(transition.To)),
-			zap.Float64("probability", prob),
-			zap.Float64("positionMultiplier", adjustments.PositionSizeMultiplier),
-		)
+	}
+	sr.lastClose = closePrice
+
+	if newRegime == sr.current {
+		sr.probability = prob
+	} else if prob >= o.config.RegimeMinProbability {
+		o.recordRegimeTransition(key, sr.current, newRegime, prob)
+		sr.current = newRegime
+		sr.probability = prob
+	}
 
-		// Apply regime adjustments to active strategies
-		o.applyRegimeAdjustments(adjustments)
+	o.updatePortfolioRegime()
+}
+
+// WarmupRegime replays historical bars through a symbol's regime detector
+// and volatility estimator, so regime gating and sizing have history before
+// live bars arrive.
+func (o *TradingOrchestrator) WarmupRegime(symbol string, bars []*types.OHLCV) {
+	for _, bar := range bars {
+		o.handleBarEvent(events.NewBarEvent(symbol, bar.Open, bar.High, bar.Low, bar.Close, bar.Volume, bar.Timestamp))
 	}
-	o.mu.Unlock()
+
+	current, prob := o.GetCurrentRegime(symbol)
+	o.logger.Info("Regime warmed up",
+		zap.String("symbol", symbol),
+		zap.Int("bars", len(bars)),
+		zap.String("regime", string(current)),
+		zap.Float64("probability", prob),
+	)
+}
+
+// symbolRegimeFor returns a symbol's regime state, creating its detector on
+// the symbol's first bar.
+func (o *TradingOrchestrator) symbolRegimeFor(key string) *symbolRegime {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	sr, ok := o.symbolRegimes[key]
+	if !ok {
+		sr = &symbolRegime{
+			detector: regime.NewHMMRegimeDetector(o.logger.With(zap.String("symbol", key)), o.regimeConfig),
+			current:  regime.RegimeNeutral,
+		}
+		o.symbolRegimes[key] = sr
+	}
+	if o.config.GARCHEnabled && sr.garch == nil {
+		sr.garch = regime.NewGARCHDetector(o.logger.With(zap.String("symbol", key)), nil)
+	}
+	return sr
+}
+
+// updatePortfolioRegime sets the portfolio-level regime to the one holding
+// the most probability across symbols, and applies its adjustments to the
+// active strategies when it changes. Callers hold o.mu.
+func (o *TradingOrchestrator) updatePortfolioRegime() {
+	weights := make(map[regime.RegimeType]float64)
+	total := 0.0
+	for _, sr := range o.symbolRegimes {
+		weights[sr.current] += sr.probability
+		total += sr.probability
+	}
+	if total == 0 {
+		return
+	}
+
+	// Ties keep the current regime
+	best := o.currentRegime
+	for regimeType, weight := range weights {
+		if weight > weights[best] {
+			best = regimeType
+		}
+	}
+	prob := weights[best] / total
+
+	if best == o.currentRegime {
+		o.currentRegimeProb = prob
+		return
+	}
+
+	adjustments := o.recordRegimeTransition("", o.currentRegime, best, prob)
+	o.currentRegime = best
+	o.currentRegimeProb = prob
+	o.metrics.RegimeChanges++
+	o.metrics.LastRegimeChange = time.Now()
+	o.metrics.CurrentRegime = string(best)
+
+	// Apply regime adjustments to active strategies
+	o.applyRegimeAdjustments(adjustments)
+}
+
+// recordRegimeTransition logs a regime change of a symbol, or of the
+// portfolio for an empty symbol, and returns the new regime's adjustments.
+// Callers hold o.mu.
+func (o *TradingOrchestrator) recordRegimeTransition(
+	symbol string,
+	from, to regime.RegimeType,
+	prob float64,
+) regime.StrategyAdjustments {
+	adjustments := o.regimeDetector.GetStrategyAdjustments(to)
+
+	transition := RegimeTransition{
+		Symbol:      symbol,
+		From:        from,
+		To:          to,
+		Probability: prob,
+		Timestamp:   time.Now(),
+		Adjustments: adjustments,
+	}
+	o.regimeHistory = append(o.regimeHistory, transition)
+
+	o.logger.Info("Regime transition detected",
+		zap.String("symbol", symbol),
+		zap.String("from", string(transition.From)),
+		zap.String("to", string(transition.To)),
+		zap.Float64("probability", prob),
+		zap.Float64("positionMultiplier", adjustments.PositionSizeMultiplier),
+	)
+
+	return adjustments
+}
+
+// regimeForLocked returns a symbol's regime and its probability, falling back
+// to the portfolio regime for an empty symbol or one without bars yet.
+// Callers hold o.mu.
+func (o *TradingOrchestrator) regimeForLocked(symbol string) (regime.RegimeType, float64) {
+	if symbol != "" {
+		if sr, ok := o.symbolRegimes[execution.NormalizeSymbol(symbol)]; ok {
+			return sr.current, sr.probability
+		}
+	}
+	return o.currentRegime, o.currentRegimeProb
 }
 
 // handleSignalEvent processes trading signals through position sizing.
 func (o *TradingOrchestrator) handleSignalEvent(e *events.SignalEvent) {
 	o.mu.RLock()
-	currentRegime := o.currentRegime
+	currentRegime, _ := o.regimeForLocked(e.Symbol)
 	o.mu.RUnlock()
 
-	// Get regime adjustments
-	adjustments := o.regimeDetector.GetStrategyAdjustments(currentRegime)
+	// Blend regime adjustments by probability
+	adjustments := o.GetBlendedAdjustments(e.Symbol)
 
 	// Calculate position size using Kelly Criterion
 	request := sizing.PositionSizeRequest{
@@ -431,13 +694,13 @@ func (o *TradingOrchestrator) handleSignalEvent(e *events.SignalEvent) {
 		SignalStrength:    e.Strength,
 		Confidence:        e.Confidence,
 		PortfolioValue:    e.PortfolioValue,
-		CurrentVolatility: e.Volatility,
+		CurrentVolatility: o.currentVolatility(e.Symbol, e.Volatility),
 		HistoricalWinRate: e.WinRate,
 		AvgWinLossRatio:   e.WinLossRatio,
 	}
 
 	// Size the position
-	result := o.positionSizer.Size(request)
+	result := o.sizeWithStrategy(request)
 
 	// Apply regime multiplier
 	result.PositionSize *= adjustments.PositionSizeMultiplier
@@ -473,18 +736,28 @@ func (o *TradingOrchestrator) handleSignalEvent(e *events.SignalEvent) {
 
 // handleExecutionEvent processes trade execution results for learning.
 func (o *TradingOrchestrator) handleExecutionEvent(e *events.ExecutionEvent) {
+	// Every fill's slippage is compared against the backtest
+	if e.StrategyID != "" && e.Quantity > 0 {
+		o.driftDetector.RecordSlippage(e.StrategyID, e.Slippage)
+		defer o.checkDrift(e.StrategyID)
+	}
+
 	// Record execution for strategy performance tracking
+	// Opening fills carry no PnL; closes feed Monte Carlo validation and the
+	// strategy's performance in the traded symbol's regime
+	if e.PnL == 0 {
+		return
+	}
+	o.tradeHistory.Record(e.StrategyID, e.PnL)
+	o.driftDetector.RecordTrade(e.StrategyID, e.PnL)
+
 	o.mu.Lock()
 	if strategy, exists := o.activeStrategies[e.StrategyID]; exists {
-		if perfMap, ok := strategy.RegimePerf[o.currentRegime]; ok {
-			perfMap.TradeCount++
-			perfMap.TotalPnL += e.PnL
-			if e.PnL > 0 {
-				perfMap.WinRate = float64(perfMap.TradeCount) // Will recalculate
-			}
-			perfMap.LastUpdated = time.Now()
-			strategy.RegimePerf[o.currentRegime] = perfMap
-		}
+		currentRegime, _ := o.regimeForLocked(e.Symbol)
+		perf := strategy.RegimePerf[currentRegime]
+		perf.RecordTrade(e.PnL)
+		perf.LastUpdated = time.Now()
+		strategy.RegimePerf[currentRegime] = perf
 	}
 	o.mu.Unlock()
 }
@@ -497,8 +770,9 @@ func (o *TradingOrchestrator) handleRiskAlert(e *events.RiskAlertEvent) {
 		zap.String("severity", e.Severity),
 	)
 
-	// If critical, reduce all position sizes
-	if e.Severity == "critical" {
+	// If critical, reduce all position sizes. Drift is confined to one
+	// strategy, which checkDrift deactivates itself.
+	if e.Severity == "critical" && e.AlertType != driftAlertType {
 		o.mu.Lock()
 		for _, strategy := range o.activeStrategies {
 			strategy.IsActive = false
@@ -545,11 +819,21 @@ func (o *TradingOrchestrator) regimeDetectionLoop(ctx context.Context) {
 		case <-ticker.C:
 			// Regime detection is event-driven via bar events
 			// This loop can perform additional regime analysis if needed
-			currentRegime, prob := o.regimeDetector.GetCurrentRegime()
-			o.logger.Debug("Regime check",
-				zap.String("regime", string(currentRegime)),
-				zap.Float64("probability", prob),
+			o.mu.RLock()
+			for symbol, sr := range o.symbolRegimes {
+				o.logger.Debug("Regime check",
+					zap.String("symbol", symbol),
+					zap.String("regime", string(sr.current)),
+					zap.Float64("probability", sr.probability),
+				)
+			}
+			o.logger.Debug("Portfolio regime check",
+				zap.String("regime", string(o.currentRegime)),
+				zap.Float64("probability", o.currentRegimeProb),
 			)
+			o.mu.RUnlock()
+
+			o.checkpointRegimeModels()
 		}
 	}
 }
@@ -602,38 +886,68 @@ func (o *TradingOrchestrator) evaluateStrategy(ctx context.Context, strategyID s
 		return
 	}
 
-	// Get strategy's backtest results (would come from actual backtest)
-	// For now, use placeholder
-	results := backtester.BacktestResults{
-		TotalReturn:  0.15, // 15%
-		SharpeRatio:  0.8,
-		MaxDrawdown:  0.12,
-		WinRate:      0.52,
-		TradeCount:   150,
-		ProfitFactor: 1.3,
+	o.mu.RLock()
+	runBacktest := o.backtestRunner
+	params := make(map[string]float64, len(strategy.CurrentParams))
+	for name, value := range strategy.CurrentParams {
+		params[name] = value
 	}
+	o.mu.RUnlock()
+
+	if runBacktest == nil {
+		o.logger.Debug("No backtest runner set, skipping strategy evaluation",
+			zap.String("strategyId", strategyID))
+		return
+	}
+
+	result, err := runBacktest(ctx, strategyID, params)
+	if err != nil {
+		o.logger.Warn("Strategy backtest failed",
+			zap.String("strategyId", strategyID),
+			zap.Error(err),
+		)
+		return
+	}
+	summary := backtester.Summarize(result)
 
 	// Check viability
-	report := o.viabilityCheck.Check(results)
+	report := o.viabilityCheck.Check(result)
 
-	// Run Monte Carlo validation
-	trades := make([]float64, results.TradeCount)
-	// Populate with historical trade PnLs...
+	// Run Monte Carlo validation on the backtest's trades and any live
+	// trades since. Too few trades fails validation rather than passing it.
+	o.tradeHistory.SetBacktest(strategyID, summary.TradePnLs)
 
-	mcResults := o.monteCarloSim.Simulate(trades)
+	// Refresh the distribution live results are held to
+	if o.driftDetector.SetBaseline(strategyID, result.Trades) {
+		o.checkDrift(strategyID)
+	}
+
+	var robustness float64
+	mcResults, err := o.ValidateStrategy(strategyID)
+	if err != nil {
+		o.logger.Warn("Monte Carlo validation failed",
+			zap.String("strategyId", strategyID),
+			zap.Error(err),
+		)
+	} else {
+		robustness = mcResults.RobustnessScore
+	}
 
 	o.mu.Lock()
 	strategy.ViabilityGrade = report.Grade
-	strategy.ViabilityScore = report.OverallScore
-	strategy.RobustnessScore = mcResults.RobustnessScore
-	strategy.IsActive = report.IsViable && mcResults.RobustnessScore >= o.config.MinRobustnessScore
+	strategy.ViabilityScore = float64(report.Score) / 100
+	strategy.RobustnessScore = robustness
+	strategy.IsActive = report.IsViable && err == nil && robustness >= o.config.MinRobustnessScore &&
+		!o.driftedOut(strategyID)
 	o.mu.Unlock()
 
 	o.logger.Info("Strategy evaluated",
 		zap.String("strategyId", strategyID),
 		zap.String("grade", report.Grade),
-		zap.Float64("score", report.OverallScore),
-		zap.Float64("robustness", mcResults.RobustnessScore),
+		zap.Int("score", report.Score),
+		zap.Int("trades", summary.TradeCount),
+		zap.Float64("totalReturn", summary.TotalReturn),
+		zap.Float64("robustness", robustness),
 		zap.Bool("active", strategy.IsActive),
 	)
 }
@@ -659,6 +973,11 @@ func (o *TradingOrchestrator) metricsLoop(ctx context.Context) {
 			o.metrics.EventsProcessed = ebStats.TotalProcessed
 			o.metrics.EventsPerSecond = float64(ebStats.TotalProcessed-lastEventsProcessed) / 10.0
 			o.metrics.P99Latency = ebStats.P99Latency
+			o.metrics.EventOverflows = EventOverflows{
+				Blocked:       ebStats.OverflowBlocked,
+				DroppedOldest: ebStats.OverflowDroppedOldest,
+				DroppedNewest: ebStats.OverflowDroppedNewest,
+			}
 			o.metrics.TasksExecuted = wpStats.TotalCompleted
 
 			// Count active strategies
@@ -681,6 +1000,14 @@ func (o *TradingOrchestrator) metricsLoop(ctx context.Context) {
 	}
 }
 
+// SetBacktestRunner sets how strategies are backtested during evaluation.
+// Until one is set, strategies keep their registered scores.
+func (o *TradingOrchestrator) SetBacktestRunner(runner BacktestRunner) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.backtestRunner = runner
+}
+
 // RegisterStrategy registers a new strategy for monitoring.
 func (o *TradingOrchestrator) RegisterStrategy(strategyID string, params map[string]float64) {
 	o.mu.Lock()
@@ -705,12 +1032,18 @@ func (o *TradingOrchestrator) UnregisterStrategy(strategyID string) {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 	delete(o.activeStrategies, strategyID)
+	o.tradeHistory.Clear(strategyID)
+	o.driftDetector.Clear(strategyID)
 	o.logger.Info("Strategy unregistered", zap.String("strategyId", strategyID))
 }
 
-// GetCurrentRegime returns the current detected market regime.
-func (o *TradingOrchestrator) GetCurrentRegime() (regime.RegimeType, float64) {
-	return o.regimeDetector.GetCurrentRegime()
+// GetCurrentRegime returns the detected regime of a symbol and its
+// probability. An empty symbol, or one without bars yet, returns the
+// portfolio-level regime aggregated across symbols.
+func (o *TradingOrchestrator) GetCurrentRegime(symbol string) (regime.RegimeType, float64) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.regimeForLocked(symbol)
 }
 
 // GetRegimeHistory returns recent regime transitions.
@@ -732,39 +1065,289 @@ func (o *TradingOrchestrator) GetRegimeHistory(limit int) []RegimeTransition {
 	return result
 }
 
-// GetStrategyAdjustments returns current regime-based strategy adjustments.
-func (o *TradingOrchestrator) GetStrategyAdjustments() regime.StrategyAdjustments {
+// GetStrategyAdjustments returns the strategy adjustments for a symbol's
+// regime, or for the portfolio regime when symbol is empty.
+func (o *TradingOrchestrator) GetStrategyAdjustments(symbol string) regime.StrategyAdjustments {
 	o.mu.RLock()
-	currentRegime := o.currentRegime
+	currentRegime, _ := o.regimeForLocked(symbol)
 	o.mu.RUnlock()
 	return o.regimeDetector.GetStrategyAdjustments(currentRegime)
 }
 
+// GetRegimeProbabilities returns the probability of each regime for a
+// symbol. An empty symbol, or one without bars yet, returns the mean
+// distribution across symbols.
+func (o *TradingOrchestrator) GetRegimeProbabilities(symbol string) map[regime.RegimeType]float64 {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.regimeProbabilitiesLocked(symbol)
+}
+
+// GetBlendedAdjustments returns strategy adjustments for a symbol weighted
+// across regimes by their probabilities. Multipliers are probability-weighted
+// means; a strategy is preferred or avoided when regimes holding more than
+// half the probability say so. With GARCHRegimeWeight set, the symbol's
+// GARCH volatility regime takes that share of the probability. Without a
+// distribution it falls back to the top regime's adjustments.
+func (o *TradingOrchestrator) GetBlendedAdjustments(symbol string) regime.StrategyAdjustments {
+	probs := o.blendVolatilityRegime(symbol, o.GetRegimeProbabilities(symbol))
+
+	total := 0.0
+	for _, prob := range probs {
+		total += prob
+	}
+	if total <= 0 {
+		return o.GetStrategyAdjustments(symbol)
+	}
+
+	var blended regime.StrategyAdjustments
+	preferred := make(map[string]float64)
+	avoided := make(map[string]float64)
+
+	for regimeType, prob := range probs {
+		if prob <= 0 {
+			continue
+		}
+		weight := prob / total
+		adj := o.regimeDetector.GetStrategyAdjustments(regimeType)
+
+		blended.PositionSizeMultiplier += weight * adj.PositionSizeMultiplier
+		blended.StopLossMultiplier += weight * adj.StopLossMultiplier
+		blended.TakeProfitMultiplier += weight * adj.TakeProfitMultiplier
+		for _, strategyID := range adj.PreferredStrategies {
+			preferred[strategyID] += weight
+		}
+		for _, strategyID := range adj.AvoidStrategies {
+			avoided[strategyID] += weight
+		}
+	}
+
+	blended.PreferredStrategies = majorityStrategies(preferred)
+	blended.AvoidStrategies = majorityStrategies(avoided)
+	return blended
+}
+
+// regimeProbabilitiesLocked returns a symbol's regime distribution, or the
+// mean across symbols. Callers hold o.mu.
+func (o *TradingOrchestrator) regimeProbabilitiesLocked(symbol string) map[regime.RegimeType]float64 {
+	if symbol != "" {
+		if sr, ok := o.symbolRegimes[execution.NormalizeSymbol(symbol)]; ok {
+			return sr.detector.GetRegimeProbabilities()
+		}
+	}
+
+	mean := make(map[regime.RegimeType]float64)
+	count := 0
+	for _, sr := range o.symbolRegimes {
+		probs := sr.detector.GetRegimeProbabilities()
+		if len(probs) == 0 {
+			continue
+		}
+		for regimeType, prob := range probs {
+			mean[regimeType] += prob
+		}
+		count++
+	}
+	for regimeType := range mean {
+		mean[regimeType] /= float64(count)
+	}
+	return mean
+}
+
+// majorityStrategies returns the strategies whose weight exceeds one half,
+// sorted for stable output.
+func majorityStrategies(weights map[string]float64) []string {
+	var strategies []string
+	for strategyID, weight := range weights {
+		if weight > 0.5 {
+			strategies = append(strategies, strategyID)
+		}
+	}
+	sort.Strings(strategies)
+	return strategies
+}
+
 // SizePosition calculates optimal position size with regime awareness.
 func (o *TradingOrchestrator) SizePosition(request sizing.PositionSizeRequest) sizing.PositionSizeResult {
-	o.mu.RLock()
-	currentRegime := o.currentRegime
-	o.mu.RUnlock()
+	request.CurrentVolatility = o.currentVolatility(request.Symbol, request.CurrentVolatility)
 
-	// Size with position sizer
-	result := o.positionSizer.Size(request)
+	// Size with the configured strategy
+	result := o.sizeWithStrategy(request)
 
-	// Apply regime adjustments
-	adjustments := o.regimeDetector.GetStrategyAdjustments(currentRegime)
+	// Apply regime adjustments blended by probability, so size shifts
+	// gradually instead of flipping when the top regime changes
+	adjustments := o.GetBlendedAdjustments(request.Symbol)
 	result.PositionSize *= adjustments.PositionSizeMultiplier
 
 	return result
 }
 
-// RunMonteCarloValidation validates a strategy with Monte Carlo simulation.
-func (o *TradingOrchestrator) RunMonteCarloValidation(trades []float64) *montecarlo.SimulationResults {
-	results := o.monteCarloSim.Simulate(trades)
+// currentVolatility returns the volatility a caller supplied, or the
+// symbol's annualized GARCH forecast or EWMA volatility estimated from bars
+// when it supplied none.
+func (o *TradingOrchestrator) currentVolatility(symbol string, supplied float64) float64 {
+	if supplied > 0 {
+		return supplied
+	}
+	if forecast, ok := o.GetVolatilityForecast(symbol); ok {
+		return forecast.Volatility
+	}
+	vol, _ := o.volEstimator.Volatility(execution.NormalizeSymbol(symbol))
+	return vol
+}
+
+// GetVolatilityEstimate returns a symbol's realized volatility estimated
+// from bars, and false until enough bars have been seen.
+func (o *TradingOrchestrator) GetVolatilityEstimate(symbol string) (sizing.VolatilityEstimate, bool) {
+	return o.volEstimator.Estimate(execution.NormalizeSymbol(symbol))
+}
+
+// Correlations returns the estimator of correlations across symbols from
+// bars, keyed by normalized symbol.
+func (o *TradingOrchestrator) Correlations() *sizing.CorrelationEstimator {
+	return o.corrEstimator
+}
+
+// GetCorrelationMatrix returns the current correlations between symbols, or
+// between every symbol with bars when none are given.
+func (o *TradingOrchestrator) GetCorrelationMatrix(symbols []string) sizing.CorrelationMatrix {
+	keys := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		keys[i] = execution.NormalizeSymbol(symbol)
+	}
+	return o.corrEstimator.Matrix(keys)
+}
+
+// sizeWithStrategy sizes a request with the configured sizing strategy.
+// Risk parity and CVaR need returns observed from bars; until a symbol has
+// enough of them, its request falls back to the position sizer.
+func (o *TradingOrchestrator) sizeWithStrategy(request sizing.PositionSizeRequest) sizing.PositionSizeResult {
+	var (
+		fraction float64
+		err      error
+	)
+
+	switch o.config.DefaultSizingStrategy {
+	case sizing.MethodRiskParity:
+		fraction, err = o.riskParityFraction(request.Symbol)
+	case sizing.MethodCVaR:
+		fraction, err = o.cvarFraction(request.Symbol, request.Direction)
+	default:
+		return o.positionSizer.Size(request)
+	}
+
+	if err != nil {
+		o.logger.Debug("Falling back to default position sizer",
+			zap.String("symbol", request.Symbol),
+			zap.String("method", o.config.DefaultSizingStrategy),
+			zap.Error(err),
+		)
+		return o.positionSizer.Size(request)
+	}
+
+	return sizing.PositionSizeResult{
+		PositionSize: fraction * request.PortfolioValue,
+		Method:       o.config.DefaultSizingStrategy,
+	}
+}
+
+// riskParityFraction returns a symbol's risk-parity weight in the basket of
+// every symbol with observed returns. The basket's EWMA covariance is used
+// once every pair has shared enough bars, and the sample covariance of the
+// returns until then.
+func (o *TradingOrchestrator) riskParityFraction(symbol string) (float64, error) {
+	key := execution.NormalizeSymbol(symbol)
+
+	o.mu.RLock()
+	returns := make(map[string][]float64, len(o.symbolRegimes))
+	for k, sr := range o.symbolRegimes {
+		if len(sr.returns) > 0 {
+			returns[k] = append([]float64(nil), sr.returns...)
+		}
+	}
+	o.mu.RUnlock()
+
+	if _, ok := returns[key]; !ok {
+		return 0, sizing.ErrInsufficientReturns
+	}
+
+	symbols := make([]string, 0, len(returns))
+	for k := range returns {
+		symbols = append(symbols, k)
+	}
+	cov, err := o.corrEstimator.CovarianceMatrix(symbols)
+	if err != nil {
+		cov, err = sizing.EstimateCovariance(returns)
+	}
+	if err != nil {
+		return 0, err
+	}
+	weights, err := o.riskParity.CalculateWeights(cov)
+	if err != nil {
+		return 0, err
+	}
+	return weights[key], nil
+}
+
+// cvarFraction returns the fraction of the portfolio whose expected
+// shortfall over a symbol's observed returns stays within the limit.
+func (o *TradingOrchestrator) cvarFraction(symbol, direction string) (float64, error) {
+	o.mu.RLock()
+	var returns []float64
+	if sr, ok := o.symbolRegimes[execution.NormalizeSymbol(symbol)]; ok {
+		returns = append(returns, sr.returns...)
+	}
+	o.mu.RUnlock()
+
+	// A short loses when the price rises
+	if direction == "short" {
+		for i := range returns {
+			returns[i] = -returns[i]
+		}
+	}
+
+	return o.cvarSizer.CalculateCVaRSize(returns)
+}
+
+// RunMonteCarloValidation validates a sequence of trade PnLs with Monte Carlo
+// simulation. It returns montecarlo.ErrInsufficientTrades when there are
+// fewer trades than the configured minimum.
+func (o *TradingOrchestrator) RunMonteCarloValidation(trades []float64) (*montecarlo.SimulationResult, error) {
+	capital := o.config.AccountCapital
+	sequence := &montecarlo.TradeSequence{
+		Returns: montecarlo.ReturnsFromPnLs(trades, capital.InexactFloat64()),
+	}
+
+	results, err := o.monteCarloSim.Validate(sequence, capital)
+	if err != nil {
+		return nil, err
+	}
 
 	o.mu.Lock()
 	o.metrics.MonteCarloRuns++
 	o.mu.Unlock()
 
-	return results
+	return results, nil
+}
+
+// ValidateStrategy runs Monte Carlo validation on a strategy's trade history.
+func (o *TradingOrchestrator) ValidateStrategy(strategyID string) (*montecarlo.SimulationResult, error) {
+	results, err := o.RunMonteCarloValidation(o.tradeHistory.PnLs(strategyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate strategy %s: %w", strategyID, err)
+	}
+	return results, nil
+}
+
+// WorkerPool returns the pool that runs background tasks such as strategy
+// evaluation.
+func (o *TradingOrchestrator) WorkerPool() *workers.Pool {
+	return o.workerPool
+}
+
+// GetTradeHistory returns the per-strategy trade history used for validation.
+func (o *TradingOrchestrator) GetTradeHistory() *montecarlo.TradeHistory {
+	return o.tradeHistory
 }
 
 // OptimizeStrategy runs walk-forward optimization on a strategy.
@@ -797,6 +1380,22 @@ func (o *TradingOrchestrator) OptimizeStrategy(
 	return results, nil
 }
 
+// OptimizeStrategyForMetric runs OptimizeStrategy scoring each parameter
+// set's backtest by a named objective metric, such as "sortino" or
+// "penalized_sharpe".
+func (o *TradingOrchestrator) OptimizeStrategyForMetric(
+	strategyID string,
+	paramGrid map[string][]float64,
+	metric string,
+	backtest optimization.BacktestFunc,
+) (*optimization.WalkForwardResults, error) {
+	objective, err := optimization.Objective(metric, backtest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build objective: %w", err)
+	}
+	return o.OptimizeStrategy(strategyID, paramGrid, objective)
+}
+
 // PublishEvent publishes an event to the event bus.
 func (o *TradingOrchestrator) PublishEvent(event events.Event) {
 	o.eventBus.Publish(event)
@@ -0,0 +1,47 @@
+// Package orchestrator_test provides tests for the trading orchestrator.
+package orchestrator_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/orchestrator"
+)
+
+func TestStrategyPerformanceWinRateBounded(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	var perf orchestrator.StrategyPerformance
+	for i := 0; i < 500; i++ {
+		perf.RecordTrade(rng.NormFloat64() * 100)
+
+		if perf.WinRate < 0 || perf.WinRate > 1 {
+			t.Fatalf("trade %d: win rate %v outside [0, 1]", i, perf.WinRate)
+		}
+	}
+
+	if perf.Wins+perf.Losses != perf.TradeCount {
+		t.Errorf("wins %d + losses %d != trades %d", perf.Wins, perf.Losses, perf.TradeCount)
+	}
+}
+
+func TestStrategyPerformanceRecordTrade(t *testing.T) {
+	var perf orchestrator.StrategyPerformance
+	for _, pnl := range []float64{10, -5, 20, -5} {
+		perf.RecordTrade(pnl)
+	}
+
+	if perf.WinRate != 0.5 {
+		t.Errorf("win rate = %v, want 0.5", perf.WinRate)
+	}
+	if perf.TotalPnL != 20 {
+		t.Errorf("total PnL = %v, want 20", perf.TotalPnL)
+	}
+
+	// Mean 5, sample standard deviation sqrt(450/3)
+	want := 5 / math.Sqrt(150)
+	if math.Abs(perf.Sharpe-want) > 1e-9 {
+		t.Errorf("sharpe = %v, want %v", perf.Sharpe, want)
+	}
+}
@@ -0,0 +1,89 @@
+// Package orchestrator provides checkpointing of per-symbol regime models.
+package orchestrator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/atlas-desktop/trading-backend/internal/regime"
+	"go.uber.org/zap"
+)
+
+// regimeModelPath returns the checkpoint file of a normalized symbol.
+func (o *TradingOrchestrator) regimeModelPath(key string) string {
+	return filepath.Join(o.config.RegimeModelDir, key+".json")
+}
+
+// loadRegimeModels restores each symbol's detector from its last checkpoint,
+// so regime calls are reliable right after a restart. Checkpoints in an
+// older format are skipped and their symbols start fresh.
+func (o *TradingOrchestrator) loadRegimeModels() {
+	if o.config.RegimeModelDir == "" {
+		return
+	}
+
+	entries, err := os.ReadDir(o.config.RegimeModelDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			o.logger.Warn("Failed to read regime model dir", zap.Error(err))
+		}
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, entry := range entries {
+		key, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		detector := regime.NewHMMRegimeDetector(o.logger.With(zap.String("symbol", key)), o.regimeConfig)
+		if err := detector.LoadModel(o.regimeModelPath(key)); err != nil {
+			if errors.Is(err, regime.ErrModelVersion) {
+				o.logger.Info("Ignoring regime model in an old format",
+					zap.String("symbol", key),
+					zap.Error(err))
+			} else {
+				o.logger.Warn("Failed to load regime model",
+					zap.String("symbol", key),
+					zap.Error(err))
+			}
+			continue
+		}
+
+		sr := &symbolRegime{detector: detector, current: regime.RegimeNeutral}
+		if current, prob := detector.GetCurrentRegime(); prob >= o.config.RegimeMinProbability {
+			sr.current = current
+			sr.probability = prob
+		}
+		o.symbolRegimes[key] = sr
+	}
+
+	o.updatePortfolioRegime()
+}
+
+// checkpointRegimeModels saves each symbol's detector parameters.
+func (o *TradingOrchestrator) checkpointRegimeModels() {
+	if o.config.RegimeModelDir == "" {
+		return
+	}
+
+	o.mu.RLock()
+	detectors := make(map[string]*regime.HMMRegimeDetector, len(o.symbolRegimes))
+	for key, sr := range o.symbolRegimes {
+		detectors[key] = sr.detector
+	}
+	o.mu.RUnlock()
+
+	for key, detector := range detectors {
+		if err := detector.SaveModel(o.regimeModelPath(key)); err != nil {
+			o.logger.Warn("Failed to checkpoint regime model",
+				zap.String("symbol", key),
+				zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package orchestrator
+
+import (
+	"github.com/atlas-desktop/trading-backend/internal/execution"
+	"github.com/atlas-desktop/trading-backend/internal/regime"
+)
+
+// GetVolatilityForecast returns a symbol's GARCH volatility forecast and
+// regime, and false when GARCH is disabled or not yet fitted.
+func (o *TradingOrchestrator) GetVolatilityForecast(symbol string) (regime.GARCHState, bool) {
+	o.mu.RLock()
+	sr, ok := o.symbolRegimes[execution.NormalizeSymbol(symbol)]
+	o.mu.RUnlock()
+	if !ok || sr.garch == nil {
+		return regime.GARCHState{}, false
+	}
+
+	state := sr.garch.State()
+	return state, state.Volatility > 0
+}
+
+// blendVolatilityRegime gives a symbol's low or high GARCH volatility
+// regime GARCHRegimeWeight of its regime distribution, scaling the HMM's
+// probabilities down to the rest.
+func (o *TradingOrchestrator) blendVolatilityRegime(symbol string, probs map[regime.RegimeType]float64) map[regime.RegimeType]float64 {
+	weight := o.config.GARCHRegimeWeight
+	if weight <= 0 || symbol == "" {
+		return probs
+	}
+	forecast, ok := o.GetVolatilityForecast(symbol)
+	if !ok {
+		return probs
+	}
+	volRegime, ok := regimeForVolatility(forecast.Regime)
+	if !ok {
+		return probs
+	}
+	if weight > 1 {
+		weight = 1
+	}
+
+	blended := make(map[regime.RegimeType]float64, len(probs)+1)
+	for regimeType, prob := range probs {
+		blended[regimeType] = (1 - weight) * prob
+	}
+	blended[volRegime] += weight
+	return blended
+}
+
+// regimeForVolatility maps a volatility regime to the HMM regime whose
+// strategy adjustments suit it, and false for normal volatility, which
+// leaves the HMM's distribution as it is.
+func regimeForVolatility(vol regime.VolatilityRegime) (regime.RegimeType, bool) {
+	switch vol {
+	case regime.VolRegimeLow:
+		return regime.RegimeLowVol, true
+	case regime.VolRegimeHigh, regime.VolRegimeExtreme:
+		return regime.RegimeHighVol, true
+	default:
+		return "", false
+	}
+}
@@ -496,6 +496,23 @@ func (rd *RegimeDetector) GetCurrentRegime() *RegimeState {
 	return &state
 }
 
+// GetRegimeProbabilities returns the probability of each regime in the
+// current state, or nil before enough data has arrived
+func (rd *RegimeDetector) GetRegimeProbabilities() map[RegimeType]float64 {
+	rd.mu.RLock()
+	defer rd.mu.RUnlock()
+
+	if rd.currentState == nil {
+		return nil
+	}
+
+	probs := make(map[RegimeType]float64, len(rd.currentState.Probabilities))
+	for regime, prob := range rd.currentState.Probabilities {
+		probs[regime] = prob
+	}
+	return probs
+}
+
 // GetRegimeHistory returns recent regime history
 func (rd *RegimeDetector) GetRegimeHistory(limit int) []*RegimeState {
 	rd.mu.RLock()
@@ -0,0 +1,42 @@
+package regime_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/regime"
+	"go.uber.org/zap"
+)
+
+func TestRegimeProbabilities(t *testing.T) {
+	detector := regime.NewRegimeDetector(zap.NewNop(), nil)
+	if probs := detector.GetRegimeProbabilities(); probs != nil {
+		t.Fatalf("probabilities before any data = %v, want nil", probs)
+	}
+
+	for i := 0; i < 150; i++ {
+		detector.AddReturn(0.01 * math.Sin(float64(i)/3))
+	}
+
+	probs := detector.GetRegimeProbabilities()
+	total := 0.0
+	for _, prob := range probs {
+		if prob < 0 || prob > 1 {
+			t.Errorf("probability %v outside [0, 1]", prob)
+		}
+		total += prob
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("probabilities sum to %v, want 1: %v", total, probs)
+	}
+
+	// The returned map is a copy
+	for regimeType := range probs {
+		probs[regimeType] = 2
+	}
+	for regimeType, prob := range detector.GetRegimeProbabilities() {
+		if prob == 2 {
+			t.Errorf("%s probability changed through the returned map", regimeType)
+		}
+	}
+}
@@ -0,0 +1,476 @@
+package regime
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// VolatilityRegime classifies a volatility forecast against its own history
+type VolatilityRegime string
+
+const (
+	VolRegimeLow     VolatilityRegime = "low"
+	VolRegimeNormal  VolatilityRegime = "normal"
+	VolRegimeHigh    VolatilityRegime = "high"
+	VolRegimeExtreme VolatilityRegime = "extreme"
+	VolRegimeUnknown VolatilityRegime = "unknown"
+)
+
+// year is the period forecasts are annualized to
+const year = 365 * 24 * time.Hour
+
+// ErrGARCHInsufficientData is returned when fitting too few returns
+var ErrGARCHInsufficientData = errors.New("not enough returns to fit GARCH")
+
+// GARCHConfig configures the GARCH volatility detector
+type GARCHConfig struct {
+	WindowSize        int     // Returns kept and fitted
+	MinObservations   int     // Returns before the first fit
+	RefitEvery        int     // Returns between refits
+	PercentileWindow  int     // Forecasts the current one is ranked against
+	MinForecasts      int     // Forecasts before a regime is classified
+	LowPercentile     float64 // Below this rank volatility is low
+	HighPercentile    float64 // At or above this rank volatility is high
+	ExtremePercentile float64 // At or above this rank volatility is extreme
+	PeriodsPerYear    float64 // Annualizes returns added without timestamps
+}
+
+// DefaultGARCHConfig returns sensible defaults
+func DefaultGARCHConfig() *GARCHConfig {
+	return &GARCHConfig{
+		WindowSize:        1000,
+		MinObservations:   100,
+		RefitEvery:        50,
+		PercentileWindow:  250,
+		MinForecasts:      20,
+		LowPercentile:     0.25,
+		HighPercentile:    0.75,
+		ExtremePercentile: 0.95,
+		PeriodsPerYear:    365, // Daily bars, trading every day
+	}
+}
+
+// GARCHParams are fitted GARCH(1,1) parameters: the next period's variance
+// is Omega + Alpha*shock² + Beta*variance
+type GARCHParams struct {
+	Omega         float64 `json:"omega"`
+	Alpha         float64 `json:"alpha"`
+	Beta          float64 `json:"beta"`
+	Mean          float64 `json:"mean"` // Mean return, removed before fitting
+	LogLikelihood float64 `json:"log_likelihood"`
+}
+
+// Persistence returns how slowly volatility shocks decay
+func (p GARCHParams) Persistence() float64 {
+	return p.Alpha + p.Beta
+}
+
+// LongRunVariance returns the per-period variance forecasts revert to
+func (p GARCHParams) LongRunVariance() float64 {
+	return p.Omega / (1 - p.Persistence())
+}
+
+// maxPersistence keeps fitted models stationary
+const maxPersistence = 0.999
+
+// FitGARCH fits GARCH(1,1) to returns by maximizing the Gaussian
+// log-likelihood, searching with Nelder-Mead over parameters transformed so
+// every point searched is a stationary model
+func FitGARCH(returns []float64) (GARCHParams, error) {
+	if len(returns) < 30 {
+		return GARCHParams{}, fmt.Errorf("%w: %d", ErrGARCHInsufficientData, len(returns))
+	}
+
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	shocks := make([]float64, len(returns))
+	variance := 0.0
+	for i, r := range returns {
+		shocks[i] = r - mean
+		variance += shocks[i] * shocks[i]
+	}
+	variance /= float64(len(returns))
+	if variance <= 0 {
+		return GARCHParams{}, fmt.Errorf("returns have no variance")
+	}
+
+	// Omega is searched relative to the sample variance, so the search is
+	// the same for any scale of returns
+	decode := func(x []float64) GARCHParams {
+		persistence := maxPersistence * sigmoid(x[1])
+		share := sigmoid(x[2])
+		return GARCHParams{
+			Omega: variance * math.Exp(x[0]),
+			Alpha: persistence * share,
+			Beta:  persistence * (1 - share),
+			Mean:  mean,
+		}
+	}
+	objective := func(x []float64) float64 {
+		return -garchLogLikelihood(decode(x), shocks, variance)
+	}
+
+	// Start at a typical financial fit: persistence 0.95, a tenth of it from
+	// the last shock, reverting to the sample variance
+	start := []float64{math.Log(0.05), logit(0.95 / maxPersistence), logit(0.1)}
+	best := nelderMead(objective, start, 0.5, 1e-8, 2000)
+
+	params := decode(best)
+	params.LogLikelihood = garchLogLikelihood(params, shocks, variance)
+	if math.IsInf(params.LogLikelihood, 0) || math.IsNaN(params.LogLikelihood) {
+		return GARCHParams{}, fmt.Errorf("GARCH fit did not converge")
+	}
+	return params, nil
+}
+
+// garchLogLikelihood returns the Gaussian log-likelihood of shocks under
+// params, starting the variance recursion at initial
+func garchLogLikelihood(p GARCHParams, shocks []float64, initial float64) float64 {
+	sigma2 := initial
+	ll := 0.0
+	for _, e := range shocks {
+		if sigma2 <= 0 {
+			return math.Inf(-1)
+		}
+		ll -= 0.5 * (math.Log(2*math.Pi) + math.Log(sigma2) + e*e/sigma2)
+		sigma2 = p.Omega + p.Alpha*e*e + p.Beta*sigma2
+	}
+	return ll
+}
+
+// GARCHState is the detector's current forecast and volatility regime
+type GARCHState struct {
+	Regime       VolatilityRegime `json:"regime"`
+	Volatility   float64          `json:"volatility"` // One-step-ahead forecast, annualized
+	Percentile   float64          `json:"percentile"` // Rank of the forecast among recent ones
+	Params       GARCHParams      `json:"params"`
+	Observations int              `json:"observations"`
+	FittedAt     time.Time        `json:"fitted_at"`
+}
+
+// GARCHDetector forecasts conditional volatility with GARCH(1,1) and
+// classifies the forecast into a volatility regime by its percentile among
+// recent forecasts. It complements the HMM detector's discrete regimes with
+// an explicit volatility estimate for volatility targeting
+type GARCHDetector struct {
+	logger *zap.Logger
+	config *GARCHConfig
+
+	mu           sync.RWMutex
+	returns      []float64
+	params       GARCHParams
+	fitted       bool
+	fittedAt     time.Time
+	sinceFit     int
+	variance     float64   // Next period's forecast variance
+	forecasts    []float64 // Recent per-period forecast variances
+	lastPrice    float64
+	lastTime     time.Time
+	meanInterval time.Duration // Mean spacing of timestamped prices
+	intervals    int
+}
+
+// NewGARCHDetector creates a new GARCH volatility detector
+func NewGARCHDetector(logger *zap.Logger, config *GARCHConfig) *GARCHDetector {
+	if config == nil {
+		config = DefaultGARCHConfig()
+	}
+
+	return &GARCHDetector{
+		logger:    logger,
+		config:    config,
+		returns:   make([]float64, 0, config.WindowSize),
+		forecasts: make([]float64, 0, config.PercentileWindow),
+	}
+}
+
+// AddPrice adds a closing price, using the spacing of prices to annualize
+// forecasts
+func (gd *GARCHDetector) AddPrice(price float64, t time.Time) {
+	if price <= 0 {
+		return
+	}
+
+	gd.mu.Lock()
+	lastPrice, lastTime := gd.lastPrice, gd.lastTime
+	gd.lastPrice, gd.lastTime = price, t
+	if lastPrice > 0 && t.After(lastTime) {
+		gd.intervals++
+		gd.meanInterval += (t.Sub(lastTime) - gd.meanInterval) / time.Duration(gd.intervals)
+	}
+	gd.mu.Unlock()
+
+	if lastPrice > 0 {
+		gd.AddReturn(math.Log(price / lastPrice))
+	}
+}
+
+// AddReturn adds a return observation, refitting the model when due
+func (gd *GARCHDetector) AddReturn(ret float64) {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+
+	gd.returns = append(gd.returns, ret)
+	if excess := len(gd.returns) - gd.config.WindowSize; excess > 0 {
+		gd.returns = gd.returns[excess:]
+	}
+	gd.sinceFit++
+
+	if len(gd.returns) < gd.config.MinObservations {
+		return
+	}
+	if !gd.fitted || gd.sinceFit >= gd.config.RefitEvery {
+		gd.fitLocked()
+		return
+	}
+
+	shock := ret - gd.params.Mean
+	gd.variance = gd.params.Omega + gd.params.Alpha*shock*shock + gd.params.Beta*gd.variance
+	gd.recordForecast()
+}
+
+// AddReturns adds multiple returns (batch)
+func (gd *GARCHDetector) AddReturns(returns []float64) {
+	for _, ret := range returns {
+		gd.AddReturn(ret)
+	}
+}
+
+// Fit refits the model to the returns held
+func (gd *GARCHDetector) Fit() error {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+
+	return gd.fitLocked()
+}
+
+// fitLocked refits the model and reruns the variance recursion over the
+// window to today's forecast. A failed fit keeps the previous model.
+// Callers hold gd.mu.
+func (gd *GARCHDetector) fitLocked() error {
+	gd.sinceFit = 0
+
+	params, err := FitGARCH(gd.returns)
+	if err != nil {
+		gd.logger.Debug("GARCH fit failed", zap.Error(err))
+		return err
+	}
+
+	sample := 0.0
+	for _, r := range gd.returns {
+		sample += (r - params.Mean) * (r - params.Mean)
+	}
+	variance := sample / float64(len(gd.returns))
+	for _, r := range gd.returns {
+		shock := r - params.Mean
+		variance = params.Omega + params.Alpha*shock*shock + params.Beta*variance
+	}
+
+	gd.params = params
+	gd.fitted = true
+	gd.fittedAt = time.Now()
+	gd.variance = variance
+	gd.recordForecast()
+
+	gd.logger.Debug("GARCH refit",
+		zap.Float64("omega", params.Omega),
+		zap.Float64("alpha", params.Alpha),
+		zap.Float64("beta", params.Beta),
+		zap.Float64("logLikelihood", params.LogLikelihood),
+	)
+
+	return nil
+}
+
+// recordForecast adds the current forecast to the percentile history.
+// Callers hold gd.mu.
+func (gd *GARCHDetector) recordForecast() {
+	gd.forecasts = append(gd.forecasts, gd.variance)
+	if excess := len(gd.forecasts) - gd.config.PercentileWindow; excess > 0 {
+		gd.forecasts = gd.forecasts[excess:]
+	}
+}
+
+// ForecastVolatility returns the annualized one-step-ahead volatility
+// forecast, or 0 before the model is fitted
+func (gd *GARCHDetector) ForecastVolatility() float64 {
+	gd.mu.RLock()
+	defer gd.mu.RUnlock()
+
+	if !gd.fitted {
+		return 0
+	}
+	return math.Sqrt(gd.variance * gd.periodsPerYear())
+}
+
+// periodsPerYear returns the returns per year, from the spacing of
+// timestamped prices when there are any. Callers hold gd.mu.
+func (gd *GARCHDetector) periodsPerYear() float64 {
+	if gd.meanInterval > 0 {
+		return float64(year) / float64(gd.meanInterval)
+	}
+	return gd.config.PeriodsPerYear
+}
+
+// Regime returns the current volatility regime
+func (gd *GARCHDetector) Regime() VolatilityRegime {
+	return gd.State().Regime
+}
+
+// State returns the current forecast, its percentile and regime, and the
+// fitted parameters
+func (gd *GARCHDetector) State() GARCHState {
+	gd.mu.RLock()
+	defer gd.mu.RUnlock()
+
+	state := GARCHState{
+		Regime:       VolRegimeUnknown,
+		Params:       gd.params,
+		Observations: len(gd.returns),
+		FittedAt:     gd.fittedAt,
+	}
+	if !gd.fitted {
+		return state
+	}
+	state.Volatility = math.Sqrt(gd.variance * gd.periodsPerYear())
+
+	if len(gd.forecasts) < gd.config.MinForecasts {
+		return state
+	}
+	state.Percentile = percentileRank(gd.forecasts, gd.variance)
+	state.Regime = gd.classify(state.Percentile)
+	return state
+}
+
+// classify maps a forecast's percentile to a volatility regime
+func (gd *GARCHDetector) classify(percentile float64) VolatilityRegime {
+	switch {
+	case percentile >= gd.config.ExtremePercentile:
+		return VolRegimeExtreme
+	case percentile >= gd.config.HighPercentile:
+		return VolRegimeHigh
+	case percentile < gd.config.LowPercentile:
+		return VolRegimeLow
+	default:
+		return VolRegimeNormal
+	}
+}
+
+// Params returns the fitted parameters, and false before the first fit
+func (gd *GARCHDetector) Params() (GARCHParams, bool) {
+	gd.mu.RLock()
+	defer gd.mu.RUnlock()
+
+	return gd.params, gd.fitted
+}
+
+// percentileRank returns the fraction of values below x, counting ties as
+// half below
+func percentileRank(values []float64, x float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	below := sort.SearchFloat64s(sorted, x)
+	ties := sort.SearchFloat64s(sorted, math.Nextafter(x, math.Inf(1))) - below
+	return (float64(below) + 0.5*float64(ties)) / float64(len(sorted))
+}
+
+// nelderMead minimizes f from x0 with the Nelder-Mead simplex method,
+// stopping when the simplex's values agree within tol or after maxIter
+// iterations
+func nelderMead(f func([]float64) float64, x0 []float64, step, tol float64, maxIter int) []float64 {
+	n := len(x0)
+	simplex := make([][]float64, n+1)
+	values := make([]float64, n+1)
+	for i := range simplex {
+		simplex[i] = append([]float64(nil), x0...)
+		if i > 0 {
+			simplex[i][i-1] += step
+		}
+		values[i] = f(simplex[i])
+	}
+
+	point := func(centroid, towards []float64, coef float64) []float64 {
+		p := make([]float64, n)
+		for j := range p {
+			p[j] = centroid[j] + coef*(towards[j]-centroid[j])
+		}
+		return p
+	}
+
+	for iter := 0; iter < maxIter; iter++ {
+		order := make([]int, n+1)
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(a, b int) bool { return values[order[a]] < values[order[b]] })
+		sortedSimplex := make([][]float64, n+1)
+		sortedValues := make([]float64, n+1)
+		for i, idx := range order {
+			sortedSimplex[i], sortedValues[i] = simplex[idx], values[idx]
+		}
+		simplex, values = sortedSimplex, sortedValues
+
+		if math.Abs(values[n]-values[0]) <= tol*(math.Abs(values[0])+tol) {
+			break
+		}
+
+		centroid := make([]float64, n)
+		for _, p := range simplex[:n] {
+			for j := range centroid {
+				centroid[j] += p[j] / float64(n)
+			}
+		}
+
+		reflected := point(centroid, simplex[n], -1)
+		fr := f(reflected)
+		switch {
+		case fr < values[0]:
+			expanded := point(centroid, simplex[n], -2)
+			if fe := f(expanded); fe < fr {
+				simplex[n], values[n] = expanded, fe
+			} else {
+				simplex[n], values[n] = reflected, fr
+			}
+		case fr < values[n-1]:
+			simplex[n], values[n] = reflected, fr
+		default:
+			contracted := point(centroid, simplex[n], 0.5)
+			if fc := f(contracted); fc < values[n] {
+				simplex[n], values[n] = contracted, fc
+				continue
+			}
+			// Shrink toward the best point
+			for i := 1; i <= n; i++ {
+				simplex[i] = point(simplex[0], simplex[i], 0.5)
+				values[i] = f(simplex[i])
+			}
+		}
+	}
+
+	best := 0
+	for i := range values {
+		if values[i] < values[best] {
+			best = i
+		}
+	}
+	return simplex[best]
+}
+
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+func logit(p float64) float64 {
+	return math.Log(p / (1 - p))
+}
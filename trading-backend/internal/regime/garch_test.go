@@ -0,0 +1,73 @@
+package regime_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/regime"
+	"go.uber.org/zap"
+)
+
+// simulateGARCH draws n returns from a GARCH(1,1) process
+func simulateGARCH(rng *rand.Rand, n int, omega, alpha, beta float64) []float64 {
+	returns := make([]float64, n)
+	sigma2 := omega / (1 - alpha - beta)
+	for i := range returns {
+		returns[i] = math.Sqrt(sigma2) * rng.NormFloat64()
+		sigma2 = omega + alpha*returns[i]*returns[i] + beta*sigma2
+	}
+	return returns
+}
+
+func TestFitGARCHRecoversParameters(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	returns := simulateGARCH(rng, 5000, 2e-6, 0.1, 0.85)
+
+	params, err := regime.FitGARCH(returns)
+	if err != nil {
+		t.Fatalf("FitGARCH: %v", err)
+	}
+
+	if math.Abs(params.Alpha-0.1) > 0.03 {
+		t.Errorf("alpha = %v, want 0.1", params.Alpha)
+	}
+	if math.Abs(params.Beta-0.85) > 0.05 {
+		t.Errorf("beta = %v, want 0.85", params.Beta)
+	}
+	if math.Abs(params.LongRunVariance()-4e-5)/4e-5 > 0.2 {
+		t.Errorf("long-run variance = %v, want 4e-5", params.LongRunVariance())
+	}
+
+	if _, err := regime.FitGARCH(returns[:10]); err == nil {
+		t.Errorf("FitGARCH accepted 10 returns")
+	}
+}
+
+func TestGARCHDetectorClassifiesVolatilityRegime(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	detector := regime.NewGARCHDetector(zap.NewNop(), nil)
+	if vol := detector.ForecastVolatility(); vol != 0 {
+		t.Fatalf("forecast before any data = %v, want 0", vol)
+	}
+
+	detector.AddReturns(simulateGARCH(rng, 600, 2e-6, 0.1, 0.85))
+	calm := detector.ForecastVolatility()
+	if calm <= 0 {
+		t.Fatalf("forecast after 600 returns = %v, want positive", calm)
+	}
+
+	// A burst of shocks five times the usual size
+	for i := 0; i < 5; i++ {
+		detector.AddReturn(0.03 * rng.NormFloat64())
+		detector.AddReturn(0.03)
+	}
+
+	state := detector.State()
+	if state.Volatility <= calm {
+		t.Errorf("forecast after shocks = %v, want above %v", state.Volatility, calm)
+	}
+	if state.Regime != regime.VolRegimeExtreme && state.Regime != regime.VolRegimeHigh {
+		t.Errorf("regime after shocks = %s at percentile %v, want high or extreme", state.Regime, state.Percentile)
+	}
+}
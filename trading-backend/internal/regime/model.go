@@ -0,0 +1,115 @@
+// Package regime provides persistence of learned HMM parameters, so a
+// restarted detector resumes from its last checkpoint instead of retraining.
+package regime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// modelVersion is the current model file format. Bump it whenever
+// savedModel changes incompatibly.
+const modelVersion = 1
+
+// ErrModelVersion is returned by LoadModel for a model saved in another
+// format. The detector is left untouched and can be used as is.
+var ErrModelVersion = errors.New("unsupported regime model version")
+
+// savedModel is the on-disk form of a detector's learned parameters.
+type savedModel struct {
+	Version          int          `json:"version"`
+	NumStates        int          `json:"num_states"`
+	TransitionMatrix [][]float64  `json:"transition_matrix"`
+	EmissionMeans    []float64    `json:"emission_means"`
+	EmissionVars     []float64    `json:"emission_vars"`
+	State            *RegimeState `json:"state,omitempty"`
+	SavedAt          time.Time    `json:"saved_at"`
+}
+
+// SaveModel writes the transition matrix, emission parameters and current
+// state probabilities to path. The file is replaced atomically, so a crash
+// mid-write leaves the previous checkpoint intact.
+func (rd *RegimeDetector) SaveModel(path string) error {
+	rd.mu.RLock()
+	model := savedModel{
+		Version:          modelVersion,
+		NumStates:        rd.config.NumStates,
+		TransitionMatrix: rd.transitionMatrix,
+		EmissionMeans:    rd.emissionMeans,
+		EmissionVars:     rd.emissionVars,
+		State:            rd.currentState,
+		SavedAt:          time.Now(),
+	}
+	data, err := json.MarshalIndent(model, "", "  ")
+	rd.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal regime model: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create model dir: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write regime model: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace regime model: %w", err)
+	}
+
+	return nil
+}
+
+// LoadModel restores parameters written by SaveModel. It returns
+// ErrModelVersion for a model in another format and an error for one with a
+// different number of states; in both cases the detector is unchanged.
+func (rd *RegimeDetector) LoadModel(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read regime model: %w", err)
+	}
+
+	var model savedModel
+	if err := json.Unmarshal(data, &model); err != nil {
+		return fmt.Errorf("failed to unmarshal regime model: %w", err)
+	}
+
+	if model.Version != modelVersion {
+		return fmt.Errorf("%w: %d, want %d", ErrModelVersion, model.Version, modelVersion)
+	}
+
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+
+	n := rd.config.NumStates
+	if model.NumStates != n || len(model.TransitionMatrix) != n ||
+		len(model.EmissionMeans) != n || len(model.EmissionVars) != n {
+		return fmt.Errorf("regime model has %d states, detector has %d", model.NumStates, n)
+	}
+	for _, row := range model.TransitionMatrix {
+		if len(row) != n {
+			return fmt.Errorf("regime model transition matrix is not %dx%d", n, n)
+		}
+	}
+
+	rd.transitionMatrix = model.TransitionMatrix
+	rd.emissionMeans = model.EmissionMeans
+	rd.emissionVars = model.EmissionVars
+	if model.State != nil {
+		rd.currentState = model.State
+	}
+
+	rd.logger.Info("Loaded regime model",
+		zap.String("path", path),
+		zap.Time("savedAt", model.SavedAt),
+	)
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package regime_test
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/regime"
+	"go.uber.org/zap"
+)
+
+func TestRegimeModelRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "BTCUSDT.json")
+
+	trained := regime.NewRegimeDetector(zap.NewNop(), nil)
+	for i := 0; i < 200; i++ {
+		trained.AddReturn(0.002 * math.Sin(float64(i)))
+	}
+	if err := trained.SaveModel(path); err != nil {
+		t.Fatalf("SaveModel: %v", err)
+	}
+
+	restored := regime.NewRegimeDetector(zap.NewNop(), nil)
+	if err := restored.LoadModel(path); err != nil {
+		t.Fatalf("LoadModel: %v", err)
+	}
+
+	want, got := trained.GetCurrentRegime(), restored.GetCurrentRegime()
+	if got.Primary != want.Primary || got.Confidence != want.Confidence {
+		t.Errorf("restored regime = %s (%v), want %s (%v)", got.Primary, got.Confidence, want.Primary, want.Confidence)
+	}
+	for regimeType, prob := range want.Probabilities {
+		if got.Probabilities[regimeType] != prob {
+			t.Errorf("restored %s probability = %v, want %v", regimeType, got.Probabilities[regimeType], prob)
+		}
+	}
+}
+
+func TestRegimeModelRejectsIncompatible(t *testing.T) {
+	dir := t.TempDir()
+	detector := regime.NewRegimeDetector(zap.NewNop(), nil)
+
+	oldFormat := filepath.Join(dir, "old.json")
+	if err := os.WriteFile(oldFormat, []byte(`{"version": 0}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := detector.LoadModel(oldFormat); !errors.Is(err, regime.ErrModelVersion) {
+		t.Errorf("LoadModel of an old format = %v, want ErrModelVersion", err)
+	}
+
+	// A model trained with more states does not fit this detector
+	config := regime.DefaultRegimeConfig()
+	config.NumStates = 6
+	wider := filepath.Join(dir, "wider.json")
+	if err := regime.NewRegimeDetector(zap.NewNop(), config).SaveModel(wider); err != nil {
+		t.Fatalf("SaveModel: %v", err)
+	}
+	if err := detector.LoadModel(wider); err == nil {
+		t.Errorf("LoadModel accepted a model with a different number of states")
+	}
+}
@@ -0,0 +1,249 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Exchange is a venue quoting the bars being replayed. Market orders fill at
+// the latest bar's close across the spread; limit and stop orders that
+// can't fill yet rest until a later bar trades through their price. Route
+// the replayed symbols to it so the executor prices paper fills from history.
+type Exchange struct {
+	name     string
+	spread   decimal.Decimal // Bid/ask spread as a fraction of price
+	clock    Clock
+	balances map[string]decimal.Decimal
+
+	mu     sync.RWMutex
+	bars   map[string]types.OHLCV // Latest bar by symbol
+	orders map[string]*types.Order
+	nextID int
+}
+
+var _ adapters.ExchangeAdapter = (*Exchange)(nil)
+
+// NewExchange creates a replay venue. balances are reported as configured;
+// paper portfolios track their own cash.
+func NewExchange(name string, spread decimal.Decimal, clock Clock, balances map[string]decimal.Decimal) *Exchange {
+	return &Exchange{
+		name:     name,
+		spread:   spread,
+		clock:    clock,
+		balances: balances,
+		bars:     make(map[string]types.OHLCV),
+		orders:   make(map[string]*types.Order),
+	}
+}
+
+// Name returns the venue name.
+func (x *Exchange) Name() string { return x.name }
+
+// Connect does nothing; the venue is in memory.
+func (x *Exchange) Connect(ctx context.Context) error { return nil }
+
+// Disconnect does nothing; the venue is in memory.
+func (x *Exchange) Disconnect() error { return nil }
+
+// Update records a symbol's next bar, filling resting orders it trades
+// through.
+func (x *Exchange) Update(symbol string, bar types.OHLCV) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.bars[symbol] = bar
+	for _, order := range x.orders {
+		if order.Symbol != symbol || order.Status != types.OrderStatusOpen {
+			continue
+		}
+		if price, ok := restingFill(order, bar); ok {
+			x.fillLocked(order, price)
+		}
+	}
+}
+
+// restingFill returns the price a resting order fills at within bar. Limit
+// orders fill at their limit, and stops at their stop price or the open if
+// the bar gapped through it.
+func restingFill(order *types.Order, bar types.OHLCV) (decimal.Decimal, bool) {
+	buy := order.Side == types.OrderSideBuy
+	switch order.Type {
+	case types.OrderTypeLimit, types.OrderTypeTakeProfit:
+		if buy && bar.Low.LessThanOrEqual(order.Price) || !buy && bar.High.GreaterThanOrEqual(order.Price) {
+			return order.Price, true
+		}
+	case types.OrderTypeStopMarket, types.OrderTypeStopLoss, types.OrderTypeStopLimit:
+		if buy && bar.High.GreaterThanOrEqual(order.StopPrice) {
+			return decimal.Max(order.StopPrice, bar.Open), true
+		}
+		if !buy && bar.Low.LessThanOrEqual(order.StopPrice) {
+			return decimal.Min(order.StopPrice, bar.Open), true
+		}
+	}
+	return decimal.Zero, false
+}
+
+// quoteLocked returns the bid and ask around a symbol's latest close.
+func (x *Exchange) quoteLocked(symbol string) (bid, ask decimal.Decimal, err error) {
+	bar, ok := x.bars[symbol]
+	if !ok {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("no replayed price for %s", symbol)
+	}
+	half := bar.Close.Mul(x.spread).Div(decimal.NewFromInt(2))
+	return bar.Close.Sub(half), bar.Close.Add(half), nil
+}
+
+// PlaceOrder fills a market order, or a limit order priced through the
+// quote, immediately. Other orders rest.
+func (x *Exchange) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	if !order.Quantity.IsPositive() {
+		return nil, fmt.Errorf("invalid quantity %s", order.Quantity)
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	bid, ask, err := x.quoteLocked(order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	x.nextID++
+	placed := *order
+	placed.ID = fmt.Sprintf("replay-%d", x.nextID)
+	placed.Status = types.OrderStatusOpen
+	placed.CreatedAt = x.clock.Now()
+	placed.UpdatedAt = placed.CreatedAt
+	x.orders[placed.ID] = &placed
+
+	buy := order.Side == types.OrderSideBuy
+	switch order.Type {
+	case types.OrderTypeMarket:
+		if buy {
+			x.fillLocked(&placed, ask)
+		} else {
+			x.fillLocked(&placed, bid)
+		}
+	case types.OrderTypeLimit, types.OrderTypeTakeProfit:
+		if buy && ask.LessThanOrEqual(order.Price) {
+			x.fillLocked(&placed, ask)
+		} else if !buy && bid.GreaterThanOrEqual(order.Price) {
+			x.fillLocked(&placed, bid)
+		}
+	}
+
+	result := placed
+	return &result, nil
+}
+
+// fillLocked fills an order in full at price.
+func (x *Exchange) fillLocked(order *types.Order, price decimal.Decimal) {
+	now := x.clock.Now()
+	order.Status = types.OrderStatusFilled
+	order.FilledQty = order.Quantity
+	order.AvgFillPrice = price
+	order.UpdatedAt = now
+	order.FilledAt = &now
+}
+
+// CancelOrder cancels a resting order.
+func (x *Exchange) CancelOrder(ctx context.Context, orderID string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	order, ok := x.orders[orderID]
+	if !ok {
+		return fmt.Errorf("order not found: %s", orderID)
+	}
+	if order.Status != types.OrderStatusOpen {
+		return fmt.Errorf("order %s is %s", orderID, order.Status)
+	}
+	order.Status = types.OrderStatusCancelled
+	order.UpdatedAt = x.clock.Now()
+	return nil
+}
+
+// GetOrder returns a copy of an order.
+func (x *Exchange) GetOrder(ctx context.Context, orderID string) (*types.Order, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	order, ok := x.orders[orderID]
+	if !ok {
+		return nil, fmt.Errorf("order not found: %s", orderID)
+	}
+	copied := *order
+	return &copied, nil
+}
+
+// GetOpenOrders returns the resting orders for symbol, or for every symbol
+// when symbol is empty, oldest first.
+func (x *Exchange) GetOpenOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var open []*types.Order
+	for _, order := range x.orders {
+		if order.Status == types.OrderStatusOpen && (symbol == "" || order.Symbol == symbol) {
+			copied := *order
+			open = append(open, &copied)
+		}
+	}
+	sort.Slice(open, func(i, j int) bool { return open[i].CreatedAt.Before(open[j].CreatedAt) })
+	return open, nil
+}
+
+// GetBalance returns the configured balance of an asset.
+func (x *Exchange) GetBalance(ctx context.Context, asset string) (decimal.Decimal, error) {
+	return x.balances[asset], nil
+}
+
+// GetPositions returns no positions; the venue is spot.
+func (x *Exchange) GetPositions(ctx context.Context) ([]*types.Position, error) {
+	return nil, nil
+}
+
+// GetOrderBook returns a one-level book at the quote, sized by the latest
+// bar's volume.
+func (x *Exchange) GetOrderBook(ctx context.Context, symbol string, limit int) (*types.OrderBook, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	bid, ask, err := x.quoteLocked(symbol)
+	if err != nil {
+		return nil, err
+	}
+	volume := x.bars[symbol].Volume
+	return &types.OrderBook{
+		Symbol:    symbol,
+		Bids:      []types.OrderBookLevel{{Price: bid, Quantity: volume}},
+		Asks:      []types.OrderBookLevel{{Price: ask, Quantity: volume}},
+		Timestamp: x.clock.Now(),
+	}, nil
+}
+
+// GetTicker returns the quote around the latest close.
+func (x *Exchange) GetTicker(ctx context.Context, symbol string) (*adapters.Ticker, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	bid, ask, err := x.quoteLocked(symbol)
+	if err != nil {
+		return nil, err
+	}
+	bar := x.bars[symbol]
+	return &adapters.Ticker{
+		Symbol:    symbol,
+		LastPrice: bar.Close,
+		BidPrice:  bid,
+		AskPrice:  ask,
+		Volume:    bar.Volume,
+		Timestamp: x.clock.Now(),
+	}, nil
+}
@@ -0,0 +1,64 @@
+package replay_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/replay"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+func bar(open, high, low, close int64) types.OHLCV {
+	return types.OHLCV{
+		Timestamp: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Open:      decimal.NewFromInt(open),
+		High:      decimal.NewFromInt(high),
+		Low:       decimal.NewFromInt(low),
+		Close:     decimal.NewFromInt(close),
+		Volume:    decimal.NewFromInt(5),
+	}
+}
+
+func TestReplayExchangeFillsRestingOrdersAsBarsTradeThrough(t *testing.T) {
+	ctx := context.Background()
+	exchange := replay.NewExchange("replay", decimal.Zero, replay.NewSimClock(time.Now()), nil)
+
+	if _, err := exchange.PlaceOrder(ctx, &types.Order{Symbol: "BTCUSDT", Type: types.OrderTypeMarket, Quantity: decimal.NewFromInt(1)}); err == nil {
+		t.Error("Expected an order before any replayed price to be rejected")
+	}
+	exchange.Update("BTCUSDT", bar(100, 101, 99, 100))
+
+	limit, _ := exchange.PlaceOrder(ctx, &types.Order{
+		Symbol: "BTCUSDT", Side: types.OrderSideBuy, Type: types.OrderTypeLimit,
+		Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(95),
+	})
+	stop, _ := exchange.PlaceOrder(ctx, &types.Order{
+		Symbol: "BTCUSDT", Side: types.OrderSideSell, Type: types.OrderTypeStopLoss,
+		Quantity: decimal.NewFromInt(1), StopPrice: decimal.NewFromInt(90),
+	})
+	if limit.Status != types.OrderStatusOpen || stop.Status != types.OrderStatusOpen {
+		t.Fatalf("Expected both orders to rest, got %s and %s", limit.Status, stop.Status)
+	}
+	if open, _ := exchange.GetOpenOrders(ctx, "BTCUSDT"); len(open) != 2 {
+		t.Errorf("Expected two open orders, got %d", len(open))
+	}
+
+	// Trades down through the limit but not the stop
+	exchange.Update("BTCUSDT", bar(98, 99, 94, 96))
+	if got, _ := exchange.GetOrder(ctx, limit.ID); got.Status != types.OrderStatusFilled || !got.AvgFillPrice.Equal(decimal.NewFromInt(95)) {
+		t.Errorf("Expected the limit filled at 95, got %s at %s", got.Status, got.AvgFillPrice)
+	}
+
+	// Gaps below the stop, filling at the open
+	exchange.Update("BTCUSDT", bar(85, 88, 80, 82))
+	if got, _ := exchange.GetOrder(ctx, stop.ID); got.Status != types.OrderStatusFilled || !got.AvgFillPrice.Equal(decimal.NewFromInt(85)) {
+		t.Errorf("Expected the stop filled at the 85 open, got %s at %s", got.Status, got.AvgFillPrice)
+	}
+
+	ticker, err := exchange.GetTicker(ctx, "BTCUSDT")
+	if err != nil || !ticker.LastPrice.Equal(decimal.NewFromInt(82)) {
+		t.Errorf("Expected the ticker at the latest close, got %+v (%v)", ticker, err)
+	}
+}
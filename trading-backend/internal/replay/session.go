@@ -0,0 +1,384 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/events"
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// Session statuses
+const (
+	SessionRunning   = "running"
+	SessionCompleted = "completed"
+	SessionStopped   = "stopped"
+	SessionFailed    = "failed"
+)
+
+// ErrSessionRunning is returned when starting a replay while one runs.
+var ErrSessionRunning = errors.New("a replay session is already running")
+
+// SymbolRouter routes symbols' orders to an adapter, replacing any existing
+// routes. *execution.Executor implements it.
+type SymbolRouter interface {
+	SetSymbolRoutes(routes map[string]adapters.ExchangeAdapter)
+}
+
+// ManagerConfig configures replay sessions.
+type ManagerConfig struct {
+	DefaultSpeed   float64                    `json:"defaultSpeed"`   // Speed of requests that don't set one
+	Spread         decimal.Decimal            `json:"spread"`         // Bid/ask spread of the replay exchange, as a fraction of price
+	Balances       map[string]decimal.Decimal `json:"balances"`       // Balances the replay exchange reports
+	UpdateInterval time.Duration              `json:"updateInterval"` // Minimum real time between progress updates
+	MaxRetained    int                        `json:"maxRetained"`    // Finished sessions kept for polling
+}
+
+// DefaultManagerConfig returns 100x real time over a 2 basis point spread.
+func DefaultManagerConfig() ManagerConfig {
+	return ManagerConfig{
+		DefaultSpeed:   100,
+		Spread:         decimal.NewFromFloat(0.0002),
+		Balances:       map[string]decimal.Decimal{"USDT": decimal.NewFromInt(10000)},
+		UpdateInterval: time.Second,
+		MaxRetained:    20,
+	}
+}
+
+// Request is the body of POST /api/v1/replay.
+type Request struct {
+	Symbols   []string        `json:"symbols"`
+	Timeframe types.Timeframe `json:"timeframe"`
+	Start     time.Time       `json:"start"`
+	End       time.Time       `json:"end"`
+	Speed     float64         `json:"speed,omitempty"`   // Multiple of real time; zero uses the default
+	Unpaced   bool            `json:"unpaced,omitempty"` // Replay as fast as the bus accepts
+}
+
+// Execution is a fill made during a replay, at the simulated time it
+// happened.
+type Execution struct {
+	SimTime time.Time              `json:"simTime"`
+	Event   *events.ExecutionEvent `json:"event"`
+}
+
+// Session is the state of a replay.
+type Session struct {
+	ID          string      `json:"id"`
+	Status      string      `json:"status"`
+	Request     Request     `json:"request"`
+	SimTime     time.Time   `json:"simTime"`
+	Replayed    int         `json:"replayed"` // Bars replayed so far
+	TotalBars   int         `json:"totalBars"`
+	Progress    float64     `json:"progress"` // Percent of bars replayed
+	Executions  []Execution `json:"executions"`
+	Error       string      `json:"error,omitempty"`
+	StartedAt   time.Time   `json:"startedAt"`
+	CompletedAt time.Time   `json:"completedAt,omitempty"`
+}
+
+// Manager runs one replay session at a time. While it runs, orders for the
+// replayed symbols are routed to a replay exchange quoting the historical
+// bars, and the bars are published through the event bus, so the
+// orchestrator and agent trade the past as they would trade live.
+type Manager struct {
+	logger *zap.Logger
+	config ManagerConfig
+	loader HistoryLoader
+	bus    *events.EventBus
+	router SymbolRouter
+
+	mu        sync.RWMutex
+	sessions  map[string]*Session
+	finished  []string // Finished session IDs, oldest first
+	active    string
+	cancel    context.CancelFunc
+	done      chan struct{}
+	replaying map[string]bool // Normalized symbols of the active session
+	onUpdate  func(*Session)
+	onBar     func(Bar)
+}
+
+// NewManager creates a replay manager loading history from loader.
+func NewManager(logger *zap.Logger, config ManagerConfig, loader HistoryLoader, bus *events.EventBus) *Manager {
+	return &Manager{
+		logger:    logger.Named("replay"),
+		config:    config,
+		loader:    loader,
+		bus:       bus,
+		sessions:  make(map[string]*Session),
+		replaying: make(map[string]bool),
+	}
+}
+
+// SetRouter sets the router the replayed symbols' orders are routed through.
+func (m *Manager) SetRouter(router SymbolRouter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.router = router
+}
+
+// OnUpdate sets the callback told of session progress, at most once per
+// UpdateInterval, and when a session ends.
+func (m *Manager) OnUpdate(fn func(*Session)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onUpdate = fn
+}
+
+// OnBar sets the callback told of each replayed bar, for components fed
+// prices directly rather than through the bus.
+func (m *Manager) OnBar(fn func(Bar)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onBar = fn
+}
+
+// Replaying reports whether the active session replays symbol. Live prices
+// for it should be held back until the session ends.
+func (m *Manager) Replaying(symbol string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.replaying[normalizeSymbol(symbol)]
+}
+
+// Start validates a request, loads its history and starts replaying it.
+func (m *Manager) Start(req Request) (*Session, error) {
+	if req.Speed < 0 {
+		return nil, fmt.Errorf("invalid speed %g", req.Speed)
+	}
+	speed := req.Speed
+	if speed == 0 {
+		speed = m.config.DefaultSpeed
+	}
+	if req.Unpaced {
+		speed = 0
+	}
+
+	feeder, err := NewFeeder(m.logger, FeederConfig{
+		Symbols:   req.Symbols,
+		Timeframe: req.Timeframe,
+		Start:     req.Start,
+		End:       req.End,
+		Speed:     speed,
+	}, m.loader, m.bus)
+	if err != nil {
+		return nil, err
+	}
+	exchange := NewExchange("replay", m.config.Spread, feeder.Clock(), m.config.Balances)
+	feeder.SetExchange(exchange)
+
+	m.mu.Lock()
+	if m.active != "" {
+		m.mu.Unlock()
+		return nil, ErrSessionRunning
+	}
+	session := &Session{
+		ID:        uuid.New().String(),
+		Status:    SessionRunning,
+		Request:   req,
+		SimTime:   req.Start,
+		TotalBars: feeder.Len(),
+		StartedAt: time.Now(),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.sessions[session.ID] = session
+	m.active = session.ID
+	m.cancel = cancel
+	m.done = make(chan struct{})
+	for _, symbol := range req.Symbols {
+		m.replaying[normalizeSymbol(symbol)] = true
+	}
+	if m.router != nil {
+		routes := make(map[string]adapters.ExchangeAdapter, len(req.Symbols))
+		for _, symbol := range req.Symbols {
+			routes[symbol] = exchange
+		}
+		m.router.SetSymbolRoutes(routes)
+	}
+	onBar := m.onBar
+	done := m.done
+	snapshot := m.snapshotLocked(session)
+	m.mu.Unlock()
+
+	feeder.OnBar(onBar)
+
+	m.logger.Info("Replay started",
+		zap.String("id", session.ID),
+		zap.Strings("symbols", req.Symbols),
+		zap.Time("start", req.Start),
+		zap.Time("end", req.End),
+		zap.Float64("speed", speed),
+		zap.Int("bars", feeder.Len()))
+
+	go m.run(ctx, session.ID, feeder, done)
+	return snapshot, nil
+}
+
+// run replays a session's bars, recording executions as they happen.
+func (m *Manager) run(ctx context.Context, id string, feeder *Feeder, done chan struct{}) {
+	defer close(done)
+
+	// Handled inline so a fill is stamped with the bar it was made on
+	clock := feeder.Clock()
+	sub := m.bus.Subscribe(events.EventTypeExecution, func(e events.Event) error {
+		execution, ok := e.(*events.ExecutionEvent)
+		if !ok || !m.Replaying(execution.Symbol) {
+			return nil
+		}
+		m.update(id, func(session *Session) {
+			session.Executions = append(session.Executions, Execution{SimTime: clock.Now(), Event: execution})
+		})
+		return nil
+	}, events.SubscriptionOptions{Async: false})
+	defer m.bus.Unsubscribe(sub)
+
+	var lastUpdate time.Time
+	err := feeder.Run(ctx, func(replayed int) {
+		m.update(id, func(session *Session) {
+			session.Replayed = replayed
+			session.SimTime = clock.Now()
+			session.Progress = float64(replayed) / float64(session.TotalBars) * 100
+		})
+		if time.Since(lastUpdate) >= m.config.UpdateInterval {
+			lastUpdate = time.Now()
+			m.notify(id)
+		}
+	})
+
+	status := SessionCompleted
+	switch {
+	case errors.Is(err, context.Canceled):
+		status = SessionStopped
+	case err != nil:
+		status = SessionFailed
+	}
+
+	m.mu.Lock()
+	if session, ok := m.sessions[id]; ok {
+		session.Status = status
+		session.CompletedAt = time.Now()
+		if status == SessionFailed {
+			session.Error = err.Error()
+		}
+	}
+	m.active = ""
+	m.cancel = nil
+	m.replaying = make(map[string]bool)
+	if m.router != nil {
+		// Restore routing to the executor's own adapters
+		m.router.SetSymbolRoutes(nil)
+	}
+	m.finished = append(m.finished, id)
+	for len(m.finished) > m.config.MaxRetained {
+		delete(m.sessions, m.finished[0])
+		m.finished = m.finished[1:]
+	}
+	m.mu.Unlock()
+
+	m.logger.Info("Replay finished", zap.String("id", id), zap.String("status", status), zap.Error(err))
+	m.notify(id)
+}
+
+// Stop stops a running session.
+func (m *Manager) Stop(id string) error {
+	m.mu.RLock()
+	session, ok := m.sessions[id]
+	var cancel context.CancelFunc
+	var done chan struct{}
+	if ok && m.active == id {
+		cancel, done = m.cancel, m.done
+	}
+	m.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("replay session not found: %s", id)
+	}
+	if cancel == nil {
+		return fmt.Errorf("replay session %s is %s", id, session.Status)
+	}
+	cancel()
+	<-done
+	return nil
+}
+
+// Close stops the running session, if any.
+func (m *Manager) Close() {
+	m.mu.RLock()
+	id := m.active
+	m.mu.RUnlock()
+	if id != "" {
+		m.Stop(id)
+	}
+}
+
+// Get returns a copy of a session.
+func (m *Manager) Get(id string) (*Session, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, false
+	}
+	return m.snapshotLocked(session), true
+}
+
+// List returns copies of the retained sessions, newest first.
+func (m *Manager) List() []*Session {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sessions := make([]*Session, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		sessions = append(sessions, m.snapshotLocked(session))
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].StartedAt.After(sessions[j].StartedAt) })
+	return sessions
+}
+
+// update applies fn to a session under the lock.
+func (m *Manager) update(id string, fn func(*Session)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if session, ok := m.sessions[id]; ok {
+		fn(session)
+	}
+}
+
+// notify tells the update callback of a session's state.
+func (m *Manager) notify(id string) {
+	m.mu.RLock()
+	fn := m.onUpdate
+	session, ok := m.sessions[id]
+	var snapshot *Session
+	if ok {
+		snapshot = m.snapshotLocked(session)
+	}
+	m.mu.RUnlock()
+
+	if fn != nil && snapshot != nil {
+		fn(snapshot)
+	}
+}
+
+// snapshotLocked returns a copy of a session. The caller must hold m.mu.
+func (m *Manager) snapshotLocked(session *Session) *Session {
+	copied := *session
+	copied.Executions = append([]Execution(nil), session.Executions...)
+	return &copied
+}
+
+// normalizeSymbol strips separators so "BTC/USDT" and "BTCUSDT" match.
+func normalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.NewReplacer("/", "", "-", "", "_", "").Replace(symbol))
+}
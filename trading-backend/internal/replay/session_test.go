@@ -0,0 +1,223 @@
+package replay_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/events"
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/internal/replay"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// memLoader serves bars from memory
+type memLoader map[string][]*types.OHLCV
+
+func (l memLoader) LoadHistory(symbol string, timeframe types.Timeframe) ([]*types.OHLCV, error) {
+	return l[symbol], nil
+}
+
+// minuteBars builds 1m bars closing at the given prices from 2024-03-01
+func minuteBars(offset time.Duration, closes ...int64) []*types.OHLCV {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).Add(offset)
+	bars := make([]*types.OHLCV, len(closes))
+	for i, c := range closes {
+		price := decimal.NewFromInt(c)
+		bars[i] = &types.OHLCV{
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Open:      price, High: price, Low: price, Close: price,
+			Volume: decimal.NewFromInt(10),
+		}
+	}
+	return bars
+}
+
+// recordingRouter keeps the latest routes
+type recordingRouter struct {
+	mu     sync.Mutex
+	routes map[string]adapters.ExchangeAdapter
+}
+
+func (r *recordingRouter) SetSymbolRoutes(routes map[string]adapters.ExchangeAdapter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = routes
+}
+
+func (r *recordingRouter) route(symbol string) adapters.ExchangeAdapter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.routes[symbol]
+}
+
+func TestReplaySessionPublishesHistoryAndRoutesFills(t *testing.T) {
+	loader := memLoader{
+		"BTCUSDT": minuteBars(0, 50000, 49000, 48000),
+		"ETHUSDT": minuteBars(30*time.Second, 3000, 2900, 2800),
+	}
+	// One partition delivers events in the order published
+	busConfig := events.DefaultEventBusConfig()
+	busConfig.Partitions = 1
+	bus := events.NewEventBus(zap.NewNop(), busConfig)
+	defer bus.Stop()
+
+	var mu sync.Mutex
+	var barOrder []string
+	var ticks []*events.TickEvent
+	bus.Subscribe(events.EventTypeBar, func(e events.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		barOrder = append(barOrder, e.(*events.BarEvent).Symbol)
+		return nil
+	})
+	bus.Subscribe(events.EventTypeTick, func(e events.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		ticks = append(ticks, e.(*events.TickEvent))
+		return nil
+	})
+
+	config := replay.DefaultManagerConfig()
+	config.Spread = decimal.NewFromFloat(0.001)
+	config.UpdateInterval = time.Hour
+	manager := replay.NewManager(zap.NewNop(), config, loader, bus)
+	router := &recordingRouter{}
+	manager.SetRouter(router)
+
+	// Stand in for the executor: buy BTC on the second bar through the
+	// route, and report the fill the way it does
+	fills := make(chan *types.Order, 1)
+	manager.OnBar(func(bar replay.Bar) {
+		if bar.Symbol != "BTCUSDT" || !bar.Close.Equal(decimal.NewFromInt(49000)) {
+			return
+		}
+		order, err := router.route("BTCUSDT").PlaceOrder(context.Background(), &types.Order{
+			Symbol: "BTCUSDT", Side: types.OrderSideBuy, Type: types.OrderTypeMarket, Quantity: decimal.NewFromInt(1),
+		})
+		if err != nil {
+			t.Errorf("PlaceOrder: %v", err)
+			return
+		}
+		fills <- order
+		bus.PublishSync(&events.ExecutionEvent{
+			BaseEvent: events.NewBaseEvent(events.EventTypeExecution, "BTCUSDT"),
+			OrderID:   order.ID, Symbol: "BTCUSDT", Side: "buy", Quantity: 1, Price: order.AvgFillPrice.InexactFloat64(),
+		})
+	})
+
+	updates := make(chan *replay.Session, 10)
+	manager.OnUpdate(func(s *replay.Session) { updates <- s })
+
+	session, err := manager.Start(replay.Request{
+		Symbols:   []string{"BTCUSDT", "ETHUSDT"},
+		Timeframe: types.Timeframe1m,
+		Start:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		End:       time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		Unpaced:   true,
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if session.TotalBars != 6 || !manager.Replaying("BTC/USDT") {
+		t.Errorf("Expected six bars replaying BTC, got %d", session.TotalBars)
+	}
+
+	var final *replay.Session
+	for final == nil {
+		select {
+		case s := <-updates:
+			if s.Status != replay.SessionRunning {
+				final = s
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected the replay to finish")
+		}
+	}
+
+	if final.Status != replay.SessionCompleted || final.Replayed != 6 || final.Progress != 100 {
+		t.Errorf("Expected all six bars replayed, got %+v", final)
+	}
+	if want := time.Date(2024, 3, 1, 0, 2, 30, 0, time.UTC).Add(time.Minute); !final.SimTime.Equal(want) {
+		t.Errorf("Expected the clock at the last close %s, got %s", want, final.SimTime)
+	}
+
+	// Filled at the second bar's close plus half the spread
+	order := <-fills
+	if order.Status != types.OrderStatusFilled || !order.AvgFillPrice.Equal(decimal.NewFromFloat(49024.5)) {
+		t.Errorf("Expected a fill at 49024.5, got %s at %s", order.Status, order.AvgFillPrice)
+	}
+	if len(final.Executions) != 1 || !final.Executions[0].SimTime.Equal(time.Date(2024, 3, 1, 0, 2, 0, 0, time.UTC)) {
+		t.Errorf("Expected the fill recorded at its simulated time, got %+v", final.Executions)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		delivered := len(ticks)
+		mu.Unlock()
+		if delivered == 6 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	mu.Lock()
+	if got := len(barOrder); got != 6 || barOrder[0] != "BTCUSDT" || barOrder[1] != "ETHUSDT" {
+		t.Errorf("Expected bars interleaved by time, got %v", barOrder)
+	}
+	if len(ticks) != 6 || !ticks[0].AskPrice.Equal(decimal.NewFromInt(50025)) {
+		t.Errorf("Expected a tick quoting each close, got %d", len(ticks))
+	}
+	mu.Unlock()
+
+	if router.route("BTCUSDT") != nil || manager.Replaying("BTCUSDT") {
+		t.Error("Expected routes restored once the replay finished")
+	}
+}
+
+func TestReplaySessionStopsAndRejectsBadRequests(t *testing.T) {
+	loader := memLoader{"BTCUSDT": minuteBars(0, 1, 2, 3, 4, 5)}
+	bus := events.NewEventBus(zap.NewNop(), events.DefaultEventBusConfig())
+	defer bus.Stop()
+	manager := replay.NewManager(zap.NewNop(), replay.DefaultManagerConfig(), loader, bus)
+
+	request := replay.Request{
+		Symbols:   []string{"BTCUSDT"},
+		Timeframe: types.Timeframe1m,
+		Start:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		End:       time.Date(2024, 3, 1, 1, 0, 0, 0, time.UTC),
+		Speed:     1, // A minute between bars
+	}
+	updates := make(chan *replay.Session, 10)
+	manager.OnUpdate(func(s *replay.Session) { updates <- s })
+	session, err := manager.Start(request)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if first := <-updates; first.Replayed != 1 {
+		t.Fatalf("Expected an update after the first bar, got %+v", first)
+	}
+	if _, err := manager.Start(request); err != replay.ErrSessionRunning {
+		t.Errorf("Expected a second session to be refused, got %v", err)
+	}
+
+	if err := manager.Stop(session.ID); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if stopped, _ := manager.Get(session.ID); stopped.Status != replay.SessionStopped || stopped.Replayed != 1 {
+		t.Errorf("Expected the session stopped after its first bar, got %+v", stopped)
+	}
+
+	for name, req := range map[string]replay.Request{
+		"no history":    {Symbols: []string{"SOLUSDT"}, Timeframe: types.Timeframe1m, Start: request.Start, End: request.End},
+		"bad timeframe": {Symbols: []string{"BTCUSDT"}, Timeframe: "7m", Start: request.Start, End: request.End},
+		"empty range":   {Symbols: []string{"BTCUSDT"}, Timeframe: types.Timeframe1m, Start: request.End, End: request.Start},
+	} {
+		if _, err := manager.Start(req); err == nil {
+			t.Errorf("%s: expected the request to be rejected", name)
+		}
+	}
+}
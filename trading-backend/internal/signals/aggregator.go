@@ -4,11 +4,18 @@ package signals
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/atlas-desktop/trading-backend/internal/blockchain"
 	"github.com/atlas-desktop/trading-backend/pkg/types"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
@@ -23,6 +30,12 @@ type SignalSource interface {
 	Health() SourceHealth
 }
 
+// ConfidenceCalibrator maps a source's stated confidence to the probability
+// its signals have realized.
+type ConfidenceCalibrator interface {
+	CalibrateSourceConfidence(source string, raw decimal.Decimal) decimal.Decimal
+}
+
 // SignalSourceType categorizes signal sources.
 type SignalSourceType string
 
@@ -44,6 +57,7 @@ type SourceHealth struct {
 	Latency         time.Duration `json:"latency"`
 	ErrorRate       float64       `json:"errorRate"`
 	LastError       string        `json:"lastError,omitempty"`
+	CacheHitRate    float64       `json:"cacheHitRate,omitempty"`
 }
 
 // AggregatedSignal combines signals from multiple sources.
@@ -63,6 +77,14 @@ type AggregatedSignal struct {
 	SuggestedStop   decimal.Decimal      `json:"suggestedStop,omitempty"`
 	SuggestedTarget decimal.Decimal      `json:"suggestedTarget,omitempty"`
 	RiskRewardRatio decimal.Decimal      `json:"riskRewardRatio,omitempty"`
+	
+	// Set when the signal passed only the relaxed DegradedMinSources
+	// threshold; PositionScale then scales the maximum position size down
+	Degraded        bool                 `json:"degraded,omitempty"`
+	PositionScale   decimal.Decimal      `json:"positionScale"`
+	
+	// Aggregation details, e.g. effective per-source weights
+	Metadata        map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // Aggregator combines signals from multiple sources.
@@ -71,9 +93,18 @@ type Aggregator struct {
 	sources map[string]SignalSource
 	weights map[string]decimal.Decimal // Source weights
 	
+	// Calibrates source confidence against realized outcomes, when set
+	calibrator ConfidenceCalibrator
+	
 	// State
 	latestSignals map[string][]*types.Signal // symbol -> signals
 	aggregated    map[string]*AggregatedSignal
+	explanations  map[string]*SignalExplanation // symbol -> latest aggregation's breakdown
+	
+	// Symbols short of healthy sources, and changes not yet reported
+	degradations        map[string]SourceDegradation
+	pendingDegradations []SourceDegradation
+	onDegradation       func(SourceDegradation)
 	
 	// Configuration
 	config AggregatorConfig
@@ -93,9 +124,21 @@ type AggregatorConfig struct {
 	// Aggregation settings
 	AggregationWindow  time.Duration          `json:"aggregationWindow"`
 	MinSources         int                    `json:"minSources"` // Minimum sources for valid signal
+	
+	// Below MinSources, keep trading while at least DegradedMinSources
+	// remain, at DegradedPositionScale of the maximum position size.
+	// Zero stops trading the symbol until sources recover.
+	DegradedMinSources    int                 `json:"degradedMinSources"`
+	DegradedPositionScale decimal.Decimal     `json:"degradedPositionScale"`
 	MinConfidence      decimal.Decimal        `json:"minConfidence"`
 	MinConsensus       decimal.Decimal        `json:"minConsensus"`
 	
+	// Conflict resolution. When buy and sell weights differ by no more than
+	// ConflictEpsilon of their sum, ConflictPolicy picks the direction. A
+	// resolved signal keeps its split consensus, which scales confidence.
+	ConflictPolicy     ConflictPolicy         `json:"conflictPolicy"`
+	ConflictEpsilon    decimal.Decimal        `json:"conflictEpsilon"`
+	
 	// Source weights
 	SourceWeights      map[string]decimal.Decimal `json:"sourceWeights"`
 	TypeWeights        map[SignalSourceType]decimal.Decimal `json:"typeWeights"`
@@ -104,9 +147,32 @@ type AggregatorConfig struct {
 	MinStrength        decimal.Decimal        `json:"minStrength"`
 	MaxAge             time.Duration          `json:"maxAge"`
 	
+	// Time decay. When DecayHalfLife is set, each signal is weighted by
+	// 0.5^(age/DecayHalfLife) and all signals in the window contribute.
+	// LatestSignalOnly keeps using only each source's most recent signal.
+	DecayHalfLife      time.Duration          `json:"decayHalfLife"`
+	LatestSignalOnly   bool                   `json:"latestSignalOnly"`
+	
 	// Output
 	SignalBufferSize   int                    `json:"signalBufferSize"`
 	EmitInterval       time.Duration          `json:"emitInterval"`
+	
+	// Sources are created and added by NewAggregator
+	Sources            []SignalSourceConfig   `json:"sources"`
+}
+
+// SignalSourceConfig declares a signal source to build from configuration.
+type SignalSourceConfig struct {
+	Type         SignalSourceType `json:"type"`
+	Enabled      bool             `json:"enabled"`
+	Weight       float64          `json:"weight"` // Overrides the type weight when positive
+	URL          string           `json:"url"`
+	APIKey       string           `json:"apiKey"`
+	Provider     string           `json:"provider"`
+	PollInterval time.Duration    `json:"pollInterval"`
+	
+	// TradeSubscriber streams trades to an order flow source
+	TradeSubscriber TradeSubscriber `json:"-"`
 }
 
 // DefaultAggregatorConfig returns sensible defaults.
@@ -114,8 +180,10 @@ func DefaultAggregatorConfig() AggregatorConfig {
 	return AggregatorConfig{
 		AggregationWindow:  5 * time.Minute,
 		MinSources:         2,
+		DegradedPositionScale: decimal.NewFromFloat(0.5),
 		MinConfidence:      decimal.NewFromFloat(0.6),
 		MinConsensus:       decimal.NewFromFloat(0.5),
+		ConflictPolicy:     ConflictHold,
 		SourceWeights:      make(map[string]decimal.Decimal),
 		TypeWeights: map[SignalSourceType]decimal.Decimal{
 			SourceTypeTechnical: decimal.NewFromFloat(1.0),
@@ -135,15 +203,86 @@ func DefaultAggregatorConfig() AggregatorConfig {
 
 // NewAggregator creates a new signal aggregator.
 func NewAggregator(logger *zap.Logger, config AggregatorConfig) *Aggregator {
-	return &Aggregator{
+	weights := config.SourceWeights
+	if weights == nil {
+		weights = make(map[string]decimal.Decimal)
+	}
+	
+	if !config.ConflictPolicy.Valid() {
+		logger.Warn("Unknown conflict policy, holding on conflicts",
+			zap.String("policy", string(config.ConflictPolicy)))
+		config.ConflictPolicy = ConflictHold
+	}
+	
+	a := &Aggregator{
 		logger:        logger.Named("signal-aggregator"),
 		sources:       make(map[string]SignalSource),
-		weights:       config.SourceWeights,
+		weights:       weights,
 		latestSignals: make(map[string][]*types.Signal),
 		aggregated:    make(map[string]*AggregatedSignal),
+		explanations:  make(map[string]*SignalExplanation),
+		degradations:  make(map[string]SourceDegradation),
 		config:        config,
 		signals:       make(chan *AggregatedSignal, config.SignalBufferSize),
 	}
+	
+	for _, sourceConfig := range config.Sources {
+		if !sourceConfig.Enabled {
+			continue
+		}
+		
+		source, err := NewSignalSource(logger, sourceConfig)
+		if err != nil {
+			a.logger.Warn("Skipping signal source", zap.String("type", string(sourceConfig.Type)), zap.Error(err))
+			continue
+		}
+		
+		if sourceConfig.Weight > 0 {
+			a.weights[source.Name()] = decimal.NewFromFloat(sourceConfig.Weight)
+		}
+		a.AddSource(source)
+	}
+	
+	return a
+}
+
+// NewSignalSource builds a signal source from its configuration.
+func NewSignalSource(logger *zap.Logger, config SignalSourceConfig) (SignalSource, error) {
+	switch config.Type {
+	case SourceTypeTechnical:
+		return NewTechnicalSignalSource(logger, config.URL, config.APIKey), nil
+	case SourceTypeSentiment:
+		return NewSentimentSignalSource(logger, config.URL, config.APIKey), nil
+	case SourceTypeOnChain:
+		return NewOnChainSignalSource(logger, nil), nil
+	case SourceTypeAI, "perplexity":
+		perplexityConfig := DefaultPerplexityConfig()
+		perplexityConfig.APIKey = config.APIKey
+		return NewPerplexitySignalSource(logger, perplexityConfig), nil
+	case SourceTypeNews:
+		return NewNewsSignalSource(logger, NewsSourceConfig{
+			Provider:     NewsProvider(config.Provider),
+			URL:          config.URL,
+			APIKey:       config.APIKey,
+			PollInterval: config.PollInterval,
+		}), nil
+	case SourceTypeOrderFlow:
+		if config.TradeSubscriber == nil {
+			return nil, fmt.Errorf("order flow source requires a trade subscriber")
+		}
+		return NewOrderFlowSignalSource(logger, DefaultOrderFlowConfig(), config.TradeSubscriber), nil
+	default:
+		return nil, fmt.Errorf("unknown signal source type: %s", config.Type)
+	}
+}
+
+// SetCalibrator sets the calibrator that maps each source's stated confidence
+// to its realized hit rate before aggregation.
+func (a *Aggregator) SetCalibrator(calibrator ConfidenceCalibrator) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	
+	a.calibrator = calibrator
 }
 
 // AddSource adds a signal source.
@@ -276,27 +415,42 @@ func (a *Aggregator) collectSignals(ctx context.Context, sourceName string, sign
 	}
 }
 
-// recordSignal records a signal from a source.
+// recordSignal records a copy of a signal from a source. Sources re-report
+// signals when polled, so one already recorded from the same source, matched
+// by ID or, without one, by timestamp, is replaced rather than counted twice.
 func (a *Aggregator) recordSignal(sourceName string, signal *types.Signal) {
+	recorded := *signal
+	recorded.Source = sourceName
+	
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	
-	signal.Source = sourceName
-	
-	// Add to symbol's signal list
-	signals := a.latestSignals[signal.Symbol]
-	signals = append(signals, signal)
-	
-	// Remove expired signals
+	// Remove expired signals and the one being replaced
+	signals := a.latestSignals[recorded.Symbol]
 	cutoff := time.Now().Add(-a.config.MaxAge)
-	filtered := make([]*types.Signal, 0, len(signals))
+	filtered := make([]*types.Signal, 0, len(signals)+1)
 	for _, s := range signals {
-		if s.Timestamp.After(cutoff) {
+		if s.Timestamp.After(cutoff) && !sameSignal(s, &recorded) {
 			filtered = append(filtered, s)
 		}
 	}
+	if recorded.Timestamp.After(cutoff) {
+		filtered = append(filtered, &recorded)
+	}
 	
-	a.latestSignals[signal.Symbol] = filtered
+	a.latestSignals[recorded.Symbol] = filtered
+}
+
+// sameSignal reports whether two recorded signals are one signal reported
+// twice by its source.
+func sameSignal(a, b *types.Signal) bool {
+	if a.Source != b.Source {
+		return false
+	}
+	if a.ID != "" || b.ID != "" {
+		return a.ID == b.ID
+	}
+	return a.Timestamp.Equal(b.Timestamp)
 }
 
 // aggregateLoop periodically aggregates signals.
@@ -319,46 +473,11 @@ func (a *Aggregator) aggregateLoop(ctx context.Context) {
 // aggregate combines signals from all sources.
 func (a *Aggregator) aggregate() {
 	a.mu.Lock()
-	defer a.mu.Unlock()
-	
 	now := time.Now()
-	windowStart := now.Add(-a.config.AggregationWindow)
 	
-	for symbol, signals := range a.latestSignals {
-		// Filter to window
-		var windowSignals []*types.Signal
-		for _, s := range signals {
-			if s.Timestamp.After(windowStart) {
-				windowSignals = append(windowSignals, s)
-			}
-		}
-		
-		if len(windowSignals) == 0 {
-			continue
-		}
-		
-		// Group by source
-		sourceSignals := make(map[string][]*types.Signal)
-		for _, s := range windowSignals {
-			sourceSignals[s.Source] = append(sourceSignals[s.Source], s)
-		}
-		
-		// Check minimum sources
-		if len(sourceSignals) < a.config.MinSources {
-			continue
-		}
-		
-		// Calculate aggregated signal
-		aggregated := a.calculateAggregatedSignal(symbol, sourceSignals)
-		
-		// Apply filters
-		if aggregated.Strength.LessThan(a.config.MinStrength) {
-			continue
-		}
-		if aggregated.Confidence.LessThan(a.config.MinConfidence) {
-			continue
-		}
-		if aggregated.ConsensusScore.LessThan(a.config.MinConsensus) {
+	for symbol := range a.latestSignals {
+		aggregated, err := a.aggregateSymbol(symbol, now)
+		if err != nil {
 			continue
 		}
 		
@@ -372,13 +491,132 @@ func (a *Aggregator) aggregate() {
 				zap.String("symbol", symbol))
 		}
 	}
+	
+	notify, pending := a.takeDegradations()
+	a.mu.Unlock()
+	
+	notifyDegradations(notify, pending)
+}
+
+// AggregateSignals polls every source for the symbol's latest signals and
+// aggregates them immediately, using the same weighting and filters as the
+// background loop. It returns a *NoConsensusError when no signal passes.
+func (a *Aggregator) AggregateSignals(ctx context.Context, symbol string) (*AggregatedSignal, error) {
+	a.mu.RLock()
+	sources := make(map[string]SignalSource, len(a.sources))
+	for name, source := range a.sources {
+		sources[name] = source
+	}
+	a.mu.RUnlock()
+	
+	for name, source := range sources {
+		signals, err := source.GetLatestSignals(ctx, symbol)
+		if err != nil {
+			a.logger.Debug("Failed to poll source",
+				zap.String("source", name),
+				zap.String("symbol", symbol),
+				zap.Error(err))
+			continue
+		}
+		for _, signal := range signals {
+			a.recordSignal(name, signal)
+		}
+	}
+	
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	
+	a.mu.Lock()
+	aggregated, err := a.aggregateSymbol(symbol, time.Now())
+	if err == nil {
+		a.aggregated[symbol] = aggregated
+	}
+	notify, pending := a.takeDegradations()
+	a.mu.Unlock()
+	
+	notifyDegradations(notify, pending)
+	
+	if err != nil {
+		return nil, err
+	}
+	return aggregated, nil
 }
 
-// calculateAggregatedSignal calculates the aggregated signal.
+// NoConsensusError is returned when a symbol's signals do not produce an
+// aggregated signal that passes the configured filters.
+type NoConsensusError struct {
+	Symbol string
+	Reason string
+}
+
+func (e *NoConsensusError) Error() string {
+	return fmt.Sprintf("no consensus for %s: %s", e.Symbol, e.Reason)
+}
+
+// aggregateSymbol aggregates the windowed signals for one symbol and applies
+// the minimum source, strength, confidence, and consensus filters. Falling
+// short of MinSources healthy sources is tracked as a degradation. The
+// breakdown is kept for Explain whether or not the signal passes. Callers
+// must hold a.mu.
+func (a *Aggregator) aggregateSymbol(symbol string, now time.Time) (result *AggregatedSignal, err error) {
+	windowStart := now.Add(-a.config.AggregationWindow)
+	
+	// Filter to window and group by source
+	sourceSignals := make(map[string][]*types.Signal)
+	for _, s := range a.latestSignals[symbol] {
+		if s.Timestamp.After(windowStart) {
+			sourceSignals[s.Source] = append(sourceSignals[s.Source], s)
+		}
+	}
+	
+	// Unhealthy or stale sources are excluded from the calculation, so only
+	// those left count toward the minimum
+	var aggregated *AggregatedSignal
+	var contributing []string
+	explanation := &SignalExplanation{Symbol: symbol, ExplainedAt: now}
+	if len(sourceSignals) > 0 {
+		aggregated, explanation = a.calculateAggregatedSignal(symbol, sourceSignals)
+		contributing = aggregated.Sources
+	}
+	defer func() { a.recordExplanation(explanation, aggregated, err) }()
+	
+	minSources, relaxed := a.trackSources(symbol, contributing, now)
+	
+	if len(sourceSignals) == 0 {
+		return nil, &NoConsensusError{Symbol: symbol, Reason: "no signals in aggregation window"}
+	}
+	if len(contributing) < minSources {
+		return nil, &NoConsensusError{
+			Symbol: symbol,
+			Reason: fmt.Sprintf("%d healthy sources, need %d", len(contributing), minSources),
+		}
+	}
+	if relaxed {
+		aggregated.Degraded = true
+		aggregated.PositionScale = a.config.DegradedPositionScale
+	}
+	
+	// Apply filters
+	if aggregated.Strength.LessThan(a.config.MinStrength) {
+		return nil, &NoConsensusError{Symbol: symbol, Reason: "strength below minimum"}
+	}
+	if aggregated.Confidence.LessThan(a.config.MinConfidence) {
+		return nil, &NoConsensusError{Symbol: symbol, Reason: "confidence below minimum"}
+	}
+	if aggregated.ConsensusScore.LessThan(a.config.MinConsensus) {
+		return nil, &NoConsensusError{Symbol: symbol, Reason: "consensus below minimum"}
+	}
+	
+	return aggregated, nil
+}
+
+// calculateAggregatedSignal calculates the aggregated signal and the
+// per-source breakdown behind it.
 func (a *Aggregator) calculateAggregatedSignal(
 	symbol string,
 	sourceSignals map[string][]*types.Signal,
-) *AggregatedSignal {
+) (*AggregatedSignal, *SignalExplanation) {
 	var (
 		totalWeight    = decimal.Zero
 		buyWeight      = decimal.Zero
@@ -387,46 +625,87 @@ func (a *Aggregator) calculateAggregatedSignal(
 		confidenceSum  = decimal.Zero
 		sources        []string
 		allSignals     []*types.Signal
+		now            = time.Now()
+		effective      = make(map[string]decimal.Decimal, len(sourceSignals))
+		votes          = make([]conflictVote, 0, len(sourceSignals))
+		breakdown      = make([]SourceBreakdown, 0, len(sourceSignals))
 	)
 	
 	for sourceName, signals := range sourceSignals {
-		sources = append(sources, sourceName)
-		
 		sourceWeight := a.weights[sourceName]
 		if sourceWeight.IsZero() {
 			sourceWeight = decimal.NewFromFloat(1.0)
 		}
 		
-		// Take the most recent signal from each source
+		// The most recent signal from each source drives levels and health
 		latestSignal := signals[len(signals)-1]
-		allSignals = append(allSignals, latestSignal)
 		
-		totalWeight = totalWeight.Add(sourceWeight)
+		// Without decay, only the most recent signal counts
+		used := signals
+		if a.config.DecayHalfLife <= 0 || a.config.LatestSignalOnly {
+			used = signals[len(signals)-1:]
+		}
+		contrib := a.sourceContribution(used, now)
+		if a.calibrator != nil {
+			contrib.confidence = a.calibrator.CalibrateSourceConfidence(sourceName, contrib.confidence)
+		}
 		
-		switch latestSignal.Direction {
-		case types.SignalBuy:
-			buyWeight = buyWeight.Add(sourceWeight.Mul(latestSignal.Strength))
-		case types.SignalSell:
-			sellWeight = sellWeight.Add(sourceWeight.Mul(latestSignal.Strength))
+		// Scale by source health and freshness; fully stale sources drop out entirely
+		baseWeight := sourceWeight
+		health := a.healthFactor(sourceName, latestSignal, now)
+		sourceWeight = sourceWeight.Mul(health).Mul(contrib.freshness)
+		effective[sourceName] = sourceWeight
+		breakdown = append(breakdown, SourceBreakdown{
+			Source:               sourceName,
+			Signal:               latestSignal,
+			SignalCount:          len(used),
+			Weight:               baseWeight,
+			HealthFactor:         health,
+			HealthAdjustedWeight: baseWeight.Mul(health),
+			Freshness:            contrib.freshness,
+			EffectiveWeight:      sourceWeight,
+			BuyWeight:            sourceWeight.Mul(contrib.buy),
+			SellWeight:           sourceWeight.Mul(contrib.sell),
+			Confidence:           contrib.confidence,
+			Excluded:             sourceWeight.IsZero(),
+		})
+		if sourceWeight.IsZero() {
+			continue
 		}
 		
-		strengthSum = strengthSum.Add(latestSignal.Strength.Mul(sourceWeight))
-		confidenceSum = confidenceSum.Add(latestSignal.Confidence.Mul(sourceWeight))
+		sources = append(sources, sourceName)
+		allSignals = append(allSignals, latestSignal)
+		votes = append(votes, conflictVote{source: sourceName, weight: sourceWeight, signal: latestSignal})
+		
+		totalWeight = totalWeight.Add(sourceWeight)
+		buyWeight = buyWeight.Add(sourceWeight.Mul(contrib.buy))
+		sellWeight = sellWeight.Add(sourceWeight.Mul(contrib.sell))
+		
+		strengthSum = strengthSum.Add(contrib.strength.Mul(sourceWeight))
+		confidenceSum = confidenceSum.Add(contrib.confidence.Mul(sourceWeight))
 	}
 	
-	// Determine direction
+	// Determine direction, resolving near ties by the conflict policy
 	var direction types.SignalDirection
-	var directionWeight decimal.Decimal
+	var conflict *ConflictResolution
 	
-	if buyWeight.GreaterThan(sellWeight) {
+	if a.inConflict(buyWeight, sellWeight) {
+		conflict = a.resolveConflict(votes, buyWeight, sellWeight)
+		direction = conflict.Direction
+	} else if buyWeight.GreaterThan(sellWeight) {
 		direction = types.SignalBuy
-		directionWeight = buyWeight
 	} else if sellWeight.GreaterThan(buyWeight) {
 		direction = types.SignalSell
-		directionWeight = sellWeight
 	} else {
 		direction = types.SignalHold
-		directionWeight = decimal.Zero
+	}
+	
+	directionWeight := decimal.Zero
+	switch direction {
+	case types.SignalBuy:
+		directionWeight = buyWeight
+	case types.SignalSell:
+		directionWeight = sellWeight
 	}
 	
 	// Calculate consensus (how much sources agree)
@@ -437,8 +716,11 @@ func (a *Aggregator) calculateAggregatedSignal(
 	}
 	
 	// Calculate weighted averages
-	avgStrength := strengthSum.Div(totalWeight)
-	avgConfidence := confidenceSum.Div(totalWeight)
+	var avgStrength, avgConfidence decimal.Decimal
+	if !totalWeight.IsZero() {
+		avgStrength = strengthSum.Div(totalWeight)
+		avgConfidence = confidenceSum.Div(totalWeight)
+	}
 	
 	// Calculate suggested levels
 	suggestedEntry, suggestedStop, suggestedTarget := a.calculateLevels(allSignals, direction)
@@ -453,7 +735,14 @@ func (a *Aggregator) calculateAggregatedSignal(
 		}
 	}
 	
-	return &AggregatedSignal{
+	metadata := map[string]interface{}{
+		"effectiveWeights": effective,
+	}
+	if conflict != nil {
+		metadata["conflict"] = conflict
+	}
+	
+	aggregated := &AggregatedSignal{
 		Symbol:          symbol,
 		Direction:       direction,
 		Strength:        avgStrength,
@@ -467,7 +756,124 @@ func (a *Aggregator) calculateAggregatedSignal(
 		SuggestedStop:   suggestedStop,
 		SuggestedTarget: suggestedTarget,
 		RiskRewardRatio: rrRatio,
+		PositionScale:   decimal.NewFromInt(1),
+		Metadata:        metadata,
+	}
+	
+	sort.Slice(breakdown, func(i, j int) bool { return breakdown[i].Source < breakdown[j].Source })
+	return aggregated, &SignalExplanation{
+		Symbol:      symbol,
+		Sources:     breakdown,
+		TotalWeight: totalWeight,
+		BuyWeight:   buyWeight,
+		SellWeight:  sellWeight,
+		Direction:   direction,
+		Consensus:   consensus,
+		Conflict:    conflict,
+		ExplainedAt: now,
+	}
+}
+
+// sourceContribution is one source's decay-weighted view of its signals.
+type sourceContribution struct {
+	buy        decimal.Decimal // weighted strength of buy signals
+	sell       decimal.Decimal // weighted strength of sell signals
+	strength   decimal.Decimal
+	confidence decimal.Decimal
+	freshness  decimal.Decimal // decay of the most recent signal
+}
+
+// sourceContribution averages a source's signals weighted by time decay.
+// With DecayHalfLife unset every signal has weight 1.
+func (a *Aggregator) sourceContribution(signals []*types.Signal, now time.Time) sourceContribution {
+	var c sourceContribution
+	decaySum := decimal.Zero
+	
+	for _, s := range signals {
+		decay := a.decay(s, now)
+		decaySum = decaySum.Add(decay)
+		
+		switch s.Direction {
+		case types.SignalBuy:
+			c.buy = c.buy.Add(s.Strength.Mul(decay))
+		case types.SignalSell:
+			c.sell = c.sell.Add(s.Strength.Mul(decay))
+		}
+		c.strength = c.strength.Add(s.Strength.Mul(decay))
+		c.confidence = c.confidence.Add(s.Confidence.Mul(decay))
+	}
+	
+	if decaySum.IsZero() {
+		return c
+	}
+	
+	c.buy = c.buy.Div(decaySum)
+	c.sell = c.sell.Div(decaySum)
+	c.strength = c.strength.Div(decaySum)
+	c.confidence = c.confidence.Div(decaySum)
+	c.freshness = a.decay(signals[len(signals)-1], now)
+	return c
+}
+
+// decay returns the exponential time-decay weight of a signal.
+func (a *Aggregator) decay(s *types.Signal, now time.Time) decimal.Decimal {
+	if a.config.DecayHalfLife <= 0 {
+		return decimal.NewFromInt(1)
+	}
+	age := now.Sub(s.Timestamp)
+	if age < 0 {
+		age = 0
+	}
+	return decimal.NewFromFloat(math.Pow(0.5, float64(age)/float64(a.config.DecayHalfLife)))
+}
+
+// unhealthyWeightFactor scales the weight of a source reporting itself unhealthy.
+var unhealthyWeightFactor = decimal.NewFromFloat(0.5)
+
+// healthFactor returns a 0-1 multiplier for a source's weight based on its
+// reported health, error rate, and how recently it produced a signal. The
+// weight decays linearly with the time since the source's last signal,
+// reaching zero at MaxAge, so a source fades out rather than dropping off at
+// the aggregation window's edge.
+func (a *Aggregator) healthFactor(sourceName string, latest *types.Signal, now time.Time) decimal.Decimal {
+	source, ok := a.sources[sourceName]
+	if !ok {
+		return decimal.NewFromInt(1)
+	}
+	health := source.Health()
+	
+	factor := decimal.NewFromInt(1)
+	if !health.IsHealthy {
+		factor = factor.Mul(unhealthyWeightFactor)
 	}
+	
+	errorRate := health.ErrorRate
+	if errorRate < 0 {
+		errorRate = 0
+	} else if errorRate > 1 {
+		errorRate = 1
+	}
+	factor = factor.Mul(decimal.NewFromFloat(1 - errorRate))
+	
+	lastSignal := health.LastSignalTime
+	if lastSignal.IsZero() {
+		lastSignal = latest.Timestamp
+	}
+	// Ages are judged to the second, so a source that just signalled keeps
+	// its full weight
+	age := now.Sub(lastSignal).Truncate(time.Second)
+	
+	maxAge := a.config.MaxAge
+	switch {
+	case age <= 0 || maxAge <= 0:
+	case age >= maxAge:
+		return decimal.Zero
+	default:
+		remaining := float64(maxAge-age) / float64(maxAge)
+		factor = factor.Mul(decimal.NewFromFloat(remaining))
+	}
+	
+	return factor
 }
 
 // calculateLevels calculates suggested entry, stop, and target levels.
@@ -758,21 +1164,50 @@ func (s *SentimentSignalSource) GetLatestSignals(ctx context.Context, symbol str
 	return []*types.Signal{signal}, nil
 }
 
-// OnChainSignalSource provides signals from on-chain data.
+// onChainChains maps base assets to the EVM chain whose native flows they track.
+var onChainChains = map[string]string{
+	"ETH":   "ethereum",
+	"WETH":  "ethereum",
+	"MATIC": "polygon",
+	"POL":   "polygon",
+	"ARB":   "arbitrum",
+	"BNB":   "bsc",
+	"AVAX":  "avalanche",
+}
+
+// quoteAssets are stripped from symbols to find the base asset.
+var quoteAssets = []string{"USDT", "USDC", "BUSD", "FDUSD", "USD", "EUR"}
+
+const (
+	// onChainLookback is how many recent blocks flows are measured over
+	onChainLookback = 100
+	// onChainFullConfidenceTransfers is the whale transfer count at which
+	// the signal reaches full confidence
+	onChainFullConfidenceTransfers = 20
+	// onChainDirectionThreshold is the net flow share of gross exchange flow
+	// needed to call a direction
+	onChainDirectionThreshold = 0.2
+)
+
+// OnChainSignalSource provides signals from whale transfers and exchange flows
+// recorded by the block tracker.
 type OnChainSignalSource struct {
 	logger     *zap.Logger
 	name       string
 	httpClient *http.Client
+	tracker    *blockchain.BlockTracker
 	health     SourceHealth
 	mu         sync.RWMutex
 }
 
-// NewOnChainSignalSource creates an on-chain signal source.
-func NewOnChainSignalSource(logger *zap.Logger) *OnChainSignalSource {
+// NewOnChainSignalSource creates an on-chain signal source. Without a block
+// tracker it has no data and returns no signals.
+func NewOnChainSignalSource(logger *zap.Logger, tracker *blockchain.BlockTracker) *OnChainSignalSource {
 	return &OnChainSignalSource{
 		logger:     logger.Named("onchain-signals"),
 		name:       "onchain",
 		httpClient: &http.Client{Timeout: 30 * time.Second},
+		tracker:    tracker,
 		health: SourceHealth{
 			IsHealthy: true,
 		},
@@ -824,53 +1259,158 @@ func (o *OnChainSignalSource) Subscribe(ctx context.Context, symbols []string) (
 }
 
 func (o *OnChainSignalSource) GetLatestSignals(ctx context.Context, symbol string) ([]*types.Signal, error) {
-	// In production, would analyze:
-	// - Whale movements
-	// - Exchange inflows/outflows
-	// - Active addresses
-	// - Network value metrics
+	if o.tracker == nil {
+		return nil, fmt.Errorf("no block tracker configured")
+	}
+	
+	asset := baseAsset(symbol)
+	chain, ok := onChainChains[asset]
+	if !ok {
+		return nil, fmt.Errorf("no on-chain data for %s", symbol)
+	}
+	
+	flows := o.tracker.GetFlowMetrics(chain, onChainLookback)
+	if flows == nil {
+		err := fmt.Errorf("no %s blocks recorded", chain)
+		o.recordError(err.Error())
+		return nil, err
+	}
+	
+	// Coins leaving exchanges are being accumulated; coins arriving are
+	// about to be sold
+	inflow := flows.ExchangeInflow.InexactFloat64()
+	outflow := flows.ExchangeOutflow.InexactFloat64()
+	netFlow := flows.NetExchangeFlow.InexactFloat64()
+	
+	var flowRatio float64
+	if gross := inflow + outflow; gross > 0 {
+		flowRatio = netFlow / gross
+	}
+	
+	direction := types.SignalHold
+	if flowRatio <= -onChainDirectionThreshold {
+		direction = types.SignalBuy
+	} else if flowRatio >= onChainDirectionThreshold {
+		direction = types.SignalSell
+	}
+	
+	confidence := math.Min(1, float64(flows.LargeTransfers)/onChainFullConfidenceTransfers)
 	
 	signal := &types.Signal{
 		ID:         fmt.Sprintf("onchain-%s-%d", symbol, time.Now().UnixNano()),
 		Symbol:     symbol,
-		Direction:  types.SignalBuy,
-		Strength:   decimal.NewFromFloat(0.8),
-		Confidence: decimal.NewFromFloat(0.85),
+		Direction:  direction,
+		Strength:   decimal.NewFromFloat(math.Abs(flowRatio)),
+		Confidence: decimal.NewFromFloat(confidence),
 		Source:     "onchain",
 		Timestamp:  time.Now(),
 		Metadata: map[string]interface{}{
-			"whaleAccumulation": true,
-			"exchangeOutflow":   15000000,
-			"activeAddresses":   125000,
-			"nvtRatio":          45.2,
+			"chain":             chain,
+			"blocks":            flows.Blocks,
+			"largeTransfers":    flows.LargeTransfers,
+			"whaleVolume":       flows.WhaleVolume.InexactFloat64(),
+			"exchangeInflow":    inflow,
+			"exchangeOutflow":   outflow,
+			"netExchangeFlow":   netFlow,
+			"flowRatio":         flowRatio,
+			"whaleAccumulation": netFlow < 0,
+			"whaleDistribution": netFlow > 0,
 		},
 	}
 	
 	o.mu.Lock()
 	o.health.LastSignalTime = time.Now()
 	o.health.IsHealthy = true
+	o.health.LastError = ""
 	o.mu.Unlock()
 	
 	return []*types.Signal{signal}, nil
 }
 
+// recordError marks the source unhealthy.
+func (o *OnChainSignalSource) recordError(msg string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.health.IsHealthy = false
+	o.health.LastError = msg
+}
+
+// baseAsset returns the base asset of a symbol such as "ETHUSDT" or "ETH/USD".
+func baseAsset(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	if i := strings.IndexAny(symbol, "/-_"); i > 0 {
+		return symbol[:i]
+	}
+	
+	for _, quote := range quoteAssets {
+		if base := strings.TrimSuffix(symbol, quote); base != symbol && base != "" {
+			return base
+		}
+	}
+	return symbol
+}
+
 // PerplexitySignalSource provides AI research signals via Perplexity API.
 type PerplexitySignalSource struct {
 	logger     *zap.Logger
 	name       string
 	httpClient *http.Client
 	apiKey     string
+	config     PerplexityConfig
 	health     SourceHealth
 	mu         sync.RWMutex
+	
+	// Response cache and rate-limit backoff
+	cache        map[string]*perplexityCacheEntry
+	cacheHits    int
+	cacheMisses  int
+	requests     int
+	failures     int
+	backoffUntil time.Time
+}
+
+// PerplexityConfig configures the Perplexity signal source.
+type PerplexityConfig struct {
+	APIKey      string        `json:"apiKey"`
+	CacheTTL    time.Duration `json:"cacheTTL"`
+	BaseBackoff time.Duration `json:"baseBackoff"`
+	MaxBackoff  time.Duration `json:"maxBackoff"`
+}
+
+// DefaultPerplexityConfig returns sensible defaults.
+func DefaultPerplexityConfig() PerplexityConfig {
+	return PerplexityConfig{
+		CacheTTL:    10 * time.Minute,
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  30 * time.Minute,
+	}
+}
+
+// perplexityCacheEntry is the last good signal for a symbol.
+type perplexityCacheEntry struct {
+	signal    *types.Signal
+	fetchedAt time.Time
+}
+
+// perplexityStatusError is returned for non-200 responses.
+type perplexityStatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *perplexityStatusError) Error() string {
+	return fmt.Sprintf("perplexity API error: %d", e.StatusCode)
 }
 
 // NewPerplexitySignalSource creates a Perplexity AI signal source.
-func NewPerplexitySignalSource(logger *zap.Logger, apiKey string) *PerplexitySignalSource {
+func NewPerplexitySignalSource(logger *zap.Logger, config PerplexityConfig) *PerplexitySignalSource {
 	return &PerplexitySignalSource{
 		logger:     logger.Named("perplexity-signals"),
 		name:       "perplexity",
 		httpClient: &http.Client{Timeout: 60 * time.Second},
-		apiKey:     apiKey,
+		apiKey:     config.APIKey,
+		config:     config,
+		cache:      make(map[string]*perplexityCacheEntry),
 		health: SourceHealth{
 			IsHealthy: true,
 		},
@@ -930,19 +1470,60 @@ func (p *PerplexitySignalSource) GetLatestSignals(ctx context.Context, symbol st
 		return nil, fmt.Errorf("perplexity API key not configured")
 	}
 	
+	now := time.Now()
+	
+	p.mu.Lock()
+	entry := p.cache[symbol]
+	if entry != nil && now.Sub(entry.fetchedAt) < p.config.CacheTTL {
+		p.cacheHits++
+		p.updateCacheStatsLocked()
+		p.mu.Unlock()
+		return []*types.Signal{entry.cachedCopy(false)}, nil
+	}
+	if now.Before(p.backoffUntil) {
+		backoffUntil := p.backoffUntil
+		if entry != nil {
+			p.cacheHits++
+			p.updateCacheStatsLocked()
+			p.mu.Unlock()
+			return []*types.Signal{entry.cachedCopy(true)}, nil
+		}
+		p.mu.Unlock()
+		return nil, fmt.Errorf("perplexity backing off until %s", backoffUntil.Format(time.RFC3339))
+	}
+	p.cacheMisses++
+	p.requests++
+	p.updateCacheStatsLocked()
+	p.mu.Unlock()
+	
 	// Query Perplexity for market analysis
 	query := fmt.Sprintf(`Analyze the current market conditions for %s cryptocurrency. 
-		Provide a trading signal (BUY, SELL, or HOLD) with confidence level (0-100%%) and key reasons.
+		Provide a trading signal (BUY, SELL, or HOLD) with confidence level (0-100) and key reasons.
 		Focus on: recent news, technical levels, market sentiment, and upcoming events.
-		Format: SIGNAL: [BUY/SELL/HOLD], CONFIDENCE: [0-100], REASONS: [brief list]`, symbol)
+		Respond with only a JSON object of the form:
+		{"signal": "BUY" | "SELL" | "HOLD", "confidence": 0-100, "reasons": ["brief reason", ...]}`, symbol)
 	
 	// Call Perplexity API
 	response, err := p.callPerplexity(ctx, query)
 	if err != nil {
 		p.mu.Lock()
-		p.health.IsHealthy = false
+		defer p.mu.Unlock()
+		
+		p.failures++
+		p.backoffUntil = time.Now().Add(p.backoffDelay(err))
 		p.health.LastError = err.Error()
-		p.mu.Unlock()
+		p.updateCacheStatsLocked()
+		
+		// Serve the last good signal rather than dropping out of aggregation
+		if entry != nil {
+			p.logger.Warn("Perplexity request failed, serving stale signal",
+				zap.String("symbol", symbol),
+				zap.Time("backoffUntil", p.backoffUntil),
+				zap.Error(err))
+			return []*types.Signal{entry.cachedCopy(true)}, nil
+		}
+		
+		p.health.IsHealthy = false
 		return nil, err
 	}
 	
@@ -950,6 +1531,9 @@ func (p *PerplexitySignalSource) GetLatestSignals(ctx context.Context, symbol st
 	signal := p.parseResponse(symbol, response)
 	
 	p.mu.Lock()
+	p.cache[symbol] = &perplexityCacheEntry{signal: signal, fetchedAt: signal.Timestamp}
+	p.failures = 0
+	p.backoffUntil = time.Time{}
 	p.health.LastSignalTime = time.Now()
 	p.health.IsHealthy = true
 	p.health.LastError = ""
@@ -958,6 +1542,56 @@ func (p *PerplexitySignalSource) GetLatestSignals(ctx context.Context, symbol st
 	return []*types.Signal{signal}, nil
 }
 
+// backoffDelay returns an exponential backoff with jitter for the current
+// failure count, honoring Retry-After when the API sends it. Callers must
+// hold p.mu.
+func (p *PerplexitySignalSource) backoffDelay(err error) time.Duration {
+	delay := p.config.BaseBackoff
+	for i := 1; i < p.failures && delay < p.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.config.MaxBackoff {
+		delay = p.config.MaxBackoff
+	}
+	if delay > 0 {
+		delay += time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	}
+	
+	var statusErr *perplexityStatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > delay {
+		delay = statusErr.RetryAfter
+	}
+	return delay
+}
+
+// updateCacheStatsLocked refreshes cache and error rates in the health report.
+func (p *PerplexitySignalSource) updateCacheStatsLocked() {
+	if total := p.cacheHits + p.cacheMisses; total > 0 {
+		p.health.CacheHitRate = float64(p.cacheHits) / float64(total)
+	}
+	if p.requests > 0 {
+		p.health.ErrorRate = float64(p.failures) / float64(p.requests)
+		if p.health.ErrorRate > 1 {
+			p.health.ErrorRate = 1
+		}
+	}
+}
+
+// cachedCopy returns a copy of the cached signal keeping its ID and the time
+// it was fetched, so it ages out of the aggregation window like any other
+// signal. It is flagged as stale when served during backoff.
+func (e *perplexityCacheEntry) cachedCopy(stale bool) *types.Signal {
+	signal := *e.signal
+	signal.Metadata = make(map[string]interface{}, len(e.signal.Metadata)+3)
+	for k, v := range e.signal.Metadata {
+		signal.Metadata[k] = v
+	}
+	signal.Metadata["cached"] = true
+	signal.Metadata["stale"] = stale
+	signal.Metadata["fetchedAt"] = e.fetchedAt
+	return &signal
+}
+
 func (p *PerplexitySignalSource) callPerplexity(ctx context.Context, query string) (string, error) {
 	reqBody := map[string]interface{}{
 		"model": "llama-3.1-sonar-large-128k-online",
@@ -973,6 +1607,12 @@ func (p *PerplexitySignalSource) callPerplexity(ctx context.Context, query strin
 		},
 		"temperature": 0.2,
 		"max_tokens":  500,
+		"response_format": map[string]interface{}{
+			"type": "json_schema",
+			"json_schema": map[string]interface{}{
+				"schema": perplexityAnalysisSchema,
+			},
+		},
 	}
 	
 	jsonBody, _ := json.Marshal(reqBody)
@@ -993,7 +1633,11 @@ func (p *PerplexitySignalSource) callPerplexity(ctx context.Context, query strin
 	defer resp.Body.Close()
 	
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("perplexity API error: %d", resp.StatusCode)
+		statusErr := &perplexityStatusError{StatusCode: resp.StatusCode}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			statusErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return "", statusErr
 	}
 	
 	var result struct {
@@ -1015,54 +1659,114 @@ func (p *PerplexitySignalSource) callPerplexity(ctx context.Context, query strin
 	return result.Choices[0].Message.Content, nil
 }
 
+// perplexityAnalysis is the structured analysis requested from Perplexity.
+type perplexityAnalysis struct {
+	Signal     string   `json:"signal"`
+	Confidence float64  `json:"confidence"`
+	Reasons    []string `json:"reasons"`
+}
+
+// perplexityAnalysisSchema is the JSON schema for perplexityAnalysis.
+var perplexityAnalysisSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"signal":     map[string]interface{}{"type": "string", "enum": []string{"BUY", "SELL", "HOLD"}},
+		"confidence": map[string]interface{}{"type": "number", "minimum": 0, "maximum": 100},
+		"reasons":    map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+	},
+	"required": []string{"signal", "confidence", "reasons"},
+}
+
 func (p *PerplexitySignalSource) parseResponse(symbol, response string) *types.Signal {
+	signal := &types.Signal{
+		ID:        fmt.Sprintf("perplexity-%s-%d", symbol, time.Now().UnixNano()),
+		Symbol:    symbol,
+		Source:    "perplexity",
+		Timestamp: time.Now(),
+		Metadata: map[string]interface{}{
+			"analysis": response,
+			"model":    "llama-3.1-sonar-large-128k-online",
+		},
+	}
+	
+	analysis, err := parsePerplexityAnalysis(response)
+	if err != nil {
+		p.logger.Debug("Falling back to heuristic Perplexity parsing", zap.Error(err))
+		signal.Direction, signal.Strength, signal.Confidence = parseHeuristicAnalysis(response)
+		signal.Metadata["parser"] = "heuristic"
+		return signal
+	}
+	
+	switch strings.ToUpper(strings.TrimSpace(analysis.Signal)) {
+	case "BUY":
+		signal.Direction = types.SignalBuy
+	case "SELL":
+		signal.Direction = types.SignalSell
+	default:
+		signal.Direction = types.SignalHold
+	}
+	
+	// Accept either a 0-100 percentage or a 0-1 fraction
+	confidence := analysis.Confidence
+	if confidence > 1 {
+		confidence /= 100
+	}
+	confidence = math.Max(0, math.Min(1, confidence))
+	
+	signal.Confidence = decimal.NewFromFloat(confidence)
+	signal.Strength = decimal.NewFromFloat(confidence)
+	if signal.Direction == types.SignalHold {
+		signal.Strength = decimal.NewFromFloat(0.5)
+	}
+	signal.Metadata["reasons"] = analysis.Reasons
+	signal.Metadata["parser"] = "json"
+	
+	return signal
+}
+
+// parsePerplexityAnalysis decodes the JSON analysis, tolerating surrounding
+// prose or Markdown code fences around the object.
+func parsePerplexityAnalysis(response string) (*perplexityAnalysis, error) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON object in response")
+	}
+	
+	var analysis perplexityAnalysis
+	if err := json.Unmarshal([]byte(response[start:end+1]), &analysis); err != nil {
+		return nil, fmt.Errorf("failed to parse analysis JSON: %w", err)
+	}
+	if analysis.Signal == "" {
+		return nil, fmt.Errorf("analysis missing signal")
+	}
+	
+	return &analysis, nil
+}
+
+// parseHeuristicAnalysis guesses direction and confidence from free text.
+func parseHeuristicAnalysis(response string) (types.SignalDirection, decimal.Decimal, decimal.Decimal) {
 	// Default values
 	direction := types.SignalHold
 	strength := decimal.NewFromFloat(0.5)
 	confidence := decimal.NewFromFloat(0.5)
 	
-	// Simple parsing - in production would use better NLP
-	if contains(response, "BUY") || contains(response, "bullish") {
+	if strings.Contains(response, "BUY") || strings.Contains(response, "bullish") {
 		direction = types.SignalBuy
 		strength = decimal.NewFromFloat(0.7)
-	} else if contains(response, "SELL") || contains(response, "bearish") {
+	} else if strings.Contains(response, "SELL") || strings.Contains(response, "bearish") {
 		direction = types.SignalSell
 		strength = decimal.NewFromFloat(0.7)
 	}
 	
 	// Extract confidence if mentioned
-	if contains(response, "high confidence") || contains(response, "90") {
+	if strings.Contains(response, "high confidence") || strings.Contains(response, "90") {
 		confidence = decimal.NewFromFloat(0.9)
-	} else if contains(response, "moderate") || contains(response, "70") {
+	} else if strings.Contains(response, "moderate") || strings.Contains(response, "70") {
 		confidence = decimal.NewFromFloat(0.7)
 	}
 	
-	return &types.Signal{
-		ID:         fmt.Sprintf("perplexity-%s-%d", symbol, time.Now().UnixNano()),
-		Symbol:     symbol,
-		Direction:  direction,
-		Strength:   strength,
-		Confidence: confidence,
-		Source:     "perplexity",
-		Timestamp:  time.Now(),
-		Metadata: map[string]interface{}{
-			"analysis": response,
-			"model":    "llama-3.1-sonar-large-128k-online",
-		},
-	}
-}
-
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsHelper(s, substr))
-}
-
-func containsHelper(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
-	}
-	return false
+	return direction, strength, confidence
 }
 
 // bytes import needed for Perplexity
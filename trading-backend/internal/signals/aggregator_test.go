@@ -0,0 +1,412 @@
+// Package signals_test provides tests for signal aggregation.
+package signals_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/signals"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// staticSource returns a fixed signal and reports a fixed health.
+type staticSource struct {
+	name      string
+	direction types.SignalDirection
+	strength  float64
+	health    signals.SourceHealth
+}
+
+func (s *staticSource) Name() string                   { return s.name }
+func (s *staticSource) Type() signals.SignalSourceType { return signals.SourceTypeTechnical }
+func (s *staticSource) Health() signals.SourceHealth   { return s.health }
+
+func (s *staticSource) Subscribe(ctx context.Context, symbols []string) (<-chan *types.Signal, error) {
+	return make(chan *types.Signal), nil
+}
+
+func (s *staticSource) GetLatestSignals(ctx context.Context, symbol string) ([]*types.Signal, error) {
+	return []*types.Signal{{
+		ID:         s.name + "-" + symbol,
+		Symbol:     symbol,
+		Direction:  s.direction,
+		Strength:   decimal.NewFromFloat(s.strength),
+		Confidence: decimal.NewFromFloat(0.8),
+		Timestamp:  time.Now(),
+	}}, nil
+}
+
+func healthySource(name string, direction types.SignalDirection) *staticSource {
+	return &staticSource{
+		name:      name,
+		direction: direction,
+		strength:  0.7,
+		health:    signals.SourceHealth{IsHealthy: true, LastSignalTime: time.Now()},
+	}
+}
+
+func testConfig() signals.AggregatorConfig {
+	config := signals.DefaultAggregatorConfig()
+	config.MinSources = 2
+	config.MinConfidence = decimal.Zero
+	config.MinConsensus = decimal.Zero
+	config.MinStrength = decimal.Zero
+	return config
+}
+
+func TestAggregateSignalsExcludesStaleSource(t *testing.T) {
+	config := testConfig()
+	agg := signals.NewAggregator(zap.NewNop(), config)
+
+	agg.AddSource(healthySource("a", types.SignalBuy))
+	agg.AddSource(healthySource("b", types.SignalBuy))
+
+	// A strong sell from a source that has been silent past MaxAge
+	agg.AddSource(&staticSource{
+		name:      "stale",
+		direction: types.SignalSell,
+		strength:  1.0,
+		health: signals.SourceHealth{
+			IsHealthy:      true,
+			LastSignalTime: time.Now().Add(-2 * config.MaxAge),
+		},
+	})
+
+	result, err := agg.AggregateSignals(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("AggregateSignals failed: %v", err)
+	}
+
+	if result.Direction != types.SignalBuy {
+		t.Errorf("Expected buy direction, got %s", result.Direction)
+	}
+	for _, source := range result.Sources {
+		if source == "stale" {
+			t.Error("Stale source should not contribute to the aggregated signal")
+		}
+	}
+
+	weights, ok := result.Metadata["effectiveWeights"].(map[string]decimal.Decimal)
+	if !ok {
+		t.Fatalf("Expected effective weights in metadata, got %v", result.Metadata)
+	}
+	if !weights["stale"].IsZero() {
+		t.Errorf("Expected zero effective weight for stale source, got %s", weights["stale"])
+	}
+	if weights["a"].IsZero() {
+		t.Error("Expected healthy source to keep its weight")
+	}
+}
+
+func TestHealthFactorDecaysContinuouslyWithAge(t *testing.T) {
+	config := testConfig()
+	config.MinSources = 1
+	agg := signals.NewAggregator(zap.NewNop(), config)
+
+	ages := map[string]time.Duration{
+		"inside":  config.AggregationWindow - time.Second,
+		"outside": config.AggregationWindow + time.Second,
+		"half":    config.MaxAge / 2,
+		"expired": config.MaxAge,
+	}
+	for name, age := range ages {
+		source := healthySource(name, types.SignalBuy)
+		source.health.LastSignalTime = time.Now().Add(-age)
+		agg.AddSource(source)
+	}
+
+	if _, err := agg.AggregateSignals(context.Background(), "BTCUSDT"); err != nil {
+		t.Fatalf("AggregateSignals failed: %v", err)
+	}
+	explanation, _ := agg.Explain("BTCUSDT")
+	factors := make(map[string]decimal.Decimal)
+	for _, source := range explanation.Sources {
+		factors[source.Source] = source.HealthFactor
+	}
+
+	// Crossing the window's edge costs two seconds' worth of decay, not a cliff
+	step := factors["inside"].Sub(factors["outside"])
+	if !step.IsPositive() || step.GreaterThan(decimal.NewFromFloat(0.01)) {
+		t.Errorf("Expected a small decay across the window edge, got %s to %s", factors["inside"], factors["outside"])
+	}
+	if !factors["inside"].LessThan(decimal.NewFromInt(1)) {
+		t.Errorf("Expected decay inside the window, got %s", factors["inside"])
+	}
+	if !factors["half"].Equal(decimal.NewFromFloat(0.5)) {
+		t.Errorf("Expected half weight at half MaxAge, got %s", factors["half"])
+	}
+	if !factors["expired"].IsZero() {
+		t.Errorf("Expected no weight at MaxAge, got %s", factors["expired"])
+	}
+}
+
+func TestAggregateSignalsDownWeightsErroringSource(t *testing.T) {
+	agg := signals.NewAggregator(zap.NewNop(), testConfig())
+
+	agg.AddSource(healthySource("a", types.SignalBuy))
+	agg.AddSource(&staticSource{
+		name:      "flaky",
+		direction: types.SignalBuy,
+		strength:  0.7,
+		health: signals.SourceHealth{
+			IsHealthy:      false,
+			ErrorRate:      0.5,
+			LastSignalTime: time.Now(),
+		},
+	})
+
+	result, err := agg.AggregateSignals(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("AggregateSignals failed: %v", err)
+	}
+
+	weights := result.Metadata["effectiveWeights"].(map[string]decimal.Decimal)
+	if !weights["flaky"].LessThan(weights["a"]) {
+		t.Errorf("Expected flaky source weight %s below healthy weight %s", weights["flaky"], weights["a"])
+	}
+}
+
+func TestAggregateSignalsNoConsensus(t *testing.T) {
+	agg := signals.NewAggregator(zap.NewNop(), testConfig())
+	agg.AddSource(healthySource("a", types.SignalBuy))
+
+	_, err := agg.AggregateSignals(context.Background(), "BTCUSDT")
+
+	var noConsensus *signals.NoConsensusError
+	if !errors.As(err, &noConsensus) {
+		t.Fatalf("Expected NoConsensusError with a single source, got %v", err)
+	}
+}
+
+// historySource returns a fixed sequence of signals, oldest first.
+type historySource struct {
+	staticSource
+	history []*types.Signal
+}
+
+func (h *historySource) GetLatestSignals(ctx context.Context, symbol string) ([]*types.Signal, error) {
+	return h.history, nil
+}
+
+func TestAggregateSignalsDecayFavorsFreshSignals(t *testing.T) {
+	now := time.Now()
+	flapping := &historySource{
+		staticSource: *healthySource("flapping", types.SignalSell),
+		history: []*types.Signal{
+			{ID: "old", Symbol: "BTCUSDT", Direction: types.SignalSell, Strength: decimal.NewFromFloat(0.9),
+				Confidence: decimal.NewFromFloat(0.8), Timestamp: now.Add(-4 * time.Minute)},
+			{ID: "new", Symbol: "BTCUSDT", Direction: types.SignalBuy, Strength: decimal.NewFromFloat(0.9),
+				Confidence: decimal.NewFromFloat(0.8), Timestamp: now.Add(-30 * time.Second)},
+		},
+	}
+
+	config := testConfig()
+	config.MinSources = 1
+	config.DecayHalfLife = time.Minute
+	agg := signals.NewAggregator(zap.NewNop(), config)
+	agg.AddSource(flapping)
+
+	result, err := agg.AggregateSignals(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("AggregateSignals failed: %v", err)
+	}
+	if result.Direction != types.SignalBuy {
+		t.Errorf("Expected the fresher buy to dominate, got %s", result.Direction)
+	}
+	// The stale sell still pulls consensus below unanimity
+	if !result.ConsensusScore.LessThan(decimal.NewFromInt(1)) {
+		t.Errorf("Expected decayed sell to reduce consensus, got %s", result.ConsensusScore)
+	}
+
+	config.LatestSignalOnly = true
+	latestOnly := signals.NewAggregator(zap.NewNop(), config)
+	latestOnly.AddSource(flapping)
+
+	result, err = latestOnly.AggregateSignals(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("AggregateSignals failed: %v", err)
+	}
+	if !result.ConsensusScore.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Expected unanimous consensus using only the latest signal, got %s", result.ConsensusScore)
+	}
+}
+
+func TestAggregateSignalsCountsRepolledSignalsOnce(t *testing.T) {
+	now := time.Now()
+	history := &historySource{
+		staticSource: *healthySource("history", types.SignalBuy),
+		history: []*types.Signal{
+			{ID: "first", Symbol: "BTCUSDT", Direction: types.SignalBuy, Strength: decimal.NewFromFloat(0.7),
+				Confidence: decimal.NewFromFloat(0.8), Timestamp: now.Add(-time.Minute)},
+			{ID: "second", Symbol: "BTCUSDT", Direction: types.SignalBuy, Strength: decimal.NewFromFloat(0.7),
+				Confidence: decimal.NewFromFloat(0.8), Timestamp: now},
+		},
+	}
+
+	config := testConfig()
+	config.DecayHalfLife = time.Minute
+	agg := signals.NewAggregator(zap.NewNop(), config)
+	// Reports the same signal ID with a new timestamp on every poll
+	agg.AddSource(healthySource("static", types.SignalBuy))
+	agg.AddSource(history)
+
+	for i := 0; i < 3; i++ {
+		if _, err := agg.AggregateSignals(context.Background(), "BTCUSDT"); err != nil {
+			t.Fatalf("AggregateSignals failed: %v", err)
+		}
+	}
+
+	explanation, ok := agg.Explain("BTCUSDT")
+	if !ok {
+		t.Fatal("Expected an explanation")
+	}
+	counts := make(map[string]int)
+	for _, source := range explanation.Sources {
+		counts[source.Source] = source.SignalCount
+	}
+	if counts["static"] != 1 || counts["history"] != 2 {
+		t.Errorf("Expected each distinct signal counted once, got %v", counts)
+	}
+
+	for _, signal := range history.history {
+		if signal.Source != "" {
+			t.Errorf("Expected the source's own signal left unmodified, got source %q", signal.Source)
+		}
+	}
+}
+
+func TestAggregateSignalsReportsSourceDegradation(t *testing.T) {
+	config := testConfig()
+	agg := signals.NewAggregator(zap.NewNop(), config)
+
+	var reported []signals.SourceDegradation
+	agg.SetOnDegradation(func(d signals.SourceDegradation) {
+		reported = append(reported, d)
+	})
+
+	flaky := healthySource("b", types.SignalBuy)
+	flaky.health.LastSignalTime = time.Now().Add(-2 * config.MaxAge)
+	agg.AddSource(healthySource("a", types.SignalBuy))
+	agg.AddSource(flaky)
+
+	// One healthy source stops trading the symbol
+	if _, err := agg.AggregateSignals(context.Background(), "BTCUSDT"); err == nil {
+		t.Fatal("Expected no signal with one healthy source")
+	}
+	agg.AggregateSignals(context.Background(), "BTCUSDT")
+
+	if len(reported) != 1 || !reported[0].Degraded || reported[0].HealthySources != 1 || reported[0].Relaxed {
+		t.Fatalf("Expected a single unrelaxed degradation, got %+v", reported)
+	}
+	if _, ok := agg.GetDegradations()["BTCUSDT"]; !ok {
+		t.Error("Expected BTCUSDT listed as degraded")
+	}
+
+	flaky.health.LastSignalTime = time.Now()
+	result, err := agg.AggregateSignals(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("AggregateSignals failed after recovery: %v", err)
+	}
+
+	if result.Degraded || !result.PositionScale.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Expected a full-size signal after recovery, got scale %s", result.PositionScale)
+	}
+	if len(reported) != 2 || reported[1].Degraded {
+		t.Errorf("Expected a recovery to be reported, got %+v", reported)
+	}
+	if len(agg.GetDegradations()) != 0 {
+		t.Errorf("Expected no degraded symbols, got %v", agg.GetDegradations())
+	}
+}
+
+func TestAggregateSignalsRelaxesMinSourcesWhenDegraded(t *testing.T) {
+	config := testConfig()
+	config.DegradedMinSources = 1
+	config.DegradedPositionScale = decimal.NewFromFloat(0.25)
+	agg := signals.NewAggregator(zap.NewNop(), config)
+
+	var reported []signals.SourceDegradation
+	agg.SetOnDegradation(func(d signals.SourceDegradation) {
+		reported = append(reported, d)
+	})
+	agg.AddSource(healthySource("a", types.SignalBuy))
+
+	result, err := agg.AggregateSignals(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("Expected a signal at the relaxed threshold, got %v", err)
+	}
+
+	if !result.Degraded || !result.PositionScale.Equal(config.DegradedPositionScale) {
+		t.Errorf("Expected a degraded signal at scale %s, got %v at %s",
+			config.DegradedPositionScale, result.Degraded, result.PositionScale)
+	}
+	if len(reported) != 1 || !reported[0].Relaxed || reported[0].MinSources != 2 {
+		t.Errorf("Expected a relaxed degradation against MinSources 2, got %+v", reported)
+	}
+}
+
+func TestAggregateSignalsResolvesConflictsByPolicy(t *testing.T) {
+	now := time.Now()
+	// An older strong buy against a fresh, weaker sell from a heavier
+	// source: 0.9 of buy weight against 0.84 of sell, within 10% of each other
+	buy := &historySource{
+		staticSource: *healthySource("buyer", types.SignalBuy),
+		history: []*types.Signal{{ID: "buy", Symbol: "BTCUSDT", Direction: types.SignalBuy,
+			Strength: decimal.NewFromFloat(0.9), Confidence: decimal.NewFromFloat(0.8), Timestamp: now.Add(-time.Minute)}},
+	}
+	sell := &historySource{
+		staticSource: *healthySource("seller", types.SignalSell),
+		history: []*types.Signal{{ID: "sell", Symbol: "BTCUSDT", Direction: types.SignalSell,
+			Strength: decimal.NewFromFloat(0.6), Confidence: decimal.NewFromFloat(0.8), Timestamp: now}},
+	}
+
+	for policy, want := range map[signals.ConflictPolicy]types.SignalDirection{
+		signals.ConflictHold:          types.SignalHold,
+		signals.ConflictHighestWeight: types.SignalSell,
+		signals.ConflictMostRecent:    types.SignalSell,
+		signals.ConflictStrongest:     types.SignalBuy,
+	} {
+		config := testConfig()
+		config.ConflictPolicy = policy
+		config.ConflictEpsilon = decimal.NewFromFloat(0.1)
+		config.SourceWeights = map[string]decimal.Decimal{
+			"buyer":  decimal.NewFromFloat(1.0),
+			"seller": decimal.NewFromFloat(1.4),
+		}
+		agg := signals.NewAggregator(zap.NewNop(), config)
+		agg.AddSource(buy)
+		agg.AddSource(sell)
+
+		result, err := agg.AggregateSignals(context.Background(), "BTCUSDT")
+		if err != nil {
+			t.Fatalf("%s: AggregateSignals failed: %v", policy, err)
+		}
+		if result.Direction != want {
+			t.Errorf("%s: expected %s, got %s", policy, want, result.Direction)
+		}
+		conflict, ok := result.Metadata["conflict"].(*signals.ConflictResolution)
+		if !ok || conflict.Policy != policy || conflict.Direction != want {
+			t.Errorf("%s: expected the resolution in metadata, got %+v", policy, result.Metadata["conflict"])
+		}
+	}
+
+	// Without an epsilon the slightly heavier buy wins outright
+	config := testConfig()
+	config.ConflictPolicy = signals.ConflictMostRecent
+	config.SourceWeights = map[string]decimal.Decimal{"buyer": decimal.NewFromFloat(1.0), "seller": decimal.NewFromFloat(1.4)}
+	agg := signals.NewAggregator(zap.NewNop(), config)
+	agg.AddSource(buy)
+	agg.AddSource(sell)
+
+	result, err := agg.AggregateSignals(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("AggregateSignals failed: %v", err)
+	}
+	if result.Direction != types.SignalBuy || result.Metadata["conflict"] != nil {
+		t.Errorf("Expected an unresolved buy without an epsilon, got %s with %v", result.Direction, result.Metadata["conflict"])
+	}
+}
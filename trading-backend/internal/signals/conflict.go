@@ -0,0 +1,107 @@
+package signals
+
+import (
+	"sort"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// ConflictPolicy decides the direction of a symbol whose buy and sell
+// weights are within ConflictEpsilon of each other.
+type ConflictPolicy string
+
+const (
+	ConflictHold          ConflictPolicy = "hold"           // Stand down
+	ConflictHighestWeight ConflictPolicy = "highest_weight" // Follow the source with the highest effective weight
+	ConflictMostRecent    ConflictPolicy = "most_recent"    // Follow the newest directional signal
+	ConflictStrongest     ConflictPolicy = "strongest"      // Follow the strongest directional signal
+)
+
+// Valid reports whether the policy is known. The empty policy means hold.
+func (p ConflictPolicy) Valid() bool {
+	switch p {
+	case "", ConflictHold, ConflictHighestWeight, ConflictMostRecent, ConflictStrongest:
+		return true
+	}
+	return false
+}
+
+// ConflictResolution records how a split between buy and sell sources was
+// resolved. It is stored in the aggregated signal's "conflict" metadata.
+type ConflictResolution struct {
+	Policy     ConflictPolicy        `json:"policy"`
+	Direction  types.SignalDirection `json:"direction"`
+	Source     string                `json:"source,omitempty"` // The source followed, unless holding
+	BuyWeight  decimal.Decimal       `json:"buyWeight"`
+	SellWeight decimal.Decimal       `json:"sellWeight"`
+}
+
+// conflictVote is a contributing source's latest signal and effective weight.
+type conflictVote struct {
+	source string
+	weight decimal.Decimal
+	signal *types.Signal
+}
+
+// inConflict reports whether buy and sell weights differ by no more than
+// ConflictEpsilon of their sum. Without any directional weight there is
+// nothing to resolve.
+func (a *Aggregator) inConflict(buyWeight, sellWeight decimal.Decimal) bool {
+	total := buyWeight.Add(sellWeight)
+	if !total.IsPositive() {
+		return false
+	}
+	return buyWeight.Sub(sellWeight).Abs().LessThanOrEqual(total.Mul(a.config.ConflictEpsilon))
+}
+
+// resolveConflict picks a direction for split sources by the configured
+// policy, following the best directional vote. Ties between votes go to the
+// source name sorting first, so resolution does not depend on map order.
+// Holding, or finding no directional vote, resolves to SignalHold.
+func (a *Aggregator) resolveConflict(votes []conflictVote, buyWeight, sellWeight decimal.Decimal) *ConflictResolution {
+	policy := a.config.ConflictPolicy
+	if policy == "" {
+		policy = ConflictHold
+	}
+	resolution := &ConflictResolution{
+		Policy:     policy,
+		Direction:  types.SignalHold,
+		BuyWeight:  buyWeight,
+		SellWeight: sellWeight,
+	}
+	if policy == ConflictHold {
+		return resolution
+	}
+
+	sort.Slice(votes, func(i, j int) bool { return votes[i].source < votes[j].source })
+
+	var best *conflictVote
+	for i := range votes {
+		vote := &votes[i]
+		if vote.signal.Direction != types.SignalBuy && vote.signal.Direction != types.SignalSell {
+			continue
+		}
+		if best == nil || preferVote(policy, vote, best) {
+			best = vote
+		}
+	}
+	if best != nil {
+		resolution.Direction = best.signal.Direction
+		resolution.Source = best.source
+	}
+	return resolution
+}
+
+// preferVote reports whether vote beats best under the policy.
+func preferVote(policy ConflictPolicy, vote, best *conflictVote) bool {
+	switch policy {
+	case ConflictHighestWeight:
+		return vote.weight.GreaterThan(best.weight)
+	case ConflictMostRecent:
+		return vote.signal.Timestamp.After(best.signal.Timestamp)
+	case ConflictStrongest:
+		return vote.signal.Strength.GreaterThan(best.signal.Strength)
+	}
+	return false
+}
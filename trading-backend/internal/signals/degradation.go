@@ -0,0 +1,137 @@
+package signals
+
+import (
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SourceDegradation reports that fewer than MinSources healthy sources are
+// contributing to a symbol's signals, or that they have recovered.
+type SourceDegradation struct {
+	Symbol         string             `json:"symbol"`
+	Degraded       bool               `json:"degraded"` // False once the symbol has recovered
+	HealthySources int                `json:"healthySources"`
+	MinSources     int                `json:"minSources"`
+	Relaxed        bool               `json:"relaxed"` // Still trading at DegradedMinSources with reduced size
+	HealthyTypes   []SignalSourceType `json:"healthyTypes"`
+	MissingTypes   []SignalSourceType `json:"missingTypes"` // Registered source types not contributing
+	Timestamp      time.Time          `json:"timestamp"`
+}
+
+// SetOnDegradation sets the callback told when a symbol's healthy sources
+// fall below MinSources, change while degraded, or recover. It is called
+// outside the aggregator's lock.
+func (a *Aggregator) SetOnDegradation(fn func(SourceDegradation)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.onDegradation = fn
+}
+
+// GetDegradations returns the symbols currently short of healthy sources.
+func (a *Aggregator) GetDegradations() map[string]SourceDegradation {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	degradations := make(map[string]SourceDegradation, len(a.degradations))
+	for symbol, degradation := range a.degradations {
+		degradations[symbol] = degradation
+	}
+	return degradations
+}
+
+// trackSources records how many healthy sources contributed to a symbol and
+// returns the minimum the symbol's signal must meet: MinSources normally, or
+// DegradedMinSources when relaxing is enabled and enough sources remain.
+// Callers must hold a.mu.
+func (a *Aggregator) trackSources(symbol string, contributing []string, now time.Time) (int, bool) {
+	healthy := len(contributing)
+	previous, wasDegraded := a.degradations[symbol]
+
+	if healthy >= a.config.MinSources {
+		if wasDegraded {
+			delete(a.degradations, symbol)
+			recovered := a.describeSources(symbol, contributing, now)
+			a.logger.Info("Signal sources recovered",
+				zap.String("symbol", symbol),
+				zap.Int("healthySources", healthy))
+			a.pendingDegradations = append(a.pendingDegradations, recovered)
+		}
+		return a.config.MinSources, false
+	}
+
+	degradation := a.describeSources(symbol, contributing, now)
+	degradation.Degraded = true
+	degradation.Relaxed = a.config.DegradedMinSources > 0 && healthy >= a.config.DegradedMinSources
+	a.degradations[symbol] = degradation
+
+	if !wasDegraded || previous.HealthySources != healthy || previous.Relaxed != degradation.Relaxed {
+		a.logger.Warn("Signal sources below minimum",
+			zap.String("symbol", symbol),
+			zap.Int("healthySources", healthy),
+			zap.Int("minSources", a.config.MinSources),
+			zap.Bool("relaxed", degradation.Relaxed))
+		a.pendingDegradations = append(a.pendingDegradations, degradation)
+	}
+
+	if degradation.Relaxed {
+		return a.config.DegradedMinSources, true
+	}
+	return a.config.MinSources, false
+}
+
+// describeSources splits the registered source types into those
+// contributing to a symbol and those missing. Callers must hold a.mu.
+func (a *Aggregator) describeSources(symbol string, contributing []string, now time.Time) SourceDegradation {
+	healthy := make(map[SignalSourceType]bool)
+	for _, name := range contributing {
+		if source, ok := a.sources[name]; ok {
+			healthy[source.Type()] = true
+		}
+	}
+
+	missing := make(map[SignalSourceType]bool)
+	for _, source := range a.sources {
+		if !healthy[source.Type()] {
+			missing[source.Type()] = true
+		}
+	}
+
+	return SourceDegradation{
+		Symbol:         symbol,
+		HealthySources: len(contributing),
+		MinSources:     a.config.MinSources,
+		HealthyTypes:   sortedTypes(healthy),
+		MissingTypes:   sortedTypes(missing),
+		Timestamp:      now,
+	}
+}
+
+// takeDegradations returns and clears the changes waiting to be reported.
+// Callers must hold a.mu.
+func (a *Aggregator) takeDegradations() (func(SourceDegradation), []SourceDegradation) {
+	pending := a.pendingDegradations
+	a.pendingDegradations = nil
+	return a.onDegradation, pending
+}
+
+// notifyDegradations reports changes taken by takeDegradations.
+func notifyDegradations(fn func(SourceDegradation), pending []SourceDegradation) {
+	if fn == nil {
+		return
+	}
+	for _, degradation := range pending {
+		fn(degradation)
+	}
+}
+
+func sortedTypes(set map[SignalSourceType]bool) []SignalSourceType {
+	types := make([]SignalSourceType, 0, len(set))
+	for t := range set {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
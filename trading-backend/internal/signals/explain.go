@@ -0,0 +1,74 @@
+package signals
+
+import (
+	"errors"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// SignalExplanation breaks a symbol's latest aggregation down into each
+// source's vote and the tallies that set the direction and consensus.
+type SignalExplanation struct {
+	Symbol string `json:"symbol"`
+
+	// Signal is the aggregated signal the sources produced, also when a
+	// filter then rejected it; nil without signals in the window
+	Signal    *AggregatedSignal `json:"signal,omitempty"`
+	Accepted  bool              `json:"accepted"`
+	Rejection string            `json:"rejection,omitempty"` // Why no signal was emitted
+
+	Sources     []SourceBreakdown     `json:"sources"`
+	TotalWeight decimal.Decimal       `json:"totalWeight"`
+	BuyWeight   decimal.Decimal       `json:"buyWeight"`
+	SellWeight  decimal.Decimal       `json:"sellWeight"`
+	Direction   types.SignalDirection `json:"direction"`
+	Consensus   decimal.Decimal       `json:"consensus"` // Direction's share of buy plus sell weight
+	Conflict    *ConflictResolution   `json:"conflict,omitempty"`
+	ExplainedAt time.Time             `json:"explainedAt"`
+}
+
+// SourceBreakdown is one source's part in an aggregation.
+type SourceBreakdown struct {
+	Source      string        `json:"source"`
+	Signal      *types.Signal `json:"signal"`      // Most recent signal in the window
+	SignalCount int           `json:"signalCount"` // Signals averaged into the vote
+
+	Weight               decimal.Decimal `json:"weight"`               // Configured weight
+	HealthFactor         decimal.Decimal `json:"healthFactor"`         // 0-1 from health, error rate and silence
+	HealthAdjustedWeight decimal.Decimal `json:"healthAdjustedWeight"` // Weight times HealthFactor
+	Freshness            decimal.Decimal `json:"freshness"`            // Decay of the most recent signal
+	EffectiveWeight      decimal.Decimal `json:"effectiveWeight"`      // Weight the vote counted with
+
+	BuyWeight  decimal.Decimal `json:"buyWeight"`  // Added to the buy tally
+	SellWeight decimal.Decimal `json:"sellWeight"` // Added to the sell tally
+	Confidence decimal.Decimal `json:"confidence"` // Calibrated when a calibrator is set
+	Excluded   bool            `json:"excluded,omitempty"`
+}
+
+// Explain returns the breakdown of a symbol's latest aggregation, and false
+// when it has not been aggregated.
+func (a *Aggregator) Explain(symbol string) (*SignalExplanation, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	explanation, ok := a.explanations[symbol]
+	return explanation, ok
+}
+
+// recordExplanation keeps an aggregation's breakdown with its outcome.
+// Callers hold a.mu.
+func (a *Aggregator) recordExplanation(explanation *SignalExplanation, candidate *AggregatedSignal, err error) {
+	explanation.Signal = candidate
+	explanation.Accepted = err == nil
+
+	var noConsensus *NoConsensusError
+	if errors.As(err, &noConsensus) {
+		explanation.Rejection = noConsensus.Reason
+	} else if err != nil {
+		explanation.Rejection = err.Error()
+	}
+
+	a.explanations[explanation.Symbol] = explanation
+}
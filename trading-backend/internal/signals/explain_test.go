@@ -0,0 +1,55 @@
+package signals_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/signals"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func TestExplainBreaksDownAggregation(t *testing.T) {
+	config := testConfig()
+	config.SourceWeights = map[string]decimal.Decimal{"a": decimal.NewFromInt(2)}
+	config.MinConsensus = decimal.NewFromFloat(0.9)
+	agg := signals.NewAggregator(zap.NewNop(), config)
+
+	if _, ok := agg.Explain("BTCUSDT"); ok {
+		t.Fatal("Expected no explanation before aggregating")
+	}
+
+	agg.AddSource(healthySource("a", types.SignalBuy))
+	erroring := healthySource("b", types.SignalSell)
+	erroring.health.ErrorRate = 0.5
+	agg.AddSource(erroring)
+
+	if _, err := agg.AggregateSignals(context.Background(), "BTCUSDT"); err == nil {
+		t.Fatal("Expected the split vote to fail the consensus filter")
+	}
+
+	explanation, ok := agg.Explain("BTCUSDT")
+	if !ok {
+		t.Fatal("Expected an explanation of the rejected aggregation")
+	}
+	if explanation.Accepted || explanation.Rejection != "consensus below minimum" || explanation.Signal == nil {
+		t.Errorf("Expected a rejected candidate signal, got accepted=%v rejection=%q", explanation.Accepted, explanation.Rejection)
+	}
+	if len(explanation.Sources) != 2 || explanation.Sources[0].Source != "a" {
+		t.Fatalf("Expected sources a and b in order, got %+v", explanation.Sources)
+	}
+
+	a, b := explanation.Sources[0], explanation.Sources[1]
+	if !a.EffectiveWeight.Equal(decimal.NewFromInt(2)) || !a.BuyWeight.Equal(decimal.NewFromFloat(1.4)) {
+		t.Errorf("Expected a to vote 1.4 to buy at weight 2, got %s at %s", a.BuyWeight, a.EffectiveWeight)
+	}
+	if !b.HealthFactor.Equal(decimal.NewFromFloat(0.5)) || !b.HealthAdjustedWeight.Equal(decimal.NewFromFloat(0.5)) {
+		t.Errorf("Expected b's weight halved by its error rate, got factor %s weight %s", b.HealthFactor, b.HealthAdjustedWeight)
+	}
+
+	// 1.4 to buy against 0.35 to sell
+	if explanation.Direction != types.SignalBuy || !explanation.Consensus.Equal(decimal.NewFromFloat(0.8)) {
+		t.Errorf("Expected a buy at 0.8 consensus, got %s at %s", explanation.Direction, explanation.Consensus)
+	}
+}
@@ -0,0 +1,491 @@
+// Package signals provides a news headline signal source.
+package signals
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/atlas-desktop/trading-backend/pkg/utils"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// NewsProvider identifies the response format of a news API.
+type NewsProvider string
+
+const (
+	NewsProviderNewsAPI     NewsProvider = "newsapi"
+	NewsProviderCryptoPanic NewsProvider = "cryptopanic"
+)
+
+var defaultNewsURLs = map[NewsProvider]string{
+	NewsProviderNewsAPI:     "https://newsapi.org/v2/everything",
+	NewsProviderCryptoPanic: "https://cryptopanic.com/api/v1/posts/",
+}
+
+// NewsSourceConfig configures the news signal source.
+type NewsSourceConfig struct {
+	Provider     NewsProvider  `json:"provider"`
+	URL          string        `json:"url"`
+	APIKey       string        `json:"apiKey"`
+	PollInterval time.Duration `json:"pollInterval"`
+	MaxBackoff   time.Duration `json:"maxBackoff"` // Longest wait between polls after repeated failures
+	MaxHeadlines int           `json:"maxHeadlines"`
+}
+
+// DefaultNewsSourceConfig returns sensible defaults.
+func DefaultNewsSourceConfig() NewsSourceConfig {
+	return NewsSourceConfig{
+		Provider:     NewsProviderCryptoPanic,
+		URL:          defaultNewsURLs[NewsProviderCryptoPanic],
+		PollInterval: 5 * time.Minute,
+		MaxBackoff:   time.Hour,
+		MaxHeadlines: 20,
+	}
+}
+
+// Headline is a single news item.
+type Headline struct {
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Source      string    `json:"source"`
+	PublishedAt time.Time `json:"publishedAt"`
+	Score       float64   `json:"score"`
+}
+
+// Keyword lists for lightweight headline sentiment scoring. Keywords match
+// whole words, including their regular plural and tense forms.
+var (
+	bullishNewsKeywords = []string{
+		"surge", "soar", "rally", "jump", "gain", "bull", "bullish", "record high", "all-time high",
+		"breakout", "adopt", "approval", "approve", "partnership", "upgrade", "inflow",
+		"launch", "buy", "accumulate", "outperform", "recover", "recovery",
+	}
+	bearishNewsKeywords = []string{
+		"plunge", "crash", "drop", "fall", "fell", "slump", "bear", "bearish", "sell-off", "selloff",
+		"hack", "hacker", "exploit", "lawsuit", "sue", "ban", "reject", "outflow", "liquidate",
+		"fraud", "investigation", "delist", "downgrade",
+	}
+)
+
+// newsSentimentThreshold is the minimum average headline score for a direction.
+const newsSentimentThreshold = 0.1
+
+// NewsSignalSource provides signals from news headline sentiment.
+type NewsSignalSource struct {
+	logger     *zap.Logger
+	name       string
+	httpClient *http.Client
+	config     NewsSourceConfig
+	health     SourceHealth
+	lastPoll   map[string]time.Time // Last attempt, successful or not
+	failures   map[string]int       // Consecutive failed polls
+	lastSignal map[string]*types.Signal
+	mu         sync.RWMutex
+}
+
+// NewNewsSignalSource creates a news signal source.
+func NewNewsSignalSource(logger *zap.Logger, config NewsSourceConfig) *NewsSignalSource {
+	if config.Provider == "" {
+		config.Provider = DefaultNewsSourceConfig().Provider
+	}
+	if config.URL == "" {
+		config.URL = defaultNewsURLs[config.Provider]
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultNewsSourceConfig().PollInterval
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultNewsSourceConfig().MaxBackoff
+	}
+	if config.MaxHeadlines <= 0 {
+		config.MaxHeadlines = DefaultNewsSourceConfig().MaxHeadlines
+	}
+
+	return &NewsSignalSource{
+		logger:     logger.Named("news-signals"),
+		name:       "news",
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		config:     config,
+		lastPoll:   make(map[string]time.Time),
+		failures:   make(map[string]int),
+		lastSignal: make(map[string]*types.Signal),
+		health: SourceHealth{
+			IsHealthy: true,
+		},
+	}
+}
+
+func (n *NewsSignalSource) Name() string           { return n.name }
+func (n *NewsSignalSource) Type() SignalSourceType { return SourceTypeNews }
+
+func (n *NewsSignalSource) Health() SourceHealth {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.health
+}
+
+func (n *NewsSignalSource) Subscribe(ctx context.Context, symbols []string) (<-chan *types.Signal, error) {
+	signalChan := make(chan *types.Signal, 100)
+
+	go func() {
+		defer close(signalChan)
+
+		ticker := time.NewTicker(n.config.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, symbol := range symbols {
+					signals, err := n.GetLatestSignals(ctx, symbol)
+					if err != nil {
+						n.logger.Debug("Failed to get news signals", zap.String("symbol", symbol), zap.Error(err))
+						continue
+					}
+
+					for _, signal := range signals {
+						select {
+						case signalChan <- signal:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+			}
+		}
+	}()
+
+	return signalChan, nil
+}
+
+// GetLatestSignals returns the news signal for a symbol, polling the news API
+// at most once per poll interval. After a failed poll the wait doubles with
+// each consecutive failure, up to MaxBackoff.
+func (n *NewsSignalSource) GetLatestSignals(ctx context.Context, symbol string) ([]*types.Signal, error) {
+	n.mu.RLock()
+	lastPoll := n.lastPoll[symbol]
+	failures := n.failures[symbol]
+	cached := n.lastSignal[symbol]
+	n.mu.RUnlock()
+
+	if wait := n.pollWait(failures); time.Since(lastPoll) < wait {
+		if cached != nil {
+			return []*types.Signal{cached}, nil
+		}
+		if failures > 0 {
+			return nil, fmt.Errorf("news API backing off until %s", lastPoll.Add(wait).Format(time.RFC3339))
+		}
+		return nil, nil
+	}
+
+	start := time.Now()
+	headlines, err := n.fetchHeadlines(ctx, symbol)
+	latency := time.Since(start)
+	if err != nil {
+		n.mu.Lock()
+		n.lastPoll[symbol] = start
+		n.failures[symbol]++
+		n.health.IsHealthy = false
+		n.health.LastError = err.Error()
+		n.health.Latency = latency
+		n.mu.Unlock()
+		return nil, err
+	}
+
+	signal := n.scoreHeadlines(symbol, headlines)
+
+	n.mu.Lock()
+	n.lastPoll[symbol] = time.Now()
+	delete(n.failures, symbol)
+	n.lastSignal[symbol] = signal
+	n.health.Latency = latency
+	n.health.IsHealthy = true
+	n.health.LastError = ""
+	if signal != nil {
+		n.health.LastSignalTime = time.Now()
+	}
+	n.mu.Unlock()
+
+	if signal == nil {
+		return nil, nil
+	}
+	return []*types.Signal{signal}, nil
+}
+
+// pollWait returns how long to wait after a poll followed by failures
+// consecutive failed ones.
+func (n *NewsSignalSource) pollWait(failures int) time.Duration {
+	wait := n.config.PollInterval
+	for i := 0; i < failures && wait < n.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if failures > 0 && wait > n.config.MaxBackoff {
+		wait = n.config.MaxBackoff
+	}
+	return wait
+}
+
+// fetchHeadlines queries the configured news API for a symbol's base asset.
+func (n *NewsSignalSource) fetchHeadlines(ctx context.Context, symbol string) ([]Headline, error) {
+	base, _ := utils.ParseSymbol(utils.FormatSymbol(symbol))
+
+	reqURL, err := url.Parse(n.config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid news URL: %w", err)
+	}
+
+	query := reqURL.Query()
+	switch n.config.Provider {
+	case NewsProviderNewsAPI:
+		query.Set("q", base)
+		query.Set("sortBy", "publishedAt")
+		query.Set("language", "en")
+		query.Set("pageSize", fmt.Sprintf("%d", n.config.MaxHeadlines))
+	default:
+		query.Set("currencies", base)
+		query.Set("public", "true")
+		if n.config.APIKey != "" {
+			query.Set("auth_token", n.config.APIKey)
+		}
+	}
+	reqURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if n.config.Provider == NewsProviderNewsAPI && n.config.APIKey != "" {
+		req.Header.Set("X-Api-Key", n.config.APIKey)
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("news API error: %d", resp.StatusCode)
+	}
+
+	var headlines []Headline
+	switch n.config.Provider {
+	case NewsProviderNewsAPI:
+		var result struct {
+			Articles []struct {
+				Title       string    `json:"title"`
+				URL         string    `json:"url"`
+				PublishedAt time.Time `json:"publishedAt"`
+				Source      struct {
+					Name string `json:"name"`
+				} `json:"source"`
+			} `json:"articles"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, fmt.Errorf("failed to decode news response: %w", err)
+		}
+		for _, a := range result.Articles {
+			headlines = append(headlines, Headline{
+				Title:       a.Title,
+				URL:         a.URL,
+				Source:      a.Source.Name,
+				PublishedAt: a.PublishedAt,
+			})
+		}
+	default:
+		var result struct {
+			Results []struct {
+				Title       string    `json:"title"`
+				URL         string    `json:"url"`
+				PublishedAt time.Time `json:"published_at"`
+				Source      struct {
+					Title string `json:"title"`
+				} `json:"source"`
+			} `json:"results"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, fmt.Errorf("failed to decode news response: %w", err)
+		}
+		for _, r := range result.Results {
+			headlines = append(headlines, Headline{
+				Title:       r.Title,
+				URL:         r.URL,
+				Source:      r.Source.Title,
+				PublishedAt: r.PublishedAt,
+			})
+		}
+	}
+
+	if len(headlines) > n.config.MaxHeadlines {
+		headlines = headlines[:n.config.MaxHeadlines]
+	}
+	return headlines, nil
+}
+
+// scoreHeadlines turns headline sentiment into a signal, or nil if no
+// headline carries sentiment.
+func (n *NewsSignalSource) scoreHeadlines(symbol string, headlines []Headline) *types.Signal {
+	var total float64
+	scored := 0
+
+	for i := range headlines {
+		headlines[i].Score = ScoreHeadline(headlines[i].Title)
+		if headlines[i].Score != 0 {
+			total += headlines[i].Score
+			scored++
+		}
+	}
+
+	if scored == 0 {
+		return nil
+	}
+
+	avg := total / float64(scored)
+	direction := types.SignalHold
+	if avg >= newsSentimentThreshold {
+		direction = types.SignalBuy
+	} else if avg <= -newsSentimentThreshold {
+		direction = types.SignalSell
+	}
+
+	strength := avg
+	if strength < 0 {
+		strength = -strength
+	}
+
+	// More corroborating headlines raise confidence
+	confidence := 0.4 + 0.05*float64(scored)
+	if confidence > 0.85 {
+		confidence = 0.85
+	}
+
+	return &types.Signal{
+		ID:         fmt.Sprintf("news-%s-%d", symbol, time.Now().UnixNano()),
+		Symbol:     symbol,
+		Direction:  direction,
+		Strength:   decimal.NewFromFloat(strength),
+		Confidence: decimal.NewFromFloat(confidence),
+		Source:     "news",
+		Timestamp:  time.Now(),
+		Metadata: map[string]interface{}{
+			"provider":        string(n.config.Provider),
+			"sentiment":       avg,
+			"headlines":       headlines,
+			"scoredHeadlines": scored,
+		},
+	}
+}
+
+// ScoreHeadline returns a keyword sentiment score in [-1, 1] for a headline.
+func ScoreHeadline(title string) float64 {
+	words := headlineWords(title)
+
+	var bullish, bearish int
+	for _, kw := range bullishNewsKeywords {
+		if containsKeyword(words, kw) {
+			bullish++
+		}
+	}
+	for _, kw := range bearishNewsKeywords {
+		if containsKeyword(words, kw) {
+			bearish++
+		}
+	}
+
+	if bullish+bearish == 0 {
+		return 0
+	}
+	return float64(bullish-bearish) / float64(bullish+bearish)
+}
+
+// headlineWords splits a headline into lowercase words, keeping hyphenated
+// words such as "sell-off" whole.
+func headlineWords(title string) []string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+	for i, word := range words {
+		words[i] = strings.Trim(word, "-")
+	}
+	return words
+}
+
+// containsKeyword reports whether a keyword, one word or a phrase, appears
+// among words, each of its words in any of its forms.
+func containsKeyword(words []string, keyword string) bool {
+	phrase := strings.Fields(keyword)
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		matched := true
+		for j, base := range phrase {
+			if !isWordForm(words[i+j], base) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// isWordForm reports whether word is base or one of its regular inflections:
+// plurals and third person ("surges", "crashes", "rallies"), past tense and
+// participles ("dropped", "rallied", "surging") and "-ion" nouns
+// ("adoption", "liquidation").
+func isWordForm(word, base string) bool {
+	if word == base {
+		return true
+	}
+	n := len(base)
+	if n < 2 || !strings.HasPrefix(word, base[:n-1]) {
+		return false
+	}
+
+	var forms []string
+	switch last := base[n-1]; {
+	case last == 'e':
+		stem := base[:n-1]
+		forms = append(forms, base+"s", base+"d", stem+"ing", stem+"ion", stem+"ions")
+	case last == 'y':
+		stem := base[:n-1]
+		forms = append(forms, stem+"ies", stem+"ied", base+"ing")
+	default:
+		forms = append(forms, base+"s", base+"ed", base+"ing")
+		if strings.HasSuffix(base, "s") || strings.HasSuffix(base, "x") ||
+			strings.HasSuffix(base, "ch") || strings.HasSuffix(base, "sh") {
+			forms = append(forms, base+"es")
+		}
+		if last == 't' {
+			forms = append(forms, base+"ion", base+"ions")
+		}
+		// Short words ending consonant-vowel-consonant double it: "dropped", "banned"
+		if n >= 3 && !isVowel(base[n-3]) && isVowel(base[n-2]) && !isVowel(last) && !strings.ContainsRune("wxy", rune(last)) {
+			doubled := base + string(last)
+			forms = append(forms, doubled+"ed", doubled+"ing")
+		}
+	}
+
+	for _, form := range forms {
+		if word == form {
+			return true
+		}
+	}
+	return false
+}
+
+// isVowel reports whether a lowercase ASCII letter is a vowel.
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) >= 0
+}
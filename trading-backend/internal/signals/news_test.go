@@ -0,0 +1,161 @@
+package signals_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/signals"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"go.uber.org/zap"
+)
+
+func newsServer(t *testing.T, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		if got := r.URL.Query().Get("currencies"); got != "BTC" {
+			t.Errorf("Expected currencies=BTC, got %q", got)
+		}
+		fmt.Fprint(w, `{"results": [
+			{"title": "Bitcoin surges to record high on ETF inflows", "url": "https://example.com/1", "published_at": "2024-01-01T00:00:00Z"},
+			{"title": "Analysts expect rally to continue", "url": "https://example.com/2", "published_at": "2024-01-01T00:05:00Z"},
+			{"title": "Exchange opens new office", "url": "https://example.com/3", "published_at": "2024-01-01T00:10:00Z"}
+		]}`)
+	}))
+}
+
+func TestNewsSourceScoresHeadlines(t *testing.T) {
+	var requests int32
+	server := newsServer(t, &requests)
+	defer server.Close()
+
+	source := signals.NewNewsSignalSource(zap.NewNop(), signals.NewsSourceConfig{
+		Provider:     signals.NewsProviderCryptoPanic,
+		URL:          server.URL,
+		PollInterval: time.Hour,
+	})
+
+	result, err := source.GetLatestSignals(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("Failed to get news signals: %v", err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("Expected one signal, got %d", len(result))
+	}
+	if result[0].Direction != types.SignalBuy {
+		t.Errorf("Expected buy signal, got %v", result[0].Direction)
+	}
+	if headlines, ok := result[0].Metadata["headlines"].([]signals.Headline); !ok || len(headlines) != 3 {
+		t.Errorf("Expected three headlines in metadata, got %v", result[0].Metadata["headlines"])
+	}
+	if !source.Health().IsHealthy {
+		t.Error("Expected source to be healthy")
+	}
+
+	// A second call within the poll interval must not hit the API
+	if _, err := source.GetLatestSignals(context.Background(), "BTCUSDT"); err != nil {
+		t.Fatalf("Failed to get news signals: %v", err)
+	}
+	if atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("Expected one API request within the poll interval, got %d", requests)
+	}
+}
+
+func TestNewsSourceMarksUnhealthyOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	source := signals.NewNewsSignalSource(zap.NewNop(), signals.NewsSourceConfig{URL: server.URL})
+
+	if _, err := source.GetLatestSignals(context.Background(), "ETHUSDT"); err == nil {
+		t.Fatal("Expected error from failing news API")
+	}
+	if source.Health().IsHealthy {
+		t.Error("Expected source to be unhealthy after an API error")
+	}
+}
+
+func TestNewsSourceBacksOffAfterErrors(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	interval := 100 * time.Millisecond
+	source := signals.NewNewsSignalSource(zap.NewNop(), signals.NewsSourceConfig{
+		URL:          server.URL,
+		PollInterval: interval,
+		MaxBackoff:   time.Hour,
+	})
+
+	start := time.Now()
+	if _, err := source.GetLatestSignals(context.Background(), "BTCUSDT"); err == nil {
+		t.Fatal("Expected error from failing news API")
+	}
+	// A retry right away is refused without calling the API
+	if _, err := source.GetLatestSignals(context.Background(), "BTCUSDT"); err == nil {
+		t.Error("Expected an error while backing off")
+	}
+
+	// One failure doubles the wait to two poll intervals
+	time.Sleep(interval*3/2 - time.Since(start))
+	source.GetLatestSignals(context.Background(), "BTCUSDT")
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected one request inside the backoff, got %d", got)
+	}
+
+	time.Sleep(interval*5/2 - time.Since(start))
+	source.GetLatestSignals(context.Background(), "BTCUSDT")
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("Expected a retry once the backoff elapsed, got %d requests", got)
+	}
+}
+
+func TestAggregatorBuildsNewsSourceFromConfig(t *testing.T) {
+	config := testConfig()
+	config.Sources = []signals.SignalSourceConfig{
+		{Type: signals.SourceTypeNews, Enabled: true, Weight: 0.4, URL: "http://localhost"},
+		{Type: signals.SourceTypeSentiment, Enabled: false},
+	}
+
+	aggregator := signals.NewAggregator(zap.NewNop(), config)
+
+	health := aggregator.GetSourceHealth()
+	if _, ok := health["news"]; !ok {
+		t.Error("Expected news source to be added from config")
+	}
+	if len(health) != 1 {
+		t.Errorf("Expected only enabled sources to be added, got %d", len(health))
+	}
+}
+
+func TestScoreHeadline(t *testing.T) {
+	tests := []struct {
+		title string
+		want  float64
+	}{
+		{"Bitcoin rallies after ETF approval", 1},
+		{"Exchange hack triggers sell-off", -1},
+		{"Markets open for the week", 0},
+		// Keywords match whole words in their inflected forms
+		{"Token price dropped after exchange delisting", -1},
+		{"Regulator sued over stalled approval", 0},
+		{"Bank issues bulletin on Suez shipping", 0},
+		{"Bearish traders buy the dip", 0},
+		{"Whales accumulating as adoption grows", 1},
+	}
+
+	for _, tt := range tests {
+		if got := signals.ScoreHeadline(tt.title); got != tt.want {
+			t.Errorf("ScoreHeadline(%q) = %v, want %v", tt.title, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package signals_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/blockchain"
+	"github.com/atlas-desktop/trading-backend/internal/signals"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+const (
+	exchangeWallet = "0x28c6c06298d514db089934071355e5743bf21d60" // Binance
+	whaleWallet    = "0x00000000000000000000000000000000000000aa"
+)
+
+// transfer is an ETH transfer in a JSON-RPC block.
+func transfer(from, to string, eth int64) map[string]interface{} {
+	return map[string]interface{}{
+		"from":     from,
+		"to":       to,
+		"value":    "0x" + decimal.New(eth, 18).BigInt().Text(16),
+		"gasPrice": "0x3b9aca00",
+	}
+}
+
+// trackedBlock starts a block tracker on an ethereum node whose head block
+// holds txs, and waits for it to record the block.
+func trackedBlock(t *testing.T, txs ...map[string]interface{}) *blockchain.BlockTracker {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var result interface{} = "0x64"
+		if req.Method == "eth_getBlockByNumber" {
+			result = map[string]interface{}{
+				"hash":         "0x64",
+				"parentHash":   "0x63",
+				"timestamp":    "0x6553f100",
+				"gasUsed":      "0x0",
+				"gasLimit":     "0x1c9c380",
+				"transactions": txs,
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	t.Cleanup(server.Close)
+
+	client := blockchain.NewEVMClient(zap.NewNop(), &blockchain.EVMConfig{
+		Chain:  blockchain.ChainEthereum,
+		RPCURL: server.URL,
+	})
+
+	config := blockchain.DefaultBlockTrackerConfig()
+	config.EnableSolana = false
+	config.EVMChains = []string{"ethereum"}
+	config.PollInterval = 10 * time.Millisecond
+	tracker := blockchain.NewBlockTracker(zap.NewNop(), nil,
+		map[string]*blockchain.EVMClient{"ethereum": client}, config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	tracker.Start(ctx)
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-tracker.Events():
+			if event.Type == blockchain.BlockEventNew {
+				return tracker
+			}
+		case <-timeout:
+			t.Fatal("Timed out waiting for the block to be tracked")
+		}
+	}
+}
+
+func TestOnChainSignalFollowsExchangeFlows(t *testing.T) {
+	tests := []struct {
+		name      string
+		txs       []map[string]interface{}
+		direction types.SignalDirection
+		strength  float64
+	}{
+		{
+			name: "withdrawals",
+			txs: []map[string]interface{}{
+				transfer(exchangeWallet, whaleWallet, 45),
+				transfer(whaleWallet, exchangeWallet, 15),
+			},
+			direction: types.SignalBuy,
+			strength:  0.5,
+		},
+		{
+			name: "deposits",
+			txs: []map[string]interface{}{
+				transfer(whaleWallet, exchangeWallet, 30),
+				transfer(exchangeWallet, whaleWallet, 20),
+			},
+			direction: types.SignalSell,
+			strength:  0.2,
+		},
+		{
+			name: "balanced",
+			txs: []map[string]interface{}{
+				transfer(whaleWallet, exchangeWallet, 11),
+				transfer(exchangeWallet, whaleWallet, 14),
+			},
+			direction: types.SignalHold,
+			strength:  0.12,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := signals.NewOnChainSignalSource(zap.NewNop(), trackedBlock(t, tt.txs...))
+
+			got, err := source.GetLatestSignals(context.Background(), "ETH/USDT")
+			if err != nil {
+				t.Fatalf("GetLatestSignals failed: %v", err)
+			}
+			if len(got) != 1 {
+				t.Fatalf("Expected one signal, got %d", len(got))
+			}
+			signal := got[0]
+
+			if signal.Direction != tt.direction {
+				t.Errorf("Direction = %s, want %s", signal.Direction, tt.direction)
+			}
+			if strength, _ := signal.Strength.Float64(); strength < tt.strength-1e-9 || strength > tt.strength+1e-9 {
+				t.Errorf("Strength = %v, want %v", strength, tt.strength)
+			}
+			// Two whale transfers of the twenty needed for full confidence
+			if !signal.Confidence.Equal(decimal.NewFromFloat(0.1)) {
+				t.Errorf("Confidence = %s, want 0.1", signal.Confidence)
+			}
+
+			metadata := signal.Metadata
+			if metadata["chain"] != "ethereum" || metadata["largeTransfers"] != 2 {
+				t.Errorf("Expected two large ethereum transfers in metadata, got %v", metadata)
+			}
+			net := metadata["exchangeInflow"].(float64) - metadata["exchangeOutflow"].(float64)
+			if metadata["netExchangeFlow"] != net || metadata["whaleAccumulation"] != (net < 0) || metadata["whaleDistribution"] != (net > 0) {
+				t.Errorf("Inconsistent flow metadata %v", metadata)
+			}
+		})
+	}
+}
+
+func TestOnChainSignalWithoutData(t *testing.T) {
+	untracked := signals.NewOnChainSignalSource(zap.NewNop(), nil)
+	if _, err := untracked.GetLatestSignals(context.Background(), "ETH/USDT"); err == nil {
+		t.Error("Expected an error without a block tracker")
+	}
+
+	source := signals.NewOnChainSignalSource(zap.NewNop(), trackedBlock(t))
+	if _, err := source.GetLatestSignals(context.Background(), "BTCUSDT"); err == nil {
+		t.Error("Expected an error for an asset without a tracked chain")
+	}
+	if _, err := source.GetLatestSignals(context.Background(), "ETHUSDT"); err != nil {
+		t.Errorf("Expected a signal for ETHUSDT, got %v", err)
+	}
+}
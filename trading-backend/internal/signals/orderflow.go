@@ -0,0 +1,302 @@
+// Package signals provides an order flow signal source.
+package signals
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/execution/adapters"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// OrderFlowTrade is a single executed trade with its aggressor side.
+type OrderFlowTrade struct {
+	Symbol   string
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+	BuySide  bool // Buyer was the aggressor
+	Time     time.Time
+}
+
+// TradeSubscriber starts a trade stream for symbols, calling onTrade for
+// every executed trade until ctx is cancelled.
+type TradeSubscriber func(ctx context.Context, symbols []string, onTrade func(OrderFlowTrade)) error
+
+// BinanceTradeSubscriber streams trades from a Binance adapter.
+func BinanceTradeSubscriber(adapter *adapters.BinanceAdapter) TradeSubscriber {
+	return func(ctx context.Context, symbols []string, onTrade func(OrderFlowTrade)) error {
+		return adapter.SubscribeToTrades(ctx, symbols, func(trade *adapters.BinanceTrade) {
+			onTrade(OrderFlowTrade{
+				Symbol:   trade.Symbol,
+				Price:    trade.Price,
+				Quantity: trade.Quantity,
+				BuySide:  !trade.IsBuyer,
+				Time:     time.UnixMilli(trade.Time),
+			})
+		})
+	}
+}
+
+// OrderFlowConfig configures the order flow signal source.
+type OrderFlowConfig struct {
+	Window         time.Duration   `json:"window"`         // Rolling CVD window
+	DeltaThreshold decimal.Decimal `json:"deltaThreshold"` // Net delta / volume to signal
+	MinPriceMove   decimal.Decimal `json:"minPriceMove"`   // Price change for a divergence
+	MinTrades      int             `json:"minTrades"`
+	EmitInterval   time.Duration   `json:"emitInterval"`
+}
+
+// DefaultOrderFlowConfig returns sensible defaults.
+func DefaultOrderFlowConfig() OrderFlowConfig {
+	return OrderFlowConfig{
+		Window:         5 * time.Minute,
+		DeltaThreshold: decimal.NewFromFloat(0.3),
+		MinPriceMove:   decimal.NewFromFloat(0.002),
+		MinTrades:      20,
+		EmitInterval:   10 * time.Second,
+	}
+}
+
+// OrderFlowSignalSource derives signals from cumulative volume delta (CVD),
+// the difference between aggressive buy and sell volume over a rolling window.
+type OrderFlowSignalSource struct {
+	logger     *zap.Logger
+	name       string
+	subscriber TradeSubscriber
+	streaming  bool // Whether the trade stream has been started
+	config     OrderFlowConfig
+	trades     map[string][]OrderFlowTrade
+	health     SourceHealth
+	mu         sync.RWMutex
+}
+
+// NewOrderFlowSignalSource creates an order flow signal source. If subscriber
+// is nil, trades must be fed through OnTrade.
+func NewOrderFlowSignalSource(logger *zap.Logger, config OrderFlowConfig, subscriber TradeSubscriber) *OrderFlowSignalSource {
+	defaults := DefaultOrderFlowConfig()
+	if config.Window <= 0 {
+		config.Window = defaults.Window
+	}
+	if config.EmitInterval <= 0 {
+		config.EmitInterval = defaults.EmitInterval
+	}
+
+	return &OrderFlowSignalSource{
+		logger:     logger.Named("orderflow-signals"),
+		name:       "orderflow",
+		subscriber: subscriber,
+		config:     config,
+		trades:     make(map[string][]OrderFlowTrade),
+		health: SourceHealth{
+			IsHealthy: true,
+		},
+	}
+}
+
+func (o *OrderFlowSignalSource) Name() string           { return o.name }
+func (o *OrderFlowSignalSource) Type() SignalSourceType { return SourceTypeOrderFlow }
+
+func (o *OrderFlowSignalSource) Health() SourceHealth {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.health
+}
+
+// Window returns the rolling CVD window.
+func (o *OrderFlowSignalSource) Window() time.Duration {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.config.Window
+}
+
+// SetWindow changes the rolling CVD window.
+func (o *OrderFlowSignalSource) SetWindow(window time.Duration) error {
+	if window <= 0 {
+		return fmt.Errorf("window must be positive, got %s", window)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.config.Window = window
+	return nil
+}
+
+// OnTrade records a trade and tracks stream latency.
+func (o *OrderFlowSignalSource) OnTrade(trade OrderFlowTrade) {
+	now := time.Now()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	trades := append(o.trades[trade.Symbol], trade)
+	o.trades[trade.Symbol] = pruneTrades(trades, now.Add(-o.config.Window))
+
+	if latency := now.Sub(trade.Time); latency > 0 {
+		o.health.Latency = latency
+	}
+	o.health.IsHealthy = true
+	o.health.LastError = ""
+}
+
+// StreamTrades starts the trade stream for symbols. It is a no-op without a
+// subscriber or once the stream is running, so the aggregator can poll the
+// source while the stream feeds it.
+func (o *OrderFlowSignalSource) StreamTrades(ctx context.Context, symbols []string) error {
+	o.mu.Lock()
+	if o.subscriber == nil || o.streaming {
+		o.mu.Unlock()
+		return nil
+	}
+	o.streaming = true
+	o.mu.Unlock()
+
+	if err := o.subscriber(ctx, symbols, o.OnTrade); err != nil {
+		o.mu.Lock()
+		o.streaming = false
+		o.health.IsHealthy = false
+		o.health.LastError = err.Error()
+		o.mu.Unlock()
+		return fmt.Errorf("failed to subscribe to trades: %w", err)
+	}
+	return nil
+}
+
+func (o *OrderFlowSignalSource) Subscribe(ctx context.Context, symbols []string) (<-chan *types.Signal, error) {
+	if err := o.StreamTrades(ctx, symbols); err != nil {
+		return nil, err
+	}
+
+	signalChan := make(chan *types.Signal, 100)
+
+	go func() {
+		defer close(signalChan)
+
+		ticker := time.NewTicker(o.config.EmitInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, symbol := range symbols {
+					signals, _ := o.GetLatestSignals(ctx, symbol)
+					for _, signal := range signals {
+						select {
+						case signalChan <- signal:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+			}
+		}
+	}()
+
+	return signalChan, nil
+}
+
+// GetLatestSignals evaluates the current CVD window for a symbol.
+func (o *OrderFlowSignalSource) GetLatestSignals(ctx context.Context, symbol string) ([]*types.Signal, error) {
+	signal := o.evaluate(symbol, time.Now())
+	if signal == nil {
+		return nil, nil
+	}
+
+	o.mu.Lock()
+	o.health.LastSignalTime = signal.Timestamp
+	o.mu.Unlock()
+
+	return []*types.Signal{signal}, nil
+}
+
+// evaluate emits a signal when CVD diverges from price or its normalized
+// delta crosses the threshold.
+func (o *OrderFlowSignalSource) evaluate(symbol string, now time.Time) *types.Signal {
+	o.mu.Lock()
+	trades := pruneTrades(o.trades[symbol], now.Add(-o.config.Window))
+	o.trades[symbol] = trades
+	config := o.config
+	o.mu.Unlock()
+
+	if len(trades) == 0 || len(trades) < config.MinTrades {
+		return nil
+	}
+
+	cvd := decimal.Zero
+	volume := decimal.Zero
+	for _, trade := range trades {
+		volume = volume.Add(trade.Quantity)
+		if trade.BuySide {
+			cvd = cvd.Add(trade.Quantity)
+		} else {
+			cvd = cvd.Sub(trade.Quantity)
+		}
+	}
+	if volume.IsZero() {
+		return nil
+	}
+
+	delta := cvd.Div(volume)
+	firstPrice := trades[0].Price
+	lastPrice := trades[len(trades)-1].Price
+	priceChange := decimal.Zero
+	if !firstPrice.IsZero() {
+		priceChange = lastPrice.Sub(firstPrice).Div(firstPrice)
+	}
+
+	// Divergence: price moves one way while aggressive flow pushes the other
+	halfThreshold := config.DeltaThreshold.Div(decimal.NewFromInt(2))
+	trigger := ""
+	var direction types.SignalDirection
+	switch {
+	case priceChange.LessThanOrEqual(config.MinPriceMove.Neg()) && delta.GreaterThanOrEqual(halfThreshold):
+		trigger, direction = "divergence", types.SignalBuy
+	case priceChange.GreaterThanOrEqual(config.MinPriceMove) && delta.LessThanOrEqual(halfThreshold.Neg()):
+		trigger, direction = "divergence", types.SignalSell
+	case delta.GreaterThanOrEqual(config.DeltaThreshold):
+		trigger, direction = "threshold", types.SignalBuy
+	case delta.LessThanOrEqual(config.DeltaThreshold.Neg()):
+		trigger, direction = "threshold", types.SignalSell
+	default:
+		return nil
+	}
+
+	strength := delta.Abs()
+	confidence := decimal.NewFromFloat(0.5).Add(strength.Mul(decimal.NewFromFloat(0.3)))
+	if trigger == "divergence" {
+		confidence = confidence.Add(decimal.NewFromFloat(0.1))
+	}
+
+	return &types.Signal{
+		ID:         fmt.Sprintf("orderflow-%s-%d", symbol, now.UnixNano()),
+		Symbol:     symbol,
+		Direction:  direction,
+		Strength:   strength,
+		Confidence: confidence,
+		Price:      lastPrice,
+		Source:     "orderflow",
+		Timestamp:  now,
+		Metadata: map[string]interface{}{
+			"trigger":     trigger,
+			"cvd":         cvd.String(),
+			"delta":       delta.StringFixed(4),
+			"priceChange": priceChange.StringFixed(6),
+			"trades":      len(trades),
+			"window":      config.Window.String(),
+		},
+	}
+}
+
+// pruneTrades drops trades older than cutoff. Trades arrive in time order.
+func pruneTrades(trades []OrderFlowTrade, cutoff time.Time) []OrderFlowTrade {
+	i := 0
+	for i < len(trades) && trades[i].Time.Before(cutoff) {
+		i++
+	}
+	return trades[i:]
+}
@@ -0,0 +1,148 @@
+package signals_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/signals"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// feedTrades sends trades with a linear price path; buysPerTen controls how
+// many of every ten trades are aggressive buys.
+func feedTrades(source *signals.OrderFlowSignalSource, count int, startPrice, step float64, buysPerTen int) {
+	start := time.Now().Add(-time.Minute)
+	for i := 0; i < count; i++ {
+		source.OnTrade(signals.OrderFlowTrade{
+			Symbol:   "BTCUSDT",
+			Price:    decimal.NewFromFloat(startPrice + step*float64(i)),
+			Quantity: decimal.NewFromInt(1),
+			BuySide:  i%10 < buysPerTen,
+			Time:     start.Add(time.Duration(i) * time.Second),
+		})
+	}
+}
+
+func TestOrderFlowThresholdCross(t *testing.T) {
+	source := signals.NewOrderFlowSignalSource(zap.NewNop(), signals.DefaultOrderFlowConfig(), nil)
+
+	// 80% aggressive buys with flat price
+	feedTrades(source, 40, 100, 0, 8)
+
+	result, err := source.GetLatestSignals(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("Failed to get order flow signals: %v", err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("Expected one signal, got %d", len(result))
+	}
+	if result[0].Direction != types.SignalBuy {
+		t.Errorf("Expected buy, got %v", result[0].Direction)
+	}
+	if result[0].Metadata["trigger"] != "threshold" {
+		t.Errorf("Expected threshold trigger, got %v", result[0].Metadata["trigger"])
+	}
+	if !result[0].Strength.Equal(decimal.NewFromFloat(0.6)) {
+		t.Errorf("Expected strength 0.6, got %s", result[0].Strength)
+	}
+	if source.Health().Latency <= 0 {
+		t.Error("Expected trade stream latency to be reported")
+	}
+}
+
+func TestOrderFlowBearishDivergence(t *testing.T) {
+	source := signals.NewOrderFlowSignalSource(zap.NewNop(), signals.DefaultOrderFlowConfig(), nil)
+
+	// Price rises while 80% of volume is aggressive selling
+	feedTrades(source, 40, 100, 0.1, 2)
+
+	result, err := source.GetLatestSignals(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("Failed to get order flow signals: %v", err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("Expected one signal, got %d", len(result))
+	}
+	if result[0].Direction != types.SignalSell {
+		t.Errorf("Expected sell, got %v", result[0].Direction)
+	}
+	if result[0].Metadata["trigger"] != "divergence" {
+		t.Errorf("Expected divergence trigger, got %v", result[0].Metadata["trigger"])
+	}
+}
+
+func TestOrderFlowWindowExpiresTrades(t *testing.T) {
+	source := signals.NewOrderFlowSignalSource(zap.NewNop(), signals.DefaultOrderFlowConfig(), nil)
+	feedTrades(source, 40, 100, 0, 8)
+
+	if err := source.SetWindow(0); err == nil {
+		t.Error("Expected error for non-positive window")
+	}
+	if err := source.SetWindow(time.Second); err != nil {
+		t.Fatalf("Failed to set window: %v", err)
+	}
+
+	result, err := source.GetLatestSignals(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("Failed to get order flow signals: %v", err)
+	}
+	if len(result) != 0 {
+		t.Errorf("Expected no signal once trades fall outside the window, got %d", len(result))
+	}
+}
+
+func TestNewSignalSourceBuildsOrderFlowSource(t *testing.T) {
+	if _, err := signals.NewSignalSource(zap.NewNop(), signals.SignalSourceConfig{Type: signals.SourceTypeOrderFlow}); err == nil {
+		t.Error("Expected an error without a trade subscriber")
+	}
+
+	calls := 0
+	var onTrade func(signals.OrderFlowTrade)
+	subscriber := func(ctx context.Context, symbols []string, fn func(signals.OrderFlowTrade)) error {
+		calls++
+		onTrade = fn
+		return nil
+	}
+
+	built, err := signals.NewSignalSource(zap.NewNop(), signals.SignalSourceConfig{
+		Type:            signals.SourceTypeOrderFlow,
+		TradeSubscriber: subscriber,
+	})
+	if err != nil {
+		t.Fatalf("NewSignalSource failed: %v", err)
+	}
+	source, ok := built.(*signals.OrderFlowSignalSource)
+	if !ok {
+		t.Fatalf("Expected an order flow source, got %T", built)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := source.StreamTrades(ctx, []string{"BTCUSDT"}); err != nil {
+		t.Fatalf("StreamTrades failed: %v", err)
+	}
+	if _, err := source.Subscribe(ctx, []string{"BTCUSDT"}); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected the trade stream started once, got %d", calls)
+	}
+
+	// Streamed trades feed the polled signals
+	for i := 0; i < 40; i++ {
+		onTrade(signals.OrderFlowTrade{
+			Symbol:   "BTCUSDT",
+			Price:    decimal.NewFromInt(100),
+			Quantity: decimal.NewFromInt(1),
+			BuySide:  i%10 < 8,
+			Time:     time.Now(),
+		})
+	}
+	result, err := source.GetLatestSignals(ctx, "BTCUSDT")
+	if err != nil || len(result) != 1 || result[0].Direction != types.SignalBuy {
+		t.Errorf("Expected a buy signal from streamed trades, got %v (%v)", result, err)
+	}
+}
@@ -0,0 +1,70 @@
+package signals
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// roundTripFunc serves HTTP requests from a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestPerplexityCachedSignalKeepsIdentity(t *testing.T) {
+	config := DefaultPerplexityConfig()
+	config.APIKey = "test"
+	p := NewPerplexitySignalSource(zap.NewNop(), config)
+
+	fail := false
+	p.httpClient = &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		if fail {
+			return &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}, nil
+		}
+		content, _ := json.Marshal(`{"signal": "BUY", "confidence": 80, "reasons": ["breakout"]}`)
+		body := `{"choices": [{"message": {"content": ` + string(content) + `}}]}`
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+
+	fetched, err := p.GetLatestSignals(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("GetLatestSignals failed: %v", err)
+	}
+	original := fetched[0]
+
+	time.Sleep(time.Millisecond)
+	cached, err := p.GetLatestSignals(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("GetLatestSignals failed: %v", err)
+	}
+	if cached[0].ID != original.ID || !cached[0].Timestamp.Equal(original.Timestamp) {
+		t.Errorf("Expected the cached signal to keep ID %s at %v, got %s at %v",
+			original.ID, original.Timestamp, cached[0].ID, cached[0].Timestamp)
+	}
+	if cached[0].Metadata["cached"] != true || cached[0].Metadata["stale"] != false {
+		t.Errorf("Expected a fresh cached signal, got metadata %v", cached[0].Metadata)
+	}
+
+	// Past its TTL the refresh fails, and the last good signal is served stale
+	p.cache["BTCUSDT"].fetchedAt = time.Now().Add(-2 * config.CacheTTL)
+	fail = true
+	stale, err := p.GetLatestSignals(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("GetLatestSignals failed: %v", err)
+	}
+	if stale[0].ID != original.ID || !stale[0].Timestamp.Equal(original.Timestamp) {
+		t.Errorf("Expected the stale signal to keep its identity, got %s at %v", stale[0].ID, stale[0].Timestamp)
+	}
+	if stale[0].Metadata["stale"] != true {
+		t.Errorf("Expected the signal flagged stale, got metadata %v", stale[0].Metadata)
+	}
+	if _, ok := original.Metadata["cached"]; ok {
+		t.Error("Expected the cached copies to leave the original signal's metadata alone")
+	}
+}
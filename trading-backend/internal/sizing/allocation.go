@@ -0,0 +1,246 @@
+// Package sizing provides risk-parity and expected-shortfall position sizing.
+// Risk parity equalizes each position's contribution to portfolio variance
+// across a basket; CVaR sizing caps the average loss in the tail of a
+// symbol's return distribution rather than the loss to a stop.
+package sizing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// Sizing methods selectable alongside kelly, volatility and risk_budget
+const (
+	MethodRiskParity = "risk_parity"
+	MethodCVaR       = "cvar"
+)
+
+// ErrInsufficientReturns is returned when there are too few returns to
+// estimate a covariance or an expected shortfall.
+var ErrInsufficientReturns = errors.New("insufficient returns for sizing")
+
+// Covariance is an estimate of the covariance of periodic returns across a
+// basket of symbols. Matrix[i][j] is the covariance of Symbols[i] and
+// Symbols[j].
+type Covariance struct {
+	Symbols []string    `json:"symbols"`
+	Matrix  [][]float64 `json:"matrix"`
+}
+
+// EstimateCovariance returns the sample covariance of return series sampled
+// on the same schedule. Series are aligned on their most recent returns and
+// truncated to the shortest one.
+func EstimateCovariance(returns map[string][]float64) (*Covariance, error) {
+	symbols := make([]string, 0, len(returns))
+	n := -1
+	for symbol, series := range returns {
+		symbols = append(symbols, symbol)
+		if n < 0 || len(series) < n {
+			n = len(series)
+		}
+	}
+	if n < 2 {
+		return nil, ErrInsufficientReturns
+	}
+	sort.Strings(symbols)
+
+	// Align on the latest n returns and demean
+	centered := make([][]float64, len(symbols))
+	for i, symbol := range symbols {
+		series := returns[symbol][len(returns[symbol])-n:]
+		mean := 0.0
+		for _, r := range series {
+			mean += r
+		}
+		mean /= float64(n)
+
+		centered[i] = make([]float64, n)
+		for t, r := range series {
+			centered[i][t] = r - mean
+		}
+	}
+
+	matrix := make([][]float64, len(symbols))
+	for i := range matrix {
+		matrix[i] = make([]float64, len(symbols))
+	}
+	for i := range symbols {
+		for j := i; j < len(symbols); j++ {
+			sum := 0.0
+			for t := 0; t < n; t++ {
+				sum += centered[i][t] * centered[j][t]
+			}
+			matrix[i][j] = sum / float64(n-1)
+			matrix[j][i] = matrix[i][j]
+		}
+	}
+
+	return &Covariance{Symbols: symbols, Matrix: matrix}, nil
+}
+
+// RiskParitySizer allocates a basket so every position contributes equally
+// to portfolio variance
+type RiskParitySizer struct {
+	logger        *zap.Logger
+	maxIterations int
+	tolerance     float64
+}
+
+// NewRiskParitySizer creates a risk-parity sizer
+func NewRiskParitySizer(logger *zap.Logger) *RiskParitySizer {
+	return &RiskParitySizer{
+		logger:        logger,
+		maxIterations: 500,
+		tolerance:     1e-10,
+	}
+}
+
+// CalculateWeights returns long-only weights summing to 1 whose risk
+// contributions w_i * (Σw)_i are equal across the basket
+func (rps *RiskParitySizer) CalculateWeights(cov *Covariance) (map[string]float64, error) {
+	n := len(cov.Symbols)
+	if n == 0 {
+		return nil, ErrInsufficientReturns
+	}
+	if len(cov.Matrix) != n {
+		return nil, fmt.Errorf("covariance matrix is not %dx%d", n, n)
+	}
+	for i, row := range cov.Matrix {
+		if len(row) != n {
+			return nil, fmt.Errorf("covariance matrix is not %dx%d", n, n)
+		}
+		if row[i] <= 0 {
+			return nil, fmt.Errorf("covariance of %s has no variance", cov.Symbols[i])
+		}
+	}
+
+	// Cyclical coordinate descent on 1/2 x'Σx - Σ b_i ln(x_i) with equal
+	// budgets b_i = 1/n. Each step solves
+	// Σ_ii x_i^2 + x_i Σ_{j≠i} Σ_ij x_j - b_i = 0 for its positive root.
+	budget := 1.0 / float64(n)
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = 1.0 / math.Sqrt(cov.Matrix[i][i])
+	}
+
+	converged := false
+	for iter := 0; iter < rps.maxIterations && !converged; iter++ {
+		maxChange := 0.0
+		for i := range x {
+			c := 0.0
+			for j := range x {
+				if j != i {
+					c += cov.Matrix[i][j] * x[j]
+				}
+			}
+			a := cov.Matrix[i][i]
+			next := (-c + math.Sqrt(c*c+4*a*budget)) / (2 * a)
+			maxChange = math.Max(maxChange, math.Abs(next-x[i]))
+			x[i] = next
+		}
+		converged = maxChange < rps.tolerance
+	}
+	if !converged {
+		rps.logger.Warn("Risk parity weights did not converge",
+			zap.Int("iterations", rps.maxIterations),
+		)
+	}
+
+	total := 0.0
+	for _, xi := range x {
+		total += xi
+	}
+
+	weights := make(map[string]float64, n)
+	for i, symbol := range cov.Symbols {
+		weights[symbol] = x[i] / total
+	}
+
+	return weights, nil
+}
+
+// RiskContributions returns each position's share of portfolio variance.
+// The shares sum to 1.
+func RiskContributions(cov *Covariance, weights map[string]float64) map[string]float64 {
+	marginal := make([]float64, len(cov.Symbols))
+	variance := 0.0
+	for i, si := range cov.Symbols {
+		for j, sj := range cov.Symbols {
+			marginal[i] += cov.Matrix[i][j] * weights[sj]
+		}
+		variance += weights[si] * marginal[i]
+	}
+
+	contributions := make(map[string]float64, len(cov.Symbols))
+	if variance <= 0 {
+		return contributions
+	}
+	for i, symbol := range cov.Symbols {
+		contributions[symbol] = weights[symbol] * marginal[i] / variance
+	}
+	return contributions
+}
+
+// CVaRSizer calculates position sizes that keep expected shortfall, the
+// average loss beyond VaR, within a fraction of the portfolio
+type CVaRSizer struct {
+	logger          *zap.Logger
+	maxShortfall    float64 // Maximum expected shortfall as % of portfolio
+	confidenceLevel float64 // Tail confidence (e.g., 0.95)
+	maxPosition     float64 // Cap when the tail holds no losses
+}
+
+// NewCVaRSizer creates an expected-shortfall sizer
+func NewCVaRSizer(logger *zap.Logger, maxShortfall, confidence, maxPosition float64) *CVaRSizer {
+	return &CVaRSizer{
+		logger:          logger,
+		maxShortfall:    maxShortfall,
+		confidenceLevel: confidence,
+		maxPosition:     maxPosition,
+	}
+}
+
+// CalculateCVaRSize returns the position as a fraction of the portfolio
+// whose expected shortfall over returns equals the shortfall limit. Returns
+// should be those of the position's direction, so negate a short's.
+func (cs *CVaRSizer) CalculateCVaRSize(returns []float64) (float64, error) {
+	shortfall, err := ExpectedShortfall(returns, cs.confidenceLevel)
+	if err != nil {
+		return 0, err
+	}
+
+	// A tail without losses does not constrain the position
+	if shortfall <= 0 {
+		return cs.maxPosition, nil
+	}
+
+	return math.Min(cs.maxShortfall/shortfall, cs.maxPosition), nil
+}
+
+// ExpectedShortfall returns the average loss, as a positive fraction, of the
+// worst 1-confidence share of returns. It needs enough returns for the tail
+// to hold at least one of them.
+func ExpectedShortfall(returns []float64, confidence float64) (float64, error) {
+	tail := 1 - confidence
+	if tail <= 0 || tail >= 1 {
+		return 0, fmt.Errorf("confidence %v is not in (0, 1)", confidence)
+	}
+	if float64(len(returns))*tail < 1-1e-9 {
+		return 0, ErrInsufficientReturns
+	}
+
+	sorted := append([]float64(nil), returns...)
+	sort.Float64s(sorted)
+
+	k := int(math.Ceil(float64(len(sorted))*tail - 1e-9))
+	loss := 0.0
+	for _, r := range sorted[:k] {
+		loss -= r
+	}
+
+	return loss / float64(k), nil
+}
@@ -0,0 +1,117 @@
+package sizing_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/sizing"
+	"go.uber.org/zap"
+)
+
+func TestRiskParityWeights(t *testing.T) {
+	rps := sizing.NewRiskParitySizer(zap.NewNop())
+
+	// Uncorrelated assets are weighted by inverse volatility: 1/0.2 : 1/0.1
+	uncorrelated := &sizing.Covariance{
+		Symbols: []string{"BTCUSDT", "ETHUSDT"},
+		Matrix: [][]float64{
+			{0.04, 0},
+			{0, 0.01},
+		},
+	}
+	weights, err := rps.CalculateWeights(uncorrelated)
+	if err != nil {
+		t.Fatalf("CalculateWeights: %v", err)
+	}
+	if math.Abs(weights["BTCUSDT"]-1.0/3) > 1e-9 || math.Abs(weights["ETHUSDT"]-2.0/3) > 1e-9 {
+		t.Errorf("weights = %v, want 1/3 and 2/3", weights)
+	}
+
+	// Correlated assets still contribute equal risk
+	correlated := &sizing.Covariance{
+		Symbols: []string{"A", "B", "C"},
+		Matrix: [][]float64{
+			{0.04, 0.006, 0.012},
+			{0.006, 0.01, 0.002},
+			{0.012, 0.002, 0.09},
+		},
+	}
+	weights, err = rps.CalculateWeights(correlated)
+	if err != nil {
+		t.Fatalf("CalculateWeights: %v", err)
+	}
+	total := 0.0
+	for symbol, share := range sizing.RiskContributions(correlated, weights) {
+		total += weights[symbol]
+		if math.Abs(share-1.0/3) > 1e-6 {
+			t.Errorf("%s risk contribution = %v, want 1/3", symbol, share)
+		}
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("weights sum to %v, want 1", total)
+	}
+}
+
+func TestEstimateCovariance(t *testing.T) {
+	cov, err := sizing.EstimateCovariance(map[string][]float64{
+		"A": {0.5, 0.01, 0.03, 0.02}, // Only the latest three align with B
+		"B": {0.02, 0.06, 0.04},
+	})
+	if err != nil {
+		t.Fatalf("EstimateCovariance: %v", err)
+	}
+
+	// A = {0.01, 0.03, 0.02}, B = {0.02, 0.06, 0.04}: var(A) = 1e-4, var(B) = 4e-4, cov = 2e-4
+	want := [][]float64{{1e-4, 2e-4}, {2e-4, 4e-4}}
+	for i := range want {
+		for j := range want[i] {
+			if math.Abs(cov.Matrix[i][j]-want[i][j]) > 1e-12 {
+				t.Errorf("cov[%d][%d] = %v, want %v", i, j, cov.Matrix[i][j], want[i][j])
+			}
+		}
+	}
+
+	if _, err := sizing.EstimateCovariance(map[string][]float64{"A": {0.01}}); !errors.Is(err, sizing.ErrInsufficientReturns) {
+		t.Errorf("single return = %v, want ErrInsufficientReturns", err)
+	}
+}
+
+func TestCVaRSize(t *testing.T) {
+	returns := []float64{-0.10, -0.05}
+	for len(returns) < 20 {
+		returns = append(returns, 0.01)
+	}
+
+	// The worst 5% of 20 returns is -10%: 2% / 10% of the portfolio
+	cs := sizing.NewCVaRSizer(zap.NewNop(), 0.02, 0.95, 0.5)
+	size, err := cs.CalculateCVaRSize(returns)
+	if err != nil {
+		t.Fatalf("CalculateCVaRSize: %v", err)
+	}
+	if math.Abs(size-0.2) > 1e-9 {
+		t.Errorf("size = %v, want 0.2", size)
+	}
+
+	// The worst 10% averages -7.5%
+	shortfall, err := sizing.ExpectedShortfall(returns, 0.90)
+	if err != nil {
+		t.Fatalf("ExpectedShortfall: %v", err)
+	}
+	if math.Abs(shortfall-0.075) > 1e-9 {
+		t.Errorf("shortfall = %v, want 0.075", shortfall)
+	}
+
+	// No losses in the tail: capped at the max position
+	gains := make([]float64, 20)
+	for i := range gains {
+		gains[i] = 0.01
+	}
+	if size, _ := cs.CalculateCVaRSize(gains); size != 0.5 {
+		t.Errorf("size without losses = %v, want 0.5", size)
+	}
+
+	if _, err := cs.CalculateCVaRSize(returns[:10]); !errors.Is(err, sizing.ErrInsufficientReturns) {
+		t.Errorf("10 returns at 95%% = %v, want ErrInsufficientReturns", err)
+	}
+}
@@ -0,0 +1,271 @@
+// Package sizing provides EWMA correlation and covariance estimation across
+// symbols from the bar stream.
+package sizing
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// CorrelationConfig configures correlation estimation
+type CorrelationConfig struct {
+	DecayFactor     float64 `json:"decay_factor"`     // EWMA lambda; higher reacts slower
+	MinObservations int     `json:"min_observations"` // Shared returns before a pair is reported
+}
+
+// DefaultCorrelationConfig returns the RiskMetrics decay for correlations,
+// which is slower than for volatilities since they are noisier
+func DefaultCorrelationConfig() CorrelationConfig {
+	return CorrelationConfig{
+		DecayFactor:     0.97,
+		MinObservations: 20,
+	}
+}
+
+// CorrelationMatrix is a snapshot of pairwise correlations. Correlations[i][j]
+// is nil while Symbols[i] and Symbols[j] share too few returns.
+type CorrelationMatrix struct {
+	Symbols      []string     `json:"symbols"`
+	Correlations [][]*float64 `json:"correlations"`
+	Observations [][]int      `json:"observations"`
+}
+
+// CorrelationEstimator maintains EWMA variances and pairwise covariances of
+// log returns from closing prices. Two symbols' returns are paired only when
+// they span the same bar interval, so symbols on different schedules or
+// with missing bars never bias each other. Moments are zero-mean, as in
+// RiskMetrics, and annualized like VolatilityEstimator's.
+type CorrelationEstimator struct {
+	logger *zap.Logger
+	config CorrelationConfig
+
+	mu      sync.RWMutex
+	symbols map[string]*symbolReturn
+	pairs   map[correlationPair]*pairMoments
+}
+
+// symbolReturn is the running state of one symbol
+type symbolReturn struct {
+	lastPrice    float64
+	lastTime     time.Time
+	ret          float64   // Latest log return
+	from         time.Time // Start of the latest return; zero before the first
+	years        float64
+	variance     float64 // Annualized EWMA variance
+	observations int
+}
+
+// correlationPair orders two symbols so each pair has one key
+type correlationPair struct {
+	a, b string
+}
+
+func pairOf(a, b string) correlationPair {
+	if b < a {
+		a, b = b, a
+	}
+	return correlationPair{a: a, b: b}
+}
+
+// pairMoments tracks a pair over their shared returns only, which keeps the
+// correlation within [-1, 1]
+type pairMoments struct {
+	varA, varB   float64
+	covariance   float64
+	observations int
+}
+
+// NewCorrelationEstimator creates a correlation estimator
+func NewCorrelationEstimator(logger *zap.Logger, config CorrelationConfig) *CorrelationEstimator {
+	defaults := DefaultCorrelationConfig()
+	if config.DecayFactor <= 0 || config.DecayFactor >= 1 {
+		config.DecayFactor = defaults.DecayFactor
+	}
+	if config.MinObservations <= 0 {
+		config.MinObservations = defaults.MinObservations
+	}
+
+	return &CorrelationEstimator{
+		logger:  logger,
+		config:  config,
+		symbols: make(map[string]*symbolReturn),
+		pairs:   make(map[correlationPair]*pairMoments),
+	}
+}
+
+// Update adds a symbol's closing price and pairs its return with every
+// symbol whose latest return spans the same interval. Prices that are not
+// positive or do not move forward in time are ignored.
+func (ce *CorrelationEstimator) Update(symbol string, price float64, timestamp time.Time) {
+	if price <= 0 {
+		return
+	}
+
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+
+	sr, ok := ce.symbols[symbol]
+	if !ok {
+		ce.symbols[symbol] = &symbolReturn{lastPrice: price, lastTime: timestamp}
+		return
+	}
+
+	elapsed := timestamp.Sub(sr.lastTime)
+	if elapsed <= 0 {
+		return
+	}
+
+	sr.ret = math.Log(price / sr.lastPrice)
+	sr.from = sr.lastTime
+	sr.years = float64(elapsed) / float64(year)
+	sr.lastPrice = price
+	sr.lastTime = timestamp
+
+	lambda := ce.config.DecayFactor
+	rate := sr.ret * sr.ret / sr.years
+	if sr.observations == 0 {
+		sr.variance = rate
+	} else {
+		sr.variance = lambda*sr.variance + (1-lambda)*rate
+	}
+	sr.observations++
+
+	for other, peer := range ce.symbols {
+		if other == symbol || peer.from.IsZero() || !peer.from.Equal(sr.from) || !peer.lastTime.Equal(sr.lastTime) {
+			continue
+		}
+
+		key := pairOf(symbol, other)
+		ra, rb := sr.ret, peer.ret
+		if key.a != symbol {
+			ra, rb = rb, ra
+		}
+
+		pm, ok := ce.pairs[key]
+		if !ok {
+			pm = &pairMoments{}
+			ce.pairs[key] = pm
+		}
+		varA, varB, cov := ra*ra/sr.years, rb*rb/sr.years, ra*rb/sr.years
+		if pm.observations == 0 {
+			pm.varA, pm.varB, pm.covariance = varA, varB, cov
+		} else {
+			pm.varA = lambda*pm.varA + (1-lambda)*varA
+			pm.varB = lambda*pm.varB + (1-lambda)*varB
+			pm.covariance = lambda*pm.covariance + (1-lambda)*cov
+		}
+		pm.observations++
+	}
+}
+
+// Correlation returns the EWMA correlation of two symbols' returns, and
+// false until they have shared enough returns. A symbol is perfectly
+// correlated with itself once it has been seen.
+func (ce *CorrelationEstimator) Correlation(a, b string) (float64, bool) {
+	ce.mu.RLock()
+	defer ce.mu.RUnlock()
+
+	return ce.correlation(a, b)
+}
+
+func (ce *CorrelationEstimator) correlation(a, b string) (float64, bool) {
+	if a == b {
+		_, ok := ce.symbols[a]
+		return 1, ok
+	}
+
+	pm, ok := ce.pairs[pairOf(a, b)]
+	if !ok || pm.observations < ce.config.MinObservations {
+		return 0, false
+	}
+	if pm.varA <= 0 || pm.varB <= 0 {
+		return 0, true
+	}
+	return pm.covariance / math.Sqrt(pm.varA*pm.varB), true
+}
+
+// CovarianceMatrix returns the annualized covariance of symbols' returns.
+// Each symbol's own EWMA variance is on the diagonal and each pair's
+// correlation scales the off-diagonal, so the matrix reflects every return
+// a symbol has had rather than only those shared with the rest of the
+// basket. It returns ErrInsufficientReturns until every symbol and pair has
+// enough returns.
+func (ce *CorrelationEstimator) CovarianceMatrix(symbols []string) (*Covariance, error) {
+	ce.mu.RLock()
+	defer ce.mu.RUnlock()
+
+	if len(symbols) == 0 {
+		return nil, ErrInsufficientReturns
+	}
+	symbols = append([]string(nil), symbols...)
+	sort.Strings(symbols)
+
+	vols := make([]float64, len(symbols))
+	for i, symbol := range symbols {
+		sr, ok := ce.symbols[symbol]
+		if !ok || sr.observations < ce.config.MinObservations {
+			return nil, ErrInsufficientReturns
+		}
+		vols[i] = math.Sqrt(sr.variance)
+	}
+
+	matrix := make([][]float64, len(symbols))
+	for i := range matrix {
+		matrix[i] = make([]float64, len(symbols))
+	}
+	for i := range symbols {
+		for j := i; j < len(symbols); j++ {
+			corr, ok := ce.correlation(symbols[i], symbols[j])
+			if !ok {
+				return nil, ErrInsufficientReturns
+			}
+			matrix[i][j] = corr * vols[i] * vols[j]
+			matrix[j][i] = matrix[i][j]
+		}
+	}
+
+	return &Covariance{Symbols: symbols, Matrix: matrix}, nil
+}
+
+// Matrix returns the correlations between symbols, or between every symbol
+// seen when symbols is empty.
+func (ce *CorrelationEstimator) Matrix(symbols []string) CorrelationMatrix {
+	ce.mu.RLock()
+	defer ce.mu.RUnlock()
+
+	if len(symbols) == 0 {
+		for symbol := range ce.symbols {
+			symbols = append(symbols, symbol)
+		}
+	} else {
+		symbols = append([]string(nil), symbols...)
+	}
+	sort.Strings(symbols)
+
+	matrix := CorrelationMatrix{
+		Symbols:      symbols,
+		Correlations: make([][]*float64, len(symbols)),
+		Observations: make([][]int, len(symbols)),
+	}
+	for i, a := range symbols {
+		matrix.Correlations[i] = make([]*float64, len(symbols))
+		matrix.Observations[i] = make([]int, len(symbols))
+		for j, b := range symbols {
+			if corr, ok := ce.correlation(a, b); ok {
+				matrix.Correlations[i][j] = &corr
+			}
+			if a == b {
+				if sr, ok := ce.symbols[a]; ok {
+					matrix.Observations[i][j] = sr.observations
+				}
+			} else if pm, ok := ce.pairs[pairOf(a, b)]; ok {
+				matrix.Observations[i][j] = pm.observations
+			}
+		}
+	}
+	return matrix
+}
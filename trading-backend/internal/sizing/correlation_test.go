@@ -0,0 +1,89 @@
+package sizing_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/sizing"
+	"go.uber.org/zap"
+)
+
+func TestCorrelationEstimatorPairsSharedReturns(t *testing.T) {
+	ce := sizing.NewCorrelationEstimator(zap.NewNop(), sizing.CorrelationConfig{DecayFactor: 0.97, MinObservations: 10})
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// LEVERED moves twice as far as BASE, INVERSE the opposite way, and
+	// OFFSET closes half an hour later so it never shares an interval
+	base, levered, inverse, offset := 100.0, 100.0, 100.0, 100.0
+	for i := 0; i <= 30; i++ {
+		if i > 0 {
+			ret := 0.01 * math.Sin(float64(i))
+			base *= math.Exp(ret)
+			levered *= math.Exp(2 * ret)
+			inverse *= math.Exp(-ret)
+			offset *= math.Exp(ret)
+		}
+		at := start.Add(time.Duration(i) * time.Hour)
+		ce.Update("BASE", base, at)
+		ce.Update("LEVERED", levered, at)
+		ce.Update("INVERSE", inverse, at)
+		ce.Update("OFFSET", offset, at.Add(30*time.Minute))
+
+		if _, ok := ce.Correlation("BASE", "LEVERED"); ok != (i >= 10) {
+			t.Fatalf("correlation after %d returns reported = %v", i, ok)
+		}
+	}
+
+	for pair, want := range map[[2]string]float64{{"LEVERED", "BASE"}: 1, {"BASE", "INVERSE"}: -1, {"INVERSE", "LEVERED"}: -1} {
+		corr, ok := ce.Correlation(pair[0], pair[1])
+		if !ok || math.Abs(corr-want) > 1e-9 {
+			t.Errorf("%v correlation = %v, %v, want %v", pair, corr, ok, want)
+		}
+	}
+	if _, ok := ce.Correlation("BASE", "OFFSET"); ok {
+		t.Error("symbols closing at different times were paired")
+	}
+
+	cov, err := ce.CovarianceMatrix([]string{"LEVERED", "BASE"})
+	if err != nil {
+		t.Fatalf("CovarianceMatrix: %v", err)
+	}
+	if cov.Symbols[0] != "BASE" || math.Abs(cov.Matrix[1][1]-4*cov.Matrix[0][0]) > 1e-12 || math.Abs(cov.Matrix[0][1]-2*cov.Matrix[0][0]) > 1e-12 {
+		t.Errorf("covariance = %+v, want LEVERED at twice BASE's volatility", cov)
+	}
+	if _, err := ce.CovarianceMatrix([]string{"BASE", "OFFSET"}); !errors.Is(err, sizing.ErrInsufficientReturns) {
+		t.Errorf("unpaired covariance = %v, want ErrInsufficientReturns", err)
+	}
+
+	matrix := ce.Matrix(nil)
+	if len(matrix.Symbols) != 4 || matrix.Symbols[2] != "LEVERED" {
+		t.Fatalf("matrix symbols = %v", matrix.Symbols)
+	}
+	if matrix.Correlations[0][3] != nil || matrix.Observations[0][2] != 30 || *matrix.Correlations[2][2] != 1 {
+		t.Errorf("matrix = %+v", matrix)
+	}
+}
+
+func TestCorrelationEstimatorSkipsMissedBars(t *testing.T) {
+	ce := sizing.NewCorrelationEstimator(zap.NewNop(), sizing.CorrelationConfig{MinObservations: 1})
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ce.Update("A", 100, start)
+	ce.Update("B", 100, start)
+	ce.Update("A", 101, start.Add(time.Hour))
+	// B misses the first hour, so its return spans two of A's
+	ce.Update("A", 102, start.Add(2*time.Hour))
+	ce.Update("B", 103, start.Add(2*time.Hour))
+
+	if _, ok := ce.Correlation("A", "B"); ok {
+		t.Error("returns over different intervals were paired")
+	}
+
+	ce.Update("A", 101, start.Add(3*time.Hour))
+	ce.Update("B", 102, start.Add(3*time.Hour))
+	if corr, ok := ce.Correlation("B", "A"); !ok || math.Abs(corr-1) > 1e-9 {
+		t.Errorf("correlation = %v, %v, want one shared return moving together", corr, ok)
+	}
+}
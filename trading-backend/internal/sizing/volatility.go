@@ -0,0 +1,161 @@
+// Package sizing provides realized volatility estimation from the bar stream
+// for volatility-targeted sizing.
+package sizing
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// year is the period volatility is annualized to
+const year = 365 * 24 * time.Hour
+
+// VolatilityConfig configures volatility estimation
+type VolatilityConfig struct {
+	DecayFactor     float64 `json:"decay_factor"`     // EWMA lambda; higher reacts slower
+	WindowSize      int     `json:"window_size"`      // Returns in the rolling window
+	MinObservations int     `json:"min_observations"` // Returns before an estimate is reported
+}
+
+// DefaultVolatilityConfig returns the RiskMetrics decay over a 20-bar window
+func DefaultVolatilityConfig() VolatilityConfig {
+	return VolatilityConfig{
+		DecayFactor:     0.94,
+		WindowSize:      20,
+		MinObservations: 10,
+	}
+}
+
+// VolatilityEstimate is a symbol's annualized volatility
+type VolatilityEstimate struct {
+	Symbol       string  `json:"symbol"`
+	EWMA         float64 `json:"ewma"`
+	Realized     float64 `json:"realized"` // Over the rolling window
+	Observations int     `json:"observations"`
+}
+
+// VolatilityEstimator maintains per-symbol EWMA and rolling-window realized
+// volatility from closing prices. Each squared log return is scaled by the
+// time it spans, so estimates annualize correctly for any bar interval.
+type VolatilityEstimator struct {
+	logger *zap.Logger
+	config VolatilityConfig
+
+	mu      sync.RWMutex
+	symbols map[string]*symbolVolatility
+}
+
+// symbolVolatility is the running state of one symbol
+type symbolVolatility struct {
+	lastPrice    float64
+	lastTime     time.Time
+	ewmaVariance float64 // Annualized
+	observations int
+	window       []volatilityObservation
+}
+
+// volatilityObservation is one return in the rolling window
+type volatilityObservation struct {
+	squaredReturn float64
+	years         float64
+}
+
+// NewVolatilityEstimator creates a volatility estimator
+func NewVolatilityEstimator(logger *zap.Logger, config VolatilityConfig) *VolatilityEstimator {
+	defaults := DefaultVolatilityConfig()
+	if config.DecayFactor <= 0 || config.DecayFactor >= 1 {
+		config.DecayFactor = defaults.DecayFactor
+	}
+	if config.WindowSize <= 0 {
+		config.WindowSize = defaults.WindowSize
+	}
+	if config.MinObservations <= 0 {
+		config.MinObservations = defaults.MinObservations
+	}
+
+	return &VolatilityEstimator{
+		logger:  logger,
+		config:  config,
+		symbols: make(map[string]*symbolVolatility),
+	}
+}
+
+// Update adds a symbol's closing price. Prices that are not positive or do
+// not move forward in time are ignored.
+func (ve *VolatilityEstimator) Update(symbol string, price float64, timestamp time.Time) {
+	if price <= 0 {
+		return
+	}
+
+	ve.mu.Lock()
+	defer ve.mu.Unlock()
+
+	sv, ok := ve.symbols[symbol]
+	if !ok {
+		ve.symbols[symbol] = &symbolVolatility{lastPrice: price, lastTime: timestamp}
+		return
+	}
+
+	elapsed := timestamp.Sub(sv.lastTime)
+	if elapsed <= 0 {
+		return
+	}
+
+	ret := math.Log(price / sv.lastPrice)
+	years := float64(elapsed) / float64(year)
+	sv.lastPrice = price
+	sv.lastTime = timestamp
+
+	// Variance rate of this return, per year
+	rate := ret * ret / years
+	if sv.observations == 0 {
+		sv.ewmaVariance = rate
+	} else {
+		lambda := ve.config.DecayFactor
+		sv.ewmaVariance = lambda*sv.ewmaVariance + (1-lambda)*rate
+	}
+	sv.observations++
+
+	sv.window = append(sv.window, volatilityObservation{squaredReturn: ret * ret, years: years})
+	if excess := len(sv.window) - ve.config.WindowSize; excess > 0 {
+		sv.window = sv.window[excess:]
+	}
+}
+
+// Volatility returns a symbol's annualized EWMA volatility, and false until
+// enough returns have been observed
+func (ve *VolatilityEstimator) Volatility(symbol string) (float64, bool) {
+	estimate, ok := ve.Estimate(symbol)
+	if !ok {
+		return 0, false
+	}
+	return estimate.EWMA, true
+}
+
+// Estimate returns a symbol's EWMA and rolling-window volatility, and false
+// until enough returns have been observed
+func (ve *VolatilityEstimator) Estimate(symbol string) (VolatilityEstimate, bool) {
+	ve.mu.RLock()
+	defer ve.mu.RUnlock()
+
+	sv, ok := ve.symbols[symbol]
+	if !ok || sv.observations < ve.config.MinObservations {
+		return VolatilityEstimate{}, false
+	}
+
+	var squared, years float64
+	for _, obs := range sv.window {
+		squared += obs.squaredReturn
+		years += obs.years
+	}
+
+	return VolatilityEstimate{
+		Symbol:       symbol,
+		EWMA:         math.Sqrt(sv.ewmaVariance),
+		Realized:     math.Sqrt(squared / years),
+		Observations: sv.observations,
+	}, true
+}
@@ -0,0 +1,66 @@
+package sizing_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/sizing"
+	"go.uber.org/zap"
+)
+
+func TestVolatilityEstimatorAnnualizes(t *testing.T) {
+	ve := sizing.NewVolatilityEstimator(zap.NewNop(), sizing.DefaultVolatilityConfig())
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Daily and hourly closes alternating by a 1% log return
+	price := 100.0
+	for i := 0; i <= 30; i++ {
+		if i > 0 {
+			price *= math.Exp(0.01 * math.Pow(-1, float64(i)))
+		}
+		ve.Update("DAILY", price, start.Add(time.Duration(i)*24*time.Hour))
+		ve.Update("HOURLY", price, start.Add(time.Duration(i)*time.Hour))
+
+		if _, ok := ve.Volatility("DAILY"); ok != (i >= 10) {
+			t.Fatalf("estimate after %d returns reported = %v", i, ok)
+		}
+	}
+
+	for symbol, periods := range map[string]float64{"DAILY": 365, "HOURLY": 365 * 24} {
+		want := 0.01 * math.Sqrt(periods)
+		estimate, ok := ve.Estimate(symbol)
+		if !ok {
+			t.Fatalf("%s has no estimate", symbol)
+		}
+		if math.Abs(estimate.EWMA-want) > 1e-9 || math.Abs(estimate.Realized-want) > 1e-9 {
+			t.Errorf("%s = %+v, want %v", symbol, estimate, want)
+		}
+		if estimate.Observations != 30 {
+			t.Errorf("%s observations = %d, want 30", symbol, estimate.Observations)
+		}
+	}
+}
+
+func TestVolatilityEstimatorDecay(t *testing.T) {
+	config := sizing.DefaultVolatilityConfig()
+	config.MinObservations = 1
+	config.WindowSize = 2
+	ve := sizing.NewVolatilityEstimator(zap.NewNop(), config)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Three quiet days, then a 10% move
+	closes := []float64{100, 100, 100, 100, 100 * math.Exp(0.1)}
+	for i, c := range closes {
+		ve.Update("BTCUSDT", c, start.Add(time.Duration(i)*24*time.Hour))
+	}
+
+	estimate, _ := ve.Estimate("BTCUSDT")
+	// EWMA: 0.06 of the move's variance; window: the move and one quiet day
+	if want := math.Sqrt(0.06 * 0.01 * 365); math.Abs(estimate.EWMA-want) > 1e-9 {
+		t.Errorf("EWMA = %v, want %v", estimate.EWMA, want)
+	}
+	if want := math.Sqrt(0.01 / 2 * 365); math.Abs(estimate.Realized-want) > 1e-9 {
+		t.Errorf("realized = %v, want %v", estimate.Realized, want)
+	}
+}
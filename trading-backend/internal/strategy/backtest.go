@@ -0,0 +1,74 @@
+// Package strategy provides adapters that run registered strategies in the
+// backtesting engine.
+package strategy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atlas-desktop/trading-backend/internal/backtester"
+	bus "github.com/atlas-desktop/trading-backend/internal/events"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/google/uuid"
+)
+
+// backtestAdapter runs a Strategy as a backtester.Strategy
+type backtestAdapter struct {
+	strategy Strategy
+}
+
+// NewBacktestStrategy creates the named strategy with params applied and
+// adapts it to backtester.Strategy. Buys open positions and sells close
+// them, matching the engine's long-only model.
+func (r *StrategyRegistry) NewBacktestStrategy(name string, params map[string]float64) (backtester.Strategy, error) {
+	s, ok := r.Create(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy: %s", name)
+	}
+
+	for param, value := range params {
+		if err := s.SetParameter(param, value); err != nil {
+			return nil, fmt.Errorf("failed to set %s on %s: %w", param, name, err)
+		}
+	}
+
+	if err := s.Initialize(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to initialize %s: %w", name, err)
+	}
+
+	return &backtestAdapter{strategy: s}, nil
+}
+
+// Name returns the wrapped strategy's name
+func (a *backtestAdapter) Name() string { return a.strategy.Name() }
+
+// OnBar passes the bar to the wrapped strategy and converts its signal
+func (a *backtestAdapter) OnBar(bar *bus.BarEvent) (*types.Signal, error) {
+	signal, err := a.strategy.OnBar(types.OHLCV{
+		Timestamp: bar.Timestamp,
+		Open:      bar.Open,
+		High:      bar.High,
+		Low:       bar.Low,
+		Close:     bar.Close,
+		Volume:    bar.Volume,
+	})
+	if err != nil || signal == nil {
+		return nil, err
+	}
+
+	signalType := types.SignalTypeEntry
+	if signal.Side == types.OrderSideSell {
+		signalType = types.SignalTypeExit
+	}
+
+	// No price, so the engine fills at market on the next bar
+	return &types.Signal{
+		ID:         uuid.New().String(),
+		Symbol:     bar.Symbol,
+		Type:       signalType,
+		Side:       signal.Side,
+		Confidence: signal.Strength,
+		Source:     a.strategy.Name(),
+		CreatedAt:  bar.Timestamp,
+	}, nil
+}
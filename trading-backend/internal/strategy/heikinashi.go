@@ -0,0 +1,41 @@
+package strategy
+
+import (
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// ToHeikinAshi transforms bars into Heikin-Ashi candles. Each candle's open
+// is the midpoint of the previous candle's open and close, so the transform
+// is recursive and must run over bars in order from the start of a series.
+func ToHeikinAshi(bars []types.OHLCV) []types.OHLCV {
+	candles := make([]types.OHLCV, len(bars))
+	for i, bar := range bars {
+		var prev *types.OHLCV
+		if i > 0 {
+			prev = &candles[i-1]
+		}
+		candles[i] = nextHeikinAshi(prev, bar)
+	}
+	return candles
+}
+
+// nextHeikinAshi returns the Heikin-Ashi candle of bar following prev, or
+// seeds the series from bar alone when prev is nil. Fields other than the
+// prices, such as the timestamp and volume, are kept from bar.
+func nextHeikinAshi(prev *types.OHLCV, bar types.OHLCV) types.OHLCV {
+	two := decimal.NewFromInt(2)
+
+	haClose := bar.Open.Add(bar.High).Add(bar.Low).Add(bar.Close).Div(decimal.NewFromInt(4))
+	haOpen := bar.Open.Add(bar.Close).Div(two)
+	if prev != nil {
+		haOpen = prev.Open.Add(prev.Close).Div(two)
+	}
+
+	candle := bar
+	candle.Open = haOpen
+	candle.Close = haClose
+	candle.High = decimal.Max(bar.High, haOpen, haClose)
+	candle.Low = decimal.Min(bar.Low, haOpen, haClose)
+	return candle
+}
@@ -0,0 +1,83 @@
+package strategy_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/strategy"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func bar(open, high, low, close float64) types.OHLCV {
+	return types.OHLCV{
+		Open:  decimal.NewFromFloat(open),
+		High:  decimal.NewFromFloat(high),
+		Low:   decimal.NewFromFloat(low),
+		Close: decimal.NewFromFloat(close),
+	}
+}
+
+func TestToHeikinAshiCarriesPriorCandle(t *testing.T) {
+	candles := strategy.ToHeikinAshi([]types.OHLCV{
+		bar(10, 12, 9, 11),
+		bar(11, 13, 10, 12),
+		bar(12, 12.5, 8, 9),
+	})
+
+	expected := []types.OHLCV{
+		bar(10.5, 12, 9, 10.5),
+		// Opens at the midpoint of the previous Heikin-Ashi candle, not the real one
+		bar(10.5, 13, 10, 11.5),
+		bar(11, 12.5, 8, 10.375),
+	}
+	for i, want := range expected {
+		got := candles[i]
+		if !got.Open.Equal(want.Open) || !got.High.Equal(want.High) || !got.Low.Equal(want.Low) || !got.Close.Equal(want.Close) {
+			t.Errorf("Candle %d: expected %s/%s/%s/%s, got %s/%s/%s/%s", i,
+				want.Open, want.High, want.Low, want.Close, got.Open, got.High, got.Low, got.Close)
+		}
+	}
+}
+
+func TestHeikinAshiReducesTrendFollowingWhipsaws(t *testing.T) {
+	registry := strategy.NewStrategyRegistry(zap.NewNop())
+
+	run := func(heikinAshi bool) []*strategy.Signal {
+		s, _ := registry.Create("trend_following")
+		for name, value := range map[string]interface{}{"fast_period": 5, "slow_period": 10, "heikin_ashi": heikinAshi} {
+			if err := s.SetParameter(name, value); err != nil {
+				t.Fatalf("SetParameter(%s) failed: %v", name, err)
+			}
+		}
+
+		// A slow swing with a sharp bar against it every few bars
+		var signals []*strategy.Signal
+		price := 100.0
+		for i := 0; i < 80; i++ {
+			move := 1.0
+			if (i/10)%2 == 1 {
+				move = -1
+			}
+			if i%3 == 2 {
+				move *= -2.5
+			}
+			open := price
+			price += move
+			signal, err := s.OnBar(bar(open, math.Max(open, price)+0.5, math.Min(open, price)-0.5, price))
+			if err != nil {
+				t.Fatalf("OnBar failed: %v", err)
+			}
+			if signal != nil {
+				signals = append(signals, signal)
+			}
+		}
+		return signals
+	}
+
+	raw, smoothed := run(false), run(true)
+	if len(smoothed) >= len(raw) {
+		t.Errorf("Expected fewer crossovers on Heikin-Ashi candles, got %d against %d", len(smoothed), len(raw))
+	}
+}
@@ -0,0 +1,360 @@
+package strategy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ErrLiveStrategyNotFound is returned for an unknown running strategy ID.
+var ErrLiveStrategyNotFound = errors.New("running strategy not found")
+
+// LiveRegistryConfig configures running strategies.
+type LiveRegistryConfig struct {
+	AuditFile     string `json:"auditFile"`     // JSONL file parameter changes are appended to; empty keeps them in memory only
+	MaxAuditTrail int    `json:"maxAuditTrail"` // Parameter changes kept in memory
+}
+
+// DefaultLiveRegistryConfig keeps the last 1000 parameter changes in memory.
+func DefaultLiveRegistryConfig() LiveRegistryConfig {
+	return LiveRegistryConfig{
+		MaxAuditTrail: 1000,
+	}
+}
+
+// ParamChange is an audited change of a running strategy's parameter.
+type ParamChange struct {
+	StrategyID string      `json:"strategyId"`
+	Strategy   string      `json:"strategy"`
+	Parameter  string      `json:"parameter"`
+	OldValue   interface{} `json:"oldValue"`
+	NewValue   interface{} `json:"newValue"`
+	User       string      `json:"user"`
+	Source     string      `json:"source"` // "api" or "ws"
+	ChangedAt  time.Time   `json:"changedAt"`
+}
+
+// LiveStrategy is a strategy instance running on one symbol's bars.
+type LiveStrategy struct {
+	mu        sync.Mutex
+	id        string
+	name      string
+	symbol    string
+	strategy  Strategy
+	startedAt time.Time
+	bars      int
+}
+
+// LiveStrategyInfo describes a running strategy.
+type LiveStrategyInfo struct {
+	ID         string                       `json:"id"`
+	Strategy   string                       `json:"strategy"`
+	Symbol     string                       `json:"symbol"`
+	Parameters map[string]StrategyParameter `json:"parameters"`
+	StartedAt  time.Time                    `json:"startedAt"`
+	Bars       int                          `json:"bars"`
+	MinBars    int                          `json:"minBars"` // Bars needed before signals are routed
+	Ready      bool                         `json:"ready"`   // Warmed up; signals are routed
+}
+
+// Info returns a snapshot of the running strategy.
+func (l *LiveStrategy) Info() LiveStrategyInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	params := make(map[string]StrategyParameter, len(l.strategy.Parameters()))
+	for name, param := range l.strategy.Parameters() {
+		params[name] = param
+	}
+	return LiveStrategyInfo{
+		ID:         l.id,
+		Strategy:   l.name,
+		Symbol:     l.symbol,
+		Parameters: params,
+		StartedAt:  l.startedAt,
+		Bars:       l.bars,
+		MinBars:    l.strategy.MinBars(),
+		Ready:      l.readyLocked(),
+	}
+}
+
+// readyLocked reports whether the strategy has seen the bars it needs.
+// Callers hold l.mu.
+func (l *LiveStrategy) readyLocked() bool {
+	return l.bars >= l.strategy.MinBars()
+}
+
+// LiveRegistry holds running strategy instances by ID, so their parameters
+// can be tuned while they trade. Parameter changes are made between bars and
+// take effect on the next one.
+type LiveRegistry struct {
+	logger   *zap.Logger
+	config   LiveRegistryConfig
+	registry *StrategyRegistry
+
+	mu         sync.RWMutex
+	strategies map[string]*LiveStrategy
+	audit      []ParamChange
+	auditMu    sync.Mutex
+
+	// OnSignal is called with each running strategy's signals
+	OnSignal func(id string, signal *Signal)
+	// OnParamChange is called after each applied parameter change
+	OnParamChange func(change ParamChange)
+}
+
+// NewLiveRegistry creates a registry of running strategies created from
+// registry's factories.
+func NewLiveRegistry(logger *zap.Logger, config LiveRegistryConfig, registry *StrategyRegistry) *LiveRegistry {
+	if config.MaxAuditTrail <= 0 {
+		config.MaxAuditTrail = DefaultLiveRegistryConfig().MaxAuditTrail
+	}
+	return &LiveRegistry{
+		logger:     logger.Named("live-strategies"),
+		config:     config,
+		registry:   registry,
+		strategies: make(map[string]*LiveStrategy),
+	}
+}
+
+// Start creates the named strategy with params applied and runs it on
+// symbol's bars.
+func (r *LiveRegistry) Start(name, symbol string, params map[string]interface{}) (LiveStrategyInfo, error) {
+	if symbol == "" {
+		return LiveStrategyInfo{}, fmt.Errorf("symbol is required")
+	}
+	s, ok := r.registry.Create(name)
+	if !ok {
+		return LiveStrategyInfo{}, fmt.Errorf("unknown strategy: %s", name)
+	}
+	for param, value := range params {
+		if err := s.SetParameter(param, value); err != nil {
+			return LiveStrategyInfo{}, fmt.Errorf("failed to set %s on %s: %w", param, name, err)
+		}
+	}
+	if err := s.Initialize(context.Background()); err != nil {
+		return LiveStrategyInfo{}, fmt.Errorf("failed to initialize %s: %w", name, err)
+	}
+
+	live := &LiveStrategy{
+		id:        uuid.New().String(),
+		name:      name,
+		symbol:    symbol,
+		strategy:  s,
+		startedAt: time.Now(),
+	}
+	r.mu.Lock()
+	r.strategies[live.id] = live
+	r.mu.Unlock()
+
+	r.logger.Info("Started strategy",
+		zap.String("id", live.id),
+		zap.String("strategy", name),
+		zap.String("symbol", symbol))
+	return live.Info(), nil
+}
+
+// Stop removes a running strategy.
+func (r *LiveRegistry) Stop(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.strategies[id]; !ok {
+		return fmt.Errorf("%s: %w", id, ErrLiveStrategyNotFound)
+	}
+	delete(r.strategies, id)
+	return nil
+}
+
+// Get returns a running strategy.
+func (r *LiveRegistry) Get(id string) (*LiveStrategy, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	live, ok := r.strategies[id]
+	return live, ok
+}
+
+// List describes the running strategies, oldest first.
+func (r *LiveRegistry) List() []LiveStrategyInfo {
+	r.mu.RLock()
+	running := make([]*LiveStrategy, 0, len(r.strategies))
+	for _, live := range r.strategies {
+		running = append(running, live)
+	}
+	r.mu.RUnlock()
+
+	infos := make([]LiveStrategyInfo, len(running))
+	for i, live := range running {
+		infos[i] = live.Info()
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].StartedAt.Before(infos[j].StartedAt) })
+	return infos
+}
+
+// OnBar passes symbol's bar to every strategy running on it. Signals from a
+// strategy still warming up are dropped, so a partial lookback never reaches
+// OnSignal.
+func (r *LiveRegistry) OnBar(symbol string, bar types.OHLCV) {
+	r.mu.RLock()
+	running := make([]*LiveStrategy, 0, len(r.strategies))
+	for _, live := range r.strategies {
+		if sameSymbol(live.symbol, symbol) {
+			running = append(running, live)
+		}
+	}
+	r.mu.RUnlock()
+
+	for _, live := range running {
+		live.mu.Lock()
+		signal, err := live.strategy.OnBar(bar)
+		live.bars++
+		ready := live.readyLocked()
+		live.mu.Unlock()
+
+		if err != nil {
+			r.logger.Warn("Strategy failed on bar",
+				zap.String("id", live.id),
+				zap.String("strategy", live.name),
+				zap.Error(err))
+			continue
+		}
+		if signal != nil && !ready {
+			r.logger.Debug("Dropping signal from warming strategy",
+				zap.String("id", live.id),
+				zap.String("strategy", live.name))
+			continue
+		}
+		if signal != nil && r.OnSignal != nil {
+			if signal.Symbol == "" {
+				signal.Symbol = live.symbol
+			}
+			r.OnSignal(live.id, signal)
+		}
+	}
+}
+
+// SetParameters validates and applies parameter changes to a running
+// strategy, recording each to the audit trail under user. Either every
+// change is applied or, if one is rejected, none are.
+func (r *LiveRegistry) SetParameters(id string, params map[string]interface{}, user, source string) ([]ParamChange, error) {
+	live, ok := r.Get(id)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", id, ErrLiveStrategyNotFound)
+	}
+
+	// Apply in name order so the audit trail is deterministic
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	changes := make([]ParamChange, 0, len(names))
+
+	live.mu.Lock()
+	for _, name := range names {
+		old := live.strategy.Parameters()[name].Current
+		if err := live.strategy.SetParameter(name, params[name]); err != nil {
+			for i := len(changes) - 1; i >= 0; i-- {
+				live.strategy.SetParameter(changes[i].Parameter, changes[i].OldValue)
+			}
+			live.mu.Unlock()
+			return nil, err
+		}
+		changes = append(changes, ParamChange{
+			StrategyID: id,
+			Strategy:   live.name,
+			Parameter:  name,
+			OldValue:   old,
+			NewValue:   live.strategy.Parameters()[name].Current,
+			User:       user,
+			Source:     source,
+			ChangedAt:  now,
+		})
+	}
+	live.mu.Unlock()
+
+	for _, change := range changes {
+		r.record(change)
+		r.logger.Info("Strategy parameter changed",
+			zap.String("id", id),
+			zap.String("strategy", change.Strategy),
+			zap.String("parameter", change.Parameter),
+			zap.Any("old", change.OldValue),
+			zap.Any("new", change.NewValue),
+			zap.String("user", user))
+		if r.OnParamChange != nil {
+			r.OnParamChange(change)
+		}
+	}
+	return changes, nil
+}
+
+// AuditTrail returns the recorded parameter changes, oldest first, of one
+// running strategy, or of all when id is empty.
+func (r *LiveRegistry) AuditTrail(id string) []ParamChange {
+	r.auditMu.Lock()
+	defer r.auditMu.Unlock()
+
+	trail := make([]ParamChange, 0, len(r.audit))
+	for _, change := range r.audit {
+		if id == "" || change.StrategyID == id {
+			trail = append(trail, change)
+		}
+	}
+	return trail
+}
+
+// record adds a change to the audit trail and the audit file.
+func (r *LiveRegistry) record(change ParamChange) {
+	r.auditMu.Lock()
+	defer r.auditMu.Unlock()
+
+	r.audit = append(r.audit, change)
+	if excess := len(r.audit) - r.config.MaxAuditTrail; excess > 0 {
+		r.audit = r.audit[excess:]
+	}
+
+	if r.config.AuditFile == "" {
+		return
+	}
+	if err := appendAudit(r.config.AuditFile, change); err != nil {
+		r.logger.Error("Failed to write parameter audit", zap.Error(err))
+	}
+}
+
+// appendAudit appends a change to a JSONL audit file.
+func appendAudit(path string, change ParamChange) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("failed to encode parameter change: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit file: %w", err)
+	}
+	return nil
+}
+
+// sameSymbol matches "BTC/USDT" and "btcusdt" to the same symbol.
+func sameSymbol(a, b string) bool {
+	normalize := func(s string) string { return strings.ToUpper(strings.ReplaceAll(s, "/", "")) }
+	return normalize(a) == normalize(b)
+}
@@ -0,0 +1,136 @@
+package strategy_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/strategy"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func TestLiveParameterChangesApplyOnNextBarAndAreAudited(t *testing.T) {
+	config := strategy.DefaultLiveRegistryConfig()
+	config.AuditFile = filepath.Join(t.TempDir(), "strategy-params.jsonl")
+	live := strategy.NewLiveRegistry(zap.NewNop(), config, strategy.NewStrategyRegistry(zap.NewNop()))
+
+	var signals []*strategy.Signal
+	var broadcast []strategy.ParamChange
+	live.OnSignal = func(id string, signal *strategy.Signal) { signals = append(signals, signal) }
+	live.OnParamChange = func(change strategy.ParamChange) { broadcast = append(broadcast, change) }
+
+	info, err := live.Start("momentum", "BTCUSDT", map[string]interface{}{"period": 5, "threshold": 0.1})
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	// Prices rise 1% a bar, too slowly for a 10% threshold
+	price := 100.0
+	bar := func() {
+		price *= 1.01
+		live.OnBar("BTC/USDT", types.OHLCV{Close: decimal.NewFromFloat(price)})
+	}
+	for i := 0; i < 6; i++ {
+		bar()
+	}
+	if len(signals) != 0 {
+		t.Fatalf("Expected no signals at a 10%% threshold, got %d", len(signals))
+	}
+
+	// A rejected value leaves every parameter as it was
+	_, err = live.SetParameters(info.ID, map[string]interface{}{"threshold": 0.03, "period": 1000}, "alice", "api")
+	if err == nil {
+		t.Fatal("Expected an out-of-range period to be rejected")
+	}
+	if params := live.AuditTrail(info.ID); len(params) != 0 {
+		t.Errorf("Expected nothing audited for a rejected change, got %+v", params)
+	}
+	got, _ := live.Get(info.ID)
+	if threshold := got.Info().Parameters["threshold"].Current; threshold != 0.1 {
+		t.Errorf("Expected the threshold rolled back to 0.1, got %v", threshold)
+	}
+
+	changes, err := live.SetParameters(info.ID, map[string]interface{}{"threshold": 0.03}, "alice", "api")
+	if err != nil {
+		t.Fatalf("SetParameters failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].OldValue != 0.1 || changes[0].NewValue != 0.03 || changes[0].User != "alice" {
+		t.Errorf("Expected a change from 0.1 to 0.03 by alice, got %+v", changes)
+	}
+	bar()
+	if len(signals) != 1 || signals[0].Side != types.OrderSideBuy || signals[0].Symbol != "BTCUSDT" {
+		t.Fatalf("Expected a buy on the bar after lowering the threshold, got %+v", signals)
+	}
+
+	if len(broadcast) != 1 || len(live.AuditTrail("")) != 1 {
+		t.Errorf("Expected one broadcast and one audited change, got %d and %d", len(broadcast), len(live.AuditTrail("")))
+	}
+	f, err := os.Open(config.AuditFile)
+	if err != nil {
+		t.Fatalf("Expected an audit file: %v", err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	var lines []strategy.ParamChange
+	for scanner.Scan() {
+		var change strategy.ParamChange
+		if err := json.Unmarshal(scanner.Bytes(), &change); err != nil {
+			t.Fatalf("Invalid audit line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, change)
+	}
+	if len(lines) != 1 || lines[0].Parameter != "threshold" || lines[0].StrategyID != info.ID {
+		t.Errorf("Expected the threshold change in the audit file, got %+v", lines)
+	}
+
+	if err := live.Stop(info.ID); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if _, err := live.SetParameters(info.ID, map[string]interface{}{"threshold": 0.05}, "alice", "api"); !errors.Is(err, strategy.ErrLiveStrategyNotFound) {
+		t.Errorf("Expected ErrLiveStrategyNotFound after Stop, got %v", err)
+	}
+}
+
+func TestLiveStrategyWarmupGatesSignals(t *testing.T) {
+	registry := strategy.NewStrategyRegistry(zap.NewNop())
+	registry.Register("eager", func() strategy.Strategy {
+		s := voter("eager", types.OrderSideBuy)
+		s.minBars = 3
+		return s
+	})
+	live := strategy.NewLiveRegistry(zap.NewNop(), strategy.DefaultLiveRegistryConfig(), registry)
+
+	var signals []*strategy.Signal
+	live.OnSignal = func(id string, signal *strategy.Signal) { signals = append(signals, signal) }
+
+	info, err := live.Start("eager", "BTCUSDT", nil)
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if info.Ready || info.MinBars != 3 {
+		t.Errorf("Expected a new strategy to report 3 bars of warmup, got %+v", info)
+	}
+
+	bar := types.OHLCV{Close: decimal.NewFromInt(100)}
+	live.OnBar("BTCUSDT", bar)
+	live.OnBar("BTCUSDT", bar)
+	if len(signals) != 0 {
+		t.Fatalf("Expected signals dropped while warming up, got %d", len(signals))
+	}
+	if got := live.List()[0]; got.Ready || got.Bars != 2 {
+		t.Errorf("Expected 2 bars and not ready, got %+v", got)
+	}
+
+	live.OnBar("BTCUSDT", bar)
+	if len(signals) != 1 {
+		t.Errorf("Expected the signal routed once warm, got %d", len(signals))
+	}
+	if got := live.List()[0]; !got.Ready {
+		t.Errorf("Expected the strategy reported ready, got %+v", got)
+	}
+}
@@ -0,0 +1,432 @@
+// Package strategy provides a cointegration-based pairs trading strategy.
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// MultiSymbolStrategy is a Strategy that trades relationships between
+// symbols. OnBars receives the bars of every symbol for one timestamp, keyed
+// by symbol, and returns a signal per leg to trade.
+type MultiSymbolStrategy interface {
+	Strategy
+	Symbols() []string
+	OnBars(bars map[string]types.OHLCV) ([]*Signal, error)
+}
+
+// adfLags is the number of lagged differences in the ADF regression.
+const adfLags = 1
+
+// PairsStrategy trades the spread between two cointegrated symbols. A rolling
+// OLS of the first symbol's price on the second's gives the hedge ratio, and
+// the residual spread's z-score opens a long or short spread beyond entry_z
+// and closes it once the spread reverts inside exit_z. Entries require an
+// Engle-Granger ADF test to find the spread stationary.
+type PairsStrategy struct {
+	BaseStrategy
+	symbolA     string
+	symbolB     string
+	lookback    int
+	entryZ      float64
+	exitZ       float64
+	adfCritical float64
+	pricesA     []float64
+	pricesB     []float64
+	position    int // 1 long spread (long A, short B), -1 short spread, 0 flat
+}
+
+// PairStats describes the spread between the two legs over the lookback.
+type PairStats struct {
+	HedgeRatio float64 // Units of B per unit of A
+	Intercept  float64
+	ZScore     float64 // Latest spread in standard deviations from its mean
+	ADFStat    float64 // ADF t-statistic of the spread; more negative is more stationary
+}
+
+// NewPairsStrategy creates a pairs strategy trading symbolA against symbolB.
+func NewPairsStrategy(logger *zap.Logger, symbolA, symbolB string) *PairsStrategy {
+	s := &PairsStrategy{
+		BaseStrategy: BaseStrategy{
+			logger: logger,
+			params: make(map[string]StrategyParameter),
+		},
+		symbolA:     symbolA,
+		symbolB:     symbolB,
+		lookback:    60,
+		entryZ:      2.0,
+		exitZ:       0.5,
+		adfCritical: -3.34,
+	}
+
+	s.params["lookback"] = StrategyParameter{
+		Name:        "lookback",
+		Description: "Bars in the rolling hedge ratio regression and z-score",
+		Type:        "int",
+		Default:     60,
+		Min:         20,
+		Max:         500,
+		Current:     60,
+	}
+	s.params["entry_z"] = StrategyParameter{
+		Name:        "entry_z",
+		Description: "Spread z-score beyond which a position is opened",
+		Type:        "float",
+		Default:     2.0,
+		Min:         0.5,
+		Max:         5.0,
+		Current:     2.0,
+	}
+	s.params["exit_z"] = StrategyParameter{
+		Name:        "exit_z",
+		Description: "Spread z-score inside which an open position is closed",
+		Type:        "float",
+		Default:     0.5,
+		Min:         0.0,
+		Max:         3.0,
+		Current:     0.5,
+	}
+	s.params["adf_critical"] = StrategyParameter{
+		Name:        "adf_critical",
+		Description: "ADF statistic the spread must fall below to count as cointegrated (-3.34 is 5% for two series)",
+		Type:        "float",
+		Default:     -3.34,
+		Min:         -5.0,
+		Max:         -2.0,
+		Current:     -3.34,
+	}
+
+	return s
+}
+
+func (s *PairsStrategy) Name() string { return "pairs" }
+func (s *PairsStrategy) Description() string {
+	return "Trades mean reversion of the spread between two cointegrated symbols"
+}
+
+// Symbols returns the two legs, the dependent symbol first.
+func (s *PairsStrategy) Symbols() []string { return []string{s.symbolA, s.symbolB} }
+
+func (s *PairsStrategy) SetParameter(name string, value interface{}) error {
+	// Keep the exit inside the entry so positions can't close as they open
+	if f, ok := toFloat64(value); ok {
+		if (name == "exit_z" && f >= s.entryZ) || (name == "entry_z" && f <= s.exitZ) {
+			return fmt.Errorf("parameter %s would put exit_z at or beyond entry_z", name)
+		}
+	}
+
+	if err := s.BaseStrategy.SetParameter(name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "lookback":
+		s.lookback = s.intParam(name)
+	case "entry_z":
+		s.entryZ, _ = s.params[name].Current.(float64)
+	case "exit_z":
+		s.exitZ, _ = s.params[name].Current.(float64)
+	case "adf_critical":
+		s.adfCritical, _ = s.params[name].Current.(float64)
+	}
+	return nil
+}
+
+func (s *PairsStrategy) Initialize(ctx context.Context) error {
+	s.Reset()
+	return nil
+}
+
+// Reset clears both price buffers and forgets any open position.
+func (s *PairsStrategy) Reset() {
+	s.pricesA = s.pricesA[:0]
+	s.pricesB = s.pricesB[:0]
+	s.position = 0
+}
+
+// OnBar always fails; a pair needs both legs' bars together through OnBars.
+func (s *PairsStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
+	return nil, fmt.Errorf("pairs strategy needs bars for %s and %s together; use OnBars", s.symbolA, s.symbolB)
+}
+
+// OnBars adds one bar per leg and returns a signal for each leg when the
+// spread opens or closes a position. Timestamps missing either leg are
+// skipped.
+func (s *PairsStrategy) OnBars(bars map[string]types.OHLCV) ([]*Signal, error) {
+	barA, okA := bars[s.symbolA]
+	barB, okB := bars[s.symbolB]
+	if !okA || !okB {
+		return nil, nil
+	}
+
+	s.pricesA = appendWindow(s.pricesA, barA.Close.InexactFloat64(), s.lookback)
+	s.pricesB = appendWindow(s.pricesB, barB.Close.InexactFloat64(), s.lookback)
+	if len(s.pricesA) < s.lookback {
+		return nil, nil
+	}
+
+	stats, ok := s.Stats()
+	if !ok {
+		return nil, nil
+	}
+
+	switch {
+	case s.position == 0 && stats.ADFStat < s.adfCritical && stats.ZScore > s.entryZ:
+		// The spread is rich: sell A, buy B
+		s.position = -1
+		return s.legSignals(stats, types.OrderSideSell, "open"), nil
+	case s.position == 0 && stats.ADFStat < s.adfCritical && stats.ZScore < -s.entryZ:
+		s.position = 1
+		return s.legSignals(stats, types.OrderSideBuy, "open"), nil
+	case s.position == 1 && stats.ZScore > -s.exitZ:
+		s.position = 0
+		return s.legSignals(stats, types.OrderSideSell, "close"), nil
+	case s.position == -1 && stats.ZScore < s.exitZ:
+		s.position = 0
+		return s.legSignals(stats, types.OrderSideBuy, "close"), nil
+	}
+
+	return nil, nil
+}
+
+// Stats regresses the buffered prices of A on B and tests the residual
+// spread for stationarity. It reports false until the buffers are full or
+// when either leg's prices are constant.
+func (s *PairsStrategy) Stats() (PairStats, bool) {
+	n := len(s.pricesA)
+	if n < s.lookback || n < adfLags+3 {
+		return PairStats{}, false
+	}
+
+	beta, alpha, ok := regress(s.pricesB, s.pricesA)
+	if !ok {
+		return PairStats{}, false
+	}
+
+	spread := make([]float64, n)
+	for i := range spread {
+		spread[i] = s.pricesA[i] - beta*s.pricesB[i] - alpha
+	}
+
+	mean, std := meanStd(spread)
+	if std == 0 {
+		return PairStats{}, false
+	}
+
+	return PairStats{
+		HedgeRatio: beta,
+		Intercept:  alpha,
+		ZScore:     (spread[n-1] - mean) / std,
+		ADFStat:    adfStatistic(spread, adfLags),
+	}, true
+}
+
+// legSignals returns signals for both legs, trading A on sideA and B on the
+// opposite side.
+func (s *PairsStrategy) legSignals(stats PairStats, sideA types.OrderSide, action string) []*Signal {
+	sideB := types.OrderSideBuy
+	if sideA == types.OrderSideBuy {
+		sideB = types.OrderSideSell
+	}
+
+	strength := decimal.NewFromInt(1)
+	if action == "open" {
+		strength = decimal.NewFromFloat(math.Min(math.Abs(stats.ZScore)/(2*s.entryZ), 1))
+	}
+
+	now := time.Now()
+	signal := func(symbol string, side types.OrderSide, leg string) *Signal {
+		return &Signal{
+			Symbol:   symbol,
+			Side:     side,
+			Strength: strength,
+			Reason:   fmt.Sprintf("Pairs spread %s at z-score %.2f", action, stats.ZScore),
+			Metadata: map[string]interface{}{
+				"pair":        []string{s.symbolA, s.symbolB},
+				"leg":         leg,
+				"pair_action": action,
+				"hedge_ratio": stats.HedgeRatio,
+				"z_score":     stats.ZScore,
+				"adf_stat":    stats.ADFStat,
+			},
+			GeneratedAt: now,
+		}
+	}
+
+	return []*Signal{
+		signal(s.symbolA, sideA, "a"),
+		signal(s.symbolB, sideB, "b"),
+	}
+}
+
+func (s *PairsStrategy) MinBars() int { return s.lookback }
+
+func (s *PairsStrategy) OnTick(tick TickData) (*Signal, error) {
+	return nil, nil
+}
+
+// appendWindow appends v and drops the oldest values beyond size.
+func appendWindow(values []float64, v float64, size int) []float64 {
+	values = append(values, v)
+	if len(values) > size {
+		values = append(values[:0], values[len(values)-size:]...)
+	}
+	return values
+}
+
+// regress fits y = beta*x + alpha by ordinary least squares. It reports
+// false when x is constant.
+func regress(x, y []float64) (beta, alpha float64, ok bool) {
+	meanX, _ := meanStd(x)
+	meanY, _ := meanStd(y)
+
+	var cov, varX float64
+	for i := range x {
+		dx := x[i] - meanX
+		cov += dx * (y[i] - meanY)
+		varX += dx * dx
+	}
+	if varX == 0 {
+		return 0, 0, false
+	}
+
+	beta = cov / varX
+	return beta, meanY - beta*meanX, true
+}
+
+// meanStd returns the mean and population standard deviation of values.
+func meanStd(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var sq float64
+	for _, v := range values {
+		sq += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sq / float64(len(values)))
+}
+
+// adfStatistic returns the augmented Dickey-Fuller t-statistic of series
+// from the regression
+//
+//	Δe[t] = γ·e[t-1] + Σ φ[j]·Δe[t-j] + ε
+//
+// without a constant, as the Engle-Granger test applies it to OLS residuals.
+// It returns 0, which never passes a cointegration gate, when the regression
+// is degenerate.
+func adfStatistic(series []float64, lags int) float64 {
+	k := 1 + lags
+	rows := len(series) - 1 - lags
+	if rows <= k {
+		return 0
+	}
+
+	diff := make([]float64, len(series)-1)
+	for i := range diff {
+		diff[i] = series[i+1] - series[i]
+	}
+
+	// Accumulate the normal equations X'X b = X'y
+	xtx := make([][]float64, k)
+	for i := range xtx {
+		xtx[i] = make([]float64, k)
+	}
+	xty := make([]float64, k)
+	x := make([]float64, k)
+	for t := lags; t < len(diff); t++ {
+		x[0] = series[t]
+		for j := 1; j <= lags; j++ {
+			x[j] = diff[t-j]
+		}
+		for i := 0; i < k; i++ {
+			xty[i] += x[i] * diff[t]
+			for j := 0; j < k; j++ {
+				xtx[i][j] += x[i] * x[j]
+			}
+		}
+	}
+
+	inverse, ok := invert(xtx)
+	if !ok {
+		return 0
+	}
+
+	coef := make([]float64, k)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			coef[i] += inverse[i][j] * xty[j]
+		}
+	}
+
+	var ssr float64
+	for t := lags; t < len(diff); t++ {
+		resid := diff[t] - coef[0]*series[t]
+		for j := 1; j <= lags; j++ {
+			resid -= coef[j] * diff[t-j]
+		}
+		ssr += resid * resid
+	}
+
+	variance := ssr / float64(rows-k) * inverse[0][0]
+	if variance <= 0 {
+		return 0
+	}
+	return coef[0] / math.Sqrt(variance)
+}
+
+// invert inverts a small square matrix by Gauss-Jordan elimination with
+// partial pivoting. It reports false for a singular matrix.
+func invert(m [][]float64) ([][]float64, bool) {
+	n := len(m)
+	a := make([][]float64, n)
+	for i := range m {
+		a[i] = make([]float64, 2*n)
+		copy(a[i], m[i])
+		a[i][n+i] = 1
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+
+		scale := a[col][col]
+		for j := range a[col] {
+			a[col][j] /= scale
+		}
+		for row := 0; row < n; row++ {
+			if row == col {
+				continue
+			}
+			factor := a[row][col]
+			for j := range a[row] {
+				a[row][j] -= factor * a[col][j]
+			}
+		}
+	}
+
+	inverse := make([][]float64, n)
+	for i := range a {
+		inverse[i] = a[i][n:]
+	}
+	return inverse, true
+}
@@ -0,0 +1,125 @@
+package strategy_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/atlas-desktop/trading-backend/internal/strategy"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// feedPair passes matching bars for ETH and BTC to s and returns the signals
+// of the last pair.
+func feedPair(t *testing.T, s *strategy.PairsStrategy, eth, btc float64) []*strategy.Signal {
+	t.Helper()
+	signals, err := s.OnBars(map[string]types.OHLCV{
+		"ETH": {Close: decimal.NewFromFloat(eth)},
+		"BTC": {Close: decimal.NewFromFloat(btc)},
+	})
+	if err != nil {
+		t.Fatalf("OnBars failed: %v", err)
+	}
+	return signals
+}
+
+// cointegrated returns a random-walk BTC series and an ETH series tracking
+// twice it plus mean-reverting noise.
+func cointegrated(n int) ([]float64, []float64) {
+	rng := rand.New(rand.NewSource(1))
+	eth := make([]float64, n)
+	btc := make([]float64, n)
+	price, noise := 100.0, 0.0
+	for i := 0; i < n; i++ {
+		price += 3 * rng.NormFloat64()
+		noise = 0.3*noise + rng.NormFloat64()
+		btc[i] = price
+		eth[i] = 2*price + 10 + noise
+	}
+	return eth, btc
+}
+
+func TestPairsTradesSpreadReversion(t *testing.T) {
+	s := strategy.NewPairsStrategy(zap.NewNop(), "ETH", "BTC")
+	eth, btc := cointegrated(60)
+	for i := range eth {
+		if signals := feedPair(t, s, eth[i], btc[i]); signals != nil {
+			t.Fatalf("Unexpected signal at bar %d: %s", i, signals[0].Reason)
+		}
+	}
+
+	stats, ok := s.Stats()
+	if !ok || stats.ADFStat >= -3.34 {
+		t.Fatalf("Expected a cointegrated spread, got %+v", stats)
+	}
+	if stats.HedgeRatio < 1.9 || stats.HedgeRatio > 2.1 {
+		t.Errorf("Expected a hedge ratio near 2, got %g", stats.HedgeRatio)
+	}
+
+	// ETH jumps well above its fair value against BTC
+	last := btc[len(btc)-1]
+	open := feedPair(t, s, 2*last+10+8, last)
+	if len(open) != 2 {
+		t.Fatalf("Expected paired entry signals, got %v", open)
+	}
+	if open[0].Symbol != "ETH" || open[0].Side != types.OrderSideSell ||
+		open[1].Symbol != "BTC" || open[1].Side != types.OrderSideBuy {
+		t.Errorf("Expected sell ETH / buy BTC, got %s %s / %s %s",
+			open[0].Side, open[0].Symbol, open[1].Side, open[1].Symbol)
+	}
+	if open[0].Metadata["pair_action"] != "open" {
+		t.Errorf("Expected an open action, got %v", open[0].Metadata["pair_action"])
+	}
+
+	// Back at fair value the spread closes
+	exit := feedPair(t, s, 2*last+10, last)
+	if len(exit) != 2 || exit[0].Side != types.OrderSideBuy || exit[1].Side != types.OrderSideSell {
+		t.Fatalf("Expected paired exit signals, got %v", exit)
+	}
+	if exit[0].Metadata["pair_action"] != "close" {
+		t.Errorf("Expected a close action, got %v", exit[0].Metadata["pair_action"])
+	}
+}
+
+func TestPairsRequiresCointegration(t *testing.T) {
+	s := strategy.NewPairsStrategy(zap.NewNop(), "ETH", "BTC")
+
+	// Two independent random walks
+	rng := rand.New(rand.NewSource(9))
+	eth, btc := 200.0, 100.0
+	for i := 0; i < 60; i++ {
+		eth += 2 * rng.NormFloat64()
+		btc += rng.NormFloat64()
+		feedPair(t, s, eth, btc)
+	}
+
+	stats, ok := s.Stats()
+	if !ok || stats.ADFStat < -3.34 {
+		t.Fatalf("Expected independent walks to fail the ADF gate, got %+v", stats)
+	}
+	if signals := feedPair(t, s, eth+100, btc); signals != nil {
+		t.Errorf("Expected no entry without cointegration, got %s", signals[0].Reason)
+	}
+}
+
+func TestPairsSkipsIncompleteBarsAndSingleSymbolFeeds(t *testing.T) {
+	s := strategy.NewPairsStrategy(zap.NewNop(), "ETH", "BTC")
+
+	signals, err := s.OnBars(map[string]types.OHLCV{"ETH": {Close: decimal.NewFromInt(200)}})
+	if err != nil || signals != nil {
+		t.Errorf("Expected a bar missing a leg to be skipped, got %v, %v", signals, err)
+	}
+	if _, err := s.OnBar(types.OHLCV{Close: decimal.NewFromInt(200)}); err == nil {
+		t.Error("Expected OnBar to fail for a pairs strategy")
+	}
+
+	if err := s.SetParameter("exit_z", 2.5); err == nil {
+		t.Error("Expected exit_z beyond entry_z to be rejected")
+	}
+	if err := s.SetParameter("lookback", 30); err != nil || s.MinBars() != 30 {
+		t.Errorf("Expected lookback 30, got %d (%v)", s.MinBars(), err)
+	}
+
+	var _ strategy.MultiSymbolStrategy = s
+}
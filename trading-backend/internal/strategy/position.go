@@ -0,0 +1,73 @@
+package strategy
+
+import (
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+)
+
+// PositionState is the position a strategy's signals have put it in.
+type PositionState string
+
+// Position states
+const (
+	PositionFlat  PositionState = "flat"
+	PositionLong  PositionState = "long"
+	PositionShort PositionState = "short"
+)
+
+// initPositionTracking registers the allow_pyramiding parameter. Strategies
+// that add to positions by design, like grid and DCA, allow it by default.
+func (s *BaseStrategy) initPositionTracking(allowPyramiding bool) {
+	s.AllowPyramiding = allowPyramiding
+	s.params["allow_pyramiding"] = StrategyParameter{
+		Name:        "allow_pyramiding",
+		Description: "Emit every signal instead of only those changing the intended position",
+		Type:        "bool",
+		Default:     allowPyramiding,
+		Current:     allowPyramiding,
+	}
+}
+
+// Position returns the position the strategy's signals have put it in.
+func (s *BaseStrategy) Position() PositionState {
+	if s.position == "" {
+		return PositionFlat
+	}
+	return s.position
+}
+
+// SetPosition overrides the tracked position, so a strategy whose position
+// was closed by a stop or by hand can signal an entry in the same direction
+// again.
+func (s *BaseStrategy) SetPosition(state PositionState) {
+	s.position = state
+}
+
+// transition moves the tracked position to the signal's side, returning nil
+// for a signal in the direction the strategy is already positioned unless
+// AllowPyramiding is set. Buys take the strategy long and sells short, from
+// whichever state it was in.
+func (s *BaseStrategy) transition(signal *Signal) *Signal {
+	if signal == nil {
+		return nil
+	}
+
+	next := PositionLong
+	if signal.Side == types.OrderSideSell {
+		next = PositionShort
+	}
+	if next == s.Position() && !s.AllowPyramiding {
+		return nil
+	}
+
+	s.position = next
+	return signal
+}
+
+// setAllowPyramiding sets AllowPyramiding and the parameter reporting it.
+func (s *BaseStrategy) setAllowPyramiding(allow bool) {
+	s.AllowPyramiding = allow
+	if param, ok := s.params["allow_pyramiding"]; ok {
+		param.Current = allow
+		s.params["allow_pyramiding"] = param
+	}
+}
@@ -0,0 +1,42 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestSqrtDecimalAccuracy(t *testing.T) {
+	values := []float64{
+		1e-8, 3.7e-7, 2e-5, 0.0001, 0.042, 0.5, 1, 2, 9.87, 144,
+		12345.678, 1e6, 7.3e8, 1e10, 4.2e11, 1e12,
+	}
+
+	for _, v := range values {
+		got, _ := sqrtDecimal(decimal.NewFromFloat(v)).Float64()
+		want := math.Sqrt(v)
+
+		relErr := math.Abs(got-want) / want
+		if relErr > 1e-10 {
+			t.Errorf("sqrtDecimal(%g) = %.17g, want %.17g (relative error %g)", v, got, want, relErr)
+		}
+	}
+}
+
+func TestSqrtDecimalNonPositive(t *testing.T) {
+	if !sqrtDecimal(decimal.Zero).IsZero() {
+		t.Error("Expected sqrt of zero to be zero")
+	}
+	if !sqrtDecimal(decimal.NewFromInt(-4)).IsZero() {
+		t.Error("Expected sqrt of a negative value to be zero")
+	}
+}
+
+func TestSqrtDecimalWithEpsilonTerminates(t *testing.T) {
+	// A loose epsilon should still produce a usable root
+	got, _ := sqrtDecimalWithEpsilon(decimal.NewFromInt(2), decimal.New(1, -4)).Float64()
+	if math.Abs(got-math.Sqrt2) > 1e-4 {
+		t.Errorf("Expected ~%g, got %g", math.Sqrt2, got)
+	}
+}
@@ -3,6 +3,9 @@ package strategy
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"math"
 	"sync"
 	"time"
 
@@ -20,6 +23,9 @@ type Strategy interface {
 	Initialize(ctx context.Context) error
 	OnBar(bar types.OHLCV) (*Signal, error)
 	OnTick(tick TickData) (*Signal, error)
+	// MinBars returns the number of bars the strategy needs before it can
+	// emit signals.
+	MinBars() int
 	Reset()
 }
 
@@ -81,6 +87,21 @@ func NewStrategyRegistry(logger *zap.Logger) *StrategyRegistry {
 	r.Register("vwap_reversion", func() Strategy { return NewVWAPReversionStrategy(logger) })
 	r.Register("grid", func() Strategy { return NewGridStrategy(logger) })
 	r.Register("dca", func() Strategy { return NewDCAStrategy(logger) })
+	r.Register("ichimoku", func() Strategy { return NewIchimokuStrategy(logger) })
+	r.Register("ob_imbalance", func() Strategy { return NewOrderBookImbalanceStrategy(logger) })
+	r.Register("supertrend", func() Strategy { return NewSuperTrendStrategy(logger) })
+	r.Register("keltner", func() Strategy { return NewKeltnerChannelStrategy(logger) })
+	r.Register("ensemble", func() Strategy {
+		ensemble, err := r.CreateEnsemble(
+			[]string{"momentum", "trend_following", "mean_reversion"},
+			[]float64{1, 1, 1},
+		)
+		if err != nil {
+			logger.Error("Failed to create default ensemble", zap.Error(err))
+			return NewEnsembleStrategy(logger, nil)
+		}
+		return ensemble
+	})
 	
 	return r
 }
@@ -95,16 +116,45 @@ func (r *StrategyRegistry) Register(name string, factory func() Strategy) {
 // Create creates a new strategy instance by name.
 func (r *StrategyRegistry) Create(name string) (Strategy, bool) {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
-	
 	factory, ok := r.strategies[name]
+	r.mu.RUnlock()
+	
 	if !ok {
 		return nil, false
 	}
 	
+	// Called without the lock held so factories may create other strategies
 	return factory(), true
 }
 
+// CreateEnsemble creates an ensemble over the named strategies with the given
+// vote weights.
+func (r *StrategyRegistry) CreateEnsemble(names []string, weights []float64) (*EnsembleStrategy, error) {
+	if len(names) != len(weights) {
+		return nil, fmt.Errorf("ensemble has %d strategies but %d weights", len(names), len(weights))
+	}
+	
+	members := make([]EnsembleMember, 0, len(names))
+	for i, name := range names {
+		if name == "ensemble" {
+			return nil, fmt.Errorf("ensemble cannot contain another ensemble")
+		}
+		if weights[i] <= 0 {
+			return nil, fmt.Errorf("ensemble weight for %s must be positive", name)
+		}
+		sub, ok := r.Create(name)
+		if !ok {
+			return nil, fmt.Errorf("strategy not registered: %s", name)
+		}
+		members = append(members, EnsembleMember{
+			Strategy: sub,
+			Weight:   decimal.NewFromFloat(weights[i]),
+		})
+	}
+	
+	return NewEnsembleStrategy(r.logger, members), nil
+}
+
 // List returns all available strategy names.
 func (r *StrategyRegistry) List() []string {
 	r.mu.RLock()
@@ -119,37 +169,271 @@ func (r *StrategyRegistry) List() []string {
 
 // BaseStrategy provides common functionality.
 type BaseStrategy struct {
-	logger     *zap.Logger
-	params     map[string]StrategyParameter
-	bars       []types.OHLCV
-	maxBars    int
+	logger      *zap.Logger
+	params      map[string]StrategyParameter
+	bars        []types.OHLCV
+	maxBars     int
+	atrPeriod   int
+	atrStopMult decimal.Decimal
+	atrTPMult   decimal.Decimal
+	
+	// UseHeikinAshi buffers Heikin-Ashi candles in place of the bars passed
+	// to OnBar, so indicators are calculated on the smoothed series.
+	UseHeikinAshi bool
+	
+	// AllowPyramiding emits repeated signals in the direction the strategy
+	// is already positioned instead of suppressing them.
+	AllowPyramiding bool
+	position        PositionState
+}
+
+// SetParameter validates a parameter value against its declared type and
+// bounds and stores it. Numeric values are coerced to the parameter type, so
+// float64 values decoded from JSON can be applied to "int" parameters.
+func (s *BaseStrategy) SetParameter(name string, value interface{}) error {
+	param, ok := s.params[name]
+	if !ok {
+		return fmt.Errorf("unknown parameter: %s", name)
+	}
+	
+	coerced, err := coerceParameter(param, value)
+	if err != nil {
+		return err
+	}
+	
+	param.Current = coerced
+	s.params[name] = param
+	
+	switch name {
+	case "atr_period":
+		s.atrPeriod = s.intParam(name)
+	case "atr_stop_mult":
+		s.atrStopMult = s.decimalParam(name)
+	case "atr_tp_mult":
+		s.atrTPMult = s.decimalParam(name)
+	case "heikin_ashi":
+		// Real bars and Heikin-Ashi candles can't share the buffer
+		use, _ := s.params[name].Current.(bool)
+		if use != s.UseHeikinAshi {
+			s.bars = s.bars[:0]
+		}
+		s.UseHeikinAshi = use
+	case "allow_pyramiding":
+		s.AllowPyramiding, _ = s.params[name].Current.(bool)
+	}
+	return nil
 }
 
-// SetParameter sets a parameter value.
-func (s *BaseStrategy) SetParameter(name string, value interface{}) error {
-	if param, ok := s.params[name]; ok {
-		param.Current = value
-		s.params[name] = param
+// intParam returns the current value of an "int" parameter.
+func (s *BaseStrategy) intParam(name string) int {
+	v, _ := s.params[name].Current.(int)
+	return v
+}
+
+// decimalParam returns the current value of a "float" parameter.
+func (s *BaseStrategy) decimalParam(name string) decimal.Decimal {
+	v, _ := s.params[name].Current.(float64)
+	return decimal.NewFromFloat(v)
+}
+
+// coerceParameter converts value to the type declared by param and checks it
+// against the parameter's Min/Max bounds.
+func coerceParameter(param StrategyParameter, value interface{}) (interface{}, error) {
+	switch param.Type {
+	case "int":
+		f, ok := toFloat64(value)
+		if !ok {
+			return nil, fmt.Errorf("parameter %s expects int, got %T", param.Name, value)
+		}
+		if f != math.Trunc(f) {
+			return nil, fmt.Errorf("parameter %s expects int, got non-integral value %v", param.Name, value)
+		}
+		if err := checkParameterBounds(param, f); err != nil {
+			return nil, err
+		}
+		return int(f), nil
+	case "float":
+		f, ok := toFloat64(value)
+		if !ok {
+			return nil, fmt.Errorf("parameter %s expects float, got %T", param.Name, value)
+		}
+		if err := checkParameterBounds(param, f); err != nil {
+			return nil, err
+		}
+		return f, nil
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("parameter %s expects bool, got %T", param.Name, value)
+		}
+		return b, nil
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("parameter %s expects string, got %T", param.Name, value)
+		}
+		return str, nil
+	default:
+		return nil, fmt.Errorf("parameter %s has unsupported type %q", param.Name, param.Type)
+	}
+}
+
+// checkParameterBounds rejects values outside the parameter's Min/Max.
+func checkParameterBounds(param StrategyParameter, f float64) error {
+	if param.Min != nil {
+		if min, ok := toFloat64(param.Min); ok && f < min {
+			return fmt.Errorf("parameter %s value %v is below minimum %v", param.Name, f, param.Min)
+		}
+	}
+	if param.Max != nil {
+		if max, ok := toFloat64(param.Max); ok && f > max {
+			return fmt.Errorf("parameter %s value %v is above maximum %v", param.Name, f, param.Max)
+		}
 	}
 	return nil
 }
 
+// toFloat64 converts the numeric types produced by Go code and JSON decoding to float64.
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case decimal.Decimal:
+		f, _ := v.Float64()
+		return f, true
+	default:
+		return 0, false
+	}
+}
+
 // Parameters returns strategy parameters.
 func (s *BaseStrategy) Parameters() map[string]StrategyParameter {
 	return s.params
 }
 
-// AddBar adds a bar to the buffer.
-func (s *BaseStrategy) AddBar(bar types.OHLCV) {
+// AddBar adds a bar to the buffer, returning the bar indicators should be
+// calculated from: its Heikin-Ashi candle when UseHeikinAshi is set. Entries
+// and stops should still be keyed off the real bar's close.
+func (s *BaseStrategy) AddBar(bar types.OHLCV) types.OHLCV {
+	if s.UseHeikinAshi {
+		var prev *types.OHLCV
+		if len(s.bars) > 0 {
+			prev = &s.bars[len(s.bars)-1]
+		}
+		bar = nextHeikinAshi(prev, bar)
+	}
+	
 	s.bars = append(s.bars, bar)
 	if len(s.bars) > s.maxBars {
 		s.bars = s.bars[1:]
 	}
+	return bar
 }
 
 // Reset resets the strategy state.
 func (s *BaseStrategy) Reset() {
 	s.bars = s.bars[:0]
+	s.position = PositionFlat
+}
+
+// initATR enables ATR-based stop loss and take profit levels and registers
+// their parameters.
+func (s *BaseStrategy) initATR() {
+	s.atrPeriod = 14
+	s.atrStopMult = decimal.NewFromFloat(2.0)
+	s.atrTPMult = decimal.NewFromFloat(3.0)
+	
+	s.params["atr_period"] = StrategyParameter{
+		Name:        "atr_period",
+		Description: "Average True Range period for stop loss and take profit",
+		Type:        "int",
+		Default:     14,
+		Min:         2,
+		Max:         50,
+		Current:     14,
+	}
+	s.params["atr_stop_mult"] = StrategyParameter{
+		Name:        "atr_stop_mult",
+		Description: "ATR multiple between entry and stop loss",
+		Type:        "float",
+		Default:     2.0,
+		Min:         0.5,
+		Max:         10.0,
+		Current:     2.0,
+	}
+	s.params["atr_tp_mult"] = StrategyParameter{
+		Name:        "atr_tp_mult",
+		Description: "ATR multiple between entry and take profit",
+		Type:        "float",
+		Default:     3.0,
+		Min:         0.5,
+		Max:         20.0,
+		Current:     3.0,
+	}
+}
+
+// initHeikinAshi registers the heikin_ashi parameter, letting each instance
+// choose whether to calculate on Heikin-Ashi candles.
+func (s *BaseStrategy) initHeikinAshi() {
+	s.params["heikin_ashi"] = StrategyParameter{
+		Name:        "heikin_ashi",
+		Description: "Calculate indicators on Heikin-Ashi candles to smooth out whipsaws",
+		Type:        "bool",
+		Default:     false,
+		Current:     false,
+	}
+}
+
+// ATR returns the Average True Range over the last period bars, or zero if the
+// buffer does not hold period+1 bars yet.
+func (s *BaseStrategy) ATR(period int) decimal.Decimal {
+	return ATR(s.bars, period)
+}
+
+// ATR returns the Average True Range over the last period of bars, or zero if
+// fewer than period+1 bars are given.
+func ATR(bars []types.OHLCV, period int) decimal.Decimal {
+	if period <= 0 || len(bars) < period+1 {
+		return decimal.Zero
+	}
+	
+	sum := decimal.Zero
+	for i := len(bars) - period; i < len(bars); i++ {
+		bar := bars[i]
+		prevClose := bars[i-1].Close
+		tr := bar.High.Sub(bar.Low)
+		tr = decimal.Max(tr, bar.High.Sub(prevClose).Abs())
+		tr = decimal.Max(tr, bar.Low.Sub(prevClose).Abs())
+		sum = sum.Add(tr)
+	}
+	return sum.Div(decimal.NewFromInt(int64(period)))
+}
+
+// atrStops returns stop loss and take profit levels placed ATR multiples away
+// from entry. The fallbacks are returned until enough bars exist to compute ATR.
+func (s *BaseStrategy) atrStops(side types.OrderSide, entry, fallbackStop, fallbackTarget decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	atr := s.ATR(s.atrPeriod)
+	if atr.IsZero() {
+		return fallbackStop, fallbackTarget
+	}
+	
+	stopDist := atr.Mul(s.atrStopMult)
+	targetDist := atr.Mul(s.atrTPMult)
+	if side == types.OrderSideBuy {
+		return entry.Sub(stopDist), entry.Add(targetDist)
+	}
+	return entry.Add(stopDist), entry.Sub(targetDist)
 }
 
 // MomentumStrategy implements momentum-based trading.
@@ -190,6 +474,10 @@ func NewMomentumStrategy(logger *zap.Logger) *MomentumStrategy {
 		Current:     0.02,
 	}
 	
+	s.initATR()
+	s.initHeikinAshi()
+	s.initPositionTracking(false)
+	
 	return s
 }
 
@@ -198,6 +486,19 @@ func (s *MomentumStrategy) Description() string {
 	return "Trades based on price momentum over a lookback period"
 }
 
+func (s *MomentumStrategy) SetParameter(name string, value interface{}) error {
+	if err := s.BaseStrategy.SetParameter(name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "period":
+		s.period = s.intParam(name)
+	case "threshold":
+		s.threshold = s.decimalParam(name)
+	}
+	return nil
+}
+
 func (s *MomentumStrategy) Initialize(ctx context.Context) error {
 	s.bars = make([]types.OHLCV, 0, s.maxBars)
 	return nil
@@ -219,33 +520,40 @@ func (s *MomentumStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
 	}
 	
 	momentum := current.Sub(past).Div(past)
+	price := bar.Close
 	
 	// Generate signal if momentum exceeds threshold
 	if momentum.GreaterThan(s.threshold) {
-		return &Signal{
+		stop, target := s.atrStops(types.OrderSideBuy, price,
+			price.Mul(decimal.NewFromFloat(0.95)), price.Mul(decimal.NewFromFloat(1.05)))
+		return s.transition(&Signal{
 			Symbol:      bar.Symbol,
 			Side:        types.OrderSideBuy,
 			Strength:    momentum.Div(s.threshold).Min(decimal.NewFromInt(1)),
-			StopLoss:    current.Mul(decimal.NewFromFloat(0.95)),
-			TakeProfit:  current.Mul(decimal.NewFromFloat(1.05)),
+			StopLoss:    stop,
+			TakeProfit:  target,
 			Reason:      "Strong positive momentum",
 			GeneratedAt: time.Now(),
-		}, nil
+		}), nil
 	} else if momentum.LessThan(s.threshold.Neg()) {
-		return &Signal{
+		stop, target := s.atrStops(types.OrderSideSell, price,
+			price.Mul(decimal.NewFromFloat(1.05)), price.Mul(decimal.NewFromFloat(0.95)))
+		return s.transition(&Signal{
 			Symbol:      bar.Symbol,
 			Side:        types.OrderSideSell,
 			Strength:    momentum.Abs().Div(s.threshold).Min(decimal.NewFromInt(1)),
-			StopLoss:    current.Mul(decimal.NewFromFloat(1.05)),
-			TakeProfit:  current.Mul(decimal.NewFromFloat(0.95)),
+			StopLoss:    stop,
+			TakeProfit:  target,
 			Reason:      "Strong negative momentum",
 			GeneratedAt: time.Now(),
-		}, nil
+		}), nil
 	}
 	
 	return nil, nil
 }
 
+func (s *MomentumStrategy) MinBars() int { return s.period }
+
 func (s *MomentumStrategy) OnTick(tick TickData) (*Signal, error) {
 	return nil, nil
 }
@@ -291,6 +599,9 @@ func NewMeanReversionStrategy(logger *zap.Logger) *MeanReversionStrategy {
 		Current:     2.0,
 	}
 	
+	s.initATR()
+	s.initPositionTracking(false)
+	
 	return s
 }
 
@@ -299,6 +610,19 @@ func (s *MeanReversionStrategy) Description() string {
 	return "Trades when price deviates from moving average by multiple standard deviations"
 }
 
+func (s *MeanReversionStrategy) SetParameter(name string, value interface{}) error {
+	if err := s.BaseStrategy.SetParameter(name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "period":
+		s.period = s.intParam(name)
+	case "std_dev_mult":
+		s.stdDevMult = s.decimalParam(name)
+	}
+	return nil
+}
+
 func (s *MeanReversionStrategy) Initialize(ctx context.Context) error {
 	s.bars = make([]types.OHLCV, 0, s.maxBars)
 	s.ema = decimal.Zero
@@ -339,34 +663,38 @@ func (s *MeanReversionStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
 	if current.LessThan(lowerBand) {
 		// Price below lower band - buy for mean reversion
 		deviation := lowerBand.Sub(current).Div(stdDev)
-		return &Signal{
+		stop, _ := s.atrStops(types.OrderSideBuy, current, current.Mul(decimal.NewFromFloat(0.97)), sma)
+		return s.transition(&Signal{
 			Symbol:      bar.Symbol,
 			Side:        types.OrderSideBuy,
 			Strength:    deviation.Div(s.stdDevMult).Min(decimal.NewFromInt(1)),
-			StopLoss:    current.Mul(decimal.NewFromFloat(0.97)),
+			StopLoss:    stop,
 			TakeProfit:  sma,
 			Reason:      "Price below lower Bollinger Band",
 			Metadata:    map[string]interface{}{"sma": sma, "stdDev": stdDev},
 			GeneratedAt: time.Now(),
-		}, nil
+		}), nil
 	} else if current.GreaterThan(upperBand) {
 		// Price above upper band - sell for mean reversion
 		deviation := current.Sub(upperBand).Div(stdDev)
-		return &Signal{
+		stop, _ := s.atrStops(types.OrderSideSell, current, current.Mul(decimal.NewFromFloat(1.03)), sma)
+		return s.transition(&Signal{
 			Symbol:      bar.Symbol,
 			Side:        types.OrderSideSell,
 			Strength:    deviation.Div(s.stdDevMult).Min(decimal.NewFromInt(1)),
-			StopLoss:    current.Mul(decimal.NewFromFloat(1.03)),
+			StopLoss:    stop,
 			TakeProfit:  sma,
 			Reason:      "Price above upper Bollinger Band",
 			Metadata:    map[string]interface{}{"sma": sma, "stdDev": stdDev},
 			GeneratedAt: time.Now(),
-		}, nil
+		}), nil
 	}
 	
 	return nil, nil
 }
 
+func (s *MeanReversionStrategy) MinBars() int { return s.period }
+
 func (s *MeanReversionStrategy) OnTick(tick TickData) (*Signal, error) {
 	return nil, nil
 }
@@ -409,6 +737,9 @@ func NewBreakoutStrategy(logger *zap.Logger) *BreakoutStrategy {
 		Current:     1.5,
 	}
 	
+	s.initATR()
+	s.initPositionTracking(false)
+	
 	return s
 }
 
@@ -417,6 +748,19 @@ func (s *BreakoutStrategy) Description() string {
 	return "Trades breakouts from consolidation ranges with volume confirmation"
 }
 
+func (s *BreakoutStrategy) SetParameter(name string, value interface{}) error {
+	if err := s.BaseStrategy.SetParameter(name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "lookback":
+		s.lookback = s.intParam(name)
+	case "min_volume_mult":
+		s.minVolMult = s.decimalParam(name)
+	}
+	return nil
+}
+
 func (s *BreakoutStrategy) Initialize(ctx context.Context) error {
 	s.bars = make([]types.OHLCV, 0, s.maxBars)
 	return nil
@@ -454,34 +798,40 @@ func (s *BreakoutStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
 	if current.GreaterThan(highest) && hasVolumeConfirm {
 		// Bullish breakout
 		rangeSize := highest.Sub(lowest)
-		return &Signal{
+		stop, target := s.atrStops(types.OrderSideBuy, current,
+			highest.Sub(rangeSize.Mul(decimal.NewFromFloat(0.5))), current.Add(rangeSize))
+		return s.transition(&Signal{
 			Symbol:      bar.Symbol,
 			Side:        types.OrderSideBuy,
 			Strength:    decimal.NewFromFloat(0.8),
-			StopLoss:    highest.Sub(rangeSize.Mul(decimal.NewFromFloat(0.5))),
-			TakeProfit:  current.Add(rangeSize),
+			StopLoss:    stop,
+			TakeProfit:  target,
 			Reason:      "Bullish breakout with volume",
 			Metadata:    map[string]interface{}{"highest": highest, "volume_mult": currentVol.Div(avgVolume)},
 			GeneratedAt: time.Now(),
-		}, nil
+		}), nil
 	} else if current.LessThan(lowest) && hasVolumeConfirm {
 		// Bearish breakout
 		rangeSize := highest.Sub(lowest)
-		return &Signal{
+		stop, target := s.atrStops(types.OrderSideSell, current,
+			lowest.Add(rangeSize.Mul(decimal.NewFromFloat(0.5))), current.Sub(rangeSize))
+		return s.transition(&Signal{
 			Symbol:      bar.Symbol,
 			Side:        types.OrderSideSell,
 			Strength:    decimal.NewFromFloat(0.8),
-			StopLoss:    lowest.Add(rangeSize.Mul(decimal.NewFromFloat(0.5))),
-			TakeProfit:  current.Sub(rangeSize),
+			StopLoss:    stop,
+			TakeProfit:  target,
 			Reason:      "Bearish breakout with volume",
 			Metadata:    map[string]interface{}{"lowest": lowest, "volume_mult": currentVol.Div(avgVolume)},
 			GeneratedAt: time.Now(),
-		}, nil
+		}), nil
 	}
 	
 	return nil, nil
 }
 
+func (s *BreakoutStrategy) MinBars() int { return s.lookback + 1 }
+
 func (s *BreakoutStrategy) OnTick(tick TickData) (*Signal, error) {
 	return nil, nil
 }
@@ -526,6 +876,10 @@ func NewTrendFollowingStrategy(logger *zap.Logger) *TrendFollowingStrategy {
 		Current:     26,
 	}
 	
+	s.initATR()
+	s.initHeikinAshi()
+	s.initPositionTracking(false)
+	
 	return s
 }
 
@@ -534,6 +888,19 @@ func (s *TrendFollowingStrategy) Description() string {
 	return "Follows trends using EMA crossovers"
 }
 
+func (s *TrendFollowingStrategy) SetParameter(name string, value interface{}) error {
+	if err := s.BaseStrategy.SetParameter(name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "fast_period":
+		s.fastPeriod = s.intParam(name)
+	case "slow_period":
+		s.slowPeriod = s.intParam(name)
+	}
+	return nil
+}
+
 func (s *TrendFollowingStrategy) Initialize(ctx context.Context) error {
 	s.bars = make([]types.OHLCV, 0, s.maxBars)
 	s.fastEMA = decimal.Zero
@@ -542,14 +909,14 @@ func (s *TrendFollowingStrategy) Initialize(ctx context.Context) error {
 }
 
 func (s *TrendFollowingStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
-	s.AddBar(bar)
+	smoothed := s.AddBar(bar).Close
 	
 	price := bar.Close
 	
 	// Update EMAs
 	if s.fastEMA.IsZero() {
-		s.fastEMA = price
-		s.slowEMA = price
+		s.fastEMA = smoothed
+		s.slowEMA = smoothed
 		return nil, nil
 	}
 	
@@ -559,8 +926,8 @@ func (s *TrendFollowingStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
 	prevFastEMA := s.fastEMA
 	prevSlowEMA := s.slowEMA
 	
-	s.fastEMA = price.Mul(fastMult).Add(s.fastEMA.Mul(decimal.NewFromInt(1).Sub(fastMult)))
-	s.slowEMA = price.Mul(slowMult).Add(s.slowEMA.Mul(decimal.NewFromInt(1).Sub(slowMult)))
+	s.fastEMA = smoothed.Mul(fastMult).Add(s.fastEMA.Mul(decimal.NewFromInt(1).Sub(fastMult)))
+	s.slowEMA = smoothed.Mul(slowMult).Add(s.slowEMA.Mul(decimal.NewFromInt(1).Sub(slowMult)))
 	
 	if len(s.bars) < s.slowPeriod {
 		return nil, nil
@@ -572,33 +939,39 @@ func (s *TrendFollowingStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
 	
 	if !wasBullish && isBullish {
 		// Bullish crossover
-		return &Signal{
+		stop, target := s.atrStops(types.OrderSideBuy, price,
+			s.slowEMA.Mul(decimal.NewFromFloat(0.97)), price.Mul(decimal.NewFromFloat(1.06)))
+		return s.transition(&Signal{
 			Symbol:      bar.Symbol,
 			Side:        types.OrderSideBuy,
 			Strength:    decimal.NewFromFloat(0.7),
-			StopLoss:    s.slowEMA.Mul(decimal.NewFromFloat(0.97)),
-			TakeProfit:  price.Mul(decimal.NewFromFloat(1.06)),
+			StopLoss:    stop,
+			TakeProfit:  target,
 			Reason:      "Bullish EMA crossover",
 			Metadata:    map[string]interface{}{"fast_ema": s.fastEMA, "slow_ema": s.slowEMA},
 			GeneratedAt: time.Now(),
-		}, nil
+		}), nil
 	} else if wasBullish && !isBullish {
 		// Bearish crossover
-		return &Signal{
+		stop, target := s.atrStops(types.OrderSideSell, price,
+			s.slowEMA.Mul(decimal.NewFromFloat(1.03)), price.Mul(decimal.NewFromFloat(0.94)))
+		return s.transition(&Signal{
 			Symbol:      bar.Symbol,
 			Side:        types.OrderSideSell,
 			Strength:    decimal.NewFromFloat(0.7),
-			StopLoss:    s.slowEMA.Mul(decimal.NewFromFloat(1.03)),
-			TakeProfit:  price.Mul(decimal.NewFromFloat(0.94)),
+			StopLoss:    stop,
+			TakeProfit:  target,
 			Reason:      "Bearish EMA crossover",
 			Metadata:    map[string]interface{}{"fast_ema": s.fastEMA, "slow_ema": s.slowEMA},
 			GeneratedAt: time.Now(),
-		}, nil
+		}), nil
 	}
 	
 	return nil, nil
 }
 
+func (s *TrendFollowingStrategy) MinBars() int { return s.slowPeriod }
+
 func (s *TrendFollowingStrategy) OnTick(tick TickData) (*Signal, error) {
 	return nil, nil
 }
@@ -620,7 +993,7 @@ type RSIDivergenceStrategy struct {
 
 // NewRSIDivergenceStrategy creates a new RSI divergence strategy.
 func NewRSIDivergenceStrategy(logger *zap.Logger) *RSIDivergenceStrategy {
-	return &RSIDivergenceStrategy{
+	s := &RSIDivergenceStrategy{
 		BaseStrategy: BaseStrategy{
 			logger:  logger,
 			params:  make(map[string]StrategyParameter),
@@ -632,6 +1005,11 @@ func NewRSIDivergenceStrategy(logger *zap.Logger) *RSIDivergenceStrategy {
 		rsiValues:  make([]decimal.Decimal, 0, 50),
 		priceValues: make([]decimal.Decimal, 0, 50),
 	}
+	
+	s.initATR()
+	s.initPositionTracking(false)
+	
+	return s
 }
 
 func (s *RSIDivergenceStrategy) Name() string { return "rsi_divergence" }
@@ -716,7 +1094,7 @@ func (s *RSIDivergenceStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
 	
 	// Check for divergence
 	if signal := s.checkDivergence(bar.Symbol); signal != nil {
-		return signal, nil
+		return s.transition(signal), nil
 	}
 	
 	return nil, nil
@@ -737,12 +1115,14 @@ func (s *RSIDivergenceStrategy) checkDivergence(symbol string) *Signal {
 		for i := 0; i < n-3; i++ {
 			if s.priceValues[i].GreaterThan(currentPrice) && s.rsiValues[i].LessThan(currentRSI) {
 				// Bullish divergence
+				stop, target := s.atrStops(types.OrderSideBuy, currentPrice,
+					currentPrice.Mul(decimal.NewFromFloat(0.96)), currentPrice.Mul(decimal.NewFromFloat(1.08)))
 				return &Signal{
 					Symbol:      symbol,
 					Side:        types.OrderSideBuy,
 					Strength:    decimal.NewFromFloat(0.75),
-					StopLoss:    currentPrice.Mul(decimal.NewFromFloat(0.96)),
-					TakeProfit:  currentPrice.Mul(decimal.NewFromFloat(1.08)),
+					StopLoss:    stop,
+					TakeProfit:  target,
 					Reason:      "Bullish RSI divergence detected",
 					Metadata:    map[string]interface{}{"rsi": currentRSI},
 					GeneratedAt: time.Now(),
@@ -756,12 +1136,14 @@ func (s *RSIDivergenceStrategy) checkDivergence(symbol string) *Signal {
 		for i := 0; i < n-3; i++ {
 			if s.priceValues[i].LessThan(currentPrice) && s.rsiValues[i].GreaterThan(currentRSI) {
 				// Bearish divergence
+				stop, target := s.atrStops(types.OrderSideSell, currentPrice,
+					currentPrice.Mul(decimal.NewFromFloat(1.04)), currentPrice.Mul(decimal.NewFromFloat(0.92)))
 				return &Signal{
 					Symbol:      symbol,
 					Side:        types.OrderSideSell,
 					Strength:    decimal.NewFromFloat(0.75),
-					StopLoss:    currentPrice.Mul(decimal.NewFromFloat(1.04)),
-					TakeProfit:  currentPrice.Mul(decimal.NewFromFloat(0.92)),
+					StopLoss:    stop,
+					TakeProfit:  target,
 					Reason:      "Bearish RSI divergence detected",
 					Metadata:    map[string]interface{}{"rsi": currentRSI},
 					GeneratedAt: time.Now(),
@@ -773,132 +1155,297 @@ func (s *RSIDivergenceStrategy) checkDivergence(symbol string) *Signal {
 	return nil
 }
 
+// MinBars covers the RSI seed period plus the 10 RSI readings needed for divergence.
+func (s *RSIDivergenceStrategy) MinBars() int { return s.period + 10 }
+
 func (s *RSIDivergenceStrategy) OnTick(tick TickData) (*Signal, error) {
 	return nil, nil
 }
 
-// VWAPReversionStrategy implements VWAP reversion.
+// VWAP anchors.
+const (
+	vwapAnchorSession = "session" // Reset at 00:00 UTC each day
+	vwapAnchorWeekly  = "weekly"  // Reset at 00:00 UTC each Monday
+)
+
+// vwapMinBars is how many bars since the anchor the bands need.
+const vwapMinBars = 10
+
+// VWAPReversionStrategy trades reversion to a VWAP anchored at the start of
+// the daily session, the week, or an explicit time such as a swing high or
+// low. The VWAP and its standard deviation bands reset at each anchor.
 type VWAPReversionStrategy struct {
 	BaseStrategy
 	stdDevMult    decimal.Decimal
+	anchor        string    // vwapAnchorSession, vwapAnchorWeekly or an RFC 3339 time
+	anchorAt      time.Time // Explicit anchor time, when anchor is one
+	anchorStart   time.Time // Start of the active anchor period
 	cumVolPrice   decimal.Decimal
 	cumVolume     decimal.Decimal
 	vwap          decimal.Decimal
-	dayBars       []types.OHLCV
+	anchorBars    []types.OHLCV
 }
 
 // NewVWAPReversionStrategy creates a new VWAP reversion strategy.
 func NewVWAPReversionStrategy(logger *zap.Logger) *VWAPReversionStrategy {
-	return &VWAPReversionStrategy{
+	s := &VWAPReversionStrategy{
 		BaseStrategy: BaseStrategy{
 			logger:  logger,
 			params:  make(map[string]StrategyParameter),
 			maxBars: 500,
 		},
 		stdDevMult: decimal.NewFromFloat(2.0),
+		anchor:     vwapAnchorSession,
+	}
+	
+	s.params["std_dev_mult"] = StrategyParameter{
+		Name:        "std_dev_mult",
+		Description: "Standard deviation multiplier for the VWAP bands",
+		Type:        "float",
+		Default:     2.0,
+		Min:         1.0,
+		Max:         4.0,
+		Current:     2.0,
+	}
+	s.params["anchor"] = StrategyParameter{
+		Name:        "anchor",
+		Description: "Where the VWAP resets: \"session\" (daily UTC), \"weekly\" (Monday UTC), or an RFC 3339 time",
+		Type:        "string",
+		Default:     vwapAnchorSession,
+		Current:     vwapAnchorSession,
 	}
+	
+	s.initATR()
+	s.initPositionTracking(false)
+	
+	return s
 }
 
 func (s *VWAPReversionStrategy) Name() string { return "vwap_reversion" }
 func (s *VWAPReversionStrategy) Description() string {
-	return "Trades reversion to VWAP"
+	return "Trades reversion to a session, weekly or explicitly anchored VWAP"
+}
+
+func (s *VWAPReversionStrategy) SetParameter(name string, value interface{}) error {
+	var anchorAt time.Time
+	if anchor, ok := value.(string); ok && name == "anchor" && anchor != vwapAnchorSession && anchor != vwapAnchorWeekly {
+		t, err := time.Parse(time.RFC3339, anchor)
+		if err != nil {
+			return fmt.Errorf("parameter anchor must be %q, %q or an RFC 3339 time, got %q", vwapAnchorSession, vwapAnchorWeekly, anchor)
+		}
+		anchorAt = t.UTC()
+	}
+	if err := s.BaseStrategy.SetParameter(name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "std_dev_mult":
+		s.stdDevMult = s.decimalParam(name)
+	case "anchor":
+		s.anchor, _ = s.params[name].Current.(string)
+		s.anchorAt = anchorAt
+		s.resetVWAP(time.Time{})
+	}
+	return nil
+}
+
+// AnchorAt anchors the VWAP at t, such as the time of a swing high or low.
+// Bars before t are ignored.
+func (s *VWAPReversionStrategy) AnchorAt(t time.Time) {
+	anchor := t.UTC().Format(time.RFC3339)
+	param := s.params["anchor"]
+	param.Current = anchor
+	s.params["anchor"] = param
+	s.anchor = anchor
+	s.anchorAt = t.UTC()
+	s.resetVWAP(time.Time{})
+}
+
+// AnchorStart returns the start of the active anchor period, or zero before
+// the first bar since the anchor.
+func (s *VWAPReversionStrategy) AnchorStart() time.Time {
+	return s.anchorStart
 }
 
 func (s *VWAPReversionStrategy) Initialize(ctx context.Context) error {
 	s.bars = make([]types.OHLCV, 0, s.maxBars)
+	s.resetVWAP(time.Time{})
+	return nil
+}
+
+// Reset clears the bar buffer along with the VWAP and its bands.
+func (s *VWAPReversionStrategy) Reset() {
+	s.BaseStrategy.Reset()
+	s.resetVWAP(time.Time{})
+}
+
+// resetVWAP starts a new anchor period at start.
+func (s *VWAPReversionStrategy) resetVWAP(start time.Time) {
+	s.anchorStart = start
 	s.cumVolPrice = decimal.Zero
 	s.cumVolume = decimal.Zero
-	s.dayBars = make([]types.OHLCV, 0)
-	return nil
+	s.vwap = decimal.Zero
+	s.anchorBars = s.anchorBars[:0]
+}
+
+// periodStart returns the start of the anchor period t falls in.
+func (s *VWAPReversionStrategy) periodStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch s.anchor {
+	case vwapAnchorSession:
+		return day
+	case vwapAnchorWeekly:
+		// Weeks start on Monday
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	default:
+		return s.anchorAt
+	}
 }
 
 func (s *VWAPReversionStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
 	s.AddBar(bar)
 	
+	// Bars before an explicit anchor don't count toward its VWAP
+	if !s.anchorAt.IsZero() && bar.Timestamp.Before(s.anchorAt) {
+		return nil, nil
+	}
+	if start := s.periodStart(bar.Timestamp); len(s.anchorBars) == 0 || !start.Equal(s.anchorStart) {
+		s.resetVWAP(start)
+	}
+	
 	// Calculate typical price
 	typical := bar.High.Add(bar.Low).Add(bar.Close).Div(decimal.NewFromInt(3))
 	
 	// Update cumulative values
 	s.cumVolPrice = s.cumVolPrice.Add(typical.Mul(bar.Volume))
 	s.cumVolume = s.cumVolume.Add(bar.Volume)
+	s.anchorBars = append(s.anchorBars, bar)
 	
 	if s.cumVolume.IsZero() {
 		return nil, nil
 	}
 	
 	s.vwap = s.cumVolPrice.Div(s.cumVolume)
-	s.dayBars = append(s.dayBars, bar)
 	
-	if len(s.dayBars) < 10 {
+	if len(s.anchorBars) < vwapMinBars {
 		return nil, nil
 	}
 	
-	// Calculate VWAP standard deviation
+	// Calculate VWAP standard deviation since the anchor
 	variance := decimal.Zero
-	for _, b := range s.dayBars {
+	for _, b := range s.anchorBars {
 		typPrice := b.High.Add(b.Low).Add(b.Close).Div(decimal.NewFromInt(3))
 		diff := typPrice.Sub(s.vwap)
 		variance = variance.Add(diff.Mul(diff))
 	}
-	variance = variance.Div(decimal.NewFromInt(int64(len(s.dayBars))))
+	variance = variance.Div(decimal.NewFromInt(int64(len(s.anchorBars))))
 	stdDev := sqrtDecimal(variance)
 	
 	current := bar.Close
 	upperBand := s.vwap.Add(stdDev.Mul(s.stdDevMult))
 	lowerBand := s.vwap.Sub(stdDev.Mul(s.stdDevMult))
+	metadata := map[string]interface{}{
+		"vwap":       s.vwap,
+		"upper_band": upperBand,
+		"lower_band": lowerBand,
+		"anchor":     s.anchor,
+		"anchor_at":  s.anchorStart,
+	}
 	
 	if current.LessThan(lowerBand) {
-		return &Signal{
+		stop, _ := s.atrStops(types.OrderSideBuy, current, current.Mul(decimal.NewFromFloat(0.97)), s.vwap)
+		return s.transition(&Signal{
 			Symbol:      bar.Symbol,
 			Side:        types.OrderSideBuy,
 			Strength:    decimal.NewFromFloat(0.7),
-			StopLoss:    current.Mul(decimal.NewFromFloat(0.97)),
+			StopLoss:    stop,
 			TakeProfit:  s.vwap,
 			Reason:      "Price below VWAP lower band",
-			Metadata:    map[string]interface{}{"vwap": s.vwap},
+			Metadata:    metadata,
 			GeneratedAt: time.Now(),
-		}, nil
+		}), nil
 	} else if current.GreaterThan(upperBand) {
-		return &Signal{
+		stop, _ := s.atrStops(types.OrderSideSell, current, current.Mul(decimal.NewFromFloat(1.03)), s.vwap)
+		return s.transition(&Signal{
 			Symbol:      bar.Symbol,
 			Side:        types.OrderSideSell,
 			Strength:    decimal.NewFromFloat(0.7),
-			StopLoss:    current.Mul(decimal.NewFromFloat(1.03)),
+			StopLoss:    stop,
 			TakeProfit:  s.vwap,
 			Reason:      "Price above VWAP upper band",
-			Metadata:    map[string]interface{}{"vwap": s.vwap},
+			Metadata:    metadata,
 			GeneratedAt: time.Now(),
-		}, nil
+		}), nil
 	}
 	
 	return nil, nil
 }
 
+func (s *VWAPReversionStrategy) MinBars() int { return vwapMinBars }
+
 func (s *VWAPReversionStrategy) OnTick(tick TickData) (*Signal, error) {
 	return nil, nil
 }
 
-// GridStrategy implements grid trading.
+// GridStrategy implements grid trading. Each grid level crossed opens a lot
+// that is closed by the opposite-side signal once price returns to the
+// adjacent level toward the base price, and the grid recenters on price when
+// it drifts beyond the outermost level.
 type GridStrategy struct {
 	BaseStrategy
-	gridSize    decimal.Decimal
-	gridLevels  int
-	basePrice   decimal.Decimal
-	buyLevels   []decimal.Decimal
-	sellLevels  []decimal.Decimal
+	gridSize          decimal.Decimal
+	gridLevels        int
+	geometric         bool
+	recenterThreshold decimal.Decimal
+	basePrice         decimal.Decimal
+	buyLevels         []decimal.Decimal // Nearest to the base price first
+	sellLevels        []decimal.Decimal // Nearest to the base price first
+	lots              []gridLot
+}
+
+// gridLot is inventory opened at a grid level, closed when price reaches
+// Exit.
+type gridLot struct {
+	Side  types.OrderSide
+	Entry decimal.Decimal
+	Exit  decimal.Decimal
 }
 
 // NewGridStrategy creates a new grid strategy.
 func NewGridStrategy(logger *zap.Logger) *GridStrategy {
-	return &GridStrategy{
+	s := &GridStrategy{
 		BaseStrategy: BaseStrategy{
 			logger:  logger,
 			params:  make(map[string]StrategyParameter),
 			maxBars: 100,
 		},
-		gridSize:   decimal.NewFromFloat(0.01),
-		gridLevels: 5,
+		gridSize:          decimal.NewFromFloat(0.01),
+		gridLevels:        5,
+		recenterThreshold: decimal.NewFromFloat(0.02),
+	}
+	
+	s.params["recenter_threshold"] = StrategyParameter{
+		Name:        "recenter_threshold",
+		Description: "Fraction beyond the outermost level price must move to recenter the grid on it (0 disables)",
+		Type:        "float",
+		Default:     0.02,
+		Min:         0.0,
+		Max:         0.5,
+		Current:     0.02,
 	}
+	s.params["geometric"] = StrategyParameter{
+		Name:        "geometric",
+		Description: "Space levels by a constant ratio instead of a constant price step",
+		Type:        "bool",
+		Default:     false,
+		Current:     false,
+	}
+	
+	s.initATR()
+	s.initPositionTracking(true)
+	
+	return s
 }
 
 func (s *GridStrategy) Name() string { return "grid" }
@@ -906,50 +1453,120 @@ func (s *GridStrategy) Description() string {
 	return "Grid trading with multiple buy/sell levels"
 }
 
+func (s *GridStrategy) SetParameter(name string, value interface{}) error {
+	if err := s.BaseStrategy.SetParameter(name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "recenter_threshold":
+		s.recenterThreshold = s.decimalParam(name)
+	case "geometric":
+		s.geometric, _ = s.params[name].Current.(bool)
+		if !s.basePrice.IsZero() {
+			s.setupGridLevels()
+		}
+	}
+	return nil
+}
+
 func (s *GridStrategy) Initialize(ctx context.Context) error {
 	s.bars = make([]types.OHLCV, 0, s.maxBars)
+	s.resetGrid()
 	return nil
 }
 
+// Reset clears the bar buffer along with the grid and its open lots.
+func (s *GridStrategy) Reset() {
+	s.BaseStrategy.Reset()
+	s.resetGrid()
+}
+
+func (s *GridStrategy) resetGrid() {
+	s.basePrice = decimal.Zero
+	s.buyLevels = nil
+	s.sellLevels = nil
+	s.lots = nil
+}
+
 func (s *GridStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
 	s.AddBar(bar)
 	
-	if s.basePrice.IsZero() {
-		s.basePrice = bar.Close
+	current := bar.Close
+	
+	if s.basePrice.IsZero() || s.beyondGrid(current) {
+		// Open lots keep their exits across a recenter
+		s.basePrice = current
 		s.setupGridLevels()
 	}
 	
-	current := bar.Close
+	// Close the first lot whose exit level price has returned to
+	for i, lot := range s.lots {
+		reached := current.GreaterThanOrEqual(lot.Exit)
+		side := types.OrderSideSell
+		if lot.Side == types.OrderSideSell {
+			reached = current.LessThanOrEqual(lot.Exit)
+			side = types.OrderSideBuy
+		}
+		if !reached {
+			continue
+		}
+		
+		s.lots = append(s.lots[:i], s.lots[i+1:]...)
+		return s.transition(&Signal{
+			Symbol:      bar.Symbol,
+			Side:        side,
+			Strength:    decimal.NewFromFloat(0.6),
+			Reason:      fmt.Sprintf("Grid %s level closed at adjacent level", lot.Side),
+			Metadata:    s.gridMetadata(lot.Entry, "close"),
+			GeneratedAt: time.Now(),
+		}), nil
+	}
 	
-	// Check if price hit a grid level
-	for _, level := range s.buyLevels {
-		if current.LessThanOrEqual(level) && s.bars[len(s.bars)-2].Close.GreaterThan(level) {
-			return &Signal{
-				Symbol:      bar.Symbol,
-				Side:        types.OrderSideBuy,
-				Strength:    decimal.NewFromFloat(0.6),
-				StopLoss:    level.Mul(decimal.NewFromFloat(0.95)),
-				TakeProfit:  s.basePrice,
-				Reason:      "Grid buy level triggered",
-				Metadata:    map[string]interface{}{"grid_level": level},
-				GeneratedAt: time.Now(),
-			}, nil
+	// Open at the nearest level price has reached that holds no lot
+	for i, level := range s.buyLevels {
+		if current.GreaterThan(level) || s.levelOpen(level) {
+			continue
 		}
+		exit := s.basePrice
+		if i > 0 {
+			exit = s.buyLevels[i-1]
+		}
+		s.lots = append(s.lots, gridLot{Side: types.OrderSideBuy, Entry: level, Exit: exit})
+		
+		stop, _ := s.atrStops(types.OrderSideBuy, level, level.Mul(decimal.NewFromFloat(0.95)), exit)
+		return s.transition(&Signal{
+			Symbol:      bar.Symbol,
+			Side:        types.OrderSideBuy,
+			Strength:    decimal.NewFromFloat(0.6),
+			StopLoss:    stop,
+			TakeProfit:  exit,
+			Reason:      "Grid buy level triggered",
+			Metadata:    s.gridMetadata(level, "open"),
+			GeneratedAt: time.Now(),
+		}), nil
 	}
 	
-	for _, level := range s.sellLevels {
-		if current.GreaterThanOrEqual(level) && s.bars[len(s.bars)-2].Close.LessThan(level) {
-			return &Signal{
-				Symbol:      bar.Symbol,
-				Side:        types.OrderSideSell,
-				Strength:    decimal.NewFromFloat(0.6),
-				StopLoss:    level.Mul(decimal.NewFromFloat(1.05)),
-				TakeProfit:  s.basePrice,
-				Reason:      "Grid sell level triggered",
-				Metadata:    map[string]interface{}{"grid_level": level},
-				GeneratedAt: time.Now(),
-			}, nil
+	for i, level := range s.sellLevels {
+		if current.LessThan(level) || s.levelOpen(level) {
+			continue
+		}
+		exit := s.basePrice
+		if i > 0 {
+			exit = s.sellLevels[i-1]
 		}
+		s.lots = append(s.lots, gridLot{Side: types.OrderSideSell, Entry: level, Exit: exit})
+		
+		stop, _ := s.atrStops(types.OrderSideSell, level, level.Mul(decimal.NewFromFloat(1.05)), exit)
+		return s.transition(&Signal{
+			Symbol:      bar.Symbol,
+			Side:        types.OrderSideSell,
+			Strength:    decimal.NewFromFloat(0.6),
+			StopLoss:    stop,
+			TakeProfit:  exit,
+			Reason:      "Grid sell level triggered",
+			Metadata:    s.gridMetadata(level, "open"),
+			GeneratedAt: time.Now(),
+		}), nil
 	}
 	
 	return nil, nil
@@ -959,13 +1576,88 @@ func (s *GridStrategy) setupGridLevels() {
 	s.buyLevels = make([]decimal.Decimal, s.gridLevels)
 	s.sellLevels = make([]decimal.Decimal, s.gridLevels)
 	
+	one := decimal.NewFromInt(1)
+	ratio := one.Add(s.gridSize)
 	for i := 0; i < s.gridLevels; i++ {
+		if s.geometric {
+			step := ratio.Pow(decimal.NewFromInt(int64(i + 1)))
+			s.buyLevels[i] = s.basePrice.Div(step)
+			s.sellLevels[i] = s.basePrice.Mul(step)
+			continue
+		}
 		offset := s.gridSize.Mul(decimal.NewFromInt(int64(i + 1)))
 		s.buyLevels[i] = s.basePrice.Sub(s.basePrice.Mul(offset))
 		s.sellLevels[i] = s.basePrice.Add(s.basePrice.Mul(offset))
 	}
 }
 
+// beyondGrid reports whether price has moved past the outermost level by
+// more than the recenter threshold.
+func (s *GridStrategy) beyondGrid(price decimal.Decimal) bool {
+	if s.recenterThreshold.IsZero() || len(s.buyLevels) == 0 {
+		return false
+	}
+	one := decimal.NewFromInt(1)
+	lowest := s.buyLevels[len(s.buyLevels)-1].Mul(one.Sub(s.recenterThreshold))
+	highest := s.sellLevels[len(s.sellLevels)-1].Mul(one.Add(s.recenterThreshold))
+	return price.LessThan(lowest) || price.GreaterThan(highest)
+}
+
+// levelOpen reports whether a lot is open at the level.
+func (s *GridStrategy) levelOpen(level decimal.Decimal) bool {
+	for _, lot := range s.lots {
+		if lot.Entry.Equal(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// gridMetadata describes the signal's level and the grid with its open lots
+// so the UI can render it.
+func (s *GridStrategy) gridMetadata(level decimal.Decimal, action string) map[string]interface{} {
+	levels := make([]map[string]interface{}, 0, len(s.buyLevels)+len(s.sellLevels))
+	for i := len(s.buyLevels) - 1; i >= 0; i-- {
+		levels = append(levels, map[string]interface{}{
+			"price": s.buyLevels[i],
+			"side":  types.OrderSideBuy,
+			"open":  s.levelOpen(s.buyLevels[i]),
+		})
+	}
+	for _, level := range s.sellLevels {
+		levels = append(levels, map[string]interface{}{
+			"price": level,
+			"side":  types.OrderSideSell,
+			"open":  s.levelOpen(level),
+		})
+	}
+	
+	lots := make([]map[string]interface{}, len(s.lots))
+	for i, lot := range s.lots {
+		lots[i] = map[string]interface{}{
+			"side":  lot.Side,
+			"entry": lot.Entry,
+			"exit":  lot.Exit,
+		}
+	}
+	
+	spacing := "arithmetic"
+	if s.geometric {
+		spacing = "geometric"
+	}
+	
+	return map[string]interface{}{
+		"grid_level":  level,
+		"grid_action": action,
+		"base_price":  s.basePrice,
+		"spacing":     spacing,
+		"levels":      levels,
+		"open_lots":   lots,
+	}
+}
+
+func (s *GridStrategy) MinBars() int { return 2 }
+
 func (s *GridStrategy) OnTick(tick TickData) (*Signal, error) {
 	return nil, nil
 }
@@ -981,7 +1673,7 @@ type DCAStrategy struct {
 
 // NewDCAStrategy creates a new DCA strategy.
 func NewDCAStrategy(logger *zap.Logger) *DCAStrategy {
-	return &DCAStrategy{
+	s := &DCAStrategy{
 		BaseStrategy: BaseStrategy{
 			logger:  logger,
 			params:  make(map[string]StrategyParameter),
@@ -990,6 +1682,10 @@ func NewDCAStrategy(logger *zap.Logger) *DCAStrategy {
 		interval:      24, // Buy every 24 bars
 		dropThreshold: decimal.NewFromFloat(0.05), // Extra buy on 5% drop
 	}
+	
+	s.initPositionTracking(true)
+	
+	return s
 }
 
 func (s *DCAStrategy) Name() string { return "dca" }
@@ -1011,13 +1707,13 @@ func (s *DCAStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
 	// Regular DCA buy
 	if s.barCount-s.lastBuyBar >= s.interval {
 		s.lastBuyBar = s.barCount
-		return &Signal{
+		return s.transition(&Signal{
 			Symbol:      bar.Symbol,
 			Side:        types.OrderSideBuy,
 			Strength:    decimal.NewFromFloat(0.5),
 			Reason:      "Scheduled DCA buy",
 			GeneratedAt: time.Now(),
-		}, nil
+		}), nil
 	}
 	
 	// Extra buy on significant drop
@@ -1027,34 +1723,1041 @@ func (s *DCAStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
 		
 		if drop.GreaterThan(s.dropThreshold) {
 			s.lastBuyBar = s.barCount
-			return &Signal{
+			return s.transition(&Signal{
 				Symbol:      bar.Symbol,
 				Side:        types.OrderSideBuy,
 				Strength:    decimal.NewFromFloat(0.7),
 				Reason:      "DCA dip buy opportunity",
 				Metadata:    map[string]interface{}{"drop_pct": drop.Mul(decimal.NewFromInt(100))},
 				GeneratedAt: time.Now(),
-			}, nil
+			}), nil
 		}
 	}
 	
 	return nil, nil
 }
 
+func (s *DCAStrategy) MinBars() int { return 1 }
+
 func (s *DCAStrategy) OnTick(tick TickData) (*Signal, error) {
 	return nil, nil
 }
 
-// Helper: sqrt using Newton's method
-func sqrtDecimal(d decimal.Decimal) decimal.Decimal {
-	if d.IsZero() || d.IsNegative() {
-		return decimal.Zero
+// IchimokuStrategy implements Ichimoku Kinko Hyo cloud trading.
+type IchimokuStrategy struct {
+	BaseStrategy
+	tenkanPeriod  int
+	kijunPeriod   int
+	senkouBPeriod int
+	senkouA       []decimal.Decimal // forward-shifted by kijunPeriod bars
+	senkouB       []decimal.Decimal // forward-shifted by kijunPeriod bars
+	prevTenkan    decimal.Decimal
+	prevKijun     decimal.Decimal
+}
+
+// NewIchimokuStrategy creates a new Ichimoku cloud strategy.
+func NewIchimokuStrategy(logger *zap.Logger) *IchimokuStrategy {
+	s := &IchimokuStrategy{
+		BaseStrategy: BaseStrategy{
+			logger:  logger,
+			params:  make(map[string]StrategyParameter),
+			maxBars: 200,
+		},
+		tenkanPeriod:  9,
+		kijunPeriod:   26,
+		senkouBPeriod: 52,
+	}
+	
+	s.params["tenkan_period"] = StrategyParameter{
+		Name:        "tenkan_period",
+		Description: "Conversion line (Tenkan-sen) period",
+		Type:        "int",
+		Default:     9,
+		Min:         5,
+		Max:         30,
+		Current:     9,
+	}
+	s.params["kijun_period"] = StrategyParameter{
+		Name:        "kijun_period",
+		Description: "Base line (Kijun-sen) period, also used as the cloud displacement",
+		Type:        "int",
+		Default:     26,
+		Min:         10,
+		Max:         60,
+		Current:     26,
+	}
+	s.params["senkou_b_period"] = StrategyParameter{
+		Name:        "senkou_b_period",
+		Description: "Leading span B (Senkou Span B) period",
+		Type:        "int",
+		Default:     52,
+		Min:         20,
+		Max:         120,
+		Current:     52,
 	}
 	
-	// Newton's method
-	x := d
-	for i := 0; i < 20; i++ {
-		x = x.Add(d.Div(x)).Div(decimal.NewFromInt(2))
+	s.initATR()
+	s.initPositionTracking(false)
+	
+	return s
+}
+
+func (s *IchimokuStrategy) Name() string { return "ichimoku" }
+func (s *IchimokuStrategy) Description() string {
+	return "Trades Tenkan/Kijun crosses confirmed by the cloud and the Chikou span"
+}
+
+func (s *IchimokuStrategy) SetParameter(name string, value interface{}) error {
+	if err := s.BaseStrategy.SetParameter(name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "tenkan_period":
+		s.tenkanPeriod = s.intParam(name)
+	case "kijun_period":
+		s.kijunPeriod = s.intParam(name)
+	case "senkou_b_period":
+		s.senkouBPeriod = s.intParam(name)
+	}
+	return nil
+}
+
+func (s *IchimokuStrategy) Initialize(ctx context.Context) error {
+	s.bars = make([]types.OHLCV, 0, s.maxBars)
+	s.senkouA = make([]decimal.Decimal, 0, s.kijunPeriod+1)
+	s.senkouB = make([]decimal.Decimal, 0, s.kijunPeriod+1)
+	s.prevTenkan = decimal.Zero
+	s.prevKijun = decimal.Zero
+	return nil
+}
+
+// Reset clears the bar buffer along with the forward-shifted span buffers.
+func (s *IchimokuStrategy) Reset() {
+	s.BaseStrategy.Reset()
+	s.senkouA = s.senkouA[:0]
+	s.senkouB = s.senkouB[:0]
+	s.prevTenkan = decimal.Zero
+	s.prevKijun = decimal.Zero
+}
+
+func (s *IchimokuStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
+	s.AddBar(bar)
+	
+	longest := s.senkouBPeriod
+	if s.kijunPeriod > longest {
+		longest = s.kijunPeriod
+	}
+	if len(s.bars) < longest {
+		return nil, nil
+	}
+	
+	tenkan := s.midpoint(s.tenkanPeriod)
+	kijun := s.midpoint(s.kijunPeriod)
+	
+	// Spans computed now are plotted kijunPeriod bars ahead, so the cloud
+	// for the current bar is the value computed kijunPeriod bars ago.
+	s.senkouA = append(s.senkouA, tenkan.Add(kijun).Div(decimal.NewFromInt(2)))
+	s.senkouB = append(s.senkouB, s.midpoint(s.senkouBPeriod))
+	if keep := s.kijunPeriod + 1; len(s.senkouA) > keep {
+		s.senkouA = s.senkouA[len(s.senkouA)-keep:]
+		s.senkouB = s.senkouB[len(s.senkouB)-keep:]
+	}
+	
+	prevTenkan, prevKijun := s.prevTenkan, s.prevKijun
+	s.prevTenkan, s.prevKijun = tenkan, kijun
+	
+	if len(s.senkouA) <= s.kijunPeriod || prevTenkan.IsZero() {
+		return nil, nil
+	}
+	
+	spanA := s.senkouA[0]
+	spanB := s.senkouB[0]
+	cloudTop := decimal.Max(spanA, spanB)
+	cloudBottom := decimal.Min(spanA, spanB)
+	
+	current := bar.Close
+	// Chikou span is the current close plotted kijunPeriod bars back; it
+	// confirms a cross when it clears the price it is plotted against.
+	if len(s.bars) <= s.kijunPeriod {
+		return nil, nil
+	}
+	chikou := current
+	laggedClose := s.bars[len(s.bars)-1-s.kijunPeriod].Close
+	metadata := map[string]interface{}{
+		"tenkan":       tenkan,
+		"kijun":        kijun,
+		"senkou_a":     spanA,
+		"senkou_b":     spanB,
+		"cloud_top":    cloudTop,
+		"cloud_bottom": cloudBottom,
+		"chikou":       chikou,
+		"chikou_price": laggedClose,
+	}
+	
+	wasBullish := prevTenkan.GreaterThan(prevKijun)
+	isBullish := tenkan.GreaterThan(kijun)
+	
+	if !wasBullish && isBullish && current.GreaterThan(cloudTop) && chikou.GreaterThan(laggedClose) {
+		stop, target := s.atrStops(types.OrderSideBuy, current,
+			decimal.Min(kijun, cloudBottom), current.Add(current.Sub(cloudBottom)))
+		return s.transition(&Signal{
+			Symbol:      bar.Symbol,
+			Side:        types.OrderSideBuy,
+			Strength:    decimal.NewFromFloat(0.75),
+			StopLoss:    stop,
+			TakeProfit:  target,
+			Reason:      "Bullish TK cross above the cloud",
+			Metadata:    metadata,
+			GeneratedAt: time.Now(),
+		}), nil
+	} else if wasBullish && !isBullish && current.LessThan(cloudBottom) && chikou.LessThan(laggedClose) {
+		stop, target := s.atrStops(types.OrderSideSell, current,
+			decimal.Max(kijun, cloudTop), current.Sub(cloudTop.Sub(current)))
+		return s.transition(&Signal{
+			Symbol:      bar.Symbol,
+			Side:        types.OrderSideSell,
+			Strength:    decimal.NewFromFloat(0.75),
+			StopLoss:    stop,
+			TakeProfit:  target,
+			Reason:      "Bearish TK cross below the cloud",
+			Metadata:    metadata,
+			GeneratedAt: time.Now(),
+		}), nil
+	}
+	
+	return nil, nil
+}
+
+// midpoint returns the (highest high + lowest low) / 2 of the last n bars.
+func (s *IchimokuStrategy) midpoint(n int) decimal.Decimal {
+	start := len(s.bars) - n
+	if start < 0 {
+		start = 0
+	}
+	highest := s.bars[start].High
+	lowest := s.bars[start].Low
+	for i := start + 1; i < len(s.bars); i++ {
+		if s.bars[i].High.GreaterThan(highest) {
+			highest = s.bars[i].High
+		}
+		if s.bars[i].Low.LessThan(lowest) {
+			lowest = s.bars[i].Low
+		}
+	}
+	return highest.Add(lowest).Div(decimal.NewFromInt(2))
+}
+
+// MinBars covers the longest lookback plus the forward displacement of the cloud.
+func (s *IchimokuStrategy) MinBars() int {
+	longest := s.senkouBPeriod
+	if s.kijunPeriod > longest {
+		longest = s.kijunPeriod
+	}
+	return longest + s.kijunPeriod
+}
+
+func (s *IchimokuStrategy) OnTick(tick TickData) (*Signal, error) {
+	return nil, nil
+}
+
+// OrderBookImbalanceStrategy implements tick-level scalping on top-of-book imbalance.
+type OrderBookImbalanceStrategy struct {
+	BaseStrategy
+	window       int
+	threshold    decimal.Decimal
+	maxSpreadBps decimal.Decimal
+	imbalances   []decimal.Decimal
+}
+
+// NewOrderBookImbalanceStrategy creates a new order book imbalance strategy.
+func NewOrderBookImbalanceStrategy(logger *zap.Logger) *OrderBookImbalanceStrategy {
+	s := &OrderBookImbalanceStrategy{
+		BaseStrategy: BaseStrategy{
+			logger:  logger,
+			params:  make(map[string]StrategyParameter),
+			maxBars: 50,
+		},
+		window:       5,
+		threshold:    decimal.NewFromFloat(0.3),
+		maxSpreadBps: decimal.NewFromInt(10),
+	}
+	
+	s.params["window"] = StrategyParameter{
+		Name:        "window",
+		Description: "Number of consecutive ticks the imbalance must persist",
+		Type:        "int",
+		Default:     5,
+		Min:         1,
+		Max:         100,
+		Current:     5,
+	}
+	s.params["imbalance_threshold"] = StrategyParameter{
+		Name:        "imbalance_threshold",
+		Description: "Minimum absolute bid/ask size imbalance (-1 to 1) for a signal",
+		Type:        "float",
+		Default:     0.3,
+		Min:         0.05,
+		Max:         0.95,
+		Current:     0.3,
+	}
+	
+	s.initPositionTracking(false)
+	
+	return s
+}
+
+func (s *OrderBookImbalanceStrategy) Name() string { return "ob_imbalance" }
+func (s *OrderBookImbalanceStrategy) Description() string {
+	return "Scalps sustained top-of-book bid/ask size imbalances"
+}
+
+func (s *OrderBookImbalanceStrategy) SetParameter(name string, value interface{}) error {
+	if err := s.BaseStrategy.SetParameter(name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "window":
+		s.window = s.intParam(name)
+	case "imbalance_threshold":
+		s.threshold = s.decimalParam(name)
+	}
+	return nil
+}
+
+func (s *OrderBookImbalanceStrategy) Initialize(ctx context.Context) error {
+	s.bars = make([]types.OHLCV, 0, s.maxBars)
+	s.imbalances = make([]decimal.Decimal, 0, s.window)
+	return nil
+}
+
+// Reset clears the bar buffer and the rolling imbalance window.
+func (s *OrderBookImbalanceStrategy) Reset() {
+	s.BaseStrategy.Reset()
+	s.imbalances = s.imbalances[:0]
+}
+
+func (s *OrderBookImbalanceStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
+	s.AddBar(bar)
+	return nil, nil
+}
+
+// MinBars is zero because signals are driven by ticks, not bars.
+func (s *OrderBookImbalanceStrategy) MinBars() int { return 0 }
+
+func (s *OrderBookImbalanceStrategy) OnTick(tick TickData) (*Signal, error) {
+	if !tick.Bid.IsPositive() || !tick.Ask.IsPositive() || tick.Ask.LessThan(tick.Bid) {
+		return nil, nil
+	}
+	
+	totalSize := tick.BidSize.Add(tick.AskSize)
+	if !totalSize.IsPositive() {
+		return nil, nil
+	}
+	
+	mid := tick.Bid.Add(tick.Ask).Div(decimal.NewFromInt(2))
+	spread := tick.Ask.Sub(tick.Bid)
+	spreadBps := spread.Div(mid).Mul(decimal.NewFromInt(10000))
+	
+	// A wide spread means the top of book is thin and the imbalance is noise
+	if spreadBps.GreaterThan(s.maxSpreadBps) {
+		s.imbalances = s.imbalances[:0]
+		return nil, nil
+	}
+	
+	imbalance := tick.BidSize.Sub(tick.AskSize).Div(totalSize)
+	s.imbalances = append(s.imbalances, imbalance)
+	if len(s.imbalances) > s.window {
+		s.imbalances = s.imbalances[len(s.imbalances)-s.window:]
+	}
+	
+	if len(s.imbalances) < s.window {
+		return nil, nil
+	}
+	
+	// Require the imbalance to be sustained in the same direction across the window
+	sum := decimal.Zero
+	for _, imb := range s.imbalances {
+		if imb.Abs().LessThan(s.threshold) || imb.Sign() != imbalance.Sign() {
+			return nil, nil
+		}
+		sum = sum.Add(imb)
+	}
+	avg := sum.Div(decimal.NewFromInt(int64(len(s.imbalances))))
+	
+	// Start a fresh window so a single sustained run emits one signal
+	s.imbalances = s.imbalances[:0]
+	
+	strength := avg.Abs()
+	if strength.GreaterThan(decimal.NewFromInt(1)) {
+		strength = decimal.NewFromInt(1)
+	}
+	metadata := map[string]interface{}{
+		"imbalance":  avg,
+		"spread":     spread,
+		"spread_bps": spreadBps,
+		"mid":        mid,
+	}
+	
+	if avg.IsPositive() {
+		return s.transition(&Signal{
+			Symbol:      tick.Symbol,
+			Side:        types.OrderSideBuy,
+			Strength:    strength,
+			StopLoss:    mid.Mul(decimal.NewFromFloat(0.998)),
+			TakeProfit:  mid.Mul(decimal.NewFromFloat(1.003)),
+			Reason:      "Sustained bid-side order book imbalance",
+			Metadata:    metadata,
+			GeneratedAt: time.Now(),
+		}), nil
+	}
+	
+	return s.transition(&Signal{
+		Symbol:      tick.Symbol,
+		Side:        types.OrderSideSell,
+		Strength:    strength,
+		StopLoss:    mid.Mul(decimal.NewFromFloat(1.002)),
+		TakeProfit:  mid.Mul(decimal.NewFromFloat(0.997)),
+		Reason:      "Sustained ask-side order book imbalance",
+		Metadata:    metadata,
+		GeneratedAt: time.Now(),
+	}), nil
+}
+
+// Keltner channel modes.
+const (
+	keltnerBreakout  = "breakout"  // Trade closes outside the channel in their direction
+	keltnerReversion = "reversion" // Fade touches of a band back toward the midline
+)
+
+// KeltnerChannelStrategy trades a channel of ATR-scaled bands around an EMA
+// midline. Unlike Bollinger Bands, the bands do not widen with the moves of
+// a steady trend, so closes outside them mark real breakouts.
+type KeltnerChannelStrategy struct {
+	BaseStrategy
+	emaPeriod int
+	bandMult  decimal.Decimal
+	mode      string
+	ema       decimal.Decimal
+	prevUpper decimal.Decimal
+	prevLower decimal.Decimal
+}
+
+// NewKeltnerChannelStrategy creates a new Keltner channel strategy.
+func NewKeltnerChannelStrategy(logger *zap.Logger) *KeltnerChannelStrategy {
+	s := &KeltnerChannelStrategy{
+		BaseStrategy: BaseStrategy{
+			logger:    logger,
+			params:    make(map[string]StrategyParameter),
+			maxBars:   200,
+			atrPeriod: 10,
+		},
+		emaPeriod: 20,
+		bandMult:  decimal.NewFromFloat(2.0),
+		mode:      keltnerBreakout,
+	}
+	
+	s.params["ema_period"] = StrategyParameter{
+		Name:        "ema_period",
+		Description: "EMA period for the channel midline",
+		Type:        "int",
+		Default:     20,
+		Min:         5,
+		Max:         100,
+		Current:     20,
+	}
+	s.params["atr_period"] = StrategyParameter{
+		Name:        "atr_period",
+		Description: "Average True Range period for the band width",
+		Type:        "int",
+		Default:     10,
+		Min:         2,
+		Max:         100,
+		Current:     10,
+	}
+	s.params["band_mult"] = StrategyParameter{
+		Name:        "band_mult",
+		Description: "ATR multiple between the midline and each band",
+		Type:        "float",
+		Default:     2.0,
+		Min:         0.5,
+		Max:         5.0,
+		Current:     2.0,
+	}
+	s.params["mode"] = StrategyParameter{
+		Name:        "mode",
+		Description: "\"breakout\" trades closes outside the channel, \"reversion\" fades band touches",
+		Type:        "string",
+		Default:     keltnerBreakout,
+		Current:     keltnerBreakout,
+	}
+	
+	s.initPositionTracking(false)
+	
+	return s
+}
+
+func (s *KeltnerChannelStrategy) Name() string { return "keltner" }
+func (s *KeltnerChannelStrategy) Description() string {
+	return "Trades breakouts from, or reversions inside, an EMA channel with ATR bands"
+}
+
+func (s *KeltnerChannelStrategy) SetParameter(name string, value interface{}) error {
+	if mode, ok := value.(string); ok && name == "mode" && mode != keltnerBreakout && mode != keltnerReversion {
+		return fmt.Errorf("parameter mode must be %q or %q, got %q", keltnerBreakout, keltnerReversion, mode)
+	}
+	if err := s.BaseStrategy.SetParameter(name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "ema_period":
+		s.emaPeriod = s.intParam(name)
+	case "band_mult":
+		s.bandMult = s.decimalParam(name)
+	case "mode":
+		s.mode, _ = s.params[name].Current.(string)
+	}
+	return nil
+}
+
+func (s *KeltnerChannelStrategy) Initialize(ctx context.Context) error {
+	s.bars = make([]types.OHLCV, 0, s.maxBars)
+	s.resetChannel()
+	return nil
+}
+
+// Reset clears the bar buffer along with the midline and previous bands.
+func (s *KeltnerChannelStrategy) Reset() {
+	s.BaseStrategy.Reset()
+	s.resetChannel()
+}
+
+func (s *KeltnerChannelStrategy) resetChannel() {
+	s.ema = decimal.Zero
+	s.prevUpper = decimal.Zero
+	s.prevLower = decimal.Zero
+}
+
+func (s *KeltnerChannelStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
+	s.AddBar(bar)
+	
+	price := bar.Close
+	if s.ema.IsZero() {
+		s.ema = price
+		return nil, nil
+	}
+	mult := decimal.NewFromFloat(2.0).Div(decimal.NewFromInt(int64(s.emaPeriod + 1)))
+	s.ema = price.Mul(mult).Add(s.ema.Mul(decimal.NewFromInt(1).Sub(mult)))
+	
+	atr := s.ATR(s.atrPeriod)
+	if atr.IsZero() || len(s.bars) < s.emaPeriod {
+		return nil, nil
+	}
+	
+	width := atr.Mul(s.bandMult)
+	upper := s.ema.Add(width)
+	lower := s.ema.Sub(width)
+	prevUpper, prevLower := s.prevUpper, s.prevLower
+	s.prevUpper, s.prevLower = upper, lower
+	if prevUpper.IsZero() {
+		return nil, nil
+	}
+	
+	prev := s.bars[len(s.bars)-2]
+	metadata := map[string]interface{}{
+		"ema":        s.ema,
+		"upper_band": upper,
+		"lower_band": lower,
+		"atr":        atr,
+		"mode":       s.mode,
+	}
+	
+	if s.mode == keltnerReversion {
+		// Fade the first bar to reach a band, targeting the midline with the
+		// stop an ATR beyond the band
+		if bar.Low.LessThanOrEqual(lower) && prev.Low.GreaterThan(prevLower) {
+			return s.transition(&Signal{
+				Symbol:      bar.Symbol,
+				Side:        types.OrderSideBuy,
+				Strength:    decimal.NewFromFloat(0.65),
+				StopLoss:    lower.Sub(atr),
+				TakeProfit:  s.ema,
+				Reason:      "Price touched the lower Keltner band",
+				Metadata:    metadata,
+				GeneratedAt: time.Now(),
+			}), nil
+		}
+		if bar.High.GreaterThanOrEqual(upper) && prev.High.LessThan(prevUpper) {
+			return s.transition(&Signal{
+				Symbol:      bar.Symbol,
+				Side:        types.OrderSideSell,
+				Strength:    decimal.NewFromFloat(0.65),
+				StopLoss:    upper.Add(atr),
+				TakeProfit:  s.ema,
+				Reason:      "Price touched the upper Keltner band",
+				Metadata:    metadata,
+				GeneratedAt: time.Now(),
+			}), nil
+		}
+		return nil, nil
+	}
+	
+	// Follow the first close outside the channel, stopped at the midline and
+	// targeting a channel width beyond entry
+	if price.GreaterThan(upper) && prev.Close.LessThanOrEqual(prevUpper) {
+		return s.transition(&Signal{
+			Symbol:      bar.Symbol,
+			Side:        types.OrderSideBuy,
+			Strength:    decimal.NewFromFloat(0.7),
+			StopLoss:    s.ema,
+			TakeProfit:  price.Add(upper.Sub(lower)),
+			Reason:      "Close broke above the Keltner channel",
+			Metadata:    metadata,
+			GeneratedAt: time.Now(),
+		}), nil
+	}
+	if price.LessThan(lower) && prev.Close.GreaterThanOrEqual(prevLower) {
+		return s.transition(&Signal{
+			Symbol:      bar.Symbol,
+			Side:        types.OrderSideSell,
+			Strength:    decimal.NewFromFloat(0.7),
+			StopLoss:    s.ema,
+			TakeProfit:  price.Sub(upper.Sub(lower)),
+			Reason:      "Close broke below the Keltner channel",
+			Metadata:    metadata,
+			GeneratedAt: time.Now(),
+		}), nil
+	}
+	
+	return nil, nil
+}
+
+// MinBars covers the longer of the EMA and ATR lookbacks, plus a bar so the
+// first signal can compare against the previous bands.
+func (s *KeltnerChannelStrategy) MinBars() int {
+	longest := s.emaPeriod
+	if s.atrPeriod+1 > longest {
+		longest = s.atrPeriod + 1
+	}
+	return longest + 1
+}
+
+func (s *KeltnerChannelStrategy) OnTick(tick TickData) (*Signal, error) {
+	return nil, nil
+}
+
+// SuperTrendStrategy trades flips of the SuperTrend indicator: ATR bands
+// around each bar's midpoint that only ratchet toward price, with the trend
+// turning when a close crosses the band on the other side. The band under
+// price in an uptrend, or over it in a downtrend, is the SuperTrend line.
+type SuperTrendStrategy struct {
+	BaseStrategy
+	multiplier decimal.Decimal
+	upperBand  decimal.Decimal
+	lowerBand  decimal.Decimal
+	prevClose  decimal.Decimal
+	uptrend    bool
+	started    bool // The bands have been seeded from a first bar with ATR
+}
+
+// NewSuperTrendStrategy creates a new SuperTrend strategy.
+func NewSuperTrendStrategy(logger *zap.Logger) *SuperTrendStrategy {
+	s := &SuperTrendStrategy{
+		BaseStrategy: BaseStrategy{
+			logger:    logger,
+			params:    make(map[string]StrategyParameter),
+			maxBars:   200,
+			atrPeriod: 10,
+		},
+		multiplier: decimal.NewFromFloat(3.0),
+	}
+	
+	s.params["atr_period"] = StrategyParameter{
+		Name:        "atr_period",
+		Description: "Average True Range period for the bands",
+		Type:        "int",
+		Default:     10,
+		Min:         2,
+		Max:         100,
+		Current:     10,
+	}
+	s.params["multiplier"] = StrategyParameter{
+		Name:        "multiplier",
+		Description: "ATR multiple between each bar's midpoint and the bands",
+		Type:        "float",
+		Default:     3.0,
+		Min:         0.5,
+		Max:         10.0,
+		Current:     3.0,
+	}
+	
+	s.initPositionTracking(false)
+	
+	return s
+}
+
+func (s *SuperTrendStrategy) Name() string { return "supertrend" }
+func (s *SuperTrendStrategy) Description() string {
+	return "Trades SuperTrend flips, with the stop at the opposite ATR band"
+}
+
+func (s *SuperTrendStrategy) SetParameter(name string, value interface{}) error {
+	if err := s.BaseStrategy.SetParameter(name, value); err != nil {
+		return err
+	}
+	if name == "multiplier" {
+		s.multiplier = s.decimalParam(name)
+	}
+	return nil
+}
+
+func (s *SuperTrendStrategy) Initialize(ctx context.Context) error {
+	s.bars = make([]types.OHLCV, 0, s.maxBars)
+	s.resetBands()
+	return nil
+}
+
+// Reset clears the bar buffer along with the bands and trend.
+func (s *SuperTrendStrategy) Reset() {
+	s.BaseStrategy.Reset()
+	s.resetBands()
+}
+
+func (s *SuperTrendStrategy) resetBands() {
+	s.upperBand = decimal.Zero
+	s.lowerBand = decimal.Zero
+	s.prevClose = decimal.Zero
+	s.uptrend = false
+	s.started = false
+}
+
+func (s *SuperTrendStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
+	s.AddBar(bar)
+	
+	atr := s.ATR(s.atrPeriod)
+	if atr.IsZero() {
+		return nil, nil
+	}
+	
+	midpoint := bar.High.Add(bar.Low).Div(decimal.NewFromInt(2))
+	offset := atr.Mul(s.multiplier)
+	upper := midpoint.Add(offset)
+	lower := midpoint.Sub(offset)
+	
+	if !s.started {
+		s.upperBand, s.lowerBand = upper, lower
+		s.uptrend = bar.Close.GreaterThan(midpoint)
+		s.prevClose = bar.Close
+		s.started = true
+		return nil, nil
+	}
+	
+	// A band only moves toward price, unless the last close broke through it
+	if upper.LessThan(s.upperBand) || s.prevClose.GreaterThan(s.upperBand) {
+		s.upperBand = upper
+	}
+	if lower.GreaterThan(s.lowerBand) || s.prevClose.LessThan(s.lowerBand) {
+		s.lowerBand = lower
+	}
+	s.prevClose = bar.Close
+	
+	wasUptrend := s.uptrend
+	if s.uptrend && bar.Close.LessThan(s.lowerBand) {
+		s.uptrend = false
+	} else if !s.uptrend && bar.Close.GreaterThan(s.upperBand) {
+		s.uptrend = true
+	}
+	
+	superTrend, trend := s.upperBand, "down"
+	if s.uptrend {
+		superTrend, trend = s.lowerBand, "up"
+	}
+	metadata := map[string]interface{}{
+		"supertrend": superTrend,
+		"trend":      trend,
+		"upper_band": s.upperBand,
+		"lower_band": s.lowerBand,
+		"atr":        atr,
+	}
+	
+	if s.uptrend == wasUptrend {
+		return nil, nil
+	}
+	
+	// The stop is the band on the other side of price, with the target twice
+	// as far away
+	current := bar.Close
+	if s.uptrend {
+		return s.transition(&Signal{
+			Symbol:      bar.Symbol,
+			Side:        types.OrderSideBuy,
+			Strength:    decimal.NewFromFloat(0.7),
+			StopLoss:    s.lowerBand,
+			TakeProfit:  current.Add(current.Sub(s.lowerBand).Mul(decimal.NewFromInt(2))),
+			Reason:      "Close crossed above the SuperTrend",
+			Metadata:    metadata,
+			GeneratedAt: time.Now(),
+		}), nil
+	}
+	return s.transition(&Signal{
+		Symbol:      bar.Symbol,
+		Side:        types.OrderSideSell,
+		Strength:    decimal.NewFromFloat(0.7),
+		StopLoss:    s.upperBand,
+		TakeProfit:  current.Sub(s.upperBand.Sub(current).Mul(decimal.NewFromInt(2))),
+		Reason:      "Close crossed below the SuperTrend",
+		Metadata:    metadata,
+		GeneratedAt: time.Now(),
+	}), nil
+}
+
+// MinBars covers the ATR period, the bar that seeds the bands and one bar
+// that can flip them.
+func (s *SuperTrendStrategy) MinBars() int { return s.atrPeriod + 2 }
+
+func (s *SuperTrendStrategy) OnTick(tick TickData) (*Signal, error) {
+	return nil, nil
+}
+
+// EnsembleMember is a sub-strategy and its vote weight within an ensemble.
+type EnsembleMember struct {
+	Strategy Strategy
+	Weight   decimal.Decimal
+}
+
+// EnsembleStrategy emits a signal only when a weighted majority of its
+// sub-strategies agree on direction.
+type EnsembleStrategy struct {
+	BaseStrategy
+	members      []EnsembleMember
+	minAgreement decimal.Decimal
+}
+
+// NewEnsembleStrategy creates a new ensemble strategy over the given members.
+func NewEnsembleStrategy(logger *zap.Logger, members []EnsembleMember) *EnsembleStrategy {
+	s := &EnsembleStrategy{
+		BaseStrategy: BaseStrategy{
+			logger:  logger,
+			params:  make(map[string]StrategyParameter),
+			maxBars: 1,
+		},
+		members:      members,
+		minAgreement: decimal.NewFromFloat(0.6),
+	}
+	
+	s.params["min_agreement"] = StrategyParameter{
+		Name:        "min_agreement",
+		Description: "Minimum share of total member weight that must vote for the winning side",
+		Type:        "float",
+		Default:     0.6,
+		Min:         0.5,
+		Max:         1.0,
+		Current:     0.6,
+	}
+	
+	// Members vote their view on every bar; only the ensemble's own signals
+	// are limited to position changes
+	for _, m := range members {
+		if tracked, ok := m.Strategy.(interface{ setAllowPyramiding(bool) }); ok {
+			tracked.setAllowPyramiding(true)
+		}
+	}
+	s.initPositionTracking(false)
+	
+	return s
+}
+
+func (s *EnsembleStrategy) Name() string { return "ensemble" }
+func (s *EnsembleStrategy) Description() string {
+	return "Weighted vote across multiple strategies"
+}
+
+func (s *EnsembleStrategy) SetParameter(name string, value interface{}) error {
+	if err := s.BaseStrategy.SetParameter(name, value); err != nil {
+		return err
+	}
+	if name == "min_agreement" {
+		s.minAgreement = s.decimalParam(name)
+	}
+	return nil
+}
+
+func (s *EnsembleStrategy) Initialize(ctx context.Context) error {
+	for _, m := range s.members {
+		if err := m.Strategy.Initialize(ctx); err != nil {
+			return fmt.Errorf("failed to initialize %s: %w", m.Strategy.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Reset resets every member strategy.
+func (s *EnsembleStrategy) Reset() {
+	s.BaseStrategy.Reset()
+	for _, m := range s.members {
+		m.Strategy.Reset()
+	}
+}
+
+func (s *EnsembleStrategy) OnBar(bar types.OHLCV) (*Signal, error) {
+	signals := make([]*Signal, len(s.members))
+	for i, m := range s.members {
+		signal, err := m.Strategy.OnBar(bar)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", m.Strategy.Name(), err)
+		}
+		signals[i] = signal
+	}
+	return s.transition(s.vote(signals)), nil
+}
+
+func (s *EnsembleStrategy) OnTick(tick TickData) (*Signal, error) {
+	signals := make([]*Signal, len(s.members))
+	for i, m := range s.members {
+		signal, err := m.Strategy.OnTick(tick)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", m.Strategy.Name(), err)
+		}
+		signals[i] = signal
+	}
+	return s.transition(s.vote(signals)), nil
+}
+
+// MinBars returns the longest warmup across member strategies.
+func (s *EnsembleStrategy) MinBars() int {
+	minBars := 0
+	for _, m := range s.members {
+		if n := m.Strategy.MinBars(); n > minBars {
+			minBars = n
+		}
+	}
+	return minBars
+}
+
+// vote combines member signals (nil for an abstention) into one signal.
+func (s *EnsembleStrategy) vote(signals []*Signal) *Signal {
+	totalWeight := decimal.Zero
+	buyWeight := decimal.Zero
+	sellWeight := decimal.Zero
+	votes := make([]map[string]interface{}, 0, len(s.members))
+	
+	for i, m := range s.members {
+		totalWeight = totalWeight.Add(m.Weight)
+		
+		vote := map[string]interface{}{
+			"strategy": m.Strategy.Name(),
+			"weight":   m.Weight,
+			"side":     "none",
+		}
+		if signal := signals[i]; signal != nil {
+			vote["side"] = signal.Side
+			vote["strength"] = signal.Strength
+			if signal.Side == types.OrderSideBuy {
+				buyWeight = buyWeight.Add(m.Weight)
+			} else {
+				sellWeight = sellWeight.Add(m.Weight)
+			}
+		}
+		votes = append(votes, vote)
+	}
+	
+	if totalWeight.IsZero() || buyWeight.Equal(sellWeight) {
+		return nil
+	}
+	
+	side := types.OrderSideBuy
+	sideWeight := buyWeight
+	if sellWeight.GreaterThan(buyWeight) {
+		side = types.OrderSideSell
+		sideWeight = sellWeight
+	}
+	
+	agreement := sideWeight.Div(totalWeight)
+	if agreement.LessThan(s.minAgreement) {
+		return nil
+	}
+	
+	// Weighted averages over the members voting for the winning side
+	var symbol string
+	strength := decimal.Zero
+	stopLoss, stopWeight := decimal.Zero, decimal.Zero
+	takeProfit, targetWeight := decimal.Zero, decimal.Zero
+	for i, m := range s.members {
+		signal := signals[i]
+		if signal == nil || signal.Side != side {
+			continue
+		}
+		if symbol == "" {
+			symbol = signal.Symbol
+		}
+		strength = strength.Add(signal.Strength.Mul(m.Weight))
+		if !signal.StopLoss.IsZero() {
+			stopLoss = stopLoss.Add(signal.StopLoss.Mul(m.Weight))
+			stopWeight = stopWeight.Add(m.Weight)
+		}
+		if !signal.TakeProfit.IsZero() {
+			takeProfit = takeProfit.Add(signal.TakeProfit.Mul(m.Weight))
+			targetWeight = targetWeight.Add(m.Weight)
+		}
+	}
+	strength = strength.Div(sideWeight).Mul(agreement)
+	if !stopWeight.IsZero() {
+		stopLoss = stopLoss.Div(stopWeight)
+	}
+	if !targetWeight.IsZero() {
+		takeProfit = takeProfit.Div(targetWeight)
+	}
+	
+	return &Signal{
+		Symbol:     symbol,
+		Side:       side,
+		Strength:   strength,
+		StopLoss:   stopLoss,
+		TakeProfit: takeProfit,
+		Reason:     fmt.Sprintf("Ensemble %s consensus (%s%% agreement)", side, agreement.Mul(decimal.NewFromInt(100)).StringFixed(0)),
+		Metadata: map[string]interface{}{
+			"agreement": agreement,
+			"votes":     votes,
+		},
+		GeneratedAt: time.Now(),
+	}
+}
+
+// sqrtEpsilon is the relative change between Newton iterations below which
+// sqrtDecimal considers the result converged.
+var sqrtEpsilon = decimal.New(1, -18)
+
+// sqrtMaxIterations caps Newton iterations in case convergence stalls.
+const sqrtMaxIterations = 50
+
+// Helper: sqrt using Newton's method
+func sqrtDecimal(d decimal.Decimal) decimal.Decimal {
+	return sqrtDecimalWithEpsilon(d, sqrtEpsilon)
+}
+
+// sqrtDecimalWithEpsilon computes the square root of d, iterating until the
+// relative change between Newton steps drops below epsilon.
+func sqrtDecimalWithEpsilon(d, epsilon decimal.Decimal) decimal.Decimal {
+	if d.IsZero() || d.IsNegative() {
+		return decimal.Zero
+	}
+	
+	// d = coefficient * 10^exponent, so its base-10 magnitude is known exactly.
+	// Starting from 10^(magnitude/2) puts the first guess within a factor of
+	// ~3 of the root, after which Newton converges quadratically.
+	magnitude := int32(len(d.Coefficient().String())) - 1 + d.Exponent()
+	x := decimal.New(1, magnitude/2)
+	
+	// Keep a fixed number of significant digits regardless of magnitude
+	scale := 24 - magnitude/2
+	two := decimal.NewFromInt(2)
+	
+	for i := 0; i < sqrtMaxIterations; i++ {
+		next := x.Add(d.DivRound(x, scale)).DivRound(two, scale)
+		delta := next.Sub(x).Abs()
+		x = next
+		if delta.LessThanOrEqual(x.Mul(epsilon)) {
+			break
+		}
 	}
 	return x
 }
@@ -0,0 +1,635 @@
+// Package strategy_test provides tests for trading strategies.
+package strategy_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/atlas-desktop/trading-backend/internal/strategy"
+	"github.com/atlas-desktop/trading-backend/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func TestSetParameterCoercesJSONFloatToInt(t *testing.T) {
+	s := strategy.NewMomentumStrategy(zap.NewNop())
+
+	// HTTP handlers decode numbers into float64
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"period": 20}`), &payload); err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+
+	if err := s.SetParameter("period", payload["period"]); err != nil {
+		t.Fatalf("SetParameter failed: %v", err)
+	}
+
+	current, ok := s.Parameters()["period"].Current.(int)
+	if !ok {
+		t.Fatalf("Expected int current value, got %T", s.Parameters()["period"].Current)
+	}
+	if current != 20 {
+		t.Errorf("Expected period 20, got %d", current)
+	}
+}
+
+func TestSetParameterRejectsInvalidValues(t *testing.T) {
+	s := strategy.NewMomentumStrategy(zap.NewNop())
+
+	tests := []struct {
+		name  string
+		param string
+		value interface{}
+	}{
+		{"string for int", "period", "fourteen"},
+		{"non-integral for int", "period", 14.5},
+		{"below minimum", "period", 1},
+		{"above maximum", "threshold", 0.5},
+		{"bool for float", "threshold", true},
+		{"unknown parameter", "does_not_exist", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.SetParameter(tt.param, tt.value); err == nil {
+				t.Errorf("Expected error setting %s to %v", tt.param, tt.value)
+			}
+		})
+	}
+
+	// Rejected values must not change the current value
+	if current := s.Parameters()["period"].Current; current != 14 {
+		t.Errorf("Expected period to remain 14, got %v", current)
+	}
+}
+
+func TestSetParameterAppliesToStrategy(t *testing.T) {
+	s := strategy.NewMomentumStrategy(zap.NewNop())
+
+	if err := s.SetParameter("period", float64(5)); err != nil {
+		t.Fatalf("SetParameter failed: %v", err)
+	}
+
+	// With the default period of 14, five bars would never produce a signal
+	var signal *strategy.Signal
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		price := decimal.NewFromInt(int64(100 + i*10))
+		var err error
+		signal, err = s.OnBar(types.OHLCV{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Open:      price,
+			High:      price,
+			Low:       price,
+			Close:     price,
+			Volume:    decimal.NewFromInt(1000),
+		})
+		if err != nil {
+			t.Fatalf("OnBar failed: %v", err)
+		}
+	}
+
+	if signal == nil {
+		t.Fatal("Expected a signal after period bars with the updated period")
+	}
+	if signal.Side != types.OrderSideBuy {
+		t.Errorf("Expected buy signal, got %s", signal.Side)
+	}
+}
+
+func TestATRStopsScaleWithVolatility(t *testing.T) {
+	s := strategy.NewMomentumStrategy(zap.NewNop())
+	if err := s.SetParameter("period", 5); err != nil {
+		t.Fatalf("SetParameter failed: %v", err)
+	}
+	// Keep signalling on every bar so the last one carries the stops checked
+	if err := s.SetParameter("allow_pyramiding", true); err != nil {
+		t.Fatalf("SetParameter failed: %v", err)
+	}
+
+	// Each bar rises by 2 with a 2-wide range, so every true range is 3
+	var signal *strategy.Signal
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		closePrice := decimal.NewFromInt(int64(100 + i*2))
+		var err error
+		signal, err = s.OnBar(types.OHLCV{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Open:      closePrice,
+			High:      closePrice.Add(decimal.NewFromInt(1)),
+			Low:       closePrice.Sub(decimal.NewFromInt(1)),
+			Close:     closePrice,
+			Volume:    decimal.NewFromInt(1000),
+		})
+		if err != nil {
+			t.Fatalf("OnBar failed: %v", err)
+		}
+	}
+
+	if atr := s.ATR(14); !atr.Equal(decimal.NewFromInt(3)) {
+		t.Fatalf("Expected ATR of 3, got %s", atr)
+	}
+	if signal == nil {
+		t.Fatal("Expected a momentum signal")
+	}
+
+	entry := decimal.NewFromInt(138)
+	if !signal.StopLoss.Equal(entry.Sub(decimal.NewFromInt(6))) {
+		t.Errorf("Expected stop loss at entry - 2 ATR, got %s", signal.StopLoss)
+	}
+	if !signal.TakeProfit.Equal(entry.Add(decimal.NewFromInt(9))) {
+		t.Errorf("Expected take profit at entry + 3 ATR, got %s", signal.TakeProfit)
+	}
+}
+
+func TestMomentumSignalsOnlyPositionChanges(t *testing.T) {
+	run := func(allowPyramiding bool) (*strategy.MomentumStrategy, []types.OrderSide) {
+		s := strategy.NewMomentumStrategy(zap.NewNop())
+		for name, value := range map[string]interface{}{"period": 5, "allow_pyramiding": allowPyramiding} {
+			if err := s.SetParameter(name, value); err != nil {
+				t.Fatalf("SetParameter(%s) failed: %v", name, err)
+			}
+		}
+
+		// Ten bars rising by 5%, then ten falling by 5%
+		var sides []types.OrderSide
+		price := 100.0
+		for i := 0; i < 20; i++ {
+			if i < 10 {
+				price *= 1.05
+			} else {
+				price *= 0.95
+			}
+			signal, err := s.OnBar(types.OHLCV{Close: decimal.NewFromFloat(price)})
+			if err != nil {
+				t.Fatalf("OnBar failed: %v", err)
+			}
+			if signal != nil {
+				sides = append(sides, signal.Side)
+			}
+		}
+		return s, sides
+	}
+
+	s, sides := run(false)
+	if len(sides) != 2 || sides[0] != types.OrderSideBuy || sides[1] != types.OrderSideSell {
+		t.Fatalf("Expected one buy then one sell, got %v", sides)
+	}
+	if s.Position() != strategy.PositionShort {
+		t.Errorf("Expected to end short, got %s", s.Position())
+	}
+
+	// Flattened by a stop, the strategy can signal the same side again
+	s.SetPosition(strategy.PositionFlat)
+	if signal, _ := s.OnBar(types.OHLCV{Close: decimal.NewFromInt(40)}); signal == nil || signal.Side != types.OrderSideSell {
+		t.Errorf("Expected a sell once flat, got %+v", signal)
+	}
+
+	if _, sides := run(true); len(sides) < 10 {
+		t.Errorf("Expected a signal on most bars with pyramiding allowed, got %v", sides)
+	}
+}
+
+// fixedStrategy returns the same signal on every bar, warm or not.
+type fixedStrategy struct {
+	strategy.BaseStrategy
+	name    string
+	signal  *strategy.Signal
+	minBars int
+}
+
+func (f *fixedStrategy) Name() string                                    { return f.name }
+func (f *fixedStrategy) Description() string                             { return "fixed test strategy" }
+func (f *fixedStrategy) Initialize(ctx context.Context) error            { return nil }
+func (f *fixedStrategy) MinBars() int                                    { return f.minBars }
+func (f *fixedStrategy) OnBar(bar types.OHLCV) (*strategy.Signal, error) { return f.signal, nil }
+func (f *fixedStrategy) OnTick(tick strategy.TickData) (*strategy.Signal, error) {
+	return f.signal, nil
+}
+
+func voter(name string, side types.OrderSide) *fixedStrategy {
+	return &fixedStrategy{
+		name: name,
+		signal: &strategy.Signal{
+			Symbol:   "BTCUSDT",
+			Side:     side,
+			Strength: decimal.NewFromFloat(0.8),
+		},
+	}
+}
+
+func TestEnsembleWeightedMajority(t *testing.T) {
+	ensemble := strategy.NewEnsembleStrategy(zap.NewNop(), []strategy.EnsembleMember{
+		{Strategy: voter("a", types.OrderSideBuy), Weight: decimal.NewFromInt(2)},
+		{Strategy: voter("b", types.OrderSideBuy), Weight: decimal.NewFromInt(1)},
+		{Strategy: voter("c", types.OrderSideSell), Weight: decimal.NewFromInt(1)},
+	})
+
+	signal, err := ensemble.OnBar(types.OHLCV{Close: decimal.NewFromInt(100)})
+	if err != nil {
+		t.Fatalf("OnBar failed: %v", err)
+	}
+	if signal == nil {
+		t.Fatal("Expected ensemble signal")
+	}
+	if signal.Side != types.OrderSideBuy {
+		t.Errorf("Expected buy, got %s", signal.Side)
+	}
+	// 75% agreement scales the 0.8 member strength down to 0.6
+	if !signal.Strength.Equal(decimal.NewFromFloat(0.6)) {
+		t.Errorf("Expected strength 0.6, got %s", signal.Strength)
+	}
+	if votes, ok := signal.Metadata["votes"].([]map[string]interface{}); !ok || len(votes) != 3 {
+		t.Errorf("Expected three votes in metadata, got %v", signal.Metadata["votes"])
+	}
+}
+
+func TestEnsembleConflictProducesNoSignal(t *testing.T) {
+	ensemble := strategy.NewEnsembleStrategy(zap.NewNop(), []strategy.EnsembleMember{
+		{Strategy: voter("a", types.OrderSideBuy), Weight: decimal.NewFromInt(1)},
+		{Strategy: voter("b", types.OrderSideSell), Weight: decimal.NewFromInt(1)},
+	})
+
+	signal, err := ensemble.OnBar(types.OHLCV{Close: decimal.NewFromInt(100)})
+	if err != nil {
+		t.Fatalf("OnBar failed: %v", err)
+	}
+	if signal != nil {
+		t.Errorf("Expected no signal on an evenly split vote, got %s", signal.Side)
+	}
+}
+
+func TestRegistryCreatesEnsemble(t *testing.T) {
+	registry := strategy.NewStrategyRegistry(zap.NewNop())
+
+	s, ok := registry.Create("ensemble")
+	if !ok {
+		t.Fatal("Ensemble strategy not registered")
+	}
+	if s.Name() != "ensemble" {
+		t.Errorf("Expected ensemble, got %s", s.Name())
+	}
+
+	if _, err := registry.CreateEnsemble([]string{"momentum", "missing"}, []float64{1, 1}); err == nil {
+		t.Error("Expected error for unregistered member strategy")
+	}
+}
+
+func TestMinBarsReflectsLongestLookback(t *testing.T) {
+	registry := strategy.NewStrategyRegistry(zap.NewNop())
+
+	trend, _ := registry.Create("trend_following")
+	if err := trend.SetParameter("slow_period", 40); err != nil {
+		t.Fatalf("SetParameter failed: %v", err)
+	}
+	if trend.MinBars() != 40 {
+		t.Errorf("Expected trend following to need 40 bars, got %d", trend.MinBars())
+	}
+
+	ensemble, err := registry.CreateEnsemble([]string{"momentum", "trend_following"}, []float64{1, 1})
+	if err != nil {
+		t.Fatalf("CreateEnsemble failed: %v", err)
+	}
+	if ensemble.MinBars() != 26 {
+		t.Errorf("Expected ensemble to need 26 bars, got %d", ensemble.MinBars())
+	}
+}
+
+// gridBars feeds closing prices to a grid strategy and returns its signals.
+func gridBars(t *testing.T, s *strategy.GridStrategy, closes ...float64) []*strategy.Signal {
+	t.Helper()
+	signals := make([]*strategy.Signal, len(closes))
+	for i, c := range closes {
+		price := decimal.NewFromFloat(c)
+		signal, err := s.OnBar(types.OHLCV{Symbol: "BTCUSDT", Open: price, High: price, Low: price, Close: price})
+		if err != nil {
+			t.Fatalf("OnBar failed: %v", err)
+		}
+		signals[i] = signal
+	}
+	return signals
+}
+
+func TestGridClosesLotsAtAdjacentLevel(t *testing.T) {
+	s := strategy.NewGridStrategy(zap.NewNop())
+	signals := gridBars(t, s, 100, 99, 99.5, 100, 100, 99)
+
+	buy := signals[1]
+	if buy == nil || buy.Side != types.OrderSideBuy || buy.Metadata["grid_action"] != "open" {
+		t.Fatalf("Expected a grid buy opening at 99, got %+v", buy)
+	}
+	if !buy.TakeProfit.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected take profit at the base price, got %s", buy.TakeProfit)
+	}
+	if lots := buy.Metadata["open_lots"].([]map[string]interface{}); len(lots) != 1 {
+		t.Errorf("Expected one open lot, got %v", lots)
+	}
+
+	if signals[2] != nil {
+		t.Errorf("Expected no signal between levels, got %s", signals[2].Side)
+	}
+
+	exit := signals[3]
+	if exit == nil || exit.Side != types.OrderSideSell || exit.Metadata["grid_action"] != "close" {
+		t.Fatalf("Expected a sell closing the lot at 100, got %+v", exit)
+	}
+	if lots := exit.Metadata["open_lots"].([]map[string]interface{}); len(lots) != 0 {
+		t.Errorf("Expected no open lots after the close, got %v", lots)
+	}
+
+	// The closed level is armed again
+	if signals[4] != nil {
+		t.Errorf("Expected no signal at the base price, got %s", signals[4].Side)
+	}
+	if again := signals[5]; again == nil || again.Side != types.OrderSideBuy {
+		t.Fatalf("Expected the level to reopen, got %+v", again)
+	}
+}
+
+func TestGridRecentersBeyondOutermostLevel(t *testing.T) {
+	s := strategy.NewGridStrategy(zap.NewNop())
+	if err := s.SetParameter("recenter_threshold", 0.01); err != nil {
+		t.Fatalf("SetParameter failed: %v", err)
+	}
+
+	// The lowest level is 95, so 90 is more than 1% beyond it
+	signals := gridBars(t, s, 100, 90, 89.1)
+	if signals[1] != nil {
+		t.Errorf("Expected no signal on the recentering bar, got %s", signals[1].Side)
+	}
+	buy := signals[2]
+	if buy == nil || buy.Side != types.OrderSideBuy {
+		t.Fatalf("Expected a buy on the recentered grid, got %+v", buy)
+	}
+	if base := buy.Metadata["base_price"].(decimal.Decimal); !base.Equal(decimal.NewFromInt(90)) {
+		t.Errorf("Expected base price 90, got %s", base)
+	}
+}
+
+func TestGridGeometricSpacing(t *testing.T) {
+	s := strategy.NewGridStrategy(zap.NewNop())
+	if err := s.SetParameter("geometric", true); err != nil {
+		t.Fatalf("SetParameter failed: %v", err)
+	}
+
+	signals := gridBars(t, s, 100, 99)
+	if signals[1] == nil {
+		t.Fatal("Expected a grid buy")
+	}
+	level := signals[1].Metadata["grid_level"].(decimal.Decimal)
+	want := decimal.NewFromInt(100).Div(decimal.NewFromFloat(1.01))
+	if !level.Equal(want) {
+		t.Errorf("Expected level at 100/1.01, got %s", level)
+	}
+	if signals[1].Metadata["spacing"] != "geometric" {
+		t.Errorf("Expected geometric spacing, got %v", signals[1].Metadata["spacing"])
+	}
+}
+
+func TestSuperTrendFlipsWithStopAtOppositeBand(t *testing.T) {
+	registry := strategy.NewStrategyRegistry(zap.NewNop())
+	s, ok := registry.Create("supertrend")
+	if !ok {
+		t.Fatal("Expected supertrend to be registered")
+	}
+	if err := s.SetParameter("atr_period", 3); err != nil {
+		t.Fatalf("SetParameter failed: %v", err)
+	}
+	if err := s.SetParameter("multiplier", 1.0); err != nil {
+		t.Fatalf("SetParameter failed: %v", err)
+	}
+	if s.MinBars() != 5 {
+		t.Errorf("Expected 5 bars of warmup, got %d", s.MinBars())
+	}
+
+	// A steady decline, then a rally through the upper band, then a collapse
+	closes := []float64{110, 108, 106, 104, 102, 100, 98, 96, 104, 108, 112, 116, 100}
+	var signals []*strategy.Signal
+	for _, c := range closes {
+		price := decimal.NewFromFloat(c)
+		signal, err := s.OnBar(types.OHLCV{
+			Open:  price,
+			High:  price.Add(decimal.NewFromInt(1)),
+			Low:   price.Sub(decimal.NewFromInt(1)),
+			Close: price,
+		})
+		if err != nil {
+			t.Fatalf("OnBar failed: %v", err)
+		}
+		if signal != nil {
+			signals = append(signals, signal)
+		}
+	}
+
+	if len(signals) != 2 {
+		t.Fatalf("Expected a buy and a sell, got %d signals", len(signals))
+	}
+
+	buy, sell := signals[0], signals[1]
+	if buy.Side != types.OrderSideBuy || buy.Metadata["trend"] != "up" {
+		t.Fatalf("Expected a buy on the flip up, got %+v", buy)
+	}
+	if lower := buy.Metadata["lower_band"].(decimal.Decimal); !buy.StopLoss.Equal(lower) || !lower.LessThan(decimal.NewFromInt(104)) {
+		t.Errorf("Expected the buy stop at the lower band under 104, got %s", buy.StopLoss)
+	}
+	if line := buy.Metadata["supertrend"].(decimal.Decimal); !line.Equal(buy.StopLoss) {
+		t.Errorf("Expected the SuperTrend line on the lower band in an uptrend, got %s", line)
+	}
+
+	if sell.Side != types.OrderSideSell || sell.Metadata["trend"] != "down" {
+		t.Fatalf("Expected a sell on the flip down, got %+v", sell)
+	}
+	if upper := sell.Metadata["upper_band"].(decimal.Decimal); !sell.StopLoss.Equal(upper) || !upper.GreaterThan(decimal.NewFromInt(100)) {
+		t.Errorf("Expected the sell stop at the upper band over 100, got %s", sell.StopLoss)
+	}
+
+	s.Reset()
+	if signal, _ := s.OnBar(types.OHLCV{Open: decimal.NewFromInt(100), High: decimal.NewFromInt(101), Low: decimal.NewFromInt(99), Close: decimal.NewFromInt(100)}); signal != nil {
+		t.Error("Expected no signal right after Reset")
+	}
+}
+
+func TestKeltnerChannelBreakoutAndReversion(t *testing.T) {
+	registry := strategy.NewStrategyRegistry(zap.NewNop())
+
+	// A quiet range around 100, then a bar closing at 106
+	run := func(mode string) []*strategy.Signal {
+		s, ok := registry.Create("keltner")
+		if !ok {
+			t.Fatal("Expected keltner to be registered")
+		}
+		for name, value := range map[string]interface{}{"ema_period": 5, "atr_period": 3, "band_mult": 1.0, "mode": mode} {
+			if err := s.SetParameter(name, value); err != nil {
+				t.Fatalf("SetParameter(%s) failed: %v", name, err)
+			}
+		}
+
+		var signals []*strategy.Signal
+		for _, c := range []float64{100, 100, 100, 100, 100, 100, 100, 100, 106} {
+			price := decimal.NewFromFloat(c)
+			signal, err := s.OnBar(types.OHLCV{
+				Open:  price,
+				High:  price.Add(decimal.NewFromInt(1)),
+				Low:   price.Sub(decimal.NewFromInt(1)),
+				Close: price,
+			})
+			if err != nil {
+				t.Fatalf("OnBar failed: %v", err)
+			}
+			if signal != nil {
+				signals = append(signals, signal)
+			}
+		}
+		return signals
+	}
+
+	signals := run("breakout")
+	if len(signals) != 1 || signals[0].Side != types.OrderSideBuy {
+		t.Fatalf("Expected a breakout buy, got %+v", signals)
+	}
+	buy := signals[0]
+	if ema := buy.Metadata["ema"].(decimal.Decimal); !buy.StopLoss.Equal(ema) || !buy.TakeProfit.GreaterThan(decimal.NewFromInt(106)) {
+		t.Errorf("Expected the stop at the midline %s and a target above entry, got %s and %s", ema, buy.StopLoss, buy.TakeProfit)
+	}
+
+	signals = run("reversion")
+	if len(signals) != 1 || signals[0].Side != types.OrderSideSell {
+		t.Fatalf("Expected the band touch to be faded with a sell, got %+v", signals)
+	}
+	sell := signals[0]
+	upper := sell.Metadata["upper_band"].(decimal.Decimal)
+	if !sell.TakeProfit.Equal(sell.Metadata["ema"].(decimal.Decimal)) || !sell.StopLoss.GreaterThan(upper) {
+		t.Errorf("Expected a target at the midline and a stop beyond the band, got %s and %s", sell.TakeProfit, sell.StopLoss)
+	}
+
+	s, _ := registry.Create("keltner")
+	if err := s.SetParameter("mode", "momentum"); err == nil {
+		t.Error("Expected an unknown mode to be rejected")
+	}
+}
+
+func TestVWAPResetsAtSessionAndExplicitAnchors(t *testing.T) {
+	registry := strategy.NewStrategyRegistry(zap.NewNop())
+	created, _ := registry.Create("vwap_reversion")
+	s := created.(*strategy.VWAPReversionStrategy)
+
+	bar := func(at time.Time, price float64) types.OHLCV {
+		p := decimal.NewFromFloat(price)
+		return types.OHLCV{
+			Timestamp: at,
+			Open:      p,
+			High:      p.Add(decimal.NewFromFloat(0.5)),
+			Low:       p.Sub(decimal.NewFromFloat(0.5)),
+			Close:     p,
+			Volume:    decimal.NewFromInt(10),
+		}
+	}
+
+	// A day trading around 200, then a new session trading around 100
+	day := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 20; i++ {
+		s.OnBar(bar(day.Add(time.Duration(i)*time.Hour), 200+float64(i%2)))
+	}
+	next := day.AddDate(0, 0, 1)
+	var signal *strategy.Signal
+	for i := 0; i < 12; i++ {
+		signal, _ = s.OnBar(bar(next.Add(time.Duration(i)*time.Hour), 100+float64(i%2)))
+		if signal != nil {
+			t.Fatalf("Expected no signal around the new session's VWAP, got %s at bar %d", signal.Side, i)
+		}
+	}
+	if !s.AnchorStart().Equal(next) {
+		t.Errorf("Expected the session anchored at %s, got %s", next, s.AnchorStart())
+	}
+
+	// A drop well below the session's bands is a buy targeting its VWAP
+	signal, _ = s.OnBar(bar(next.Add(12*time.Hour), 95))
+	if signal == nil || signal.Side != types.OrderSideBuy {
+		t.Fatalf("Expected a buy below the session VWAP, got %+v", signal)
+	}
+	if vwap := signal.TakeProfit.InexactFloat64(); vwap < 99 || vwap > 101 {
+		t.Errorf("Expected a target at the session VWAP near 100, got %v", vwap)
+	}
+	if signal.Metadata["anchor_at"] != next {
+		t.Errorf("Expected the anchor time in metadata, got %v", signal.Metadata["anchor_at"])
+	}
+
+	// Anchored at a swing low, earlier bars no longer count
+	swing := next.Add(12 * time.Hour)
+	if err := s.SetParameter("anchor", swing.Format(time.RFC3339)); err != nil {
+		t.Fatalf("SetParameter failed: %v", err)
+	}
+	if signal, _ := s.OnBar(bar(swing.Add(-time.Hour), 50)); signal != nil || !s.AnchorStart().IsZero() {
+		t.Error("Expected a bar before the anchor to be ignored")
+	}
+	s.OnBar(bar(swing, 95))
+	if !s.AnchorStart().Equal(swing) {
+		t.Errorf("Expected the VWAP anchored at %s, got %s", swing, s.AnchorStart())
+	}
+
+	if err := s.SetParameter("anchor", "monthly"); err == nil {
+		t.Error("Expected an unknown anchor to be rejected")
+	}
+}
+
+func TestIchimokuChikouConfirmsCross(t *testing.T) {
+	// Flat, a dip, then a rally whose Tenkan/Kijun cross clears the cloud on
+	// bar 50; the Chikou span compares that close with bar 40's
+	run := func(laggedClose float64) []types.OrderSide {
+		s := strategy.NewIchimokuStrategy(zap.NewNop())
+		for name, value := range map[string]interface{}{"tenkan_period": 5, "kijun_period": 10, "senkou_b_period": 20} {
+			if err := s.SetParameter(name, value); err != nil {
+				t.Fatalf("SetParameter failed: %v", err)
+			}
+		}
+		if err := s.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize failed: %v", err)
+		}
+
+		var closes []float64
+		for i := 0; i < 40; i++ {
+			closes = append(closes, 100)
+		}
+		closes = append(closes, laggedClose)
+		for i := 0; i < 5; i++ {
+			closes = append(closes, 97)
+		}
+		for i := 1; i <= 15; i++ {
+			closes = append(closes, 97+float64(i*3))
+		}
+
+		var sides []types.OrderSide
+		start := time.Now()
+		for i, c := range closes {
+			closePrice := decimal.NewFromFloat(c)
+			signal, err := s.OnBar(types.OHLCV{
+				Timestamp: start.Add(time.Duration(i) * time.Hour),
+				Open:      closePrice,
+				High:      closePrice.Add(decimal.NewFromInt(1)),
+				Low:       closePrice.Sub(decimal.NewFromInt(1)),
+				Close:     closePrice,
+				Volume:    decimal.NewFromInt(1000),
+			})
+			if err != nil {
+				t.Fatalf("OnBar failed: %v", err)
+			}
+			if signal != nil {
+				sides = append(sides, signal.Side)
+				if i == 50 && !signal.Metadata["chikou_price"].(decimal.Decimal).Equal(decimal.NewFromFloat(laggedClose)) {
+					t.Errorf("Expected the Chikou span compared with %v, got %v", laggedClose, signal.Metadata["chikou_price"])
+				}
+			}
+		}
+		return sides
+	}
+
+	if sides := run(97); len(sides) != 1 || sides[0] != types.OrderSideBuy {
+		t.Errorf("Expected one buy with the Chikou span above its lagged price, got %v", sides)
+	}
+	// The same cross with the close 10 bars back above the current one
+	if sides := run(115); len(sides) != 0 {
+		t.Errorf("Expected no signal with the Chikou span below its lagged price, got %v", sides)
+	}
+}
@@ -18,6 +18,7 @@ type BacktestConfig struct {
 	InitialCapital decimal.Decimal `json:"initialCapital"`
 	Commission     decimal.Decimal `json:"commission"`
 	Slippage       SlippageConfig  `json:"slippage"`
+	Fees           *FeeSchedule    `json:"fees,omitempty"` // Maker/taker fees replacing the flat Commission rate
 	RiskLimits     RiskLimits      `json:"riskLimits"`
 	Validation     ValidationConfig `json:"validation"`
 }
@@ -42,10 +43,20 @@ type Rule struct {
 
 // SlippageConfig represents slippage model configuration
 type SlippageConfig struct {
-	Model           string          `json:"model"` // "fixed", "volume_weighted", "orderbook"
+	Model           string          `json:"model"` // "fixed", "volume_weighted", "orderbook", "sqrt_impact", or "calibrated" from live fills
 	FixedBps        decimal.Decimal `json:"fixedBps,omitempty"`
 	ImpactFactor    decimal.Decimal `json:"impactFactor,omitempty"`
 	VolumeFraction  decimal.Decimal `json:"volumeFraction,omitempty"`
+	Symbols         map[string]SlippageConfig `json:"symbols,omitempty"` // Per-symbol models, e.g. calibrated from live fills
+}
+
+// FeeSchedule represents one venue's trading fees
+type FeeSchedule struct {
+	MakerBps decimal.Decimal `json:"makerBps"`           // Fee on fills that rested on the book
+	TakerBps decimal.Decimal `json:"takerBps"`           // Fee on fills that took liquidity
+	MinFee   decimal.Decimal `json:"minFee,omitempty"`   // Minimum fee per fill, in the quote asset
+	FeeAsset string          `json:"feeAsset,omitempty"` // "quote" (default), "base", or a third asset such as "BNB"
+	Discount decimal.Decimal `json:"discount,omitempty"` // Fraction off when fees are paid in a third asset
 }
 
 // RiskLimits represents risk management limits
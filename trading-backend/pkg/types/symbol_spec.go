@@ -0,0 +1,159 @@
+// Package types provides venue precision rules for rounding orders.
+package types
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// SymbolSpec represents a venue's precision and minimums for a symbol.
+// Zero values disable the corresponding rounding or check.
+type SymbolSpec struct {
+	Venue          string          `json:"venue"` // Adapter name the spec was loaded from
+	Symbol         string          `json:"symbol"`
+	TickSize       decimal.Decimal `json:"tickSize"`       // Price increment
+	StepSize       decimal.Decimal `json:"stepSize"`       // Quantity increment
+	MinNotional    decimal.Decimal `json:"minNotional"`    // Smallest order value in the quote asset
+	BasePrecision  int32           `json:"basePrecision"`  // Decimals the venue reports base amounts in
+	QuotePrecision int32           `json:"quotePrecision"` // Decimals the venue reports quote amounts in
+}
+
+// RoundQuantity floors quantity to the step size, so rounding never sizes
+// an order above what risk allowed.
+func (s SymbolSpec) RoundQuantity(quantity decimal.Decimal) decimal.Decimal {
+	if !s.StepSize.IsPositive() {
+		return quantity
+	}
+	return quantity.Div(s.StepSize).Floor().Mul(s.StepSize)
+}
+
+// RoundPrice rounds price to the tick on the passive side for an order of
+// side. See RoundPriceToTick.
+func (s SymbolSpec) RoundPrice(side OrderSide, price decimal.Decimal) decimal.Decimal {
+	return RoundPriceToTick(price, s.TickSize, side)
+}
+
+// RoundPriceToTick rounds price to a multiple of tick away from the market:
+// buy prices floor and sell prices ceil, so rounding never makes a limit
+// more aggressive than requested and never moves a stop further from the
+// entry. A zero tick leaves price unchanged.
+func RoundPriceToTick(price, tick decimal.Decimal, side OrderSide) decimal.Decimal {
+	if !tick.IsPositive() {
+		return price
+	}
+	ticks := price.Div(tick)
+	if side == OrderSideSell {
+		return ticks.Ceil().Mul(tick)
+	}
+	return ticks.Floor().Mul(tick)
+}
+
+// MeetsMinNotional reports whether an order of quantity at price is worth
+// at least the minimum notional. A zero price can't be checked and passes.
+func (s SymbolSpec) MeetsMinNotional(quantity, price decimal.Decimal) bool {
+	if !s.MinNotional.IsPositive() || !price.IsPositive() {
+		return true
+	}
+	return quantity.Mul(price).GreaterThanOrEqual(s.MinNotional)
+}
+
+// SymbolSpecRegistry holds the specs adapters load from exchange info, one
+// per venue and symbol. Symbols are keyed without separators or case, so
+// "BTC/USDT" and "BTCUSDT" share a spec.
+type SymbolSpecRegistry struct {
+	mu    sync.RWMutex
+	specs map[string]map[string]SymbolSpec // venue -> symbol key -> spec
+}
+
+// NewSymbolSpecRegistry creates an empty registry.
+func NewSymbolSpecRegistry() *SymbolSpecRegistry {
+	return &SymbolSpecRegistry{specs: make(map[string]map[string]SymbolSpec)}
+}
+
+// Set adds or replaces specs under their venue.
+func (r *SymbolSpecRegistry) Set(specs ...SymbolSpec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, spec := range specs {
+		venue := strings.ToLower(spec.Venue)
+		if r.specs[venue] == nil {
+			r.specs[venue] = make(map[string]SymbolSpec)
+		}
+		r.specs[venue][symbolSpecKey(spec.Symbol)] = spec
+	}
+}
+
+// Get returns a symbol's spec on a venue, or false if that venue's adapter
+// has not loaded it. An empty venue, for sizing before an order is routed,
+// returns the coarsest spec across every venue listing the symbol: the
+// largest tick, step and minimum notional, so an order rounded with it is
+// accepted wherever it is sent.
+func (r *SymbolSpecRegistry) Get(venue, symbol string) (SymbolSpec, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key := symbolSpecKey(symbol)
+	if venue != "" {
+		spec, ok := r.specs[strings.ToLower(venue)][key]
+		return spec, ok
+	}
+
+	var merged SymbolSpec
+	found := false
+	for _, specs := range r.specs {
+		spec, ok := specs[key]
+		if !ok {
+			continue
+		}
+		if !found {
+			merged = spec
+			merged.Venue = ""
+			found = true
+			continue
+		}
+		merged.TickSize = decimal.Max(merged.TickSize, spec.TickSize)
+		merged.StepSize = decimal.Max(merged.StepSize, spec.StepSize)
+		merged.MinNotional = decimal.Max(merged.MinNotional, spec.MinNotional)
+		if spec.BasePrecision < merged.BasePrecision {
+			merged.BasePrecision = spec.BasePrecision
+		}
+		if spec.QuotePrecision < merged.QuotePrecision {
+			merged.QuotePrecision = spec.QuotePrecision
+		}
+	}
+	return merged, found
+}
+
+// symbolSpecKey maps "BTC/USDT", "btc-usdt" and "BTCUSDT" to one key.
+func symbolSpecKey(symbol string) string {
+	return strings.ToUpper(strings.NewReplacer("/", "", "-", "", "_", "").Replace(symbol))
+}
+
+// SymbolSpecs is the registry adapters populate from their exchange info
+// and the sizing and execution paths round orders with.
+var SymbolSpecs = NewSymbolSpecRegistry()
+
+// RoundQuantity floors quantity to the symbol's step size on venue, or on
+// every venue when venue is empty, leaving it unchanged for symbols without
+// a spec.
+func RoundQuantity(venue, symbol string, quantity decimal.Decimal) decimal.Decimal {
+	spec, ok := SymbolSpecs.Get(venue, symbol)
+	if !ok {
+		return quantity
+	}
+	return spec.RoundQuantity(quantity)
+}
+
+// RoundPrice rounds price for an order of side to the symbol's tick size on
+// venue, or on every venue when venue is empty, leaving it unchanged for
+// symbols without a spec.
+func RoundPrice(venue, symbol string, side OrderSide, price decimal.Decimal) decimal.Decimal {
+	spec, ok := SymbolSpecs.Get(venue, symbol)
+	if !ok {
+		return price
+	}
+	return spec.RoundPrice(side, price)
+}
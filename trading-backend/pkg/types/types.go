@@ -106,6 +106,8 @@ type Order struct {
 	CreatedAt     time.Time       `json:"createdAt"`
 	UpdatedAt     time.Time       `json:"updatedAt"`
 	FilledAt      *time.Time      `json:"filledAt,omitempty"`
+	OrderListID   string          `json:"orderListId,omitempty"` // Shared by orders placed as one list, e.g. OCO legs
+	PostOnly      bool            `json:"postOnly,omitempty"`    // Limit order the venue rejects rather than let take liquidity
 }
 
 // Position represents an open position
@@ -117,9 +119,16 @@ type Position struct {
 	CurrentPrice  decimal.Decimal `json:"currentPrice"`
 	UnrealizedPnL decimal.Decimal `json:"unrealizedPnl"`
 	RealizedPnL   decimal.Decimal `json:"realizedPnl"`
+	Funding       decimal.Decimal `json:"funding,omitempty"` // Perpetual funding received less paid, included in RealizedPnL
 	StopLoss      decimal.Decimal `json:"stopLoss,omitempty"`
 	TakeProfit    decimal.Decimal `json:"takeProfit,omitempty"`
 	OpenedAt      time.Time       `json:"openedAt"`
+	TimeInTrade   time.Duration   `json:"timeInTrade,omitempty"` // Since OpenedAt, set on status copies
+	CostUnknown   bool            `json:"costUnknown,omitempty"` // Adopted from an exchange balance with no entry price
+
+	// Take-profit ladder progress; Quantity is what remains open
+	TPRungs       int `json:"tpRungs,omitempty"`
+	TPRungsFilled int `json:"tpRungsFilled,omitempty"`
 }
 
 // OrderBook represents an order book snapshot
@@ -147,6 +156,7 @@ type Trade struct {
 	Commission   decimal.Decimal `json:"commission"`
 	Slippage     decimal.Decimal `json:"slippage"`
 	PnL          decimal.Decimal `json:"pnl"`
+	Funding      decimal.Decimal `json:"funding,omitempty"` // Perpetual funding received less paid, included in PnL
 	ExecutedAt   time.Time       `json:"executedAt"`
 	BlockNumber  uint64          `json:"blockNumber,omitempty"`
 	TxHash       string          `json:"txHash,omitempty"`
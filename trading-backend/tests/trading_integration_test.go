@@ -361,7 +361,7 @@ func TestStrategyOptimizer(t *testing.T) {
 
 func TestPerformanceAnalyzer(t *testing.T) {
 	logger := zap.NewNop()
-	pa := learning.NewPerformanceAnalyzer(logger)
+	pa := learning.NewPerformanceAnalyzer(logger, learning.DefaultPerformanceConfig())
 	
 	// Create test trades
 	trades := make([]*types.Trade, 20)